@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/fernandoocampo/pack/model"
+)
+
+// APIKeyHeader is the header that carries an api key.
+const APIKeyHeader = "X-Api-Key"
+
+// IAPIKeyStore defines where api keys are looked up.
+type IAPIKeyStore interface {
+	// GetByHash returns the api key with the given hash, nil if there is none.
+	GetByHash(hash string) (*model.APIKey, error)
+	// Touch records the last time the key was used.
+	Touch(id string, when time.Time) error
+}
+
+// APIKeyAuthenticator authenticates callers by the api key in the
+// X-Api-Key header.
+type APIKeyAuthenticator struct {
+	store IAPIKeyStore
+}
+
+// NewAPIKeyAuthenticator creates an authenticator that looks up api keys
+// in the given store.
+func NewAPIKeyAuthenticator(store IAPIKeyStore) *APIKeyAuthenticator {
+	return &APIKeyAuthenticator{store: store}
+}
+
+// Authenticate implements IAuthenticator.Authenticate.
+func (a *APIKeyAuthenticator) Authenticate(r *http.Request) (*model.Identity, error) {
+	plain := r.Header.Get(APIKeyHeader)
+	if plain == "" {
+		return nil, ErrNoCredentials
+	}
+	key, err := a.store.GetByHash(model.HashAPIKey(plain))
+	if err != nil {
+		return nil, err
+	}
+	if key == nil || key.Revoked {
+		return nil, ErrInvalidCredentials
+	}
+	err = a.store.Touch(key.ID.Hex(), time.Now())
+	if err != nil {
+		log.Warnf("last use of api key %s cannot be recorded: %v", key.ID.Hex(), err)
+	}
+	return key.Identity(), nil
+}
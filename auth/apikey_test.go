@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fernandoocampo/pack/model"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// memoryKeyStore keeps api keys in memory for tests.
+type memoryKeyStore map[string]*model.APIKey
+
+func (s memoryKeyStore) GetByHash(hash string) (*model.APIKey, error) {
+	return s[hash], nil
+}
+
+func (s memoryKeyStore) Touch(id string, when time.Time) error {
+	return nil
+}
+
+// TestAPIKeyAuthenticate verify that only stored and not revoked keys are accepted.
+func TestAPIKeyAuthenticate(t *testing.T) {
+	// GIVEN a stored key and a revoked one
+	key, plain, _ := model.NewAPIKey("billing", []string{"viewer"}, 7, 0)
+	key.ID = bson.NewObjectId()
+	revoked, revokedplain, _ := model.NewAPIKey("old", nil, 0, 0)
+	revoked.Revoked = true
+	store := memoryKeyStore{key.Hash: key, revoked.Hash: revoked}
+	authenticator := NewAPIKeyAuthenticator(store)
+
+	tests := []struct {
+		name    string
+		header  string
+		wantErr error
+	}{
+		{name: "valid key", header: plain, wantErr: nil},
+		{name: "revoked key", header: revokedplain, wantErr: ErrInvalidCredentials},
+		{name: "unknown key", header: "pk_pepe", wantErr: ErrInvalidCredentials},
+		{name: "no key", header: "", wantErr: ErrNoCredentials},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/graphql", nil)
+			r.Header.Set(APIKeyHeader, tt.header)
+
+			identity, err := authenticator.Authenticate(r)
+
+			if err != tt.wantErr {
+				t.Fatalf("Authenticate() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (identity.Ownerid != 7 || identity.Method != "apikey") {
+				t.Fatalf("Unexpected identity %+v", identity)
+			}
+		})
+	}
+}
+
+// TestMiddleware verify that anonymous requests are rejected and the
+// identity reaches the handler.
+func TestMiddleware(t *testing.T) {
+	key, plain, _ := model.NewAPIKey("billing", nil, 7, 0)
+	key.ID = bson.NewObjectId()
+	jwt := newTestAuthenticator(t, JWTConfig{Secret: "s3cr3t"})
+	chain := Chain{jwt, NewAPIKeyAuthenticator(memoryKeyStore{key.Hash: key})}
+
+	var got *model.Identity
+	handler := Middleware(chain, func(w http.ResponseWriter, r *http.Request) {
+		got = FromContext(r.Context())
+	})
+
+	// an anonymous request is rejected
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest("POST", "/graphql", nil))
+	if w.Code != http.StatusUnauthorized || got != nil {
+		t.Fatalf("Expected 401 for an anonymous request but got %d", w.Code)
+	}
+
+	// a request with api key reaches the handler with its identity
+	w = httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/graphql", nil)
+	r.Header.Set(APIKeyHeader, plain)
+	handler(w, r)
+	if w.Code != http.StatusOK || got == nil || got.Ownerid != 7 {
+		t.Fatalf("Expected the identity of the key but got %d, %+v", w.Code, got)
+	}
+}
@@ -0,0 +1,77 @@
+// Package auth authenticates the callers of the service and carries
+// their identity in the request context.
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/fernandoocampo/pack/model"
+)
+
+// IAuthenticator defines a way to authenticate the caller of a request.
+type IAuthenticator interface {
+	// Authenticate returns the identity of the caller. It returns
+	// ErrNoCredentials if the request has no credentials of its kind.
+	Authenticate(r *http.Request) (*model.Identity, error)
+}
+
+// Authentication errors
+var (
+	ErrNoCredentials      = errors.New("request has no credentials")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// contextKey is the type of the values this package puts in a request context.
+type contextKey string
+
+// identityKey is the key of the caller identity in the request context.
+const identityKey contextKey = "identity"
+
+// Chain tries its authenticators in order, the first one that finds
+// credentials in the request decides.
+type Chain []IAuthenticator
+
+// Authenticate implements IAuthenticator.Authenticate.
+func (c Chain) Authenticate(r *http.Request) (*model.Identity, error) {
+	for _, authenticator := range c {
+		identity, err := authenticator.Authenticate(r)
+		if err == ErrNoCredentials {
+			continue
+		}
+		return identity, err
+	}
+	return nil, ErrNoCredentials
+}
+
+// Middleware rejects the requests that cannot be authenticated and puts
+// the identity of the caller in the context of the others.
+func Middleware(authenticator IAuthenticator, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		identity, err := authenticator.Authenticate(r)
+		if err != nil {
+			if err != ErrNoCredentials {
+				log.Warnf("rejected credentials from %s: %v", r.RemoteAddr, err)
+			}
+			w.Header().Set("WWW-Authenticate", `Bearer realm="pack"`)
+			http.Error(w, ErrInvalidCredentials.Error(), http.StatusUnauthorized)
+			return
+		}
+		next(w, r.WithContext(NewContext(r.Context(), identity)))
+	}
+}
+
+// NewContext returns a copy of ctx that carries the given identity.
+func NewContext(ctx context.Context, identity *model.Identity) context.Context {
+	return context.WithValue(ctx, identityKey, identity)
+}
+
+// FromContext returns the identity of the caller, nil if there is none.
+func FromContext(ctx context.Context) *model.Identity {
+	if ctx == nil {
+		return nil
+	}
+	identity, _ := ctx.Value(identityKey).(*model.Identity)
+	return identity
+}
@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+)
+
+// KeySet contains the keys to verify tokens by key id, values are
+// *rsa.PublicKey for RS256 and []byte for HS256.
+type KeySet map[string]interface{}
+
+// jwk is a json web key.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	K   string `json:"k"`
+}
+
+// LoadJWKS reads the RSA and symmetric keys of a json web key set file.
+func LoadJWKS(path string) (KeySet, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseJWKS(data)
+}
+
+// ParseJWKS reads the RSA and symmetric keys of a json web key set.
+func ParseJWKS(data []byte) (KeySet, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	err := json.Unmarshal(data, &set)
+	if err != nil {
+		return nil, fmt.Errorf("invalid jwks: %v", err)
+	}
+	keys := KeySet{}
+	for _, key := range set.Keys {
+		switch key.Kty {
+		case "RSA":
+			public, err := rsaKey(key)
+			if err != nil {
+				return nil, fmt.Errorf("invalid jwks key %q: %v", key.Kid, err)
+			}
+			keys[key.Kid] = public
+		case "oct":
+			secret, err := base64.RawURLEncoding.DecodeString(key.K)
+			if err != nil {
+				return nil, fmt.Errorf("invalid jwks key %q: %v", key.Kid, err)
+			}
+			keys[key.Kid] = secret
+		default:
+			log.Warnf("jwks key %q of type %s is not supported", key.Kid, key.Kty)
+		}
+	}
+	return keys, nil
+}
+
+// rsaKey builds the public key of a RSA json web key.
+func rsaKey(key jwk) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(key.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := base64.RawURLEncoding.DecodeString(key.E)
+	if err != nil {
+		return nil, err
+	}
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+		return nil, fmt.Errorf("exponent is too large")
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+}
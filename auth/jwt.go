@@ -0,0 +1,204 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/fernandoocampo/pack/model"
+)
+
+// JWTConfig contains the parameters to validate json web tokens.
+type JWTConfig struct {
+	Secret   string `mapstructure:"secret"`   // shared secret of HS256 tokens
+	JWKSFile string `mapstructure:"jwksFile"` // local json web key set with the keys of RS256 and HS256 tokens
+	Issuer   string `mapstructure:"issuer"`   // expected iss claim, empty to accept any
+	Audience string `mapstructure:"audience"` // expected aud claim, empty to accept any
+	Leeway   int    `mapstructure:"leeway"`   // seconds of clock skew accepted
+}
+
+// JWTAuthenticator authenticates callers by the bearer token in the
+// Authorization header.
+type JWTAuthenticator struct {
+	config JWTConfig
+	keys   KeySet
+	now    func() time.Time
+}
+
+// claims contains the registered and the private claims of a token.
+type claims struct {
+	Subject   string   `json:"sub"`
+	Issuer    string   `json:"iss"`
+	Audience  audience `json:"aud"`
+	ExpiresAt int64    `json:"exp"`
+	NotBefore int64    `json:"nbf"`
+	Roles     []string `json:"roles"`
+	Ownerid   int      `json:"ownerid"`
+	MnoID     int8     `json:"mnoid"`
+	Channel   string   `json:"channel"`
+}
+
+// audience is the aud claim, it can be a string or a list.
+type audience []string
+
+// header contains the jose header of a token.
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// NewJWTAuthenticator creates an authenticator with the keys of the
+// configuration, the JWKS file and the shared secret are both accepted.
+func NewJWTAuthenticator(config JWTConfig) (*JWTAuthenticator, error) {
+	keys := KeySet{}
+	if config.JWKSFile != "" {
+		loaded, err := LoadJWKS(config.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		keys = loaded
+	}
+	if config.Secret != "" {
+		keys[""] = []byte(config.Secret)
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("jwt authentication needs a secret or a jwks file")
+	}
+	return &JWTAuthenticator{config: config, keys: keys, now: time.Now}, nil
+}
+
+// Authenticate implements IAuthenticator.Authenticate.
+func (a *JWTAuthenticator) Authenticate(r *http.Request) (*model.Identity, error) {
+	authorization := r.Header.Get("Authorization")
+	if !strings.HasPrefix(authorization, "Bearer ") {
+		return nil, ErrNoCredentials
+	}
+	token := strings.TrimSpace(strings.TrimPrefix(authorization, "Bearer "))
+	tokenclaims, err := a.Verify(token)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Identity{Subject: tokenclaims.Subject, Method: "jwt", Roles: tokenclaims.Roles,
+		Ownerid: tokenclaims.Ownerid, MnoID: tokenclaims.MnoID,
+		Channel: model.NormalizeAvailabilityCode(tokenclaims.Channel)}, nil
+}
+
+// Verify checks the signature and the claims of a token.
+func (a *JWTAuthenticator) Verify(token string) (*claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%v: malformed token", ErrInvalidCredentials)
+	}
+	tokenheader := header{}
+	err := decodeSegment(parts[0], &tokenheader)
+	if err != nil {
+		return nil, fmt.Errorf("%v: malformed header", ErrInvalidCredentials)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%v: malformed signature", ErrInvalidCredentials)
+	}
+	err = a.verifySignature(tokenheader, []byte(parts[0]+"."+parts[1]), signature)
+	if err != nil {
+		return nil, err
+	}
+	tokenclaims := new(claims)
+	err = decodeSegment(parts[1], tokenclaims)
+	if err != nil {
+		return nil, fmt.Errorf("%v: malformed claims", ErrInvalidCredentials)
+	}
+	return tokenclaims, a.validateClaims(tokenclaims)
+}
+
+// verifySignature checks the signature with the key of the token.
+func (a *JWTAuthenticator) verifySignature(tokenheader header, signed []byte, signature []byte) error {
+	key, ok := a.keys[tokenheader.Kid]
+	if !ok {
+		return fmt.Errorf("%v: unknown key %q", ErrInvalidCredentials, tokenheader.Kid)
+	}
+	digest := sha256.Sum256(signed)
+	switch tokenheader.Alg {
+	case "HS256":
+		secret, ok := key.([]byte)
+		if !ok {
+			return fmt.Errorf("%v: key %q is not a secret", ErrInvalidCredentials, tokenheader.Kid)
+		}
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(signed)
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return fmt.Errorf("%v: bad signature", ErrInvalidCredentials)
+		}
+	case "RS256":
+		public, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("%v: key %q is not a rsa key", ErrInvalidCredentials, tokenheader.Kid)
+		}
+		if rsa.VerifyPKCS1v15(public, crypto.SHA256, digest[:], signature) != nil {
+			return fmt.Errorf("%v: bad signature", ErrInvalidCredentials)
+		}
+	default:
+		return fmt.Errorf("%v: algorithm %q is not accepted", ErrInvalidCredentials, tokenheader.Alg)
+	}
+	return nil
+}
+
+// validateClaims checks the time window, issuer and audience of a token.
+func (a *JWTAuthenticator) validateClaims(tokenclaims *claims) error {
+	now := a.now().Unix()
+	leeway := int64(a.config.Leeway)
+	if tokenclaims.ExpiresAt == 0 || now > tokenclaims.ExpiresAt+leeway {
+		return fmt.Errorf("%v: token expired", ErrInvalidCredentials)
+	}
+	if tokenclaims.NotBefore != 0 && now < tokenclaims.NotBefore-leeway {
+		return fmt.Errorf("%v: token not valid yet", ErrInvalidCredentials)
+	}
+	if a.config.Issuer != "" && tokenclaims.Issuer != a.config.Issuer {
+		return fmt.Errorf("%v: unexpected issuer", ErrInvalidCredentials)
+	}
+	if a.config.Audience != "" && !tokenclaims.Audience.contains(a.config.Audience) {
+		return fmt.Errorf("%v: unexpected audience", ErrInvalidCredentials)
+	}
+	if tokenclaims.Subject == "" {
+		return fmt.Errorf("%v: token without subject", ErrInvalidCredentials)
+	}
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if json.Unmarshal(data, &single) == nil {
+		*a = audience{single}
+		return nil
+	}
+	var list []string
+	err := json.Unmarshal(data, &list)
+	*a = list
+	return err
+}
+
+func (a audience) contains(expected string) bool {
+	for _, value := range a {
+		if value == expected {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeSegment decodes a base64url json segment of a token.
+func decodeSegment(segment string, value interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, value)
+}
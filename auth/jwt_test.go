@@ -0,0 +1,165 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var testNow = time.Date(2018, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+// TestJWTAuthenticateSecret verify tokens signed with the shared secret.
+func TestJWTAuthenticateSecret(t *testing.T) {
+	authenticator := newTestAuthenticator(t, JWTConfig{Secret: "s3cr3t", Issuer: "amphora", Audience: "pack"})
+	valid := map[string]interface{}{"sub": "ana", "iss": "amphora", "aud": []string{"pack", "other"},
+		"exp": testNow.Add(time.Hour).Unix(), "roles": []string{"viewer"}, "ownerid": 7, "mnoid": 2,
+		"channel": "Retail"}
+
+	// GIVEN a request with a valid token
+	r := httptest.NewRequest("POST", "/graphql", nil)
+	r.Header.Set("Authorization", "Bearer "+signHS256(t, "", "s3cr3t", valid))
+
+	// WHEN we authenticate it
+	identity, err := authenticator.Authenticate(r)
+
+	// THEN we get the identity in the token
+	if err != nil {
+		t.Fatalf("Expected err to be nil but it was: %s", err)
+	}
+	if identity.Subject != "ana" || identity.Ownerid != 7 || identity.MnoID != 2 || identity.Channel != "retail" ||
+		!identity.HasRole("viewer") {
+		t.Fatalf("Unexpected identity %+v", identity)
+	}
+}
+
+// TestJWTRejected verify the tokens that are not accepted.
+func TestJWTRejected(t *testing.T) {
+	authenticator := newTestAuthenticator(t, JWTConfig{Secret: "s3cr3t", Issuer: "amphora", Audience: "pack"})
+	claimsWith := func(key string, value interface{}) map[string]interface{} {
+		result := map[string]interface{}{"sub": "ana", "iss": "amphora", "aud": "pack", "exp": testNow.Add(time.Hour).Unix()}
+		result[key] = value
+		return result
+	}
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "expired", token: signHS256(t, "", "s3cr3t", claimsWith("exp", testNow.Add(-time.Minute).Unix()))},
+		{name: "not yet valid", token: signHS256(t, "", "s3cr3t", claimsWith("nbf", testNow.Add(time.Hour).Unix()))},
+		{name: "other issuer", token: signHS256(t, "", "s3cr3t", claimsWith("iss", "evil"))},
+		{name: "other audience", token: signHS256(t, "", "s3cr3t", claimsWith("aud", "billing"))},
+		{name: "no subject", token: signHS256(t, "", "s3cr3t", claimsWith("sub", ""))},
+		{name: "other secret", token: signHS256(t, "", "pepe", claimsWith("sub", "ana"))},
+		{name: "unknown key", token: signHS256(t, "k9", "s3cr3t", claimsWith("sub", "ana"))},
+		{name: "alg none", token: segment(t, map[string]string{"alg": "none"}) + "." + segment(t, claimsWith("sub", "ana")) + "."},
+		{name: "malformed", token: "pepe"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/graphql", nil)
+			r.Header.Set("Authorization", "Bearer "+tt.token)
+
+			identity, err := authenticator.Authenticate(r)
+
+			if err == nil || err == ErrNoCredentials {
+				t.Fatalf("Expected the token to be rejected but got %+v, %v", identity, err)
+			}
+		})
+	}
+}
+
+// TestJWTAuthenticateJWKS verify tokens signed with a RSA key of a local jwks file.
+func TestJWTAuthenticateJWKS(t *testing.T) {
+	// GIVEN a jwks file with a RSA key
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Expected err to be nil but it was: %s", err)
+	}
+	dir, _ := ioutil.TempDir("", "jwks")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "jwks.json")
+	jwks := map[string]interface{}{"keys": []map[string]string{{
+		"kty": "RSA", "kid": "k1",
+		"n": base64.RawURLEncoding.EncodeToString(private.N.Bytes()),
+		"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(private.E)).Bytes()),
+	}}}
+	data, _ := json.Marshal(jwks)
+	ioutil.WriteFile(path, data, 0600)
+	authenticator := newTestAuthenticator(t, JWTConfig{JWKSFile: path})
+
+	// WHEN we authenticate a token signed with the key
+	token := signRS256(t, "k1", private, map[string]interface{}{"sub": "svc", "exp": testNow.Add(time.Hour).Unix()})
+	r := httptest.NewRequest("POST", "/graphql", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	identity, err := authenticator.Authenticate(r)
+
+	// THEN the token is accepted
+	if err != nil || identity.Subject != "svc" {
+		t.Fatalf("Expected identity svc but got %+v, %v", identity, err)
+	}
+
+	// AND a token signed with the rsa key as hmac secret is not
+	forged := signHS256(t, "k1", string(private.N.Bytes()), map[string]interface{}{"sub": "svc", "exp": testNow.Add(time.Hour).Unix()})
+	if _, err := authenticator.Verify(forged); err == nil {
+		t.Fatalf("Expected a HS256 token with a rsa key to be rejected")
+	}
+}
+
+// TestJWTNoCredentials verify that requests without bearer token are left
+// to other authenticators.
+func TestJWTNoCredentials(t *testing.T) {
+	authenticator := newTestAuthenticator(t, JWTConfig{Secret: "s3cr3t"})
+	r := httptest.NewRequest("POST", "/graphql", nil)
+
+	_, err := authenticator.Authenticate(r)
+
+	if err != ErrNoCredentials {
+		t.Fatalf("Expected ErrNoCredentials but got %v", err)
+	}
+}
+
+func newTestAuthenticator(t *testing.T, config JWTConfig) *JWTAuthenticator {
+	authenticator, err := NewJWTAuthenticator(config)
+	if err != nil {
+		t.Fatalf("Expected err to be nil but it was: %s", err)
+	}
+	authenticator.now = func() time.Time { return testNow }
+	return authenticator
+}
+
+func signHS256(t *testing.T, kid string, secret string, tokenclaims interface{}) string {
+	signed := segment(t, map[string]string{"alg": "HS256", "kid": kid}) + "." + segment(t, tokenclaims)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func signRS256(t *testing.T, kid string, key *rsa.PrivateKey, tokenclaims interface{}) string {
+	signed := segment(t, map[string]string{"alg": "RS256", "kid": kid}) + "." + segment(t, tokenclaims)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("signing token: %v", err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func segment(t *testing.T, value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(fmt.Sprintf("encoding segment: %v", err))
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
@@ -6,4 +6,13 @@ BASE_PATH="${GOPATH}/src/bitbucket.org/${CV_TEAM}"
 mkdir -p ${BASE_PATH}
 export IMPORT_PATH="${BASE_PATH}/${BITBUCKET_REPO_SLUG}"
 ln -s ${PWD} ${IMPORT_PATH}
-export PATH=$GOPATH/bin:$GOROOT/bin:$PATH
\ No newline at end of file
+export PATH=$GOPATH/bin:$GOROOT/bin:$PATH
+# the project is built in the GOPATH with the dependencies of dep
+export GO111MODULE=off
+
+# grpc and golang.org/x/net need go 1.25 or newer
+GO_MINOR=$(go env GOVERSION | sed -E 's/^go1\.([0-9]+).*/\1/')
+if [ "${GO_MINOR:-0}" -lt 25 ]; then
+    echo "go 1.25 or newer is required, the image has $(go version)"
+    exit 1
+fi
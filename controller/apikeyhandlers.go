@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/service"
+	"github.com/graphql-go/graphql"
+)
+
+// apiKeyService references the IAPIKeyService
+var apiKeyService service.IAPIKeyService
+
+// getAPIKeys implements IAPIKeyService.GetAll.
+func getAPIKeys(params graphql.ResolveParams) (interface{}, error) {
+	err := requireAdmin(params)
+	if err != nil {
+		return nil, err
+	}
+	return apiKeyService.GetAll()
+}
+
+// createAPIKey implements IAPIKeyService.Create. The new key goes in the
+// result message.
+func createAPIKey(params graphql.ResolveParams) (interface{}, error) {
+	err := requireAdmin(params)
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	name, _ := params.Args["name"].(string)
+	ownerid, _ := params.Args["ownerid"].(int)
+	mnoid, _ := params.Args["mnoid"].(int)
+	channel, _ := params.Args["channel"].(string)
+	roles := []string{}
+	if values, ok := params.Args["roles"].([]interface{}); ok {
+		for _, value := range values {
+			if role, ok := value.(string); ok {
+				roles = append(roles, role)
+			}
+		}
+	}
+
+	_, plain, err := apiKeyService.Create(name, roles, ownerid, int8(mnoid), channel)
+
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	result := model.NewOKResult("10")
+	result.Msg = plain
+	return result, nil
+}
+
+// revokeAPIKey implements IAPIKeyService.Revoke.
+func revokeAPIKey(params graphql.ResolveParams) (interface{}, error) {
+	err := requireAdmin(params)
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	id, _ := params.Args["id"].(string)
+
+	err = apiKeyService.Revoke(id)
+
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	return model.NewOKResult("10"), nil
+}
+
+// SetAPIKeyService sets the api key service for this handler.
+func SetAPIKeyService(service service.IAPIKeyService) {
+	apiKeyService = service
+}
@@ -0,0 +1,127 @@
+package controller
+
+import (
+	"github.com/fernandoocampo/pack/model"
+	"github.com/graphql-go/graphql"
+)
+
+// apiKeyType is a credential of a machine caller, the secret is never returned.
+var apiKeyType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ApiKey",
+	Description: "A credential of a machine caller",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The id of the api key.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				key := p.Source.(model.APIKey)
+				return key.ID.Hex(), nil
+			},
+		},
+		"name": &graphql.Field{
+			Type:        graphql.String,
+			Description: "what the key is used for.",
+		},
+		"prefix": &graphql.Field{
+			Type:        graphql.String,
+			Description: "first characters of the key to recognize it.",
+		},
+		"roles": &graphql.Field{
+			Type:        graphql.NewList(graphql.String),
+			Description: "roles granted to the caller.",
+		},
+		"ownerid": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "owner the caller acts for, 0 if any.",
+		},
+		"mnoid": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "mno the caller acts for, 0 if any.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				key := p.Source.(model.APIKey)
+				return int(key.MnoID), nil
+			},
+		},
+		"channel": &graphql.Field{
+			Type:        graphql.String,
+			Description: "channel the caller sells in, empty if any.",
+		},
+		"revoked": &graphql.Field{
+			Type:        graphql.Boolean,
+			Description: "revoked keys are not accepted.",
+		},
+		"created": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "when the key was created.",
+		},
+		"lastused": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "last time the key was used.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				key := p.Source.(model.APIKey)
+				if key.LastUsed.IsZero() {
+					return nil, nil
+				}
+				return key.LastUsed, nil
+			},
+		},
+	},
+})
+
+// apiKeyQueryFields contains the queries over api keys.
+var apiKeyQueryFields = graphql.Fields{
+	"apiKeys": &graphql.Field{
+		Type:        graphql.NewList(apiKeyType),
+		Description: "query every api key, only for platform admins",
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return getAPIKeys(params)
+		},
+	},
+}
+
+// apiKeyMutationFields contains the mutations over api keys.
+var apiKeyMutationFields = graphql.Fields{
+	/*
+		create an api key
+	*/
+	"createApiKey": &graphql.Field{
+		Type:        resultType, // the return type for this field
+		Description: "creates an api key, it goes in the result message and it is not shown again. Only for platform admins",
+		Args: graphql.FieldConfigArgument{
+			"name": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+			"roles": &graphql.ArgumentConfig{
+				Type: graphql.NewList(graphql.String),
+			},
+			"ownerid": &graphql.ArgumentConfig{
+				Type: graphql.Int,
+			},
+			"mnoid": &graphql.ArgumentConfig{
+				Type: graphql.Int,
+			},
+			"channel": &graphql.ArgumentConfig{
+				Type:        graphql.String,
+				Description: "channel of a reseller app, it only gets the packs sold in it",
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return createAPIKey(params)
+		},
+	},
+	/*
+		revoke an api key
+	*/
+	"revokeApiKey": &graphql.Field{
+		Type:        resultType, // the return type for this field
+		Description: "stops accepting an api key. Only for platform admins",
+		Args: graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return revokeAPIKey(params)
+		},
+	},
+}
@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/service"
+	"github.com/graphql-go/graphql"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// availabilityService references the IAvailabilityService
+var availabilityService service.IAvailabilityService
+
+// getAvailabilityCodes implements IAvailabilityService.GetAll.
+func getAvailabilityCodes(kind string) (interface{}, error) {
+	return availabilityService.GetAll(kind)
+}
+
+// addAvailabilityCode implements IAvailabilityService.Add.
+func addAvailabilityCode(params graphql.ResolveParams, kind string) (interface{}, error) {
+	code := model.NewAvailabilityCode(kind, params.Args)
+
+	err := availabilityService.Add(code)
+
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	return model.NewOKResult("10"), nil
+}
+
+// removeAvailabilityCode implements IAvailabilityService.Remove.
+func removeAvailabilityCode(params graphql.ResolveParams, kind string) (interface{}, error) {
+	code, _ := params.Args["code"].(string)
+
+	err := availabilityService.Remove(kind, code)
+
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	return model.NewOKResult("10"), nil
+}
+
+// setPackAvailability implements IPackService.SetAvailability.
+func setPackAvailability(params graphql.ResolveParams) (interface{}, error) {
+	id, _ := params.Args["id"].(string)
+	channels := model.NewAvailabilityCodes(params.Args["channels"])
+	regions := model.NewAvailabilityCodes(params.Args["regions"])
+
+	err := tenantPackService(params).SetAvailability(id, channels, regions)
+
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	return model.NewOKResult("10"), nil
+}
+
+// availablePack returns the pack if it is sold in the channel and region
+// arguments, nil if it is not.
+func availablePack(params graphql.ResolveParams, pack *model.Pack) *model.Pack {
+	channel, _ := params.Args["channel"].(string)
+	region, _ := params.Args["region"].(string)
+	if !pack.AvailableIn(channel, region) {
+		return nil
+	}
+	return pack
+}
+
+// availablePacks returns the packs sold in the channel and region arguments.
+func availablePacks(params graphql.ResolveParams, packs []model.Pack) []model.Pack {
+	available := make([]model.Pack, 0, len(packs))
+	for i := range packs {
+		if availablePack(params, &packs[i]) != nil {
+			available = append(available, packs[i])
+		}
+	}
+	return available
+}
+
+// checkSellable returns service.ErrPackNotFound if the pack with the given
+// id is not a pack of the tenant of the caller or, if the caller sells in a
+// channel, it is not sold in it. An empty id is left to the services to
+// reject.
+func checkSellable(params graphql.ResolveParams, packid string) error {
+	if packid == "" {
+		return nil
+	}
+	if !bson.IsObjectIdHex(packid) {
+		return service.ErrPackNotFound
+	}
+	pack, err := tenantPackService(params).FindByID(packid)
+	if err != nil {
+		return err
+	}
+	if pack == nil || pack.ID == "" {
+		return service.ErrPackNotFound
+	}
+	return nil
+}
+
+// SetAvailabilityService sets the availability service for this handler.
+func SetAvailabilityService(service service.IAvailabilityService) {
+	availabilityService = service
+}
@@ -0,0 +1,162 @@
+package controller
+
+import (
+	"github.com/fernandoocampo/pack/model"
+	"github.com/graphql-go/graphql"
+)
+
+// Channel or region of the catalog
+var availabilityCodeType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "AvailabilityCode",
+	Description: "A sales channel or region where packs can be sold",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The id of the channel or region.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				code := p.Source.(model.AvailabilityCode)
+				return code.ID.Hex(), nil
+			},
+		},
+		"kind": &graphql.Field{
+			Type:        graphql.String,
+			Description: "channel or region.",
+		},
+		"code": &graphql.Field{
+			Type:        graphql.String,
+			Description: "lower case code used in the packs. e.g. app, ussd, north",
+		},
+		"name": &graphql.Field{
+			Type:        graphql.String,
+			Description: "name to show.",
+		},
+		"created": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "when it was added to the catalog.",
+		},
+	},
+})
+
+// availabilityArguments returns the arguments of the read queries that
+// only return the packs sold in a channel and region.
+func availabilityArguments() graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		"channel": &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "channel where the packs are sold. e.g. app, web, ussd, retail",
+		},
+		"region": &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "region where the packs are sold",
+		},
+	}
+}
+
+// availabilityQueryFields contains the queries of the channel and region catalog.
+var availabilityQueryFields = graphql.Fields{
+	"salesChannels": &graphql.Field{
+		Type:        graphql.NewList(availabilityCodeType),
+		Description: "query the channels where packs can be sold sorted by code",
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return getAvailabilityCodes(model.AvailabilityChannel)
+		},
+	},
+	"salesRegions": &graphql.Field{
+		Type:        graphql.NewList(availabilityCodeType),
+		Description: "query the regions where packs can be sold sorted by code",
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return getAvailabilityCodes(model.AvailabilityRegion)
+		},
+	},
+}
+
+// availabilityCodeArguments returns the arguments to add a channel or region.
+func availabilityCodeArguments() graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		"code": &graphql.ArgumentConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "lower case letters, digits, - and _, at most 32 characters",
+		},
+		"name": &graphql.ArgumentConfig{
+			Type: graphql.NewNonNull(graphql.String),
+		},
+	}
+}
+
+// availabilityMutationFields contains the mutations of the channel and
+// region catalog and of the availability of packs.
+var availabilityMutationFields = graphql.Fields{
+	/*
+		add a channel to the catalog
+	*/
+	"addSalesChannel": &graphql.Field{
+		Type:        resultType, // the return type for this field
+		Description: "adds a channel where packs can be sold",
+		Args:        availabilityCodeArguments(),
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return addAvailabilityCode(params, model.AvailabilityChannel)
+		},
+	},
+	/*
+		remove a channel from the catalog
+	*/
+	"removeSalesChannel": &graphql.Field{
+		Type:        resultType, // the return type for this field
+		Description: "removes a channel from the catalog, packs that have it keep it until their availability is changed",
+		Args: graphql.FieldConfigArgument{
+			"code": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return removeAvailabilityCode(params, model.AvailabilityChannel)
+		},
+	},
+	/*
+		add a region to the catalog
+	*/
+	"addSalesRegion": &graphql.Field{
+		Type:        resultType, // the return type for this field
+		Description: "adds a region where packs can be sold",
+		Args:        availabilityCodeArguments(),
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return addAvailabilityCode(params, model.AvailabilityRegion)
+		},
+	},
+	/*
+		remove a region from the catalog
+	*/
+	"removeSalesRegion": &graphql.Field{
+		Type:        resultType, // the return type for this field
+		Description: "removes a region from the catalog, packs that have it keep it until their availability is changed",
+		Args: graphql.FieldConfigArgument{
+			"code": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return removeAvailabilityCode(params, model.AvailabilityRegion)
+		},
+	},
+	/*
+		replace the channels and regions of a pack
+	*/
+	"setPackAvailability": &graphql.Field{
+		Type:        resultType, // the return type for this field
+		Description: "replaces the channels and regions where a pack is sold, an empty list means everywhere",
+		Args: graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+			"channels": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			},
+			"regions": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return setPackAvailability(params)
+		},
+	},
+}
@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/service"
+	"github.com/graphql-go/graphql"
+)
+
+// bulkService references the IBulkService
+var bulkService service.IBulkService
+
+// bulkUpdatePacks implements IBulkService.Start for a patch. Only pricing
+// managers can change prices and only catalog roles the other data.
+func bulkUpdatePacks(params graphql.ResolveParams) (interface{}, error) {
+	identity := identityFrom(params)
+	patchargs, _ := params.Args["patch"].(map[string]interface{})
+	patch := model.NewPackPatch(patchargs)
+	if patch.ChangesPrice() {
+		if err := allow(identity, "price", pricingRoles); err != nil {
+			return nil, err
+		}
+	}
+	if patch.ChangesCatalog() {
+		if err := allow(identity, "patch", catalogRoles); err != nil {
+			return nil, err
+		}
+	}
+	return startBulkJob(params, model.NewBulkUpdate(bulkFilter(params), patch))
+}
+
+// bulkChangeState implements IBulkService.Start for a state change.
+func bulkChangeState(params graphql.ResolveParams) (interface{}, error) {
+	state, _ := params.Args["state"].(int)
+	return startBulkJob(params, model.NewBulkChangeState(bulkFilter(params), model.PackState(state)))
+}
+
+// startBulkJob starts the job over the packs of the tenant of the caller,
+// or only counts them if the preview argument is true.
+func startBulkJob(params graphql.ResolveParams, job *model.BulkJob) (interface{}, error) {
+	job.Tenant = tenantFrom(params.Context)
+	job.Subject = subjectOf(identityFrom(params))
+	job.Locale = localeFrom(params.Context)
+	if preview, _ := params.Args["preview"].(bool); preview {
+		return bulkService.Preview(job)
+	}
+	return bulkService.Start(job)
+}
+
+// bulkFilter returns the pack filter of the filter argument.
+func bulkFilter(params graphql.ResolveParams) *model.PackFilter {
+	filterargs, _ := params.Args["filter"].(map[string]interface{})
+	return model.NewPackFilter(filterargs)
+}
+
+// getBulkJob implements IBulkService.GetJob, the jobs of other callers are
+// only visible to platform admins.
+func getBulkJob(params graphql.ResolveParams) (interface{}, error) {
+	id, _ := params.Args["id"].(string)
+	job, err := bulkService.GetJob(id)
+	if err != nil {
+		return nil, err
+	}
+	identity := identityFrom(params)
+	if job.Subject != subjectOf(identity) && !identity.HasRole(model.RolePlatformAdmin) {
+		return nil, service.ErrBulkJobNotFound
+	}
+	return job, nil
+}
+
+// getBulkJobs implements IBulkService.GetJobs.
+func getBulkJobs(params graphql.ResolveParams) (interface{}, error) {
+	return bulkService.GetJobs(subjectOf(identityFrom(params)))
+}
+
+// getPackAudit implements IBulkService.GetAudit for a pack of the tenant
+// of the caller.
+func getPackAudit(params graphql.ResolveParams) (interface{}, error) {
+	id, _ := params.Args["id"].(string)
+	pack, err := tenantPackService(params).FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if pack == nil || pack.ID == "" {
+		return nil, service.ErrPackNotFound.WithField("id")
+	}
+	return bulkService.GetAudit(id)
+}
+
+// SetBulkService sets the bulk service for this handler.
+func SetBulkService(service service.IBulkService) {
+	bulkService = service
+}
@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/fernandoocampo/pack/auth"
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/service"
+	"github.com/graphql-go/graphql"
+)
+
+// bulkJobService records the jobs, the methods the handlers do not use
+// are left to the embedded nil service.
+type bulkJobService struct {
+	service.IBulkService
+	job     *model.BulkJob
+	preview bool
+}
+
+func (s *bulkJobService) Preview(job *model.BulkJob) (*model.BulkJob, error) {
+	s.job = job
+	s.preview = true
+	return job, nil
+}
+
+func (s *bulkJobService) Start(job *model.BulkJob) (*model.BulkJob, error) {
+	s.job = job
+	return job, nil
+}
+
+// TestBulkUpdatePacksRoles tests prices and the other catalog data are
+// changed in bulk by their own roles
+func TestBulkUpdatePacksRoles(t *testing.T) {
+	jobs := &bulkJobService{}
+	defer SetBulkService(bulkService)
+	SetBulkService(jobs)
+	raise := map[string]interface{}{"pricepercent": 5.0}
+	retype := map[string]interface{}{"type": map[string]interface{}{"id": 2, "name": "Data"}}
+	both := map[string]interface{}{"pricepercent": 5.0, "kwds": "datos"}
+
+	tests := []struct {
+		name      string
+		role      string
+		patch     map[string]interface{}
+		wantField string // field of the forbidden error, empty if allowed
+	}{
+		{name: "pricing manager raises prices", role: model.RolePricingManager, patch: raise},
+		{name: "editor changes the type", role: model.RoleCatalogEditor, patch: retype},
+		{name: "editor raises prices", role: model.RoleCatalogEditor, patch: raise, wantField: "price"},
+		{name: "pricing manager changes the type", role: model.RolePricingManager, patch: retype, wantField: "patch"},
+		{name: "pricing manager changes prices and keywords", role: model.RolePricingManager, patch: both, wantField: "patch"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs.job = nil
+			identity := &model.Identity{Subject: "tester", Roles: []string{tt.role}}
+			params := graphql.ResolveParams{
+				Context: auth.NewContext(context.Background(), identity),
+				Args: map[string]interface{}{"filter": map[string]interface{}{"mnoid": 2},
+					"patch": tt.patch, "preview": false},
+			}
+
+			_, err := bulkUpdatePacks(params)
+
+			if tt.wantField == "" {
+				if err != nil || jobs.job == nil {
+					t.Fatalf("Expected the job to start but got %v", err)
+				}
+				if jobs.job.Subject != "tester" || jobs.job.Filter.MnoID != 2 || jobs.preview {
+					t.Errorf("Expected a job of the caller over the mno 2 but got %+v", jobs.job)
+				}
+				return
+			}
+			var catalogerr *service.Error
+			if !errors.As(err, &catalogerr) || !errors.Is(err, service.ErrForbidden) || catalogerr.Field != tt.wantField {
+				t.Errorf("Expected a forbidden %s but got %v", tt.wantField, err)
+			}
+			if jobs.job != nil {
+				t.Errorf("Expected no job but got %+v", jobs.job)
+			}
+		})
+	}
+}
+
+// TestBulkChangeStatePreview tests the preview only counts the packs
+func TestBulkChangeStatePreview(t *testing.T) {
+	jobs := &bulkJobService{}
+	defer SetBulkService(bulkService)
+	SetBulkService(jobs)
+	editor := &model.Identity{Subject: "editor", Roles: []string{model.RoleCatalogEditor}}
+	params := graphql.ResolveParams{
+		Context: auth.NewContext(context.Background(), editor),
+		Args:    map[string]interface{}{"state": 0, "preview": true},
+	}
+
+	_, err := bulkChangeState(params)
+
+	if err != nil || !jobs.preview || jobs.job.Kind != model.BulkChangeState || jobs.job.State != model.Inactive {
+		t.Errorf("Expected a preview of a state change but got %+v, %v", jobs.job, err)
+	}
+}
@@ -0,0 +1,319 @@
+package controller
+
+import (
+	"time"
+
+	"github.com/fernandoocampo/pack/model"
+	"github.com/graphql-go/graphql"
+)
+
+// packFilterInput contains the conditions of the packs changed in bulk,
+// its fields are the filter arguments of the packs query.
+var packFilterInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "PackFilter",
+	Description: "The packs changed in bulk, every condition is optional",
+	Fields:      inputFields(packFilterArguments()),
+})
+
+// packPatchInput contains the catalog data changed by a bulk update.
+var packPatchInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "PackPatch",
+	Description: "The catalog data changed by a bulk update, fields that are not set are kept",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"price": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "new price, only pricing managers can change it",
+		},
+		"pricepercent": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Float,
+			Description: "percent added to the price, negative to reduce it. e.g. 5 raises it 5%",
+		},
+		"type": &graphql.InputObjectFieldConfig{
+			Type:        inputType,
+			Description: "new pack type",
+		},
+		"term": &graphql.InputObjectFieldConfig{
+			Type:        inputTerm,
+			Description: "new validity",
+		},
+		"currency": &graphql.InputObjectFieldConfig{
+			Type:        inputCcy,
+			Description: "new currency of the price",
+		},
+		"imgurl": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "new image url",
+		},
+		"kwds": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "new keywords",
+		},
+	},
+})
+
+// bulkFailureType is a pack that a bulk job could not change.
+var bulkFailureType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "BulkFailure",
+	Description: "A pack that a bulk job could not change",
+	Fields: graphql.Fields{
+		"packid": &graphql.Field{
+			Type:        graphql.String,
+			Description: "id of the pack.",
+		},
+		"packcode": &graphql.Field{
+			Type:        graphql.String,
+			Description: "code of the pack, empty if it could not be read.",
+		},
+		"error": &graphql.Field{
+			Type:        errorDetailType,
+			Description: "details of the failure.",
+		},
+	},
+})
+
+// bulkJobType is a change of many packs and its progress.
+var bulkJobType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "BulkJob",
+	Description: "A change of every pack that matches a filter and its progress",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The id of the job, empty for previews.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				job := bulkJobFromSource(p.Source)
+				if job == nil || job.ID == "" {
+					return nil, nil
+				}
+				return job.ID.Hex(), nil
+			},
+		},
+		"kind": &graphql.Field{
+			Type:        graphql.String,
+			Description: "update or state.",
+		},
+		"status": &graphql.Field{
+			Type:        graphql.String,
+			Description: "preview, pending, running, done or interrupted.",
+		},
+		"subject": &graphql.Field{
+			Type:        graphql.String,
+			Description: "caller that started the job.",
+		},
+		"total": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "packs that match the filter.",
+		},
+		"processed": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "packs already processed.",
+		},
+		"succeeded": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "packs changed.",
+		},
+		"skipped": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "packs that already had the change or stopped matching the filter.",
+		},
+		"failed": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "packs that could not be changed.",
+		},
+		"failures": &graphql.Field{
+			Type:        graphql.NewList(bulkFailureType),
+			Description: "first packs that could not be changed.",
+		},
+		"created": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "when the job was started.",
+		},
+		"started": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "when the packs started to be changed.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				job := bulkJobFromSource(p.Source)
+				if job == nil {
+					return nil, nil
+				}
+				return optionalTime(job.Started), nil
+			},
+		},
+		"finished": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "when the job finished.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				job := bulkJobFromSource(p.Source)
+				if job == nil {
+					return nil, nil
+				}
+				return optionalTime(job.Finished), nil
+			},
+		},
+	},
+})
+
+// auditChangeType is the change of a field of a pack.
+var auditChangeType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "AuditChange",
+	Description: "The value of a pack field before and after a change",
+	Fields: graphql.Fields{
+		"field": &graphql.Field{
+			Type:        graphql.String,
+			Description: "name of the changed field. e.g. price, type.",
+		},
+		"before": &graphql.Field{
+			Type:        graphql.String,
+			Description: "value before the change as json.",
+		},
+		"after": &graphql.Field{
+			Type:        graphql.String,
+			Description: "value after the change as json.",
+		},
+	},
+})
+
+// auditEntryType is a change of a pack.
+var auditEntryType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "AuditEntry",
+	Description: "Who changed a pack, when and what changed",
+	Fields: graphql.Fields{
+		"action": &graphql.Field{
+			Type:        graphql.String,
+			Description: "what was done. e.g. bulkUpdate, bulkChangeState.",
+		},
+		"subject": &graphql.Field{
+			Type:        graphql.String,
+			Description: "caller that made the change.",
+		},
+		"jobid": &graphql.Field{
+			Type:        graphql.String,
+			Description: "bulk job that made the change.",
+		},
+		"changes": &graphql.Field{
+			Type:        graphql.NewList(auditChangeType),
+			Description: "changed fields.",
+		},
+		"created": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "when the pack was changed.",
+		},
+	},
+})
+
+// bulkQueryFields contains the queries over bulk jobs and the audit of packs.
+var bulkQueryFields = graphql.Fields{
+	"bulkJob": &graphql.Field{
+		Type:        bulkJobType,
+		Description: "query a bulk job started by the caller and its progress",
+		Args: graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return getBulkJob(params)
+		},
+	},
+	"bulkJobs": &graphql.Field{
+		Type:        graphql.NewList(bulkJobType),
+		Description: "query the last bulk jobs started by the caller, failures are not included",
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return getBulkJobs(params)
+		},
+	},
+	"packAudit": &graphql.Field{
+		Type:        graphql.NewList(auditEntryType),
+		Description: "query the last changes of a pack",
+		Args: graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return getPackAudit(params)
+		},
+	},
+}
+
+// bulkMutationFields contains the changes of many packs at once.
+var bulkMutationFields = graphql.Fields{
+	/*
+		update the packs that match a filter
+	*/
+	"bulkUpdatePacks": &graphql.Field{
+		Type:        bulkJobType,
+		Description: "applies a patch to every pack that matches the filter in background",
+		Args: graphql.FieldConfigArgument{
+			"filter": &graphql.ArgumentConfig{
+				Type: packFilterInput,
+			},
+			"patch": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(packPatchInput),
+			},
+			"preview": &graphql.ArgumentConfig{
+				Type:         graphql.Boolean,
+				DefaultValue: false,
+				Description:  "only count the packs that would be changed",
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return bulkUpdatePacks(params)
+		},
+	},
+	/*
+		change the state of the packs that match a filter
+	*/
+	"bulkChangeState": &graphql.Field{
+		Type:        bulkJobType,
+		Description: "changes the state of every pack that matches the filter in background",
+		Args: graphql.FieldConfigArgument{
+			"filter": &graphql.ArgumentConfig{
+				Type: packFilterInput,
+			},
+			"state": &graphql.ArgumentConfig{
+				Type:        graphql.NewNonNull(graphql.Int),
+				Description: "new state of the packs. 1. active, 0. inactive",
+			},
+			"preview": &graphql.ArgumentConfig{
+				Type:         graphql.Boolean,
+				DefaultValue: false,
+				Description:  "only count the packs that would be changed",
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return bulkChangeState(params)
+		},
+	},
+}
+
+// inputFields returns the fields of an input object with the given
+// arguments, so a query and a mutation can take the same data as
+// arguments and as an object.
+func inputFields(args graphql.FieldConfigArgument) graphql.InputObjectConfigFieldMap {
+	fields := graphql.InputObjectConfigFieldMap{}
+	for name, arg := range args {
+		fields[name] = &graphql.InputObjectFieldConfig{Type: arg.Type, Description: arg.Description}
+	}
+	return fields
+}
+
+// bulkJobFromSource returns the job resolved by a parent field, lists
+// give values and single queries give pointers.
+func bulkJobFromSource(source interface{}) *model.BulkJob {
+	switch job := source.(type) {
+	case *model.BulkJob:
+		return job
+	case model.BulkJob:
+		return &job
+	default:
+		return nil
+	}
+}
+
+// optionalTime returns nil for the zero time so it is null in graphql.
+func optionalTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
@@ -0,0 +1,12 @@
+package controller
+
+import (
+	"github.com/fernandoocampo/pack/model"
+	"github.com/graphql-go/graphql"
+)
+
+// catalogStats implements IPackService.CatalogStats.
+func catalogStats(params graphql.ResolveParams) (interface{}, error) {
+	groupBy := model.NewStatsGroups(params.Args["groupBy"])
+	return tenantPackService(params).CatalogStats(bulkFilter(params), groupBy)
+}
@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"github.com/fernandoocampo/pack/model"
+	"github.com/graphql-go/graphql"
+)
+
+// Total of a resource in a group of packs
+var resourceTotalType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ResourceTotal",
+	Description: "The amount of a resource in every pack of a group",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "id of the resource.",
+		},
+		"name": &graphql.Field{
+			Type:        graphql.String,
+			Description: "name of the resource.",
+		},
+		"units": &graphql.Field{
+			Type:        graphql.String,
+			Description: "units of the total.",
+		},
+		"total": &graphql.Field{
+			Type:        graphql.Float,
+			Description: "amount of the resource added up in the packs of the group.",
+		},
+	},
+})
+
+// Stats of a group of packs
+var catalogStatsType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "CatalogStats",
+	Description: "The figures of a group of packs, only the fields of the group are set",
+	Fields: graphql.Fields{
+		"mno": &graphql.Field{
+			Type:        mnoInterface,
+			Description: "mno of the group.",
+		},
+		"type": &graphql.Field{
+			Type:        typeInterface,
+			Description: "pack type of the group.",
+		},
+		"state": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "state of the group. 1. active, 2. deactive",
+			Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+				return statsState(params.Source), nil
+			},
+		},
+		"ownerid": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "id of the owner of the group.",
+		},
+		"currency": &graphql.Field{
+			Type:        ccyInterface,
+			Description: "currency of the group.",
+		},
+		"count": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "packs of the group.",
+		},
+		"minPrice": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "lowest price of the group.",
+		},
+		"maxPrice": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "highest price of the group.",
+		},
+		"avgPrice": &graphql.Field{
+			Type:        graphql.Float,
+			Description: "average price of the group rounded to two decimals.",
+		},
+		"totalStock": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "stock of every pack of the group.",
+		},
+		"resources": &graphql.Field{
+			Type:        graphql.NewList(resourceTotalType),
+			Description: "amounts of every resource of the group by id and units.",
+		},
+	},
+})
+
+// catalogStatsQueryFields contains the queries of the catalog stats.
+var catalogStatsQueryFields = graphql.Fields{
+	"catalogStats": &graphql.Field{
+		Type:        graphql.NewList(catalogStatsType),
+		Description: "query the count, prices, stock and resource totals of the packs grouped by mno, type, state, owner and currency",
+		Args: graphql.FieldConfigArgument{
+			"groupBy": &graphql.ArgumentConfig{
+				Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+				Description: "fields the packs are grouped by: mno, type, state, owner or currency, none means one group",
+			},
+			"filter": &graphql.ArgumentConfig{
+				Type: packFilterInput,
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return catalogStats(params)
+		},
+	},
+}
+
+// statsState returns the state of the stats as an int, nil if the stats
+// are not grouped by state.
+func statsState(source interface{}) interface{} {
+	var state *model.PackState
+	switch stats := source.(type) {
+	case model.CatalogStats:
+		state = stats.State
+	case *model.CatalogStats:
+		state = stats.State
+	}
+	if state == nil {
+		return nil
+	}
+	return int(*state)
+}
@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/service"
+	"github.com/graphql-go/graphql"
+)
+
+// commissionService references the ICommissionService
+var commissionService service.ICommissionService
+
+// getCommissionRules implements ICommissionService.GetRules.
+func getCommissionRules(params graphql.ResolveParams) (interface{}, error) {
+	ownerid, _ := params.Args["ownerid"].(int)
+	if !actsFor(tenantFrom(params.Context), ownerid) {
+		return nil, service.ErrNotOwner
+	}
+	return commissionService.GetRules(ownerid)
+}
+
+// addCommissionRule implements ICommissionService.AddRule. The id of the
+// new rule goes in the result message.
+func addCommissionRule(params graphql.ResolveParams) (interface{}, error) {
+	ownerid, _ := params.Args["ownerid"].(int)
+	packtype, _ := params.Args["packtype"].(int)
+	packid, _ := params.Args["packid"].(string)
+	kind, _ := params.Args["kind"].(int)
+	value, _ := params.Args["value"].(float64)
+	if !actsFor(tenantFrom(params.Context), ownerid) {
+		return koResult(params, service.ErrNotOwner), nil
+	}
+
+	rule := &model.CommissionRule{
+		Ownerid:  ownerid,
+		Packtype: int8(packtype),
+		PackID:   packid,
+		Kind:     model.CommissionKind(kind),
+		Value:    value,
+	}
+	err := commissionService.AddRule(rule)
+
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	result := model.NewOKResult("10")
+	result.Msg = rule.ID.Hex()
+	return result, nil
+}
+
+// deleteCommissionRule implements ICommissionService.DeleteRule.
+func deleteCommissionRule(params graphql.ResolveParams) (interface{}, error) {
+	id, _ := params.Args["id"].(string)
+	rule, err := commissionService.GetRule(id)
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	if rule == nil {
+		return koResult(params, service.ErrCommissionRuleNotFound), nil
+	}
+	if !actsFor(tenantFrom(params.Context), rule.Ownerid) {
+		return koResult(params, service.ErrNotOwner), nil
+	}
+
+	err = commissionService.DeleteRule(id)
+
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	return model.NewOKResult("10"), nil
+}
+
+// SetCommissionService sets the commission service for this handler.
+func SetCommissionService(service service.ICommissionService) {
+	commissionService = service
+}
@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"github.com/fernandoocampo/pack/model"
+	"github.com/graphql-go/graphql"
+)
+
+// commissionRuleType is the commission a reseller earns on its sales.
+var commissionRuleType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "CommissionRule",
+	Description: "The commission a reseller earns on the sales of its packs",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The id of the rule.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				rule := p.Source.(model.CommissionRule)
+				return rule.ID.Hex(), nil
+			},
+		},
+		"ownerid": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "company owner of the packs for resale.",
+		},
+		"packtype": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "pack type the rule applies to, 0 for any.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				rule := p.Source.(model.CommissionRule)
+				return int(rule.Packtype), nil
+			},
+		},
+		"packid": &graphql.Field{
+			Type:        graphql.String,
+			Description: "id of the pack the rule applies to, empty for any.",
+		},
+		"kind": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "how the commission is calculated. 0. percentage of the price, 1. fixed amount",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				rule := p.Source.(model.CommissionRule)
+				return int(rule.Kind), nil
+			},
+		},
+		"value": &graphql.Field{
+			Type:        graphql.Float,
+			Description: "percentage or fixed amount.",
+		},
+		"created": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "when the rule was created.",
+		},
+	},
+})
+
+// commissionQueryFields contains the queries over commission rules.
+var commissionQueryFields = graphql.Fields{
+	"commissionRules": &graphql.Field{
+		Type:        graphql.NewList(commissionRuleType),
+		Description: "query the commission rules of an owner",
+		Args: graphql.FieldConfigArgument{
+			"ownerid": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.Int),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return getCommissionRules(params)
+		},
+	},
+}
+
+// commissionMutationFields contains the mutations over commission rules.
+var commissionMutationFields = graphql.Fields{
+	/*
+		add a commission rule
+	*/
+	"addCommissionRule": &graphql.Field{
+		Type:        resultType, // the return type for this field
+		Description: "adds a commission rule for an owner, a pack or a pack type",
+		Args: graphql.FieldConfigArgument{
+			"ownerid": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.Int),
+			},
+			"packtype": &graphql.ArgumentConfig{
+				Type: graphql.Int,
+			},
+			"packid": &graphql.ArgumentConfig{
+				Type: graphql.String,
+			},
+			"kind": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.Int),
+			},
+			"value": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.Float),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return addCommissionRule(params)
+		},
+	},
+	/*
+		delete a commission rule
+	*/
+	"deleteCommissionRule": &graphql.Field{
+		Type:        resultType, // the return type for this field
+		Description: "deletes a commission rule, sales already made keep their commission",
+		Args: graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return deleteCommissionRule(params)
+		},
+	},
+}
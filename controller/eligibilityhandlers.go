@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"github.com/fernandoocampo/pack/model"
+	"github.com/graphql-go/graphql"
+)
+
+// setEligibilityRules implements IPackService.SetRules.
+func setEligibilityRules(params graphql.ResolveParams) (interface{}, error) {
+	id, _ := params.Args["id"].(string)
+	rules := model.NewEligibilityRules(params.Args["rules"])
+
+	err := tenantPackService(params).SetRules(id, rules)
+
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	return model.NewOKResult("10"), nil
+}
+
+// checkEligibility implements IPackService.CheckEligibility.
+func checkEligibility(params graphql.ResolveParams) (interface{}, error) {
+	id, _ := params.Args["id"].(string)
+	return tenantPackService(params).CheckEligibility(id, customerContext(params))
+}
+
+// eligiblePacks implements IPackService.EligiblePacks.
+func eligiblePacks(params graphql.ResolveParams) (interface{}, error) {
+	limit, _ := params.Args["limit"].(int)
+	packs, err := tenantPackService(params).EligiblePacks(customerContext(params), bulkFilter(params), limit)
+	if err != nil {
+		return nil, err
+	}
+	return localizePacks(params, packs), nil
+}
+
+// customerContext returns the customer of the customer argument.
+func customerContext(params graphql.ResolveParams) *model.CustomerContext {
+	customerargs, _ := params.Args["customer"].(map[string]interface{})
+	return model.NewCustomerContext(customerargs)
+}
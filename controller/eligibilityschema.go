@@ -0,0 +1,204 @@
+package controller
+
+import "github.com/graphql-go/graphql"
+
+// eligibilityRuleFields are the fields of a rule, as input and output.
+var eligibilityRuleFields = graphql.FieldConfigArgument{
+	"name": &graphql.ArgumentConfig{
+		Type:        graphql.NewNonNull(graphql.String),
+		Description: "name of the rule, unique in the pack",
+	},
+	"segments": &graphql.ArgumentConfig{
+		Type:        graphql.NewList(graphql.String),
+		Description: "customer segments allowed. e.g. youth, business",
+	},
+	"plantypes": &graphql.ArgumentConfig{
+		Type:        graphql.NewList(graphql.String),
+		Description: "plan types allowed: prepaid, postpaid or hybrid",
+	},
+	"regions": &graphql.ArgumentConfig{
+		Type:        graphql.NewList(graphql.String),
+		Description: "regions allowed",
+	},
+	"minlineage": &graphql.ArgumentConfig{
+		Type:        graphql.Int,
+		Description: "fewest days since the line was activated. e.g. 0 for new lines",
+	},
+	"maxlineage": &graphql.ArgumentConfig{
+		Type:        graphql.Int,
+		Description: "most days since the line was activated, 0 for any. e.g. 30 for new lines",
+	},
+	"purchased": &graphql.ArgumentConfig{
+		Type:        graphql.NewList(graphql.String),
+		Description: "pack codes, the customer must have bought one of them",
+	},
+	"notpurchased": &graphql.ArgumentConfig{
+		Type:        graphql.NewList(graphql.String),
+		Description: "pack codes the customer must never have bought",
+	},
+}
+
+// eligibilityRuleInput contains the conditions of a rule of a pack.
+var eligibilityRuleInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "EligibilityRuleInput",
+	Description: "Conditions a customer must meet to buy a pack, conditions that are not set are met by any customer",
+	Fields:      inputFields(eligibilityRuleFields),
+})
+
+// eligibilityRuleType contains the conditions of a rule of a pack.
+var eligibilityRuleType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "EligibilityRule",
+	Description: "Conditions a customer must meet to buy a pack",
+	Fields:      outputFields(eligibilityRuleFields),
+})
+
+// customerContextInput contains the data of a customer checked by the rules.
+var customerContextInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "CustomerContext",
+	Description: "The customer checked by the eligibility rules, a condition on data that is not given fails",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"msisdn": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "line number, the packs of its completed orders are purchases",
+		},
+		"segment": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "customer segment. e.g. youth, business",
+		},
+		"plantype": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "prepaid, postpaid or hybrid",
+		},
+		"region": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "region of the customer",
+		},
+		"lineage": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "days since the line was activated",
+		},
+		"purchases": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewList(graphql.String),
+			Description: "pack codes bought by the customer out of this service",
+		},
+	},
+})
+
+// ruleFailureType is a condition the customer does not meet.
+var ruleFailureType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "RuleFailure",
+	Description: "A condition of an eligibility rule the customer does not meet",
+	Fields: graphql.Fields{
+		"rule": &graphql.Field{
+			Type:        graphql.String,
+			Description: "name of the rule.",
+		},
+		"condition": &graphql.Field{
+			Type:        graphql.String,
+			Description: "segment, plantype, region, lineage, purchased or notpurchased.",
+		},
+		"reason": &graphql.Field{
+			Type:        graphql.String,
+			Description: "why the customer does not meet it.",
+		},
+	},
+})
+
+// eligibilityType is whether a customer can buy a pack.
+var eligibilityType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Eligibility",
+	Description: "Whether a customer can buy a pack and the conditions that failed",
+	Fields: graphql.Fields{
+		"packid": &graphql.Field{
+			Type:        graphql.String,
+			Description: "id of the pack.",
+		},
+		"packcode": &graphql.Field{
+			Type:        graphql.String,
+			Description: "code of the pack.",
+		},
+		"eligible": &graphql.Field{
+			Type:        graphql.Boolean,
+			Description: "true if the customer meets every rule.",
+		},
+		"failures": &graphql.Field{
+			Type:        graphql.NewList(ruleFailureType),
+			Description: "conditions the customer does not meet.",
+		},
+	},
+})
+
+// eligibilityQueryFields contains the queries of the packs a customer can buy.
+var eligibilityQueryFields = graphql.Fields{
+	"eligiblePacks": &graphql.Field{
+		Type:        graphql.NewList(packType),
+		Description: "query the active packs the customer can buy",
+		Args: graphql.FieldConfigArgument{
+			"customer": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(customerContextInput),
+			},
+			"filter": &graphql.ArgumentConfig{
+				Type: packFilterInput,
+			},
+			"limit": &graphql.ArgumentConfig{
+				Type:         graphql.Int,
+				DefaultValue: 20,
+			},
+			"locale": localeArgument,
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return eligiblePacks(params)
+		},
+	},
+	"checkEligibility": &graphql.Field{
+		Type:        eligibilityType,
+		Description: "query whether the customer can buy a pack and which rules failed",
+		Args: graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+			"customer": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(customerContextInput),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return checkEligibility(params)
+		},
+	},
+}
+
+// eligibilityMutationFields contains the mutations of the eligibility rules.
+var eligibilityMutationFields = graphql.Fields{
+	/*
+		replace the eligibility rules of a pack
+	*/
+	"setEligibilityRules": &graphql.Field{
+		Type:        resultType, // the return type for this field
+		Description: "replaces the rules a customer must meet to buy a pack, an empty list makes it available to everyone",
+		Args: graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+			"rules": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(eligibilityRuleInput))),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return setEligibilityRules(params)
+		},
+	},
+}
+
+// outputFields returns the fields of an object with the given arguments,
+// so an input and an output object have the same fields.
+func outputFields(args graphql.FieldConfigArgument) graphql.Fields {
+	fields := graphql.Fields{}
+	for name, arg := range args {
+		fieldtype := arg.Type
+		if nonnull, ok := fieldtype.(*graphql.NonNull); ok {
+			fieldtype = nonnull.OfType
+		}
+		fields[name] = &graphql.Field{Type: fieldtype, Description: arg.Description}
+	}
+	return fields
+}
@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"time"
+
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/service"
+	"github.com/graphql-go/graphql"
+)
+
+// entitlementService references the IEntitlementService
+var entitlementService service.IEntitlementService
+
+// getEntitlements implements IEntitlementService.GetByMsisdn, only the
+// entitlements of the tenant of the caller are returned.
+func getEntitlements(params graphql.ResolveParams) (interface{}, error) {
+	msisdn, _ := params.Args["msisdn"].(string)
+	onlyopen, _ := params.Args["onlyopen"].(bool)
+	return ownedEntitlements(params, msisdn, onlyopen)
+}
+
+// getBalances returns the remaining amount per resource of the open
+// entitlements of the tenant of the caller, as IEntitlementService.Balances.
+func getBalances(params graphql.ResolveParams) (interface{}, error) {
+	msisdn, _ := params.Args["msisdn"].(string)
+	entitlements, err := ownedEntitlements(params, msisdn, true)
+	if err != nil {
+		return nil, err
+	}
+	return model.SumBalances(entitlements, time.Now()), nil
+}
+
+// grantPack implements IEntitlementService.Grant.
+func grantPack(params graphql.ResolveParams) (interface{}, error) {
+	msisdn, _ := params.Args["msisdn"].(string)
+	packid, _ := params.Args["packid"].(string)
+
+	err := checkSellable(params, packid)
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	_, err = entitlementService.Grant(msisdn, packid)
+
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	return model.NewOKResult("10"), nil
+}
+
+// consumeResource implements IEntitlementService.Consume.
+func consumeResource(params graphql.ResolveParams) (interface{}, error) {
+	msisdn, _ := params.Args["msisdn"].(string)
+	resourceid, _ := params.Args["resourceid"].(int)
+	amount, _ := params.Args["amount"].(float64)
+
+	err := entitlementService.Consume(msisdn, int16(resourceid), float32(amount))
+
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	return model.NewOKResult("10"), nil
+}
+
+// ownedEntitlements returns the entitlements of the subscriber that were
+// sold by the tenant of the caller.
+func ownedEntitlements(params graphql.ResolveParams, msisdn string, onlyopen bool) ([]model.Entitlement, error) {
+	entitlements, err := entitlementService.GetByMsisdn(msisdn, onlyopen)
+	if err != nil {
+		return nil, err
+	}
+	tenant := tenantFrom(params.Context)
+	owned := make([]model.Entitlement, 0, len(entitlements))
+	for _, entitlement := range entitlements {
+		if tenant.OwnsSale(entitlement.Ownerid, entitlement.MnoID) {
+			owned = append(owned, entitlement)
+		}
+	}
+	return owned, nil
+}
+
+// SetEntitlementService sets the entitlement service for this handler.
+func SetEntitlementService(service service.IEntitlementService) {
+	entitlementService = service
+}
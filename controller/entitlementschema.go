@@ -0,0 +1,202 @@
+package controller
+
+import (
+	"github.com/fernandoocampo/pack/model"
+	"github.com/graphql-go/graphql"
+)
+
+// balanceType is the balance of a resource inside an entitlement.
+var balanceType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Balance",
+	Description: "The balance of a pack resource owned by a subscriber",
+	Fields: graphql.Fields{
+		"resource_id": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "The id of the pack resource.",
+		},
+		"name": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The name of the resource. e.g. internet, whatsapp, etc.",
+		},
+		"units": &graphql.Field{
+			Type:        graphql.String,
+			Description: "the units name used for this resource.",
+		},
+		"granted": &graphql.Field{
+			Type:        graphql.Float,
+			Description: "amount of units given by the pack.",
+		},
+		"used": &graphql.Field{
+			Type:        graphql.Float,
+			Description: "amount of units already consumed.",
+		},
+		"remaining": &graphql.Field{
+			Type:        graphql.Float,
+			Description: "amount of units that still can be used.",
+		},
+		"isfree": &graphql.Field{
+			Type:        graphql.Boolean,
+			Description: "indicates if the resource is free or not.",
+		},
+	},
+})
+
+// resourceBalanceType is the remaining amount of a resource for a subscriber.
+var resourceBalanceType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ResourceBalance",
+	Description: "The remaining amount of a resource summed over the open entitlements of a subscriber",
+	Fields: graphql.Fields{
+		"resource_id": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "The id of the pack resource.",
+		},
+		"name": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The name of the resource.",
+		},
+		"units": &graphql.Field{
+			Type:        graphql.String,
+			Description: "the units name used for this resource.",
+		},
+		"remaining": &graphql.Field{
+			Type:        graphql.Float,
+			Description: "amount of units that still can be used.",
+		},
+		"expires": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "the farthest expiry of the summed balances.",
+		},
+	},
+})
+
+// entitlementType is a pack owned by a subscriber.
+var entitlementType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Entitlement",
+	Description: "A pack purchased by a subscriber with its balances",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The id of the entitlement.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				entitlement := p.Source.(model.Entitlement)
+				return entitlement.ID.Hex(), nil
+			},
+		},
+		"msisdn": &graphql.Field{
+			Type:        graphql.String,
+			Description: "line number of the subscriber.",
+		},
+		"packid": &graphql.Field{
+			Type:        graphql.String,
+			Description: "id of the purchased pack.",
+		},
+		"packcode": &graphql.Field{
+			Type:        graphql.String,
+			Description: "code of the purchased pack.",
+		},
+		"mnoid": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "id of the mobile network operator of the pack.",
+		},
+		"ownerid": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "id of the company owner of the pack.",
+		},
+		"balances": &graphql.Field{
+			Type:        graphql.NewList(balanceType),
+			Description: "balance of every resource of the pack.",
+		},
+		"starts": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "when the entitlement was granted.",
+		},
+		"expires": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "when the entitlement ends.",
+		},
+		"state": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "state of the entitlement. 0. closed, 1. open",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				entitlement := p.Source.(model.Entitlement)
+				return int(entitlement.State), nil
+			},
+		},
+	},
+})
+
+// entitlementQueryFields contains the queries over subscriber entitlements.
+var entitlementQueryFields = graphql.Fields{
+	"entitlements": &graphql.Field{
+		Type:        graphql.NewList(entitlementType),
+		Description: "query the entitlements of a subscriber",
+		Args: graphql.FieldConfigArgument{
+			"msisdn": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+			"onlyopen": &graphql.ArgumentConfig{
+				Type:         graphql.Boolean,
+				DefaultValue: true,
+				Description:  "leave out closed and expired entitlements",
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return getEntitlements(params)
+		},
+	},
+	"balances": &graphql.Field{
+		Type:        graphql.NewList(resourceBalanceType),
+		Description: "query the remaining balance per resource of a subscriber",
+		Args: graphql.FieldConfigArgument{
+			"msisdn": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return getBalances(params)
+		},
+	},
+}
+
+// entitlementMutationFields contains the mutations over subscriber entitlements.
+var entitlementMutationFields = graphql.Fields{
+	/*
+		grant a pack to a subscriber
+	*/
+	"grantPack": &graphql.Field{
+		Type:        resultType, // the return type for this field
+		Description: "gives a subscriber an entitlement for a purchased pack",
+		Args: graphql.FieldConfigArgument{
+			"msisdn": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+			"packid": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return grantPack(params)
+		},
+	},
+	/*
+		record the usage of a resource
+	*/
+	"consumeResource": &graphql.Field{
+		Type:        resultType, // the return type for this field
+		Description: "records the usage of an amount of a resource, e.g. MB or minutes",
+		Args: graphql.FieldConfigArgument{
+			"msisdn": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+			"resourceid": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.Int),
+			},
+			"amount": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.Float),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return consumeResource(params)
+		},
+	},
+}
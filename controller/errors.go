@@ -0,0 +1,206 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/service"
+	"github.com/graphql-go/graphql"
+)
+
+// httpStatus contains the http status answered for every error category.
+var httpStatus = map[service.ErrorCategory]int{
+	service.CategoryInvalid:      http.StatusBadRequest,
+	service.CategoryForbidden:    http.StatusForbidden,
+	service.CategoryNotFound:     http.StatusNotFound,
+	service.CategoryConflict:     http.StatusConflict,
+	service.CategoryPrecondition: http.StatusPreconditionFailed,
+	service.CategoryInternal:     http.StatusInternalServerError,
+	service.CategoryUnavailable:  http.StatusServiceUnavailable,
+}
+
+// localeKey is the key of the caller locale in the request context.
+type localeKey struct{}
+
+// languagesKey is the key of the languages the caller accepts in the
+// request context.
+type languagesKey struct{}
+
+// graphqlError is an error of the catalog shown to the caller in its
+// locale, the code, category and field are given in the extensions.
+type graphqlError struct {
+	err     *service.Error
+	message string
+}
+
+// Error returns the localized message of the error.
+func (e *graphqlError) Error() string {
+	return e.message
+}
+
+// Extensions returns the details of the error shown in the graphql error.
+func (e *graphqlError) Extensions() map[string]interface{} {
+	extensions := map[string]interface{}{
+		"code":     e.err.Code,
+		"category": string(e.err.Category),
+	}
+	if e.err.Field != "" {
+		extensions["field"] = e.err.Field
+	}
+	return extensions
+}
+
+// localize puts the locale of the messages and the languages asked in
+// the Accept-Language header in the request context.
+func localize(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		next(w, r.WithContext(withLanguages(r.Context(), r.Header.Get("Accept-Language"))))
+	}
+}
+
+// withLanguages returns a copy of ctx with the locale of the messages and
+// the languages asked in the given Accept-Language header.
+func withLanguages(ctx context.Context, header string) context.Context {
+	languages := acceptedLanguages(header)
+	ctx = context.WithValue(ctx, localeKey{}, messageLocale(languages))
+	return context.WithValue(ctx, languagesKey{}, languages)
+}
+
+// localeFrom returns the locale of the messages for the caller, the
+// default locale if it asked for none.
+func localeFrom(ctx context.Context) string {
+	if ctx != nil {
+		if locale, ok := ctx.Value(localeKey{}).(string); ok {
+			return locale
+		}
+	}
+	return service.DefaultLocale
+}
+
+// languagesFrom returns the languages the caller accepts, the preferred first.
+func languagesFrom(ctx context.Context) []string {
+	if ctx != nil {
+		if languages, ok := ctx.Value(languagesKey{}).([]string); ok {
+			return languages
+		}
+	}
+	return nil
+}
+
+// parseAcceptLanguage returns the supported locale of the messages the
+// caller prefers, the default locale if it prefers none of them.
+func parseAcceptLanguage(header string) string {
+	return messageLocale(acceptedLanguages(header))
+}
+
+// messageLocale returns the first of the given languages that has
+// messages, the default locale if none has.
+func messageLocale(languages []string) string {
+	for _, language := range languages {
+		if service.SupportedLocale(language) {
+			return language
+		}
+	}
+	return service.DefaultLocale
+}
+
+// acceptedLanguages returns the languages of the Accept-Language header
+// sorted by quality, the refused ones are left out.
+func acceptedLanguages(header string) []string {
+	type language struct {
+		locale  string
+		quality float64
+	}
+	languages := []language{}
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		locale := model.NormalizeLocale(fields[0])
+		if locale == "" || locale == "*" {
+			continue
+		}
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if value, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					quality = value
+				}
+			}
+		}
+		if quality > 0 {
+			languages = append(languages, language{locale: locale, quality: quality})
+		}
+	}
+	sort.SliceStable(languages, func(i, j int) bool { return languages[i].quality > languages[j].quality })
+	locales := make([]string, 0, len(languages))
+	for _, lang := range languages {
+		locales = append(locales, lang.locale)
+	}
+	return locales
+}
+
+// catalogError returns the given error as an error of the catalog, the
+// failures of the service are logged as their causes are not shown.
+func catalogError(err error) *service.Error {
+	catalogerr := service.AsError(err)
+	if catalogerr.Category == service.CategoryInternal || catalogerr.Category == service.CategoryUnavailable {
+		log.Errorf("%v", catalogerr)
+	}
+	return catalogerr
+}
+
+// localizeError returns the given error as an error of the catalog in the
+// locale of the caller.
+func localizeError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	catalogerr := catalogError(err)
+	return &graphqlError{err: catalogerr, message: catalogerr.Localize(localeFrom(ctx))}
+}
+
+// koResult returns the failed result of an operation with the details of
+// the error in the locale of the caller.
+func koResult(params graphql.ResolveParams, err error) *model.Result {
+	return model.NewErrorResult(errorDetail(params.Context, err))
+}
+
+// errorDetail returns the details of the given error in the locale of the caller.
+func errorDetail(ctx context.Context, err error) *model.ErrorDetail {
+	return catalogError(err).Detail(localeFrom(ctx))
+}
+
+// respondWithCatalogError writes the details of the given error with the
+// http status of its category.
+func respondWithCatalogError(w http.ResponseWriter, r *http.Request, err error) {
+	detail := errorDetail(r.Context(), err)
+	respondWithJSON(w, httpStatus[service.ErrorCategory(detail.Category)], map[string]interface{}{"error": detail})
+}
+
+// localized wraps the resolvers of every field of the given root object,
+// and the subscribers of the subscription fields, so their errors are
+// shown as errors of the catalog.
+func localized(root *graphql.Object) *graphql.Object {
+	for _, field := range root.Fields() {
+		field.Resolve = localizedResolve(field.Resolve)
+		if field.Subscribe != nil {
+			field.Subscribe = localizedResolve(field.Subscribe)
+		}
+	}
+	return root
+}
+
+// localizedResolve returns a resolver that localizes the errors of resolve.
+func localizedResolve(resolve graphql.FieldResolveFn) graphql.FieldResolveFn {
+	return func(params graphql.ResolveParams) (interface{}, error) {
+		result, err := resolve(params)
+		if err != nil {
+			return nil, localizeError(params.Context, err)
+		}
+		return result, nil
+	}
+}
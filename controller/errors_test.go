@@ -0,0 +1,139 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fernandoocampo/pack/auth"
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/service"
+	"github.com/graphql-go/graphql"
+)
+
+// TestParseAcceptLanguage tests the locale chosen from the Accept-Language header
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{name: "empty", header: "", want: "en"},
+		{name: "spanish", header: "es", want: "es"},
+		{name: "spanish region", header: "es-CO", want: "es"},
+		{name: "first supported", header: "fr-FR, es;q=0.8, en;q=0.5", want: "es"},
+		{name: "by quality", header: "en;q=0.4, es-CO;q=0.9", want: "es"},
+		{name: "unsupported", header: "fr, de", want: "en"},
+		{name: "refused", header: "es;q=0", want: "en"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseAcceptLanguage(tt.header); got != tt.want {
+				t.Errorf("parseAcceptLanguage(%q) = %s, want %s", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestKOResult tests failed results carry the details of the catalog error
+func TestKOResult(t *testing.T) {
+	// GIVEN a spanish caller
+	params := graphql.ResolveParams{Context: context.WithValue(context.Background(), localeKey{}, "es")}
+
+	// WHEN a duplicated pack is created
+	result := koResult(params, service.ErrPackDuplicated)
+
+	// THEN the result has the code, category, field and spanish message
+	if result.Success || result.Code != "-1" {
+		t.Fatalf("Expected a failed result but got %+v", result)
+	}
+	if result.Error == nil || result.Error.Code != "07" || result.Error.Category != "CONFLICT" || result.Error.Field != "packcode" {
+		t.Fatalf("Expected error 07 CONFLICT on packcode but got %+v", result.Error)
+	}
+	if result.Msg != "ya existe un paquete del operador con el código o el id de producto" {
+		t.Fatalf("Expected the spanish message but got %s", result.Msg)
+	}
+}
+
+// TestKOResultHidesUnknownErrors tests errors out of the catalog are not shown
+func TestKOResultHidesUnknownErrors(t *testing.T) {
+	params := graphql.ResolveParams{Context: context.Background()}
+
+	result := koResult(params, errors.New("An error updating pack - mongodao: no reachable servers"))
+
+	if result.Error.Code != service.ErrInternal.Code || result.Error.Category != "INTERNAL" {
+		t.Fatalf("Expected an internal error but got %+v", result.Error)
+	}
+	if result.Msg != service.ErrInternal.Message {
+		t.Fatalf("Expected message %s but got %s", service.ErrInternal.Message, result.Msg)
+	}
+}
+
+// TestLocalizedGraphqlError tests graphql errors are shown in the locale
+// asked in the Accept-Language header
+func TestLocalizedGraphqlError(t *testing.T) {
+	// GIVEN a viewer that prefers spanish
+	viewer := &model.Identity{Subject: "viewer", Roles: []string{model.RoleViewer}}
+	request := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+	request.Header.Set("Accept-Language", "es-CO,es;q=0.9")
+	request = request.WithContext(auth.NewContext(request.Context(), viewer))
+
+	// WHEN the viewer tries to move the stock of a pack
+	var result *graphql.Result
+	localize(func(w http.ResponseWriter, r *http.Request) {
+		result = graphql.Do(graphql.Params{
+			Schema:        schema,
+			RequestString: `mutation { moveStock(id:"5a1221a8cc7c76da03df50fc", amount:2) { success } }`,
+			Context:       r.Context(),
+		})
+	})(httptest.NewRecorder(), request)
+
+	// THEN the error is in spanish
+	if len(result.Errors) != 1 {
+		t.Fatalf("Expected 1 error but got %d: %v", len(result.Errors), result.Errors)
+	}
+	if result.Errors[0].Message != "quien llama no tiene permiso para usar el campo" {
+		t.Fatalf("Expected the spanish message but got %s", result.Errors[0].Message)
+	}
+	if result.Errors[0].Extensions["code"] != service.ErrForbidden.Code {
+		t.Fatalf("Expected code %s but got %v", service.ErrForbidden.Code, result.Errors[0].Extensions["code"])
+	}
+}
+
+// TestRespondWithCatalogError tests the http status follows the error category
+func TestRespondWithCatalogError(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/owners/7/statement/csv", nil)
+
+	respondWithCatalogError(recorder, request, service.ErrNotOwner)
+
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("Expected status %d but got %d", http.StatusForbidden, recorder.Code)
+	}
+}
+
+// TestLocalizePack tests the pack texts follow the locale argument and
+// else the Accept-Language header
+func TestLocalizePack(t *testing.T) {
+	pack := &model.Pack{Name: "Navegacion ilimitada", Mno: &model.Mno{ID: 2},
+		Translations: []model.Translation{{Locale: "en", Name: "Unlimited browsing"}}}
+	languages := context.WithValue(context.Background(), languagesKey{}, acceptedLanguages("fr, en-US;q=0.8"))
+	tests := []struct {
+		name   string
+		params graphql.ResolveParams
+		want   string
+	}{
+		{name: "no locale", params: graphql.ResolveParams{Context: context.Background()}, want: "Navegacion ilimitada"},
+		{name: "header", params: graphql.ResolveParams{Context: languages}, want: "Unlimited browsing"},
+		{name: "argument", params: graphql.ResolveParams{Context: languages, Args: map[string]interface{}{"locale": "es"}}, want: "Navegacion ilimitada"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := localizePack(tt.params, pack); got.Name != tt.want {
+				t.Errorf("localizePack() = %q, want %q", got.Name, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/fernandoocampo/pack/auth"
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/service"
+)
+
+// packFilterParams contains the number parameters of a pack filter, they
+// are the arguments of the packs query.
+var packFilterParams = []string{"mnoid", "ownerid", "typeid", "state", "minprice", "maxprice"}
+
+// packFilterTexts contains the text parameters of a pack filter.
+var packFilterTexts = []string{"channel", "region"}
+
+// ExportPacks writes every pack that matches the filter of the parameters
+// as they are read. Parameters:
+// format: csv (default), jsonl or xlsx.
+// mnoid, ownerid, typeid, state, minprice, maxprice, channel, region: the
+// filter of the packs query.
+func ExportPacks(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	identity := auth.FromContext(r.Context())
+	err := authorizeRoute(identity, "exportPacks")
+	if err != nil {
+		log.Warnf("%s is not allowed to export packs", subjectOf(identity))
+		respondWithCatalogError(w, r, err)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = model.ExportCSV
+	}
+	contenttype, ok := model.ExportContentTypes[format]
+	if !ok {
+		respondWithCatalogError(w, r, service.ErrExportFormat)
+		return
+	}
+	filter, err := packFilterFromQuery(r.URL.Query())
+	if err != nil {
+		respondWithCatalogError(w, r, err)
+		return
+	}
+
+	filename := "packs-" + time.Now().Format("20060102") + "." + format
+	w.Header().Set("Content-Type", contenttype)
+	w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+	export := &exportWriter{ResponseWriter: w}
+	err = packService.WithTenant(tenantFrom(r.Context())).Export(export, format, filter)
+	if err != nil && !export.written {
+		w.Header().Del("Content-Disposition")
+		respondWithCatalogError(w, r, err)
+		return
+	}
+	if err != nil {
+		// the status was sent with the first packs, the export is cut
+		log.Errorf("exporting packs as %s: %v", format, err)
+	}
+}
+
+// exportWriter records if the export has started to be written.
+type exportWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+// Write writes a part of the export.
+func (e *exportWriter) Write(content []byte) (int, error) {
+	e.written = true
+	return e.ResponseWriter.Write(content)
+}
+
+// packFilterFromQuery returns the pack filter of the given parameters.
+func packFilterFromQuery(query url.Values) (*model.PackFilter, error) {
+	params := map[string]interface{}{}
+	for _, name := range packFilterParams {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, service.ErrPackFilterInvalid.WithField(name)
+		}
+		params[name] = number
+	}
+	for _, name := range packFilterTexts {
+		if value := query.Get(name); value != "" {
+			params[name] = value
+		}
+	}
+	return model.NewPackFilter(params), nil
+}
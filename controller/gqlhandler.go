@@ -1,21 +1,82 @@
 package controller
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync"
+	"time"
 
+	"github.com/fernandoocampo/pack/auth"
+	"github.com/fernandoocampo/pack/service"
+	"github.com/gorilla/websocket"
 	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
 	"github.com/graphql-go/handler"
 )
 
-// define schema, with our rootQuery and rootMutation
-// TODO root query and mutation are pending
+// define schema, with our rootQuery, rootMutation and rootSubscription,
+// every root field checks the permissions of the caller and shows its
+// errors from the catalog.
 var schema, _ = graphql.NewSchema(graphql.SchemaConfig{
-	Query:    rootQuery,
-	Mutation: packMutation,
+	Query:        localized(authorized(rootQuery)),
+	Mutation:     localized(authorized(packMutation)),
+	Subscription: localized(authorized(rootSubscription)),
 })
 
+// Subprotocols of graphql over websocket
+const (
+	graphqlWS          = "graphql-ws"           // subscriptions-transport-ws protocol
+	graphqlTransportWS = "graphql-transport-ws" // graphql-ws protocol, the successor of subscriptions-transport-ws
+)
+
+// Limits of the graphql websocket connections
+const (
+	wsInitTimeout      = 10 * time.Second // wait for the connection_init message
+	wsWriteTimeout     = 10 * time.Second // wait for the client to take a message, it is disconnected after it
+	wsKeepAlive        = 20 * time.Second // time between keep alive messages
+	wsReadLimit        = 64 * 1024        // longest message of a client
+	wsMaxSubscriptions = 20               // subscriptions of a connection at once
+	wsSendBuffer       = 16               // messages waiting to be written to the client
+)
+
+// wsUpgrader upgrades graphql requests to websockets. Credentials come in
+// the connection_init message and not in cookies, so any origin is
+// accepted.
+var wsUpgrader = websocket.Upgrader{
+	Subprotocols: []string{graphqlTransportWS, graphqlWS},
+	CheckOrigin: func(r *http.Request) bool {
+		return true
+	},
+}
+
+// wsMessage is a message of the graphql websocket protocols.
+type wsMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// wsOperation is the payload of the message that starts a subscription.
+type wsOperation struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables"`
+	OperationName string                 `json:"operationName"`
+}
+
+// wsConnection is a graphql websocket connection with its subscriptions.
+// Messages are written by one goroutine in the order they are sent.
+type wsConnection struct {
+	conn       *websocket.Conn
+	protocol   string
+	ctx        context.Context // ends when the connection is closed
+	cancel     context.CancelFunc
+	send       chan wsMessage
+	mutex      sync.Mutex
+	operations map[string]context.CancelFunc
+}
+
 // HttpGet is the handler function to attend all http get requests
 // It gets the graphql parameter and delegates to business logic
 // function.
@@ -24,6 +85,7 @@ func httpGet(w http.ResponseWriter, r *http.Request) {
 	result := graphql.Do(graphql.Params{
 		Schema:        schema,
 		RequestString: query,
+		Context:       r.Context(),
 	})
 
 	if len(result.Errors) > 0 {
@@ -58,6 +120,7 @@ func httpPost(w http.ResponseWriter, r *http.Request) {
 		VariableValues: opts.Variables,
 		OperationName:  opts.OperationName,
 		RootObject:     rootValue,
+		Context:        r.Context(),
 	}
 
 	result := graphql.Do(params)
@@ -72,3 +135,258 @@ func httpPost(w http.ResponseWriter, r *http.Request) {
 		log.Println(err.Error())
 	}
 }
+
+// httpWebSocket serves graphql subscriptions over a websocket with the
+// graphql-transport-ws or the graphql-ws subprotocol. The client is
+// authenticated with the connection_init payload, whose string values are
+// taken as headers, e.g. {"Authorization": "Bearer <jwt>"}.
+//
+// A client that does not keep up is not waited for: a subscription whose
+// events pile up ends with service.ErrSubscriptionBehind and a client that
+// does not take a message in wsWriteTimeout is disconnected.
+func httpWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Warnf("graphql websocket cannot be opened: %s", err)
+		return
+	}
+	ctx, cancel := context.WithCancel(r.Context())
+	connection := &wsConnection{conn: conn, protocol: conn.Subprotocol(), ctx: ctx, cancel: cancel,
+		send: make(chan wsMessage, wsSendBuffer), operations: map[string]context.CancelFunc{}}
+	if connection.protocol == "" {
+		connection.protocol = graphqlWS
+	}
+	defer conn.Close()
+	defer cancel()
+	conn.SetReadLimit(wsReadLimit)
+
+	opctx, ok := connection.init(r)
+	if !ok {
+		return
+	}
+	go connection.write()
+	connection.read(opctx)
+}
+
+// init waits for the connection_init message and authenticates the
+// client. It returns the context of the operations of the client.
+func (c *wsConnection) init(r *http.Request) (context.Context, bool) {
+	_ = c.conn.SetReadDeadline(time.Now().Add(wsInitTimeout))
+	message := wsMessage{}
+	err := c.conn.ReadJSON(&message)
+	if err != nil {
+		c.reject(4408, "Connection initialisation timeout")
+		return nil, false
+	}
+	if message.Type != "connection_init" {
+		c.reject(4401, "Unauthorized")
+		return nil, false
+	}
+	identity, err := authenticator.Authenticate(initRequest(r, message.Payload))
+	if err != nil {
+		log.Warnf("graphql websocket rejected: %s", err)
+		c.reject(4403, "Forbidden")
+		return nil, false
+	}
+	_ = c.conn.SetReadDeadline(time.Time{})
+	_ = c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	err = c.conn.WriteJSON(wsMessage{Type: "connection_ack"})
+	if err != nil {
+		return nil, false
+	}
+	return auth.NewContext(c.ctx, identity), true
+}
+
+// reject tells the client why the connection is closed, in graphql-ws
+// with a connection_error message and in graphql-transport-ws with the
+// close code.
+func (c *wsConnection) reject(code int, reason string) {
+	deadline := time.Now().Add(wsWriteTimeout)
+	if c.protocol == graphqlWS {
+		payload, _ := json.Marshal(map[string]string{"message": reason})
+		_ = c.conn.SetWriteDeadline(deadline)
+		_ = c.conn.WriteJSON(wsMessage{Type: "connection_error", Payload: payload})
+		code = websocket.CloseNormalClosure
+	}
+	_ = c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), deadline)
+}
+
+// initRequest returns the upgrade request with the string values of the
+// connection_init payload as headers, so the client is authenticated as
+// any http caller.
+func initRequest(r *http.Request, payload json.RawMessage) *http.Request {
+	params := map[string]interface{}{}
+	_ = json.Unmarshal(payload, &params)
+	initrequest := r.Clone(r.Context())
+	for key, value := range params {
+		if text, ok := value.(string); ok {
+			initrequest.Header.Set(key, text)
+		}
+	}
+	return initrequest
+}
+
+// read handles the messages of the client until it goes away.
+func (c *wsConnection) read(opctx context.Context) {
+	for {
+		message := wsMessage{}
+		err := c.conn.ReadJSON(&message)
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				log.Warnf("graphql websocket closed: %s", err)
+			}
+			return
+		}
+		switch message.Type {
+		case "start", "subscribe":
+			c.start(opctx, message)
+		case "stop", "complete":
+			c.stop(message.ID)
+		case "ping":
+			c.push(wsMessage{Type: "pong"})
+		case "pong":
+		case "connection_terminate":
+			return
+		default:
+			c.fail(message.ID, service.ErrSubscriptionInvalid)
+		}
+	}
+}
+
+// start runs the subscription of the message.
+func (c *wsConnection) start(opctx context.Context, message wsMessage) {
+	operation := wsOperation{}
+	err := json.Unmarshal(message.Payload, &operation)
+	if err != nil || message.ID == "" || operation.Query == "" {
+		c.fail(message.ID, service.ErrSubscriptionInvalid)
+		return
+	}
+	c.mutex.Lock()
+	_, running := c.operations[message.ID]
+	if running || len(c.operations) >= wsMaxSubscriptions {
+		c.mutex.Unlock()
+		if running {
+			c.fail(message.ID, service.ErrSubscriptionInvalid.WithField("id"))
+		} else {
+			c.fail(message.ID, service.ErrSubscriptionLimit)
+		}
+		return
+	}
+	ctx, cancel := context.WithCancel(opctx)
+	c.operations[message.ID] = cancel
+	c.mutex.Unlock()
+
+	go c.run(ctx, message.ID, operation)
+}
+
+// run sends the results of a subscription until it ends. The results are
+// always read so the graphql executor is never left waiting.
+func (c *wsConnection) run(ctx context.Context, id string, operation wsOperation) {
+	results := graphql.Subscribe(graphql.Params{
+		Schema:         schema,
+		RequestString:  operation.Query,
+		VariableValues: operation.Variables,
+		OperationName:  operation.OperationName,
+		Context:        ctx,
+	})
+	next := "next"
+	if c.protocol == graphqlWS {
+		next = "data"
+	}
+	for result := range results {
+		payload, err := json.Marshal(result)
+		if err != nil {
+			log.Errorf("result of subscription %s cannot be sent: %s", id, err)
+			continue
+		}
+		c.push(wsMessage{ID: id, Type: next, Payload: payload})
+	}
+
+	cancel, running := c.take(id)
+	if running {
+		// the subscription ended on its own, the client did not stop it
+		cancel()
+		c.push(wsMessage{ID: id, Type: "complete"})
+	}
+}
+
+// stop ends the subscription with the given id.
+func (c *wsConnection) stop(id string) {
+	cancel, running := c.take(id)
+	if running {
+		cancel()
+	}
+}
+
+// take removes the subscription with the given id and returns its cancel
+// function, false if it is not running. The map is copied as the delete
+// builtin is shadowed by the delete resolver of this package.
+func (c *wsConnection) take(id string) (context.CancelFunc, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	cancel, running := c.operations[id]
+	if !running {
+		return nil, false
+	}
+	operations := make(map[string]context.CancelFunc, len(c.operations))
+	for key, value := range c.operations {
+		if key != id {
+			operations[key] = value
+		}
+	}
+	c.operations = operations
+	return cancel, true
+}
+
+// fail sends the given error of the operation with the given id.
+func (c *wsConnection) fail(id string, err error) {
+	localized := localizeError(c.ctx, err)
+	formatted := gqlerrors.FormatError(&gqlerrors.Error{Message: localized.Error(), OriginalError: localized})
+	var payload []byte
+	if c.protocol == graphqlWS {
+		payload, _ = json.Marshal(formatted)
+	} else {
+		payload, _ = json.Marshal([]gqlerrors.FormattedError{formatted})
+	}
+	c.push(wsMessage{ID: id, Type: "error", Payload: payload})
+}
+
+// push queues a message to the client, it returns false if the connection
+// is closed.
+func (c *wsConnection) push(message wsMessage) bool {
+	select {
+	case c.send <- message:
+		return true
+	case <-c.ctx.Done():
+		return false
+	}
+}
+
+// write writes the queued messages and the keep alive messages until the
+// connection is closed. A client that does not take a message in time
+// is disconnected.
+func (c *wsConnection) write() {
+	keepalive := time.NewTicker(wsKeepAlive)
+	defer keepalive.Stop()
+	alive := wsMessage{Type: "ping"}
+	if c.protocol == graphqlWS {
+		alive = wsMessage{Type: "ka"}
+	}
+	for {
+		message := alive
+		select {
+		case <-c.ctx.Done():
+			return
+		case message = <-c.send:
+		case <-keepalive.C:
+		}
+		_ = c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+		err := c.conn.WriteJSON(message)
+		if err != nil {
+			log.Warnf("graphql websocket client is gone: %s", err)
+			c.cancel()
+			_ = c.conn.Close()
+			return
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/fernandoocampo/pack/auth"
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/packpb"
+	"github.com/fernandoocampo/pack/service"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// grpcListPage is the number of packs read at once by ListPacks.
+const grpcListPage = 500
+
+// grpcExportChunk is the size of the biggest chunk sent by ExportPacks.
+const grpcExportChunk = 32 << 10
+
+// packServer implements packpb.PackServiceServer with the pack service of
+// the tenant of the caller.
+type packServer struct {
+	packpb.UnimplementedPackServiceServer
+}
+
+// GetPack implements packpb.PackServiceServer.GetPack.
+func (s *packServer) GetPack(ctx context.Context, request *packpb.GetPackRequest) (*packpb.Pack, error) {
+	pack, err := grpcCurrentPack(ctx, request.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return packMessage(pack), nil
+}
+
+// GetPackByCode implements packpb.PackServiceServer.GetPackByCode.
+func (s *packServer) GetPackByCode(ctx context.Context, request *packpb.GetPackByCodeRequest) (*packpb.Pack, error) {
+	pack, err := grpcPackService(ctx).GetByPackCode(request.GetPackCode())
+	if err != nil {
+		return nil, err
+	}
+	if pack == nil {
+		return nil, service.ErrPackNotFound.WithField("packcode")
+	}
+	return packMessage(pack), nil
+}
+
+// GetPackByProductID implements packpb.PackServiceServer.GetPackByProductID.
+func (s *packServer) GetPackByProductID(ctx context.Context, request *packpb.GetPackByProductIDRequest) (*packpb.Pack, error) {
+	pack, err := grpcPackService(ctx).GetByProductID(request.GetProdId())
+	if err != nil {
+		return nil, err
+	}
+	if pack == nil {
+		return nil, service.ErrPackNotFound.WithField("prodid")
+	}
+	return packMessage(pack), nil
+}
+
+// CreatePack implements packpb.PackServiceServer.CreatePack.
+func (s *packServer) CreatePack(ctx context.Context, request *packpb.CreatePackRequest) (*packpb.Pack, error) {
+	pack := packFromMessage(request.GetPack())
+	pack.ID = ""
+	err := grpcPackService(ctx).Create(pack)
+	if err != nil {
+		return nil, err
+	}
+	return packMessage(pack), nil
+}
+
+// UpdatePack implements packpb.PackServiceServer.UpdatePack, the price
+// can only be changed by pricing managers, the owner by admins and the
+// other data by catalog roles.
+func (s *packServer) UpdatePack(ctx context.Context, request *packpb.UpdatePackRequest) (*packpb.Pack, error) {
+	pack, err := grpcCurrentPack(ctx, request.GetId())
+	if err != nil {
+		return nil, err
+	}
+	newpack := packFromMessage(request.GetPack())
+	err = authorizePackChange(auth.FromContext(ctx), pack, newpack)
+	if err != nil {
+		return nil, err
+	}
+	return grpcChangePack(ctx, request.GetId(), request.Version, func(packs service.IPackService) error {
+		return packs.Update(request.GetId(), newpack)
+	})
+}
+
+// ChangePackState implements packpb.PackServiceServer.ChangePackState.
+func (s *packServer) ChangePackState(ctx context.Context, request *packpb.ChangePackStateRequest) (*packpb.Pack, error) {
+	return grpcChangePack(ctx, request.GetId(), request.Version, func(packs service.IPackService) error {
+		return packs.ChangeState(request.GetId(), model.PackState(request.GetState()))
+	})
+}
+
+// ChangePackPrice implements packpb.PackServiceServer.ChangePackPrice.
+func (s *packServer) ChangePackPrice(ctx context.Context, request *packpb.ChangePackPriceRequest) (*packpb.Pack, error) {
+	return grpcChangePack(ctx, request.GetId(), request.Version, func(packs service.IPackService) error {
+		return packs.ChangePrice(request.GetId(), int(request.GetPrice()))
+	})
+}
+
+// MoveStock implements packpb.PackServiceServer.MoveStock.
+func (s *packServer) MoveStock(ctx context.Context, request *packpb.MoveStockRequest) (*packpb.Pack, error) {
+	return grpcChangePack(ctx, request.GetId(), request.Version, func(packs service.IPackService) error {
+		return packs.MoveStock(request.GetId(), int(request.GetAmount()))
+	})
+}
+
+// ReplaceResources implements packpb.PackServiceServer.ReplaceResources.
+func (s *packServer) ReplaceResources(ctx context.Context, request *packpb.ReplaceResourcesRequest) (*packpb.Pack, error) {
+	return grpcChangePack(ctx, request.GetId(), request.Version, func(packs service.IPackService) error {
+		return packs.UpdateResources(request.GetId(), resourcesFromMessages(request.GetResources()))
+	})
+}
+
+// DeleteResources implements packpb.PackServiceServer.DeleteResources.
+func (s *packServer) DeleteResources(ctx context.Context, request *packpb.DeleteResourcesRequest) (*packpb.Pack, error) {
+	return grpcChangePack(ctx, request.GetId(), request.Version, func(packs service.IPackService) error {
+		return packs.DeleteResources(request.GetId())
+	})
+}
+
+// SetTranslation implements packpb.PackServiceServer.SetTranslation.
+func (s *packServer) SetTranslation(ctx context.Context, request *packpb.SetTranslationRequest) (*packpb.Pack, error) {
+	return grpcChangePack(ctx, request.GetId(), request.Version, func(packs service.IPackService) error {
+		return packs.SetTranslation(request.GetId(), translationFromMessage(request.GetTranslation()))
+	})
+}
+
+// RemoveTranslation implements packpb.PackServiceServer.RemoveTranslation.
+func (s *packServer) RemoveTranslation(ctx context.Context, request *packpb.RemoveTranslationRequest) (*packpb.Pack, error) {
+	return grpcChangePack(ctx, request.GetId(), request.Version, func(packs service.IPackService) error {
+		return packs.RemoveTranslation(request.GetId(), request.GetLocale())
+	})
+}
+
+// TransferOwnership implements packpb.PackServiceServer.TransferOwnership,
+// the pack may not be visible to the caller afterwards.
+func (s *packServer) TransferOwnership(ctx context.Context, request *packpb.TransferOwnershipRequest) (*emptypb.Empty, error) {
+	if !bson.IsObjectIdHex(request.GetId()) {
+		return nil, service.ErrPackNotFound.WithField("id")
+	}
+	err := grpcVersionedService(ctx, request.Version).TransferOwnership(request.GetId(), int(request.GetNewOwnerId()))
+	if err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// DeletePack implements packpb.PackServiceServer.DeletePack.
+func (s *packServer) DeletePack(ctx context.Context, request *packpb.DeletePackRequest) (*emptypb.Empty, error) {
+	if !bson.IsObjectIdHex(request.GetId()) {
+		return nil, service.ErrPackNotFound.WithField("id")
+	}
+	err := grpcVersionedService(ctx, request.Version).Delete(request.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// SearchPacks implements packpb.PackServiceServer.SearchPacks.
+func (s *packServer) SearchPacks(ctx context.Context, request *packpb.SearchPacksRequest) (*packpb.SearchPacksResponse, error) {
+	packs, err := grpcPackService(ctx).Search(request.GetText(), int(request.GetLimit()))
+	if err != nil {
+		return nil, err
+	}
+	return &packpb.SearchPacksResponse{Packs: packMessages(packs)}, nil
+}
+
+// ListPacks implements packpb.PackServiceServer.ListPacks, the packs are
+// read by pages and sent as they are read.
+func (s *packServer) ListPacks(request *packpb.ListPacksRequest, stream packpb.PackService_ListPacksServer) error {
+	if request.GetSkip() < 0 {
+		return service.ErrPackFilterInvalid.WithField("skip")
+	}
+	if request.GetLimit() < 0 {
+		return service.ErrPackFilterInvalid.WithField("limit")
+	}
+	packs := grpcPackService(stream.Context())
+	filter := packFilterFromMessage(request.GetFilter())
+	skip, remaining := int(request.GetSkip()), int(request.GetLimit())
+	for {
+		size := grpcListPage
+		if remaining > 0 && remaining < size {
+			size = remaining
+		}
+		page, err := packs.List(filter, skip, size)
+		if err != nil {
+			return err
+		}
+		for i := range page {
+			err = stream.Send(packMessage(&page[i]))
+			if err != nil {
+				return err
+			}
+		}
+		skip += len(page)
+		remaining -= len(page)
+		if len(page) < size || (request.GetLimit() > 0 && remaining <= 0) {
+			return nil
+		}
+	}
+}
+
+// ExportPacks implements packpb.PackServiceServer.ExportPacks.
+func (s *packServer) ExportPacks(request *packpb.ExportPacksRequest, stream packpb.PackService_ExportPacksServer) error {
+	format := request.GetFormat()
+	if format == "" {
+		format = model.ExportCSV
+	}
+	contenttype, ok := model.ExportContentTypes[format]
+	if !ok {
+		return service.ErrExportFormat
+	}
+	writer := &exportChunkWriter{stream: stream, contentType: contenttype}
+	return grpcPackService(stream.Context()).Export(writer, format, packFilterFromMessage(request.GetFilter()))
+}
+
+// exportChunkWriter sends the written data in chunks of an export, the
+// first chunk has the content type.
+type exportChunkWriter struct {
+	stream      packpb.PackService_ExportPacksServer
+	contentType string
+}
+
+// Write sends the data in chunks of grpcExportChunk bytes at most.
+func (w *exportChunkWriter) Write(data []byte) (int, error) {
+	written := 0
+	for written < len(data) {
+		end := written + grpcExportChunk
+		if end > len(data) {
+			end = len(data)
+		}
+		chunk := &packpb.ExportChunk{Data: append([]byte(nil), data[written:end]...), ContentType: w.contentType}
+		err := w.stream.Send(chunk)
+		if err != nil {
+			return written, err
+		}
+		w.contentType = ""
+		written = end
+	}
+	return written, nil
+}
+
+// grpcPackService returns the pack service scoped to the tenant of the
+// caller.
+func grpcPackService(ctx context.Context) service.IPackService {
+	return packService.WithTenant(tenantFrom(ctx))
+}
+
+// grpcVersionedService returns the pack service of the tenant of the
+// caller whose changes only apply to a pack at the version, if it is
+// given.
+func grpcVersionedService(ctx context.Context, version *int64) service.IPackService {
+	packs := grpcPackService(ctx)
+	if version != nil {
+		return packs.WithVersion(int(*version))
+	}
+	return packs
+}
+
+// grpcCurrentPack returns the pack with the given id if the caller can
+// see it.
+func grpcCurrentPack(ctx context.Context, id string) (*model.Pack, error) {
+	if !bson.IsObjectIdHex(id) {
+		return nil, service.ErrPackNotFound.WithField("id")
+	}
+	pack, err := grpcPackService(ctx).FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if pack == nil {
+		return nil, service.ErrPackNotFound.WithField("id")
+	}
+	return pack, nil
+}
+
+// grpcChangePack makes the change of the pack with the service at the
+// given version and returns the changed pack.
+func grpcChangePack(ctx context.Context, id string, version *int64, change func(packs service.IPackService) error) (*packpb.Pack, error) {
+	if !bson.IsObjectIdHex(id) {
+		return nil, service.ErrPackNotFound.WithField("id")
+	}
+	err := change(grpcVersionedService(ctx, version))
+	if err != nil {
+		return nil, err
+	}
+	pack, err := grpcCurrentPack(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return packMessage(pack), nil
+}
@@ -0,0 +1,187 @@
+package controller
+
+import (
+	"time"
+
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/packpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// packMessage returns the grpc message of the pack.
+func packMessage(pack *model.Pack) *packpb.Pack {
+	message := &packpb.Pack{
+		Id:         pack.ID.Hex(),
+		ProdId:     pack.ProdID,
+		PackCode:   pack.Packcode,
+		Name:       pack.Name,
+		Desc:       pack.Desc,
+		ImageUrl:   pack.Img,
+		Keywords:   pack.Kwds,
+		Price:      int64(pack.Price),
+		Stock:      int64(pack.Stock),
+		OwnerId:    int64(pack.Ownerid),
+		Created:    timestampMessage(pack.Created),
+		Updated:    timestampMessage(pack.Updated),
+		Version:    int64(pack.Version),
+		State:      packpb.PackState(pack.State),
+		Components: pack.Components,
+		Channels:   pack.Channels,
+		Regions:    pack.Regions,
+		Locale:     pack.Locale,
+	}
+	if pack.Packtype != nil {
+		message.Type = &packpb.PackType{Id: int32(pack.Packtype.ID), Name: pack.Packtype.Name}
+	}
+	if pack.Mno != nil {
+		message.Mno = &packpb.Mno{Id: int32(pack.Mno.ID), Name: pack.Mno.Name}
+	}
+	if pack.Term != nil {
+		message.Term = &packpb.Term{UnitId: int32(pack.Term.UnitID), Unit: pack.Term.Unit, Amount: int32(pack.Term.Amount)}
+	}
+	if pack.Ccy != nil {
+		message.Currency = &packpb.Currency{Id: int32(pack.Ccy.ID), Name: pack.Ccy.Name}
+	}
+	message.Resources = resourceMessages(pack.Resources)
+	for _, rule := range pack.Rules {
+		message.Rules = append(message.Rules, &packpb.EligibilityRule{
+			Name:         rule.Name,
+			Segments:     rule.Segments,
+			PlanTypes:    rule.PlanTypes,
+			Regions:      rule.Regions,
+			MinLineAge:   int32(rule.MinLineAge),
+			MaxLineAge:   int32(rule.MaxLineAge),
+			Purchased:    rule.Purchased,
+			NotPurchased: rule.NotPurchased,
+		})
+	}
+	for _, translation := range pack.Translations {
+		message.Translations = append(message.Translations, translationMessage(translation))
+	}
+	return message
+}
+
+// packMessages returns the grpc messages of the packs.
+func packMessages(packs []model.Pack) []*packpb.Pack {
+	messages := make([]*packpb.Pack, 0, len(packs))
+	for i := range packs {
+		messages = append(messages, packMessage(&packs[i]))
+	}
+	return messages
+}
+
+// packFromMessage returns the catalog data of the pack of the grpc
+// message, the data changed by other operations is left out.
+func packFromMessage(message *packpb.Pack) *model.Pack {
+	pack := &model.Pack{
+		ProdID:   message.GetProdId(),
+		Packcode: message.GetPackCode(),
+		Name:     message.GetName(),
+		Desc:     message.GetDesc(),
+		Img:      message.GetImageUrl(),
+		Kwds:     message.GetKeywords(),
+		Price:    int(message.GetPrice()),
+		Ownerid:  int(message.GetOwnerId()),
+	}
+	if bson.IsObjectIdHex(message.GetId()) {
+		pack.ID = bson.ObjectIdHex(message.GetId())
+	}
+	if packtype := message.GetType(); packtype != nil {
+		pack.Packtype = &model.Type{ID: int8(packtype.GetId()), Name: packtype.GetName()}
+	}
+	if mno := message.GetMno(); mno != nil {
+		pack.Mno = &model.Mno{ID: int8(mno.GetId()), Name: mno.GetName()}
+	}
+	if term := message.GetTerm(); term != nil {
+		pack.Term = &model.Term{UnitID: int8(term.GetUnitId()), Unit: term.GetUnit(), Amount: int(term.GetAmount())}
+	}
+	if currency := message.GetCurrency(); currency != nil {
+		pack.Ccy = &model.Currency{ID: int8(currency.GetId()), Name: currency.GetName()}
+	}
+	if len(message.GetResources()) > 0 {
+		pack.Resources = resourcesFromMessages(message.GetResources())
+	}
+	for _, translation := range message.GetTranslations() {
+		pack.Translations = append(pack.Translations, translationFromMessage(translation))
+	}
+	return pack
+}
+
+// resourceMessages returns the grpc messages of the resources.
+func resourceMessages(resources []model.Resource) []*packpb.Resource {
+	messages := make([]*packpb.Resource, 0, len(resources))
+	for _, resource := range resources {
+		messages = append(messages, &packpb.Resource{
+			Id:     int32(resource.ID),
+			Name:   resource.Name,
+			Units:  resource.Units,
+			Amount: resource.Amount,
+			IsFree: resource.Isfree,
+		})
+	}
+	return messages
+}
+
+// resourcesFromMessages returns the resources of the grpc messages.
+func resourcesFromMessages(messages []*packpb.Resource) []model.Resource {
+	resources := make([]model.Resource, 0, len(messages))
+	for _, message := range messages {
+		resources = append(resources, model.Resource{
+			ID:     int16(message.GetId()),
+			Name:   message.GetName(),
+			Units:  message.GetUnits(),
+			Amount: message.GetAmount(),
+			Isfree: message.GetIsFree(),
+		})
+	}
+	return resources
+}
+
+// translationMessage returns the grpc message of the translation.
+func translationMessage(translation model.Translation) *packpb.Translation {
+	return &packpb.Translation{
+		Locale:   translation.Locale,
+		Name:     translation.Name,
+		Desc:     translation.Desc,
+		Keywords: translation.Kwds,
+	}
+}
+
+// translationFromMessage returns the translation of the grpc message.
+func translationFromMessage(message *packpb.Translation) model.Translation {
+	return model.Translation{
+		Locale: message.GetLocale(),
+		Name:   message.GetName(),
+		Desc:   message.GetDesc(),
+		Kwds:   message.GetKeywords(),
+	}
+}
+
+// packFilterFromMessage returns the pack filter of the grpc message, a
+// missing filter matches any pack.
+func packFilterFromMessage(message *packpb.PackFilter) *model.PackFilter {
+	filter := &model.PackFilter{
+		MnoID:    int8(message.GetMnoId()),
+		Ownerid:  int(message.GetOwnerId()),
+		TypeID:   int8(message.GetTypeId()),
+		MinPrice: int(message.GetMinPrice()),
+		MaxPrice: int(message.GetMaxPrice()),
+		Channel:  model.NormalizeAvailabilityCode(message.GetChannel()),
+		Region:   model.NormalizeAvailabilityCode(message.GetRegion()),
+	}
+	if message != nil && message.State != nil {
+		state := model.PackState(message.GetState())
+		filter.State = &state
+	}
+	return filter
+}
+
+// timestampMessage returns the grpc timestamp of the time, nil for the
+// zero time.
+func timestampMessage(moment time.Time) *timestamppb.Timestamp {
+	if moment.IsZero() {
+		return nil
+	}
+	return timestamppb.New(moment)
+}
@@ -0,0 +1,202 @@
+package controller
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/fernandoocampo/pack/auth"
+	"github.com/fernandoocampo/pack/packpb"
+	"github.com/fernandoocampo/pack/service"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// grpcHealthInterval is the time between two checks of the health of
+// the resources served by the grpc health protocol.
+const grpcHealthInterval = 10 * time.Second
+
+// grpcErrorDomain is the domain of the ErrorInfo details of the grpc
+// errors.
+const grpcErrorDomain = "pack"
+
+// grpcCodes contains the grpc code answered for every error category.
+var grpcCodes = map[service.ErrorCategory]codes.Code{
+	service.CategoryInvalid:      codes.InvalidArgument,
+	service.CategoryForbidden:    codes.PermissionDenied,
+	service.CategoryNotFound:     codes.NotFound,
+	service.CategoryConflict:     codes.FailedPrecondition,
+	service.CategoryPrecondition: codes.Aborted,
+	service.CategoryInternal:     codes.Internal,
+	service.CategoryUnavailable:  codes.Unavailable,
+}
+
+// StartGRPCServer starts the grpc server for this service on the given
+// port, it serves the pack service and the grpc health protocol.
+func StartGRPCServer(port string) {
+	listener, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		log.Panicf("An error occured starting gRPC listener at port %s, error %s", port, err)
+	}
+	server, healthserver := NewGRPCServer()
+	done := make(chan struct{})
+	defer close(done)
+	go watchGRPCHealth(healthserver, grpcHealthInterval, done)
+
+	log.Info("Starting gRPC service at ", port)
+	err = server.Serve(listener)
+	if err != nil {
+		log.Panicf("An error occured serving gRPC at port %s, error %s", port, err)
+	}
+}
+
+// NewGRPCServer returns a grpc server with the pack service and the
+// health service, the health of the pack service is set by the caller.
+func NewGRPCServer() (*grpc.Server, *health.Server) {
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(grpcUnaryCaller),
+		grpc.ChainStreamInterceptor(grpcStreamCaller))
+	packpb.RegisterPackServiceServer(server, new(packServer))
+	healthserver := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthserver)
+	return server, healthserver
+}
+
+// watchGRPCHealth sets the health of the server and the pack service from
+// the health of their resources every interval until done is closed.
+func watchGRPCHealth(healthserver *health.Server, interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		serving := healthpb.HealthCheckResponse_NOT_SERVING
+		if isHealthy() {
+			serving = healthpb.HealthCheckResponse_SERVING
+		}
+		healthserver.SetServingStatus("", serving)
+		healthserver.SetServingStatus(packpb.PackService_ServiceDesc.ServiceName, serving)
+		select {
+		case <-done:
+			healthserver.Shutdown()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// isHealthy returns true if the service and every db it uses are up.
+func isHealthy() bool {
+	health := healthservice.Health()
+	if health == nil || !health.ServiceStatus {
+		return false
+	}
+	for _, db := range health.DBClients {
+		if !db.Status {
+			return false
+		}
+	}
+	return true
+}
+
+// grpcUnaryCaller authenticates and authorizes the caller of a method of
+// the pack service and returns its errors as grpc errors.
+func grpcUnaryCaller(ctx context.Context, request interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := grpcCaller(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	response, err := handler(ctx, request)
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return response, nil
+}
+
+// grpcStreamCaller is grpcUnaryCaller for the streaming methods.
+func grpcStreamCaller(server interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := grpcCaller(stream.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	err = handler(server, &callerStream{ServerStream: stream, ctx: ctx})
+	if err != nil {
+		return grpcError(ctx, err)
+	}
+	return nil
+}
+
+// callerStream is a server stream whose context has the caller.
+type callerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context with the caller.
+func (s *callerStream) Context() context.Context {
+	return s.ctx
+}
+
+// grpcCaller returns the context of the call with the identity and the
+// languages of the caller. The metadata is taken as the headers of a
+// request, so the callers use the credentials of the http apis. The
+// methods of other services, as the health service, are open to any
+// caller.
+func grpcCaller(ctx context.Context, fullmethod string) (context.Context, error) {
+	prefix := "/" + packpb.PackService_ServiceDesc.ServiceName + "/"
+	if !strings.HasPrefix(fullmethod, prefix) {
+		return ctx, nil
+	}
+	request, _ := http.NewRequestWithContext(ctx, http.MethodPost, fullmethod, nil)
+	md, _ := metadata.FromIncomingContext(ctx)
+	for key, values := range md {
+		for _, value := range values {
+			request.Header.Add(key, value)
+		}
+	}
+	ctx = withLanguages(ctx, request.Header.Get("Accept-Language"))
+	identity, err := authenticator.Authenticate(request)
+	if err != nil {
+		if err != auth.ErrNoCredentials {
+			log.Warnf("rejected grpc credentials for %s: %v", fullmethod, err)
+		}
+		return nil, status.Error(codes.Unauthenticated, auth.ErrInvalidCredentials.Error())
+	}
+	method := strings.TrimPrefix(fullmethod, prefix)
+	err = allow(identity, method, rpcPermissions[method])
+	if err != nil {
+		log.Warnf("%s is not allowed to use %s", subjectOf(identity), method)
+		return nil, grpcError(ctx, err)
+	}
+	return auth.NewContext(ctx, identity), nil
+}
+
+// grpcError returns the given error as a grpc error with the code of its
+// category and the message in the locale of the caller. Its ErrorInfo
+// detail has the code of the catalog error as reason and its category
+// and field as metadata.
+func grpcError(ctx context.Context, err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	catalogerr := catalogError(err)
+	errorstatus := status.New(grpcCodes[catalogerr.Category], catalogerr.Localize(localeFrom(ctx)))
+	info := &errdetails.ErrorInfo{
+		Reason:   catalogerr.Code,
+		Domain:   grpcErrorDomain,
+		Metadata: map[string]string{"category": string(catalogerr.Category)},
+	}
+	if catalogerr.Field != "" {
+		info.Metadata["field"] = catalogerr.Field
+	}
+	detailed, detailerr := errorstatus.WithDetails(info)
+	if detailerr != nil {
+		return errorstatus.Err()
+	}
+	return detailed.Err()
+}
@@ -0,0 +1,248 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/packpb"
+	"github.com/fernandoocampo/pack/service"
+	"github.com/fernandoocampo/pack/util"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// streamPackService is the rest fake with pages of the packs sorted by
+// pack code and their export.
+type streamPackService struct {
+	*restPackService
+}
+
+func (s *streamPackService) WithTenant(tenant *model.Tenant) service.IPackService {
+	return s
+}
+
+func (s *streamPackService) List(filter *model.PackFilter, skip int, limit int) ([]model.Pack, error) {
+	packs, _ := s.restPackService.List(filter, 0, 0)
+	sort.Slice(packs, func(i, j int) bool { return packs[i].Packcode < packs[j].Packcode })
+	if skip > len(packs) {
+		skip = len(packs)
+	}
+	packs = packs[skip:]
+	if len(packs) > limit {
+		packs = packs[:limit]
+	}
+	return packs, nil
+}
+
+func (s *streamPackService) Export(w io.Writer, format string, filter *model.PackFilter) error {
+	_, err := w.Write([]byte("packcode,name\n0008,Whatsapp\n"))
+	return err
+}
+
+// fakeHealth returns the given status of the db.
+type fakeHealth struct {
+	db bool
+}
+
+func (h fakeHealth) Health() *util.HealthStatus {
+	health := util.NewHealthStatus("pack", true, nil)
+	health.AddDBToHealthStatus(util.NewDBHealth("packmongo", h.db, ""))
+	return health
+}
+
+// newGRPCTest returns a client of a grpc server in memory with the packs
+// and tokens of newRestTest.
+func newGRPCTest(t *testing.T) (*grpc.ClientConn, *restPackService, *model.Pack) {
+	_, packs, pack := newRestTest(t)
+	SetService(&streamPackService{restPackService: packs})
+	oldhealth := healthservice
+	t.Cleanup(func() { SetHealthService(oldhealth) })
+	SetHealthService(fakeHealth{db: true})
+
+	listener := bufconn.Listen(1 << 20)
+	server, healthserver := NewGRPCServer()
+	done, watched := make(chan struct{}), make(chan struct{})
+	go func() {
+		watchGRPCHealth(healthserver, time.Hour, done)
+		close(watched)
+	}()
+	go server.Serve(listener)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		server.Stop()
+		close(done)
+		<-watched
+	})
+	return conn, packs, pack
+}
+
+// asCaller returns a context with the token of the caller.
+func asCaller(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+// errorInfo returns the code and the ErrorInfo detail of a grpc error.
+func errorInfo(err error) (codes.Code, *errdetails.ErrorInfo) {
+	errorstatus := status.Convert(err)
+	for _, detail := range errorstatus.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return errorstatus.Code(), info
+		}
+	}
+	return errorstatus.Code(), nil
+}
+
+// TestGRPCGetPack tests a pack is read by an authenticated caller and the
+// errors have the code of their category
+func TestGRPCGetPack(t *testing.T) {
+	conn, _, pack := newGRPCTest(t)
+	client := packpb.NewPackServiceClient(conn)
+
+	message, err := client.GetPack(asCaller("viewer"), &packpb.GetPackRequest{Id: pack.ID.Hex()})
+	if err != nil || message.GetPackCode() != "0008" || message.GetVersion() != 3 {
+		t.Fatalf("Expected the pack but got %v %v", message, err)
+	}
+
+	_, err = client.GetPack(context.Background(), &packpb.GetPackRequest{Id: pack.ID.Hex()})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected a caller without token to be rejected but got %v", err)
+	}
+
+	_, err = client.GetPack(asCaller("viewer"), &packpb.GetPackRequest{Id: "unknown"})
+	code, info := errorInfo(err)
+	if code != codes.NotFound || info == nil || info.Reason != service.ErrPackNotFound.Code || info.Metadata["field"] != "id" {
+		t.Errorf("Expected the pack not to be found but got %v %v", code, info)
+	}
+}
+
+// TestGRPCChangePack tests changes need the roles of the changed data and
+// the expected version
+func TestGRPCChangePack(t *testing.T) {
+	conn, _, pack := newGRPCTest(t)
+	client := packpb.NewPackServiceClient(conn)
+	stale, current := int64(2), int64(3)
+
+	_, err := client.MoveStock(asCaller("viewer"), &packpb.MoveStockRequest{Id: pack.ID.Hex(), Amount: 5})
+	if code, _ := errorInfo(err); code != codes.PermissionDenied {
+		t.Errorf("Expected a viewer not to move stock but got %v", err)
+	}
+
+	_, err = client.MoveStock(asCaller("admin"), &packpb.MoveStockRequest{Id: pack.ID.Hex(), Amount: 5, Version: &stale})
+	code, info := errorInfo(err)
+	if code != codes.Aborted || info == nil || info.Reason != service.ErrPackModified.Code {
+		t.Errorf("Expected a change at an old version to be aborted but got %v %v", code, info)
+	}
+
+	message, err := client.MoveStock(asCaller("admin"), &packpb.MoveStockRequest{Id: pack.ID.Hex(), Amount: 5, Version: &current})
+	if err != nil || message.GetStock() != 15 || message.GetVersion() != 4 {
+		t.Fatalf("Expected the stock to move but got %v %v", message, err)
+	}
+
+	changed := packMessage(pack)
+	changed.Price = 3000
+	_, err = client.UpdatePack(asCaller("editor"), &packpb.UpdatePackRequest{Id: pack.ID.Hex(), Pack: changed})
+	if code, _ := errorInfo(err); code != codes.PermissionDenied || pack.Price != 2500 {
+		t.Errorf("Expected an editor not to change the price but got %v", err)
+	}
+	message, err = client.UpdatePack(asCaller("pricing"), &packpb.UpdatePackRequest{Id: pack.ID.Hex(), Pack: changed})
+	if err != nil || message.GetPrice() != 3000 {
+		t.Errorf("Expected a pricing manager to change the price but got %v %v", message, err)
+	}
+}
+
+// TestGRPCStreams tests the packs and the exports are streamed
+func TestGRPCStreams(t *testing.T) {
+	conn, packs, _ := newGRPCTest(t)
+	client := packpb.NewPackServiceClient(conn)
+	for _, packcode := range []string{"0009", "0010"} {
+		id := bson.NewObjectId()
+		packs.packs[id.Hex()] = &model.Pack{ID: id, Packcode: packcode, Version: 1}
+	}
+
+	list, err := client.ListPacks(asCaller("viewer"), &packpb.ListPacksRequest{Skip: 1, Limit: 5})
+	if err != nil {
+		t.Fatalf("ListPacks() error = %v", err)
+	}
+	packcodes := []string{}
+	for {
+		message, err := list.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv() error = %v", err)
+		}
+		packcodes = append(packcodes, message.GetPackCode())
+	}
+	if len(packcodes) != 2 || packcodes[0] != "0009" || packcodes[1] != "0010" {
+		t.Errorf("Expected the packs after the first one but got %v", packcodes)
+	}
+
+	export, err := client.ExportPacks(asCaller("viewer"), &packpb.ExportPacksRequest{})
+	if err != nil {
+		t.Fatalf("ExportPacks() error = %v", err)
+	}
+	chunk, err := export.Recv()
+	if err != nil || chunk.GetContentType() != "text/csv" || string(chunk.GetData()) != "packcode,name\n0008,Whatsapp\n" {
+		t.Errorf("Expected the csv of the packs but got %v %v", chunk, err)
+	}
+
+	export, _ = client.ExportPacks(asCaller("viewer"), &packpb.ExportPacksRequest{Format: "pdf"})
+	_, err = export.Recv()
+	if code, info := errorInfo(err); code != codes.InvalidArgument || info == nil || info.Reason != service.ErrExportFormat.Code {
+		t.Errorf("Expected an unknown format to be rejected but got %v", err)
+	}
+}
+
+// TestGRPCHealth tests the health protocol follows the health of the db
+// and needs no credentials
+func TestGRPCHealth(t *testing.T) {
+	conn, _, _ := newGRPCTest(t)
+	client := healthpb.NewHealthClient(conn)
+
+	response, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: packpb.PackService_ServiceDesc.ServiceName})
+	if err != nil || response.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Fatalf("Expected the pack service to be serving but got %v %v", response, err)
+	}
+
+	SetHealthService(fakeHealth{db: false})
+	if isHealthy() {
+		t.Errorf("Expected the service not to be healthy without db")
+	}
+}
+
+// TestRPCPermissions tests every method of the grpc pack service has its
+// roles
+func TestRPCPermissions(t *testing.T) {
+	names := []string{}
+	for _, method := range packpb.PackService_ServiceDesc.Methods {
+		names = append(names, method.MethodName)
+	}
+	for _, stream := range packpb.PackService_ServiceDesc.Streams {
+		names = append(names, stream.StreamName)
+	}
+	for _, name := range names {
+		if len(rpcPermissions[name]) == 0 {
+			t.Errorf("Expected roles for the method %s", name)
+		}
+	}
+}
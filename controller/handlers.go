@@ -19,41 +19,63 @@ var packService service.IPackService
 // GetByID implements *IPackService.GetByID using mongo implementation.
 func getByID(params graphql.ResolveParams) (interface{}, error) {
 	packid, _ := params.Args["id"].(string)
-	result, err := packService.FindByID(packid)
-	return result, err
+	result, err := tenantPackService(params).FindByID(packid)
+	if err != nil || availablePack(params, result) == nil {
+		return nil, err
+	}
+	return localizePack(params, result), nil
 }
 
 // GetByPackCode implements *IPackService.GetByPackCode.
 func getByPackCode(params graphql.ResolveParams) (interface{}, error) {
 	packcode, _ := params.Args["packcode"].(string)
-	return packService.GetByPackCode(packcode)
+	result, err := tenantPackService(params).GetByPackCode(packcode)
+	if err != nil || availablePack(params, result) == nil {
+		return nil, err
+	}
+	return localizePack(params, result), nil
 }
 
 // GetByProductID implements *IPackService.GetByProductId.
 func getByProductID(params graphql.ResolveParams) (interface{}, error) {
 	prodid, _ := params.Args["productid"].(string)
-	return packService.GetByProductID(prodid)
+	result, err := tenantPackService(params).GetByProductID(prodid)
+	if err != nil || availablePack(params, result) == nil {
+		return nil, err
+	}
+	return localizePack(params, result), nil
+}
+
+// getPacks implements *IPackService.List.
+func getPacks(params graphql.ResolveParams) (interface{}, error) {
+	skip, _ := params.Args["skip"].(int)
+	limit, _ := params.Args["limit"].(int)
+	packs, err := tenantPackService(params).List(model.NewPackFilter(params.Args), skip, limit)
+	if err != nil {
+		return nil, err
+	}
+	return localizePacks(params, packs), nil
 }
 
 // GetIDByCode implements *IPackDAO.GetIDByCode.
 func getIDByCode(params graphql.ResolveParams) (interface{}, error) {
 	packcode, _ := params.Args["packcode"].(string)
-	return packService.GetIDByCode(packcode)
+	return tenantPackService(params).GetIDByCode(packcode)
 }
 
 // getByKeys implements *IPackDAO.GetIDByCode.
 func getByKeys(params graphql.ResolveParams) (interface{}, error) {
 	keys := model.NewPackExistsFromMap(params.Args)
-	return packService.IsThereThisPack(keys)
+	return tenantPackService(params).IsThereThisPack(keys)
 }
 
 // Create implements *IPackService.Create.
 func create(params graphql.ResolveParams) (interface{}, error) {
 	pack := model.NewPack(params.Args)
 
-	err := packService.Create(pack)
+	err := tenantPackService(params).Create(pack)
 	if err != nil {
-		return model.NewKOResult("-1", err.Error()), nil
+		return koResult(params, err), nil
 	}
 	return model.NewOKResult("10"), nil
 }
@@ -65,10 +87,10 @@ func changeState(params graphql.ResolveParams) (interface{}, error) {
 
 	newstate := model.NewPackState(state)
 
-	err := packService.ChangeState(id, newstate)
+	err := tenantPackService(params).ChangeState(id, newstate)
 
 	if err != nil {
-		return model.NewKOResult("-1", err.Error()), nil
+		return koResult(params, err), nil
 	}
 	return model.NewOKResult("10"), nil
 }
@@ -79,10 +101,10 @@ func changeProductID(params graphql.ResolveParams) (interface{}, error) {
 	mnoid, _ := params.Args["mnoid"].(int)
 	prodid, _ := params.Args["productid"].(string)
 
-	err := packService.ChangeProductID(id, int8(mnoid), prodid)
+	err := tenantPackService(params).ChangeProductID(id, int8(mnoid), prodid)
 
 	if err != nil {
-		return model.NewKOResult("-1", err.Error()), nil
+		return koResult(params, err), nil
 	}
 	return model.NewOKResult("10"), nil
 }
@@ -93,10 +115,10 @@ func changePackCode(params graphql.ResolveParams) (interface{}, error) {
 	mnoid, _ := params.Args["mnoid"].(int)
 	packcode, _ := params.Args["packcode"].(string)
 
-	err := packService.ChangePackCode(id, int8(mnoid), packcode)
+	err := tenantPackService(params).ChangePackCode(id, int8(mnoid), packcode)
 
 	if err != nil {
-		return model.NewKOResult("-1", err.Error()), nil
+		return koResult(params, err), nil
 	}
 	return model.NewOKResult("10"), nil
 }
@@ -106,10 +128,10 @@ func changeName(params graphql.ResolveParams) (interface{}, error) {
 	id, _ := params.Args["id"].(string)
 	name, _ := params.Args["newname"].(string)
 
-	err := packService.ChangeName(id, name)
+	err := tenantPackService(params).ChangeName(id, name)
 
 	if err != nil {
-		return model.NewKOResult("-1", err.Error()), nil
+		return koResult(params, err), nil
 	}
 	return model.NewOKResult("10"), nil
 }
@@ -119,10 +141,10 @@ func changeDesc(params graphql.ResolveParams) (interface{}, error) {
 	id, _ := params.Args["id"].(string)
 	desc, _ := params.Args["newdesc"].(string)
 
-	err := packService.ChangeDesc(id, desc)
+	err := tenantPackService(params).ChangeDesc(id, desc)
 
 	if err != nil {
-		return model.NewKOResult("-1", err.Error()), nil
+		return koResult(params, err), nil
 	}
 	return model.NewOKResult("10"), nil
 }
@@ -132,10 +154,10 @@ func changeImg(params graphql.ResolveParams) (interface{}, error) {
 	id, _ := params.Args["id"].(string)
 	img, _ := params.Args["newimgurl"].(string)
 
-	err := packService.ChangeImg(id, img)
+	err := tenantPackService(params).ChangeImg(id, img)
 
 	if err != nil {
-		return model.NewKOResult("-1", err.Error()), nil
+		return koResult(params, err), nil
 	}
 	return model.NewOKResult("10"), nil
 }
@@ -145,10 +167,10 @@ func changeKeyword(params graphql.ResolveParams) (interface{}, error) {
 	id, _ := params.Args["id"].(string)
 	kwds, _ := params.Args["newkeywords"].(string)
 
-	err := packService.ChangeKeyword(id, kwds)
+	err := tenantPackService(params).ChangeKeyword(id, kwds)
 
 	if err != nil {
-		return model.NewKOResult("-1", err.Error()), nil
+		return koResult(params, err), nil
 	}
 	return model.NewOKResult("10"), nil
 }
@@ -158,10 +180,10 @@ func changePrice(params graphql.ResolveParams) (interface{}, error) {
 	id, _ := params.Args["id"].(string)
 	price, _ := params.Args["newprice"].(int)
 
-	err := packService.ChangePrice(id, price)
+	err := tenantPackService(params).ChangePrice(id, price)
 
 	if err != nil {
-		return model.NewKOResult("-1", err.Error()), nil
+		return koResult(params, err), nil
 	}
 	return model.NewOKResult("10"), nil
 }
@@ -173,10 +195,10 @@ func changePackType(params graphql.ResolveParams) (interface{}, error) {
 
 	newtype := model.NewType(ptype)
 
-	err := packService.ChangePackType(id, newtype)
+	err := tenantPackService(params).ChangePackType(id, newtype)
 
 	if err != nil {
-		return model.NewKOResult("-1", err.Error()), nil
+		return koResult(params, err), nil
 	}
 	return model.NewOKResult("10"), nil
 }
@@ -190,10 +212,10 @@ func changeMNO(params graphql.ResolveParams) (interface{}, error) {
 
 	newmno := model.NewMno(pmno)
 
-	err := packService.ChangeMNO(id, prodid, packcode, newmno)
+	err := tenantPackService(params).ChangeMNO(id, prodid, packcode, newmno)
 
 	if err != nil {
-		return model.NewKOResult("-1", err.Error()), nil
+		return koResult(params, err), nil
 	}
 	return model.NewOKResult("10"), nil
 }
@@ -205,10 +227,10 @@ func changeValidity(params graphql.ResolveParams) (interface{}, error) {
 
 	newterm := model.NewTerm(pterm)
 
-	err := packService.ChangeValidity(id, newterm)
+	err := tenantPackService(params).ChangeValidity(id, newterm)
 
 	if err != nil {
-		return model.NewKOResult("-1", err.Error()), nil
+		return koResult(params, err), nil
 	}
 	return model.NewOKResult("10"), nil
 }
@@ -220,10 +242,10 @@ func changeCurrency(params graphql.ResolveParams) (interface{}, error) {
 
 	newccy := model.NewCurrency(ccy)
 
-	err := packService.ChangeCurrency(id, newccy)
+	err := tenantPackService(params).ChangeCurrency(id, newccy)
 
 	if err != nil {
-		return model.NewKOResult("-1", err.Error()), nil
+		return koResult(params, err), nil
 	}
 	return model.NewOKResult("10"), nil
 }
@@ -231,10 +253,10 @@ func changeCurrency(params graphql.ResolveParams) (interface{}, error) {
 // Delete delete a pack
 func delete(params graphql.ResolveParams) (interface{}, error) {
 	id, _ := params.Args["id"].(string)
-	err := packService.Delete(id)
+	err := tenantPackService(params).Delete(id)
 
 	if err != nil {
-		return model.NewKOResult("-1", err.Error()), nil
+		return koResult(params, err), nil
 	}
 	return model.NewOKResult("10"), nil
 }
@@ -247,10 +269,10 @@ func replaceResources(params graphql.ResolveParams) (interface{}, error) {
 	// get the array parameter for resources.
 	resources := model.NewResourcesFromInterface(newresources)
 
-	err := packService.UpdateResources(id, resources)
+	err := tenantPackService(params).UpdateResources(id, resources)
 
 	if err != nil {
-		return model.NewKOResult("-1", err.Error()), nil
+		return koResult(params, err), nil
 	}
 
 	return model.NewOKResult("10"), nil
@@ -259,10 +281,10 @@ func replaceResources(params graphql.ResolveParams) (interface{}, error) {
 // Delete delete resources of a pack
 func deletePackResources(params graphql.ResolveParams) (interface{}, error) {
 	id, _ := params.Args["id"].(string)
-	err := packService.DeleteResources(id)
+	err := tenantPackService(params).DeleteResources(id)
 
 	if err != nil {
-		return model.NewKOResult("-1", err.Error()), nil
+		return koResult(params, err), nil
 	}
 
 	return model.NewOKResult("10"), nil
@@ -272,10 +294,10 @@ func deletePackResources(params graphql.ResolveParams) (interface{}, error) {
 func moveStock(params graphql.ResolveParams) (interface{}, error) {
 	id, _ := params.Args["id"].(string)
 	amount, _ := params.Args["amount"].(int)
-	err := packService.MoveStock(id, amount)
+	err := tenantPackService(params).MoveStock(id, amount)
 
 	if err != nil {
-		return model.NewKOResult("-1", err.Error()), nil
+		return koResult(params, err), nil
 	}
 	return model.NewOKResult("10"), nil
 }
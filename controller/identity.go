@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/fernandoocampo/pack/auth"
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/service"
+	"github.com/graphql-go/graphql"
+)
+
+// authenticator validates the credentials of the graphql callers, with
+// no authenticator every call is rejected.
+var authenticator auth.IAuthenticator = auth.Chain{}
+
+// authenticate rejects the requests without valid credentials and puts
+// the identity of the caller in the request context.
+func authenticate(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		auth.Middleware(authenticator, next)(w, r)
+	}
+}
+
+// identityFrom returns the identity of the caller, nil if there is none.
+func identityFrom(params graphql.ResolveParams) *model.Identity {
+	return auth.FromContext(params.Context)
+}
+
+// tenantFrom returns the tenant of the caller, nil if there is none.
+func tenantFrom(ctx context.Context) *model.Tenant {
+	return auth.FromContext(ctx).Tenant()
+}
+
+// tenantPackService returns the pack service scoped to the tenant of the caller.
+func tenantPackService(params graphql.ResolveParams) service.IPackService {
+	return packService.WithTenant(tenantFrom(params.Context))
+}
+
+// requireAdmin returns service.ErrNotAdmin if the caller is not a platform admin.
+func requireAdmin(params graphql.ResolveParams) error {
+	if !identityFrom(params).HasRole(model.RolePlatformAdmin) {
+		return service.ErrNotAdmin
+	}
+	return nil
+}
+
+// actsFor returns true if the tenant can see the data of the given owner.
+func actsFor(tenant *model.Tenant, ownerid int) bool {
+	return tenant != nil && (tenant.Admin || (ownerid > 0 && tenant.Ownerid == ownerid))
+}
+
+// SetAuthenticator sets how graphql callers are authenticated.
+func SetAuthenticator(newauthenticator auth.IAuthenticator) {
+	authenticator = newauthenticator
+}
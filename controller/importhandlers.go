@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/fernandoocampo/pack/auth"
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/service"
+)
+
+// maxImportSize is the size of the biggest import file accepted.
+const maxImportSize = 10 << 20
+
+// importFormats contains the import format of every content type and file
+// extension accepted.
+var importFormats = map[string]string{
+	"text/csv":             model.ImportCSV,
+	"application/x-ndjson": model.ImportJSONLines,
+	"application/jsonl":    model.ImportJSONLines,
+	".csv":                 model.ImportCSV,
+	".jsonl":               model.ImportJSONLines,
+	".ndjson":              model.ImportJSONLines,
+}
+
+// ImportPacks creates or updates the packs of a csv or json lines file and
+// writes the report of every row. The file is the request body or the
+// file part of a multipart form. Parameters:
+// format: csv or jsonl, by default the one of the content type.
+// mode: create (default) fails the rows of existing packs, upsert updates them.
+// dryRun: true to check the rows without storing them.
+func ImportPacks(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	identity := auth.FromContext(r.Context())
+	err := authorizeRoute(identity, "importPacks")
+	if err != nil {
+		log.Warnf("%s is not allowed to import packs", subjectOf(identity))
+		respondWithCatalogError(w, r, err)
+		return
+	}
+
+	options := model.ImportOptions{
+		Format: r.URL.Query().Get("format"),
+		Mode:   r.URL.Query().Get("mode"),
+		Locale: localeFrom(r.Context()),
+	}
+	// the rows of existing packs only change their price and owner with the
+	// roles that change them one by one
+	options.ChangePrice = allow(identity, "price", pricingRoles) == nil
+	options.ChangeOwner = allow(identity, "ownerid", adminRoles) == nil
+	if options.Mode == "" {
+		options.Mode = model.ImportCreate
+	}
+	if dryrun := r.URL.Query().Get("dryRun"); dryrun != "" {
+		options.DryRun, err = strconv.ParseBool(dryrun)
+		if err != nil {
+			respondWithCatalogError(w, r, service.ErrImportDryRun)
+			return
+		}
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+	file, format, err := importFile(r)
+	if err != nil {
+		respondWithCatalogError(w, r, service.ErrImportFile.Wrap(err))
+		return
+	}
+	defer file.Close()
+	if options.Format == "" {
+		options.Format = format
+	}
+
+	report, err := packService.WithTenant(tenantFrom(r.Context())).ImportPacks(file, options)
+	if err != nil {
+		respondWithCatalogError(w, r, err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, report)
+}
+
+// importFile returns the uploaded file and its format by content type or
+// file extension, empty if it is not known.
+func importFile(r *http.Request) (io.ReadCloser, string, error) {
+	mediatype, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediatype != "multipart/form-data" {
+		return r.Body, importFormats[mediatype], nil
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		return nil, "", err
+	}
+	format := importFormats[path.Ext(header.Filename)]
+	if partformat, ok := importFormats[header.Header.Get("Content-Type")]; ok {
+		format = partformat
+	}
+	return file, format, nil
+}
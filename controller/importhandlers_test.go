@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fernandoocampo/pack/auth"
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/service"
+)
+
+// importPackService records the options of the imports, the methods the
+// handler does not use are left to the embedded nil service.
+type importPackService struct {
+	service.IPackService
+	options model.ImportOptions
+	file    string
+}
+
+func (s *importPackService) WithTenant(tenant *model.Tenant) service.IPackService {
+	return s
+}
+
+func (s *importPackService) ImportPacks(file io.Reader, options model.ImportOptions) (*model.ImportReport, error) {
+	content, _ := io.ReadAll(file)
+	s.file = string(content)
+	s.options = options
+	report := model.NewImportReport(options)
+	report.Add(model.ImportRowResult{Line: 1, Action: model.ImportCreated})
+	return report, nil
+}
+
+// TestImportPacksUpload tests the uploaded file and the options reach the service
+func TestImportPacksUpload(t *testing.T) {
+	packs := &importPackService{}
+	defer SetService(packService)
+	SetService(packs)
+	editor := &model.Identity{Subject: "editor", Roles: []string{model.RoleCatalogEditor}}
+
+	// GIVEN a json lines file uploaded in a multipart form
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+	part, _ := form.CreateFormFile("file", "packs.jsonl")
+	part.Write([]byte(`{"packcode":"0008"}` + "\n"))
+	form.Close()
+	request := httptest.NewRequest(http.MethodPost, "/packs/import?mode=upsert&dryRun=true", body)
+	request.Header.Set("Content-Type", form.FormDataContentType())
+	request = request.WithContext(auth.NewContext(context.Background(), editor))
+
+	// WHEN it is imported
+	recorder := httptest.NewRecorder()
+	ImportPacks(recorder, request)
+
+	// THEN the service gets the file with the format of its extension
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 but got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	want := model.ImportOptions{Format: model.ImportJSONLines, Mode: model.ImportUpsert, DryRun: true, Locale: "en"}
+	if packs.options != want || packs.file != `{"packcode":"0008"}`+"\n" {
+		t.Fatalf("Expected %+v but got %+v %q", want, packs.options, packs.file)
+	}
+	report := model.ImportReport{}
+	err := json.Unmarshal(recorder.Body.Bytes(), &report)
+	if err != nil || report.Created != 1 {
+		t.Fatalf("Expected the report in the response but got %s", recorder.Body.String())
+	}
+}
+
+// TestImportPacksRejected tests imports with wrong parameters or roles are rejected
+func TestImportPacksRejected(t *testing.T) {
+	tests := []struct {
+		name   string
+		roles  []string
+		target string
+		want   int
+	}{
+		{name: "seller", roles: []string{model.RoleSeller}, target: "/packs/import", want: http.StatusForbidden},
+		{name: "dry run", roles: []string{model.RoleAdmin}, target: "/packs/import?dryRun=maybe", want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, tt.target, bytes.NewBufferString("prodid\n"))
+			request.Header.Set("Content-Type", "text/csv")
+			request = request.WithContext(auth.NewContext(context.Background(), &model.Identity{Roles: tt.roles}))
+			recorder := httptest.NewRecorder()
+
+			ImportPacks(recorder, request)
+
+			if recorder.Code != tt.want {
+				t.Fatalf("Expected status %d but got %d: %s", tt.want, recorder.Code, recorder.Body.String())
+			}
+		})
+	}
+}
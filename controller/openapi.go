@@ -0,0 +1,192 @@
+package controller
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// openAPIVersion is the version of the openapi specification of the
+// document.
+const openAPIVersion = "3.0.3"
+
+// OpenAPI writes the openapi document of the rest api.
+func OpenAPI(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, newOpenAPIDocument(restRoutes()))
+}
+
+// openAPISchemas contains the schemas of the components of an openapi
+// document by their name.
+type openAPISchemas map[string]interface{}
+
+// newOpenAPIDocument returns the openapi document of the given routes.
+func newOpenAPIDocument(routes []restRoute) map[string]interface{} {
+	schemas := openAPISchemas{}
+	errorschema := schemas.of(reflect.TypeOf(errorResponse{}))
+	paths := map[string]interface{}{}
+	for _, route := range routes {
+		path, ok := paths[route.path].(map[string]interface{})
+		if !ok {
+			path = map[string]interface{}{}
+			paths[route.path] = path
+		}
+		path[strings.ToLower(route.method)] = newOpenAPIOperation(route, schemas, errorschema)
+	}
+	return map[string]interface{}{
+		"openapi": openAPIVersion,
+		"info": map[string]interface{}{
+			"title":       "Pack catalog",
+			"description": "packs of the catalog of the tenant of the caller",
+			"version":     "1",
+		},
+		"servers": []interface{}{map[string]interface{}{"url": restPrefix}},
+		"paths":   paths,
+		"components": map[string]interface{}{
+			"schemas": schemas,
+			"securitySchemes": map[string]interface{}{
+				"bearer": map[string]interface{}{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+				"apiKey": map[string]interface{}{"type": "apiKey", "in": "header", "name": "X-Api-Key"},
+			},
+		},
+		"security": []interface{}{
+			map[string]interface{}{"bearer": []string{}},
+			map[string]interface{}{"apiKey": []string{}},
+		},
+	}
+}
+
+// newOpenAPIOperation returns the openapi operation of the route.
+func newOpenAPIOperation(route restRoute, schemas openAPISchemas, errorschema interface{}) map[string]interface{} {
+	operation := map[string]interface{}{
+		"operationId": route.name,
+		"summary":     route.summary,
+	}
+	if route.public {
+		operation["security"] = []interface{}{}
+	}
+	params := []interface{}{}
+	if strings.Contains(route.path, "{id}") {
+		params = append(params, map[string]interface{}{
+			"name": "id", "in": "path", "required": true,
+			"description": "id of the pack",
+			"schema":      map[string]interface{}{"type": "string"},
+		})
+	}
+	for _, param := range route.params {
+		params = append(params, map[string]interface{}{
+			"name": param.name, "in": param.in,
+			"description": param.description,
+			"schema":      map[string]interface{}{"type": param.kind},
+		})
+	}
+	if len(params) > 0 {
+		operation["parameters"] = params
+	}
+	if route.body != nil {
+		bodytype := route.bodyType
+		if bodytype == "" {
+			bodytype = jsonContent
+		}
+		operation["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content": map[string]interface{}{
+				bodytype: map[string]interface{}{"schema": schemas.of(reflect.TypeOf(route.body))},
+			},
+		}
+	}
+	success := map[string]interface{}{"description": http.StatusText(route.status)}
+	if route.result != nil {
+		success["content"] = map[string]interface{}{
+			jsonContent: map[string]interface{}{"schema": schemas.of(reflect.TypeOf(route.result))},
+		}
+	}
+	if strings.Contains(route.path, "{id}") && route.status != http.StatusNoContent {
+		success["headers"] = map[string]interface{}{
+			"ETag": map[string]interface{}{
+				"description": "entity tag of the pack, its version",
+				"schema":      map[string]interface{}{"type": "string"},
+			},
+		}
+	}
+	responses := map[string]interface{}{strconv.Itoa(route.status): success}
+	for _, status := range route.errors {
+		response := map[string]interface{}{"description": http.StatusText(status)}
+		if status != http.StatusNotModified {
+			response["content"] = map[string]interface{}{
+				jsonContent: map[string]interface{}{"schema": errorschema},
+			}
+		}
+		responses[strconv.Itoa(status)] = response
+	}
+	operation["responses"] = responses
+	return operation
+}
+
+// of returns the schema of the json of the type, structs are added to the
+// components and referenced.
+func (s openAPISchemas) of(kind reflect.Type) map[string]interface{} {
+	if kind == reflect.TypeOf(time.Time{}) {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+	switch kind.Kind() {
+	case reflect.Ptr:
+		return s.of(kind.Elem())
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": s.of(kind.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": s.of(kind.Elem())}
+	case reflect.Struct:
+		name := schemaName(kind)
+		if _, ok := s[name]; !ok {
+			// the name is taken before the fields so recursive types end
+			s[name] = nil
+			s[name] = s.object(kind)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+	}
+	return map[string]interface{}{}
+}
+
+// object returns the schema of the fields of the struct type as its json
+// has them.
+func (s openAPISchemas) object(kind reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	for i := 0; i < kind.NumField(); i++ {
+		field := kind.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = s.of(field.Type)
+	}
+	return map[string]interface{}{"type": "object", "properties": properties}
+}
+
+// schemaName returns the name of the component of the struct type, its
+// name with the first letter in upper case. e.g. Pack, PackList
+func schemaName(kind reflect.Type) string {
+	name := []rune(kind.Name())
+	if len(name) == 0 {
+		return "Object"
+	}
+	name[0] = unicode.ToUpper(name[0])
+	return string(name)
+}
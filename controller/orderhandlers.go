@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"errors"
+
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/service"
+	"github.com/graphql-go/graphql"
+)
+
+// orderService references the IOrderService
+var orderService service.IOrderService
+
+// getOrder implements IOrderService.GetByID.
+func getOrder(params graphql.ResolveParams) (interface{}, error) {
+	id, _ := params.Args["id"].(string)
+	return ownedOrder(params, id)
+}
+
+// getOrders implements IOrderService.GetByMsisdn, only the orders of the
+// tenant of the caller are returned.
+func getOrders(params graphql.ResolveParams) (interface{}, error) {
+	msisdn, _ := params.Args["msisdn"].(string)
+	orders, err := orderService.GetByMsisdn(msisdn)
+	if err != nil {
+		return nil, err
+	}
+	tenant := tenantFrom(params.Context)
+	owned := make([]model.Order, 0, len(orders))
+	for _, order := range orders {
+		if tenant.OwnsSale(order.Ownerid, order.MnoID) {
+			owned = append(owned, order)
+		}
+	}
+	return owned, nil
+}
+
+// getProvisionStatus implements IOrderService.ProvisionStatus.
+func getProvisionStatus(params graphql.ResolveParams) (interface{}, error) {
+	orderid, _ := params.Args["orderid"].(string)
+	_, err := ownedOrder(params, orderid)
+	if err != nil {
+		return nil, err
+	}
+	status, err := orderService.ProvisionStatus(orderid)
+	if err != nil {
+		return nil, err
+	}
+	return int(status), nil
+}
+
+// purchasePack implements IOrderService.Purchase. The id of a completed
+// order, or of a pending one answered with service.ErrProvisionPending,
+// goes in the result message.
+func purchasePack(params graphql.ResolveParams) (interface{}, error) {
+	msisdn, _ := params.Args["msisdn"].(string)
+	packid, _ := params.Args["packid"].(string)
+
+	err := checkSellable(params, packid)
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	order, err := orderService.Purchase(msisdn, packid)
+
+	if errors.Is(err, service.ErrProvisionPending) {
+		result := koResult(params, err)
+		result.Msg = order.ID.Hex()
+		return result, nil
+	}
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	result := model.NewOKResult("10")
+	result.Msg = order.ID.Hex()
+	return result, nil
+}
+
+// refundOrder implements IOrderService.Refund.
+func refundOrder(params graphql.ResolveParams) (interface{}, error) {
+	id, _ := params.Args["id"].(string)
+	reason, _ := params.Args["reason"].(string)
+	_, err := ownedOrder(params, id)
+	if err != nil {
+		return koResult(params, err), nil
+	}
+
+	err = orderService.Refund(id, reason)
+
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	return model.NewOKResult("10"), nil
+}
+
+// ownedOrder returns the order with the given id if it is a sale of the
+// tenant of the caller.
+func ownedOrder(params graphql.ResolveParams, id string) (*model.Order, error) {
+	order, err := orderService.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, service.ErrOrderNotProvisioned
+	}
+	if !tenantFrom(params.Context).OwnsSale(order.Ownerid, order.MnoID) {
+		return nil, service.ErrNotOwner
+	}
+	return order, nil
+}
+
+// SetOrderService sets the order service for this handler.
+func SetOrderService(service service.IOrderService) {
+	orderService = service
+}
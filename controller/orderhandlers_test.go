@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/fernandoocampo/pack/auth"
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/service"
+	"github.com/graphql-go/graphql"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// fakeOrderService keeps the orders in memory and records the refunds.
+type fakeOrderService struct {
+	service.IOrderService
+	orders   map[string]*model.Order
+	refunded []string
+}
+
+func (s *fakeOrderService) GetByID(id string) (*model.Order, error) {
+	return s.orders[id], nil
+}
+
+func (s *fakeOrderService) GetByMsisdn(msisdn string) ([]model.Order, error) {
+	orders := []model.Order{}
+	for _, order := range s.orders {
+		orders = append(orders, *order)
+	}
+	return orders, nil
+}
+
+func (s *fakeOrderService) Purchase(msisdn string, packid string) (*model.Order, error) {
+	order := &model.Order{ID: bson.NewObjectId(), Msisdn: msisdn, PackID: packid}
+	return order, service.ErrProvisionPending
+}
+
+func (s *fakeOrderService) Refund(id string, reason string) error {
+	s.refunded = append(s.refunded, id)
+	return nil
+}
+
+// newOrderTest returns the params of a seller of owner 7 and an order of
+// owner 7 and one of owner 8.
+func newOrderTest(t *testing.T) (graphql.ResolveParams, *fakeOrderService, *model.Order, *model.Order) {
+	own := &model.Order{ID: bson.NewObjectId(), Ownerid: 7, MnoID: 2}
+	other := &model.Order{ID: bson.NewObjectId(), Ownerid: 8, MnoID: 2}
+	orders := &fakeOrderService{orders: map[string]*model.Order{own.ID.Hex(): own, other.ID.Hex(): other}}
+	oldservice := orderService
+	t.Cleanup(func() { SetOrderService(oldservice) })
+	SetOrderService(orders)
+	seller := &model.Identity{Subject: "seller", Roles: []string{model.RoleSeller, model.RoleAdmin}, Ownerid: 7}
+	params := graphql.ResolveParams{Context: auth.NewContext(context.Background(), seller), Args: map[string]interface{}{}}
+	return params, orders, own, other
+}
+
+// TestOrdersOfOtherTenants tests a caller cannot read or refund the orders
+// of another owner
+func TestOrdersOfOtherTenants(t *testing.T) {
+	params, orders, own, other := newOrderTest(t)
+
+	params.Args["id"] = other.ID.Hex()
+	_, err := getOrder(params)
+	if !errors.Is(err, service.ErrNotOwner) {
+		t.Errorf("Expected the order of another owner to be hidden but got %v", err)
+	}
+	result, _ := refundOrder(params)
+	if result.(*model.Result).Success || len(orders.refunded) != 0 {
+		t.Errorf("Expected the order of another owner not to be refunded but got %+v", result)
+	}
+
+	params.Args["id"] = own.ID.Hex()
+	order, err := getOrder(params)
+	if err != nil || order.(*model.Order).ID != own.ID {
+		t.Errorf("Expected the own order but got %v %v", order, err)
+	}
+
+	list, _ := getOrders(params)
+	if owned := list.([]model.Order); len(owned) != 1 || owned[0].ID != own.ID {
+		t.Errorf("Expected only the own order but got %+v", owned)
+	}
+}
+
+// TestSellPacksOfOtherTenants tests a caller cannot sell a pack that is
+// not visible to its tenant
+func TestSellPacksOfOtherTenants(t *testing.T) {
+	_, _, pack := newRestTest(t)
+	params, _, _, _ := newOrderTest(t)
+
+	err := checkSellable(params, bson.NewObjectId().Hex())
+	if !errors.Is(err, service.ErrPackNotFound) {
+		t.Errorf("Expected a pack that is not visible not to be sellable but got %v", err)
+	}
+	err = checkSellable(params, "unknown")
+	if !errors.Is(err, service.ErrPackNotFound) {
+		t.Errorf("Expected an invalid id not to be sellable but got %v", err)
+	}
+	err = checkSellable(params, pack.ID.Hex())
+	if err != nil {
+		t.Errorf("Expected the visible pack to be sellable but got %v", err)
+	}
+}
+
+// TestPurchasePending tests a pending sale is answered with its error and
+// the id of the order
+func TestPurchasePending(t *testing.T) {
+	_, _, pack := newRestTest(t)
+	params, _, _, _ := newOrderTest(t)
+	params.Args["msisdn"] = "573001234567"
+	params.Args["packid"] = pack.ID.Hex()
+
+	answer, _ := purchasePack(params)
+	result := answer.(*model.Result)
+	if result.Success || result.Error == nil || result.Error.Code != service.ErrProvisionPending.Code || !bson.IsObjectIdHex(result.Msg) {
+		t.Errorf("Expected a pending sale with its order id but got %+v", result)
+	}
+}
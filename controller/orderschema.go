@@ -0,0 +1,218 @@
+package controller
+
+import (
+	"github.com/fernandoocampo/pack/model"
+	"github.com/graphql-go/graphql"
+)
+
+// orderType is the sale of a pack to a subscriber.
+var orderType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Order",
+	Description: "The sale of a pack to a subscriber",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The id of the order.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				order := orderFromSource(p.Source)
+				if order == nil {
+					return nil, nil
+				}
+				return order.ID.Hex(), nil
+			},
+		},
+		"msisdn": &graphql.Field{
+			Type:        graphql.String,
+			Description: "line number of the subscriber.",
+		},
+		"packid": &graphql.Field{
+			Type:        graphql.String,
+			Description: "id of the sold pack.",
+		},
+		"packcode": &graphql.Field{
+			Type:        graphql.String,
+			Description: "code of the sold pack.",
+		},
+		"prodid": &graphql.Field{
+			Type:        graphql.String,
+			Description: "mno product id used to activate the pack.",
+		},
+		"mnoid": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "id of the mobile network operator of the pack.",
+		},
+		"price": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "price charged.",
+		},
+		"currency": &graphql.Field{
+			Type:        ccyInterface,
+			Description: "Currency of the price.",
+		},
+		"reference": &graphql.Field{
+			Type:        graphql.String,
+			Description: "id of the activation in the mno network.",
+		},
+		"entitlementid": &graphql.Field{
+			Type:        graphql.String,
+			Description: "id of the entitlement granted by the order.",
+		},
+		"attempts": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "activation attempts made.",
+		},
+		"lasterror": &graphql.Field{
+			Type:        graphql.String,
+			Description: "reason of the failure.",
+		},
+		"nextattempt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "when a pending order is activated again.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				order := orderFromSource(p.Source)
+				if order == nil || order.NextAttempt.IsZero() {
+					return nil, nil
+				}
+				return order.NextAttempt, nil
+			},
+		},
+		"kind": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "why the order was placed. 0. purchase, 1. subscription, 2. renewal",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				order := orderFromSource(p.Source)
+				if order == nil {
+					return nil, nil
+				}
+				return int(order.Kind), nil
+			},
+		},
+		"state": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "state of the order. 0. pending, 1. completed, 2. failed, 3. refunded",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				order := orderFromSource(p.Source)
+				if order == nil {
+					return nil, nil
+				}
+				return int(order.State), nil
+			},
+		},
+		"commission": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "commission earned by the owner of the pack.",
+		},
+		"refunded": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "when the price was given back.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				order := orderFromSource(p.Source)
+				if order == nil || order.Refunded.IsZero() {
+					return nil, nil
+				}
+				return order.Refunded, nil
+			},
+		},
+		"refundreason": &graphql.Field{
+			Type:        graphql.String,
+			Description: "why the price was given back.",
+		},
+		"created": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "when the order was placed.",
+		},
+	},
+})
+
+// orderQueryFields contains the queries over pack orders.
+var orderQueryFields = graphql.Fields{
+	"order": &graphql.Field{
+		Type:        orderType,
+		Description: "query an order by its id",
+		Args: graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return getOrder(params)
+		},
+	},
+	"orders": &graphql.Field{
+		Type:        graphql.NewList(orderType),
+		Description: "query the orders of a subscriber",
+		Args: graphql.FieldConfigArgument{
+			"msisdn": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return getOrders(params)
+		},
+	},
+	"provisionStatus": &graphql.Field{
+		Type:        graphql.Int,
+		Description: "query the mno network for the status of an order activation. 0. unknown, 1. pending, 2. active, 3. inactive, 4. failed",
+		Args: graphql.FieldConfigArgument{
+			"orderid": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return getProvisionStatus(params)
+		},
+	},
+}
+
+// orderMutationFields contains the mutations over pack orders.
+var orderMutationFields = graphql.Fields{
+	/*
+		purchase a pack
+	*/
+	"purchasePack": &graphql.Field{
+		Type:        resultType, // the return type for this field
+		Description: "sells a pack to a subscriber, it is activated in the mno network and granted",
+		Args: graphql.FieldConfigArgument{
+			"msisdn": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+			"packid": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return purchasePack(params)
+		},
+	},
+	/*
+		refund an order
+	*/
+	"refundOrder": &graphql.Field{
+		Type:        resultType, // the return type for this field
+		Description: "gives back the price of a completed order",
+		Args: graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+			"reason": &graphql.ArgumentConfig{
+				Type: graphql.String,
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return refundOrder(params)
+		},
+	},
+}
+
+// orderFromSource returns the order resolved by a parent field, lists
+// give values and single queries give pointers.
+func orderFromSource(source interface{}) *model.Order {
+	switch order := source.(type) {
+	case *model.Order:
+		return order
+	case model.Order:
+		return &order
+	default:
+		return nil
+	}
+}
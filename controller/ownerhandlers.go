@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/service"
+	"github.com/graphql-go/graphql"
+)
+
+// ownerService references the IOwnerService
+var ownerService service.IOwnerService
+
+// getOwner implements IOwnerService.GetByID.
+func getOwner(params graphql.ResolveParams) (interface{}, error) {
+	id, _ := params.Args["id"].(int)
+	if !actsFor(tenantFrom(params.Context), id) {
+		return nil, service.ErrNotOwner
+	}
+	return ownerService.GetByID(id)
+}
+
+// getOwners implements IOwnerService.GetAll. Only platform admins get
+// every owner, other callers get the owner they act for.
+func getOwners(params graphql.ResolveParams) (interface{}, error) {
+	if allow(identityFrom(params), "owners", platformRoles) == nil {
+		return ownerService.GetAll()
+	}
+	tenant := tenantFrom(params.Context)
+	if tenant == nil || tenant.Ownerid <= 0 {
+		return []model.Owner{}, nil
+	}
+	owner, err := ownerService.GetByID(tenant.Ownerid)
+	if err != nil || owner == nil {
+		return []model.Owner{}, err
+	}
+	return []model.Owner{*owner}, nil
+}
+
+// getPacksByOwner implements IPackService.GetByOwner.
+func getPacksByOwner(params graphql.ResolveParams) (interface{}, error) {
+	ownerid, _ := params.Args["ownerid"].(int)
+	packs, err := tenantPackService(params).GetByOwner(ownerid)
+	if err != nil {
+		return nil, err
+	}
+	return localizePacks(params, availablePacks(params, packs)), nil
+}
+
+// createOwner implements IOwnerService.Create.
+func createOwner(params graphql.ResolveParams) (interface{}, error) {
+	owner := model.NewOwner(params.Args)
+
+	err := ownerService.Create(owner)
+
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	return model.NewOKResult("10"), nil
+}
+
+// updateOwner implements IOwnerService.Update.
+func updateOwner(params graphql.ResolveParams) (interface{}, error) {
+	owner := model.NewOwner(params.Args)
+	if !actsFor(tenantFrom(params.Context), owner.ID) {
+		return koResult(params, service.ErrNotOwner), nil
+	}
+
+	err := ownerService.Update(owner)
+
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	return model.NewOKResult("10"), nil
+}
+
+// changeOwnerState implements IOwnerService.ChangeState.
+func changeOwnerState(params graphql.ResolveParams) (interface{}, error) {
+	id, _ := params.Args["id"].(int)
+	state, _ := params.Args["state"].(int)
+	if !actsFor(tenantFrom(params.Context), id) {
+		return koResult(params, service.ErrNotOwner), nil
+	}
+
+	err := ownerService.ChangeState(id, model.OwnerState(state))
+
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	return model.NewOKResult("10"), nil
+}
+
+// transferPackOwnership implements IPackService.TransferOwnership.
+func transferPackOwnership(params graphql.ResolveParams) (interface{}, error) {
+	id, _ := params.Args["id"].(string)
+	newownerid, _ := params.Args["newOwnerId"].(int)
+
+	err := tenantPackService(params).TransferOwnership(id, newownerid)
+
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	return model.NewOKResult("10"), nil
+}
+
+// SetOwnerService sets the owner service for this handler.
+func SetOwnerService(service service.IOwnerService) {
+	ownerService = service
+}
@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/fernandoocampo/pack/auth"
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/service"
+	"github.com/graphql-go/graphql"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// fakeOwnerService keeps the owners in memory.
+type fakeOwnerService struct {
+	service.IOwnerService
+	owners map[int]*model.Owner
+}
+
+func (s *fakeOwnerService) GetByID(id int) (*model.Owner, error) {
+	return s.owners[id], nil
+}
+
+func (s *fakeOwnerService) GetAll() ([]model.Owner, error) {
+	owners := []model.Owner{}
+	for _, owner := range s.owners {
+		owners = append(owners, *owner)
+	}
+	return owners, nil
+}
+
+func (s *fakeOwnerService) ChangeState(id int, newstate model.OwnerState) error {
+	s.owners[id].State = newstate
+	return nil
+}
+
+// fakeCommissionService keeps the commission rules in memory.
+type fakeCommissionService struct {
+	service.ICommissionService
+	rules map[string]*model.CommissionRule
+}
+
+func (s *fakeCommissionService) GetRule(id string) (*model.CommissionRule, error) {
+	return s.rules[id], nil
+}
+
+func (s *fakeCommissionService) DeleteRule(id string) error {
+	s.rules[id] = nil
+	return nil
+}
+
+// TestOwnersOfOtherTenants tests an admin of owner 7 cannot see or change
+// owner 8 or its commission rules
+func TestOwnersOfOtherTenants(t *testing.T) {
+	owners := &fakeOwnerService{owners: map[int]*model.Owner{7: {ID: 7}, 8: {ID: 8}}}
+	other := &model.CommissionRule{ID: bson.NewObjectId(), Ownerid: 8}
+	commissions := &fakeCommissionService{rules: map[string]*model.CommissionRule{other.ID.Hex(): other}}
+	oldowners, oldcommissions := ownerService, commissionService
+	t.Cleanup(func() {
+		SetOwnerService(oldowners)
+		SetCommissionService(oldcommissions)
+	})
+	SetOwnerService(owners)
+	SetCommissionService(commissions)
+	admin := &model.Identity{Subject: "admin", Roles: []string{model.RoleAdmin}, Ownerid: 7}
+	params := graphql.ResolveParams{Context: auth.NewContext(context.Background(), admin), Args: map[string]interface{}{}}
+
+	params.Args["id"] = 8
+	if _, err := getOwner(params); !errors.Is(err, service.ErrNotOwner) {
+		t.Errorf("Expected owner 8 to be hidden but got %v", err)
+	}
+	params.Args["state"] = int(model.OwnerSuspended)
+	result, _ := changeOwnerState(params)
+	if result.(*model.Result).Success || owners.owners[8].State == model.OwnerSuspended {
+		t.Errorf("Expected owner 8 not to be suspended but got %+v", result)
+	}
+
+	list, _ := getOwners(params)
+	if listed := list.([]model.Owner); len(listed) != 1 || listed[0].ID != 7 {
+		t.Errorf("Expected only owner 7 but got %+v", listed)
+	}
+
+	params.Args["id"] = other.ID.Hex()
+	result, _ = deleteCommissionRule(params)
+	if result.(*model.Result).Success || commissions.rules[other.ID.Hex()] == nil {
+		t.Errorf("Expected the rule of owner 8 not to be deleted but got %+v", result)
+	}
+}
@@ -0,0 +1,234 @@
+package controller
+
+import (
+	"github.com/fernandoocampo/pack/model"
+	"github.com/graphql-go/graphql"
+)
+
+// contactType contains the contact details of an owner.
+var contactType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Contact",
+	Description: "Contact details of an owner",
+	Fields: graphql.Fields{
+		"name": &graphql.Field{
+			Type:        graphql.String,
+			Description: "name of the contact person.",
+		},
+		"email": &graphql.Field{
+			Type:        graphql.String,
+			Description: "contact email.",
+		},
+		"phone": &graphql.Field{
+			Type:        graphql.String,
+			Description: "contact phone number.",
+		},
+	},
+})
+
+// inputContact contains the contact details of an owner to store.
+var inputContact = graphql.NewInputObject(
+	graphql.InputObjectConfig{
+		Name: "inputContact",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name": &graphql.InputObjectFieldConfig{
+				Type:        graphql.String,
+				Description: "name of the contact person",
+			},
+			"email": &graphql.InputObjectFieldConfig{
+				Type:        graphql.String,
+				Description: "contact email",
+			},
+			"phone": &graphql.InputObjectFieldConfig{
+				Type:        graphql.String,
+				Description: "contact phone number",
+			},
+		},
+	},
+)
+
+// ownerType is a company that owns packs for resale.
+var ownerType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Owner",
+	Description: "A reseller company that owns packs for resale",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "The id of the owner, it is the ownerid of its packs.",
+		},
+		"name": &graphql.Field{
+			Type:        graphql.String,
+			Description: "company name.",
+		},
+		"contact": &graphql.Field{
+			Type:        contactType,
+			Description: "contact details.",
+		},
+		"mnos": &graphql.Field{
+			Type:        graphql.NewList(graphql.Int),
+			Description: "ids of the mnos the owner can sell packs of.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				owner := ownerFromSource(p.Source)
+				if owner == nil {
+					return nil, nil
+				}
+				mnos := make([]int, len(owner.Mnos))
+				for i, mnoid := range owner.Mnos {
+					mnos[i] = int(mnoid)
+				}
+				return mnos, nil
+			},
+		},
+		"state": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "state of the owner. 0. inactive, 1. active, 2. suspended",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				owner := ownerFromSource(p.Source)
+				if owner == nil {
+					return nil, nil
+				}
+				return int(owner.State), nil
+			},
+		},
+		"created": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "when the owner was registered.",
+		},
+	},
+})
+
+// ownerQueryFields contains the queries over pack owners.
+var ownerQueryFields = graphql.Fields{
+	"owner": &graphql.Field{
+		Type:        ownerType,
+		Description: "query an owner by its id",
+		Args: graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.Int),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return getOwner(params)
+		},
+	},
+	"owners": &graphql.Field{
+		Type:        graphql.NewList(ownerType),
+		Description: "query every owner",
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return getOwners(params)
+		},
+	},
+	"packsByOwner": &graphql.Field{
+		Type:        graphql.NewList(packType),
+		Description: "query the packs of an owner",
+		Args: mergeArguments(availabilityArguments(), graphql.FieldConfigArgument{
+			"ownerid": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.Int),
+			},
+			"locale": localeArgument,
+		}),
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return getPacksByOwner(params)
+		},
+	},
+}
+
+// ownerMutationFields contains the mutations over pack owners.
+var ownerMutationFields = graphql.Fields{
+	/*
+		create an owner
+	*/
+	"createOwner": &graphql.Field{
+		Type:        resultType, // the return type for this field
+		Description: "registers a company that owns packs for resale",
+		Args: graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.Int),
+			},
+			"name": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+			"contact": &graphql.ArgumentConfig{
+				Type: inputContact,
+			},
+			"mnos": &graphql.ArgumentConfig{
+				Type: graphql.NewList(graphql.Int),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return createOwner(params)
+		},
+	},
+	/*
+		update an owner
+	*/
+	"updateOwner": &graphql.Field{
+		Type:        resultType, // the return type for this field
+		Description: "changes the name, contact details and allowed mnos of an owner",
+		Args: graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.Int),
+			},
+			"name": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+			"contact": &graphql.ArgumentConfig{
+				Type: inputContact,
+			},
+			"mnos": &graphql.ArgumentConfig{
+				Type: graphql.NewList(graphql.Int),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return updateOwner(params)
+		},
+	},
+	/*
+		change the state of an owner
+	*/
+	"changeOwnerState": &graphql.Field{
+		Type:        resultType, // the return type for this field
+		Description: "activates, deactivates or suspends an owner. 0. inactive, 1. active, 2. suspended",
+		Args: graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.Int),
+			},
+			"state": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.Int),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return changeOwnerState(params)
+		},
+	},
+	/*
+		transfer the ownership of a pack
+	*/
+	"transferPackOwnership": &graphql.Field{
+		Type:        resultType, // the return type for this field
+		Description: "gives a pack to another owner, it must be active and allowed to sell packs of the pack mno",
+		Args: graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+			"newOwnerId": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.Int),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return transferPackOwnership(params)
+		},
+	},
+}
+
+// ownerFromSource returns the owner resolved by a parent field, lists
+// give values and single queries give pointers.
+func ownerFromSource(source interface{}) *model.Owner {
+	switch owner := source.(type) {
+	case *model.Owner:
+		return owner
+	case model.Owner:
+		return &owner
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,35 @@
+package controller
+
+import (
+	"github.com/fernandoocampo/pack/model"
+	"github.com/graphql-go/graphql"
+)
+
+// createBundle implements *IPackService.CreateBundle. The id of the
+// bundle goes in the result message.
+func createBundle(params graphql.ResolveParams) (interface{}, error) {
+	bundle := model.NewPack(params.Args)
+	bundle.Components = model.NewComponents(params.Args["components"])
+
+	err := tenantPackService(params).CreateBundle(bundle)
+
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	result := model.NewOKResult("10")
+	result.Msg = bundle.ID.Hex()
+	return result, nil
+}
+
+// changeBundleComponents implements *IPackService.ChangeComponents.
+func changeBundleComponents(params graphql.ResolveParams) (interface{}, error) {
+	id, _ := params.Args["id"].(string)
+	components := model.NewComponents(params.Args["components"])
+
+	err := tenantPackService(params).ChangeComponents(id, components)
+
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	return model.NewOKResult("10"), nil
+}
@@ -0,0 +1,43 @@
+package controller
+
+import "github.com/graphql-go/graphql"
+
+// componentsArgument contains the ids of the packs of a bundle.
+var componentsArgument = &graphql.ArgumentConfig{
+	Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+	Description: "ids of at least two active packs of the mno of the bundle, bundles cannot be components",
+}
+
+// packBundleMutationFields contains the mutations of bundles, packs that
+// combine other packs.
+var packBundleMutationFields = graphql.Fields{
+	/*
+		create a bundle
+	*/
+	"createBundle": &graphql.Field{
+		Type:        resultType, // the return type for this field
+		Description: "creates a pack that combines other packs with its own price and validity, it gets the resources of its components",
+		Args: mergeArguments(packArguments(), graphql.FieldConfigArgument{
+			"components": componentsArgument,
+		}),
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return createBundle(params)
+		},
+	},
+	/*
+		change the packs of a bundle
+	*/
+	"changeBundleComponents": &graphql.Field{
+		Type:        resultType, // the return type for this field
+		Description: "replaces the packs of a bundle and its resources",
+		Args: graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+			"components": componentsArgument,
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return changeBundleComponents(params)
+		},
+	},
+}
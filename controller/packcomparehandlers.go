@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"github.com/fernandoocampo/pack/model"
+	"github.com/graphql-go/graphql"
+)
+
+// comparePacks implements IPackService.ComparePacks, the packs not sold
+// in the channel and region arguments are left out of the comparison.
+func comparePacks(params graphql.ResolveParams) (interface{}, error) {
+	ids := []string{}
+	if values, ok := params.Args["ids"].([]interface{}); ok {
+		for _, value := range values {
+			if id, ok := value.(string); ok {
+				ids = append(ids, id)
+			}
+		}
+	}
+	comparison, err := tenantPackService(params).ComparePacks(ids)
+	if err != nil {
+		return nil, err
+	}
+	packs := make([]model.Pack, 0, len(comparison.Packs))
+	for i := range comparison.Packs {
+		packs = append(packs, comparison.Packs[i].Pack)
+	}
+	if available := availablePacks(params, packs); len(available) < len(packs) {
+		comparison = model.NewPackComparison(available)
+	}
+	for i := range comparison.Packs {
+		comparison.Packs[i].Pack = *localizePack(params, &comparison.Packs[i].Pack)
+	}
+	return comparison, nil
+}
+
+// similarPacks implements IPackService.SimilarPacks, only packs sold in
+// the channel and region arguments are ranked.
+func similarPacks(params graphql.ResolveParams) (interface{}, error) {
+	id, _ := params.Args["id"].(string)
+	limit, _ := params.Args["limit"].(int)
+	similar, err := tenantPackService(params).SimilarPacks(id, model.NewPackFilter(params.Args), limit)
+	if err != nil {
+		return nil, err
+	}
+	for i := range similar {
+		similar[i].Pack = *localizePack(params, &similar[i].Pack)
+	}
+	return similar, nil
+}
@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fernandoocampo/pack/auth"
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/service"
+	"github.com/graphql-go/graphql"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// comparePackService is the rest fake that compares its packs.
+type comparePackService struct {
+	*restPackService
+}
+
+func (s *comparePackService) WithTenant(tenant *model.Tenant) service.IPackService {
+	return s
+}
+
+func (s *comparePackService) ComparePacks(ids []string) (*model.PackComparison, error) {
+	packs := []model.Pack{}
+	for _, id := range ids {
+		packs = append(packs, *s.packs[id])
+	}
+	return model.NewPackComparison(packs), nil
+}
+
+// TestComparePacksAvailability tests the packs not sold in the channel
+// and region arguments are left out of the comparison
+func TestComparePacksAvailability(t *testing.T) {
+	_, packs, pack := newRestTest(t)
+	pack.Resources = []model.Resource{{ID: 1, Name: "whatsapp", Amount: 1, Units: "GB"}}
+	retail := &model.Pack{ID: bson.NewObjectId(), Packcode: "0009", Price: 3000, Channels: []string{"retail"},
+		Resources: []model.Resource{{ID: 2, Name: "voice", Amount: 100, Units: "min"}}}
+	packs.packs[retail.ID.Hex()] = retail
+	SetService(&comparePackService{restPackService: packs})
+	viewer := &model.Identity{Subject: "viewer", Roles: []string{model.RoleViewer}}
+	params := graphql.ResolveParams{Context: auth.NewContext(context.Background(), viewer),
+		Args: map[string]interface{}{"ids": []interface{}{pack.ID.Hex(), retail.ID.Hex()}}}
+
+	result, err := comparePacks(params)
+	if comparison := result.(*model.PackComparison); err != nil || len(comparison.Packs) != 2 || len(comparison.Resources) != 2 {
+		t.Fatalf("Expected both packs compared but got %+v %v", result, err)
+	}
+
+	params.Args["channel"] = "web"
+	result, err = comparePacks(params)
+	comparison := result.(*model.PackComparison)
+	if err != nil || len(comparison.Packs) != 1 || comparison.Packs[0].Pack.ID != pack.ID {
+		t.Fatalf("Expected only the pack sold in the web but got %+v %v", comparison, err)
+	}
+	if len(comparison.Resources) != 1 || len(comparison.Resources[0].Values) != 1 {
+		t.Errorf("Expected only the resources of the pack sold in the web but got %+v", comparison.Resources)
+	}
+}
@@ -0,0 +1,137 @@
+package controller
+
+import "github.com/graphql-go/graphql"
+
+// Compared pack
+var comparedPackType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ComparedPack",
+	Description: "A pack of a comparison and its price per day",
+	Fields: graphql.Fields{
+		"pack": &graphql.Field{
+			Type:        packType,
+			Description: "the compared pack.",
+		},
+		"validityDays": &graphql.Field{
+			Type:        graphql.Float,
+			Description: "days the pack is valid, a month is 30 days.",
+		},
+		"pricePerDay": &graphql.Field{
+			Type:        graphql.Float,
+			Description: "price of the pack divided by its validity days.",
+		},
+	},
+})
+
+// Amount of a resource in a compared pack
+var resourceValueType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ResourceValue",
+	Description: "The amount of a resource in a compared pack",
+	Fields: graphql.Fields{
+		"packid": &graphql.Field{
+			Type:        graphql.String,
+			Description: "id of the pack.",
+		},
+		"amount": &graphql.Field{
+			Type:        graphql.Float,
+			Description: "amount of the resource in the units of the comparison, 0 if the pack does not have it.",
+		},
+		"isfree": &graphql.Field{
+			Type:        graphql.Boolean,
+			Description: "indicates if the resource is free in the pack.",
+		},
+		"pricePerUnit": &graphql.Field{
+			Type:        graphql.Float,
+			Description: "price of the pack divided by the amount, null if the pack does not have the resource.",
+		},
+	},
+})
+
+// Resource of the compared packs
+var resourceComparisonType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ResourceComparison",
+	Description: "A resource of the compared packs with a value for every pack",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "id of the resource.",
+		},
+		"name": &graphql.Field{
+			Type:        graphql.String,
+			Description: "name of the resource.",
+		},
+		"units": &graphql.Field{
+			Type:        graphql.String,
+			Description: "units of the amounts, data is compared in GB and time in minutes.",
+		},
+		"values": &graphql.Field{
+			Type:        graphql.NewList(resourceValueType),
+			Description: "amounts of the resource in the order of the compared packs.",
+		},
+	},
+})
+
+// Comparison of packs
+var packComparisonType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "PackComparison",
+	Description: "Packs side by side with their resources aligned",
+	Fields: graphql.Fields{
+		"packs": &graphql.Field{
+			Type:        graphql.NewList(comparedPackType),
+			Description: "the compared packs in the order of the ids.",
+		},
+		"resources": &graphql.Field{
+			Type:        graphql.NewList(resourceComparisonType),
+			Description: "every resource of the compared packs.",
+		},
+	},
+})
+
+// Similar pack
+var similarPackType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "SimilarPack",
+	Description: "A pack similar to another one",
+	Fields: graphql.Fields{
+		"pack": &graphql.Field{
+			Type:        packType,
+			Description: "the similar pack.",
+		},
+		"score": &graphql.Field{
+			Type:        graphql.Float,
+			Description: "similarity from 0 to 1 of the resources, price and validity.",
+		},
+	},
+})
+
+// packCompareQueryFields contains the queries that compare packs.
+var packCompareQueryFields = graphql.Fields{
+	"comparePacks": &graphql.Field{
+		Type:        packComparisonType,
+		Description: "query between 2 and 10 packs side by side with their resources, price per unit and validity",
+		Args: mergeArguments(availabilityArguments(), graphql.FieldConfigArgument{
+			"ids": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			},
+			"locale": localeArgument,
+		}),
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return comparePacks(params)
+		},
+	},
+	"similarPacks": &graphql.Field{
+		Type:        graphql.NewList(similarPackType),
+		Description: "query the active packs in stock of the mno of a pack, the most similar first",
+		Args: mergeArguments(availabilityArguments(), graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+			"limit": &graphql.ArgumentConfig{
+				Type:         graphql.Int,
+				DefaultValue: 5,
+			},
+			"locale": localeArgument,
+		}),
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return similarPacks(params)
+		},
+	},
+}
@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"github.com/fernandoocampo/pack/events"
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/service"
+	"github.com/graphql-go/graphql"
+)
+
+// eventBroker passes the pack events relayed by this process to the
+// graphql subscriptions.
+var eventBroker = events.NewBroker(events.DefaultBrokerBuffer)
+
+// subscribePackChanged subscribes the caller to the events of a pack.
+func subscribePackChanged(params graphql.ResolveParams) (interface{}, error) {
+	id, _ := params.Args["id"].(string)
+	return subscribePackEvents(params, func(event *model.Event) bool {
+		return event.PackID == id
+	})
+}
+
+// subscribeStockChanged subscribes the caller to the stock moves of the
+// packs of a mno.
+func subscribeStockChanged(params graphql.ResolveParams) (interface{}, error) {
+	mnoid, _ := params.Args["mnoid"].(int)
+	filter := &model.PackFilter{MnoID: int8(mnoid)}
+	return subscribePackEvents(params, func(event *model.Event) bool {
+		return event.Type == model.EventStockMoved && filter.Matches(event.Pack)
+	})
+}
+
+// subscribePacksChanged subscribes the caller to the events of the packs
+// that match the filter.
+func subscribePacksChanged(params graphql.ResolveParams) (interface{}, error) {
+	filter := bulkFilter(params)
+	return subscribePackEvents(params, func(event *model.Event) bool {
+		return filter.Matches(event.Pack)
+	})
+}
+
+// subscribePackEvents returns the channel of the events that match and
+// whose pack belongs to the tenant of the caller. It is closed when the
+// caller goes away, and after service.ErrSubscriptionBehind if the
+// caller does not keep up with the events.
+func subscribePackEvents(params graphql.ResolveParams, match func(event *model.Event) bool) (interface{}, error) {
+	tenant := tenantFrom(params.Context)
+	broker := eventBroker
+	subscription := broker.Subscribe(func(event *model.Event) bool {
+		return tenant.Owns(event.Pack) && match(event)
+	})
+	payloads := make(chan interface{})
+	go func() {
+		defer close(payloads)
+		defer broker.Unsubscribe(subscription)
+		for {
+			var payload interface{}
+			select {
+			case <-params.Context.Done():
+				return
+			case event, open := <-subscription.C:
+				if !open && !subscription.Dropped() {
+					return
+				}
+				payload = event
+				if !open {
+					payload = service.ErrSubscriptionBehind
+				}
+			}
+			select {
+			case payloads <- payload:
+			case <-params.Context.Done():
+				return
+			}
+			if payload == service.ErrSubscriptionBehind {
+				return
+			}
+		}
+	}()
+	return payloads, nil
+}
+
+// resolvePackEvent returns the event pushed to a subscription, or the
+// error that ends it.
+func resolvePackEvent(params graphql.ResolveParams) (interface{}, error) {
+	switch payload := params.Source.(type) {
+	case *model.Event:
+		return payload, nil
+	case error:
+		return nil, payload
+	}
+	return nil, nil
+}
+
+// SetEventBroker sets the broker the graphql subscriptions get the pack
+// events from, it must be one of the sinks of the relay.
+func SetEventBroker(broker *events.Broker) {
+	eventBroker = broker
+}
@@ -0,0 +1,179 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fernandoocampo/pack/auth"
+	"github.com/fernandoocampo/pack/events"
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/service"
+	"github.com/gorilla/websocket"
+	"github.com/graphql-go/graphql"
+)
+
+// tokenAuthenticator returns the identity of the authorization header.
+type tokenAuthenticator map[string]*model.Identity
+
+func (a tokenAuthenticator) Authenticate(r *http.Request) (*model.Identity, error) {
+	identity, ok := a[r.Header.Get("Authorization")]
+	if !ok {
+		return nil, errors.New("unknown token")
+	}
+	return identity, nil
+}
+
+// waitSubscribers waits until the broker has the given subscribers.
+func waitSubscribers(t *testing.T, broker *events.Broker, want int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for broker.Subscribers() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("broker has %d subscribers, want %d", broker.Subscribers(), want)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// TestSubscribePackEventsTenant tests a subscriber only gets the events of
+// the packs of its tenant.
+func TestSubscribePackEventsTenant(t *testing.T) {
+	broker := events.NewBroker(4)
+	defer SetEventBroker(eventBroker)
+	SetEventBroker(broker)
+	identity := &model.Identity{Subject: "partner", Ownerid: 7, Roles: []string{model.RoleViewer}}
+	ctx, cancel := context.WithCancel(auth.NewContext(context.Background(), identity))
+	params := graphql.ResolveParams{Context: ctx}
+
+	result, err := subscribePackEvents(params, func(event *model.Event) bool { return true })
+	if err != nil {
+		t.Fatalf("subscribePackEvents() error = %v", err)
+	}
+	payloads := result.(chan interface{})
+	other := model.NewEvent(model.EventPackUpdated, "other", nil)
+	other.Pack = &model.Pack{Ownerid: 8}
+	own := model.NewEvent(model.EventPackUpdated, "own", nil)
+	own.Pack = &model.Pack{Ownerid: 7}
+	_ = broker.Publish(&other)
+	_ = broker.Publish(&own)
+
+	payload := <-payloads
+	if event, ok := payload.(*model.Event); !ok || event.PackID != "own" {
+		t.Fatalf("payload = %v, want the event of pack own", payload)
+	}
+	cancel()
+	for range payloads {
+	}
+	waitSubscribers(t, broker, 0)
+}
+
+// TestSubscribePackEventsBehind tests a subscriber that does not keep up
+// gets service.ErrSubscriptionBehind and its subscription ends.
+func TestSubscribePackEventsBehind(t *testing.T) {
+	broker := events.NewBroker(1)
+	defer SetEventBroker(eventBroker)
+	SetEventBroker(broker)
+	identity := &model.Identity{Subject: "admin", Roles: []string{model.RolePlatformAdmin}}
+	params := graphql.ResolveParams{Context: auth.NewContext(context.Background(), identity)}
+
+	result, err := subscribePackEvents(params, func(event *model.Event) bool { return true })
+	if err != nil {
+		t.Fatalf("subscribePackEvents() error = %v", err)
+	}
+	payloads := result.(chan interface{})
+	for i := 0; i < 4; i++ {
+		event := model.NewEvent(model.EventPackUpdated, "pack", nil)
+		event.Pack = &model.Pack{Ownerid: 1}
+		_ = broker.Publish(&event)
+	}
+
+	var last interface{}
+	for payload := range payloads {
+		last = payload
+	}
+	if last != service.ErrSubscriptionBehind {
+		t.Errorf("last payload = %v, want %v", last, service.ErrSubscriptionBehind)
+	}
+	if broker.Subscribers() != 0 {
+		t.Errorf("broker has %d subscribers, want 0", broker.Subscribers())
+	}
+}
+
+// TestGraphqlWebSocket tests a client subscribes to the changes of a pack
+// over a websocket and gets them.
+func TestGraphqlWebSocket(t *testing.T) {
+	broker := events.NewBroker(4)
+	defer SetEventBroker(eventBroker)
+	SetEventBroker(broker)
+	defer SetAuthenticator(authenticator)
+	SetAuthenticator(tokenAuthenticator{
+		"Bearer partner": {Subject: "partner", Ownerid: 7, Roles: []string{model.RoleViewer}},
+	})
+	server := httptest.NewServer(http.HandlerFunc(httpWebSocket))
+	defer server.Close()
+	address := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	t.Run("rejects unknown clients", func(t *testing.T) {
+		dialer := websocket.Dialer{Subprotocols: []string{graphqlTransportWS}}
+		conn, _, err := dialer.Dial(address, nil)
+		if err != nil {
+			t.Fatalf("Dial() error = %v", err)
+		}
+		defer conn.Close()
+		_ = conn.WriteJSON(map[string]interface{}{"type": "connection_init", "payload": map[string]string{"Authorization": "Bearer nobody"}})
+		_, _, err = conn.ReadMessage()
+		if !websocket.IsCloseError(err, 4403) {
+			t.Errorf("ReadMessage() error = %v, want close 4403", err)
+		}
+	})
+
+	t.Run("pushes the pack changes", func(t *testing.T) {
+		dialer := websocket.Dialer{Subprotocols: []string{graphqlTransportWS}}
+		conn, _, err := dialer.Dial(address, nil)
+		if err != nil {
+			t.Fatalf("Dial() error = %v", err)
+		}
+		defer conn.Close()
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		_ = conn.WriteJSON(map[string]interface{}{"type": "connection_init", "payload": map[string]string{"Authorization": "Bearer partner"}})
+		message := wsMessage{}
+		if err := conn.ReadJSON(&message); err != nil || message.Type != "connection_ack" {
+			t.Fatalf("ReadJSON() = %+v, %v, want connection_ack", message, err)
+		}
+		_ = conn.WriteJSON(map[string]interface{}{"id": "1", "type": "subscribe",
+			"payload": map[string]string{"query": `subscription { packChanged(id: "p1") { type packid } }`}})
+		waitSubscribers(t, broker, 1)
+
+		other := model.NewEvent(model.EventPackUpdated, "p2", nil)
+		other.Pack = &model.Pack{Ownerid: 7}
+		changed := model.NewEvent(model.EventPackUpdated, "p1", nil)
+		changed.Pack = &model.Pack{Ownerid: 7}
+		_ = broker.Publish(&other)
+		_ = broker.Publish(&changed)
+
+		if err := conn.ReadJSON(&message); err != nil || message.Type != "next" || message.ID != "1" {
+			t.Fatalf("ReadJSON() = %+v, %v, want next of 1", message, err)
+		}
+		result := struct {
+			Data struct {
+				PackChanged struct {
+					Type   string `json:"type"`
+					PackID string `json:"packid"`
+				} `json:"packChanged"`
+			} `json:"data"`
+		}{}
+		_ = json.Unmarshal(message.Payload, &result)
+		if result.Data.PackChanged.PackID != "p1" || result.Data.PackChanged.Type != model.EventPackUpdated {
+			t.Errorf("payload = %s, want the update of p1", message.Payload)
+		}
+
+		_ = conn.WriteJSON(map[string]interface{}{"id": "1", "type": "complete"})
+		waitSubscribers(t, broker, 0)
+	})
+}
@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"encoding/json"
+
+	"github.com/fernandoocampo/pack/model"
+	"github.com/graphql-go/graphql"
+)
+
+// packEventType is a change of a pack sent to the graphql subscriptions.
+var packEventType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "PackEvent",
+	Description: "A change of a pack",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The id of the event, the same in every delivery.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				event := p.Source.(*model.Event)
+				return event.ID.Hex(), nil
+			},
+		},
+		"type": &graphql.Field{
+			Type:        graphql.String,
+			Description: "type of the change. e.g. PackPriceChanged, StockMoved.",
+		},
+		"packid": &graphql.Field{
+			Type:        graphql.String,
+			Description: "id of the changed pack.",
+		},
+		"data": &graphql.Field{
+			Type:        graphql.String,
+			Description: "new values of the changed fields as json.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				event := p.Source.(*model.Event)
+				if len(event.Data) == 0 {
+					return nil, nil
+				}
+				data, err := json.Marshal(event.Data)
+				if err != nil {
+					return nil, err
+				}
+				return string(data), nil
+			},
+		},
+		"occurred": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "when the change was made.",
+		},
+		"pack": &graphql.Field{
+			Type:        packType,
+			Description: "the pack after the change, the last one for deleted packs.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				event := p.Source.(*model.Event)
+				return event.Pack, nil
+			},
+		},
+	},
+})
+
+// rootSubscription root subscription schema, the changes of the packs of
+// the caller are pushed over a websocket.
+var rootSubscription = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Subscription",
+	Fields: graphql.Fields{
+		/*
+		   http://localhost:8287/graphql over websocket
+		   subscription { packChanged(id:"5a12211dcc7c76da03df50f7") { type, pack { stock, state } } }
+		*/
+		"packChanged": &graphql.Field{
+			Type:        packEventType,
+			Description: "get every change of a pack",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			},
+			Subscribe: func(params graphql.ResolveParams) (interface{}, error) {
+				return subscribePackChanged(params)
+			},
+			Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+				return resolvePackEvent(params)
+			},
+		},
+		"stockChanged": &graphql.Field{
+			Type:        packEventType,
+			Description: "get the stock moves of the packs of a mno, of every mno if it is not given",
+			Args: graphql.FieldConfigArgument{
+				"mnoid": &graphql.ArgumentConfig{
+					Type: graphql.Int,
+				},
+			},
+			Subscribe: func(params graphql.ResolveParams) (interface{}, error) {
+				return subscribeStockChanged(params)
+			},
+			Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+				return resolvePackEvent(params)
+			},
+		},
+		"packsChanged": &graphql.Field{
+			Type:        packEventType,
+			Description: "get every change of the packs that match the filter after the change",
+			Args: graphql.FieldConfigArgument{
+				"filter": &graphql.ArgumentConfig{
+					Type: packFilterInput,
+				},
+			},
+			Subscribe: func(params graphql.ResolveParams) (interface{}, error) {
+				return subscribePacksChanged(params)
+			},
+			Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+				return resolvePackEvent(params)
+			},
+		},
+	},
+})
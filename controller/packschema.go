@@ -186,6 +186,33 @@ var resultType = graphql.NewObject(graphql.ObjectConfig{
 		"msg": &graphql.Field{
 			Type: graphql.String,
 		},
+		"error": &graphql.Field{
+			Type:        errorDetailType,
+			Description: "details of the failure, null on success.",
+		},
+	},
+})
+
+// errorDetailType contains the details of a failed operation.
+var errorDetailType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "ErrorDetail",
+	Fields: graphql.Fields{
+		"code": &graphql.Field{
+			Type:        graphql.String,
+			Description: "stable code of the error.",
+		},
+		"message": &graphql.Field{
+			Type:        graphql.String,
+			Description: "message in the locale of the caller.",
+		},
+		"category": &graphql.Field{
+			Type:        graphql.String,
+			Description: "kind of error. e.g. INVALID_ARGUMENT, NOT_FOUND, CONFLICT.",
+		},
+		"field": &graphql.Field{
+			Type:        graphql.String,
+			Description: "argument that caused the error.",
+		},
 	},
 })
 
@@ -226,7 +253,7 @@ var packType = graphql.NewObject(graphql.ObjectConfig{
 			Description: "The id of the pack.",
 			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
 				// required to return just the hex representation of objectid
-				pack := p.Source.(*model.Pack)
+				pack := packFromSource(p.Source)
 				if pack == nil {
 					return nil, nil
 				}
@@ -290,21 +317,46 @@ var packType = graphql.NewObject(graphql.ObjectConfig{
 			Type:        graphql.NewList(resourceInterface),
 			Description: "a list of resources that this pack provides",
 		},
+		"locale": &graphql.Field{
+			Type:        graphql.String,
+			Description: "locale of the name, description and keywords of the pack.",
+		},
+		"translations": &graphql.Field{
+			Type:        graphql.NewList(translationType),
+			Description: "the texts of the pack in other locales",
+		},
+		"components": &graphql.Field{
+			Type:        graphql.NewList(graphql.String),
+			Description: "ids of the packs of a bundle, empty if the pack is not a bundle",
+		},
+		"rules": &graphql.Field{
+			Type:        graphql.NewList(eligibilityRuleType),
+			Description: "conditions a customer must meet to buy the pack",
+		},
+		"channels": &graphql.Field{
+			Type:        graphql.NewList(graphql.String),
+			Description: "channels where the pack is sold, empty for every channel",
+		},
+		"regions": &graphql.Field{
+			Type:        graphql.NewList(graphql.String),
+			Description: "regions where the pack is sold, empty for every region",
+		},
 	},
 })
 
 // root query for packs
 var rootQuery = graphql.NewObject(graphql.ObjectConfig{
 	Name: "packQuery",
-	Fields: graphql.Fields{
+	Fields: mergeFields(graphql.Fields{
 		"byID": &graphql.Field{
 			Type:        packType,
 			Description: "query a pack by its id",
-			Args: graphql.FieldConfigArgument{
+			Args: mergeArguments(availabilityArguments(), graphql.FieldConfigArgument{
 				"id": &graphql.ArgumentConfig{
 					Type: graphql.NewNonNull(graphql.String),
 				},
-			},
+				"locale": localeArgument,
+			}),
 			Resolve: func(params graphql.ResolveParams) (interface{}, error) {
 				return getByID(params)
 			},
@@ -312,11 +364,12 @@ var rootQuery = graphql.NewObject(graphql.ObjectConfig{
 		"byCode": &graphql.Field{
 			Type:        packType,
 			Description: "query a pack by its code",
-			Args: graphql.FieldConfigArgument{
+			Args: mergeArguments(availabilityArguments(), graphql.FieldConfigArgument{
 				"packcode": &graphql.ArgumentConfig{
 					Type: graphql.NewNonNull(graphql.String),
 				},
-			},
+				"locale": localeArgument,
+			}),
 			Resolve: func(params graphql.ResolveParams) (interface{}, error) {
 				return getByPackCode(params)
 			},
@@ -336,15 +389,34 @@ var rootQuery = graphql.NewObject(graphql.ObjectConfig{
 		"byProductID": &graphql.Field{
 			Type:        packType,
 			Description: "query a pack by its internal product id",
-			Args: graphql.FieldConfigArgument{
+			Args: mergeArguments(availabilityArguments(), graphql.FieldConfigArgument{
 				"productid": &graphql.ArgumentConfig{
 					Type: graphql.NewNonNull(graphql.String),
 				},
-			},
+				"locale": localeArgument,
+			}),
 			Resolve: func(params graphql.ResolveParams) (interface{}, error) {
 				return getByProductID(params)
 			},
 		},
+		"packs": &graphql.Field{
+			Type:        graphql.NewList(packType),
+			Description: "query the packs that match the filter sorted by pack code",
+			Args: mergeArguments(packFilterArguments(), graphql.FieldConfigArgument{
+				"skip": &graphql.ArgumentConfig{
+					Type:         graphql.Int,
+					DefaultValue: 0,
+				},
+				"limit": &graphql.ArgumentConfig{
+					Type:         graphql.Int,
+					DefaultValue: 50,
+				},
+				"locale": localeArgument,
+			}),
+			Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+				return getPacks(params)
+			},
+		},
 		"byKeys": &graphql.Field{
 			Type:        graphql.Boolean,
 			Description: "query a pack by mno id and (productid or packcode)",
@@ -363,7 +435,11 @@ var rootQuery = graphql.NewObject(graphql.ObjectConfig{
 				return getByKeys(params)
 			},
 		},
-	},
+	}, entitlementQueryFields, subscriptionQueryFields, orderQueryFields,
+		commissionQueryFields, settlementQueryFields, ownerQueryFields, apiKeyQueryFields,
+		translationQueryFields, bulkQueryFields, packTemplateQueryFields, eligibilityQueryFields,
+		availabilityQueryFields, packCompareQueryFields, catalogStatsQueryFields, salesReportQueryFields,
+		webhookQueryFields),
 })
 
 // packMutation root mutation schema for User, here we specify the app capabilities.
@@ -374,51 +450,14 @@ var rootQuery = graphql.NewObject(graphql.ObjectConfig{
 // - It is separate of queries
 var packMutation = graphql.NewObject(graphql.ObjectConfig{
 	Name: "PackMutation",
-	Fields: graphql.Fields{
+	Fields: mergeFields(graphql.Fields{
 		/*
 			create a pack.
 		*/
 		"create": &graphql.Field{
 			Type:        resultType, // the return type for this field
 			Description: "creates a new Pack",
-			Args: graphql.FieldConfigArgument{
-				"prodid": &graphql.ArgumentConfig{
-					Type: graphql.NewNonNull(graphql.String),
-				},
-				"packcode": &graphql.ArgumentConfig{
-					Type: graphql.NewNonNull(graphql.String),
-				},
-				"name": &graphql.ArgumentConfig{
-					Type: graphql.NewNonNull(graphql.String),
-				},
-				"desc": &graphql.ArgumentConfig{
-					Type: graphql.NewNonNull(graphql.String),
-				},
-				"imgurl": &graphql.ArgumentConfig{
-					Type: graphql.NewNonNull(graphql.String),
-				},
-				"kwds": &graphql.ArgumentConfig{
-					Type: graphql.NewNonNull(graphql.String),
-				},
-				"price": &graphql.ArgumentConfig{
-					Type: graphql.NewNonNull(graphql.Int),
-				},
-				"ownerid": &graphql.ArgumentConfig{
-					Type: graphql.NewNonNull(graphql.Int),
-				},
-				"type": &graphql.ArgumentConfig{
-					Type: graphql.NewNonNull(inputType),
-				},
-				"mno": &graphql.ArgumentConfig{
-					Type: graphql.NewNonNull(inputMno),
-				},
-				"term": &graphql.ArgumentConfig{
-					Type: graphql.NewNonNull(inputTerm),
-				},
-				"currency": &graphql.ArgumentConfig{
-					Type: inputCcy,
-				},
-			},
+			Args:        packArguments(),
 			Resolve: func(params graphql.ResolveParams) (interface{}, error) {
 				return create(params)
 			},
@@ -719,5 +758,125 @@ var packMutation = graphql.NewObject(graphql.ObjectConfig{
 				return deletePackResources(params)
 			},
 		},
-	},
+	}, entitlementMutationFields, subscriptionMutationFields, orderMutationFields,
+		commissionMutationFields, settlementMutationFields, ownerMutationFields, apiKeyMutationFields,
+		translationMutationFields, bulkMutationFields, packTemplateMutationFields, packBundleMutationFields,
+		eligibilityMutationFields, availabilityMutationFields, webhookMutationFields),
 })
+
+// packArguments returns the arguments with the data of a new pack.
+func packArguments() graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		"prodid": &graphql.ArgumentConfig{
+			Type: graphql.NewNonNull(graphql.String),
+		},
+		"packcode": &graphql.ArgumentConfig{
+			Type: graphql.NewNonNull(graphql.String),
+		},
+		"name": &graphql.ArgumentConfig{
+			Type: graphql.NewNonNull(graphql.String),
+		},
+		"desc": &graphql.ArgumentConfig{
+			Type: graphql.NewNonNull(graphql.String),
+		},
+		"imgurl": &graphql.ArgumentConfig{
+			Type: graphql.NewNonNull(graphql.String),
+		},
+		"kwds": &graphql.ArgumentConfig{
+			Type: graphql.NewNonNull(graphql.String),
+		},
+		"price": &graphql.ArgumentConfig{
+			Type: graphql.NewNonNull(graphql.Int),
+		},
+		"ownerid": &graphql.ArgumentConfig{
+			Type: graphql.NewNonNull(graphql.Int),
+		},
+		"type": &graphql.ArgumentConfig{
+			Type: graphql.NewNonNull(inputType),
+		},
+		"mno": &graphql.ArgumentConfig{
+			Type: graphql.NewNonNull(inputMno),
+		},
+		"term": &graphql.ArgumentConfig{
+			Type: graphql.NewNonNull(inputTerm),
+		},
+		"currency": &graphql.ArgumentConfig{
+			Type: inputCcy,
+		},
+	}
+}
+
+// packFilterArguments returns the arguments of the queries and mutations
+// over the packs that match a filter, every one is optional.
+func packFilterArguments() graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		"mnoid": &graphql.ArgumentConfig{
+			Type:        graphql.Int,
+			Description: "mno of the packs",
+		},
+		"ownerid": &graphql.ArgumentConfig{
+			Type:        graphql.Int,
+			Description: "owner of the packs",
+		},
+		"typeid": &graphql.ArgumentConfig{
+			Type:        graphql.Int,
+			Description: "type of the packs",
+		},
+		"state": &graphql.ArgumentConfig{
+			Type:        graphql.Int,
+			Description: "state of the packs. 1. active, 0. inactive",
+		},
+		"minprice": &graphql.ArgumentConfig{
+			Type:        graphql.Int,
+			Description: "lowest price of the packs",
+		},
+		"maxprice": &graphql.ArgumentConfig{
+			Type:        graphql.Int,
+			Description: "highest price of the packs",
+		},
+		"channel": &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "channel where the packs are sold",
+		},
+		"region": &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "region where the packs are sold",
+		},
+	}
+}
+
+// mergeArguments joins the given argument maps into a new one.
+func mergeArguments(argmaps ...graphql.FieldConfigArgument) graphql.FieldConfigArgument {
+	result := graphql.FieldConfigArgument{}
+	for _, argmap := range argmaps {
+		for name, arg := range argmap {
+			result[name] = arg
+		}
+	}
+	return result
+}
+
+// mergeFields joins the given field maps into a new one, so every
+// subsystem can declare its own queries and mutations in its own schema file.
+func mergeFields(fieldmaps ...graphql.Fields) graphql.Fields {
+	result := graphql.Fields{}
+	for _, fields := range fieldmaps {
+		for name, field := range fields {
+			result[name] = field
+		}
+	}
+	return result
+}
+
+// packFromSource returns the pack resolved by a parent field, lists
+// give values and single queries give pointers.
+func packFromSource(source interface{}) *model.Pack {
+	switch pack := source.(type) {
+	case *model.Pack:
+		return pack
+	case model.Pack:
+		return &pack
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"github.com/fernandoocampo/pack/model"
+	"github.com/graphql-go/graphql"
+)
+
+// clonePack implements *IPackService.Clone. The id of the copy goes in
+// the result message.
+func clonePack(params graphql.ResolveParams) (interface{}, error) {
+	id, _ := params.Args["id"].(string)
+	overrides, _ := params.Args["overrides"].(map[string]interface{})
+
+	clone, err := tenantPackService(params).Clone(id, model.NewPackOverrides(overrides))
+
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	result := model.NewOKResult("10")
+	result.Msg = clone.ID.Hex()
+	return result, nil
+}
+
+// createPackTemplate implements *IPackService.CreateTemplate. The id of
+// the template goes in the result message.
+func createPackTemplate(params graphql.ResolveParams) (interface{}, error) {
+	name, _ := params.Args["template"].(string)
+	template := model.NewPackTemplate(name, model.NewPack(params.Args))
+
+	err := tenantPackService(params).CreateTemplate(template)
+
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	result := model.NewOKResult("10")
+	result.Msg = template.ID.Hex()
+	return result, nil
+}
+
+// deletePackTemplate implements *IPackService.DeleteTemplate.
+func deletePackTemplate(params graphql.ResolveParams) (interface{}, error) {
+	id, _ := params.Args["id"].(string)
+
+	err := tenantPackService(params).DeleteTemplate(id)
+
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	return model.NewOKResult("10"), nil
+}
+
+// createPackFromTemplate implements *IPackService.CreateFromTemplate. The
+// id of the new pack goes in the result message.
+func createPackFromTemplate(params graphql.ResolveParams) (interface{}, error) {
+	id, _ := params.Args["id"].(string)
+	values := map[string]string{}
+	list, _ := params.Args["values"].([]interface{})
+	for _, item := range list {
+		value, _ := item.(map[string]interface{})
+		name, _ := value["name"].(string)
+		values[name], _ = value["value"].(string)
+	}
+
+	pack, err := tenantPackService(params).CreateFromTemplate(id, values)
+
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	result := model.NewOKResult("10")
+	result.Msg = pack.ID.Hex()
+	return result, nil
+}
+
+// getPackTemplate implements *IPackService.GetTemplate.
+func getPackTemplate(params graphql.ResolveParams) (interface{}, error) {
+	id, _ := params.Args["id"].(string)
+	return tenantPackService(params).GetTemplate(id)
+}
+
+// getPackTemplates implements *IPackService.GetTemplates.
+func getPackTemplates(params graphql.ResolveParams) (interface{}, error) {
+	return tenantPackService(params).GetTemplates()
+}
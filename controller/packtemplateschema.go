@@ -0,0 +1,222 @@
+package controller
+
+import (
+	"github.com/fernandoocampo/pack/model"
+	"github.com/graphql-go/graphql"
+)
+
+// packOverridesInput contains the data of a cloned pack that is not
+// copied from the source pack.
+var packOverridesInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "PackOverrides",
+	Description: "The data of a cloned pack that is not copied, fields that are not set are copied",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"prodid": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "product id of the clone",
+		},
+		"packcode": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "pack code of the clone",
+		},
+		"name": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "name of the clone, a new name, description or keywords removes the translations",
+		},
+		"desc": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "description of the clone",
+		},
+		"imgurl": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "image url of the clone",
+		},
+		"kwds": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "keywords of the clone",
+		},
+		"price": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "price of the clone",
+		},
+		"ownerid": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "owner of the clone",
+		},
+		"type": &graphql.InputObjectFieldConfig{
+			Type:        inputType,
+			Description: "pack type of the clone",
+		},
+		"mno": &graphql.InputObjectFieldConfig{
+			Type:        inputMno,
+			Description: "mno of the clone",
+		},
+		"term": &graphql.InputObjectFieldConfig{
+			Type:        inputTerm,
+			Description: "validity of the clone",
+		},
+		"currency": &graphql.InputObjectFieldConfig{
+			Type:        inputCcy,
+			Description: "currency of the price of the clone",
+		},
+	},
+})
+
+// placeholderValueInput contains the value of a placeholder of a template.
+var placeholderValueInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "PlaceholderValue",
+	Description: "The value of a placeholder of a pack template",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"name": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "name of the placeholder. e.g. number for {{number}}",
+		},
+		"value": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "text that replaces the placeholder",
+		},
+	},
+})
+
+// packTemplateType is the data of a series of packs.
+var packTemplateType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "PackTemplate",
+	Description: "The data of a series of packs with placeholders in its texts",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The id of the template.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				template := packTemplateFromSource(p.Source)
+				if template == nil {
+					return nil, nil
+				}
+				return template.ID.Hex(), nil
+			},
+		},
+		"name": &graphql.Field{
+			Type:        graphql.String,
+			Description: "name of the template.",
+		},
+		"placeholders": &graphql.Field{
+			Type:        graphql.NewList(graphql.String),
+			Description: "names of the placeholders, every one needs a value to create a pack.",
+		},
+		"pack": &graphql.Field{
+			Type:        packType,
+			Description: "data of the packs, its texts have the placeholders.",
+		},
+		"created": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "when the template was created.",
+		},
+	},
+})
+
+// packTemplateQueryFields contains the queries over pack templates.
+var packTemplateQueryFields = graphql.Fields{
+	"packTemplate": &graphql.Field{
+		Type:        packTemplateType,
+		Description: "query a pack template by its id",
+		Args: graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return getPackTemplate(params)
+		},
+	},
+	"packTemplates": &graphql.Field{
+		Type:        graphql.NewList(packTemplateType),
+		Description: "query the pack templates sorted by name",
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return getPackTemplates(params)
+		},
+	},
+}
+
+// packTemplateMutationFields contains the clone of packs and the pack
+// templates mutations.
+var packTemplateMutationFields = graphql.Fields{
+	/*
+		clone a pack
+	*/
+	"clonePack": &graphql.Field{
+		Type:        resultType, // the return type for this field
+		Description: "creates a copy of a pack with the overrides, the id of the copy goes in msg",
+		Args: graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+			"overrides": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(packOverridesInput),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return clonePack(params)
+		},
+	},
+	/*
+		create a pack template
+	*/
+	"createPackTemplate": &graphql.Field{
+		Type:        resultType, // the return type for this field
+		Description: "creates a pack template, texts can have placeholders as {{number}}. The id of the template goes in msg",
+		Args: mergeArguments(packArguments(), graphql.FieldConfigArgument{
+			"template": &graphql.ArgumentConfig{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "name of the template",
+			},
+		}),
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return createPackTemplate(params)
+		},
+	},
+	/*
+		delete a pack template
+	*/
+	"deletePackTemplate": &graphql.Field{
+		Type:        resultType, // the return type for this field
+		Description: "deletes a pack template, the packs created with it are kept",
+		Args: graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return deletePackTemplate(params)
+		},
+	},
+	/*
+		create a pack with a template
+	*/
+	"createPackFromTemplate": &graphql.Field{
+		Type:        resultType, // the return type for this field
+		Description: "creates a pack with a template and a value for every placeholder, the id of the pack goes in msg",
+		Args: graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+			"values": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(placeholderValueInput))),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return createPackFromTemplate(params)
+		},
+	},
+}
+
+// packTemplateFromSource returns the template resolved by a parent field,
+// lists give values and single queries give pointers.
+func packTemplateFromSource(source interface{}) *model.PackTemplate {
+	switch template := source.(type) {
+	case *model.PackTemplate:
+		return template
+	case model.PackTemplate:
+		return &template
+	default:
+		return nil
+	}
+}
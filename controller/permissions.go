@@ -0,0 +1,230 @@
+package controller
+
+import (
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/service"
+	"github.com/graphql-go/graphql"
+)
+
+// role groups used in the permission map.
+var (
+	anyRole       = []string{model.RoleViewer, model.RoleCatalogEditor, model.RolePricingManager, model.RoleSeller, model.RoleAdmin, model.RolePlatformAdmin}
+	catalogRoles  = []string{model.RoleCatalogEditor, model.RoleAdmin, model.RolePlatformAdmin}
+	salesRoles    = []string{model.RoleSeller, model.RoleAdmin, model.RolePlatformAdmin}
+	adminRoles    = []string{model.RoleAdmin, model.RolePlatformAdmin}
+	platformRoles = []string{model.RolePlatformAdmin}
+	pricingRoles  = []string{model.RolePricingManager}
+	bulkRoles     = []string{model.RoleCatalogEditor, model.RolePricingManager, model.RoleAdmin, model.RolePlatformAdmin}
+)
+
+// permissions contains the roles allowed to use every field of the root
+// query, the root mutation and the root subscription. A field that is not
+// here cannot be used.
+var permissions = map[string][]string{
+	// queries
+	"byCode":              anyRole,
+	"byID":                anyRole,
+	"byKeys":              anyRole,
+	"byProductID":         anyRole,
+	"packs":               anyRole,
+	"idByCode":            anyRole,
+	"balances":            anyRole,
+	"entitlements":        anyRole,
+	"packSubscriptions":   anyRole,
+	"order":               anyRole,
+	"orders":              anyRole,
+	"provisionStatus":     anyRole,
+	"commissionRules":     anyRole,
+	"settlement":          anyRole,
+	"settlementStatement": anyRole,
+	"settlements":         anyRole,
+	"owner":               anyRole,
+	"owners":              anyRole,
+	"packsByOwner":        anyRole,
+	"searchPacks":         anyRole,
+	"apiKeys":             platformRoles,
+	"bulkJob":             bulkRoles,
+	"bulkJobs":            bulkRoles,
+	"packAudit":           bulkRoles,
+	"packTemplate":        anyRole,
+	"packTemplates":       anyRole,
+	"eligiblePacks":       anyRole,
+	"checkEligibility":    anyRole,
+	"salesChannels":       anyRole,
+	"salesRegions":        anyRole,
+	"comparePacks":        anyRole,
+	"similarPacks":        anyRole,
+	"catalogStats":        anyRole,
+	"salesReport":         anyRole,
+	"webhooks":            adminRoles,
+	"webhookDeliveries":   adminRoles,
+	// subscriptions
+	"packChanged":  anyRole,
+	"stockChanged": anyRole,
+	"packsChanged": anyRole,
+	// catalog mutations
+	"create":                catalogRoles,
+	"changeCurrency":        catalogRoles,
+	"changeDescription":     catalogRoles,
+	"changeImageUrl":        catalogRoles,
+	"changeKeywords":        catalogRoles,
+	"changeMno":             catalogRoles,
+	"changeName":            catalogRoles,
+	"changePackCode":        catalogRoles,
+	"changeProductID":       catalogRoles,
+	"changeState":           catalogRoles,
+	"changeType":            catalogRoles,
+	"changeValidity":        catalogRoles,
+	"replaceResources":      catalogRoles,
+	"deletePackResources":   catalogRoles,
+	"setPackTranslation":    catalogRoles,
+	"removePackTranslation": catalogRoles,
+	"setEligibilityRules":   catalogRoles,
+	"changePrice":           pricingRoles,
+	"delete":                adminRoles,
+	"moveStock":             adminRoles,
+	// bulk mutations, a patch with a price also needs pricingRoles and
+	// a patch with other data catalogRoles
+	"bulkUpdatePacks": bulkRoles,
+	"bulkChangeState": catalogRoles,
+	// clones and pack templates
+	"clonePack":              catalogRoles,
+	"createPackTemplate":     catalogRoles,
+	"deletePackTemplate":     catalogRoles,
+	"createPackFromTemplate": catalogRoles,
+	// bundles
+	"createBundle":           catalogRoles,
+	"changeBundleComponents": catalogRoles,
+	// sales channels and regions
+	"setPackAvailability": catalogRoles,
+	"addSalesChannel":     platformRoles,
+	"removeSalesChannel":  platformRoles,
+	"addSalesRegion":      platformRoles,
+	"removeSalesRegion":   platformRoles,
+	// sales mutations
+	"purchasePack":       salesRoles,
+	"grantPack":          salesRoles,
+	"consumeResource":    salesRoles,
+	"subscribePack":      salesRoles,
+	"cancelSubscription": salesRoles,
+	"pauseSubscription":  salesRoles,
+	"resumeSubscription": salesRoles,
+	"refundOrder":        adminRoles,
+	// owner and commission mutations, owner states are not catalog data
+	"createOwner":           platformRoles,
+	"updateOwner":           adminRoles,
+	"changeOwnerState":      adminRoles,
+	"transferPackOwnership": adminRoles,
+	"addCommissionRule":     adminRoles,
+	"deleteCommissionRule":  adminRoles,
+	"settleOwner":           platformRoles,
+	"createApiKey":          platformRoles,
+	"revokeApiKey":          platformRoles,
+	// webhooks of partners
+	"createWebhook":    adminRoles,
+	"deleteWebhook":    adminRoles,
+	"redeliverWebhook": adminRoles,
+}
+
+// routePermissions contains the roles allowed to use every http route
+// that is not graphql and is not open to any caller.
+var routePermissions = map[string][]string{
+	"importPacks": catalogRoles,
+	"exportPacks": anyRole,
+	// sales reports as csv, callers only see the sales of their tenant
+	"salesReportCSV": anyRole,
+	// rest api under /api/v1, a change of a pack also needs pricingRoles
+	// for its price, adminRoles for its owner and catalogRoles for the
+	// other data
+	"listPacks":            anyRole,
+	"getPack":              anyRole,
+	"getPackResources":     anyRole,
+	"getPackStock":         anyRole,
+	"createPack":           catalogRoles,
+	"replacePack":          bulkRoles,
+	"patchPack":            bulkRoles,
+	"replacePackResources": catalogRoles,
+	"deletePackResources":  catalogRoles,
+	"deletePack":           adminRoles,
+	"movePackStock":        adminRoles,
+}
+
+// rpcPermissions contains the roles allowed to use every method of the
+// grpc pack service, a method that is not here cannot be used.
+var rpcPermissions = map[string][]string{
+	"GetPack":            anyRole,
+	"GetPackByCode":      anyRole,
+	"GetPackByProductID": anyRole,
+	"SearchPacks":        anyRole,
+	"ListPacks":          anyRole,
+	"ExportPacks":        anyRole,
+	"CreatePack":         catalogRoles,
+	"ChangePackState":    catalogRoles,
+	"ReplaceResources":   catalogRoles,
+	"DeleteResources":    catalogRoles,
+	"SetTranslation":     catalogRoles,
+	"RemoveTranslation":  catalogRoles,
+	"ChangePackPrice":    pricingRoles,
+	"MoveStock":          adminRoles,
+	"DeletePack":         adminRoles,
+	"TransferOwnership":  adminRoles,
+	// an update with a price also needs pricingRoles, with an owner
+	// adminRoles and with other data catalogRoles
+	"UpdatePack": bulkRoles,
+}
+
+// authorize returns service.ErrForbidden if no role of the caller is
+// allowed to use the given root field.
+func authorize(identity *model.Identity, field string) error {
+	return allow(identity, field, permissions[field])
+}
+
+// authorizeRoute returns service.ErrForbidden if no role of the caller is
+// allowed to use the given http route.
+func authorizeRoute(identity *model.Identity, route string) error {
+	return allow(identity, route, routePermissions[route])
+}
+
+// allow returns service.ErrForbidden if the caller has none of the roles.
+func allow(identity *model.Identity, name string, roles []string) error {
+	for _, role := range roles {
+		if identity.HasRole(role) {
+			return nil
+		}
+	}
+	return service.ErrForbidden.WithField(name)
+}
+
+// authorized wraps the resolvers of every field of the given root object,
+// and the subscribers of the subscription fields, so they are only called
+// when the caller is allowed to.
+func authorized(root *graphql.Object) *graphql.Object {
+	for name, field := range root.Fields() {
+		field.Resolve = authorizedResolve(name, field.Resolve)
+		if field.Subscribe != nil {
+			field.Subscribe = authorizedResolve(name, field.Subscribe)
+		}
+	}
+	return root
+}
+
+// authorizedResolve returns a resolver that checks the permissions of the
+// field before calling resolve.
+func authorizedResolve(field string, resolve graphql.FieldResolveFn) graphql.FieldResolveFn {
+	return func(params graphql.ResolveParams) (interface{}, error) {
+		err := authorize(identityFrom(params), field)
+		if err != nil {
+			log.Warnf("%s is not allowed to use %s", subjectOf(identityFrom(params)), field)
+			return nil, err
+		}
+		return resolve(params)
+	}
+}
+
+// subjectOf returns the subject of the caller for logging.
+func subjectOf(identity *model.Identity) string {
+	if identity == nil {
+		return "anonymous"
+	}
+	return identity.Subject
+}
@@ -0,0 +1,221 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/fernandoocampo/pack/auth"
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/service"
+	"github.com/graphql-go/graphql"
+)
+
+// roles granted to the callers of the matrix.
+var matrixRoles = []string{model.RoleViewer, model.RoleCatalogEditor, model.RolePricingManager,
+	model.RoleSeller, model.RoleAdmin, model.RolePlatformAdmin}
+
+// TestPermissionMatrix tests every role against every root query and mutation
+func TestPermissionMatrix(t *testing.T) {
+	v, ce, pm, s, a, pa := true, true, true, true, true, true
+	no := false
+	// allowed by role: viewer, catalog editor, pricing manager, seller, admin, platform admin
+	tests := map[string][6]bool{
+		"byCode":                {v, ce, pm, s, a, pa},
+		"byID":                  {v, ce, pm, s, a, pa},
+		"byKeys":                {v, ce, pm, s, a, pa},
+		"byProductID":           {v, ce, pm, s, a, pa},
+		"packs":                 {v, ce, pm, s, a, pa},
+		"idByCode":              {v, ce, pm, s, a, pa},
+		"balances":              {v, ce, pm, s, a, pa},
+		"entitlements":          {v, ce, pm, s, a, pa},
+		"packSubscriptions":     {v, ce, pm, s, a, pa},
+		"order":                 {v, ce, pm, s, a, pa},
+		"orders":                {v, ce, pm, s, a, pa},
+		"provisionStatus":       {v, ce, pm, s, a, pa},
+		"commissionRules":       {v, ce, pm, s, a, pa},
+		"settlement":            {v, ce, pm, s, a, pa},
+		"settlementStatement":   {v, ce, pm, s, a, pa},
+		"settlements":           {v, ce, pm, s, a, pa},
+		"owner":                 {v, ce, pm, s, a, pa},
+		"owners":                {v, ce, pm, s, a, pa},
+		"packsByOwner":          {v, ce, pm, s, a, pa},
+		"searchPacks":           {v, ce, pm, s, a, pa},
+		"apiKeys":               {no, no, no, no, no, pa},
+		"bulkJob":               {no, ce, pm, no, a, pa},
+		"bulkJobs":              {no, ce, pm, no, a, pa},
+		"packAudit":             {no, ce, pm, no, a, pa},
+		"packTemplate":          {v, ce, pm, s, a, pa},
+		"packTemplates":         {v, ce, pm, s, a, pa},
+		"eligiblePacks":         {v, ce, pm, s, a, pa},
+		"checkEligibility":      {v, ce, pm, s, a, pa},
+		"salesChannels":         {v, ce, pm, s, a, pa},
+		"salesRegions":          {v, ce, pm, s, a, pa},
+		"comparePacks":          {v, ce, pm, s, a, pa},
+		"similarPacks":          {v, ce, pm, s, a, pa},
+		"catalogStats":          {v, ce, pm, s, a, pa},
+		"salesReport":           {v, ce, pm, s, a, pa},
+		"webhooks":              {no, no, no, no, a, pa},
+		"webhookDeliveries":     {no, no, no, no, a, pa},
+		"packChanged":           {v, ce, pm, s, a, pa},
+		"stockChanged":          {v, ce, pm, s, a, pa},
+		"packsChanged":          {v, ce, pm, s, a, pa},
+		"create":                {no, ce, no, no, a, pa},
+		"changeCurrency":        {no, ce, no, no, a, pa},
+		"changeDescription":     {no, ce, no, no, a, pa},
+		"changeImageUrl":        {no, ce, no, no, a, pa},
+		"changeKeywords":        {no, ce, no, no, a, pa},
+		"changeMno":             {no, ce, no, no, a, pa},
+		"changeName":            {no, ce, no, no, a, pa},
+		"changePackCode":        {no, ce, no, no, a, pa},
+		"changeProductID":       {no, ce, no, no, a, pa},
+		"changeState":           {no, ce, no, no, a, pa},
+		"changeType":            {no, ce, no, no, a, pa},
+		"changeValidity":        {no, ce, no, no, a, pa},
+		"replaceResources":      {no, ce, no, no, a, pa},
+		"deletePackResources":   {no, ce, no, no, a, pa},
+		"setPackTranslation":    {no, ce, no, no, a, pa},
+		"removePackTranslation": {no, ce, no, no, a, pa},
+		"setEligibilityRules":   {no, ce, no, no, a, pa},
+		"changePrice":           {no, no, pm, no, no, no},
+		"delete":                {no, no, no, no, a, pa},
+		"moveStock":             {no, no, no, no, a, pa},
+		"bulkUpdatePacks":       {no, ce, pm, no, a, pa},
+		"bulkChangeState":       {no, ce, no, no, a, pa},
+		"purchasePack":          {no, no, no, s, a, pa},
+		"grantPack":             {no, no, no, s, a, pa},
+		"consumeResource":       {no, no, no, s, a, pa},
+		"subscribePack":         {no, no, no, s, a, pa},
+		"cancelSubscription":    {no, no, no, s, a, pa},
+		"pauseSubscription":     {no, no, no, s, a, pa},
+		"resumeSubscription":    {no, no, no, s, a, pa},
+		"refundOrder":           {no, no, no, no, a, pa},
+		"createOwner":           {no, no, no, no, no, pa},
+		"updateOwner":           {no, no, no, no, a, pa},
+		"changeOwnerState":      {no, no, no, no, a, pa},
+		"transferPackOwnership": {no, no, no, no, a, pa},
+		"addCommissionRule":     {no, no, no, no, a, pa},
+		"deleteCommissionRule":  {no, no, no, no, a, pa},
+		"settleOwner":           {no, no, no, no, no, pa},
+		"createApiKey":          {no, no, no, no, no, pa},
+		"revokeApiKey":          {no, no, no, no, no, pa},
+		// clones and pack templates
+		"clonePack":              {no, ce, no, no, a, pa},
+		"createPackTemplate":     {no, ce, no, no, a, pa},
+		"deletePackTemplate":     {no, ce, no, no, a, pa},
+		"createPackFromTemplate": {no, ce, no, no, a, pa},
+		// bundles
+		"createBundle":           {no, ce, no, no, a, pa},
+		"changeBundleComponents": {no, ce, no, no, a, pa},
+		"setPackAvailability":    {no, ce, no, no, a, pa},
+		"addSalesChannel":        {no, no, no, no, no, pa},
+		"removeSalesChannel":     {no, no, no, no, no, pa},
+		"addSalesRegion":         {no, no, no, no, no, pa},
+		"removeSalesRegion":      {no, no, no, no, no, pa},
+		// webhooks
+		"createWebhook":    {no, no, no, no, a, pa},
+		"deleteWebhook":    {no, no, no, no, a, pa},
+		"redeliverWebhook": {no, no, no, no, a, pa},
+	}
+	for _, root := range []*graphql.Object{rootQuery, packMutation, rootSubscription} {
+		for field := range root.Fields() {
+			allowed, ok := tests[field]
+			if !ok {
+				t.Errorf("Expected %s.%s to be in the permission matrix", root.Name(), field)
+				continue
+			}
+			for i, role := range matrixRoles {
+				err := authorize(&model.Identity{Subject: "tester", Roles: []string{role}}, field)
+				if allowed[i] && err != nil {
+					t.Errorf("Expected %s to be allowed to use %s but it was rejected", role, field)
+				}
+				if !allowed[i] && err == nil {
+					t.Errorf("Expected %s to be rejected to use %s but it was allowed", role, field)
+				}
+			}
+		}
+	}
+}
+
+// TestPermissionsCoverSchema tests every root field has its permissions
+func TestPermissionsCoverSchema(t *testing.T) {
+	fields := map[string]bool{}
+	for _, root := range []*graphql.Object{rootQuery, packMutation, rootSubscription} {
+		for field := range root.Fields() {
+			fields[field] = true
+			if _, ok := permissions[field]; !ok {
+				t.Errorf("Expected permissions for %s.%s but there were none", root.Name(), field)
+			}
+		}
+	}
+	for field := range permissions {
+		if !fields[field] {
+			t.Errorf("Expected %s to be a root field but it is not in the schema", field)
+		}
+	}
+}
+
+// TestAuthorizeWithoutIdentity tests callers without identity are rejected
+func TestAuthorizeWithoutIdentity(t *testing.T) {
+	if err := authorize(nil, "byCode"); err == nil {
+		t.Fatalf("Expected a caller without identity to be rejected")
+	}
+	if err := authorize(&model.Identity{Roles: []string{model.RoleAdmin}}, "unknownField"); err == nil {
+		t.Fatalf("Expected a field without permissions to be rejected")
+	}
+}
+
+// TestForbiddenGraphqlError tests a rejected mutation is answered with a
+// structured graphql error
+func TestForbiddenGraphqlError(t *testing.T) {
+	// GIVEN a viewer
+	viewer := &model.Identity{Subject: "viewer", Roles: []string{model.RoleViewer}}
+	ctx := auth.NewContext(context.Background(), viewer)
+
+	// WHEN the viewer tries to delete a pack
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `mutation { delete(id:"5a1221a8cc7c76da03df50fc") { success } }`,
+		Context:       ctx,
+	})
+
+	// THEN the call is rejected with the details of the rejection
+	if len(result.Errors) != 1 {
+		t.Fatalf("Expected 1 error but got %d: %v", len(result.Errors), result.Errors)
+	}
+	graphqlerr := result.Errors[0]
+	if graphqlerr.Message != service.ErrForbidden.Message {
+		t.Fatalf("Expected message %s but got %s", service.ErrForbidden.Message, graphqlerr.Message)
+	}
+	if graphqlerr.Extensions["code"] != "57" || graphqlerr.Extensions["category"] != "FORBIDDEN" {
+		t.Fatalf("Expected forbidden extensions but got %v", graphqlerr.Extensions)
+	}
+	if graphqlerr.Extensions["field"] != "delete" {
+		t.Fatalf("Expected the rejected field in the extensions but got %v", graphqlerr.Extensions["field"])
+	}
+}
+
+// TestRoutePermissions tests the roles allowed to use the http routes
+func TestRoutePermissions(t *testing.T) {
+	editor := &model.Identity{Roles: []string{model.RoleCatalogEditor}}
+	seller := &model.Identity{Roles: []string{model.RoleSeller}}
+	if err := authorizeRoute(editor, "importPacks"); err != nil {
+		t.Fatalf("Expected a catalog editor to import packs but got %s", err)
+	}
+	if err := authorizeRoute(seller, "importPacks"); !errors.Is(err, service.ErrForbidden) {
+		t.Fatalf("Expected a seller to be rejected to import packs but got %v", err)
+	}
+	if err := authorizeRoute(editor, "unknownRoute"); err == nil {
+		t.Fatalf("Expected a route without permissions to be rejected")
+	}
+}
+
+// TestRestRoutesHavePermissions tests every route of the rest api that is
+// not public has its roles
+func TestRestRoutesHavePermissions(t *testing.T) {
+	for _, route := range restRoutes() {
+		if _, ok := routePermissions[route.name]; ok == route.public {
+			t.Errorf("Expected permissions only for the private route %s", route.name)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/fernandoocampo/pack/model"
+	"github.com/gorilla/mux"
+)
+
+// restPrefix is the path of the version 1 of the rest api.
+const restPrefix = "/api/v1"
+
+// content types of the rest api
+const (
+	jsonContent       = "application/json"
+	mergePatchContent = "application/merge-patch+json"
+)
+
+// restRoute contains a route of the rest api and its description in the
+// openapi document.
+type restRoute struct {
+	method   string           // http method
+	path     string           // path under restPrefix. e.g. /packs/{id}
+	name     string           // name of the route, its openapi operation id and its key in routePermissions
+	summary  string           // description of the operation
+	handler  http.HandlerFunc // handler of the route
+	public   bool             // true if callers are not authenticated
+	params   []restParam      // query and header parameters
+	body     interface{}      // value of the type of the request body, nil for none
+	bodyType string           // content type of the request body, json by default
+	result   interface{}      // value of the type of the response body, nil for none
+	status   int              // http status of the success
+	errors   []int            // http status of the failures
+}
+
+// restParam contains a query or header parameter of a rest route.
+type restParam struct {
+	name        string
+	in          string // query or header
+	kind        string // openapi type. e.g. integer, string
+	description string
+}
+
+// packList contains a page of the packs that match a filter.
+type packList struct {
+	Packs []model.Pack `json:"packs"`
+	Skip  int          `json:"skip"`
+	Limit int          `json:"limit"`
+}
+
+// packStock contains the stock of a pack.
+type packStock struct {
+	Stock int `json:"stock"`
+}
+
+// stockMove contains the units added to the stock of a pack, negative to
+// reduce it.
+type stockMove struct {
+	Amount int `json:"amount"`
+}
+
+// errorResponse contains the details of a failed request.
+type errorResponse struct {
+	Error model.ErrorDetail `json:"error"`
+}
+
+// parameters of the rest routes
+var (
+	ifMatchParam     = restParam{name: "If-Match", in: "header", kind: "string", description: "entity tag the pack must still have, the change fails with 412 otherwise"}
+	ifNoneMatchParam = restParam{name: "If-None-Match", in: "header", kind: "string", description: "entity tag the caller has, 304 is answered if it did not change"}
+)
+
+// restRoutes returns the routes of the rest api, the packs are the ones
+// of the tenant of the caller as in the graphql api.
+func restRoutes() []restRoute {
+	return []restRoute{
+		{method: http.MethodGet, path: "/packs", name: "listPacks", handler: ListPacks,
+			summary: "packs that match the filter sorted by pack code",
+			params:  listPacksParams(), result: packList{}, status: http.StatusOK,
+			errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden}},
+		{method: http.MethodPost, path: "/packs", name: "createPack", handler: CreatePack,
+			summary: "create a pack, it is active and has no stock",
+			body:    model.Pack{}, result: model.Pack{}, status: http.StatusCreated,
+			errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusConflict}},
+		{method: http.MethodGet, path: "/packs/{id}", name: "getPack", handler: GetPack,
+			summary: "pack with the given id",
+			params:  []restParam{ifNoneMatchParam}, result: model.Pack{}, status: http.StatusOK,
+			errors: []int{http.StatusNotModified, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound}},
+		{method: http.MethodPut, path: "/packs/{id}", name: "replacePack", handler: ReplacePack,
+			summary: "replace the catalog data of the pack, its id, state, stock, rules and availability are kept",
+			params:  []restParam{ifMatchParam}, body: model.Pack{}, result: model.Pack{}, status: http.StatusOK,
+			errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound,
+				http.StatusConflict, http.StatusPreconditionFailed}},
+		{method: http.MethodPatch, path: "/packs/{id}", name: "patchPack", handler: PatchPack,
+			summary: "change the catalog data of the pack with a json merge patch",
+			params:  []restParam{ifMatchParam}, body: model.Pack{}, bodyType: mergePatchContent, result: model.Pack{}, status: http.StatusOK,
+			errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound,
+				http.StatusConflict, http.StatusPreconditionFailed}},
+		{method: http.MethodDelete, path: "/packs/{id}", name: "deletePack", handler: DeletePack,
+			summary: "remove the pack, it cannot be a component of an active bundle",
+			params:  []restParam{ifMatchParam}, status: http.StatusNoContent,
+			errors: []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict,
+				http.StatusPreconditionFailed}},
+		{method: http.MethodGet, path: "/packs/{id}/resources", name: "getPackResources", handler: GetPackResources,
+			summary: "resources of the pack",
+			params:  []restParam{ifNoneMatchParam}, result: []model.Resource{}, status: http.StatusOK,
+			errors: []int{http.StatusNotModified, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound}},
+		{method: http.MethodPut, path: "/packs/{id}/resources", name: "replacePackResources", handler: ReplacePackResources,
+			summary: "replace the resources of a pack that is not a bundle",
+			params:  []restParam{ifMatchParam}, body: []model.Resource{}, result: []model.Resource{}, status: http.StatusOK,
+			errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound,
+				http.StatusConflict, http.StatusPreconditionFailed}},
+		{method: http.MethodDelete, path: "/packs/{id}/resources", name: "deletePackResources", handler: DeletePackResources,
+			summary: "remove the resources of a pack that is not a bundle",
+			params:  []restParam{ifMatchParam}, status: http.StatusNoContent,
+			errors: []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict,
+				http.StatusPreconditionFailed}},
+		{method: http.MethodGet, path: "/packs/{id}/stock", name: "getPackStock", handler: GetPackStock,
+			summary: "stock of the pack",
+			params:  []restParam{ifNoneMatchParam}, result: packStock{}, status: http.StatusOK,
+			errors: []int{http.StatusNotModified, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound}},
+		{method: http.MethodPost, path: "/packs/{id}/stock", name: "movePackStock", handler: MovePackStock,
+			summary: "add units to the stock of the pack, or reduce it with a negative amount",
+			params:  []restParam{ifMatchParam}, body: stockMove{}, result: packStock{}, status: http.StatusOK,
+			errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound,
+				http.StatusPreconditionFailed}},
+		{method: http.MethodGet, path: "/openapi.json", name: "openAPI", handler: OpenAPI, public: true,
+			summary: "openapi 3 document of this api", status: http.StatusOK},
+	}
+}
+
+// listPacksParams returns the parameters of the pack list, the filter of
+// the packs query and the page.
+func listPacksParams() []restParam {
+	params := []restParam{}
+	for _, name := range packFilterParams {
+		params = append(params, restParam{name: name, in: "query", kind: "integer", description: "filter of the packs"})
+	}
+	for _, name := range packFilterTexts {
+		params = append(params, restParam{name: name, in: "query", kind: "string", description: "filter of the packs"})
+	}
+	return append(params,
+		restParam{name: "skip", in: "query", kind: "integer", description: "packs skipped, 0 by default"},
+		restParam{name: "limit", in: "query", kind: "integer", description: "most packs returned, 50 by default and 500 at most"})
+}
+
+// addRestRoutes registers the routes of the rest api in the router.
+func addRestRoutes(router *mux.Router) {
+	for _, route := range restRoutes() {
+		handler := localize(route.handler)
+		if !route.public {
+			handler = authenticate(handler)
+		}
+		router.Methods(route.method).
+			Path(restPrefix + route.path).
+			Name(route.name).
+			HandlerFunc(handler)
+	}
+}
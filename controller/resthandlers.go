@@ -0,0 +1,365 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/fernandoocampo/pack/auth"
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/service"
+	"github.com/gorilla/mux"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// maxRestBody is the size of the biggest request body of the rest api.
+const maxRestBody = 1 << 20
+
+// defaultRestLimit is the number of packs listed when no limit is given.
+const defaultRestLimit = 50
+
+// ListPacks writes the packs of the tenant of the caller that match the
+// filter of the parameters. Parameters:
+// mnoid, ownerid, typeid, state, minprice, maxprice, channel, region: the
+// filter of the packs query.
+// skip, limit: the page of packs, 50 packs by default.
+func ListPacks(w http.ResponseWriter, r *http.Request) {
+	if !authorizedRoute(w, r, "listPacks") {
+		return
+	}
+	query := r.URL.Query()
+	filter, err := packFilterFromQuery(query)
+	if err != nil {
+		respondWithCatalogError(w, r, err)
+		return
+	}
+	page := map[string]int{"skip": 0, "limit": defaultRestLimit}
+	for name := range page {
+		if value := query.Get(name); value != "" {
+			page[name], err = strconv.Atoi(value)
+			if err != nil {
+				respondWithCatalogError(w, r, service.ErrPackFilterInvalid.WithField(name))
+				return
+			}
+		}
+	}
+	packs, err := packService.WithTenant(tenantFrom(r.Context())).List(filter, page["skip"], page["limit"])
+	if err != nil {
+		respondWithCatalogError(w, r, err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, packList{Packs: packs, Skip: page["skip"], Limit: page["limit"]})
+}
+
+// CreatePack creates the pack of the request body and writes it with its
+// location and entity tag.
+func CreatePack(w http.ResponseWriter, r *http.Request) {
+	if !authorizedRoute(w, r, "createPack") {
+		return
+	}
+	pack := new(model.Pack)
+	if !readBody(w, r, pack) {
+		return
+	}
+	err := packService.WithTenant(tenantFrom(r.Context())).Create(pack)
+	if err != nil {
+		respondWithCatalogError(w, r, err)
+		return
+	}
+	w.Header().Set("Location", restPrefix+"/packs/"+pack.ID.Hex())
+	w.Header().Set("ETag", pack.ETag())
+	respondWithJSON(w, http.StatusCreated, pack)
+}
+
+// GetPack writes the pack with the id of the path.
+func GetPack(w http.ResponseWriter, r *http.Request) {
+	pack, ok := currentPack(w, r, "getPack")
+	if ok {
+		respondWithPackPart(w, r, pack, http.StatusOK, wholePack)
+	}
+}
+
+// ReplacePack replaces the catalog data of the pack with the one of the
+// request body. The price can only be changed by pricing managers, the
+// owner by admins and the other data by catalog roles.
+func ReplacePack(w http.ResponseWriter, r *http.Request) {
+	pack, ok := currentPack(w, r, "replacePack")
+	if !ok {
+		return
+	}
+	newpack := new(model.Pack)
+	if !readBody(w, r, newpack) {
+		return
+	}
+	updatePack(w, r, pack, newpack)
+}
+
+// PatchPack changes the catalog data of the pack with the json merge
+// patch of the request body, with the permissions of ReplacePack.
+func PatchPack(w http.ResponseWriter, r *http.Request) {
+	pack, ok := currentPack(w, r, "patchPack")
+	if !ok {
+		return
+	}
+	patch, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRestBody))
+	if err != nil {
+		respondWithCatalogError(w, r, service.ErrPackBodyInvalid.Wrap(err))
+		return
+	}
+	newpack, err := model.MergePack(pack, patch)
+	var mergeerr *model.PackMergeError
+	if errors.As(err, &mergeerr) {
+		respondWithCatalogError(w, r, service.ErrPackFieldReadOnly.WithField(mergeerr.Field))
+		return
+	}
+	if err != nil {
+		respondWithCatalogError(w, r, service.ErrPackBodyInvalid.Wrap(err))
+		return
+	}
+	updatePack(w, r, pack, newpack)
+}
+
+// updatePack replaces the pack with the new one if it is still at the
+// version read and the caller can make the change.
+func updatePack(w http.ResponseWriter, r *http.Request, pack *model.Pack, newpack *model.Pack) {
+	err := authorizePackChange(auth.FromContext(r.Context()), pack, newpack)
+	if err != nil {
+		respondWithCatalogError(w, r, err)
+		return
+	}
+	err = versionedPackService(r, pack).Update(pack.ID.Hex(), newpack)
+	if err != nil {
+		respondWithCatalogError(w, r, err)
+		return
+	}
+	respondWithChangedPack(w, r, pack.ID.Hex(), http.StatusOK, wholePack)
+}
+
+// DeletePack removes the pack.
+func DeletePack(w http.ResponseWriter, r *http.Request) {
+	pack, ok := currentPack(w, r, "deletePack")
+	if !ok {
+		return
+	}
+	err := versionedPackService(r, pack).Delete(pack.ID.Hex())
+	if err != nil {
+		respondWithCatalogError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetPackResources writes the resources of the pack.
+func GetPackResources(w http.ResponseWriter, r *http.Request) {
+	pack, ok := currentPack(w, r, "getPackResources")
+	if ok {
+		respondWithPackPart(w, r, pack, http.StatusOK, packResources)
+	}
+}
+
+// ReplacePackResources replaces the resources of the pack with the ones
+// of the request body.
+func ReplacePackResources(w http.ResponseWriter, r *http.Request) {
+	pack, ok := currentPack(w, r, "replacePackResources")
+	if !ok {
+		return
+	}
+	resources := []model.Resource{}
+	if !readBody(w, r, &resources) {
+		return
+	}
+	err := versionedPackService(r, pack).UpdateResources(pack.ID.Hex(), resources)
+	if err != nil {
+		respondWithCatalogError(w, r, err)
+		return
+	}
+	respondWithChangedPack(w, r, pack.ID.Hex(), http.StatusOK, packResources)
+}
+
+// DeletePackResources removes the resources of the pack.
+func DeletePackResources(w http.ResponseWriter, r *http.Request) {
+	pack, ok := currentPack(w, r, "deletePackResources")
+	if !ok {
+		return
+	}
+	err := versionedPackService(r, pack).DeleteResources(pack.ID.Hex())
+	if err != nil {
+		respondWithCatalogError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetPackStock writes the stock of the pack.
+func GetPackStock(w http.ResponseWriter, r *http.Request) {
+	pack, ok := currentPack(w, r, "getPackStock")
+	if ok {
+		respondWithPackPart(w, r, pack, http.StatusOK, packStockOf)
+	}
+}
+
+// MovePackStock adds the amount of the request body to the stock of the
+// pack and writes the new stock.
+func MovePackStock(w http.ResponseWriter, r *http.Request) {
+	pack, ok := currentPack(w, r, "movePackStock")
+	if !ok {
+		return
+	}
+	move := stockMove{}
+	if !readBody(w, r, &move) {
+		return
+	}
+	err := versionedPackService(r, pack).MoveStock(pack.ID.Hex(), move.Amount)
+	if err != nil {
+		respondWithCatalogError(w, r, err)
+		return
+	}
+	respondWithChangedPack(w, r, pack.ID.Hex(), http.StatusOK, packStockOf)
+}
+
+// parts of a pack written by the rest api
+var (
+	wholePack     = func(pack *model.Pack) interface{} { return pack }
+	packStockOf   = func(pack *model.Pack) interface{} { return packStock{Stock: pack.Stock} }
+	packResources = func(pack *model.Pack) interface{} {
+		if pack.Resources == nil {
+			return []model.Resource{}
+		}
+		return pack.Resources
+	}
+)
+
+// authorizedRoute writes service.ErrForbidden and returns false if the
+// caller cannot use the given route.
+func authorizedRoute(w http.ResponseWriter, r *http.Request, route string) bool {
+	identity := auth.FromContext(r.Context())
+	err := authorizeRoute(identity, route)
+	if err != nil {
+		log.Warnf("%s is not allowed to use %s", subjectOf(identity), route)
+		respondWithCatalogError(w, r, err)
+		return false
+	}
+	return true
+}
+
+// currentPack returns the pack of the id of the path if the caller can
+// use the route and see the pack. The pack must have the entity tag of
+// the If-Match header, if there is one.
+func currentPack(w http.ResponseWriter, r *http.Request, route string) (*model.Pack, bool) {
+	if !authorizedRoute(w, r, route) {
+		return nil, false
+	}
+	id := mux.Vars(r)["id"]
+	if !bson.IsObjectIdHex(id) {
+		respondWithCatalogError(w, r, service.ErrPackNotFound.WithField("id"))
+		return nil, false
+	}
+	pack, err := packService.WithTenant(tenantFrom(r.Context())).FindByID(id)
+	if err != nil {
+		respondWithCatalogError(w, r, err)
+		return nil, false
+	}
+	if pack == nil {
+		respondWithCatalogError(w, r, service.ErrPackNotFound.WithField("id"))
+		return nil, false
+	}
+	if ifmatch := r.Header.Get("If-Match"); ifmatch != "" && !matchesETag(ifmatch, pack.ETag(), false) {
+		w.Header().Set("ETag", pack.ETag())
+		respondWithCatalogError(w, r, service.ErrPackModified)
+		return nil, false
+	}
+	return pack, true
+}
+
+// versionedPackService returns the pack service of the tenant of the
+// caller whose changes only apply to the pack at the version read, so a
+// change made meanwhile is not lost.
+func versionedPackService(r *http.Request, pack *model.Pack) service.IPackService {
+	return packService.WithTenant(tenantFrom(r.Context())).WithVersion(pack.Version)
+}
+
+// authorizePackChange returns service.ErrForbidden if the caller cannot
+// make every change of the pack: prices are changed by pricing managers,
+// owners by admins and the other catalog data by catalog roles.
+func authorizePackChange(identity *model.Identity, pack *model.Pack, newpack *model.Pack) error {
+	for _, field := range pack.ChangedFields(newpack) {
+		roles := catalogRoles
+		switch {
+		case field == "price":
+			roles = pricingRoles
+		case field == "ownerid":
+			roles = adminRoles
+		case field == "translations" && len(newpack.Translations) == 0:
+			// the translations are kept
+			continue
+		case field == "resources" && pack.IsBundle():
+			// the resources of a bundle come from its components
+			continue
+		}
+		err := allow(identity, field, roles)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readBody decodes the json request body into value, it writes
+// service.ErrPackBodyInvalid and returns false if it cannot.
+func readBody(w http.ResponseWriter, r *http.Request, value interface{}) bool {
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRestBody)).Decode(value)
+	if err != nil {
+		respondWithCatalogError(w, r, service.ErrPackBodyInvalid.Wrap(err))
+		return false
+	}
+	return true
+}
+
+// respondWithPackPart writes the part of the pack with its entity tag, or
+// only the status 304 if the caller has the entity tag of the
+// If-None-Match header.
+func respondWithPackPart(w http.ResponseWriter, r *http.Request, pack *model.Pack, status int, part func(pack *model.Pack) interface{}) {
+	w.Header().Set("ETag", pack.ETag())
+	if ifnonematch := r.Header.Get("If-None-Match"); ifnonematch != "" && matchesETag(ifnonematch, pack.ETag(), true) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	respondWithJSON(w, status, part(pack))
+}
+
+// respondWithChangedPack reads the changed pack again and writes the part
+// of it with its new entity tag.
+func respondWithChangedPack(w http.ResponseWriter, r *http.Request, id string, status int, part func(pack *model.Pack) interface{}) {
+	pack, err := packService.WithTenant(tenantFrom(r.Context())).FindByID(id)
+	if err != nil {
+		respondWithCatalogError(w, r, err)
+		return
+	}
+	if pack == nil {
+		// the pack is not visible to the caller anymore. e.g. it was
+		// given to another owner
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.Header().Set("ETag", pack.ETag())
+	respondWithJSON(w, status, part(pack))
+}
+
+// matchesETag returns true if the If-Match or If-None-Match header has
+// the entity tag or is *. Weak tags only match with the weak comparison
+// of If-None-Match.
+func matchesETag(header string, etag string, weak bool) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if weak {
+			tag = strings.TrimPrefix(tag, "W/")
+		}
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,272 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/service"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// restPackService keeps the packs in memory with the methods the rest api
+// uses, a service at a version only changes packs at that version.
+type restPackService struct {
+	service.IPackService
+	packs     map[string]*model.Pack
+	version   int
+	versioned bool
+	filter    *model.PackFilter
+	removed   map[string]bool // ids of the deleted packs
+}
+
+func (s *restPackService) WithTenant(tenant *model.Tenant) service.IPackService {
+	return s
+}
+
+func (s *restPackService) WithVersion(version int) service.IPackService {
+	return &restPackService{packs: s.packs, removed: s.removed, version: version, versioned: true}
+}
+
+func (s *restPackService) FindByID(id string) (*model.Pack, error) {
+	pack, ok := s.packs[id]
+	if !ok || s.removed[id] {
+		return nil, nil
+	}
+	copied := *pack
+	return &copied, nil
+}
+
+// change applies the change to the pack if it is at the version.
+func (s *restPackService) change(id string, change func(pack *model.Pack)) error {
+	pack, ok := s.packs[id]
+	if !ok || s.removed[id] {
+		return service.ErrPackNotFound
+	}
+	if s.versioned && pack.Version != s.version {
+		return service.ErrPackModified
+	}
+	change(pack)
+	pack.Version++
+	return nil
+}
+
+func (s *restPackService) Update(id string, packdata *model.Pack) error {
+	return s.change(id, func(pack *model.Pack) {
+		pack.Name = packdata.Name
+		pack.Price = packdata.Price
+		pack.Ownerid = packdata.Ownerid
+	})
+}
+
+func (s *restPackService) MoveStock(id string, amount int) error {
+	return s.change(id, func(pack *model.Pack) { pack.Stock += amount })
+}
+
+func (s *restPackService) Delete(id string) error {
+	return s.change(id, func(pack *model.Pack) { s.removed[id] = true })
+}
+
+func (s *restPackService) List(filter *model.PackFilter, skip int, limit int) ([]model.Pack, error) {
+	s.filter = filter
+	packs := []model.Pack{}
+	for _, pack := range s.packs {
+		packs = append(packs, *pack)
+	}
+	return packs, nil
+}
+
+// newRestTest returns a server of the router with a pack at version 3 and
+// the tokens of a viewer, an editor, a pricing manager and an admin.
+func newRestTest(t *testing.T) (*httptest.Server, *restPackService, *model.Pack) {
+	pack := &model.Pack{ID: bson.NewObjectId(), Packcode: "0008", Name: "Whatsapp", Price: 2500, Stock: 10, Version: 3}
+	packs := &restPackService{packs: map[string]*model.Pack{pack.ID.Hex(): pack}, removed: map[string]bool{}}
+	oldservice, oldauthenticator := packService, authenticator
+	t.Cleanup(func() {
+		SetService(oldservice)
+		SetAuthenticator(oldauthenticator)
+	})
+	SetService(packs)
+	SetAuthenticator(tokenAuthenticator{
+		"Bearer viewer":  {Subject: "viewer", Roles: []string{model.RoleViewer}},
+		"Bearer editor":  {Subject: "editor", Roles: []string{model.RoleCatalogEditor}},
+		"Bearer pricing": {Subject: "pricing", Roles: []string{model.RolePricingManager}},
+		"Bearer admin":   {Subject: "admin", Roles: []string{model.RoleAdmin}},
+	})
+	server := httptest.NewServer(NewRouter())
+	t.Cleanup(server.Close)
+	return server, packs, pack
+}
+
+// restRequest makes the request with the token and the headers.
+func restRequest(t *testing.T, method string, url string, token string, body string, headers map[string]string) (*http.Response, map[string]interface{}) {
+	t.Helper()
+	request, _ := http.NewRequest(method, url, strings.NewReader(body))
+	request.Header.Set("Authorization", "Bearer "+token)
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("%s %s error = %v", method, url, err)
+	}
+	defer response.Body.Close()
+	result := map[string]interface{}{}
+	json.NewDecoder(response.Body).Decode(&result)
+	return response, result
+}
+
+// TestRestGetPack tests a pack is read with its entity tag and not sent
+// again while it does not change
+func TestRestGetPack(t *testing.T) {
+	server, _, pack := newRestTest(t)
+	url := server.URL + "/api/v1/packs/" + pack.ID.Hex()
+
+	response, result := restRequest(t, http.MethodGet, url, "viewer", "", nil)
+	if response.StatusCode != http.StatusOK || result["packcode"] != "0008" {
+		t.Fatalf("Expected the pack but got %d %v", response.StatusCode, result)
+	}
+	if response.Header.Get("ETag") != `"3"` {
+		t.Fatalf("Expected the tag of version 3 but got %s", response.Header.Get("ETag"))
+	}
+
+	response, _ = restRequest(t, http.MethodGet, url, "viewer", "", map[string]string{"If-None-Match": `W/"3"`})
+	if response.StatusCode != http.StatusNotModified {
+		t.Errorf("Expected 304 for the current tag but got %d", response.StatusCode)
+	}
+
+	response, result = restRequest(t, http.MethodGet, server.URL+"/api/v1/packs/unknown", "viewer", "", nil)
+	if response.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for an invalid id but got %d %v", response.StatusCode, result)
+	}
+
+	response, _ = restRequest(t, http.MethodGet, url, "nobody", "", nil)
+	if response.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for an unknown caller but got %d", response.StatusCode)
+	}
+}
+
+// TestRestChangePack tests changes need the current entity tag and the
+// roles of the changed fields
+func TestRestChangePack(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		token      string
+		body       string
+		ifMatch    string
+		wantStatus int
+		wantCode   string // code of the error, empty on success
+		wantPrice  int
+	}{
+		{name: "patch price", method: http.MethodPatch, token: "pricing", body: `{"price":3000}`, ifMatch: `"3"`, wantStatus: http.StatusOK, wantPrice: 3000},
+		{name: "patch without tag", method: http.MethodPatch, token: "pricing", body: `{"price":3000}`, wantStatus: http.StatusOK, wantPrice: 3000},
+		{name: "patch old tag", method: http.MethodPatch, token: "pricing", body: `{"price":3000}`, ifMatch: `"2"`, wantStatus: http.StatusPreconditionFailed, wantCode: service.ErrPackModified.Code, wantPrice: 2500},
+		{name: "editor patches price", method: http.MethodPatch, token: "editor", body: `{"price":3000}`, wantStatus: http.StatusForbidden, wantCode: service.ErrForbidden.Code, wantPrice: 2500},
+		{name: "editor patches owner", method: http.MethodPatch, token: "editor", body: `{"ownerid":9}`, wantStatus: http.StatusForbidden, wantCode: service.ErrForbidden.Code, wantPrice: 2500},
+		{name: "patch stock", method: http.MethodPatch, token: "editor", body: `{"stock":30}`, wantStatus: http.StatusBadRequest, wantCode: service.ErrPackFieldReadOnly.Code, wantPrice: 2500},
+		{name: "viewer patches name", method: http.MethodPatch, token: "viewer", body: `{"name":"Chat"}`, wantStatus: http.StatusForbidden, wantCode: service.ErrForbidden.Code, wantPrice: 2500},
+		{name: "replace name", method: http.MethodPut, token: "editor", body: `{"packcode":"0008","name":"Chat","price":2500,"version":1}`, ifMatch: `"3"`, wantStatus: http.StatusOK, wantPrice: 2500},
+		{name: "replace with invalid json", method: http.MethodPut, token: "editor", body: `{"name":`, wantStatus: http.StatusBadRequest, wantCode: service.ErrPackBodyInvalid.Code, wantPrice: 2500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _, pack := newRestTest(t)
+			headers := map[string]string{}
+			if tt.ifMatch != "" {
+				headers["If-Match"] = tt.ifMatch
+			}
+
+			response, result := restRequest(t, tt.method, server.URL+"/api/v1/packs/"+pack.ID.Hex(), tt.token, tt.body, headers)
+
+			if response.StatusCode != tt.wantStatus {
+				t.Fatalf("Expected status %d but got %d %v", tt.wantStatus, response.StatusCode, result)
+			}
+			if tt.wantCode != "" {
+				detail, _ := result["error"].(map[string]interface{})
+				if detail["code"] != tt.wantCode {
+					t.Errorf("Expected the error %s but got %v", tt.wantCode, result)
+				}
+			}
+			if tt.wantCode == "" && response.Header.Get("ETag") != `"4"` {
+				t.Errorf("Expected the tag of the new version but got %s", response.Header.Get("ETag"))
+			}
+			if pack.Price != tt.wantPrice {
+				t.Errorf("Expected price %d but got %d", tt.wantPrice, pack.Price)
+			}
+		})
+	}
+}
+
+// TestRestStockAndDelete tests the stock moves and the pack is removed
+func TestRestStockAndDelete(t *testing.T) {
+	server, packs, pack := newRestTest(t)
+	url := server.URL + "/api/v1/packs/" + pack.ID.Hex()
+
+	response, result := restRequest(t, http.MethodPost, url+"/stock", "admin", `{"amount":-4}`, nil)
+	if response.StatusCode != http.StatusOK || result["stock"] != float64(6) {
+		t.Fatalf("Expected the stock to be 6 but got %d %v", response.StatusCode, result)
+	}
+
+	response, _ = restRequest(t, http.MethodDelete, url, "editor", "", nil)
+	if response.StatusCode != http.StatusForbidden {
+		t.Fatalf("Expected an editor not to delete packs but got %d", response.StatusCode)
+	}
+	response, _ = restRequest(t, http.MethodDelete, url, "admin", "", map[string]string{"If-Match": `"4"`})
+	if response.StatusCode != http.StatusNoContent || !packs.removed[pack.ID.Hex()] {
+		t.Fatalf("Expected the pack to be removed but got %d", response.StatusCode)
+	}
+}
+
+// TestRestListPacks tests the filter and the page of the list
+func TestRestListPacks(t *testing.T) {
+	server, packs, _ := newRestTest(t)
+
+	response, result := restRequest(t, http.MethodGet, server.URL+"/api/v1/packs?mnoid=2&channel=web&skip=10", "viewer", "", nil)
+	if response.StatusCode != http.StatusOK || result["skip"] != float64(10) || result["limit"] != float64(defaultRestLimit) {
+		t.Fatalf("Expected the page of packs but got %d %v", response.StatusCode, result)
+	}
+	if list, _ := result["packs"].([]interface{}); len(list) != 1 {
+		t.Errorf("Expected one pack but got %v", result["packs"])
+	}
+	if packs.filter == nil || packs.filter.MnoID != 2 || packs.filter.Channel != "web" {
+		t.Errorf("Expected the filter of the query but got %+v", packs.filter)
+	}
+
+	response, _ = restRequest(t, http.MethodGet, server.URL+"/api/v1/packs?limit=many", "viewer", "", nil)
+	if response.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid limit but got %d", response.StatusCode)
+	}
+}
+
+// TestOpenAPI tests the document describes every route and is public
+func TestOpenAPI(t *testing.T) {
+	server, _, _ := newRestTest(t)
+
+	response, document := restRequest(t, http.MethodGet, server.URL+"/api/v1/openapi.json", "", "", nil)
+
+	if response.StatusCode != http.StatusOK || document["openapi"] != openAPIVersion {
+		t.Fatalf("Expected the openapi document but got %d %v", response.StatusCode, document)
+	}
+	paths, _ := document["paths"].(map[string]interface{})
+	for _, route := range restRoutes() {
+		path, _ := paths[route.path].(map[string]interface{})
+		operation, _ := path[strings.ToLower(route.method)].(map[string]interface{})
+		if operation["operationId"] != route.name {
+			t.Errorf("Expected the operation %s in %s %s but got %v", route.name, route.method, route.path, operation)
+		}
+	}
+	components, _ := document["components"].(map[string]interface{})
+	schemas, _ := components["schemas"].(map[string]interface{})
+	pack, _ := schemas["Pack"].(map[string]interface{})
+	properties, _ := pack["properties"].(map[string]interface{})
+	if _, ok := properties["version"]; !ok {
+		t.Errorf("Expected the schema of the pack but got %v", pack)
+	}
+	if _, ok := properties["Locale"]; ok {
+		t.Errorf("Expected the json names of the fields but got %v", properties)
+	}
+}
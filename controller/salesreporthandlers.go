@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/fernandoocampo/pack/auth"
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/service"
+	"github.com/graphql-go/graphql"
+)
+
+// salesReportService references the ISalesReportService
+var salesReportService service.ISalesReportService
+
+// getSalesReport implements ISalesReportService.Report, callers only see
+// the sales of the owner and the mno they act for.
+func getSalesReport(params graphql.ResolveParams) (interface{}, error) {
+	query := model.NewSalesReportQuery(params.Args)
+	if !query.Scope(tenantFrom(params.Context)) {
+		return nil, service.ErrNotOwner
+	}
+	return salesReportService.Report(query)
+}
+
+// SalesReportCSV writes a sales report as csv. Parameters:
+// from, to: the period in RFC3339.
+// bucket: day (default), week or month.
+// by: pack (default), mno or owner.
+// timezone, ownerid, mnoid, packid: the arguments of the salesReport query.
+func SalesReportCSV(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	identity := auth.FromContext(r.Context())
+	err := authorizeRoute(identity, "salesReportCSV")
+	if err != nil {
+		log.Warnf("%s is not allowed to export sales reports", subjectOf(identity))
+		respondWithCatalogError(w, r, err)
+		return
+	}
+	query, err := salesReportQueryFromURL(r.URL.Query())
+	if err != nil {
+		respondWithCatalogError(w, r, err)
+		return
+	}
+	if !query.Scope(tenantFrom(r.Context())) {
+		respondWithCatalogError(w, r, service.ErrNotOwner)
+		return
+	}
+
+	report, err := salesReportService.Report(query)
+	if err != nil {
+		respondWithCatalogError(w, r, err)
+		return
+	}
+	filename := "sales-" + report.By + "-" + report.Bucket + "-" + report.From.Format("20060102") + ".csv"
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+	w.WriteHeader(http.StatusOK)
+	err = report.WriteCSV(w)
+	if err != nil {
+		log.Errorf("writing sales report csv: %v", err)
+	}
+}
+
+// salesReportQueryFromURL returns the sales report query of the given
+// parameters.
+func salesReportQueryFromURL(values url.Values) (*model.SalesReportQuery, error) {
+	params := map[string]interface{}{"bucket": model.ReportDay, "by": model.ReportByPack}
+	for _, name := range []string{"from", "to"} {
+		date, err := time.Parse(time.RFC3339, values.Get(name))
+		if err != nil {
+			return nil, service.ErrReportPeriod.WithField(name)
+		}
+		params[name] = date
+	}
+	for _, name := range []string{"bucket", "by", "timezone", "packid"} {
+		if value := values.Get(name); value != "" {
+			params[name] = value
+		}
+	}
+	for _, name := range []string{"ownerid", "mnoid"} {
+		value := values.Get(name)
+		if value == "" {
+			continue
+		}
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, service.ErrPackFilterInvalid.WithField(name)
+		}
+		params[name] = number
+	}
+	return model.NewSalesReportQuery(params), nil
+}
+
+// SetSalesReportService sets the sales report service for this handler.
+func SetSalesReportService(service service.ISalesReportService) {
+	salesReportService = service
+}
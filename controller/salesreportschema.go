@@ -0,0 +1,133 @@
+package controller
+
+import "github.com/graphql-go/graphql"
+
+// Row of a sales report
+var salesReportRowType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "SalesReportRow",
+	Description: "The sales and refunds of a pack, mno or owner in a day, week or month",
+	Fields: graphql.Fields{
+		"period": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "start of the day, week or month in the time zone of the report.",
+		},
+		"packid": &graphql.Field{
+			Type:        graphql.String,
+			Description: "id of the sold pack, empty if the report is not by pack.",
+		},
+		"packcode": &graphql.Field{
+			Type:        graphql.String,
+			Description: "last code the pack was sold with, empty if the report is not by pack.",
+		},
+		"mnoid": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "mno of the sold packs, null if the report is not by mno.",
+		},
+		"ownerid": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "owner of the sold packs, null if the report is not by owner.",
+		},
+		"currency": &graphql.Field{
+			Type:        ccyInterface,
+			Description: "currency of the amounts.",
+		},
+		"units": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "packs sold.",
+		},
+		"revenue": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "total charged in sales.",
+		},
+		"refunds": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "orders refunded.",
+		},
+		"refundsAmount": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "total given back in refunds.",
+		},
+		"netRevenue": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "revenue minus the refunds amount.",
+		},
+		"avgPrice": &graphql.Field{
+			Type:        graphql.Float,
+			Description: "revenue divided by the units, rounded to two decimals.",
+		},
+	},
+})
+
+// Sales report
+var salesReportType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "SalesReport",
+	Description: "The sales and refunds of a period by pack, mno or owner",
+	Fields: graphql.Fields{
+		"from": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "start of the period, inclusive.",
+		},
+		"to": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "end of the period, exclusive.",
+		},
+		"bucket": &graphql.Field{
+			Type:        graphql.String,
+			Description: "day, week or month.",
+		},
+		"by": &graphql.Field{
+			Type:        graphql.String,
+			Description: "pack, mno or owner.",
+		},
+		"timezone": &graphql.Field{
+			Type:        graphql.String,
+			Description: "time zone of the days, weeks and months.",
+		},
+		"rows": &graphql.Field{
+			Type:        graphql.NewList(salesReportRowType),
+			Description: "a row per bucket, pack, mno or owner and currency, the oldest first.",
+		},
+	},
+})
+
+// salesReportQueryFields contains the queries of the sales reports.
+var salesReportQueryFields = graphql.Fields{
+	"salesReport": &graphql.Field{
+		Type:        salesReportType,
+		Description: "query the units sold, revenue, refunds and average price of a period by day, week or month",
+		Args: graphql.FieldConfigArgument{
+			"from": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.DateTime),
+			},
+			"to": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.DateTime),
+			},
+			"bucket": &graphql.ArgumentConfig{
+				Type:         graphql.String,
+				DefaultValue: "day",
+				Description:  "day, week or month, weeks start on monday",
+			},
+			"by": &graphql.ArgumentConfig{
+				Type:         graphql.String,
+				DefaultValue: "pack",
+				Description:  "pack, mno or owner",
+			},
+			"timezone": &graphql.ArgumentConfig{
+				Type:        graphql.String,
+				Description: "time zone of the buckets e.g. America/Bogota, the one of the settlements by default",
+			},
+			"ownerid": &graphql.ArgumentConfig{
+				Type: graphql.Int,
+			},
+			"mnoid": &graphql.ArgumentConfig{
+				Type: graphql.Int,
+			},
+			"packid": &graphql.ArgumentConfig{
+				Type: graphql.String,
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return getSalesReport(params)
+		},
+	},
+}
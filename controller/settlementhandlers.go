@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/service"
+	"github.com/gorilla/mux"
+	"github.com/graphql-go/graphql"
+)
+
+// settlementService references the ISettlementService
+var settlementService service.ISettlementService
+
+// getSettlementStatement implements ISettlementService.Statement.
+func getSettlementStatement(params graphql.ResolveParams) (interface{}, error) {
+	ownerid, _ := params.Args["ownerid"].(int)
+	from, _ := params.Args["from"].(time.Time)
+	to, _ := params.Args["to"].(time.Time)
+	if !actsFor(tenantFrom(params.Context), ownerid) {
+		return nil, service.ErrNotOwner
+	}
+	return settlementService.Statement(ownerid, from, to)
+}
+
+// getSettlement implements ISettlementService.GetByID.
+func getSettlement(params graphql.ResolveParams) (interface{}, error) {
+	id, _ := params.Args["id"].(string)
+	settlement, err := settlementService.GetByID(id)
+	if err != nil || settlement == nil {
+		return nil, err
+	}
+	if !actsFor(tenantFrom(params.Context), settlement.Ownerid) {
+		return nil, service.ErrNotOwner
+	}
+	return settlement, nil
+}
+
+// getSettlements implements ISettlementService.GetByOwner.
+func getSettlements(params graphql.ResolveParams) (interface{}, error) {
+	ownerid, _ := params.Args["ownerid"].(int)
+	if !actsFor(tenantFrom(params.Context), ownerid) {
+		return nil, service.ErrNotOwner
+	}
+	return settlementService.GetByOwner(ownerid)
+}
+
+// settleOwner implements ISettlementService.Settle. The id of the new
+// settlement goes in the result message.
+func settleOwner(params graphql.ResolveParams) (interface{}, error) {
+	err := requireAdmin(params)
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	ownerid, _ := params.Args["ownerid"].(int)
+	from, _ := params.Args["from"].(time.Time)
+	to, _ := params.Args["to"].(time.Time)
+
+	settlement, err := settlementService.Settle(ownerid, from, to)
+
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	result := model.NewOKResult("10")
+	result.Msg = settlement.ID.Hex()
+	return result, nil
+}
+
+// SettlementCSV writes a stored settlement as csv.
+func SettlementCSV(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	settlement, err := settlementService.GetByID(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithCatalogError(w, r, err)
+		return
+	}
+	if settlement == nil || !actsFor(tenantFrom(r.Context()), settlement.Ownerid) {
+		respondWithError(w, http.StatusNotFound, "settlement does not exist")
+		return
+	}
+	respondWithSettlementCSV(w, settlement)
+}
+
+// StatementCSV writes the statement of an owner as csv, the period is
+// given by the from and to parameters in RFC3339.
+func StatementCSV(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	ownerid, err1 := strconv.Atoi(mux.Vars(r)["ownerid"])
+	from, err2 := time.Parse(time.RFC3339, r.URL.Query().Get("from"))
+	to, err3 := time.Parse(time.RFC3339, r.URL.Query().Get("to"))
+	if err1 != nil || err2 != nil || err3 != nil {
+		respondWithCatalogError(w, r, service.ErrSettlementArgs)
+		return
+	}
+	if !actsFor(tenantFrom(r.Context()), ownerid) {
+		respondWithCatalogError(w, r, service.ErrNotOwner)
+		return
+	}
+
+	settlement, err := settlementService.Statement(ownerid, from, to)
+	if err != nil {
+		respondWithCatalogError(w, r, err)
+		return
+	}
+	respondWithSettlementCSV(w, settlement)
+}
+
+func respondWithSettlementCSV(w http.ResponseWriter, settlement *model.Settlement) {
+	filename := "settlement-" + strconv.Itoa(settlement.Ownerid) + "-" + settlement.From.Format("20060102") + ".csv"
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+	w.WriteHeader(http.StatusOK)
+	err := settlement.WriteCSV(w)
+	if err != nil {
+		log.Errorf("writing settlement csv: %v", err)
+	}
+}
+
+// SetSettlementService sets the settlement service for this handler.
+func SetSettlementService(service service.ISettlementService) {
+	settlementService = service
+}
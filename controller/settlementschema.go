@@ -0,0 +1,193 @@
+package controller
+
+import (
+	"github.com/fernandoocampo/pack/model"
+	"github.com/graphql-go/graphql"
+)
+
+// settlementLineType is a sale or a refund of a settlement.
+var settlementLineType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "SettlementLine",
+	Description: "A sale or a refund of a settlement",
+	Fields: graphql.Fields{
+		"kind": &graphql.Field{
+			Type:        graphql.String,
+			Description: "sale or refund.",
+		},
+		"orderid": &graphql.Field{
+			Type:        graphql.String,
+			Description: "id of the order sold or refunded.",
+		},
+		"msisdn": &graphql.Field{
+			Type:        graphql.String,
+			Description: "line number of the subscriber.",
+		},
+		"packcode": &graphql.Field{
+			Type:        graphql.String,
+			Description: "code of the sold pack.",
+		},
+		"amount": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "price charged, negative for refunds.",
+		},
+		"commission": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "commission earned, negative for refunds.",
+		},
+		"date": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "when the sale or the refund was made.",
+		},
+	},
+})
+
+// settlementType is the statement of the sales of a reseller in a period.
+var settlementType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Settlement",
+	Description: "The statement of the sales of a reseller in a period",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The id of the settlement, empty if it is not stored.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				settlement := settlementFromSource(p.Source)
+				if settlement == nil || settlement.ID == "" {
+					return nil, nil
+				}
+				return settlement.ID.Hex(), nil
+			},
+		},
+		"ownerid": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "company owner of the packs for resale.",
+		},
+		"from": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "start of the period, inclusive.",
+		},
+		"to": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "end of the period, exclusive.",
+		},
+		"sales": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "number of sales.",
+		},
+		"salesamount": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "total charged in sales.",
+		},
+		"commissions": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "commissions earned in sales.",
+		},
+		"refunds": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "number of refunds.",
+		},
+		"refundsamount": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "total given back in refunds.",
+		},
+		"refundedcomm": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "commissions taken back in refunds.",
+		},
+		"payable": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "commissions owed to the reseller.",
+		},
+		"lines": &graphql.Field{
+			Type:        graphql.NewList(settlementLineType),
+			Description: "sales and refunds of the period.",
+		},
+		"created": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "when the settlement was made.",
+		},
+	},
+})
+
+// settlementQueryFields contains the queries over reseller settlements.
+var settlementQueryFields = graphql.Fields{
+	"settlementStatement": &graphql.Field{
+		Type:        settlementType,
+		Description: "calculates the statement of an owner for a period without storing it",
+		Args: graphql.FieldConfigArgument{
+			"ownerid": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.Int),
+			},
+			"from": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.DateTime),
+			},
+			"to": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.DateTime),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return getSettlementStatement(params)
+		},
+	},
+	"settlement": &graphql.Field{
+		Type:        settlementType,
+		Description: "query a settlement by its id",
+		Args: graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return getSettlement(params)
+		},
+	},
+	"settlements": &graphql.Field{
+		Type:        graphql.NewList(settlementType),
+		Description: "query the settlements of an owner, lines are not included",
+		Args: graphql.FieldConfigArgument{
+			"ownerid": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.Int),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return getSettlements(params)
+		},
+	},
+}
+
+// settlementMutationFields contains the mutations over reseller settlements.
+var settlementMutationFields = graphql.Fields{
+	/*
+		settle an owner
+	*/
+	"settleOwner": &graphql.Field{
+		Type:        resultType, // the return type for this field
+		Description: "stores the statement of an owner for a period",
+		Args: graphql.FieldConfigArgument{
+			"ownerid": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.Int),
+			},
+			"from": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.DateTime),
+			},
+			"to": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.DateTime),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return settleOwner(params)
+		},
+	},
+}
+
+// settlementFromSource returns the settlement resolved by a parent field,
+// lists give values and single queries give pointers.
+func settlementFromSource(source interface{}) *model.Settlement {
+	switch settlement := source.(type) {
+	case *model.Settlement:
+		return settlement
+	case model.Settlement:
+		return &settlement
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/service"
+	"github.com/graphql-go/graphql"
+)
+
+// subscriptionService references the ISubscriptionService
+var subscriptionService service.ISubscriptionService
+
+// getSubscriptions implements ISubscriptionService.GetByMsisdn, only the
+// subscriptions of the tenant of the caller are returned.
+func getSubscriptions(params graphql.ResolveParams) (interface{}, error) {
+	msisdn, _ := params.Args["msisdn"].(string)
+	subscriptions, err := subscriptionService.GetByMsisdn(msisdn)
+	if err != nil {
+		return nil, err
+	}
+	tenant := tenantFrom(params.Context)
+	owned := make([]model.Subscription, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		if tenant.OwnsSale(subscription.Ownerid, subscription.MnoID) {
+			owned = append(owned, subscription)
+		}
+	}
+	return owned, nil
+}
+
+// subscribePack implements ISubscriptionService.Subscribe.
+func subscribePack(params graphql.ResolveParams) (interface{}, error) {
+	msisdn, _ := params.Args["msisdn"].(string)
+	packid, _ := params.Args["packid"].(string)
+
+	err := checkSellable(params, packid)
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	_, err = subscriptionService.Subscribe(msisdn, packid)
+
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	return model.NewOKResult("10"), nil
+}
+
+// pauseSubscription implements ISubscriptionService.Pause.
+func pauseSubscription(params graphql.ResolveParams) (interface{}, error) {
+	id, _ := params.Args["id"].(string)
+	err := checkSubscription(params, id)
+	if err != nil {
+		return koResult(params, err), nil
+	}
+
+	err = subscriptionService.Pause(id)
+
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	return model.NewOKResult("10"), nil
+}
+
+// resumeSubscription implements ISubscriptionService.Resume.
+func resumeSubscription(params graphql.ResolveParams) (interface{}, error) {
+	id, _ := params.Args["id"].(string)
+	err := checkSubscription(params, id)
+	if err != nil {
+		return koResult(params, err), nil
+	}
+
+	err = subscriptionService.Resume(id)
+
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	return model.NewOKResult("10"), nil
+}
+
+// cancelSubscription implements ISubscriptionService.Cancel.
+func cancelSubscription(params graphql.ResolveParams) (interface{}, error) {
+	id, _ := params.Args["id"].(string)
+	err := checkSubscription(params, id)
+	if err != nil {
+		return koResult(params, err), nil
+	}
+
+	err = subscriptionService.Cancel(id)
+
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	return model.NewOKResult("10"), nil
+}
+
+// checkSubscription returns service.ErrNotOwner if the subscription with
+// the given id is not a sale of the tenant of the caller.
+func checkSubscription(params graphql.ResolveParams, id string) error {
+	subscription, err := subscriptionService.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if subscription == nil {
+		return service.ErrSubscriptionState
+	}
+	if !tenantFrom(params.Context).OwnsSale(subscription.Ownerid, subscription.MnoID) {
+		return service.ErrNotOwner
+	}
+	return nil
+}
+
+// SetSubscriptionService sets the subscription service for this handler.
+func SetSubscriptionService(service service.ISubscriptionService) {
+	subscriptionService = service
+}
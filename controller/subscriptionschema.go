@@ -0,0 +1,197 @@
+package controller
+
+import (
+	"github.com/fernandoocampo/pack/model"
+	"github.com/graphql-go/graphql"
+)
+
+// priceNoticeType is a pending pack price change of a subscription.
+var priceNoticeType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "PriceNotice",
+	Description: "A pack price change noticed to a subscriber",
+	Fields: graphql.Fields{
+		"price": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "new price of the pack.",
+		},
+		"noticed": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "when the price change was noticed.",
+		},
+	},
+})
+
+// renewalType is a renewal made to a subscription.
+var renewalType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Renewal",
+	Description: "A renewal of a pack subscription",
+	Fields: graphql.Fields{
+		"orderid": &graphql.Field{
+			Type:        graphql.String,
+			Description: "id of the order that sold the renewal.",
+		},
+		"entitlementid": &graphql.Field{
+			Type:        graphql.String,
+			Description: "id of the entitlement granted by the renewal.",
+		},
+		"price": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "price charged.",
+		},
+		"created": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "when the renewal was made.",
+		},
+	},
+})
+
+// subscriptionType is a pack renewed automatically for a subscriber.
+var subscriptionType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "PackSubscription",
+	Description: "A pack renewed automatically at the end of its term",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The id of the subscription.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				subscription := p.Source.(model.Subscription)
+				return subscription.ID.Hex(), nil
+			},
+		},
+		"msisdn": &graphql.Field{
+			Type:        graphql.String,
+			Description: "line number of the subscriber.",
+		},
+		"packid": &graphql.Field{
+			Type:        graphql.String,
+			Description: "id of the subscribed pack.",
+		},
+		"packcode": &graphql.Field{
+			Type:        graphql.String,
+			Description: "code of the subscribed pack.",
+		},
+		"mnoid": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "id of the mobile network operator of the pack.",
+		},
+		"ownerid": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "id of the company owner of the pack.",
+		},
+		"price": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "price charged on every renewal.",
+		},
+		"pricenotice": &graphql.Field{
+			Type:        priceNoticeType,
+			Description: "pending price change, it is charged once the notice period is over.",
+		},
+		"nextrenewal": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "when the next renewal is due.",
+		},
+		"attempts": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "failed renewal attempts in a row.",
+		},
+		"lasterror": &graphql.Field{
+			Type:        graphql.String,
+			Description: "reason of the last failed renewal.",
+		},
+		"renewals": &graphql.Field{
+			Type:        graphql.NewList(renewalType),
+			Description: "renewals made.",
+		},
+		"state": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "state of the subscription. 0. canceled, 1. active, 2. paused, 3. suspended",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				subscription := p.Source.(model.Subscription)
+				return int(subscription.State), nil
+			},
+		},
+	},
+})
+
+// subscriptionQueryFields contains the queries over pack subscriptions.
+var subscriptionQueryFields = graphql.Fields{
+	"packSubscriptions": &graphql.Field{
+		Type:        graphql.NewList(subscriptionType),
+		Description: "query the pack subscriptions of a subscriber",
+		Args: graphql.FieldConfigArgument{
+			"msisdn": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return getSubscriptions(params)
+		},
+	},
+}
+
+// subscriptionMutationFields contains the mutations over pack subscriptions.
+var subscriptionMutationFields = graphql.Fields{
+	/*
+		subscribe to a pack
+	*/
+	"subscribePack": &graphql.Field{
+		Type:        resultType, // the return type for this field
+		Description: "subscribes a subscriber to a pack that is renewed at the end of its term",
+		Args: graphql.FieldConfigArgument{
+			"msisdn": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+			"packid": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return subscribePack(params)
+		},
+	},
+	/*
+		pause a subscription
+	*/
+	"pauseSubscription": &graphql.Field{
+		Type:        resultType, // the return type for this field
+		Description: "stops the renewals of a subscription",
+		Args: graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return pauseSubscription(params)
+		},
+	},
+	/*
+		resume a subscription
+	*/
+	"resumeSubscription": &graphql.Field{
+		Type:        resultType, // the return type for this field
+		Description: "restarts the renewals of a paused subscription",
+		Args: graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return resumeSubscription(params)
+		},
+	},
+	/*
+		cancel a subscription
+	*/
+	"cancelSubscription": &graphql.Field{
+		Type:        resultType, // the return type for this field
+		Description: "cancels a subscription, the current period is kept until it expires",
+		Args: graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return cancelSubscription(params)
+		},
+	},
+}
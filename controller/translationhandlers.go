@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/service"
+	"github.com/graphql-go/graphql"
+)
+
+// searchPacks implements IPackService.Search.
+func searchPacks(params graphql.ResolveParams) (interface{}, error) {
+	text, _ := params.Args["text"].(string)
+	limit, _ := params.Args["limit"].(int)
+	packs, err := tenantPackService(params).Search(text, limit)
+	if err != nil {
+		return nil, err
+	}
+	return localizePacks(params, availablePacks(params, packs)), nil
+}
+
+// setPackTranslation implements IPackService.SetTranslation.
+func setPackTranslation(params graphql.ResolveParams) (interface{}, error) {
+	id, _ := params.Args["id"].(string)
+	translation := model.Translation{}
+	translation.Locale, _ = params.Args["locale"].(string)
+	translation.Name, _ = params.Args["name"].(string)
+	translation.Desc, _ = params.Args["desc"].(string)
+	translation.Kwds, _ = params.Args["kwds"].(string)
+
+	err := tenantPackService(params).SetTranslation(id, translation)
+
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	return model.NewOKResult("10"), nil
+}
+
+// removePackTranslation implements IPackService.RemoveTranslation.
+func removePackTranslation(params graphql.ResolveParams) (interface{}, error) {
+	id, _ := params.Args["id"].(string)
+	locale, _ := params.Args["locale"].(string)
+
+	err := tenantPackService(params).RemoveTranslation(id, locale)
+
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	return model.NewOKResult("10"), nil
+}
+
+// contentLocales returns the locales the caller asked for the pack texts,
+// the locale argument or else the languages of the Accept-Language header.
+func contentLocales(params graphql.ResolveParams) []string {
+	if locale, _ := params.Args["locale"].(string); locale != "" {
+		return []string{locale}
+	}
+	return languagesFrom(params.Context)
+}
+
+// localizePack returns the pack with its texts in the locale asked by the caller.
+func localizePack(params graphql.ResolveParams, pack *model.Pack) *model.Pack {
+	if pack == nil {
+		return nil
+	}
+	var mnoid int8
+	if pack.Mno != nil {
+		mnoid = pack.Mno.ID
+	}
+	return pack.Localize(contentLocales(params), service.ContentLocale(mnoid))
+}
+
+// localizePacks returns the packs with their texts in the locale asked by the caller.
+func localizePacks(params graphql.ResolveParams, packs []model.Pack) []model.Pack {
+	localized := make([]model.Pack, 0, len(packs))
+	for i := range packs {
+		localized = append(localized, *localizePack(params, &packs[i]))
+	}
+	return localized
+}
@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"github.com/graphql-go/graphql"
+)
+
+// translationType contains the texts of a pack in a locale.
+var translationType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Translation",
+	Description: "The name, description and keywords of a pack in a locale",
+	Fields: graphql.Fields{
+		"locale": &graphql.Field{
+			Type:        graphql.String,
+			Description: "language of the translation. e.g. en, es.",
+		},
+		"name": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Name of the pack.",
+		},
+		"desc": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Description of the pack.",
+		},
+		"kwds": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Key words for searching.",
+		},
+	},
+})
+
+// localeArgument asks for the pack texts in a locale, without it the
+// Accept-Language header is used.
+var localeArgument = &graphql.ArgumentConfig{
+	Type:        graphql.String,
+	Description: "locale of the pack texts. e.g. en, es-CO. The default locale of the mno if the pack has no translation.",
+}
+
+// translationQueryFields contains the queries over the pack texts.
+var translationQueryFields = graphql.Fields{
+	"searchPacks": &graphql.Field{
+		Type:        graphql.NewList(packType),
+		Description: "search packs by the name, description and keywords in every locale",
+		Args: mergeArguments(availabilityArguments(), graphql.FieldConfigArgument{
+			"text": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+			"limit": &graphql.ArgumentConfig{
+				Type:         graphql.Int,
+				DefaultValue: 20,
+			},
+			"locale": localeArgument,
+		}),
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return searchPacks(params)
+		},
+	},
+}
+
+// translationMutationFields contains the mutations over the pack texts.
+var translationMutationFields = graphql.Fields{
+	/*
+		add or replace the texts of a pack in a locale
+	*/
+	"setPackTranslation": &graphql.Field{
+		Type:        resultType, // the return type for this field
+		Description: "sets the name, description and keywords of a pack in a locale",
+		Args: graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+			"locale": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+			"name": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+			"desc": &graphql.ArgumentConfig{
+				Type: graphql.String,
+			},
+			"kwds": &graphql.ArgumentConfig{
+				Type: graphql.String,
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return setPackTranslation(params)
+		},
+	},
+	/*
+		remove the texts of a pack in a locale
+	*/
+	"removePackTranslation": &graphql.Field{
+		Type:        resultType, // the return type for this field
+		Description: "removes the translation of a pack in a locale",
+		Args: graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+			"locale": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return removePackTranslation(params)
+		},
+	},
+}
@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/service"
+	"github.com/graphql-go/graphql"
+)
+
+// webhookService references the IWebhookService
+var webhookService service.IWebhookService
+
+// getWebhooks implements IWebhookService.GetAll.
+func getWebhooks(params graphql.ResolveParams) (interface{}, error) {
+	ownerid, _ := params.Args["ownerid"].(int)
+	ownerid, err := webhookOwner(tenantFrom(params.Context), ownerid)
+	if err != nil {
+		return nil, err
+	}
+	return webhookService.GetAll(ownerid)
+}
+
+// getWebhookDeliveries implements IWebhookService.Deliveries.
+func getWebhookDeliveries(params graphql.ResolveParams) (interface{}, error) {
+	webhookid, _ := params.Args["webhookid"].(string)
+	state, _ := params.Args["state"].(string)
+	limit, _ := params.Args["limit"].(int)
+	_, err := ownedWebhook(params, webhookid)
+	if err != nil {
+		return nil, err
+	}
+	return webhookService.Deliveries(webhookid, state, limit)
+}
+
+// createWebhook implements IWebhookService.Create. The secret goes in the
+// result message.
+func createWebhook(params graphql.ResolveParams) (interface{}, error) {
+	webhook := model.NewWebhook(params.Args)
+	ownerid, err := webhookOwner(tenantFrom(params.Context), webhook.Ownerid)
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	webhook.Ownerid = ownerid
+
+	secret, err := webhookService.Create(webhook)
+
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	result := model.NewOKResult("10")
+	result.Msg = secret
+	return result, nil
+}
+
+// deleteWebhook implements IWebhookService.Delete.
+func deleteWebhook(params graphql.ResolveParams) (interface{}, error) {
+	id, _ := params.Args["id"].(string)
+	_, err := ownedWebhook(params, id)
+	if err != nil {
+		return koResult(params, err), nil
+	}
+
+	err = webhookService.Delete(id)
+
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	return model.NewOKResult("10"), nil
+}
+
+// redeliverWebhook implements IWebhookService.Redeliver. The state of the
+// delivery goes in the result message.
+func redeliverWebhook(params graphql.ResolveParams) (interface{}, error) {
+	id, _ := params.Args["id"].(string)
+	delivery, err := webhookService.GetDelivery(id)
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	_, err = ownedWebhook(params, delivery.WebhookID)
+	if err != nil {
+		return koResult(params, err), nil
+	}
+
+	delivery, err = webhookService.Redeliver(id)
+
+	if err != nil {
+		return koResult(params, err), nil
+	}
+	result := model.NewOKResult("10")
+	result.Msg = delivery.State
+	return result, nil
+}
+
+// webhookOwner returns the owner of the webhooks the caller asks for, the
+// owner of the caller if it asks for none. Only platform admins get the
+// webhooks of every owner.
+func webhookOwner(tenant *model.Tenant, ownerid int) (int, error) {
+	if ownerid == 0 && tenant != nil {
+		if tenant.Admin {
+			return 0, nil
+		}
+		ownerid = tenant.Ownerid
+	}
+	if !actsFor(tenant, ownerid) {
+		return 0, service.ErrNotOwner
+	}
+	return ownerid, nil
+}
+
+// ownedWebhook returns the webhook with the given id if the caller acts
+// for its owner.
+func ownedWebhook(params graphql.ResolveParams, id string) (*model.Webhook, error) {
+	webhook, err := webhookService.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !actsFor(tenantFrom(params.Context), webhook.Ownerid) {
+		return nil, service.ErrNotOwner
+	}
+	return webhook, nil
+}
+
+// SetWebhookService sets the webhook service for this handler.
+func SetWebhookService(service service.IWebhookService) {
+	webhookService = service
+}
@@ -0,0 +1,231 @@
+package controller
+
+import (
+	"github.com/fernandoocampo/pack/model"
+	"github.com/graphql-go/graphql"
+)
+
+// webhookType is a target the pack events are posted to, the secret is
+// never returned.
+var webhookType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Webhook",
+	Description: "A target the pack events are posted to",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The id of the webhook.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				webhook := p.Source.(model.Webhook)
+				return webhook.ID.Hex(), nil
+			},
+		},
+		"ownerid": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "owner whose packs are notified, 0 for every pack.",
+		},
+		"url": &graphql.Field{
+			Type:        graphql.String,
+			Description: "address the events are posted to.",
+		},
+		"events": &graphql.Field{
+			Type:        graphql.NewList(graphql.String),
+			Description: "types of the events posted, empty for every type.",
+		},
+		"created": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "when the webhook was created.",
+		},
+	},
+})
+
+// webhookAttemptType is an attempt to post an event to a webhook.
+var webhookAttemptType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "WebhookAttempt",
+	Description: "An attempt to post an event to a webhook",
+	Fields: graphql.Fields{
+		"at": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "when the attempt was made.",
+		},
+		"status": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "http status of the answer, 0 if there was none.",
+		},
+		"error": &graphql.Field{
+			Type:        graphql.String,
+			Description: "why the attempt failed.",
+		},
+		"duration": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "milliseconds the target took to answer.",
+		},
+	},
+})
+
+// webhookDeliveryType is an event posted to a webhook with its attempts.
+var webhookDeliveryType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "WebhookDelivery",
+	Description: "An event posted to a webhook with the log of its attempts",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The id of the delivery, it is sent in the X-Pack-Delivery header.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				delivery := p.Source.(model.WebhookDelivery)
+				return delivery.ID.Hex(), nil
+			},
+		},
+		"webhookid": &graphql.Field{
+			Type:        graphql.String,
+			Description: "id of the webhook.",
+		},
+		"eventid": &graphql.Field{
+			Type:        graphql.String,
+			Description: "id of the event.",
+		},
+		"eventtype": &graphql.Field{
+			Type:        graphql.String,
+			Description: "type of the event. e.g. PackPriceChanged.",
+		},
+		"packid": &graphql.Field{
+			Type:        graphql.String,
+			Description: "id of the changed pack.",
+		},
+		"payload": &graphql.Field{
+			Type:        graphql.String,
+			Description: "json body posted.",
+		},
+		"state": &graphql.Field{
+			Type:        graphql.String,
+			Description: "pending, delivered or dead.",
+		},
+		"tries": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "failed attempts in a row.",
+		},
+		"nextattempt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "when the next attempt is due if the delivery is pending.",
+		},
+		"attempts": &graphql.Field{
+			Type:        graphql.NewList(webhookAttemptType),
+			Description: "last attempts, the newest last.",
+		},
+		"created": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "when the delivery was created.",
+		},
+		"delivered": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "when the target accepted the event.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				delivery := p.Source.(model.WebhookDelivery)
+				if delivery.Delivered.IsZero() {
+					return nil, nil
+				}
+				return delivery.Delivered, nil
+			},
+		},
+	},
+})
+
+// webhookQueryFields contains the queries over webhooks.
+var webhookQueryFields = graphql.Fields{
+	"webhooks": &graphql.Field{
+		Type:        graphql.NewList(webhookType),
+		Description: "query the webhooks of an owner, the owner of the caller by default",
+		Args: graphql.FieldConfigArgument{
+			"ownerid": &graphql.ArgumentConfig{
+				Type:        graphql.Int,
+				Description: "owner of the webhooks, platform admins get every webhook without it",
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return getWebhooks(params)
+		},
+	},
+	"webhookDeliveries": &graphql.Field{
+		Type:        graphql.NewList(webhookDeliveryType),
+		Description: "query the last deliveries of a webhook, the dead ones wait for a redelivery",
+		Args: graphql.FieldConfigArgument{
+			"webhookid": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+			"state": &graphql.ArgumentConfig{
+				Type:        graphql.String,
+				Description: "pending, delivered or dead, every state if it is not given",
+			},
+			"limit": &graphql.ArgumentConfig{
+				Type:         graphql.Int,
+				DefaultValue: 50,
+				Description:  "deliveries returned, at most 500",
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return getWebhookDeliveries(params)
+		},
+	},
+}
+
+// webhookMutationFields contains the mutations over webhooks.
+var webhookMutationFields = graphql.Fields{
+	/*
+		create a webhook
+	*/
+	"createWebhook": &graphql.Field{
+		Type:        resultType, // the return type for this field
+		Description: "creates a webhook, its secret goes in the result message and it is not shown again",
+		Args: graphql.FieldConfigArgument{
+			"url": &graphql.ArgumentConfig{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "http or https address the events are posted to",
+			},
+			"events": &graphql.ArgumentConfig{
+				Type:        graphql.NewList(graphql.String),
+				Description: "types of the events posted, every type if it is not given",
+			},
+			"secret": &graphql.ArgumentConfig{
+				Type:        graphql.String,
+				Description: "key of the signatures, at least 16 characters. One is generated if it is not given",
+			},
+			"ownerid": &graphql.ArgumentConfig{
+				Type:        graphql.Int,
+				Description: "owner whose packs are notified, the owner of the caller by default",
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return createWebhook(params)
+		},
+	},
+	/*
+		delete a webhook
+	*/
+	"deleteWebhook": &graphql.Field{
+		Type:        resultType, // the return type for this field
+		Description: "deletes a webhook, its pending deliveries are not posted",
+		Args: graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return deleteWebhook(params)
+		},
+	},
+	/*
+		post a delivery again
+	*/
+	"redeliverWebhook": &graphql.Field{
+		Type:        resultType, // the return type for this field
+		Description: "posts a delivery again right away, the state of the delivery goes in the result message",
+		Args: graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "id of the delivery",
+			},
+		},
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			return redeliverWebhook(params)
+		},
+	},
+}
@@ -27,17 +27,25 @@ func NewRouter() *mux.Router {
 	// the URL or other conditions
 	router := mux.NewRouter().StrictSlash(true)
 
+	// graphql subscriptions over websocket, the client is authenticated
+	// when the connection starts.
+	router.Methods("GET").
+		Path("/graphql").
+		HeadersRegexp("Upgrade", "(?i)^websocket$").
+		Name("GraphqlWebSocket").
+		HandlerFunc(localize(httpWebSocket))
+
 	// Get for query graphql
 	router.Methods("GET").
 		Path("/graphql").
 		Name("GetGraphql").
-		HandlerFunc(httpGet) // TODO put graphql function
+		HandlerFunc(authenticate(localize(httpGet))) // TODO put graphql function
 
 	// Post for graphql to create users
 	router.Methods("POST").
 		Path("/graphql").
 		Name("PostGraphql").
-		HandlerFunc(httpPost) // TODO put graphql function
+		HandlerFunc(authenticate(localize(httpPost))) // TODO put graphql function
 
 	// get health status of this service.
 	router.Methods("GET").
@@ -45,5 +53,38 @@ func NewRouter() *mux.Router {
 		Name("health").
 		HandlerFunc(Health) // what's the health
 
+	// settlement of a reseller as csv.
+	router.Methods("GET").
+		Path("/settlements/{id}/csv").
+		Name("settlementCSV").
+		HandlerFunc(authenticate(localize(SettlementCSV)))
+
+	// statement of a reseller for a period as csv.
+	router.Methods("GET").
+		Path("/owners/{ownerid}/statement/csv").
+		Name("statementCSV").
+		HandlerFunc(authenticate(localize(StatementCSV)))
+
+	// create or update packs from a csv or json lines file.
+	router.Methods("POST").
+		Path("/packs/import").
+		Name("importPacks").
+		HandlerFunc(authenticate(localize(ImportPacks)))
+
+	// stream the packs that match a filter as csv, json lines or xlsx.
+	router.Methods("GET").
+		Path("/packs/export").
+		Name("exportPacks").
+		HandlerFunc(authenticate(localize(ExportPacks)))
+
+	// sales report of a period as csv.
+	router.Methods("GET").
+		Path("/reports/sales/csv").
+		Name("salesReportCSV").
+		HandlerFunc(authenticate(localize(SalesReportCSV)))
+
+	// rest api of the packs and its openapi document.
+	addRestRoutes(router)
+
 	return router
 }
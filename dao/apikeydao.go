@@ -0,0 +1,21 @@
+package dao
+
+import (
+	"time"
+
+	"github.com/fernandoocampo/pack/model"
+)
+
+// IAPIKeyDAO defines data access behavior for api keys.
+type IAPIKeyDAO interface {
+	// Create inserts a new api key.
+	Create(key *model.APIKey) error
+	// GetByHash returns the api key with the given hash, nil if there is none.
+	GetByHash(hash string) (*model.APIKey, error)
+	// GetAll returns every api key, the newest first.
+	GetAll() ([]model.APIKey, error)
+	// Revoke marks an api key as revoked.
+	Revoke(id string) error
+	// Touch records the last time an api key was used.
+	Touch(id string, when time.Time) error
+}
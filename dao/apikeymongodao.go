@@ -0,0 +1,117 @@
+package dao
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fernandoocampo/pack/model"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// apiKeyColl is the mongo collection name for api keys
+const apiKeyColl = "apikeys"
+
+// MongoAPIKeyDAO implements IAPIKeyDAO using mongo.
+type MongoAPIKeyDAO struct {
+}
+
+// Create implements IAPIKeyDAO.Create.
+func (m *MongoAPIKeyDAO) Create(key *model.APIKey) error {
+	if key == nil || key.Hash == "" {
+		return errors.New("Invalid api key data")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(apiKeyColl)
+
+	if key.ID == "" {
+		key.ID = bson.NewObjectId()
+	}
+	err := c.Insert(key)
+	if err != nil {
+		errmsg := "An error on api key creation - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return nil
+}
+
+// GetByHash implements IAPIKeyDAO.GetByHash.
+func (m *MongoAPIKeyDAO) GetByHash(hash string) (*model.APIKey, error) {
+	if hash == "" {
+		return nil, errors.New("Invalid api key hash")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(apiKeyColl)
+
+	result := model.APIKey{}
+	err := c.Find(bson.M{"hash": hash}).One(&result)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return nil, nil
+		}
+		errmsg := "An error finding an api key by hash - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return &result, nil
+}
+
+// GetAll implements IAPIKeyDAO.GetAll.
+func (m *MongoAPIKeyDAO) GetAll() ([]model.APIKey, error) {
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(apiKeyColl)
+
+	result := []model.APIKey{}
+	err := c.Find(nil).Sort("-created").All(&result)
+	if err != nil {
+		errmsg := "An error finding api keys - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return result, nil
+}
+
+// Revoke implements IAPIKeyDAO.Revoke.
+func (m *MongoAPIKeyDAO) Revoke(id string) error {
+	return m.update(id, bson.M{"$set": bson.M{"revoked": true}})
+}
+
+// Touch implements IAPIKeyDAO.Touch.
+func (m *MongoAPIKeyDAO) Touch(id string, when time.Time) error {
+	return m.update(id, bson.M{"$set": bson.M{"lastused": when}})
+}
+
+// update applies the given change to an api key.
+func (m *MongoAPIKeyDAO) update(id string, change bson.M) error {
+	if !bson.IsObjectIdHex(id) {
+		return errors.New("Invalid api key id")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(apiKeyColl)
+
+	err := c.UpdateId(bson.ObjectIdHex(id), change)
+	if err != nil {
+		errmsg := "An error updating an api key - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,12 @@
+package dao
+
+import "github.com/fernandoocampo/pack/model"
+
+// IAuditDAO defines data access behavior for the audit of pack changes.
+type IAuditDAO interface {
+	// Create inserts a new audit entry.
+	Create(entry *model.AuditEntry) error
+	// GetByPack returns the audit entries of the given pack, the newest
+	// first and at most limit entries.
+	GetByPack(packid string, limit int) ([]model.AuditEntry, error)
+}
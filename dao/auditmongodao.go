@@ -0,0 +1,59 @@
+package dao
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/fernandoocampo/pack/model"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// auditColl is the mongo collection name for the audit of pack changes
+const auditColl = "packaudit"
+
+// MongoAuditDAO implements IAuditDAO using mongo.
+type MongoAuditDAO struct {
+}
+
+// Create implements IAuditDAO.Create.
+func (m *MongoAuditDAO) Create(entry *model.AuditEntry) error {
+	if entry == nil {
+		return errors.New("Invalid audit entry data")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(auditColl)
+
+	if entry.ID == "" {
+		entry.ID = bson.NewObjectId()
+	}
+	err := c.Insert(entry)
+	if err != nil {
+		errmsg := "An error on audit entry creation - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return nil
+}
+
+// GetByPack implements IAuditDAO.GetByPack.
+func (m *MongoAuditDAO) GetByPack(packid string, limit int) ([]model.AuditEntry, error) {
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(auditColl)
+
+	result := []model.AuditEntry{}
+	err := c.Find(bson.M{"packid": packid}).Sort("-created").Limit(limit).All(&result)
+	if err != nil {
+		errmsg := "An error finding the audit of a pack - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return result, nil
+}
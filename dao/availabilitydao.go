@@ -0,0 +1,17 @@
+package dao
+
+import "github.com/fernandoocampo/pack/model"
+
+// IAvailabilityDAO defines data access behavior for the catalog of sales
+// channels and regions.
+type IAvailabilityDAO interface {
+	// Create inserts a new channel or region.
+	Create(code *model.AvailabilityCode) error
+	// GetByCode search the channel or region with the given code and
+	// return it.
+	GetByCode(kind string, code string) (*model.AvailabilityCode, error)
+	// GetAll returns the channels or regions sorted by code.
+	GetAll(kind string) ([]model.AvailabilityCode, error)
+	// Delete removes the channel or region with the given code.
+	Delete(kind string, code string) error
+}
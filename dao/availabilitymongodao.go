@@ -0,0 +1,100 @@
+package dao
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/fernandoocampo/pack/model"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// availabilityColl is the mongo collection name for sales channels and regions
+const availabilityColl = "availability"
+
+// MongoAvailabilityDAO implements IAvailabilityDAO using mongo.
+type MongoAvailabilityDAO struct {
+}
+
+// Create implements IAvailabilityDAO.Create.
+func (m *MongoAvailabilityDAO) Create(code *model.AvailabilityCode) error {
+	if code == nil {
+		return errors.New("Invalid availability code data")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(availabilityColl)
+
+	if code.ID == "" {
+		code.ID = bson.NewObjectId()
+	}
+	err := c.Insert(code)
+	if err != nil {
+		errmsg := "An error on availability code creation - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return nil
+}
+
+// GetByCode implements IAvailabilityDAO.GetByCode.
+func (m *MongoAvailabilityDAO) GetByCode(kind string, code string) (*model.AvailabilityCode, error) {
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(availabilityColl)
+
+	result := model.AvailabilityCode{}
+	err := c.Find(bson.M{"kind": kind, "code": code}).One(&result)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return nil, nil
+		}
+		errmsg := "An error finding an availability code - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return &result, nil
+}
+
+// GetAll implements IAvailabilityDAO.GetAll.
+func (m *MongoAvailabilityDAO) GetAll(kind string) ([]model.AvailabilityCode, error) {
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(availabilityColl)
+
+	result := []model.AvailabilityCode{}
+	err := c.Find(bson.M{"kind": kind}).Sort("code").All(&result)
+	if err != nil {
+		errmsg := "An error finding availability codes - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return result, nil
+}
+
+// Delete implements IAvailabilityDAO.Delete.
+func (m *MongoAvailabilityDAO) Delete(kind string, code string) error {
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(availabilityColl)
+
+	err := c.Remove(bson.M{"kind": kind, "code": code})
+	if err != nil && err != mgo.ErrNotFound {
+		errmsg := "An error deleting an availability code - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return nil
+}
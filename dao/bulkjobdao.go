@@ -0,0 +1,19 @@
+package dao
+
+import "github.com/fernandoocampo/pack/model"
+
+// IBulkJobDAO defines data access behavior for bulk pack jobs.
+type IBulkJobDAO interface {
+	// Create inserts a new job.
+	Create(job *model.BulkJob) error
+	// Update replaces the progress of an existent job.
+	Update(job *model.BulkJob) error
+	// GetByID search a job with the given id and return it.
+	GetByID(id string) (*model.BulkJob, error)
+	// GetBySubject returns the jobs started by the given caller, the
+	// newest first and at most limit jobs.
+	GetBySubject(subject string, limit int) ([]model.BulkJob, error)
+	// InterruptRunning marks the pending and running jobs as interrupted
+	// and returns how many there were.
+	InterruptRunning() (int, error)
+}
@@ -0,0 +1,138 @@
+package dao
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fernandoocampo/pack/model"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// bulkJobColl is the mongo collection name for bulk pack jobs
+const bulkJobColl = "bulkjobs"
+
+// MongoBulkJobDAO implements IBulkJobDAO using mongo.
+type MongoBulkJobDAO struct {
+}
+
+// Create implements IBulkJobDAO.Create.
+func (m *MongoBulkJobDAO) Create(job *model.BulkJob) error {
+	if job == nil {
+		return errors.New("Invalid bulk job data")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(bulkJobColl)
+
+	if job.ID == "" {
+		job.ID = bson.NewObjectId()
+	}
+	err := c.Insert(job)
+	if err != nil {
+		errmsg := "An error on bulk job creation - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return nil
+}
+
+// Update implements IBulkJobDAO.Update.
+func (m *MongoBulkJobDAO) Update(job *model.BulkJob) error {
+	if job == nil || job.ID == "" {
+		return errors.New("Invalid bulk job data")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(bulkJobColl)
+
+	change := bson.M{"$set": bson.M{
+		"status":    job.Status,
+		"total":     job.Total,
+		"processed": job.Processed,
+		"succeeded": job.Succeeded,
+		"skipped":   job.Skipped,
+		"failed":    job.Failed,
+		"failures":  job.Failures,
+		"started":   job.Started,
+		"finished":  job.Finished,
+	}}
+	err := c.UpdateId(job.ID, change)
+	if err != nil {
+		errmsg := "An error updating a bulk job - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return nil
+}
+
+// GetByID implements IBulkJobDAO.GetByID.
+func (m *MongoBulkJobDAO) GetByID(id string) (*model.BulkJob, error) {
+	if !bson.IsObjectIdHex(id) {
+		return nil, errors.New("Invalid bulk job id")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(bulkJobColl)
+
+	result := model.BulkJob{}
+	err := c.FindId(bson.ObjectIdHex(id)).One(&result)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return nil, nil
+		}
+		errmsg := "An error finding a bulk job by id - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return &result, nil
+}
+
+// GetBySubject implements IBulkJobDAO.GetBySubject.
+func (m *MongoBulkJobDAO) GetBySubject(subject string, limit int) ([]model.BulkJob, error) {
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(bulkJobColl)
+
+	result := []model.BulkJob{}
+	err := c.Find(bson.M{"subject": subject}).Select(bson.M{"failures": 0}).Sort("-created").Limit(limit).All(&result)
+	if err != nil {
+		errmsg := "An error finding bulk jobs by subject - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return result, nil
+}
+
+// InterruptRunning implements IBulkJobDAO.InterruptRunning.
+func (m *MongoBulkJobDAO) InterruptRunning() (int, error) {
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(bulkJobColl)
+
+	running := bson.M{"status": bson.M{"$in": []string{model.BulkPending, model.BulkRunning}}}
+	change := bson.M{"$set": bson.M{"status": model.BulkInterrupted, "finished": time.Now()}}
+	info, err := c.UpdateAll(running, change)
+	if err != nil {
+		errmsg := "An error interrupting bulk jobs - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return 0, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return info.Updated, nil
+}
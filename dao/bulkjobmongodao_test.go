@@ -0,0 +1,58 @@
+package dao_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/model"
+)
+
+// TestInterruptBulkJob verify that a running job is interrupted and its
+// progress is kept.
+func TestInterruptBulkJob(t *testing.T) {
+	// GIVEN a running job
+	dao.SetDBname("amphora")
+	dao.SetMongoAddrs([]string{"localhost:27017"})
+	dao.SetTimeout(60)
+
+	dao.InitMgoSession()
+	defer dao.CloseMgoSession()
+
+	jobdao := new(dao.MongoBulkJobDAO)
+	job := &model.BulkJob{Kind: model.BulkChangeState, Filter: &model.PackFilter{MnoID: 2},
+		Subject: "bulk-tester", Status: model.BulkPending, Created: time.Now()}
+	err1 := jobdao.Create(job)
+	if err1 != nil {
+		t.Fatalf("Expected err1 to be nil but it was: %s", err1)
+	}
+	job.Status = model.BulkRunning
+	job.Total = 10
+	job.Processed = 4
+	job.Succeeded = 4
+	err2 := jobdao.Update(job)
+	if err2 != nil {
+		t.Fatalf("Expected err2 to be nil but it was: %s", err2)
+	}
+
+	// WHEN the service starts again
+	interrupted, err3 := jobdao.InterruptRunning()
+
+	// THEN the job is interrupted with its progress
+	if err3 != nil || interrupted < 1 {
+		t.Fatalf("Expected the job to be interrupted but got %d, %v", interrupted, err3)
+	}
+	stored, err4 := jobdao.GetByID(job.ID.Hex())
+	if err4 != nil || stored == nil {
+		t.Fatalf("Expected the job but got %+v, %v", stored, err4)
+	}
+	if stored.Status != model.BulkInterrupted || stored.Processed != 4 || stored.Filter.MnoID != 2 {
+		t.Fatalf("Expected the interrupted job with its progress but got %+v", stored)
+	}
+
+	// AND it is listed for its subject
+	jobs, err5 := jobdao.GetBySubject("bulk-tester", 10)
+	if err5 != nil || len(jobs) == 0 || jobs[0].ID != job.ID {
+		t.Fatalf("Expected the job to be listed but got %+v, %v", jobs, err5)
+	}
+}
@@ -0,0 +1,15 @@
+package dao
+
+import "github.com/fernandoocampo/pack/model"
+
+// ICommissionDAO defines data access behavior for reseller commission rules.
+type ICommissionDAO interface {
+	// Create inserts a new commission rule.
+	Create(rule *model.CommissionRule) error
+	// Delete removes the commission rule with the given id.
+	Delete(id string) error
+	// GetByID search a commission rule with the given id and return it.
+	GetByID(id string) (*model.CommissionRule, error)
+	// GetByOwner returns the commission rules of the given owner.
+	GetByOwner(ownerid int) ([]model.CommissionRule, error)
+}
@@ -0,0 +1,106 @@
+package dao
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/fernandoocampo/pack/model"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// commissionColl is the mongo collection name for commission rules
+const commissionColl = "commissions"
+
+// MongoCommissionDAO implements ICommissionDAO using mongo.
+type MongoCommissionDAO struct {
+}
+
+// Create implements ICommissionDAO.Create.
+func (m *MongoCommissionDAO) Create(rule *model.CommissionRule) error {
+	if rule == nil {
+		return errors.New("Invalid commission rule data")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(commissionColl)
+
+	if rule.ID == "" {
+		rule.ID = bson.NewObjectId()
+	}
+	err := c.Insert(rule)
+	if err != nil {
+		errmsg := "An error on commission rule creation - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return nil
+}
+
+// Delete implements ICommissionDAO.Delete.
+func (m *MongoCommissionDAO) Delete(id string) error {
+	if !bson.IsObjectIdHex(id) {
+		return errors.New("Invalid commission rule id")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(commissionColl)
+
+	err := c.RemoveId(bson.ObjectIdHex(id))
+	if err != nil && err != mgo.ErrNotFound {
+		errmsg := "An error deleting a commission rule - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return nil
+}
+
+// GetByID implements ICommissionDAO.GetByID.
+func (m *MongoCommissionDAO) GetByID(id string) (*model.CommissionRule, error) {
+	if !bson.IsObjectIdHex(id) {
+		return nil, errors.New("Invalid commission rule id")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(commissionColl)
+
+	result := model.CommissionRule{}
+	err := c.FindId(bson.ObjectIdHex(id)).One(&result)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return nil, nil
+		}
+		errmsg := "An error finding a commission rule by id - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return &result, nil
+}
+
+// GetByOwner implements ICommissionDAO.GetByOwner.
+func (m *MongoCommissionDAO) GetByOwner(ownerid int) ([]model.CommissionRule, error) {
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(commissionColl)
+
+	result := []model.CommissionRule{}
+	err := c.Find(bson.M{"ownerid": ownerid}).Sort("created").All(&result)
+	if err != nil {
+		errmsg := "An error finding commission rules by owner - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return result, nil
+}
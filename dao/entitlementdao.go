@@ -0,0 +1,30 @@
+package dao
+
+import (
+	"time"
+
+	"github.com/fernandoocampo/pack/model"
+)
+
+// IEntitlementDAO defines data access behavior for the packs owned by subscribers.
+type IEntitlementDAO interface {
+	// Create inserts a new entitlement.
+	Create(entitlement *model.Entitlement) error
+	// GetByID search an entitlement with the given id and return it.
+	GetByID(id string) (*model.Entitlement, error)
+	// GetByMsisdn returns the entitlements of the given subscriber. If
+	// onlyopen is true, closed and expired entitlements are left out.
+	// Results are sorted by expiry, the first to expire goes first.
+	GetByMsisdn(msisdn string, onlyopen bool) ([]model.Entitlement, error)
+	// Consume reduces the balance of the given resource in an open entitlement
+	// and records the usage. Returns false if the entitlement is not open or
+	// its remaining balance is lower than amount.
+	Consume(id string, resourceid int16, amount float32) (bool, error)
+	// GiveBack returns an amount consumed from the balance of the given
+	// resource, even if the entitlement is no longer open, and records it
+	// as a negative usage.
+	GiveBack(id string, resourceid int16, amount float32) error
+	// CloseExpired closes the open entitlements that expired at the
+	// given time and returns how many were closed.
+	CloseExpired(now time.Time) (int, error)
+}
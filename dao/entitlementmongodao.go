@@ -0,0 +1,192 @@
+package dao
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fernandoocampo/pack/model"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// entitlementColl is the mongo collection name for entitlements
+const entitlementColl = "entitlements"
+
+// MongoEntitlementDAO implements IEntitlementDAO using mongo.
+type MongoEntitlementDAO struct {
+}
+
+// Create implements IEntitlementDAO.Create.
+func (m *MongoEntitlementDAO) Create(entitlement *model.Entitlement) error {
+	if entitlement == nil {
+		return errors.New("Invalid entitlement data")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(entitlementColl)
+
+	if entitlement.ID == "" {
+		entitlement.ID = bson.NewObjectId()
+	}
+	err := c.Insert(entitlement)
+	if err != nil {
+		errmsg := "An error on entitlement creation - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return nil
+}
+
+// GetByID implements IEntitlementDAO.GetByID.
+func (m *MongoEntitlementDAO) GetByID(id string) (*model.Entitlement, error) {
+	if !bson.IsObjectIdHex(id) {
+		return nil, errors.New("Invalid entitlement id")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(entitlementColl)
+
+	result := model.Entitlement{}
+	err := c.FindId(bson.ObjectIdHex(id)).One(&result)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return nil, nil
+		}
+		errmsg := "An error finding an entitlement by id - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return &result, nil
+}
+
+// GetByMsisdn implements IEntitlementDAO.GetByMsisdn.
+func (m *MongoEntitlementDAO) GetByMsisdn(msisdn string, onlyopen bool) ([]model.Entitlement, error) {
+	if msisdn == "" {
+		return nil, errors.New("Invalid msisdn")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(entitlementColl)
+
+	filter := bson.M{"msisdn": msisdn}
+	if onlyopen {
+		filter["state"] = model.EntitlementOpen
+		filter["expires"] = bson.M{"$gt": time.Now()}
+	}
+
+	result := []model.Entitlement{}
+	err := c.Find(filter).Sort("expires").All(&result)
+	if err != nil {
+		errmsg := "An error finding entitlements by msisdn - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return result, nil
+}
+
+// Consume implements IEntitlementDAO.Consume. The balance check and the
+// update are made in one operation, so concurrent usages cannot leave
+// a negative balance.
+func (m *MongoEntitlementDAO) Consume(id string, resourceid int16, amount float32) (bool, error) {
+	if !bson.IsObjectIdHex(id) || amount <= 0 {
+		return false, errors.New("Invalid entitlement id or amount to consume")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(entitlementColl)
+
+	now := time.Now()
+	filter := bson.M{
+		"_id":     bson.ObjectIdHex(id),
+		"state":   model.EntitlementOpen,
+		"expires": bson.M{"$gt": now},
+		"balances": bson.M{"$elemMatch": bson.M{
+			"resource_id": resourceid,
+			"remaining":   bson.M{"$gte": amount},
+		}},
+	}
+	usage := model.Usage{ResourceID: resourceid, Amount: amount, Created: now}
+	change := bson.M{
+		"$inc":  bson.M{"balances.$.used": amount, "balances.$.remaining": -amount},
+		"$push": bson.M{"usages": usage},
+		"$set":  bson.M{"updated": now},
+	}
+
+	err := c.Update(filter, change)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return false, nil
+		}
+		errmsg := "An error consuming an entitlement balance - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return false, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return true, nil
+}
+
+// GiveBack implements IEntitlementDAO.GiveBack.
+func (m *MongoEntitlementDAO) GiveBack(id string, resourceid int16, amount float32) error {
+	if !bson.IsObjectIdHex(id) || amount <= 0 {
+		return errors.New("Invalid entitlement id or amount to give back")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(entitlementColl)
+
+	now := time.Now()
+	filter := bson.M{
+		"_id":      bson.ObjectIdHex(id),
+		"balances": bson.M{"$elemMatch": bson.M{"resource_id": resourceid, "used": bson.M{"$gte": amount}}},
+	}
+	usage := model.Usage{ResourceID: resourceid, Amount: -amount, Created: now}
+	change := bson.M{
+		"$inc":  bson.M{"balances.$.used": -amount, "balances.$.remaining": amount},
+		"$push": bson.M{"usages": usage},
+		"$set":  bson.M{"updated": now},
+	}
+
+	err := c.Update(filter, change)
+	if err != nil {
+		errmsg := "An error giving back an entitlement balance - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return nil
+}
+
+// CloseExpired implements IEntitlementDAO.CloseExpired.
+func (m *MongoEntitlementDAO) CloseExpired(now time.Time) (int, error) {
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(entitlementColl)
+
+	filter := bson.M{"state": model.EntitlementOpen, "expires": bson.M{"$lte": now}}
+	change := bson.M{"$set": bson.M{"state": model.EntitlementClosed, "updated": now}}
+
+	info, err := c.UpdateAll(filter, change)
+	if err != nil {
+		errmsg := "An error closing expired entitlements - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return 0, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return info.Updated, nil
+}
@@ -0,0 +1,164 @@
+package dao_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/model"
+)
+
+// TestEntitlementConsume verify that balances are reduced and never
+// go below zero.
+func TestEntitlementConsume(t *testing.T) {
+	// GIVEN an entitlement granted to a subscriber
+	dao.SetDBname("amphora")
+	dao.SetMongoAddrs([]string{"localhost:27017"})
+	dao.SetTimeout(60)
+
+	dao.InitMgoSession()
+	defer dao.CloseMgoSession()
+
+	entitlementdao := new(dao.MongoEntitlementDAO)
+
+	msisdn := "573000000026"
+	newentitlement := newEntitlementData(msisdn, time.Now())
+
+	err1 := entitlementdao.Create(newentitlement)
+
+	if err1 != nil {
+		t.Fatalf("Expected err1 to be nil but it was: %s", err1)
+	}
+
+	// WHEN we consume part of the data balance
+	ok, err2 := entitlementdao.Consume(newentitlement.ID.Hex(), 1, 120)
+
+	// THEN we check the balance was reduced
+	if err2 != nil {
+		t.Fatalf("Expected err2 to be nil but it was: %s", err2)
+	}
+	if !ok {
+		t.Fatalf("Expected consume to be done but it was not")
+	}
+
+	entitlement, err3 := entitlementdao.GetByID(newentitlement.ID.Hex())
+
+	if err3 != nil {
+		t.Fatalf("Expected err3 to be nil but it was: %s", err3)
+	}
+	if entitlement.Remaining(1) != 380 {
+		t.Fatalf("Expected remaining balance to be 380 but it was %f", entitlement.Remaining(1))
+	}
+	if len(entitlement.Usages) != 1 {
+		t.Fatalf("Expected 1 usage recorded but there were %d", len(entitlement.Usages))
+	}
+
+	// AND we cannot consume more than the remaining balance
+	ok, err4 := entitlementdao.Consume(newentitlement.ID.Hex(), 1, 381)
+
+	if err4 != nil {
+		t.Fatalf("Expected err4 to be nil but it was: %s", err4)
+	}
+	if ok {
+		t.Fatalf("Expected consume to be rejected but it was done")
+	}
+}
+
+// TestEntitlementCloseExpired verify that expired entitlements are closed.
+func TestEntitlementCloseExpired(t *testing.T) {
+	// GIVEN an entitlement that already expired
+	dao.SetDBname("amphora")
+	dao.SetMongoAddrs([]string{"localhost:27017"})
+	dao.SetTimeout(60)
+
+	dao.InitMgoSession()
+	defer dao.CloseMgoSession()
+
+	entitlementdao := new(dao.MongoEntitlementDAO)
+
+	msisdn := "573000000027"
+	newentitlement := newEntitlementData(msisdn, time.Now().AddDate(0, 0, -5))
+
+	err1 := entitlementdao.Create(newentitlement)
+
+	if err1 != nil {
+		t.Fatalf("Expected err1 to be nil but it was: %s", err1)
+	}
+
+	// WHEN expired entitlements are closed
+	closed, err2 := entitlementdao.CloseExpired(time.Now())
+
+	// THEN we check the entitlement is not open anymore
+	if err2 != nil {
+		t.Fatalf("Expected err2 to be nil but it was: %s", err2)
+	}
+	if closed < 1 {
+		t.Fatalf("Expected at least 1 entitlement closed but it was %d", closed)
+	}
+
+	open, err3 := entitlementdao.GetByMsisdn(msisdn, true)
+
+	if err3 != nil {
+		t.Fatalf("Expected err3 to be nil but it was: %s", err3)
+	}
+	if len(open) != 0 {
+		t.Fatalf("Expected no open entitlements but there were %d", len(open))
+	}
+}
+
+// TestEntitlementGiveBack verify that a consumed amount is given back
+// and recorded as a negative usage.
+func TestEntitlementGiveBack(t *testing.T) {
+	// GIVEN an entitlement with 120 mb consumed
+	dao.SetDBname("amphora")
+	dao.SetMongoAddrs([]string{"localhost:27017"})
+	dao.SetTimeout(60)
+
+	dao.InitMgoSession()
+	defer dao.CloseMgoSession()
+
+	entitlementdao := new(dao.MongoEntitlementDAO)
+
+	newentitlement := newEntitlementData("573000000028", time.Now())
+	err1 := entitlementdao.Create(newentitlement)
+
+	if err1 != nil {
+		t.Fatalf("Expected err1 to be nil but it was: %s", err1)
+	}
+	_, err2 := entitlementdao.Consume(newentitlement.ID.Hex(), 1, 120)
+
+	if err2 != nil {
+		t.Fatalf("Expected err2 to be nil but it was: %s", err2)
+	}
+
+	// WHEN the consumed amount is given back
+	err3 := entitlementdao.GiveBack(newentitlement.ID.Hex(), 1, 120)
+
+	// THEN the whole balance remains and both usages are recorded
+	if err3 != nil {
+		t.Fatalf("Expected err3 to be nil but it was: %s", err3)
+	}
+	entitlement, err4 := entitlementdao.GetByID(newentitlement.ID.Hex())
+
+	if err4 != nil {
+		t.Fatalf("Expected err4 to be nil but it was: %s", err4)
+	}
+	if entitlement.Remaining(1) != 500 || len(entitlement.Usages) != 2 || entitlement.Usages[1].Amount != -120 {
+		t.Fatalf("Expected 500 remaining and a usage of -120 but got %+v", entitlement)
+	}
+
+	// AND more than the consumed amount cannot be given back
+	err5 := entitlementdao.GiveBack(newentitlement.ID.Hex(), 1, 1)
+
+	if err5 == nil {
+		t.Fatalf("Expected an error giving back an amount not consumed but it was nil")
+	}
+}
+
+func newEntitlementData(msisdn string, starts time.Time) *model.Entitlement {
+	pack := newPackData("ent26", "Entitlement pack", "26")
+	pack.Resources = []model.Resource{
+		{ID: 1, Name: "datos", Units: "mb", Amount: 500},
+	}
+	return model.NewEntitlement(msisdn, pack, starts)
+}
@@ -72,7 +72,7 @@ func IsRunning() error {
 	}
 	errmsg := "An error testing session - mgobase - Ping()"
 	log.Errorf("%s : %v\n", errmsg, err.Error())
-	return fmt.Errorf(errmsg, err)
+	return fmt.Errorf("%s: %w", errmsg, err)
 }
 
 // makes a little query to knows if session is ok.
@@ -91,7 +91,7 @@ func testClient() error {
 	if err := db.Run("dbstats", &result); err != nil {
 		errmsg := "An error testing session - mgobase - TestClient"
 		log.Errorf("%s : %v\n", errmsg, err.Error())
-		return fmt.Errorf(errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
 	}
 
 	return nil
@@ -4,6 +4,7 @@ package dao
 import (
 	"errors"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/fernandoocampo/pack/model"
@@ -17,8 +18,77 @@ const mongoDB = "amphora"
 // mongoColl is the mongo collection name
 const mongoColl = "packs"
 
-// MongoDAO struct for mongo connection
+// eachBatchSize is the number of packs read at once by Each.
+const eachBatchSize = 200
+
+// MongoDAO struct for mongo connection. A scoped MongoDAO only reads and
+// changes the packs of its tenant.
 type MongoDAO struct {
+	tenant    *model.Tenant // tenant the queries are scoped to
+	scoped    bool          // true if queries must be scoped to the tenant
+	version   int           // version the changed pack must be at
+	versioned bool          // true if changes must check the version
+}
+
+// Scoped implements *IPackDAO.Scoped.
+func (m *MongoDAO) Scoped(tenant *model.Tenant) IPackDAO {
+	return &MongoDAO{tenant: tenant, scoped: true}
+}
+
+// AtVersion implements *IPackDAO.AtVersion.
+func (m *MongoDAO) AtVersion(version int) IPackDAO {
+	return &MongoDAO{tenant: m.tenant, scoped: m.scoped, version: version, versioned: true}
+}
+
+// atVersion adds the version condition to the filter of a change, packs
+// stored before versions were kept have none and are at version 0.
+func (m *MongoDAO) atVersion(filter bson.M) bson.M {
+	if !m.versioned {
+		return filter
+	}
+	if m.version == 0 {
+		return bson.M{"$and": []bson.M{filter, bson.M{"version": bson.M{"$in": []interface{}{0, nil}}}}}
+	}
+	return bson.M{"$and": []bson.M{filter, bson.M{"version": m.version}}}
+}
+
+// scope adds the tenant conditions to the given filter. A scoped dao
+// without tenant matches no pack. Deleted packs waiting for their events
+// to be relayed are never matched.
+func (m *MongoDAO) scope(filter bson.M) bson.M {
+	filter = bson.M{"$and": []bson.M{filter, bson.M{"deleted": bson.M{"$exists": false}}}}
+	if !m.scoped || (m.tenant != nil && m.tenant.Admin) {
+		return filter
+	}
+	if m.tenant.IsEmpty() {
+		return bson.M{"$and": []bson.M{filter, bson.M{"_id": bson.M{"$exists": false}}}}
+	}
+	tenantfilter := bson.M{}
+	if m.tenant.Ownerid != 0 {
+		tenantfilter["ownerid"] = m.tenant.Ownerid
+	}
+	if m.tenant.MnoID != 0 {
+		tenantfilter["mno.id"] = m.tenant.MnoID
+	}
+	if m.tenant.Channel != "" {
+		return bson.M{"$and": []bson.M{filter, tenantfilter, availableIn("channels", m.tenant.Channel)}}
+	}
+	return bson.M{"$and": []bson.M{filter, tenantfilter}}
+}
+
+// availableIn returns the condition of the packs whose list in the given
+// field has the code or is empty, as an empty list means everywhere.
+func availableIn(field string, code string) bson.M {
+	return bson.M{"$or": []bson.M{
+		{field: bson.M{"$exists": false}},
+		{field: bson.M{"$size": 0}},
+		{field: code},
+	}}
+}
+
+// allows returns true if the pack can be stored by the tenant of the dao.
+func (m *MongoDAO) allows(pack *model.Pack) bool {
+	return !m.scoped || m.tenant.Owns(pack)
 }
 
 // GetByID implements *IPackDAO.GetByID using mongo implementation.
@@ -36,14 +106,14 @@ func (m *MongoDAO) GetByID(id string) (*model.Pack, error) {
 	c := sessionCopy.DB(mongoDB).C(mongoColl)
 
 	result := model.Pack{}
-	err := c.Find(bson.M{"_id": idval}).One(&result)
+	err := c.Find(m.scope(bson.M{"_id": idval})).One(&result)
 	if err != nil {
 		if err == mgo.ErrNotFound {
 			return nil, nil
 		}
 		errmsg := "An error finding an pack by id - mongodao"
 		log.Errorf("%s : %v\n", errmsg, err)
-		return nil, fmt.Errorf(errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
 	}
 
 	return &result, nil
@@ -63,14 +133,14 @@ func (m *MongoDAO) GetIDByCode(packcode string) (string, error) {
 	var result struct {
 		ID bson.ObjectId `bson:"_id"`
 	}
-	err := c.Find(bson.M{"packcode": packcode}).Select(bson.M{"_id": 1}).One(&result)
+	err := c.Find(m.scope(bson.M{"packcode": packcode})).Select(bson.M{"_id": 1}).One(&result)
 	if err != nil {
 		if err == mgo.ErrNotFound {
 			return "", nil
 		}
 		errmsg := "An error finding a pack by packcode - mongodao"
 		log.Errorf("%s : %v\n", errmsg, err)
-		return "", fmt.Errorf(errmsg, err)
+		return "", fmt.Errorf("%s: %w", errmsg, err)
 	}
 
 	return result.ID.Hex(), nil
@@ -88,14 +158,14 @@ func (m *MongoDAO) GetByPackCode(packcode string) (*model.Pack, error) {
 	c := sessionCopy.DB(mongoDB).C(mongoColl)
 
 	result := model.Pack{}
-	err := c.Find(bson.M{"packcode": packcode}).One(&result)
+	err := c.Find(m.scope(bson.M{"packcode": packcode})).One(&result)
 	if err != nil {
 		if err == mgo.ErrNotFound {
 			return nil, nil
 		}
 		errmsg := "An error finding a pack by pack code - mongodao"
 		log.Errorf("%s : %v\n", errmsg, err)
-		return nil, fmt.Errorf(errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
 	}
 
 	return &result, nil
@@ -112,14 +182,14 @@ func (m *MongoDAO) GetByProductID(productid string) (*model.Pack, error) {
 	// References the mongo collection
 	c := sessionCopy.DB(mongoDB).C(mongoColl)
 	result := model.Pack{}
-	err := c.Find(bson.M{"prodid": productid}).One(&result)
+	err := c.Find(m.scope(bson.M{"prodid": productid})).One(&result)
 	if err != nil {
 		if err == mgo.ErrNotFound {
 			return nil, nil
 		}
 		errmsg := "An error finding a pack by prodid - mongodao"
 		log.Errorf("%s : %v\n", errmsg, err)
-		return nil, fmt.Errorf(errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
 	}
 
 	return &result, nil
@@ -136,22 +206,10 @@ func (m *MongoDAO) IsThereThisPack(pack *model.PackExists) (bool, error) {
 	// References the mongo collection
 	c := sessionCopy.DB(mongoDB).C(mongoColl)
 
-	var orfilter bson.M
-	if pack.Packcode != "" && pack.ProdID != "" {
-		orfilter = bson.M{"$or": []bson.M{bson.M{"prodid": pack.ProdID}, bson.M{"packcode": pack.Packcode}}}
-	} else {
-		if pack.Packcode != "" {
-			orfilter = bson.M{"packcode": pack.Packcode}
-		} else {
-			orfilter = bson.M{"prodid": pack.ProdID}
-		}
-	}
-	andfilter := bson.M{"$and": []bson.M{bson.M{"mno.id": pack.MnoID}, orfilter}}
-
 	var result struct {
 		ID bson.ObjectId `bson:"_id"`
 	}
-	err := c.Find(andfilter).Select(bson.M{"_id": 1}).One(&result)
+	err := c.Find(m.scope(packKeysFilter(pack))).Select(bson.M{"_id": 1}).One(&result)
 
 	if err != nil {
 		if err == mgo.ErrNotFound {
@@ -159,46 +217,158 @@ func (m *MongoDAO) IsThereThisPack(pack *model.PackExists) (bool, error) {
 		}
 		errmsg := "An error on IsThereThisPack - mongodao"
 		log.Errorf("%s : %v\n", errmsg, err)
-		return false, fmt.Errorf(errmsg, err)
+		return false, fmt.Errorf("%s: %w", errmsg, err)
 	}
 
 	return true, nil
 }
 
+// GetByKeys implements *IPackDAO.GetByKeys.
+func (m *MongoDAO) GetByKeys(keys *model.PackExists) (*model.Pack, error) {
+	if keys == nil || (keys.Packcode == "" && keys.ProdID == "") {
+		return nil, errors.New("Invalid pack keys")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(mongoColl)
+
+	result := model.Pack{}
+	err := c.Find(m.scope(packKeysFilter(keys))).One(&result)
+
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return nil, nil
+		}
+		errmsg := "An error on GetByKeys - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return &result, nil
+}
+
+// packKeysFilter returns the filter of the packs of the mno with the pack
+// code or the product id of the given keys.
+func packKeysFilter(pack *model.PackExists) bson.M {
+	var orfilter bson.M
+	if pack.Packcode != "" && pack.ProdID != "" {
+		orfilter = bson.M{"$or": []bson.M{bson.M{"prodid": pack.ProdID}, bson.M{"packcode": pack.Packcode}}}
+	} else {
+		if pack.Packcode != "" {
+			orfilter = bson.M{"packcode": pack.Packcode}
+		} else {
+			orfilter = bson.M{"prodid": pack.ProdID}
+		}
+	}
+	return bson.M{"$and": []bson.M{bson.M{"mno.id": pack.MnoID}, orfilter}}
+}
+
 // Create implements *IPackDAO.Create.
 func (m *MongoDAO) Create(packdata *model.Pack) error {
 	if &packdata == nil {
 		return errors.New("Invalid pack data")
 	}
+	if !m.allows(packdata) {
+		return ErrNotInTenant
+	}
 	// make a connection to mongo database
 	sessionCopy := newMgoSession()
 	defer sessionCopy.Close()
 	// References the mongo collection
 	c := sessionCopy.DB(mongoDB).C(mongoColl)
-	err := c.Insert(&packdata)
+	if packdata.ID == "" {
+		packdata.ID = bson.NewObjectId()
+	}
+	packdata.Version = 1
+	document := packDocument{Pack: *packdata,
+		Outbox: []model.Event{model.NewEvent(model.EventPackCreated, packdata.ID.Hex(), nil)}}
+	err := c.Insert(&document)
 
 	if err != nil {
 		errmsg := "An error on pack creation function - mongodao"
 		log.Errorf("%s : %v\n", errmsg, err)
-		return fmt.Errorf(errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
 	}
 
 	return nil
 }
 
+// Update implements *IPackDAO.Update.
+func (m *MongoDAO) Update(id string, packdata *model.Pack) error {
+	if !bson.IsObjectIdHex(id) || packdata == nil {
+		return errors.New("Invalid pack id and pack data")
+	}
+	if !m.allows(packdata) {
+		return ErrNotInTenant
+	}
+	resources := packdata.Resources
+	if resources == nil {
+		resources = []model.Resource{}
+	}
+	// create update json map
+	data := bson.M{
+		"prodid":    packdata.ProdID,
+		"packcode":  packdata.Packcode,
+		"name":      packdata.Name,
+		"desc":      packdata.Desc,
+		"imgurl":    packdata.Img,
+		"kwds":      packdata.Kwds,
+		"price":     packdata.Price,
+		"ownerid":   packdata.Ownerid,
+		"type":      packdata.Packtype,
+		"mno":       packdata.Mno,
+		"term":      packdata.Term,
+		"currency":  packdata.Ccy,
+		"resources": resources,
+		"updated":   time.Now(),
+	}
+	if len(packdata.Translations) > 0 {
+		data["translations"] = packdata.Translations
+	}
+	// the events tell what changed, so the pack is written only if it is
+	// still as it was compared, it is compared again otherwise.
+	for attempt := 1; ; attempt++ {
+		stored, err := m.GetByID(id)
+		if err != nil {
+			return err
+		}
+		if m.versioned && (stored == nil || stored.Version != m.version) {
+			return ErrVersionChanged
+		}
+		if stored == nil {
+			err = mgo.ErrNotFound
+		} else {
+			compared := &MongoDAO{tenant: m.tenant, scoped: m.scoped, version: stored.Version, versioned: true}
+			err = compared.updateDataByID(id, withEvents(bson.M{"$set": data}, model.UpdateEvents(stored, packdata)))
+		}
+		if err == ErrVersionChanged && !m.versioned && attempt < maxUpdateAttempts {
+			continue
+		}
+		if err != nil && err != ErrVersionChanged {
+			errmsg := "An error updating a pack - mongodao"
+			log.Errorf("%s : %v\n", errmsg, err)
+			return fmt.Errorf("%s: %w", errmsg, err)
+		}
+		return err
+	}
+}
+
 // ChangeState implements *IPackDAO.ChangeState.
 func (m *MongoDAO) ChangeState(id string, newstate model.PackState) error {
 	if id == "" {
 		return errors.New("Invalid pack id data")
 	}
 	// create update json map
-	change := bson.M{"$set": bson.M{"state": newstate, "updated": time.Now()}}
-	err := updateDataByID(id, change)
+	change := withEvent(bson.M{"$set": bson.M{"state": newstate, "updated": time.Now()}}, id,
+		model.EventPackStateChanged, bson.M{"state": newstate})
+	err := m.updateDataByID(id, change)
 
 	if err != nil {
 		errmsg := "An error updating a pack state - mongodao"
 		log.Errorf("%s : %v\n", errmsg, err)
-		return fmt.Errorf(errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
 	}
 	return nil
 }
@@ -209,13 +379,14 @@ func (m *MongoDAO) ChangeProductID(id string, newprodid string) error {
 		return errors.New("Invalid pack id and product id data")
 	}
 	// create update json map
-	change := bson.M{"$set": bson.M{"prodid": newprodid, "updated": time.Now()}}
-	err := updateDataByID(id, change)
+	change := withEvent(bson.M{"$set": bson.M{"prodid": newprodid, "updated": time.Now()}}, id,
+		model.EventPackKeysChanged, bson.M{"prodid": newprodid})
+	err := m.updateDataByID(id, change)
 
 	if err != nil {
 		errmsg := "An error updating a pack prodid - mongodao"
 		log.Errorf("%s : %v\n", errmsg, err)
-		return fmt.Errorf(errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
 	}
 	return nil
 }
@@ -226,13 +397,14 @@ func (m *MongoDAO) ChangePackCode(id string, newpackcode string) error {
 		return errors.New("Invalid pack id and pack code data")
 	}
 	// create update json map
-	change := bson.M{"$set": bson.M{"packcode": newpackcode, "updated": time.Now()}}
-	err := updateDataByID(id, change)
+	change := withEvent(bson.M{"$set": bson.M{"packcode": newpackcode, "updated": time.Now()}}, id,
+		model.EventPackKeysChanged, bson.M{"packcode": newpackcode})
+	err := m.updateDataByID(id, change)
 
 	if err != nil {
 		errmsg := "An error updating a packcode - mongodao"
 		log.Errorf("%s : %v\n", errmsg, err)
-		return fmt.Errorf(errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
 	}
 	return nil
 }
@@ -243,13 +415,14 @@ func (m *MongoDAO) ChangeName(id string, newname string) error {
 		return errors.New("Invalid pack id and pack name data")
 	}
 	// create update json map
-	change := bson.M{"$set": bson.M{"name": newname, "updated": time.Now()}}
-	err := updateDataByID(id, change)
+	change := withEvent(bson.M{"$set": bson.M{"name": newname, "updated": time.Now()}}, id,
+		model.EventPackTextsChanged, bson.M{"name": newname})
+	err := m.updateDataByID(id, change)
 
 	if err != nil {
 		errmsg := "An error updating a pack name - mongodao"
 		log.Errorf("%s : %v\n", errmsg, err)
-		return fmt.Errorf(errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
 	}
 	return nil
 }
@@ -260,13 +433,14 @@ func (m *MongoDAO) ChangeDesc(id string, newdesc string) error {
 		return errors.New("Invalid pack id and pack code data")
 	}
 	// create update json map
-	change := bson.M{"$set": bson.M{"desc": newdesc, "updated": time.Now()}}
-	err := updateDataByID(id, change)
+	change := withEvent(bson.M{"$set": bson.M{"desc": newdesc, "updated": time.Now()}}, id,
+		model.EventPackTextsChanged, bson.M{"desc": newdesc})
+	err := m.updateDataByID(id, change)
 
 	if err != nil {
 		errmsg := "An error updating a pack description - mongodao"
 		log.Errorf("%s : %v\n", errmsg, err)
-		return fmt.Errorf(errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
 	}
 	return nil
 }
@@ -277,13 +451,14 @@ func (m *MongoDAO) ChangeImg(id string, newimgurl string) error {
 		return errors.New("Invalid pack id and pack image url data")
 	}
 	// create update json map
-	change := bson.M{"$set": bson.M{"imgurl": newimgurl, "updated": time.Now()}}
-	err := updateDataByID(id, change)
+	change := withEvent(bson.M{"$set": bson.M{"imgurl": newimgurl, "updated": time.Now()}}, id,
+		model.EventPackTextsChanged, bson.M{"imgurl": newimgurl})
+	err := m.updateDataByID(id, change)
 
 	if err != nil {
 		errmsg := "An error updating a pack image url - mongodao"
 		log.Errorf("%s : %v\n", errmsg, err)
-		return fmt.Errorf(errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
 	}
 	return nil
 }
@@ -294,13 +469,14 @@ func (m *MongoDAO) ChangeKeyword(id string, newkeyword string) error {
 		return errors.New("Invalid pack id and pack keyword data")
 	}
 	// create update json map
-	change := bson.M{"$set": bson.M{"kwds": newkeyword, "updated": time.Now()}}
-	err := updateDataByID(id, change)
+	change := withEvent(bson.M{"$set": bson.M{"kwds": newkeyword, "updated": time.Now()}}, id,
+		model.EventPackTextsChanged, bson.M{"kwds": newkeyword})
+	err := m.updateDataByID(id, change)
 
 	if err != nil {
 		errmsg := "An error updating a pack keyword - mongodao"
 		log.Errorf("%s : %v\n", errmsg, err)
-		return fmt.Errorf(errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
 	}
 	return nil
 }
@@ -311,13 +487,14 @@ func (m *MongoDAO) ChangePrice(id string, newprice int) error {
 		return errors.New("Invalid pack id and pack price data")
 	}
 	// create update json map
-	change := bson.M{"$set": bson.M{"price": newprice, "updated": time.Now()}}
-	err := updateDataByID(id, change)
+	change := withEvent(bson.M{"$set": bson.M{"price": newprice, "updated": time.Now()}}, id,
+		model.EventPackPriceChanged, bson.M{"price": newprice})
+	err := m.updateDataByID(id, change)
 
 	if err != nil {
 		errmsg := "An error updating a pack type - mongodao"
 		log.Errorf("%s : %v\n", errmsg, err)
-		return fmt.Errorf(errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
 	}
 	return nil
 }
@@ -328,14 +505,15 @@ func (m *MongoDAO) ChangePackType(id string, newtype *model.Type) error {
 		return errors.New("Invalid pack id and pack type data")
 	}
 	// create update json map
-	change := bson.M{"$set": bson.M{"type.id": newtype.ID,
-		"type.name": newtype.Name, "updated": time.Now()}}
-	err := updateDataByID(id, change)
+	change := withEvent(bson.M{"$set": bson.M{"type.id": newtype.ID,
+		"type.name": newtype.Name, "updated": time.Now()}}, id,
+		model.EventPackAttributesChanged, bson.M{"type": newtype})
+	err := m.updateDataByID(id, change)
 
 	if err != nil {
 		errmsg := "An error updating a pack mno - mongodao"
 		log.Errorf("%s : %v\n", errmsg, err)
-		return fmt.Errorf(errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
 	}
 	return nil
 }
@@ -345,15 +523,19 @@ func (m *MongoDAO) ChangeMNO(id string, newmno *model.Mno) error {
 	if id == "" || newmno == nil || newmno.ID < 1 || newmno.Name == "" {
 		return errors.New("Invalid pack id and pack mno data")
 	}
+	if m.scoped && m.tenant != nil && !m.tenant.Admin && m.tenant.MnoID != 0 && m.tenant.MnoID != newmno.ID {
+		return ErrNotInTenant
+	}
 	// create update json map
-	change := bson.M{"$set": bson.M{"mno.id": newmno.ID,
-		"mno.name": newmno.Name, "updated": time.Now()}}
-	err := updateDataByID(id, change)
+	change := withEvent(bson.M{"$set": bson.M{"mno.id": newmno.ID,
+		"mno.name": newmno.Name, "updated": time.Now()}}, id,
+		model.EventPackAttributesChanged, bson.M{"mno": newmno})
+	err := m.updateDataByID(id, change)
 
 	if err != nil {
 		errmsg := "An error updating a pack mno - mongodao"
 		log.Errorf("%s : %v\n", errmsg, err)
-		return fmt.Errorf(errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
 	}
 	return nil
 }
@@ -364,15 +546,16 @@ func (m *MongoDAO) ChangeValidity(id string, newterm *model.Term) error {
 		return errors.New("Invalid pack id and pack validity data")
 	}
 	// create update json map
-	change := bson.M{"$set": bson.M{"term.unit_id": newterm.UnitID,
+	change := withEvent(bson.M{"$set": bson.M{"term.unit_id": newterm.UnitID,
 		"term.unit": newterm.Unit, "term.amount": newterm.Amount,
-		"updated": time.Now()}}
-	err := updateDataByID(id, change)
+		"updated": time.Now()}}, id,
+		model.EventPackAttributesChanged, bson.M{"term": newterm})
+	err := m.updateDataByID(id, change)
 
 	if err != nil {
 		errmsg := "An error updating a pack validity - mongodao"
 		log.Errorf("%s : %v\n", errmsg, err)
-		return fmt.Errorf(errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
 	}
 	return nil
 }
@@ -383,14 +566,15 @@ func (m *MongoDAO) ChangeCurrency(id string, newccy *model.Currency) error {
 		return errors.New("Invalid pack id and pack price currency data")
 	}
 	// create update json map
-	change := bson.M{"$set": bson.M{"currency.id": newccy.ID,
-		"currency.name": newccy.Name, "updated": time.Now()}}
-	err := updateDataByID(id, change)
+	change := withEvent(bson.M{"$set": bson.M{"currency.id": newccy.ID,
+		"currency.name": newccy.Name, "updated": time.Now()}}, id,
+		model.EventPackAttributesChanged, bson.M{"currency": newccy})
+	err := m.updateDataByID(id, change)
 
 	if err != nil {
 		errmsg := "An error updating a pack currency - mongodao"
 		log.Errorf("%s : %v\n", errmsg, err)
-		return fmt.Errorf(errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
 	}
 	return nil
 }
@@ -402,18 +586,47 @@ func (m *MongoDAO) ChangeStock(id string, amount int) error {
 	}
 
 	// increase or descrease update json map
-	change := bson.M{"$inc": bson.M{"stock": amount}}
-	err := updateDataByID(id, change)
+	change := withEvent(bson.M{"$inc": bson.M{"stock": amount}}, id,
+		model.EventStockMoved, bson.M{"amount": amount})
+	err := m.updateDataByID(id, change)
 
 	if err != nil {
-		errmsg := "An error changing pack: %s stock: %d - mongodao: %s\n"
-		log.Errorf(errmsg, id, amount, err.Error())
-		return fmt.Errorf(errmsg, err)
+		errmsg := fmt.Sprintf("An error changing pack: %s stock: %d - mongodao", id, amount)
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
 	}
 
 	return nil
 }
 
+// ReserveStock implements IPackDAO.ReserveStock using mongo driver. The
+// stock check and the decrement are made in one operation.
+func (m *MongoDAO) ReserveStock(id string, amount int) (bool, error) {
+	if !bson.IsObjectIdHex(id) || amount < 1 {
+		return false, errors.New("Invalid pack id or amount to reserve")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(mongoColl)
+
+	filter := bson.M{"_id": bson.ObjectIdHex(id), "stock": bson.M{"$gte": amount}}
+	change := withEvent(bson.M{"$inc": bson.M{"stock": -amount}}, id,
+		model.EventStockMoved, bson.M{"amount": -amount})
+	err := c.Update(m.scope(filter), change)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return false, nil
+		}
+		errmsg := "An error reserving pack stock - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return false, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return true, nil
+}
+
 // UpdateResources implements IPackDAO.UpdateResources.
 func (m *MongoDAO) UpdateResources(id string, newresources []model.Resource) error {
 	if id == "" {
@@ -428,17 +641,59 @@ func (m *MongoDAO) UpdateResources(id string, newresources []model.Resource) err
 		resources = newresources
 	}
 	// create update json map
-	change := bson.M{"$set": bson.M{"resources": resources, "updated": time.Now()}}
-	err := updateDataByID(id, change)
+	change := withEvent(bson.M{"$set": bson.M{"resources": resources, "updated": time.Now()}}, id,
+		model.EventPackResourcesChanged, bson.M{"resources": resources})
+	err := m.updateDataByID(id, change)
 
 	if err != nil {
-		errmsg := fmt.Sprintf("An error updating a pack resources - mongodao: %v", err)
-		log.Errorf(errmsg)
-		return err
+		errmsg := "An error updating a pack resources - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
 	}
 	return nil
 }
 
+// UpdateComponents implements IPackDAO.UpdateComponents.
+func (m *MongoDAO) UpdateComponents(id string, components []string, resources []model.Resource) error {
+	if !bson.IsObjectIdHex(id) || len(components) == 0 {
+		return errors.New("Invalid pack id and components")
+	}
+	if resources == nil {
+		resources = []model.Resource{}
+	}
+	// create update json map
+	change := withEvent(bson.M{"$set": bson.M{"components": components, "resources": resources, "updated": time.Now()}}, id,
+		model.EventPackResourcesChanged, bson.M{"components": components, "resources": resources})
+	err := m.updateDataByID(id, change)
+
+	if err != nil {
+		errmsg := "An error updating a bundle components - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+	return nil
+}
+
+// GetBundlesOf implements IPackDAO.GetBundlesOf.
+func (m *MongoDAO) GetBundlesOf(id string) ([]model.Pack, error) {
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(mongoColl)
+
+	result := []model.Pack{}
+	err := c.Find(m.scope(bson.M{"components": id})).Sort("packcode").All(&result)
+	if err != nil {
+		errmsg := "An error finding the bundles of a pack - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return result, nil
+}
+
 // Delete implements *IPackDAO.Delete.
 func (m *MongoDAO) Delete(id string) error {
 	if id == "" {
@@ -450,22 +705,457 @@ func (m *MongoDAO) Delete(id string) error {
 	// References the mongo collection
 	c := sessionCopy.DB(mongoDB).C(mongoColl)
 
+	// the pack is kept hidden until the relay delivers its events
 	bsonid := bson.ObjectIdHex(id)
-	err := c.Remove(bson.M{"_id": bsonid})
+	change := withEvent(bson.M{"$set": bson.M{"deleted": time.Now()}}, id, model.EventPackDeleted, nil)
+	err := c.Update(m.atVersion(m.scope(bson.M{"_id": bsonid})), change)
+	if err == mgo.ErrNotFound && m.versioned {
+		err = ErrVersionChanged
+	}
 
 	if err != nil {
 		errmsg := "An error deleting a pack - mongodao"
 		log.Errorf("%s : %v\n", errmsg, err)
-		return fmt.Errorf(errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
 	}
 
 	return nil
 }
 
+// ChangeOwner implements *IPackDAO.ChangeOwner.
+func (m *MongoDAO) ChangeOwner(id string, newownerid int) error {
+	if id == "" || newownerid < 1 {
+		return errors.New("Invalid pack id and owner data")
+	}
+	// create update json map
+	change := withEvent(bson.M{"$set": bson.M{"ownerid": newownerid, "updated": time.Now()}}, id,
+		model.EventPackOwnerChanged, bson.M{"ownerid": newownerid})
+	err := m.updateDataByID(id, change)
+
+	if err != nil {
+		errmsg := "An error updating a pack owner - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+	return nil
+}
+
+// GetByOwner implements *IPackDAO.GetByOwner.
+func (m *MongoDAO) GetByOwner(ownerid int) ([]model.Pack, error) {
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(mongoColl)
+
+	result := []model.Pack{}
+	err := c.Find(m.scope(bson.M{"ownerid": ownerid})).Sort("packcode").All(&result)
+	if err != nil {
+		errmsg := "An error finding packs by owner - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return result, nil
+}
+
+// List implements *IPackDAO.List.
+func (m *MongoDAO) List(filter *model.PackFilter, skip int, limit int) ([]model.Pack, error) {
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(mongoColl)
+
+	result := []model.Pack{}
+	err := c.Find(m.scope(packFilter(filter))).Sort("packcode").Skip(skip).Limit(limit).All(&result)
+	if err != nil {
+		errmsg := "An error listing packs - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return result, nil
+}
+
+// Count implements *IPackDAO.Count.
+func (m *MongoDAO) Count(filter *model.PackFilter) (int, error) {
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(mongoColl)
+
+	count, err := c.Find(m.scope(packFilter(filter))).Count()
+	if err != nil {
+		errmsg := "An error counting packs - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return 0, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return count, nil
+}
+
+// Each implements *IPackDAO.Each.
+func (m *MongoDAO) Each(filter *model.PackFilter, fn func(pack *model.Pack) error) error {
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(mongoColl)
+
+	iter := c.Find(m.scope(packFilter(filter))).Sort("packcode").Batch(eachBatchSize).Iter()
+	pack := model.Pack{}
+	for iter.Next(&pack) {
+		err := fn(&pack)
+		if err != nil {
+			iter.Close()
+			return err
+		}
+		pack = model.Pack{}
+	}
+	err := iter.Close()
+	if err != nil {
+		errmsg := "An error reading packs - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+	return nil
+}
+
+// packFilter returns the mongo filter of the conditions of the given filter.
+func packFilter(filter *model.PackFilter) bson.M {
+	query := bson.M{}
+	if filter == nil {
+		return query
+	}
+	if filter.MnoID != 0 {
+		query["mno.id"] = filter.MnoID
+	}
+	if filter.Ownerid != 0 {
+		query["ownerid"] = filter.Ownerid
+	}
+	if filter.TypeID != 0 {
+		query["type.id"] = filter.TypeID
+	}
+	if filter.State != nil {
+		query["state"] = *filter.State
+	}
+	price := bson.M{}
+	if filter.MinPrice != 0 {
+		price["$gte"] = filter.MinPrice
+	}
+	if filter.MaxPrice != 0 {
+		price["$lte"] = filter.MaxPrice
+	}
+	if len(price) > 0 {
+		query["price"] = price
+	}
+	availability := []bson.M{}
+	if filter.Channel != "" {
+		availability = append(availability, availableIn("channels", filter.Channel))
+	}
+	if filter.Region != "" {
+		availability = append(availability, availableIn("regions", filter.Region))
+	}
+	if len(availability) > 0 {
+		query["$and"] = availability
+	}
+	return query
+}
+
+// statsPaths contains the fields of the packs a stats group is read from,
+// the name is kept with the id.
+var statsPaths = map[string]struct{ id, name string }{
+	model.StatsByMno:      {"mno.id", "mno.name"},
+	model.StatsByType:     {"type.id", "type.name"},
+	model.StatsByState:    {"state", ""},
+	model.StatsByOwner:    {"ownerid", ""},
+	model.StatsByCurrency: {"currency.id", "currency.name"},
+}
+
+// statsGroupID contains the values of the fields of a stats group, the
+// ones that are not grouped or not in the pack are 0 as in
+// model.StatsAccumulator.
+type statsGroupID struct {
+	Mno      int8            `bson:"mno"`
+	Type     int8            `bson:"type"`
+	State    model.PackState `bson:"state"`
+	Owner    int             `bson:"owner"`
+	Currency int8            `bson:"currency"`
+}
+
+// statsRow is a row of the stats pipeline.
+type statsRow struct {
+	ID           statsGroupID `bson:"_id"`
+	MnoName      string       `bson:"mnoname"`
+	TypeName     string       `bson:"typename"`
+	CurrencyName string       `bson:"currencyname"`
+	Count        int          `bson:"count"`
+	MinPrice     int          `bson:"minprice"`
+	MaxPrice     int          `bson:"maxprice"`
+	AvgPrice     float64      `bson:"avgprice"`
+	Stock        int          `bson:"stock"`
+}
+
+// statsResourceRow is a row of the resource totals pipeline.
+type statsResourceRow struct {
+	ID struct {
+		Group statsGroupID `bson:"group"`
+		ID    int16        `bson:"id"`
+		Units string       `bson:"units"`
+	} `bson:"_id"`
+	Name  string  `bson:"name"`
+	Total float64 `bson:"total"`
+}
+
+// Stats implements *IPackDAO.Stats with two aggregation pipelines, one for
+// the prices and stock of the groups and one for their resource totals.
+func (m *MongoDAO) Stats(filter *model.PackFilter, groupBy []string) ([]model.CatalogStats, error) {
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(mongoColl)
+
+	match := bson.M{"$match": m.scope(packFilter(filter))}
+	groupid := bson.M{}
+	group := bson.M{
+		"count":    bson.M{"$sum": 1},
+		"minprice": bson.M{"$min": "$price"},
+		"maxprice": bson.M{"$max": "$price"},
+		"avgprice": bson.M{"$avg": "$price"},
+		"stock":    bson.M{"$sum": "$stock"},
+	}
+	for _, field := range groupBy {
+		path, ok := statsPaths[field]
+		if !ok {
+			return nil, errors.New("Invalid stats group " + field)
+		}
+		groupid[field] = bson.M{"$ifNull": []interface{}{"$" + path.id, 0}}
+		if path.name != "" {
+			group[field+"name"] = bson.M{"$first": "$" + path.name}
+		}
+	}
+	group["_id"] = groupid
+
+	rows := []statsRow{}
+	err := c.Pipe([]bson.M{match, {"$group": group}}).All(&rows)
+	if err == nil {
+		resources := []statsResourceRow{}
+		err = c.Pipe([]bson.M{match, {"$unwind": "$resources"}, {"$group": bson.M{
+			"_id":   bson.M{"group": groupid, "id": "$resources.id", "units": "$resources.units"},
+			"name":  bson.M{"$first": "$resources.name"},
+			"total": bson.M{"$sum": "$resources.amount"},
+		}}}).All(&resources)
+		if err == nil {
+			return newCatalogStats(groupBy, rows, resources), nil
+		}
+	}
+	errmsg := "An error aggregating pack stats - mongodao"
+	log.Errorf("%s : %v\n", errmsg, err)
+	return nil, fmt.Errorf("%s: %w", errmsg, err)
+}
+
+// newCatalogStats returns the stats of the rows of the pipelines sorted as
+// model.StatsAccumulator does.
+func newCatalogStats(groupBy []string, rows []statsRow, resources []statsResourceRow) []model.CatalogStats {
+	result := make([]model.CatalogStats, len(rows))
+	groups := map[statsGroupID]*model.CatalogStats{}
+	for i, row := range rows {
+		stats := &result[i]
+		stats.Count, stats.MinPrice, stats.MaxPrice = row.Count, row.MinPrice, row.MaxPrice
+		stats.AvgPrice = math.Round(row.AvgPrice*100) / 100
+		stats.TotalStock = row.Stock
+		stats.Resources = []model.ResourceTotal{}
+		for _, field := range groupBy {
+			switch field {
+			case model.StatsByMno:
+				stats.Mno = &model.Mno{ID: row.ID.Mno, Name: row.MnoName}
+			case model.StatsByType:
+				stats.Packtype = &model.Type{ID: row.ID.Type, Name: row.TypeName}
+			case model.StatsByState:
+				state := row.ID.State
+				stats.State = &state
+			case model.StatsByOwner:
+				ownerid := row.ID.Owner
+				stats.Ownerid = &ownerid
+			case model.StatsByCurrency:
+				stats.Ccy = &model.Currency{ID: row.ID.Currency, Name: row.CurrencyName}
+			}
+		}
+		groups[row.ID] = stats
+	}
+	for _, resource := range resources {
+		if stats, ok := groups[resource.ID.Group]; ok {
+			stats.AddResource(resource.ID.ID, resource.Name, resource.ID.Units, resource.Total)
+		}
+	}
+	model.SortCatalogStats(result)
+	return result
+}
+
+// UpdateTranslations implements *IPackDAO.UpdateTranslations.
+func (m *MongoDAO) UpdateTranslations(id string, translations []model.Translation) error {
+	if !bson.IsObjectIdHex(id) {
+		return errors.New("Invalid pack id")
+	}
+	if translations == nil {
+		translations = []model.Translation{}
+	}
+	// create update json map
+	change := withEvent(bson.M{"$set": bson.M{"translations": translations, "updated": time.Now()}}, id,
+		model.EventPackTextsChanged, bson.M{"translations": translations})
+	err := m.updateDataByID(id, change)
+
+	if err != nil {
+		errmsg := "An error updating a pack translations - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+	return nil
+}
+
+// UpdateRules implements *IPackDAO.UpdateRules.
+func (m *MongoDAO) UpdateRules(id string, rules []model.EligibilityRule) error {
+	if !bson.IsObjectIdHex(id) {
+		return errors.New("Invalid pack id")
+	}
+	if rules == nil {
+		rules = []model.EligibilityRule{}
+	}
+	// create update json map
+	change := withEvent(bson.M{"$set": bson.M{"rules": rules, "updated": time.Now()}}, id,
+		model.EventPackRulesChanged, bson.M{"rules": rules})
+	err := m.updateDataByID(id, change)
+
+	if err != nil {
+		errmsg := "An error updating a pack eligibility rules - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+	return nil
+}
+
+// UpdateAvailability implements *IPackDAO.UpdateAvailability.
+func (m *MongoDAO) UpdateAvailability(id string, channels []string, regions []string) error {
+	if !bson.IsObjectIdHex(id) {
+		return errors.New("Invalid pack id")
+	}
+	if channels == nil {
+		channels = []string{}
+	}
+	if regions == nil {
+		regions = []string{}
+	}
+	// create update json map
+	change := withEvent(bson.M{"$set": bson.M{"channels": channels, "regions": regions, "updated": time.Now()}}, id,
+		model.EventPackAvailabilityChanged, bson.M{"channels": channels, "regions": regions})
+	err := m.updateDataByID(id, change)
+
+	if err != nil {
+		errmsg := "An error updating a pack availability - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+	return nil
+}
+
+// Search implements *IPackDAO.Search using the text index of the packs.
+func (m *MongoDAO) Search(text string, limit int) ([]model.Pack, error) {
+	if text == "" || limit < 1 {
+		return []model.Pack{}, nil
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(mongoColl)
+
+	result := []model.Pack{}
+	score := bson.M{"score": bson.M{"$meta": "textScore"}}
+	err := c.Find(m.scope(bson.M{"$text": bson.M{"$search": text}})).Select(score).Sort("$textScore:score").Limit(limit).All(&result)
+	if err != nil {
+		errmsg := "An error searching packs - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return result, nil
+}
+
+// EnsurePackIndexes creates the indexes of the packs collection. The text
+// index covers the name, description and keywords of every locale, it
+// does not stem words as they are in several languages. The outbox index
+// only has the packs with events to relay.
+func EnsurePackIndexes() error {
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(mongoColl)
+
+	err := c.EnsureIndex(mgo.Index{
+		Key: []string{"$text:name", "$text:desc", "$text:kwds",
+			"$text:translations.name", "$text:translations.desc", "$text:translations.kwds"},
+		Name:            "pack_text",
+		DefaultLanguage: "none",
+		Weights:         map[string]int{"name": 10, "translations.name": 10, "kwds": 5, "translations.kwds": 5},
+	})
+	if err == nil {
+		err = c.EnsureIndex(mgo.Index{Key: []string{"outbox.occurred"}, Name: "pack_outbox", Sparse: true})
+	}
+	if err != nil {
+		errmsg := "An error creating the pack indexes - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+	return nil
+}
+
+// maxUpdateAttempts is how many times Update compares the stored pack
+// with the new data when other changes are written meanwhile.
+const maxUpdateAttempts = 5
+
+// packDocument is a pack as it is stored, with the events of its changes
+// that are not relayed yet.
+type packDocument struct {
+	model.Pack `bson:",inline"`
+	Outbox     []model.Event `bson:"outbox"`
+}
+
+// withEvent adds an event of the given type to the outbox of the pack in
+// the given change, so the change and its event are written at once. The
+// version of the pack is increased with them.
+func withEvent(change bson.M, id string, eventtype string, data bson.M) bson.M {
+	return withEvents(change, []model.Event{model.NewEvent(eventtype, id, data)})
+}
+
+// withEvents adds the events to the outbox of the pack in the given
+// change as withEvent does.
+func withEvents(change bson.M, events []model.Event) bson.M {
+	change["$push"] = bson.M{"outbox": bson.M{"$each": events}}
+	increments, ok := change["$inc"].(bson.M)
+	if !ok {
+		increments = bson.M{}
+		change["$inc"] = increments
+	}
+	increments["version"] = 1
+	return change
+}
+
 // updateDataById update pack with the given parameter map
 // that contains the data to update. Returns error if something
-// goes wrong. id must be hex representation.
-func updateDataByID(id string, change interface{}) error {
+// goes wrong or the pack is not visible to the tenant. id must
+// be hex representation.
+func (m *MongoDAO) updateDataByID(id string, change interface{}) error {
 
 	// make a connection to mongo database
 	sessionCopy := newMgoSession()
@@ -478,10 +1168,13 @@ func updateDataByID(id string, change interface{}) error {
 	bsonid := bson.ObjectIdHex(id)
 
 	//Here the filter is formed
-	colQuerier := bson.M{"_id": bsonid}
+	colQuerier := m.atVersion(m.scope(bson.M{"_id": bsonid}))
 
 	//Here the update is perform
 	err := c.Update(colQuerier, change)
+	if err == mgo.ErrNotFound && m.versioned {
+		return ErrVersionChanged
+	}
 
 	return err
 
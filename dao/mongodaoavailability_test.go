@@ -0,0 +1,75 @@
+package dao_test
+
+import (
+	"testing"
+
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/model"
+)
+
+// TestListByAvailability verify that the packs of a channel or region are
+// the ones with it in their lists and the ones sold everywhere.
+func TestListByAvailability(t *testing.T) {
+	// GIVEN a pack of every channel, one of the app and one of the retail
+	// shops of the north
+	dao.SetDBname("amphora")
+	dao.SetMongoAddrs([]string{"localhost:27017"})
+	dao.SetTimeout(60)
+
+	dao.InitMgoSession()
+	defer dao.CloseMgoSession()
+
+	mongodao := new(dao.MongoDAO)
+
+	ids := []string{}
+	for _, code := range []string{"av50", "av51", "av52"} {
+		err1 := mongodao.Create(newPackData(code, "Availability "+code, code))
+		if err1 != nil {
+			t.Fatalf("Expected err1 to be nil but it was: %s", err1)
+		}
+		id, _ := mongodao.GetIDByCode(code)
+		ids = append(ids, id)
+	}
+	defer func() {
+		for _, id := range ids {
+			mongodao.Delete(id)
+		}
+	}()
+	err2 := mongodao.UpdateAvailability(ids[1], []string{"app"}, nil)
+	if err2 != nil {
+		t.Fatalf("Expected err2 to be nil but it was: %s", err2)
+	}
+	err3 := mongodao.UpdateAvailability(ids[2], []string{"retail"}, []string{"north"})
+	if err3 != nil {
+		t.Fatalf("Expected err3 to be nil but it was: %s", err3)
+	}
+
+	// WHEN we list the packs of the app
+	packs, err4 := mongodao.List(&model.PackFilter{Channel: "app"}, 0, 100)
+
+	// THEN the retail pack is not there
+	if err4 != nil {
+		t.Fatalf("Expected err4 to be nil but it was: %s", err4)
+	}
+	found := map[string]bool{}
+	for _, pack := range packs {
+		found[pack.Packcode] = true
+	}
+	if !found["av50"] || !found["av51"] || found["av52"] {
+		t.Errorf("Expected av50 and av51 but got %+v", found)
+	}
+
+	// WHEN a reseller of the retail shops lists the packs of the south
+	scoped := mongodao.Scoped(&model.Tenant{Channel: "retail"})
+	packs, err5 := scoped.List(&model.PackFilter{Region: "south"}, 0, 100)
+
+	// THEN only the pack sold everywhere is there
+	if err5 != nil {
+		t.Fatalf("Expected err5 to be nil but it was: %s", err5)
+	}
+	for _, pack := range packs {
+		if pack.Packcode == "av51" || pack.Packcode == "av52" {
+			t.Errorf("Expected no pack of the app or the north but got %s", pack.Packcode)
+		}
+	}
+}
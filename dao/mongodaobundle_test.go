@@ -0,0 +1,71 @@
+package dao_test
+
+import (
+	"testing"
+
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/model"
+)
+
+// TestGetBundlesOf verify that the bundles of a component are found and
+// their components and resources are replaced.
+func TestGetBundlesOf(t *testing.T) {
+	// GIVEN a bundle of two packs
+	dao.SetDBname("amphora")
+	dao.SetMongoAddrs([]string{"localhost:27017"})
+	dao.SetTimeout(60)
+
+	dao.InitMgoSession()
+	defer dao.CloseMgoSession()
+
+	mongodao := new(dao.MongoDAO)
+
+	for _, code := range []string{"bd40", "bd41"} {
+		err1 := mongodao.Create(newPackData(code, "Bundle component "+code, code))
+		if err1 != nil {
+			t.Fatalf("Expected err1 to be nil but it was: %s", err1)
+		}
+	}
+	dataid, _ := mongodao.GetIDByCode("bd40")
+	whatsappid, _ := mongodao.GetIDByCode("bd41")
+	bundle := newPackData("bd42", "Combo bd42", "bd42")
+	bundle.Components = []string{dataid, whatsappid}
+	err2 := mongodao.Create(bundle)
+
+	if err2 != nil {
+		t.Fatalf("Expected err2 to be nil but it was: %s", err2)
+	}
+	bundleid, _ := mongodao.GetIDByCode("bd42")
+
+	// WHEN we search the bundles of a component
+	bundles, err3 := mongodao.GetBundlesOf(whatsappid)
+
+	// THEN the bundle is found
+	if err3 != nil {
+		t.Fatalf("Expected err3 to be nil but it was: %s", err3)
+	}
+	if len(bundles) != 1 || bundles[0].ID.Hex() != bundleid {
+		t.Fatalf("Expected bundle bd42 but got %+v", bundles)
+	}
+
+	// WHEN the bundle only keeps the data pack
+	resources := []model.Resource{{ID: 1, Name: "data", Units: "MB", Amount: 500}}
+	err4 := mongodao.UpdateComponents(bundleid, []string{dataid}, resources)
+
+	// THEN it is not a bundle of the whatsapp pack anymore
+	if err4 != nil {
+		t.Fatalf("Expected err4 to be nil but it was: %s", err4)
+	}
+	bundles, _ = mongodao.GetBundlesOf(whatsappid)
+	if len(bundles) != 0 {
+		t.Errorf("Expected no bundles of the whatsapp pack but got %+v", bundles)
+	}
+	stored, _ := mongodao.GetByID(bundleid)
+	if stored == nil || len(stored.Resources) != 1 || stored.Resources[0].Amount != 500 {
+		t.Errorf("Expected the new resources of the bundle but got %+v", stored)
+	}
+
+	for _, id := range []string{dataid, whatsappid, bundleid} {
+		mongodao.Delete(id)
+	}
+}
@@ -0,0 +1,87 @@
+package dao_test
+
+import (
+	"testing"
+
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/model"
+)
+
+// pendingOf returns the types of the pending events of the pack.
+func pendingOf(t *testing.T, outbox *dao.MongoOutboxDAO, packid string) ([]model.Event, []string) {
+	pending, err := outbox.Pending(1000)
+	if err != nil {
+		t.Fatalf("Expected err to be nil but it was: %s", err)
+	}
+	events, types := []model.Event{}, []string{}
+	for _, event := range pending {
+		if event.PackID == packid {
+			events = append(events, event)
+			types = append(types, event.Type)
+		}
+	}
+	return events, types
+}
+
+// TestOutbox verify that changes write their events in the outbox of the
+// pack and a deleted pack is kept until its events are delivered.
+func TestOutbox(t *testing.T) {
+	// GIVEN a new pack whose price is changed
+	dao.SetDBname("amphora")
+	dao.SetMongoAddrs([]string{"localhost:27017"})
+	dao.SetTimeout(60)
+
+	dao.InitMgoSession()
+	defer dao.CloseMgoSession()
+
+	mongodao := new(dao.MongoDAO)
+	outbox := new(dao.MongoOutboxDAO)
+
+	err1 := mongodao.Create(newPackData("ev70", "Events ev70", "ev70"))
+	if err1 != nil {
+		t.Fatalf("Expected err1 to be nil but it was: %s", err1)
+	}
+	id, _ := mongodao.GetIDByCode("ev70")
+	err2 := mongodao.ChangePrice(id, 3000)
+	if err2 != nil {
+		t.Fatalf("Expected err2 to be nil but it was: %s", err2)
+	}
+
+	// WHEN we read the pending events
+	events, types := pendingOf(t, outbox, id)
+
+	// THEN they are the creation and the price change in order
+	if len(types) != 2 || types[0] != model.EventPackCreated || types[1] != model.EventPackPriceChanged {
+		t.Fatalf("Expected PackCreated and PackPriceChanged but got %v", types)
+	}
+	if events[1].Pack == nil || events[1].Pack.Price != 3000 {
+		t.Errorf("Expected the pack with the new price but got %+v", events[1].Pack)
+	}
+
+	// AND WHEN they are delivered and the pack is deleted
+	err3 := outbox.Delivered(events)
+	if err3 != nil {
+		t.Fatalf("Expected err3 to be nil but it was: %s", err3)
+	}
+	err4 := mongodao.Delete(id)
+	if err4 != nil {
+		t.Fatalf("Expected err4 to be nil but it was: %s", err4)
+	}
+
+	// THEN the pack is not found but its deletion is pending
+	pack, _ := mongodao.GetByID(id)
+	if pack != nil {
+		t.Errorf("Expected the deleted pack not to be found but got %+v", pack)
+	}
+	events, types = pendingOf(t, outbox, id)
+	if len(types) != 1 || types[0] != model.EventPackDeleted {
+		t.Fatalf("Expected PackDeleted but got %v", types)
+	}
+	err5 := outbox.Delivered(events)
+	if err5 != nil {
+		t.Fatalf("Expected err5 to be nil but it was: %s", err5)
+	}
+	if _, types = pendingOf(t, outbox, id); len(types) != 0 {
+		t.Errorf("Expected no events left but got %v", types)
+	}
+}
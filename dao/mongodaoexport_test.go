@@ -0,0 +1,64 @@
+package dao_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/model"
+)
+
+// TestListAndEach verify that packs are listed and read one by one by filter.
+func TestListAndEach(t *testing.T) {
+	// GIVEN two packs of the type 37
+	dao.SetDBname("amphora")
+	dao.SetMongoAddrs([]string{"localhost:27017"})
+	dao.SetTimeout(60)
+
+	dao.InitMgoSession()
+	defer dao.CloseMgoSession()
+
+	mongodao := new(dao.MongoDAO)
+	for _, code := range []string{"ex37b", "ex37a"} {
+		newpack := newPackData(code, "Export "+code, code)
+		newpack.Packtype = &model.Type{ID: 37, Name: "Export"}
+		err1 := mongodao.Create(newpack)
+		if err1 != nil {
+			t.Fatalf("Expected err1 to be nil but it was: %s", err1)
+		}
+	}
+	filter := &model.PackFilter{TypeID: 37, MinPrice: 2500}
+
+	// WHEN we list them
+	packs, err2 := mongodao.List(filter, 0, 10)
+
+	// THEN they are sorted by pack code
+	if err2 != nil {
+		t.Fatalf("Expected err2 to be nil but it was: %s", err2)
+	}
+	if len(packs) < 2 || packs[0].Packcode != "ex37a" {
+		t.Fatalf("Expected the packs of the type sorted by code but got %+v", packs)
+	}
+
+	// AND each one is read
+	read := 0
+	err3 := mongodao.Each(filter, func(pack *model.Pack) error {
+		if pack.Packtype.ID != 37 {
+			t.Fatalf("Expected packs of the type 37 but got %+v", pack.Packtype)
+		}
+		read++
+		return nil
+	})
+	if err3 != nil || read != len(packs) {
+		t.Fatalf("Expected %d packs to be read but got %d: %v", len(packs), read, err3)
+	}
+
+	// AND the reading stops at the first error
+	stop := errors.New("stop")
+	err4 := mongodao.Each(filter, func(pack *model.Pack) error {
+		return stop
+	})
+	if err4 != stop {
+		t.Fatalf("Expected err4 to be the error of fn but it was: %v", err4)
+	}
+}
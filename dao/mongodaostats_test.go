@@ -0,0 +1,54 @@
+package dao_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/model"
+)
+
+// TestStats verify that the aggregation pipelines return the same stats
+// as a StatsAccumulator fed with every pack.
+func TestStats(t *testing.T) {
+	// GIVEN three packs of different prices
+	dao.SetDBname("amphora")
+	dao.SetMongoAddrs([]string{"localhost:27017"})
+	dao.SetTimeout(60)
+
+	dao.InitMgoSession()
+	defer dao.CloseMgoSession()
+
+	mongodao := new(dao.MongoDAO)
+
+	for i, code := range []string{"st60", "st61", "st62"} {
+		pack := newPackData(code, "Stats "+code, code)
+		pack.Price = 1000 * (i + 1)
+		err1 := mongodao.Create(pack)
+		if err1 != nil {
+			t.Fatalf("Expected err1 to be nil but it was: %s", err1)
+		}
+		id, _ := mongodao.GetIDByCode(code)
+		defer mongodao.Delete(id)
+	}
+	groupBy := []string{model.StatsByMno, model.StatsByState, model.StatsByCurrency}
+
+	// WHEN we get the stats of the catalog
+	stats, err2 := mongodao.Stats(nil, groupBy)
+
+	// THEN they are the ones of every pack added one by one
+	if err2 != nil {
+		t.Fatalf("Expected err2 to be nil but it was: %s", err2)
+	}
+	accumulator := model.NewStatsAccumulator(groupBy)
+	err3 := mongodao.Each(nil, func(pack *model.Pack) error {
+		accumulator.Add(pack)
+		return nil
+	})
+	if err3 != nil {
+		t.Fatalf("Expected err3 to be nil but it was: %s", err3)
+	}
+	if want := accumulator.Stats(); !reflect.DeepEqual(stats, want) {
+		t.Errorf("Expected %+v but got %+v", want, stats)
+	}
+}
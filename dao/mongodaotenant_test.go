@@ -0,0 +1,204 @@
+package dao_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/model"
+)
+
+// TestScopedLookupsDoNotLeak verify that a tenant cannot read packs of
+// another tenant with any lookup.
+func TestScopedLookupsDoNotLeak(t *testing.T) {
+	// GIVEN a pack of owner 31 in mno 2
+	dao.SetDBname("amphora")
+	dao.SetMongoAddrs([]string{"localhost:27017"})
+	dao.SetTimeout(60)
+
+	dao.InitMgoSession()
+	defer dao.CloseMgoSession()
+
+	mongodao := new(dao.MongoDAO)
+
+	newpack := newPackData("ten31", "Tenant pack", "31")
+	newpack.Ownerid = 31
+	err1 := mongodao.Create(newpack)
+
+	if err1 != nil {
+		t.Fatalf("Expected err1 to be nil but it was: %s", err1)
+	}
+	packid, _ := mongodao.GetIDByCode("ten31")
+
+	others := map[string]*model.Tenant{
+		"other owner":        {Ownerid: 32},
+		"other mno":          {MnoID: 5},
+		"owner in other mno": {Ownerid: 31, MnoID: 5},
+		"empty tenant":       {},
+		"no tenant":          nil,
+	}
+	for name, tenant := range others {
+		t.Run(name, func(t *testing.T) {
+			// WHEN another tenant looks for the pack
+			scoped := mongodao.Scoped(tenant)
+
+			// THEN every lookup misses it
+			if pack, err := scoped.GetByID(packid); err != nil || pack != nil {
+				t.Errorf("GetByID leaked %+v, %v", pack, err)
+			}
+			if pack, err := scoped.GetByPackCode("ten31"); err != nil || pack != nil {
+				t.Errorf("GetByPackCode leaked %+v, %v", pack, err)
+			}
+			if pack, err := scoped.GetByProductID("31"); err != nil || pack != nil {
+				t.Errorf("GetByProductID leaked %+v, %v", pack, err)
+			}
+			if id, err := scoped.GetIDByCode("ten31"); err != nil || id != "" {
+				t.Errorf("GetIDByCode leaked %s, %v", id, err)
+			}
+			exists := &model.PackExists{MnoID: 2, Packcode: "ten31"}
+			if found, err := scoped.IsThereThisPack(exists); err != nil || found {
+				t.Errorf("IsThereThisPack leaked %v, %v", found, err)
+			}
+			if packs, err := scoped.GetByOwner(31); err != nil || len(packs) != 0 {
+				t.Errorf("GetByOwner leaked %d packs, %v", len(packs), err)
+			}
+
+			// AND it cannot change or delete it
+			if err := scoped.ChangeName(packid, "stolen"); err == nil {
+				t.Errorf("ChangeName changed a pack of another tenant")
+			}
+			if err := scoped.ChangeStock(packid, 10); err == nil {
+				t.Errorf("ChangeStock changed a pack of another tenant")
+			}
+			if err := scoped.Delete(packid); err == nil {
+				t.Errorf("Delete removed a pack of another tenant")
+			}
+		})
+	}
+
+	// AND the owner and the platform admin can read it
+	for _, tenant := range []*model.Tenant{{Ownerid: 31}, {MnoID: 2}, {Admin: true}} {
+		pack, err := mongodao.Scoped(tenant).GetByID(packid)
+		if err != nil || pack == nil {
+			t.Fatalf("Expected tenant %+v to read the pack but got %v", tenant, err)
+		}
+	}
+}
+
+// TestScopedCreate verify that a tenant can only create its own packs.
+func TestScopedCreate(t *testing.T) {
+	dao.SetDBname("amphora")
+	dao.SetMongoAddrs([]string{"localhost:27017"})
+	dao.SetTimeout(60)
+
+	dao.InitMgoSession()
+	defer dao.CloseMgoSession()
+
+	scoped := new(dao.MongoDAO).Scoped(&model.Tenant{Ownerid: 33})
+
+	newpack := newPackData("ten33", "Tenant pack", "33")
+	newpack.Ownerid = 34
+	err1 := scoped.Create(newpack)
+
+	if err1 == nil {
+		t.Fatalf("Expected an error creating a pack of another tenant but it was nil")
+	}
+}
+
+// TestScopedSellableLookupsDoNotLeak verify that a seller only finds the
+// packs of its tenant that are sold in its channel.
+func TestScopedSellableLookupsDoNotLeak(t *testing.T) {
+	// GIVEN a pack of owner 35 in mno 2 sold in the app
+	dao.SetDBname("amphora")
+	dao.SetMongoAddrs([]string{"localhost:27017"})
+	dao.SetTimeout(60)
+
+	dao.InitMgoSession()
+	defer dao.CloseMgoSession()
+
+	mongodao := new(dao.MongoDAO)
+
+	newpack := newPackData("ten35", "Tenant pack", "35")
+	newpack.Ownerid = 35
+	err1 := mongodao.Create(newpack)
+
+	if err1 != nil {
+		t.Fatalf("Expected err1 to be nil but it was: %s", err1)
+	}
+	packid, _ := mongodao.GetIDByCode("ten35")
+	defer mongodao.Delete(packid)
+	err2 := mongodao.UpdateAvailability(packid, []string{"app"}, nil)
+
+	if err2 != nil {
+		t.Fatalf("Expected err2 to be nil but it was: %s", err2)
+	}
+
+	others := map[string]*model.Tenant{
+		"other channel":          {Channel: "retail"},
+		"other owner in channel": {Ownerid: 36, Channel: "app"},
+		"owner in other channel": {Ownerid: 35, Channel: "retail"},
+		"other mno in channel":   {MnoID: 5, Channel: "app"},
+	}
+	for name, tenant := range others {
+		t.Run(name, func(t *testing.T) {
+			// WHEN a seller of another tenant or channel looks for the pack
+			pack, err := mongodao.Scoped(tenant).GetByID(packid)
+
+			// THEN it is not found
+			if err != nil || pack != nil {
+				t.Errorf("GetByID leaked %+v, %v", pack, err)
+			}
+		})
+	}
+
+	// AND the sellers of the owner and the app find it
+	for _, tenant := range []*model.Tenant{{Channel: "app"}, {Ownerid: 35, Channel: "app"}, {Ownerid: 35}, {Admin: true}} {
+		pack, err := mongodao.Scoped(tenant).GetByID(packid)
+		if err != nil || pack == nil {
+			t.Fatalf("Expected tenant %+v to find the pack but got %v", tenant, err)
+		}
+	}
+}
+
+// TestSalesKeepTheirTenant verify that the entitlements read back keep
+// the owner and the mno of their pack, so other tenants do not see them.
+func TestSalesKeepTheirTenant(t *testing.T) {
+	// GIVEN an entitlement of a pack of owner 35 in mno 2
+	dao.SetDBname("amphora")
+	dao.SetMongoAddrs([]string{"localhost:27017"})
+	dao.SetTimeout(60)
+
+	dao.InitMgoSession()
+	defer dao.CloseMgoSession()
+
+	entitlementdao := new(dao.MongoEntitlementDAO)
+
+	msisdn := "573000000035"
+	newentitlement := newEntitlementData(msisdn, time.Now())
+	newentitlement.Ownerid = 35
+	err1 := entitlementdao.Create(newentitlement)
+
+	if err1 != nil {
+		t.Fatalf("Expected err1 to be nil but it was: %s", err1)
+	}
+
+	// WHEN we read the entitlements of the subscriber
+	entitlements, err2 := entitlementdao.GetByMsisdn(msisdn, true)
+
+	// THEN only its owner, its mno and the platform admin own them
+	if err2 != nil || len(entitlements) == 0 {
+		t.Fatalf("Expected the entitlements but got %d, %v", len(entitlements), err2)
+	}
+	for _, entitlement := range entitlements {
+		for _, tenant := range []*model.Tenant{{Ownerid: 36}, {MnoID: 5}, {Channel: "app"}, {}} {
+			if tenant.OwnsSale(entitlement.Ownerid, entitlement.MnoID) {
+				t.Errorf("Expected tenant %+v not to own the entitlement %s", tenant, entitlement.ID.Hex())
+			}
+		}
+		for _, tenant := range []*model.Tenant{{Ownerid: 35}, {MnoID: 2}, {Admin: true}} {
+			if !tenant.OwnsSale(entitlement.Ownerid, entitlement.MnoID) {
+				t.Errorf("Expected tenant %+v to own the entitlement %s", tenant, entitlement.ID.Hex())
+			}
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package dao_test
+
+import (
+	"testing"
+
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/model"
+)
+
+// TestSearchEveryLocale verify that packs are found by the texts of any
+// of their translations.
+func TestSearchEveryLocale(t *testing.T) {
+	// GIVEN a spanish pack translated to english
+	dao.SetDBname("amphora")
+	dao.SetMongoAddrs([]string{"localhost:27017"})
+	dao.SetTimeout(60)
+
+	dao.InitMgoSession()
+	defer dao.CloseMgoSession()
+
+	err1 := dao.EnsurePackIndexes()
+
+	if err1 != nil {
+		t.Fatalf("Expected err1 to be nil but it was: %s", err1)
+	}
+
+	mongodao := new(dao.MongoDAO)
+
+	newpack := newPackData("tr35", "Navegacion ilimitada tr35", "35")
+	newpack.Kwds = "internet navegacion ilimitada"
+	err2 := mongodao.Create(newpack)
+
+	if err2 != nil {
+		t.Fatalf("Expected err2 to be nil but it was: %s", err2)
+	}
+	packid, _ := mongodao.GetIDByCode("tr35")
+
+	translations := []model.Translation{
+		{Locale: "en", Name: "Unlimited browsing tr35", Desc: "Browse without limits", Kwds: "internet browsing unlimited"},
+	}
+	err3 := mongodao.UpdateTranslations(packid, translations)
+
+	if err3 != nil {
+		t.Fatalf("Expected err3 to be nil but it was: %s", err3)
+	}
+
+	for _, text := range []string{"navegacion", "browsing"} {
+		// WHEN we search in every language
+		packs, err4 := mongodao.Search(text, 50)
+
+		// THEN the pack is found
+		if err4 != nil {
+			t.Fatalf("Expected err4 to be nil but it was: %s", err4)
+		}
+		found := false
+		for _, pack := range packs {
+			if pack.ID.Hex() == packid {
+				found = true
+				if pack.Translation("en") == nil {
+					t.Fatalf("Expected the english translation to be stored but got %+v", pack.Translations)
+				}
+			}
+		}
+		if !found {
+			t.Fatalf("Expected pack %s to be found searching %q", packid, text)
+		}
+	}
+
+	// AND other tenants do not find it
+	packs, err5 := mongodao.Scoped(&model.Tenant{Ownerid: 36}).Search("browsing", 50)
+
+	if err5 != nil {
+		t.Fatalf("Expected err5 to be nil but it was: %s", err5)
+	}
+	for _, pack := range packs {
+		if pack.ID.Hex() == packid {
+			t.Fatalf("Expected pack %s not to be found by another tenant", packid)
+		}
+	}
+}
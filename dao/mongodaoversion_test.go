@@ -0,0 +1,55 @@
+package dao_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/fernandoocampo/pack/dao"
+)
+
+// TestChangesAtVersion verify that every change increases the version of
+// the pack and a change at an old version is rejected.
+func TestChangesAtVersion(t *testing.T) {
+	// GIVEN a new pack at version 1
+	dao.SetDBname("amphora")
+	dao.SetMongoAddrs([]string{"localhost:27017"})
+	dao.SetTimeout(60)
+
+	dao.InitMgoSession()
+	defer dao.CloseMgoSession()
+
+	mongodao := new(dao.MongoDAO)
+
+	newpack := newPackData("ver01", "Versioned pack", "ver01")
+	err1 := mongodao.Create(newpack)
+
+	if err1 != nil {
+		t.Fatalf("Expected err1 to be nil but it was: %s", err1)
+	}
+	packid := newpack.ID.Hex()
+	defer mongodao.Delete(packid)
+
+	// WHEN it is changed at version 1 and then again at version 1
+	err2 := mongodao.AtVersion(1).ChangeName(packid, "Versioned pack 2")
+	err3 := mongodao.AtVersion(1).ChangePrice(packid, 9000)
+
+	// THEN the first change is applied and the second one is rejected
+	if err2 != nil {
+		t.Fatalf("Expected err2 to be nil but it was: %s", err2)
+	}
+	if !errors.Is(err3, dao.ErrVersionChanged) {
+		t.Fatalf("Expected a change at an old version to be rejected but got %v", err3)
+	}
+	pack, _ := mongodao.GetByID(packid)
+	if pack.Version != 2 || pack.Name != "Versioned pack 2" || pack.Price == 9000 {
+		t.Errorf("Expected only the first change at version 2 but got %+v", pack)
+	}
+	err4 := mongodao.AtVersion(2).ChangeStock(packid, 5)
+	if err4 != nil {
+		t.Fatalf("Expected err4 to be nil but it was: %s", err4)
+	}
+	pack, _ = mongodao.GetByID(packid)
+	if pack.Version != 3 || pack.Stock != 5 {
+		t.Errorf("Expected the stock move at version 3 but got %+v", pack)
+	}
+}
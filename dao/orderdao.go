@@ -0,0 +1,36 @@
+package dao
+
+import (
+	"time"
+
+	"github.com/fernandoocampo/pack/model"
+)
+
+// IOrderDAO defines data access behavior for pack orders.
+type IOrderDAO interface {
+	// Create inserts a new order.
+	Create(order *model.Order) error
+	// GetByID search an order with the given id and return it.
+	GetByID(id string) (*model.Order, error)
+	// GetByMsisdn returns the orders of the given subscriber, the
+	// newest first.
+	GetByMsisdn(msisdn string) ([]model.Order, error)
+	// GetPending returns a pending order of the given kind that sells the
+	// pack to the subscriber, nil if there is none.
+	GetPending(msisdn string, packid string, kind model.OrderKind) (*model.Order, error)
+	// Update stores the provisioning data, state and refund of the order.
+	Update(order *model.Order) error
+	// ClaimDue returns a pending order whose next attempt is due at the
+	// given time and reserves it for the given lease, nil if there is none.
+	ClaimDue(now time.Time, lease time.Duration) (*model.Order, error)
+	// GetForSettlement returns the orders of the owner sold or refunded
+	// in the period [from, to).
+	GetForSettlement(ownerid int, from time.Time, to time.Time) ([]model.Order, error)
+	// GetOwners returns the owners with orders sold or refunded in the
+	// period [from, to).
+	GetOwners(from time.Time, to time.Time) ([]int, error)
+	// EachForReport calls fn with every order of the query sold or
+	// refunded in its period, the oldest first. It stops at the first
+	// error of fn and returns it.
+	EachForReport(query *model.SalesReportQuery, fn func(order *model.Order) error) error
+}
@@ -0,0 +1,287 @@
+package dao
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fernandoocampo/pack/model"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// orderColl is the mongo collection name for orders
+const orderColl = "orders"
+
+// MongoOrderDAO implements IOrderDAO using mongo.
+type MongoOrderDAO struct {
+}
+
+// Create implements IOrderDAO.Create.
+func (m *MongoOrderDAO) Create(order *model.Order) error {
+	if order == nil {
+		return errors.New("Invalid order data")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(orderColl)
+
+	if order.ID == "" {
+		order.ID = bson.NewObjectId()
+	}
+	err := c.Insert(order)
+	if err != nil {
+		errmsg := "An error on order creation - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return nil
+}
+
+// GetByID implements IOrderDAO.GetByID.
+func (m *MongoOrderDAO) GetByID(id string) (*model.Order, error) {
+	if !bson.IsObjectIdHex(id) {
+		return nil, errors.New("Invalid order id")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(orderColl)
+
+	result := model.Order{}
+	err := c.FindId(bson.ObjectIdHex(id)).One(&result)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return nil, nil
+		}
+		errmsg := "An error finding an order by id - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return &result, nil
+}
+
+// GetByMsisdn implements IOrderDAO.GetByMsisdn.
+func (m *MongoOrderDAO) GetByMsisdn(msisdn string) ([]model.Order, error) {
+	if msisdn == "" {
+		return nil, errors.New("Invalid msisdn")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(orderColl)
+
+	result := []model.Order{}
+	err := c.Find(bson.M{"msisdn": msisdn}).Sort("-created").All(&result)
+	if err != nil {
+		errmsg := "An error finding orders by msisdn - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return result, nil
+}
+
+// GetPending implements IOrderDAO.GetPending.
+func (m *MongoOrderDAO) GetPending(msisdn string, packid string, kind model.OrderKind) (*model.Order, error) {
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(orderColl)
+
+	filter := bson.M{"msisdn": msisdn, "packid": packid, "kind": kind, "state": model.OrderPending}
+	result := model.Order{}
+	err := c.Find(filter).One(&result)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return nil, nil
+		}
+		errmsg := "An error finding a pending order - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return &result, nil
+}
+
+// Update implements IOrderDAO.Update.
+func (m *MongoOrderDAO) Update(order *model.Order) error {
+	if order == nil || order.ID == "" {
+		return errors.New("Invalid order data")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(orderColl)
+
+	change := bson.M{"$set": bson.M{
+		"reference":     order.Reference,
+		"entitlementid": order.EntitlementID,
+		"attempts":      order.Attempts,
+		"lasterror":     order.LastError,
+		"nextattempt":   order.NextAttempt,
+		"state":         order.State,
+		"refunded":      order.Refunded,
+		"refundreason":  order.RefundReason,
+		"updated":       time.Now(),
+	}}
+	err := c.UpdateId(order.ID, change)
+	if err != nil {
+		errmsg := "An error updating an order - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return nil
+}
+
+// ClaimDue implements IOrderDAO.ClaimDue. Orders being placed have no
+// next attempt, so only the orders left to retry are claimed.
+func (m *MongoOrderDAO) ClaimDue(now time.Time, lease time.Duration) (*model.Order, error) {
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(orderColl)
+
+	filter := bson.M{"state": model.OrderPending, "nextattempt": bson.M{"$lte": now}}
+	change := mgo.Change{
+		Update: bson.M{"$set": bson.M{"nextattempt": now.Add(lease)}},
+	}
+	result := model.Order{}
+	_, err := c.Find(filter).Sort("nextattempt").Apply(change, &result)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return nil, nil
+		}
+		errmsg := "An error claiming a due order - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return &result, nil
+}
+
+// EnsureOrderIndexes creates the indexes used to find and claim the
+// pending orders.
+func EnsureOrderIndexes() error {
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(orderColl)
+
+	err := c.EnsureIndex(mgo.Index{Key: []string{"state", "nextattempt"}, Name: "order_due"})
+	if err == nil {
+		err = c.EnsureIndex(mgo.Index{Key: []string{"msisdn", "packid", "state"}, Name: "order_pending"})
+	}
+	if err != nil {
+		errmsg := "An error creating the order indexes - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+	return nil
+}
+
+// GetForSettlement implements IOrderDAO.GetForSettlement.
+func (m *MongoOrderDAO) GetForSettlement(ownerid int, from time.Time, to time.Time) ([]model.Order, error) {
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(orderColl)
+
+	query := settlementQuery(from, to)
+	query["ownerid"] = ownerid
+
+	result := []model.Order{}
+	err := c.Find(query).Sort("created").All(&result)
+	if err != nil {
+		errmsg := "An error finding orders to settle - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return result, nil
+}
+
+// GetOwners implements IOrderDAO.GetOwners.
+func (m *MongoOrderDAO) GetOwners(from time.Time, to time.Time) ([]int, error) {
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(orderColl)
+
+	result := []int{}
+	err := c.Find(settlementQuery(from, to)).Distinct("ownerid", &result)
+	if err != nil {
+		errmsg := "An error finding owners to settle - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return result, nil
+}
+
+// EachForReport implements IOrderDAO.EachForReport.
+func (m *MongoOrderDAO) EachForReport(query *model.SalesReportQuery, fn func(order *model.Order) error) error {
+	if query == nil {
+		return errors.New("Invalid report query")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(orderColl)
+
+	filter := settlementQuery(query.From, query.To)
+	if query.Ownerid != 0 {
+		filter["ownerid"] = query.Ownerid
+	}
+	if query.MnoID != 0 {
+		filter["mnoid"] = query.MnoID
+	}
+	if query.PackID != "" {
+		filter["packid"] = query.PackID
+	}
+	iter := c.Find(filter).Sort("created").Batch(eachBatchSize).Iter()
+	order := model.Order{}
+	for iter.Next(&order) {
+		err := fn(&order)
+		if err != nil {
+			iter.Close()
+			return err
+		}
+		order = model.Order{}
+	}
+	err := iter.Close()
+	if err != nil {
+		errmsg := "An error reading orders to report - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+	return nil
+}
+
+// settlementQuery selects the orders sold or refunded in [from, to).
+func settlementQuery(from time.Time, to time.Time) bson.M {
+	return bson.M{"$or": []bson.M{
+		{
+			"state":   bson.M{"$in": []model.OrderState{model.OrderCompleted, model.OrderRefunded}},
+			"created": bson.M{"$gte": from, "$lt": to},
+		},
+		{
+			"state":    model.OrderRefunded,
+			"refunded": bson.M{"$gte": from, "$lt": to},
+		},
+	}}
+}
@@ -0,0 +1,15 @@
+package dao
+
+import "github.com/fernandoocampo/pack/model"
+
+// IOutboxDAO defines data access behavior for the events of the pack
+// changes waiting to be relayed.
+type IOutboxDAO interface {
+	// Pending returns the events of at most limit packs, the events of a
+	// pack in the order they were made. Every event has the pack as it
+	// is now.
+	Pending(limit int) ([]model.Event, error)
+	// Delivered removes the given events from the outbox, deleted packs
+	// are removed when they have no events left.
+	Delivered(events []model.Event) error
+}
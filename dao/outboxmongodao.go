@@ -0,0 +1,76 @@
+package dao
+
+import (
+	"fmt"
+
+	"github.com/fernandoocampo/pack/model"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// MongoOutboxDAO implements IOutboxDAO using the outbox of every pack
+// document in mongo.
+type MongoOutboxDAO struct {
+}
+
+// Pending implements IOutboxDAO.Pending.
+func (m *MongoOutboxDAO) Pending(limit int) ([]model.Event, error) {
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(mongoColl)
+
+	documents := []packDocument{}
+	err := c.Find(bson.M{"outbox.occurred": bson.M{"$exists": true}}).Limit(limit).All(&documents)
+	if err != nil {
+		errmsg := "An error finding the events to relay - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	result := []model.Event{}
+	for i := range documents {
+		for _, event := range documents[i].Outbox {
+			event.Pack = &documents[i].Pack
+			result = append(result, event)
+		}
+	}
+	return result, nil
+}
+
+// Delivered implements IOutboxDAO.Delivered.
+func (m *MongoOutboxDAO) Delivered(events []model.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(mongoColl)
+
+	delivered := map[string][]bson.ObjectId{}
+	for _, event := range events {
+		delivered[event.PackID] = append(delivered[event.PackID], event.ID)
+	}
+	for packid, ids := range delivered {
+		if !bson.IsObjectIdHex(packid) {
+			continue
+		}
+		change := bson.M{"$pull": bson.M{"outbox": bson.M{"id": bson.M{"$in": ids}}}}
+		err := c.UpdateId(bson.ObjectIdHex(packid), change)
+		if err != nil && err != mgo.ErrNotFound {
+			errmsg := "An error removing relayed events - mongodao"
+			log.Errorf("%s : %v\n", errmsg, err)
+			return fmt.Errorf("%s: %w", errmsg, err)
+		}
+	}
+	_, err := c.RemoveAll(bson.M{"deleted": bson.M{"$exists": true}, "outbox": bson.M{"$size": 0}})
+	if err != nil {
+		errmsg := "An error removing deleted packs - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+	return nil
+}
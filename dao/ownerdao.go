@@ -0,0 +1,17 @@
+package dao
+
+import "github.com/fernandoocampo/pack/model"
+
+// IOwnerDAO defines data access behavior for pack owners.
+type IOwnerDAO interface {
+	// Create inserts a new owner.
+	Create(owner *model.Owner) error
+	// GetByID search an owner with the given id and return it.
+	GetByID(id int) (*model.Owner, error)
+	// GetAll returns every owner ordered by name.
+	GetAll() ([]model.Owner, error)
+	// Update changes the name, contact and mnos of an owner.
+	Update(owner *model.Owner) error
+	// ChangeState changes the state of an owner.
+	ChangeState(id int, newstate model.OwnerState) error
+}
@@ -0,0 +1,126 @@
+package dao
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fernandoocampo/pack/model"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// ownerColl is the mongo collection name for pack owners
+const ownerColl = "owners"
+
+// MongoOwnerDAO implements IOwnerDAO using mongo.
+type MongoOwnerDAO struct {
+}
+
+// Create implements IOwnerDAO.Create.
+func (m *MongoOwnerDAO) Create(owner *model.Owner) error {
+	if owner == nil || owner.ID < 1 {
+		return errors.New("Invalid owner data")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(ownerColl)
+
+	err := c.Insert(owner)
+	if err != nil {
+		errmsg := "An error on owner creation - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return nil
+}
+
+// GetByID implements IOwnerDAO.GetByID.
+func (m *MongoOwnerDAO) GetByID(id int) (*model.Owner, error) {
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(ownerColl)
+
+	result := model.Owner{}
+	err := c.FindId(id).One(&result)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return nil, nil
+		}
+		errmsg := "An error finding an owner by id - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return &result, nil
+}
+
+// GetAll implements IOwnerDAO.GetAll.
+func (m *MongoOwnerDAO) GetAll() ([]model.Owner, error) {
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(ownerColl)
+
+	result := []model.Owner{}
+	err := c.Find(nil).Sort("name").All(&result)
+	if err != nil {
+		errmsg := "An error finding owners - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return result, nil
+}
+
+// Update implements IOwnerDAO.Update.
+func (m *MongoOwnerDAO) Update(owner *model.Owner) error {
+	if owner == nil || owner.ID < 1 {
+		return errors.New("Invalid owner data")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(ownerColl)
+
+	change := bson.M{"$set": bson.M{
+		"name":    owner.Name,
+		"contact": owner.Contact,
+		"mnos":    owner.Mnos,
+		"updated": time.Now(),
+	}}
+	err := c.UpdateId(owner.ID, change)
+	if err != nil {
+		errmsg := "An error updating an owner - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return nil
+}
+
+// ChangeState implements IOwnerDAO.ChangeState.
+func (m *MongoOwnerDAO) ChangeState(id int, newstate model.OwnerState) error {
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(ownerColl)
+
+	change := bson.M{"$set": bson.M{"state": newstate, "updated": time.Now()}}
+	err := c.UpdateId(id, change)
+	if err != nil {
+		errmsg := "An error changing the state of an owner - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package dao_test
+
+import (
+	"testing"
+
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/model"
+)
+
+// TestTransferPackOwner verify that a pack is listed by its new owner
+// after its ownership changes.
+func TestTransferPackOwner(t *testing.T) {
+	// GIVEN a pack of owner 1
+	dao.SetDBname("amphora")
+	dao.SetMongoAddrs([]string{"localhost:27017"})
+	dao.SetTimeout(60)
+
+	dao.InitMgoSession()
+	defer dao.CloseMgoSession()
+
+	mongodao := new(dao.MongoDAO)
+	ownerdao := new(dao.MongoOwnerDAO)
+
+	owner := &model.Owner{ID: 30030, Name: "Reseller 30", Mnos: []int8{2}, State: model.OwnerActive}
+	ownerdao.Create(owner)
+	newpack := newPackData("own30", "Owner pack", "30")
+
+	err1 := mongodao.Create(newpack)
+
+	if err1 != nil {
+		t.Fatalf("Expected err1 to be nil but it was: %s", err1)
+	}
+	packid, _ := mongodao.GetIDByCode("own30")
+
+	// WHEN we give the pack to the new owner
+	err2 := mongodao.ChangeOwner(packid, owner.ID)
+
+	// THEN we check the pack is listed by the new owner
+	if err2 != nil {
+		t.Fatalf("Expected err2 to be nil but it was: %s", err2)
+	}
+	packs, err3 := mongodao.GetByOwner(owner.ID)
+
+	if err3 != nil {
+		t.Fatalf("Expected err3 to be nil but it was: %s", err3)
+	}
+	if len(packs) != 1 || packs[0].ID.Hex() != packid {
+		t.Fatalf("Expected pack %s to be listed by the owner but got %+v", packid, packs)
+	}
+
+	// AND the owner can be read back
+	stored, err4 := ownerdao.GetByID(owner.ID)
+	if err4 != nil || stored == nil || stored.Name != owner.Name {
+		t.Fatalf("Expected owner %s but got %+v, %v", owner.Name, stored, err4)
+	}
+}
@@ -1,9 +1,30 @@
 package dao
 
-import "github.com/fernandoocampo/pack/model"
+import (
+	"errors"
+
+	"github.com/fernandoocampo/pack/model"
+)
+
+// ErrVersionChanged is returned by the changes of a dao at a version when
+// the pack is not at that version anymore, or it does not exist.
+var ErrVersionChanged = errors.New("pack is not at the expected version")
+
+// ErrNotInTenant is returned by the changes of a scoped dao when the pack
+// does not belong to its tenant, or would not after the change.
+var ErrNotInTenant = errors.New("pack does not belong to the tenant")
 
 // IPackDAO defines pack data access behavior for management purpose.
+// Every change of a pack writes its model.Event in the same write, so the
+// events relayed by IOutboxDAO are never lost nor made up.
 type IPackDAO interface {
+	// Scoped returns a dao that only reads and changes the packs of the
+	// given tenant, a nil tenant sees no pack.
+	Scoped(tenant *model.Tenant) IPackDAO
+	// AtVersion returns a dao whose changes only apply to a pack at the
+	// given version, otherwise they fail with ErrVersionChanged. Reads
+	// and stock reservations are not affected.
+	AtVersion(version int) IPackDAO
 	// GetByID search a pack with the given id
 	// and return it.
 	GetByID(id string) (*model.Pack, error)
@@ -20,9 +41,16 @@ type IPackDAO interface {
 	// that combination between mnoid and pack code or mnoid and
 	// product id or mnoid don't exist
 	IsThereThisPack(pack *model.PackExists) (bool, error)
+	// GetByKeys returns the pack of the mno with the pack code or the
+	// product id of the given keys, nil if there is none.
+	GetByKeys(keys *model.PackExists) (*model.Pack, error)
 	// Create inserts a new Pack in the system. Returns
 	// true if the Pack is created
 	Create(packdata *model.Pack) error
+	// Update replaces the catalog data of an existent pack with the given
+	// one, its state and stock are kept as its translations if the given
+	// pack has none.
+	Update(id string, packdata *model.Pack) error
 	// ChangeState changes the state of a pack.
 	ChangeState(id string, newstate model.PackState) error
 	// ChangeProductID changes the mno internal product id.
@@ -49,9 +77,49 @@ type IPackDAO interface {
 	ChangeCurrency(id string, newccy *model.Currency) error
 	// ChangeStock add or reduce stock to the given pack.
 	ChangeStock(id string, amount int) error
+	// ReserveStock reduces the stock of the given pack only if there are
+	// at least amount units. Returns false if the stock was not enough.
+	ReserveStock(id string, amount int) (bool, error)
 	// UpdateResources replace the resources that we configured for a pack.
 	// Send newresources empty if you want to remove all the resources.
 	UpdateResources(id string, newresources []model.Resource) error
-	// Delete removes an existent Pack and returns true if the pack can be deleted.
+	// UpdateComponents replaces the packs of a bundle and the resources
+	// aggregated from them.
+	UpdateComponents(id string, components []string, resources []model.Resource) error
+	// GetBundlesOf returns the bundles that have the given pack as a
+	// component.
+	GetBundlesOf(id string) ([]model.Pack, error)
+	// ChangeOwner changes the company owner of the pack for resale.
+	ChangeOwner(id string, newownerid int) error
+	// GetByOwner returns the packs of the given owner.
+	GetByOwner(ownerid int) ([]model.Pack, error)
+	// List returns the packs that match the filter sorted by pack code,
+	// skipping the first skip packs and at most limit packs.
+	List(filter *model.PackFilter, skip int, limit int) ([]model.Pack, error)
+	// Count returns how many packs match the filter.
+	Count(filter *model.PackFilter) (int, error)
+	// Each calls fn with every pack that matches the filter sorted by
+	// pack code, it stops at the first error of fn. Packs are read in
+	// batches so they are not loaded in memory at once, fn must not keep
+	// the given pack.
+	Each(filter *model.PackFilter, fn func(pack *model.Pack) error) error
+	// Stats returns the catalog stats of the packs that match the filter
+	// grouped by the given fields, sorted as model.StatsAccumulator does.
+	// A backend without aggregations can add the packs of Each to a
+	// model.StatsAccumulator.
+	Stats(filter *model.PackFilter, groupBy []string) ([]model.CatalogStats, error)
+	// UpdateTranslations replaces the translations of the pack.
+	UpdateTranslations(id string, translations []model.Translation) error
+	// UpdateRules replaces the eligibility rules of the pack.
+	UpdateRules(id string, rules []model.EligibilityRule) error
+	// UpdateAvailability replaces the channels and regions where the pack
+	// is sold.
+	UpdateAvailability(id string, channels []string, regions []string) error
+	// Search returns the packs whose name, description or keywords in
+	// any locale match the given text, the best matches first.
+	Search(text string, limit int) ([]model.Pack, error)
+	// Delete removes an existent Pack and returns true if the pack can be
+	// deleted. It is not found anymore but it may be kept until its
+	// events are relayed.
 	Delete(id string) error
 }
@@ -0,0 +1,17 @@
+package dao
+
+import "github.com/fernandoocampo/pack/model"
+
+// IPackTemplateDAO defines data access behavior for pack templates.
+type IPackTemplateDAO interface {
+	// Create inserts a new template.
+	Create(template *model.PackTemplate) error
+	// GetByID search a template with the given id and return it.
+	GetByID(id string) (*model.PackTemplate, error)
+	// GetByName search a template with the given name and return it.
+	GetByName(name string) (*model.PackTemplate, error)
+	// GetAll returns every template sorted by name.
+	GetAll() ([]model.PackTemplate, error)
+	// Delete removes the template with the given id.
+	Delete(id string) error
+}
@@ -0,0 +1,116 @@
+package dao
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/fernandoocampo/pack/model"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// packTemplateColl is the mongo collection name for pack templates
+const packTemplateColl = "packtemplates"
+
+// MongoPackTemplateDAO implements IPackTemplateDAO using mongo.
+type MongoPackTemplateDAO struct {
+}
+
+// Create implements IPackTemplateDAO.Create.
+func (m *MongoPackTemplateDAO) Create(template *model.PackTemplate) error {
+	if template == nil {
+		return errors.New("Invalid pack template data")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(packTemplateColl)
+
+	if template.ID == "" {
+		template.ID = bson.NewObjectId()
+	}
+	err := c.Insert(template)
+	if err != nil {
+		errmsg := "An error on pack template creation - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return nil
+}
+
+// GetByID implements IPackTemplateDAO.GetByID.
+func (m *MongoPackTemplateDAO) GetByID(id string) (*model.PackTemplate, error) {
+	if !bson.IsObjectIdHex(id) {
+		return nil, errors.New("Invalid pack template id")
+	}
+	return m.getOne(bson.M{"_id": bson.ObjectIdHex(id)})
+}
+
+// GetByName implements IPackTemplateDAO.GetByName.
+func (m *MongoPackTemplateDAO) GetByName(name string) (*model.PackTemplate, error) {
+	return m.getOne(bson.M{"name": name})
+}
+
+// getOne returns the template that matches the filter, nil if there is none.
+func (m *MongoPackTemplateDAO) getOne(filter bson.M) (*model.PackTemplate, error) {
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(packTemplateColl)
+
+	result := model.PackTemplate{}
+	err := c.Find(filter).One(&result)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return nil, nil
+		}
+		errmsg := "An error finding a pack template - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return &result, nil
+}
+
+// GetAll implements IPackTemplateDAO.GetAll.
+func (m *MongoPackTemplateDAO) GetAll() ([]model.PackTemplate, error) {
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(packTemplateColl)
+
+	result := []model.PackTemplate{}
+	err := c.Find(nil).Sort("name").All(&result)
+	if err != nil {
+		errmsg := "An error finding pack templates - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return result, nil
+}
+
+// Delete implements IPackTemplateDAO.Delete.
+func (m *MongoPackTemplateDAO) Delete(id string) error {
+	if !bson.IsObjectIdHex(id) {
+		return errors.New("Invalid pack template id")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(packTemplateColl)
+
+	err := c.RemoveId(bson.ObjectIdHex(id))
+	if err != nil && err != mgo.ErrNotFound {
+		errmsg := "An error deleting a pack template - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,21 @@
+package dao
+
+import (
+	"time"
+
+	"github.com/fernandoocampo/pack/model"
+)
+
+// ISettlementDAO defines data access behavior for reseller settlements.
+type ISettlementDAO interface {
+	// Create inserts a new settlement.
+	Create(settlement *model.Settlement) error
+	// GetByID search a settlement with the given id and return it.
+	GetByID(id string) (*model.Settlement, error)
+	// GetByOwner returns the settlements of the given owner without
+	// their lines, the newest first.
+	GetByOwner(ownerid int) ([]model.Settlement, error)
+	// Exists returns true if the owner has a settlement of the period
+	// starting at from.
+	Exists(ownerid int, from time.Time) (bool, error)
+}
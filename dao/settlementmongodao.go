@@ -0,0 +1,104 @@
+package dao
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fernandoocampo/pack/model"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// settlementColl is the mongo collection name for settlements
+const settlementColl = "settlements"
+
+// MongoSettlementDAO implements ISettlementDAO using mongo.
+type MongoSettlementDAO struct {
+}
+
+// Create implements ISettlementDAO.Create.
+func (m *MongoSettlementDAO) Create(settlement *model.Settlement) error {
+	if settlement == nil {
+		return errors.New("Invalid settlement data")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(settlementColl)
+
+	if settlement.ID == "" {
+		settlement.ID = bson.NewObjectId()
+	}
+	err := c.Insert(settlement)
+	if err != nil {
+		errmsg := "An error on settlement creation - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return nil
+}
+
+// GetByID implements ISettlementDAO.GetByID.
+func (m *MongoSettlementDAO) GetByID(id string) (*model.Settlement, error) {
+	if !bson.IsObjectIdHex(id) {
+		return nil, errors.New("Invalid settlement id")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(settlementColl)
+
+	result := model.Settlement{}
+	err := c.FindId(bson.ObjectIdHex(id)).One(&result)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return nil, nil
+		}
+		errmsg := "An error finding a settlement by id - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return &result, nil
+}
+
+// GetByOwner implements ISettlementDAO.GetByOwner.
+func (m *MongoSettlementDAO) GetByOwner(ownerid int) ([]model.Settlement, error) {
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(settlementColl)
+
+	result := []model.Settlement{}
+	err := c.Find(bson.M{"ownerid": ownerid}).Select(bson.M{"lines": 0}).Sort("-from").All(&result)
+	if err != nil {
+		errmsg := "An error finding settlements by owner - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return result, nil
+}
+
+// Exists implements ISettlementDAO.Exists.
+func (m *MongoSettlementDAO) Exists(ownerid int, from time.Time) (bool, error) {
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(settlementColl)
+
+	count, err := c.Find(bson.M{"ownerid": ownerid, "from": from}).Count()
+	if err != nil {
+		errmsg := "An error checking a settlement - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return false, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return count > 0, nil
+}
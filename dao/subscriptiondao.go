@@ -0,0 +1,36 @@
+package dao
+
+import (
+	"errors"
+	"time"
+
+	"github.com/fernandoocampo/pack/model"
+)
+
+// ErrAlreadySubscribed is returned when a subscription is created while
+// the subscriber has another one to the pack that is not canceled.
+var ErrAlreadySubscribed = errors.New("subscriber is already subscribed to the pack")
+
+// ISubscriptionDAO defines data access behavior for pack subscriptions.
+type ISubscriptionDAO interface {
+	// Create inserts a new subscription, it returns ErrAlreadySubscribed
+	// if the subscriber has one to the pack that is not canceled.
+	Create(subscription *model.Subscription) error
+	// GetByID search a subscription with the given id and return it.
+	GetByID(id string) (*model.Subscription, error)
+	// GetByMsisdn returns the subscriptions of the given subscriber.
+	GetByMsisdn(msisdn string) ([]model.Subscription, error)
+	// IsSubscribed checks if the subscriber has a subscription to the
+	// given pack that is not canceled.
+	IsSubscribed(msisdn string, packid string) (bool, error)
+	// ChangeState changes the state of a subscription only if its current
+	// state is one of from. Returns false if the state was not changed.
+	ChangeState(id string, from []model.SubscriptionState, to model.SubscriptionState) (bool, error)
+	// ClaimDue takes an active subscription whose renewal is due at now
+	// and moves its renewal forward by lease, so no other process renews
+	// it meanwhile. Returns nil if there is nothing to renew.
+	ClaimDue(now time.Time, lease time.Duration) (*model.Subscription, error)
+	// UpdateRenewal stores the renewal data of the subscription and adds
+	// the given renewal to its history if it is not nil.
+	UpdateRenewal(subscription *model.Subscription, renewal *model.Renewal) error
+}
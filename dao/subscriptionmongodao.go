@@ -0,0 +1,233 @@
+package dao
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fernandoocampo/pack/model"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// subscriptionColl is the mongo collection name for subscriptions
+const subscriptionColl = "subscriptions"
+
+// MongoSubscriptionDAO implements ISubscriptionDAO using mongo.
+type MongoSubscriptionDAO struct {
+}
+
+// Create implements ISubscriptionDAO.Create.
+func (m *MongoSubscriptionDAO) Create(subscription *model.Subscription) error {
+	if subscription == nil {
+		return errors.New("Invalid subscription data")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(subscriptionColl)
+
+	if subscription.ID == "" {
+		subscription.ID = bson.NewObjectId()
+	}
+	err := c.Insert(subscription)
+	if mgo.IsDup(err) {
+		return ErrAlreadySubscribed
+	}
+	if err != nil {
+		errmsg := "An error on subscription creation - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return nil
+}
+
+// GetByID implements ISubscriptionDAO.GetByID.
+func (m *MongoSubscriptionDAO) GetByID(id string) (*model.Subscription, error) {
+	if !bson.IsObjectIdHex(id) {
+		return nil, errors.New("Invalid subscription id")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(subscriptionColl)
+
+	result := model.Subscription{}
+	err := c.FindId(bson.ObjectIdHex(id)).One(&result)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return nil, nil
+		}
+		errmsg := "An error finding a subscription by id - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return &result, nil
+}
+
+// GetByMsisdn implements ISubscriptionDAO.GetByMsisdn.
+func (m *MongoSubscriptionDAO) GetByMsisdn(msisdn string) ([]model.Subscription, error) {
+	if msisdn == "" {
+		return nil, errors.New("Invalid msisdn")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(subscriptionColl)
+
+	result := []model.Subscription{}
+	err := c.Find(bson.M{"msisdn": msisdn}).Sort("-created").All(&result)
+	if err != nil {
+		errmsg := "An error finding subscriptions by msisdn - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return result, nil
+}
+
+// IsSubscribed implements ISubscriptionDAO.IsSubscribed.
+func (m *MongoSubscriptionDAO) IsSubscribed(msisdn string, packid string) (bool, error) {
+	if msisdn == "" || packid == "" {
+		return false, errors.New("Invalid msisdn or pack id")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(subscriptionColl)
+
+	filter := bson.M{"msisdn": msisdn, "packid": packid,
+		"state": bson.M{"$ne": model.SubscriptionCanceled}}
+	count, err := c.Find(filter).Count()
+	if err != nil {
+		errmsg := "An error on IsSubscribed - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return false, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return count > 0, nil
+}
+
+// ChangeState implements ISubscriptionDAO.ChangeState.
+func (m *MongoSubscriptionDAO) ChangeState(id string, from []model.SubscriptionState, to model.SubscriptionState) (bool, error) {
+	if !bson.IsObjectIdHex(id) {
+		return false, errors.New("Invalid subscription id")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(subscriptionColl)
+
+	filter := bson.M{"_id": bson.ObjectIdHex(id), "state": bson.M{"$in": from}}
+	change := bson.M{"$set": bson.M{"state": to, "updated": time.Now()}}
+	err := c.Update(filter, change)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return false, nil
+		}
+		errmsg := "An error changing a subscription state - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return false, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return true, nil
+}
+
+// ClaimDue implements ISubscriptionDAO.ClaimDue.
+func (m *MongoSubscriptionDAO) ClaimDue(now time.Time, lease time.Duration) (*model.Subscription, error) {
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(subscriptionColl)
+
+	filter := bson.M{"state": model.SubscriptionActive, "nextrenewal": bson.M{"$lte": now}}
+	change := mgo.Change{
+		Update: bson.M{"$set": bson.M{"nextrenewal": now.Add(lease)}},
+	}
+	result := model.Subscription{}
+	_, err := c.Find(filter).Sort("nextrenewal").Apply(change, &result)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return nil, nil
+		}
+		errmsg := "An error claiming a due subscription - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return &result, nil
+}
+
+// UpdateRenewal implements ISubscriptionDAO.UpdateRenewal. The state is
+// only changed from active, so a pause or cancel made while renewing wins.
+func (m *MongoSubscriptionDAO) UpdateRenewal(subscription *model.Subscription, renewal *model.Renewal) error {
+	if subscription == nil || subscription.ID == "" {
+		return errors.New("Invalid subscription data")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(subscriptionColl)
+
+	change := bson.M{"$set": bson.M{
+		"price":       subscription.Price,
+		"pricenotice": subscription.PriceNotice,
+		"nextrenewal": subscription.NextRenewal,
+		"attempts":    subscription.Attempts,
+		"lasterror":   subscription.LastError,
+		"updated":     time.Now(),
+	}}
+	if renewal != nil {
+		change["$push"] = bson.M{"renewals": renewal}
+	}
+	err := c.UpdateId(subscription.ID, change)
+	if err == nil && subscription.State != model.SubscriptionActive {
+		err = c.Update(bson.M{"_id": subscription.ID, "state": model.SubscriptionActive},
+			bson.M{"$set": bson.M{"state": subscription.State}})
+		if err == mgo.ErrNotFound {
+			err = nil
+		}
+	}
+	if err != nil {
+		errmsg := "An error updating a subscription renewal - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return nil
+}
+
+// EnsureSubscriptionIndexes creates the unique index that keeps a
+// subscriber from having two subscriptions to a pack that are not
+// canceled. mgo has no partial indexes, so the command is run as is.
+func EnsureSubscriptionIndexes() error {
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+
+	command := bson.D{
+		{Name: "createIndexes", Value: subscriptionColl},
+		{Name: "indexes", Value: []bson.M{{
+			"key":                     bson.D{{Name: "msisdn", Value: 1}, {Name: "packid", Value: 1}},
+			"name":                    "subscription_open",
+			"unique":                  true,
+			"partialFilterExpression": bson.M{"state": bson.M{"$gt": model.SubscriptionCanceled}},
+		}}},
+	}
+	err := sessionCopy.DB(mongoDB).Run(command, nil)
+	if err != nil {
+		errmsg := "An error creating the subscription indexes - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+	return nil
+}
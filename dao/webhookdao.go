@@ -0,0 +1,16 @@
+package dao
+
+import "github.com/fernandoocampo/pack/model"
+
+// IWebhookDAO defines data access behavior for the webhooks of partners.
+type IWebhookDAO interface {
+	// Create inserts a new webhook.
+	Create(webhook *model.Webhook) error
+	// GetByID returns the webhook with the given id, nil if there is none.
+	GetByID(id string) (*model.Webhook, error)
+	// GetAll returns the webhooks of the given owner, every webhook if it
+	// is 0, the newest first.
+	GetAll(ownerid int) ([]model.Webhook, error)
+	// Delete removes the webhook with the given id.
+	Delete(id string) error
+}
@@ -0,0 +1,25 @@
+package dao
+
+import (
+	"time"
+
+	"github.com/fernandoocampo/pack/model"
+)
+
+// IWebhookDeliveryDAO defines data access behavior for the deliveries of
+// the pack events to webhooks.
+type IWebhookDeliveryDAO interface {
+	// Create inserts a new delivery. A delivery of the same event to the
+	// same webhook is kept, so an event relayed twice is posted once.
+	Create(delivery *model.WebhookDelivery) error
+	// GetByID returns the delivery with the given id, nil if there is none.
+	GetByID(id string) (*model.WebhookDelivery, error)
+	// GetByWebhook returns at most limit deliveries of the webhook in the
+	// given state, every state if it is empty, the newest first.
+	GetByWebhook(webhookid string, state string, limit int) ([]model.WebhookDelivery, error)
+	// ClaimDue returns a pending delivery due at the given time and
+	// reserves it for the given lease, nil if there is none.
+	ClaimDue(now time.Time, lease time.Duration) (*model.WebhookDelivery, error)
+	// Update saves the state and the attempts of a delivery.
+	Update(delivery *model.WebhookDelivery) error
+}
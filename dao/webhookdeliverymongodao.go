@@ -0,0 +1,168 @@
+package dao
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fernandoocampo/pack/model"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// webhookDeliveryColl is the mongo collection name for webhook deliveries
+const webhookDeliveryColl = "webhookdeliveries"
+
+// MongoWebhookDeliveryDAO implements IWebhookDeliveryDAO using mongo.
+type MongoWebhookDeliveryDAO struct {
+}
+
+// Create implements IWebhookDeliveryDAO.Create. The delivery is only
+// inserted if there is none of the same event and webhook.
+func (m *MongoWebhookDeliveryDAO) Create(delivery *model.WebhookDelivery) error {
+	if delivery == nil || delivery.WebhookID == "" || delivery.EventID == "" {
+		return errors.New("Invalid webhook delivery data")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(webhookDeliveryColl)
+
+	if delivery.ID == "" {
+		delivery.ID = bson.NewObjectId()
+	}
+	filter := bson.M{"webhookid": delivery.WebhookID, "eventid": delivery.EventID}
+	_, err := c.Upsert(filter, bson.M{"$setOnInsert": delivery})
+	if err != nil {
+		errmsg := "An error on webhook delivery creation - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return nil
+}
+
+// GetByID implements IWebhookDeliveryDAO.GetByID.
+func (m *MongoWebhookDeliveryDAO) GetByID(id string) (*model.WebhookDelivery, error) {
+	if !bson.IsObjectIdHex(id) {
+		return nil, nil
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(webhookDeliveryColl)
+
+	result := model.WebhookDelivery{}
+	err := c.FindId(bson.ObjectIdHex(id)).One(&result)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return nil, nil
+		}
+		errmsg := "An error finding a webhook delivery by id - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return &result, nil
+}
+
+// GetByWebhook implements IWebhookDeliveryDAO.GetByWebhook.
+func (m *MongoWebhookDeliveryDAO) GetByWebhook(webhookid string, state string, limit int) ([]model.WebhookDelivery, error) {
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(webhookDeliveryColl)
+
+	filter := bson.M{"webhookid": webhookid}
+	if state != "" {
+		filter["state"] = state
+	}
+	result := []model.WebhookDelivery{}
+	err := c.Find(filter).Sort("-created").Limit(limit).All(&result)
+	if err != nil {
+		errmsg := "An error finding webhook deliveries - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return result, nil
+}
+
+// ClaimDue implements IWebhookDeliveryDAO.ClaimDue.
+func (m *MongoWebhookDeliveryDAO) ClaimDue(now time.Time, lease time.Duration) (*model.WebhookDelivery, error) {
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(webhookDeliveryColl)
+
+	filter := bson.M{"state": model.DeliveryPending, "nextattempt": bson.M{"$lte": now}}
+	change := mgo.Change{
+		Update: bson.M{"$set": bson.M{"nextattempt": now.Add(lease)}},
+	}
+	result := model.WebhookDelivery{}
+	_, err := c.Find(filter).Sort("nextattempt").Apply(change, &result)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return nil, nil
+		}
+		errmsg := "An error claiming a due webhook delivery - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return &result, nil
+}
+
+// Update implements IWebhookDeliveryDAO.Update.
+func (m *MongoWebhookDeliveryDAO) Update(delivery *model.WebhookDelivery) error {
+	if delivery == nil || delivery.ID == "" {
+		return errors.New("Invalid webhook delivery data")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(webhookDeliveryColl)
+
+	change := bson.M{"$set": bson.M{
+		"state":       delivery.State,
+		"tries":       delivery.Tries,
+		"nextattempt": delivery.NextAttempt,
+		"attempts":    delivery.Attempts,
+		"updated":     delivery.Updated,
+		"delivered":   delivery.Delivered,
+	}}
+	err := c.UpdateId(delivery.ID, change)
+	if err != nil {
+		errmsg := "An error updating a webhook delivery - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return nil
+}
+
+// EnsureWebhookIndexes creates the indexes of the webhook deliveries. The
+// unique index keeps an event from being delivered twice to a webhook.
+func EnsureWebhookIndexes() error {
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(webhookDeliveryColl)
+
+	err := c.EnsureIndex(mgo.Index{Key: []string{"webhookid", "eventid"}, Name: "delivery_event", Unique: true})
+	if err == nil {
+		err = c.EnsureIndex(mgo.Index{Key: []string{"state", "nextattempt"}, Name: "delivery_due"})
+	}
+	if err != nil {
+		errmsg := "An error creating the webhook delivery indexes - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package dao
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/fernandoocampo/pack/model"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// webhookColl is the mongo collection name for webhooks
+const webhookColl = "webhooks"
+
+// MongoWebhookDAO implements IWebhookDAO using mongo.
+type MongoWebhookDAO struct {
+}
+
+// Create implements IWebhookDAO.Create.
+func (m *MongoWebhookDAO) Create(webhook *model.Webhook) error {
+	if webhook == nil || webhook.URL == "" {
+		return errors.New("Invalid webhook data")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(webhookColl)
+
+	if webhook.ID == "" {
+		webhook.ID = bson.NewObjectId()
+	}
+	err := c.Insert(webhook)
+	if err != nil {
+		errmsg := "An error on webhook creation - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return nil
+}
+
+// GetByID implements IWebhookDAO.GetByID.
+func (m *MongoWebhookDAO) GetByID(id string) (*model.Webhook, error) {
+	if !bson.IsObjectIdHex(id) {
+		return nil, nil
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(webhookColl)
+
+	result := model.Webhook{}
+	err := c.FindId(bson.ObjectIdHex(id)).One(&result)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return nil, nil
+		}
+		errmsg := "An error finding a webhook by id - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return &result, nil
+}
+
+// GetAll implements IWebhookDAO.GetAll.
+func (m *MongoWebhookDAO) GetAll(ownerid int) ([]model.Webhook, error) {
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(webhookColl)
+
+	filter := bson.M{}
+	if ownerid != 0 {
+		filter["ownerid"] = ownerid
+	}
+	result := []model.Webhook{}
+	err := c.Find(filter).Sort("-created").All(&result)
+	if err != nil {
+		errmsg := "An error finding webhooks - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return nil, fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return result, nil
+}
+
+// Delete implements IWebhookDAO.Delete.
+func (m *MongoWebhookDAO) Delete(id string) error {
+	if !bson.IsObjectIdHex(id) {
+		return errors.New("Invalid webhook id")
+	}
+	// make a connection to mongo database
+	sessionCopy := newMgoSession()
+	defer sessionCopy.Close()
+	// References the mongo collection
+	c := sessionCopy.DB(mongoDB).C(webhookColl)
+
+	err := c.RemoveId(bson.ObjectIdHex(id))
+	if err != nil && err != mgo.ErrNotFound {
+		errmsg := "An error deleting a webhook - mongodao"
+		log.Errorf("%s : %v\n", errmsg, err)
+		return fmt.Errorf("%s: %w", errmsg, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package dao_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/model"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// TestWebhookDeliveries verify that an event is delivered once to a webhook
+// and that a due delivery is claimed by one process.
+func TestWebhookDeliveries(t *testing.T) {
+	// GIVEN a webhook and an event relayed twice
+	dao.SetDBname("amphora")
+	dao.SetMongoAddrs([]string{"localhost:27017"})
+	dao.SetTimeout(60)
+
+	dao.InitMgoSession()
+	defer dao.CloseMgoSession()
+
+	webhookdao := new(dao.MongoWebhookDAO)
+	deliverydao := new(dao.MongoWebhookDeliveryDAO)
+	webhook := &model.Webhook{Ownerid: 7, URL: "http://localhost:9000/hooks", Secret: "whsec_test_secret",
+		Created: time.Now()}
+	err1 := webhookdao.Create(webhook)
+	if err1 != nil {
+		t.Fatalf("Expected err1 to be nil but it was: %s", err1)
+	}
+	defer webhookdao.Delete(webhook.ID.Hex())
+	event := model.NewEvent(model.EventPackPriceChanged, bson.NewObjectId().Hex(), nil)
+	now := time.Now().Add(-time.Second)
+	for i := 0; i < 2; i++ {
+		err2 := deliverydao.Create(model.NewWebhookDelivery(webhook, &event, []byte(`{}`), now))
+		if err2 != nil {
+			t.Fatalf("Expected err2 to be nil but it was: %s", err2)
+		}
+	}
+
+	// WHEN the due deliveries are claimed twice
+	first, err3 := deliverydao.ClaimDue(time.Now(), time.Minute)
+	second, err4 := deliverydao.ClaimDue(time.Now(), time.Minute)
+
+	// THEN there was only one delivery and it was claimed once
+	if err3 != nil || err4 != nil {
+		t.Fatalf("Expected no errors but got %v and %v", err3, err4)
+	}
+	if first == nil || first.EventID != event.ID.Hex() || second != nil {
+		t.Fatalf("Expected the delivery claimed once but got %+v and %+v", first, second)
+	}
+	deliveries, err5 := deliverydao.GetByWebhook(webhook.ID.Hex(), "", 10)
+	if err5 != nil || len(deliveries) != 1 {
+		t.Errorf("Expected 1 delivery but got %d: %v", len(deliveries), err5)
+	}
+}
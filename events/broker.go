@@ -0,0 +1,97 @@
+package events
+
+import (
+	"sync"
+
+	"github.com/fernandoocampo/pack/model"
+)
+
+// DefaultBrokerBuffer is the number of events a subscriber of a broker
+// can have waiting when none is given.
+const DefaultBrokerBuffer = 64
+
+// Broker is a sink that passes the events to subscribers in memory, e.g.
+// the graphql subscriptions. Publishing never waits for a subscriber: a
+// subscriber whose buffer is full is dropped, so a slow subscriber cannot
+// hold back the relay nor the other subscribers.
+type Broker struct {
+	mutex       sync.Mutex
+	buffer      int
+	subscribers map[*Subscription]bool
+}
+
+// Subscription receives the events a subscriber of a broker is
+// interested in. C is closed when the subscription is dropped or
+// canceled.
+type Subscription struct {
+	C       <-chan *model.Event
+	events  chan *model.Event
+	match   func(event *model.Event) bool
+	dropped bool
+}
+
+// NewBroker creates a broker whose subscribers can have the given number
+// of events waiting, DefaultBrokerBuffer if it is not positive.
+func NewBroker(buffer int) *Broker {
+	if buffer <= 0 {
+		buffer = DefaultBrokerBuffer
+	}
+	return &Broker{buffer: buffer, subscribers: map[*Subscription]bool{}}
+}
+
+// Subscribe returns a subscription to the events that match, every event
+// if match is nil.
+func (b *Broker) Subscribe(match func(event *model.Event) bool) *Subscription {
+	events := make(chan *model.Event, b.buffer)
+	subscription := &Subscription{C: events, events: events, match: match}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.subscribers[subscription] = true
+	return subscription
+}
+
+// Unsubscribe cancels the subscription and closes its channel, nothing
+// happens if it was already dropped.
+func (b *Broker) Unsubscribe(subscription *Subscription) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.subscribers[subscription] {
+		delete(b.subscribers, subscription)
+		close(subscription.events)
+	}
+}
+
+// Publish implements ISink.Publish. Every subscriber gets the same copy
+// of the event, so they must not change it.
+func (b *Broker) Publish(event *model.Event) error {
+	copied := *event
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for subscription := range b.subscribers {
+		if subscription.match != nil && !subscription.match(&copied) {
+			continue
+		}
+		select {
+		case subscription.events <- &copied:
+		default:
+			log.Warnf("a subscriber of the pack events fell behind %d events and it was dropped", b.buffer)
+			subscription.dropped = true
+			delete(b.subscribers, subscription)
+			close(subscription.events)
+		}
+	}
+	return nil
+}
+
+// Subscribers returns the number of subscriptions of the broker.
+func (b *Broker) Subscribers() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return len(b.subscribers)
+}
+
+// Dropped returns true if the subscription was closed because it fell
+// behind. It is only meaningful once C is closed.
+func (s *Subscription) Dropped() bool {
+	return s.dropped
+}
@@ -0,0 +1,56 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/fernandoocampo/pack/model"
+)
+
+// TestBrokerPublish tests subscribers only get the events they match
+func TestBrokerPublish(t *testing.T) {
+	broker := NewBroker(4)
+	stock := broker.Subscribe(func(event *model.Event) bool { return event.Type == model.EventStockMoved })
+	every := broker.Subscribe(nil)
+
+	for _, eventtype := range []string{model.EventPackPriceChanged, model.EventStockMoved} {
+		event := model.NewEvent(eventtype, "p1", nil)
+		if err := broker.Publish(&event); err != nil {
+			t.Fatalf("Expected err to be nil but it was: %s", err)
+		}
+	}
+
+	if len(stock.C) != 1 || len(every.C) != 2 {
+		t.Fatalf("Expected 1 stock event and 2 events but got %d and %d", len(stock.C), len(every.C))
+	}
+	if event := <-stock.C; event.Type != model.EventStockMoved {
+		t.Errorf("Expected a stock event but got %s", event.Type)
+	}
+	broker.Unsubscribe(stock)
+	broker.Unsubscribe(stock)
+	if _, open := <-stock.C; open || stock.Dropped() || broker.Subscribers() != 1 {
+		t.Errorf("Expected the stock subscription closed without being dropped")
+	}
+}
+
+// TestBrokerSlowSubscriber tests a subscriber that falls behind is dropped
+// without holding back the others
+func TestBrokerSlowSubscriber(t *testing.T) {
+	broker := NewBroker(2)
+	slow := broker.Subscribe(nil)
+	fast := broker.Subscribe(nil)
+
+	for i := 0; i < 3; i++ {
+		event := model.NewEvent(model.EventStockMoved, "p1", map[string]interface{}{"amount": i})
+		_ = broker.Publish(&event)
+		<-fast.C
+	}
+
+	received := 0
+	for range slow.C {
+		received++
+	}
+	if received != 2 || !slow.Dropped() || broker.Subscribers() != 1 {
+		t.Errorf("Expected the slow subscriber dropped after 2 events but got %d, %v", received, slow.Dropped())
+	}
+	broker.Unsubscribe(slow)
+}
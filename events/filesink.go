@@ -0,0 +1,43 @@
+package events
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/fernandoocampo/pack/model"
+)
+
+// FileSink implements ISink writing every event as a json line, it is
+// meant for local testing.
+type FileSink struct {
+	mutex   sync.Mutex
+	encoder *json.Encoder
+}
+
+// NewFileSink creates a FileSink that appends the events to the file of
+// the config, or writes them to the standard output if it has no path or
+// its kind is stdout.
+func NewFileSink(config Config) (ISink, error) {
+	if config.Kind == "stdout" || config.Path == "" || config.Path == "-" {
+		return NewWriterSink(os.Stdout), nil
+	}
+	file, err := os.OpenFile(config.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return NewWriterSink(file), nil
+}
+
+// NewWriterSink creates a FileSink that writes the events to w.
+func NewWriterSink(w io.Writer) *FileSink {
+	return &FileSink{encoder: json.NewEncoder(w)}
+}
+
+// Publish implements ISink.Publish.
+func (f *FileSink) Publish(event *model.Event) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.encoder.Encode(event)
+}
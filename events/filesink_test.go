@@ -0,0 +1,56 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fernandoocampo/pack/model"
+)
+
+// TestFileSinkPublish tests every event is a json line
+func TestFileSinkPublish(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	sink := NewWriterSink(buffer)
+	price := model.NewEvent(model.EventPackPriceChanged, "5a12211dcc7c76da03df50f7", map[string]interface{}{"price": 2500})
+	deleted := model.NewEvent(model.EventPackDeleted, "5a12211dcc7c76da03df50f7", nil)
+
+	for _, event := range []model.Event{price, deleted} {
+		if err := sink.Publish(&event); err != nil {
+			t.Fatalf("Expected err to be nil but it was: %s", err)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines but got %q", buffer.String())
+	}
+	got := model.Event{}
+	if err := json.Unmarshal([]byte(lines[0]), &got); err != nil {
+		t.Fatalf("Expected a json event but got %q: %s", lines[0], err)
+	}
+	if got.ID != price.ID || got.Type != model.EventPackPriceChanged || got.Data["price"] != 2500.0 {
+		t.Errorf("Expected the price event but got %+v", got)
+	}
+}
+
+// TestConfigure tests sinks are built by kind and registered by name
+func TestConfigure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	defer Register("local", nil)
+
+	err := Configure(Config{Name: "local", Kind: "file", Path: path})
+
+	if err != nil {
+		t.Fatalf("Expected err to be nil but it was: %s", err)
+	}
+	if len(Sinks()) != 1 {
+		t.Errorf("Expected the local sink but got %d sinks", len(Sinks()))
+	}
+	err = Configure(Config{Kind: "kafka"})
+	if err == nil {
+		t.Errorf("Expected an error for an unknown kind")
+	}
+}
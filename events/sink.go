@@ -0,0 +1,97 @@
+// Package events delivers the changes of the packs to the systems that
+// follow them, e.g. search, billing or the storefront.
+package events
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/fernandoocampo/pack/model"
+)
+
+// ISink defines a destination of the pack events. Events are delivered
+// at least once, so a sink can get an event again and must use its id to
+// ignore repeated ones.
+type ISink interface {
+	// Publish delivers the event, an error means it was not delivered
+	// and it will be published again.
+	Publish(event *model.Event) error
+}
+
+// Config contains the parameters to build a sink.
+type Config struct {
+	Name string `mapstructure:"name"` // name of the sink, unique
+	Kind string `mapstructure:"kind"` // sink kind. e.g. file, stdout.
+	Path string `mapstructure:"path"` // file the events are appended to
+}
+
+// Factory builds a sink from its configuration.
+type Factory func(config Config) (ISink, error)
+
+var (
+	mutex     sync.RWMutex
+	factories = map[string]Factory{}
+	sinks     = map[string]ISink{}
+)
+
+func init() {
+	RegisterKind("file", NewFileSink)
+	RegisterKind("stdout", NewFileSink)
+}
+
+// RegisterKind adds a new kind of sink that can be configured.
+func RegisterKind(kind string, factory Factory) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	factories[kind] = factory
+}
+
+// Register sets the sink with the given name, a nil sink removes it.
+func Register(name string, sink ISink) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if sink == nil {
+		delete(sinks, name)
+		return
+	}
+	sinks[name] = sink
+}
+
+// Configure builds the sink described by the config and registers it
+// with its name, or its kind if it has no name.
+func Configure(config Config) error {
+	mutex.RLock()
+	factory, ok := factories[config.Kind]
+	mutex.RUnlock()
+	if !ok {
+		return fmt.Errorf("unknown event sink kind: %s", config.Kind)
+	}
+	sink, err := factory(config)
+	if err != nil {
+		return err
+	}
+	name := config.Name
+	if name == "" {
+		name = config.Kind
+	}
+	Register(name, sink)
+	log.Infof("event sink %s registered as %s", config.Kind, name)
+	return nil
+}
+
+// Sinks returns the registered sinks sorted by name.
+func Sinks() []ISink {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	names := make([]string, 0, len(sinks))
+	for name := range sinks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	result := make([]ISink, 0, len(names))
+	for _, name := range names {
+		result = append(result, sinks[name])
+	}
+	return result
+}
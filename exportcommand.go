@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/service"
+)
+
+// exit codes of the export command
+const (
+	exportOK     = 0 // every pack was written
+	exportFailed = 1 // the export is missing or cut
+)
+
+// runExport writes the packs that match the filter of the flags to a file
+// or to the standard output as a platform admin, e.g.
+// pack -file conf/conf.toml export -format xlsx -mnoid 2 -o packs.xlsx
+func runExport(args []string) int {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := flags.String("format", model.ExportCSV, "file format, csv, jsonl or xlsx")
+	output := flags.String("o", "", "file to write, the standard output by default")
+	filterflags := map[string]*int{}
+	for _, name := range []string{"mnoid", "ownerid", "typeid", "state", "minprice", "maxprice"} {
+		filterflags[name] = flags.Int(name, 0, "only the packs with this "+name)
+	}
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: pack [-file conf.toml] export [flags]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil || flags.NArg() != 0 {
+		flags.Usage()
+		return exportFailed
+	}
+	params := map[string]interface{}{}
+	flags.Visit(func(f *flag.Flag) {
+		if value, ok := filterflags[f.Name]; ok {
+			params[f.Name] = *value
+		}
+	})
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot create %s: %v\n", *output, err)
+			return exportFailed
+		}
+		defer file.Close()
+		w = file
+	}
+	buffered := bufio.NewWriter(w)
+
+	err := new(service.BasicPack).Export(buffered, *format, model.NewPackFilter(params))
+	if err == nil {
+		err = buffered.Flush()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot export the packs: %s\n", service.AsError(err).Localize(service.DefaultLocale))
+		log.Errorf("exporting packs: %v", err)
+		return exportFailed
+	}
+	return exportOK
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/service"
+)
+
+// exit codes of the import command
+const (
+	importOK         = 0 // every row was imported
+	importFailed     = 1 // the file was not imported
+	importRowsFailed = 2 // some rows were not imported
+)
+
+// runImport imports the packs of a csv or json lines file as a platform
+// admin and writes the report to the standard output, e.g.
+// pack -file conf/conf.toml import -mode upsert -dry-run packs.csv
+func runImport(args []string) int {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	format := flags.String("format", "", "file format, csv or jsonl. By default the file extension")
+	mode := flags.String("mode", model.ImportCreate, "create fails the rows of existing packs, upsert updates them")
+	dryrun := flags.Bool("dry-run", false, "check the rows without storing them")
+	locale := flags.String("locale", service.DefaultLocale, "locale of the error messages")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: pack [-file conf.toml] import [flags] FILE")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		flags.Usage()
+		return importFailed
+	}
+
+	filename := flags.Arg(0)
+	options := model.ImportOptions{Format: *format, Mode: *mode, DryRun: *dryrun, Locale: *locale,
+		ChangePrice: true, ChangeOwner: true}
+	if options.Format == "" {
+		options.Format = strings.TrimPrefix(strings.ToLower(filepath.Ext(filename)), ".")
+	}
+	file, err := os.Open(filename)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot open %s: %v\n", filename, err)
+		return importFailed
+	}
+	defer file.Close()
+
+	report, err := new(service.BasicPack).ImportPacks(file, options)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot import %s: %s\n", filename, service.AsError(err).Localize(*locale))
+		log.Errorf("importing %s: %v", filename, err)
+		return importFailed
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(report)
+	if err != nil {
+		log.Errorf("writing the import report: %v", err)
+		return importFailed
+	}
+	if report.Failed > 0 {
+		return importRowsFailed
+	}
+	return importOK
+}
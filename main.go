@@ -4,9 +4,15 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 
+	"github.com/fernandoocampo/pack/auth"
 	"github.com/fernandoocampo/pack/controller"
 	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/events"
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/provisioning"
 	"github.com/fernandoocampo/pack/service"
 	"github.com/fernandoocampo/pack/util"
 	"github.com/sirupsen/logrus"
@@ -21,9 +27,31 @@ const (
 
 var log *util.LogHandle
 
+// apiKeyDAO is shared by the api key service and the api key authenticator.
+var apiKeyDAO = new(dao.MongoAPIKeyDAO)
+
+// commands contains the commands run instead of the service, they get
+// the arguments after their name and return the exit code.
+var commands = map[string]func(args []string) int{
+	"import": runImport,
+	"export": runExport,
+}
+
 func main() {
+	// run a command instead of the service
+	if command, ok := commands[flag.Arg(0)]; ok {
+		code := command(flag.Args()[1:])
+		dao.CloseMgoSession()
+		os.Exit(code)
+	}
 	// close first connection when server will go down.
 	defer dao.CloseMgoSession()
+	// start background jobs
+	done := make(chan struct{})
+	defer close(done)
+	initJobs(done)
+	// start grpc server
+	go initGRPCServer()
 	// start http server
 	initHTTPServer()
 }
@@ -35,8 +63,14 @@ func init() {
 	initLogger()
 	// initialize database connection
 	initDb()
+	// initialize mno provisioning adapters
+	initProvisioning()
+	// initialize the sinks of the pack events
+	initEventSinks()
 	// initialize inversion of control
 	initIoC()
+	// initialize authentication of the callers
+	initAuth()
 }
 
 // initConf initializes configuration file
@@ -68,9 +102,244 @@ func initIoC() {
 	mongodao := new(dao.MongoDAO)
 	basicpack := new(service.BasicPack)
 	healthservice := new(service.PackHealth)
+	entitlementdao := new(dao.MongoEntitlementDAO)
+	basicentitlement := new(service.BasicEntitlement)
+	subscriptiondao := new(dao.MongoSubscriptionDAO)
+	basicsubscription := new(service.BasicSubscription)
+	orderdao := new(dao.MongoOrderDAO)
+	basicorder := new(service.BasicOrder)
+	commissiondao := new(dao.MongoCommissionDAO)
+	basiccommission := new(service.BasicCommission)
+	settlementdao := new(dao.MongoSettlementDAO)
+	basicsettlement := new(service.BasicSettlement)
+	basicsalesreport := new(service.BasicSalesReport)
+	ownerdao := new(dao.MongoOwnerDAO)
+	basicowner := new(service.BasicOwner)
+	basicapikey := new(service.BasicAPIKey)
+	bulkjobdao := new(dao.MongoBulkJobDAO)
+	auditdao := new(dao.MongoAuditDAO)
+	basicbulk := new(service.BasicBulk)
+	packtemplatedao := new(dao.MongoPackTemplateDAO)
+	availabilitydao := new(dao.MongoAvailabilityDAO)
+	basicavailability := new(service.BasicAvailability)
+	basicwebhook := new(service.BasicWebhook)
 	service.SetPackDAO(mongodao)
+	service.SetEntitlementDAO(entitlementdao)
+	service.SetSubscriptionDAO(subscriptiondao)
+	service.SetRenewalPolicy(loadRenewalPolicy())
+	service.SetOrderDAO(orderdao)
+	service.SetProvisionRetries(viper.GetInt("service.provisioning.attempts"),
+		time.Duration(viper.GetInt("service.provisioning.retryWait"))*time.Millisecond)
+	service.SetCommissionDAO(commissiondao)
+	service.SetSettlementDAO(settlementdao)
+	service.SetSettlementLocation(loadSettlementLocation())
+	service.SetOwnerDAO(ownerdao)
+	service.SetAPIKeyDAO(apiKeyDAO)
+	service.SetContentLocales(viper.GetString("service.locale.default"), loadMnoLocales())
+	service.SetBulkJobDAO(bulkjobdao)
+	service.SetAuditDAO(auditdao)
+	service.SetPackTemplateDAO(packtemplatedao)
+	service.SetAvailabilityDAO(availabilitydao)
+	service.SetOutboxDAO(new(dao.MongoOutboxDAO))
+	service.SetWebhookDAO(new(dao.MongoWebhookDAO))
+	service.SetWebhookDeliveryDAO(new(dao.MongoWebhookDeliveryDAO))
+	service.SetWebhookPolicy(loadWebhookPolicy())
 	controller.SetService(basicpack)
 	controller.SetHealthService(healthservice)
+	controller.SetEntitlementService(basicentitlement)
+	controller.SetSubscriptionService(basicsubscription)
+	controller.SetOrderService(basicorder)
+	controller.SetCommissionService(basiccommission)
+	controller.SetSettlementService(basicsettlement)
+	controller.SetSalesReportService(basicsalesreport)
+	controller.SetOwnerService(basicowner)
+	controller.SetAPIKeyService(basicapikey)
+	controller.SetBulkService(basicbulk)
+	controller.SetAvailabilityService(basicavailability)
+	controller.SetWebhookService(basicwebhook)
+	// the webhooks and the graphql subscriptions get the pack events like
+	// any other sink
+	events.Register("webhooks", basicwebhook)
+	broker := events.NewBroker(viper.GetInt("service.events.subscriptionBuffer"))
+	events.Register("subscriptions", broker)
+	controller.SetEventBroker(broker)
+}
+
+// initAuth sets the authenticators of the graphql callers, jwt is used
+// if there is a secret or a jwks file and api keys if they are enabled.
+func initAuth() {
+	chain := auth.Chain{}
+	var jwtconfig auth.JWTConfig
+	err := viper.UnmarshalKey("service.auth.jwt", &jwtconfig)
+	if err != nil {
+		panic(err)
+	}
+	if jwtconfig.Secret != "" || jwtconfig.JWKSFile != "" {
+		jwt, err := auth.NewJWTAuthenticator(jwtconfig)
+		if err != nil {
+			panic(err)
+		}
+		chain = append(chain, jwt)
+	}
+	if viper.GetBool("service.auth.apiKeys") {
+		chain = append(chain, auth.NewAPIKeyAuthenticator(apiKeyDAO))
+	}
+	controller.SetAuthenticator(chain)
+}
+
+// initProvisioning registers the adapters used to activate packs in the
+// network of every mno.
+func initProvisioning() {
+	var configs []provisioning.Config
+	err := viper.UnmarshalKey("service.provisioning.adapters", &configs)
+	if err != nil {
+		panic(err)
+	}
+	for _, config := range configs {
+		err = provisioning.Configure(config)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+// initEventSinks registers the sinks the pack events are relayed to.
+func initEventSinks() {
+	var configs []events.Config
+	err := viper.UnmarshalKey("service.events.sinks", &configs)
+	if err != nil {
+		panic(err)
+	}
+	for _, config := range configs {
+		err = events.Configure(config)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+// loadRenewalPolicy reads the subscription renewal rules, a missing
+// parameter keeps its default value.
+func loadRenewalPolicy() model.RenewalPolicy {
+	policy := model.RenewalPolicy{
+		PriceNotice: 30 * 24 * time.Hour,
+		RetryBase:   5 * time.Minute,
+		RetryMax:    24 * time.Hour,
+		MaxAttempts: 5,
+	}
+	if days := viper.GetInt("service.subscription.priceNoticeDays"); days > 0 {
+		policy.PriceNotice = time.Duration(days) * 24 * time.Hour
+	}
+	if base := viper.GetInt("service.subscription.retryBase"); base > 0 {
+		policy.RetryBase = time.Duration(base) * time.Second
+	}
+	if max := viper.GetInt("service.subscription.retryMax"); max > 0 {
+		policy.RetryMax = time.Duration(max) * time.Second
+	}
+	if attempts := viper.GetInt("service.subscription.maxAttempts"); attempts > 0 {
+		policy.MaxAttempts = attempts
+	}
+	return policy
+}
+
+// loadWebhookPolicy reads the webhook delivery rules, a missing parameter
+// keeps its default value.
+func loadWebhookPolicy() model.WebhookPolicy {
+	policy := model.WebhookPolicy{
+		Timeout:     10 * time.Second,
+		RetryBase:   time.Minute,
+		RetryMax:    6 * time.Hour,
+		MaxAttempts: 8,
+	}
+	if timeout := viper.GetInt("service.webhooks.timeout"); timeout > 0 {
+		policy.Timeout = time.Duration(timeout) * time.Second
+	}
+	if base := viper.GetInt("service.webhooks.retryBase"); base > 0 {
+		policy.RetryBase = time.Duration(base) * time.Second
+	}
+	if max := viper.GetInt("service.webhooks.retryMax"); max > 0 {
+		policy.RetryMax = time.Duration(max) * time.Second
+	}
+	if attempts := viper.GetInt("service.webhooks.maxAttempts"); attempts > 0 {
+		policy.MaxAttempts = attempts
+	}
+	policy.AllowPrivateTargets = viper.GetBool("service.webhooks.allowPrivateTargets")
+	return policy
+}
+
+// loadMnoLocales reads the locale of the pack texts of every mno.
+func loadMnoLocales() map[int8]string {
+	locales := map[int8]string{}
+	for key, locale := range viper.GetStringMapString("service.locale.mnos") {
+		mnoid, err := strconv.ParseInt(key, 10, 8)
+		if err != nil {
+			panic(fmt.Errorf("mno id %q of service.locale.mnos: %w", key, err))
+		}
+		locales[int8(mnoid)] = locale
+	}
+	return locales
+}
+
+// loadSettlementLocation reads the time zone of the settlement periods,
+// UTC by default.
+func loadSettlementLocation() *time.Location {
+	name := viper.GetString("service.settlement.timezone")
+	if name == "" {
+		return time.UTC
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return location
+}
+
+// initJobs starts the jobs that run in background until done is closed.
+// Bulk jobs left running by the last run of the service are interrupted.
+func initJobs(done <-chan struct{}) {
+	interrupted, err := new(service.BasicBulk).InterruptRunning()
+	if err != nil {
+		log.Errorf("cannot interrupt the bulk jobs of the last run: %s", err)
+	}
+	if interrupted > 0 {
+		log.Warnf("%d bulk jobs of the last run were interrupted", interrupted)
+	}
+
+	sweep := time.Duration(viper.GetInt("service.entitlement.sweepInterval")) * time.Second
+	if sweep <= 0 {
+		sweep = time.Minute
+	}
+	go service.RunJob("close expired entitlements", new(service.BasicEntitlement).CloseExpired, sweep, done)
+
+	renew := time.Duration(viper.GetInt("service.subscription.renewInterval")) * time.Second
+	if renew <= 0 {
+		renew = time.Minute
+	}
+	go service.RunJob("renew subscriptions", new(service.BasicSubscription).RenewDue, renew, done)
+
+	provision := time.Duration(viper.GetInt("service.provisioning.retryInterval")) * time.Second
+	if provision <= 0 {
+		provision = 5 * time.Second
+	}
+	go service.RunJob("retry pending orders", new(service.BasicOrder).ProvisionDue, provision, done)
+
+	settle := time.Duration(viper.GetInt("service.settlement.settleInterval")) * time.Second
+	if settle <= 0 {
+		settle = time.Hour
+	}
+	go service.RunJob("settle resellers", new(service.BasicSettlement).SettleDue, settle, done)
+
+	relay := time.Duration(viper.GetInt("service.events.relayInterval")) * time.Second
+	if relay <= 0 {
+		relay = time.Second
+	}
+	go service.RunJob("relay pack events", new(service.BasicEvent).Relay, relay, done)
+
+	deliver := time.Duration(viper.GetInt("service.webhooks.deliverInterval")) * time.Second
+	if deliver <= 0 {
+		deliver = 5 * time.Second
+	}
+	go service.RunJob("deliver webhooks", new(service.BasicWebhook).DeliverDue, deliver, done)
 }
 
 // initLogger Initialize logger
@@ -102,9 +371,33 @@ func initDb() {
 	dao.SetTimeout(viper.GetInt("service.mongo.timeout"))
 	// Initialize and store a Mongo session for every requests.
 	dao.InitMgoSession()
+	if err := dao.EnsurePackIndexes(); err != nil {
+		log.Errorf("cannot create the pack indexes: %s", err)
+	}
+	if err := dao.EnsureWebhookIndexes(); err != nil {
+		log.Errorf("cannot create the webhook delivery indexes: %s", err)
+	}
+	if err := dao.EnsureOrderIndexes(); err != nil {
+		log.Errorf("cannot create the order indexes: %s", err)
+	}
+	if err := dao.EnsureSubscriptionIndexes(); err != nil {
+		log.Errorf("cannot create the subscription indexes: %s", err)
+	}
 	log.Info("...Mongo session is ready")
 }
 
+// initGRPCServer starts the grpc server on the configuration parameter
+// port, there is no grpc server without port.
+func initGRPCServer() {
+	port := viper.GetString("service.grpc.port")
+	if port == "" {
+		log.Println("gRPC server is disabled")
+		return
+	}
+	log.Println("Starting gRPC application on ", port)
+	controller.StartGRPCServer(port)
+}
+
 // initHTTPServer start webserver on the configuration parameter host.
 func initHTTPServer() {
 	log.Println("Starting pack service")
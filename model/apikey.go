@@ -0,0 +1,62 @@
+package model
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// apiKeyPrefix starts every api key so they are easy to spot in logs
+// and configuration files.
+const apiKeyPrefix = "pk_"
+
+// APIKey contains a credential of a machine caller, only the hash of the
+// key is stored.
+type APIKey struct {
+	ID       bson.ObjectId `json:"id,omitempty" bson:"_id,omitempty"` // id of the key in the db
+	Name     string        `json:"name" bson:"name"`                  // what the key is used for
+	Hash     string        `json:"-" bson:"hash"`                     // sha256 of the key
+	Prefix   string        `json:"prefix" bson:"prefix"`              // first characters of the key to recognize it
+	Roles    []string      `json:"roles" bson:"roles"`                // roles granted to the caller
+	Ownerid  int           `json:"ownerid" bson:"ownerid"`            // owner the caller acts for, 0 if any
+	MnoID    int8          `json:"mnoid" bson:"mnoid"`                // mno the caller acts for, 0 if any
+	Channel  string        `json:"channel" bson:"channel,omitempty"`  // channel the caller sells in, empty if any
+	Revoked  bool          `json:"revoked" bson:"revoked"`            // revoked keys are not accepted
+	Created  time.Time     `json:"created,omitempty" bson:"created"`
+	LastUsed time.Time     `json:"lastused,omitempty" bson:"lastused,omitempty"`
+}
+
+// NewAPIKey creates an api key with a random secret. The secret is
+// returned only here, the key keeps its hash.
+func NewAPIKey(name string, roles []string, ownerid int, mnoid int8) (*APIKey, string, error) {
+	secret := make([]byte, 24)
+	_, err := rand.Read(secret)
+	if err != nil {
+		return nil, "", err
+	}
+	plain := apiKeyPrefix + hex.EncodeToString(secret)
+	newkey := new(APIKey)
+	newkey.Name = name
+	newkey.Hash = HashAPIKey(plain)
+	newkey.Prefix = plain[:len(apiKeyPrefix)+6]
+	newkey.Roles = roles
+	newkey.Ownerid = ownerid
+	newkey.MnoID = mnoid
+	newkey.Created = time.Now()
+	return newkey, plain, nil
+}
+
+// HashAPIKey returns the hash used to store and look up an api key.
+func HashAPIKey(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
+
+// Identity returns the identity of the callers that use the key.
+func (k *APIKey) Identity() *Identity {
+	return &Identity{Subject: "apikey:" + k.ID.Hex(), Method: "apikey", Roles: k.Roles,
+		Ownerid: k.Ownerid, MnoID: k.MnoID, Channel: k.Channel}
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestNewAPIKey tests that only the hash of a new api key is kept
+func TestNewAPIKey(t *testing.T) {
+	key, plain, err := NewAPIKey("billing", []string{RolePlatformAdmin}, 7, 2)
+
+	if err != nil {
+		t.Fatalf("Expected err to be nil but it was: %s", err)
+	}
+	if !strings.HasPrefix(plain, "pk_") || len(plain) != 51 {
+		t.Fatalf("Expected a pk_ key of 51 characters but got %q", plain)
+	}
+	if key.Hash != HashAPIKey(plain) || strings.Contains(key.Hash, plain) {
+		t.Fatalf("Expected the hash of the key but got %s", key.Hash)
+	}
+	if !strings.HasPrefix(plain, key.Prefix) {
+		t.Fatalf("Expected prefix %s to start the key", key.Prefix)
+	}
+
+	_, other, _ := NewAPIKey("billing", nil, 0, 0)
+	if other == plain {
+		t.Fatalf("Expected different keys but got the same twice")
+	}
+}
+
+// TestIdentityTenant tests the tenant of a caller
+func TestIdentityTenant(t *testing.T) {
+	identity := &Identity{Roles: []string{RolePlatformAdmin}, Ownerid: 7, MnoID: 2}
+
+	tenant := identity.Tenant()
+
+	if !tenant.Admin || tenant.Ownerid != 7 || tenant.MnoID != 2 {
+		t.Fatalf("Expected admin tenant of owner 7 in mno 2 but got %+v", tenant)
+	}
+	var nobody *Identity
+	if nobody.Tenant() != nil || nobody.HasRole(RolePlatformAdmin) {
+		t.Fatalf("Expected no tenant and no roles for a nil identity")
+	}
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Audit actions
+const (
+	AuditBulkUpdate      = "bulkUpdate"      // catalog data changed by a bulk update
+	AuditBulkChangeState = "bulkChangeState" // state changed by a bulk state change
+)
+
+// AuditChange contains the value of a pack field before and after a change.
+type AuditChange struct {
+	Field  string `json:"field" bson:"field"`   // name of the changed field. e.g. price, type
+	Before string `json:"before" bson:"before"` // value before the change as json
+	After  string `json:"after" bson:"after"`   // value after the change as json
+}
+
+// AuditEntry records who changed a pack, when and what changed.
+type AuditEntry struct {
+	ID      bson.ObjectId `json:"id,omitempty" bson:"_id,omitempty"` // id of the entry in the db
+	PackID  string        `json:"packid" bson:"packid"`              // id of the changed pack
+	Action  string        `json:"action" bson:"action"`              // what was done. e.g. bulkUpdate
+	Subject string        `json:"subject" bson:"subject"`            // caller that made the change
+	JobID   string        `json:"jobid,omitempty" bson:"jobid"`      // bulk job that made the change
+	Changes []AuditChange `json:"changes" bson:"changes"`            // changed fields
+	Created time.Time     `json:"created" bson:"created"`
+}
+
+// NewAuditChange creates the change of a field, values are stored as json
+// so numbers and nested data can be told apart.
+func NewAuditChange(field string, before interface{}, after interface{}) AuditChange {
+	return AuditChange{Field: field, Before: auditValue(before), After: auditValue(after)}
+}
+
+// auditValue returns the given value as json, empty for nil.
+func auditValue(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	content, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(content)
+}
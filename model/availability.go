@@ -0,0 +1,98 @@
+package model
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Kinds of availability codes
+const (
+	AvailabilityChannel = "channel" // where the pack is sold. e.g. app, web, ussd, retail
+	AvailabilityRegion  = "region"  // region of the country where the pack is sold
+)
+
+// availabilityCodePattern matches a valid channel or region code.
+var availabilityCodePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+// AvailabilityCode contains a channel or region of the managed catalog,
+// packs can only be sold in the codes of the catalog.
+type AvailabilityCode struct {
+	ID      bson.ObjectId `json:"id,omitempty" bson:"_id,omitempty"` // id of the code in the db
+	Kind    string        `json:"kind" bson:"kind"`                  // channel or region
+	Code    string        `json:"code" bson:"code"`                  // lower case code, unique by kind
+	Name    string        `json:"name" bson:"name"`                  // name to show
+	Created time.Time     `json:"created" bson:"created"`
+}
+
+// NewAvailabilityCode creates an AvailabilityCode of the given kind with
+// the given parameters.
+func NewAvailabilityCode(kind string, params map[string]interface{}) *AvailabilityCode {
+	newcode := &AvailabilityCode{Kind: kind}
+	code, _ := params["code"].(string)
+	newcode.Code = NormalizeAvailabilityCode(code)
+	newcode.Name, _ = params["name"].(string)
+	newcode.Name = strings.TrimSpace(newcode.Name)
+	return newcode
+}
+
+// NormalizeAvailabilityCode returns the code trimmed and in lower case.
+func NormalizeAvailabilityCode(code string) string {
+	return strings.ToLower(strings.TrimSpace(code))
+}
+
+// NewAvailabilityCodes returns the normalized codes of the given list
+// argument, blank and repeated codes are removed.
+func NewAvailabilityCodes(params interface{}) []string {
+	codes := []string{}
+	seen := map[string]bool{}
+	for _, code := range stringList(params) {
+		code = NormalizeAvailabilityCode(code)
+		if !seen[code] {
+			seen[code] = true
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+// InvalidField returns the name of the first field of the code with a
+// wrong value, empty if every value is right.
+func (a *AvailabilityCode) InvalidField() string {
+	switch {
+	case a.Kind != AvailabilityChannel && a.Kind != AvailabilityRegion:
+		return "kind"
+	case !availabilityCodePattern.MatchString(a.Code):
+		return "code"
+	case a.Name == "":
+		return "name"
+	}
+	return ""
+}
+
+// AvailableIn returns true if the pack is sold in the given channel and
+// region. Empty lists of the pack mean every channel or region and an
+// empty channel or region is not checked.
+func (p *Pack) AvailableIn(channel string, region string) bool {
+	if p == nil {
+		return false
+	}
+	return availableIn(p.Channels, channel) && availableIn(p.Regions, region)
+}
+
+// availableIn returns true if the code is in the list, the list is empty
+// or the code is empty.
+func availableIn(list []string, code string) bool {
+	code = NormalizeAvailabilityCode(code)
+	if code == "" || len(list) == 0 {
+		return true
+	}
+	for _, item := range list {
+		if item == code {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package model
+
+import "testing"
+
+// TestAvailableIn tests in which channels and regions a pack is sold
+func TestAvailableIn(t *testing.T) {
+	pack := createExpPack()
+	pack.Channels = []string{"app", "ussd"}
+	pack.Regions = []string{"north"}
+	tests := []struct {
+		name    string
+		channel string
+		region  string
+		want    bool
+	}{
+		{name: "any", want: true},
+		{name: "channel", channel: "app", want: true},
+		{name: "channel in other case", channel: " USSD ", want: true},
+		{name: "other channel", channel: "retail", want: false},
+		{name: "channel and region", channel: "app", region: "north", want: true},
+		{name: "other region", channel: "app", region: "south", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pack.AvailableIn(tt.channel, tt.region); got != tt.want {
+				t.Errorf("Pack.AvailableIn() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	everywhere := createExpPack()
+	if !everywhere.AvailableIn("retail", "south") {
+		t.Errorf("Expected a pack without lists to be sold everywhere")
+	}
+	filter := NewPackFilter(map[string]interface{}{"channel": "Retail"})
+	if filter.Matches(pack) || !filter.Matches(everywhere) {
+		t.Errorf("Expected the filter of the retail channel to match only the pack sold everywhere")
+	}
+}
+
+// TestAvailabilityCodeInvalidField tests the validation of the channel
+// and region codes
+func TestAvailabilityCodeInvalidField(t *testing.T) {
+	tests := []struct {
+		name   string
+		kind   string
+		params map[string]interface{}
+		want   string
+	}{
+		{name: "valid", kind: AvailabilityChannel, params: map[string]interface{}{"code": " USSD ", "name": "Ussd"}, want: ""},
+		{name: "region", kind: AvailabilityRegion, params: map[string]interface{}{"code": "north_1", "name": "North"}, want: ""},
+		{name: "unknown kind", kind: "country", params: map[string]interface{}{"code": "co", "name": "Colombia"}, want: "kind"},
+		{name: "blank code", kind: AvailabilityChannel, params: map[string]interface{}{"code": " ", "name": "App"}, want: "code"},
+		{name: "spaces in code", kind: AvailabilityChannel, params: map[string]interface{}{"code": "retail shop", "name": "Shops"}, want: "code"},
+		{name: "no name", kind: AvailabilityRegion, params: map[string]interface{}{"code": "south"}, want: "name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewAvailabilityCode(tt.kind, tt.params).InvalidField(); got != tt.want {
+				t.Errorf("AvailabilityCode.InvalidField() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+	codes := NewAvailabilityCodes([]interface{}{"App", " app", "", "Web"})
+	if len(codes) != 2 || codes[0] != "app" || codes[1] != "web" {
+		t.Errorf("Expected app and web but got %v", codes)
+	}
+}
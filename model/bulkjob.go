@@ -0,0 +1,198 @@
+package model
+
+import (
+	"math"
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Kinds of bulk jobs
+const (
+	BulkUpdate      = "update" // applies a patch to the packs
+	BulkChangeState = "state"  // changes the state of the packs
+)
+
+// Status of bulk jobs
+const (
+	BulkPreview     = "preview"     // the packs were counted, nothing is changed
+	BulkPending     = "pending"     // the job is stored and it is about to run
+	BulkRunning     = "running"     // the packs are being changed
+	BulkDone        = "done"        // every pack was processed
+	BulkInterrupted = "interrupted" // the job stopped before every pack was processed
+)
+
+// MaxBulkFailures is the number of pack failures kept in a job, the
+// failed counter has them all.
+const MaxBulkFailures = 100
+
+// PackPatch contains the catalog data changed by a bulk update, fields
+// that are not set are kept.
+type PackPatch struct {
+	Price        *int      `json:"price,omitempty" bson:"price,omitempty"`               // new price
+	PricePercent float64   `json:"pricepercent,omitempty" bson:"pricepercent,omitempty"` // percent added to the price, negative to reduce it
+	Packtype     *Type     `json:"type,omitempty" bson:"type,omitempty"`                 // new pack type
+	Term         *Term     `json:"term,omitempty" bson:"term,omitempty"`                 // new validity
+	Ccy          *Currency `json:"currency,omitempty" bson:"currency,omitempty"`         // new currency
+	Img          string    `json:"imgurl,omitempty" bson:"imgurl,omitempty"`             // new image url
+	Kwds         string    `json:"kwds,omitempty" bson:"kwds,omitempty"`                 // new keywords
+}
+
+// NewPackPatch creates a PackPatch with the given parameters, they have
+// the names of the create arguments.
+func NewPackPatch(params map[string]interface{}) *PackPatch {
+	patch := new(PackPatch)
+	if price, ok := params["price"].(int); ok {
+		patch.Price = &price
+	}
+	if percent, ok := params["pricepercent"].(float64); ok {
+		patch.PricePercent = percent
+	}
+	if packtype, ok := params["type"].(map[string]interface{}); ok {
+		patch.Packtype = NewType(packtype)
+	}
+	if term, ok := params["term"].(map[string]interface{}); ok {
+		patch.Term = NewTerm(term)
+	}
+	if ccy, ok := params["currency"].(map[string]interface{}); ok {
+		patch.Ccy = NewCurrency(ccy)
+	}
+	if img, ok := params["imgurl"].(string); ok {
+		patch.Img = img
+	}
+	if kwds, ok := params["kwds"].(string); ok {
+		patch.Kwds = kwds
+	}
+	return patch
+}
+
+// IsEmpty returns true if the patch does not change anything.
+func (p *PackPatch) IsEmpty() bool {
+	return p == nil || (p.Price == nil && p.PricePercent == 0 && p.Packtype == nil &&
+		p.Term == nil && p.Ccy == nil && p.Img == "" && p.Kwds == "")
+}
+
+// ChangesPrice returns true if the patch sets or moves the price.
+func (p *PackPatch) ChangesPrice() bool {
+	return p != nil && (p.Price != nil || p.PricePercent != 0)
+}
+
+// ChangesCatalog returns true if the patch changes something but the price.
+func (p *PackPatch) ChangesCatalog() bool {
+	return p != nil && (p.Packtype != nil || p.Term != nil || p.Ccy != nil || p.Img != "" || p.Kwds != "")
+}
+
+// InvalidField returns the name of the first field of the patch with a
+// wrong value, empty if every value is right.
+func (p *PackPatch) InvalidField() string {
+	switch {
+	case p.Price != nil && p.PricePercent != 0:
+		return "pricepercent"
+	case p.Price != nil && *p.Price < 0:
+		return "price"
+	case p.PricePercent <= -100 || math.IsNaN(p.PricePercent) || math.IsInf(p.PricePercent, 0):
+		return "pricepercent"
+	case p.Packtype != nil && (p.Packtype.ID < 1 || p.Packtype.Name == ""):
+		return "type"
+	case p.Term != nil && (p.Term.UnitID < 1 || p.Term.Unit == ""):
+		return "term"
+	case p.Ccy != nil && (p.Ccy.ID < 1 || p.Ccy.Name == ""):
+		return "currency"
+	}
+	return ""
+}
+
+// Apply changes the given pack with the patch and returns what changed,
+// values that are already there are not changes. A price moved by a
+// percent is rounded to the nearest unit.
+func (p *PackPatch) Apply(pack *Pack) []AuditChange {
+	changes := []AuditChange{}
+	price := pack.Price
+	if p.Price != nil {
+		price = *p.Price
+	}
+	if p.PricePercent != 0 {
+		price = int(math.Round(float64(pack.Price) * (100 + p.PricePercent) / 100))
+	}
+	if price != pack.Price {
+		changes = append(changes, NewAuditChange("price", pack.Price, price))
+		pack.Price = price
+	}
+	if p.Packtype != nil && (pack.Packtype == nil || *pack.Packtype != *p.Packtype) {
+		changes = append(changes, NewAuditChange("type", pack.Packtype, p.Packtype))
+		packtype := *p.Packtype
+		pack.Packtype = &packtype
+	}
+	if p.Term != nil && (pack.Term == nil || *pack.Term != *p.Term) {
+		changes = append(changes, NewAuditChange("term", pack.Term, p.Term))
+		term := *p.Term
+		pack.Term = &term
+	}
+	if p.Ccy != nil && (pack.Ccy == nil || *pack.Ccy != *p.Ccy) {
+		changes = append(changes, NewAuditChange("currency", pack.Ccy, p.Ccy))
+		ccy := *p.Ccy
+		pack.Ccy = &ccy
+	}
+	if p.Img != "" && p.Img != pack.Img {
+		changes = append(changes, NewAuditChange("imgurl", pack.Img, p.Img))
+		pack.Img = p.Img
+	}
+	if p.Kwds != "" && p.Kwds != pack.Kwds {
+		changes = append(changes, NewAuditChange("kwds", pack.Kwds, p.Kwds))
+		pack.Kwds = p.Kwds
+	}
+	return changes
+}
+
+// BulkFailure contains why a pack of a bulk job was not changed.
+type BulkFailure struct {
+	PackID   string       `json:"packid" bson:"packid"`     // id of the pack
+	Packcode string       `json:"packcode" bson:"packcode"` // code of the pack
+	Error    *ErrorDetail `json:"error" bson:"error"`       // details of the failure
+}
+
+// BulkJob contains a change of every pack that matches a filter and its
+// progress. The packs are changed in background.
+type BulkJob struct {
+	ID        bson.ObjectId `json:"id,omitempty" bson:"_id,omitempty"`          // id of the job in the db
+	Kind      string        `json:"kind" bson:"kind"`                           // update or state
+	Filter    *PackFilter   `json:"filter" bson:"filter"`                       // packs to change
+	Patch     *PackPatch    `json:"patch,omitempty" bson:"patch,omitempty"`     // catalog data of an update
+	State     PackState     `json:"state" bson:"state"`                         // new state of a state change
+	Tenant    *Tenant       `json:"tenant" bson:"tenant"`                       // tenant whose packs are changed
+	Subject   string        `json:"subject" bson:"subject"`                     // caller that started the job
+	Locale    string        `json:"locale" bson:"locale"`                       // locale of the failure messages
+	Status    string        `json:"status" bson:"status"`                       // preview, pending, running, done or interrupted
+	Total     int           `json:"total" bson:"total"`                         // packs that match the filter
+	Processed int           `json:"processed" bson:"processed"`                 // packs already processed
+	Succeeded int           `json:"succeeded" bson:"succeeded"`                 // packs changed
+	Skipped   int           `json:"skipped" bson:"skipped"`                     // packs that already had the data or stopped matching
+	Failed    int           `json:"failed" bson:"failed"`                       // packs that could not be changed
+	Failures  []BulkFailure `json:"failures,omitempty" bson:"failures"`         // first failures of the job
+	Created   time.Time     `json:"created" bson:"created"`                     // when the job was started
+	Started   time.Time     `json:"started,omitempty" bson:"started,omitempty"` // when the packs started to be changed
+	Finished  time.Time     `json:"finished,omitempty" bson:"finished,omitempty"`
+}
+
+// NewBulkUpdate creates a job that applies the patch to the packs that
+// match the filter.
+func NewBulkUpdate(filter *PackFilter, patch *PackPatch) *BulkJob {
+	return &BulkJob{Kind: BulkUpdate, Filter: filter, Patch: patch}
+}
+
+// NewBulkChangeState creates a job that changes the state of the packs
+// that match the filter.
+func NewBulkChangeState(filter *PackFilter, state PackState) *BulkJob {
+	return &BulkJob{Kind: BulkChangeState, Filter: filter, State: state}
+}
+
+// AddFailure counts a pack that could not be changed, only the first
+// MaxBulkFailures failures are kept.
+func (j *BulkJob) AddFailure(packid string, packcode string, detail *ErrorDetail) {
+	j.Processed++
+	j.Failed++
+	if len(j.Failures) >= MaxBulkFailures {
+		return
+	}
+	j.Failures = append(j.Failures, BulkFailure{PackID: packid, Packcode: packcode, Error: detail})
+}
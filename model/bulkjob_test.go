@@ -0,0 +1,100 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestPackPatchApply tests the changes of a patch to a pack
+func TestPackPatchApply(t *testing.T) {
+	price := 3000
+	tests := []struct {
+		name      string
+		patch     *PackPatch
+		wantPrice int
+		want      []string
+	}{
+		{name: "price", patch: &PackPatch{Price: &price}, wantPrice: 3000, want: []string{"price"}},
+		{name: "raise 5%", patch: &PackPatch{PricePercent: 5}, wantPrice: 2625, want: []string{"price"}},
+		{name: "reduce 10.5%", patch: &PackPatch{PricePercent: -10.5}, wantPrice: 2238, want: []string{"price"}},
+		{name: "same type", patch: &PackPatch{Packtype: &Type{ID: 1, Name: "App"}}, wantPrice: 2500, want: []string{}},
+		{name: "type and keywords", patch: &PackPatch{Packtype: &Type{ID: 2, Name: "Data"}, Kwds: "datos"},
+			wantPrice: 2500, want: []string{"type", "kwds"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pack := createExpPack()
+			changes := tt.patch.Apply(pack)
+			fields := []string{}
+			for _, change := range changes {
+				fields = append(fields, change.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.want) {
+				t.Errorf("PackPatch.Apply() changed %v, want %v", fields, tt.want)
+			}
+			if pack.Price != tt.wantPrice {
+				t.Errorf("PackPatch.Apply() price = %d, want %d", pack.Price, tt.wantPrice)
+			}
+		})
+	}
+
+	pack := createExpPack()
+	changes := (&PackPatch{Packtype: &Type{ID: 2, Name: "Data"}}).Apply(pack)
+	want := AuditChange{Field: "type", Before: `{"id":1,"name":"App"}`, After: `{"id":2,"name":"Data"}`}
+	if len(changes) != 1 || changes[0] != want {
+		t.Errorf("PackPatch.Apply() = %+v, want %+v", changes, want)
+	}
+}
+
+// TestPackPatchInvalidField tests the validation of a patch
+func TestPackPatchInvalidField(t *testing.T) {
+	negative := -1
+	price := 100
+	tests := []struct {
+		name  string
+		patch *PackPatch
+		want  string
+	}{
+		{name: "valid", patch: &PackPatch{PricePercent: 5, Kwds: "datos"}, want: ""},
+		{name: "negative price", patch: &PackPatch{Price: &negative}, want: "price"},
+		{name: "price and percent", patch: &PackPatch{Price: &price, PricePercent: 5}, want: "pricepercent"},
+		{name: "whole price", patch: &PackPatch{PricePercent: -100}, want: "pricepercent"},
+		{name: "type without name", patch: &PackPatch{Packtype: &Type{ID: 2}}, want: "type"},
+		{name: "term without unit", patch: &PackPatch{Term: &Term{UnitID: 1}}, want: "term"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.patch.InvalidField(); got != tt.want {
+				t.Errorf("PackPatch.InvalidField() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestNewPackPatch tests the patch of the graphql arguments
+func TestNewPackPatch(t *testing.T) {
+	patch := NewPackPatch(map[string]interface{}{
+		"pricepercent": 5.0,
+		"type":         map[string]interface{}{"id": 2, "name": "Data"},
+	})
+	if patch.PricePercent != 5 || patch.Packtype == nil || patch.Packtype.ID != 2 {
+		t.Errorf("NewPackPatch() = %+v", patch)
+	}
+	if !patch.ChangesPrice() || !patch.ChangesCatalog() || patch.IsEmpty() {
+		t.Errorf("NewPackPatch() changes price %v, catalog %v", patch.ChangesPrice(), patch.ChangesCatalog())
+	}
+	if !NewPackPatch(map[string]interface{}{}).IsEmpty() {
+		t.Error("NewPackPatch() without arguments is not empty")
+	}
+}
+
+// TestBulkJobAddFailure tests that only the first failures are kept
+func TestBulkJobAddFailure(t *testing.T) {
+	job := new(BulkJob)
+	for i := 0; i < MaxBulkFailures+5; i++ {
+		job.AddFailure("id", "code", &ErrorDetail{Code: "01"})
+	}
+	if job.Failed != MaxBulkFailures+5 || job.Processed != MaxBulkFailures+5 || len(job.Failures) != MaxBulkFailures {
+		t.Errorf("BulkJob.AddFailure() failed %d, processed %d, kept %d", job.Failed, job.Processed, len(job.Failures))
+	}
+}
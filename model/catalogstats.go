@@ -0,0 +1,233 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Fields the catalog stats are grouped by
+const (
+	StatsByMno      = "mno"
+	StatsByType     = "type"
+	StatsByState    = "state"
+	StatsByOwner    = "owner"
+	StatsByCurrency = "currency"
+)
+
+// StatsGroups contains the fields the catalog stats can be grouped by.
+var StatsGroups = []string{StatsByMno, StatsByType, StatsByState, StatsByOwner, StatsByCurrency}
+
+// CatalogStats contains the figures of a group of packs. Only the fields
+// of the group are set, e.g. Mno is nil if the stats are not grouped by mno.
+type CatalogStats struct {
+	Mno        *Mno            `json:"mno"`
+	Packtype   *Type           `json:"type"`
+	State      *PackState      `json:"state"`
+	Ownerid    *int            `json:"ownerid"`
+	Ccy        *Currency       `json:"currency"`
+	Count      int             `json:"count"`      // packs of the group
+	MinPrice   int             `json:"minPrice"`   // lowest price
+	MaxPrice   int             `json:"maxPrice"`   // highest price
+	AvgPrice   float64         `json:"avgPrice"`   // average price rounded to two decimals
+	TotalStock int             `json:"totalStock"` // stock of every pack
+	Resources  []ResourceTotal `json:"resources"`  // amounts of every resource
+}
+
+// ResourceTotal contains the amount of a resource in every pack of a group.
+type ResourceTotal struct {
+	ID    int16   `json:"id"`
+	Name  string  `json:"name"`
+	Units string  `json:"units"`
+	Total float64 `json:"total"`
+}
+
+// InvalidStatsGroup returns the first value of the given fields that the
+// stats cannot be grouped by or that is repeated, empty if every one is
+// right.
+func InvalidStatsGroup(groupBy []string) string {
+	seen := map[string]bool{}
+	for _, field := range groupBy {
+		known := false
+		for _, group := range StatsGroups {
+			known = known || field == group
+		}
+		if !known || seen[field] {
+			return field
+		}
+		seen[field] = true
+	}
+	return ""
+}
+
+// NewStatsGroups returns the fields of the given list argument in lower
+// case, blank ones are removed.
+func NewStatsGroups(params interface{}) []string {
+	groupBy := []string{}
+	for _, field := range stringList(params) {
+		groupBy = append(groupBy, strings.ToLower(field))
+	}
+	return groupBy
+}
+
+// StatsAccumulator calculates the catalog stats of packs one by one, so
+// they are the same for any backend that can read the packs.
+type StatsAccumulator struct {
+	groupBy []string
+	groups  map[string]*CatalogStats
+	totals  map[string]int
+}
+
+// NewStatsAccumulator creates a StatsAccumulator of the given fields.
+func NewStatsAccumulator(groupBy []string) *StatsAccumulator {
+	return &StatsAccumulator{groupBy: groupBy, groups: map[string]*CatalogStats{}, totals: map[string]int{}}
+}
+
+// Add adds the pack to the stats of its group.
+func (a *StatsAccumulator) Add(pack *Pack) {
+	key := a.groupKey(pack)
+	stats, ok := a.groups[key]
+	if !ok {
+		stats = a.newGroup(pack)
+		stats.MinPrice = pack.Price
+		stats.MaxPrice = pack.Price
+		a.groups[key] = stats
+	}
+	stats.Count++
+	a.totals[key] += pack.Price
+	if pack.Price < stats.MinPrice {
+		stats.MinPrice = pack.Price
+	}
+	if pack.Price > stats.MaxPrice {
+		stats.MaxPrice = pack.Price
+	}
+	stats.TotalStock += pack.Stock
+	for _, resource := range pack.Resources {
+		stats.AddResource(resource.ID, resource.Name, resource.Units, float64(resource.Amount))
+	}
+}
+
+// Stats returns the stats of every group sorted by the group fields.
+func (a *StatsAccumulator) Stats() []CatalogStats {
+	result := make([]CatalogStats, 0, len(a.groups))
+	for key, stats := range a.groups {
+		stats.AvgPrice = math.Round(float64(a.totals[key])/float64(stats.Count)*100) / 100
+		result = append(result, *stats)
+	}
+	SortCatalogStats(result)
+	return result
+}
+
+// groupKey returns the values of the group fields of the pack.
+func (a *StatsAccumulator) groupKey(pack *Pack) string {
+	values := make([]string, 0, len(a.groupBy))
+	for _, field := range a.groupBy {
+		switch field {
+		case StatsByMno:
+			values = append(values, fmt.Sprint(idOf(pack.Mno)))
+		case StatsByType:
+			values = append(values, fmt.Sprint(idOf(pack.Packtype)))
+		case StatsByState:
+			values = append(values, fmt.Sprint(pack.State))
+		case StatsByOwner:
+			values = append(values, fmt.Sprint(pack.Ownerid))
+		case StatsByCurrency:
+			values = append(values, fmt.Sprint(idOf(pack.Ccy)))
+		}
+	}
+	return strings.Join(values, "|")
+}
+
+// newGroup returns empty stats with the group fields of the pack.
+func (a *StatsAccumulator) newGroup(pack *Pack) *CatalogStats {
+	stats := &CatalogStats{Resources: []ResourceTotal{}}
+	for _, field := range a.groupBy {
+		switch field {
+		case StatsByMno:
+			stats.Mno = &Mno{}
+			if pack.Mno != nil {
+				*stats.Mno = *pack.Mno
+			}
+		case StatsByType:
+			stats.Packtype = &Type{}
+			if pack.Packtype != nil {
+				*stats.Packtype = *pack.Packtype
+			}
+		case StatsByState:
+			state := pack.State
+			stats.State = &state
+		case StatsByOwner:
+			ownerid := pack.Ownerid
+			stats.Ownerid = &ownerid
+		case StatsByCurrency:
+			stats.Ccy = &Currency{}
+			if pack.Ccy != nil {
+				*stats.Ccy = *pack.Ccy
+			}
+		}
+	}
+	return stats
+}
+
+// idOf returns the id of a mno, type or currency, 0 if it is nil.
+func idOf(value interface{}) int8 {
+	switch data := value.(type) {
+	case *Mno:
+		if data != nil {
+			return data.ID
+		}
+	case *Type:
+		if data != nil {
+			return data.ID
+		}
+	case *Currency:
+		if data != nil {
+			return data.ID
+		}
+	}
+	return 0
+}
+
+// AddResource adds the amount to the total of the resource with the given
+// id and units.
+func (s *CatalogStats) AddResource(id int16, name string, units string, amount float64) {
+	for i := range s.Resources {
+		if s.Resources[i].ID == id && s.Resources[i].Units == units {
+			s.Resources[i].Total += amount
+			return
+		}
+	}
+	s.Resources = append(s.Resources, ResourceTotal{ID: id, Name: name, Units: units, Total: amount})
+	sort.Slice(s.Resources, func(i, j int) bool {
+		if s.Resources[i].ID != s.Resources[j].ID {
+			return s.Resources[i].ID < s.Resources[j].ID
+		}
+		return s.Resources[i].Units < s.Resources[j].Units
+	})
+}
+
+// SortCatalogStats sorts the stats by mno, type, state, owner and currency.
+func SortCatalogStats(stats []CatalogStats) {
+	sort.SliceStable(stats, func(i, j int) bool {
+		a, b := stats[i].sortKey(), stats[j].sortKey()
+		for k := range a {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return false
+	})
+}
+
+// sortKey returns the values of the group fields of the stats.
+func (s *CatalogStats) sortKey() [5]int {
+	key := [5]int{int(idOf(s.Mno)), int(idOf(s.Packtype)), 0, 0, int(idOf(s.Ccy))}
+	if s.State != nil {
+		key[2] = int(*s.State)
+	}
+	if s.Ownerid != nil {
+		key[3] = *s.Ownerid
+	}
+	return key
+}
@@ -0,0 +1,80 @@
+package model
+
+import "testing"
+
+// TestInvalidStatsGroup tests only known fields are grouped, once each
+func TestInvalidStatsGroup(t *testing.T) {
+	tests := []struct {
+		name    string
+		groupBy []string
+		want    string
+	}{
+		{name: "no group", groupBy: nil, want: ""},
+		{name: "every field", groupBy: StatsGroups, want: ""},
+		{name: "unknown field", groupBy: []string{"mno", "price"}, want: "price"},
+		{name: "repeated field", groupBy: []string{"type", "type"}, want: "type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InvalidStatsGroup(tt.groupBy); got != tt.want {
+				t.Errorf("InvalidStatsGroup() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestNewStatsGroups tests group fields are trimmed and lower cased
+func TestNewStatsGroups(t *testing.T) {
+	groupBy := NewStatsGroups([]interface{}{" MNO", "", "State"})
+
+	if len(groupBy) != 2 || groupBy[0] != "mno" || groupBy[1] != "state" {
+		t.Errorf("Expected mno and state but got %v", groupBy)
+	}
+}
+
+// TestStatsAccumulator tests the figures of every group of packs
+func TestStatsAccumulator(t *testing.T) {
+	claro, tigo := &Mno{ID: 2, Name: "Claro"}, &Mno{ID: 3, Name: "Tigo"}
+	packs := []*Pack{
+		{Mno: tigo, Price: 1000, Stock: 5, Resources: []Resource{{ID: 1, Name: "data", Units: "MB", Amount: 500}}},
+		{Mno: claro, Price: 3000, Stock: 10, Resources: []Resource{{ID: 1, Name: "data", Units: "MB", Amount: 100}}},
+		{Mno: claro, Price: 1000, Stock: 2, Resources: []Resource{{ID: 2, Name: "sms", Units: "sms", Amount: 50},
+			{ID: 1, Name: "data", Units: "MB", Amount: 200}}},
+		{Mno: claro, Price: 1001, Stock: 0},
+	}
+	accumulator := NewStatsAccumulator([]string{StatsByMno})
+	for _, pack := range packs {
+		accumulator.Add(pack)
+	}
+
+	stats := accumulator.Stats()
+
+	if len(stats) != 2 || stats[0].Mno.ID != 2 || stats[1].Mno.ID != 3 {
+		t.Fatalf("Expected the groups of claro and tigo but got %+v", stats)
+	}
+	group := stats[0]
+	if group.Count != 3 || group.MinPrice != 1000 || group.MaxPrice != 3000 || group.AvgPrice != 1667 ||
+		group.TotalStock != 12 {
+		t.Errorf("Expected 3 packs from 1000 to 3000 but got %+v", group)
+	}
+	if len(group.Resources) != 2 || group.Resources[0].Total != 300 || group.Resources[1].Total != 50 {
+		t.Errorf("Expected 300 MB and 50 sms but got %+v", group.Resources)
+	}
+	if group.Packtype != nil || group.State != nil || group.Ownerid != nil || group.Ccy != nil {
+		t.Errorf("Expected only the mno of the group but got %+v", group)
+	}
+}
+
+// TestStatsAccumulatorNoGroup tests every pack is in one group without
+// group fields
+func TestStatsAccumulatorNoGroup(t *testing.T) {
+	accumulator := NewStatsAccumulator(nil)
+	accumulator.Add(&Pack{Price: 1000, State: Active})
+	accumulator.Add(&Pack{Price: 2000, State: Inactive, Mno: &Mno{ID: 2}})
+
+	stats := accumulator.Stats()
+
+	if len(stats) != 1 || stats[0].Count != 2 || stats[0].AvgPrice != 1500 || stats[0].Mno != nil {
+		t.Errorf("Expected one group of 2 packs but got %+v", stats)
+	}
+}
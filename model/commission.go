@@ -0,0 +1,91 @@
+package model
+
+import (
+	"math"
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// CommissionKind defines how a commission is calculated
+type CommissionKind int8
+
+// Commission kinds
+const (
+	CommissionPercentage CommissionKind = 0 // a percentage of the sale price
+	CommissionFixed      CommissionKind = 1 // a fixed amount on every sale
+)
+
+// CommissionRule contains the commission a reseller earns on the sales of
+// its packs. A rule applies to every pack of the owner, to the packs of a
+// type or to a single pack, the most specific rule wins.
+type CommissionRule struct {
+	ID       bson.ObjectId  `json:"id,omitempty" bson:"_id,omitempty"` // id of the rule in the db
+	Ownerid  int            `json:"ownerid" bson:"ownerid"`            // company owner of the packs for resale
+	Packtype int8           `json:"packtype" bson:"packtype"`          // pack type the rule applies to, 0 for any
+	PackID   string         `json:"packid" bson:"packid"`              // hex id of the pack the rule applies to, empty for any
+	Kind     CommissionKind `json:"kind" bson:"kind"`                  // how the commission is calculated
+	Value    float64        `json:"value" bson:"value"`                // percentage or fixed amount
+	Created  time.Time      `json:"created,omitempty" bson:"created"`
+}
+
+// Applies returns true if the rule can be used for sales of the given pack.
+func (r *CommissionRule) Applies(pack *Pack) bool {
+	if pack == nil || r.Ownerid != pack.Ownerid {
+		return false
+	}
+	if r.PackID != "" && r.PackID != pack.ID.Hex() {
+		return false
+	}
+	if r.Packtype != 0 && (pack.Packtype == nil || r.Packtype != pack.Packtype.ID) {
+		return false
+	}
+	return true
+}
+
+// specificity ranks a rule, pack rules first, then pack type rules and
+// owner rules at last.
+func (r *CommissionRule) specificity() int {
+	switch {
+	case r.PackID != "":
+		return 2
+	case r.Packtype != 0:
+		return 1
+	}
+	return 0
+}
+
+// Compute returns the commission of a sale at the given price, it is
+// rounded to the nearest unit and it is never greater than the price.
+func (r *CommissionRule) Compute(price int) int {
+	if r == nil || price <= 0 {
+		return 0
+	}
+	commission := r.Value
+	if r.Kind == CommissionPercentage {
+		commission = float64(price) * r.Value / 100
+	}
+	result := int(math.Floor(commission + 0.5))
+	if result > price {
+		return price
+	}
+	if result < 0 {
+		return 0
+	}
+	return result
+}
+
+// SelectCommissionRule returns the most specific rule of the given ones
+// that applies to the pack, nil if there is none.
+func SelectCommissionRule(rules []CommissionRule, pack *Pack) *CommissionRule {
+	var selected *CommissionRule
+	for i := range rules {
+		if !rules[i].Applies(pack) {
+			continue
+		}
+		if selected == nil || rules[i].specificity() > selected.specificity() {
+			selected = &rules[i]
+		}
+	}
+	return selected
+}
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"testing"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// TestCommissionRuleCompute tests percentage and fixed commissions
+func TestCommissionRuleCompute(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  *CommissionRule
+		price int
+		want  int
+	}{
+		{name: "percentage", rule: &CommissionRule{Kind: CommissionPercentage, Value: 10}, price: 2500, want: 250},
+		{name: "rounded percentage", rule: &CommissionRule{Kind: CommissionPercentage, Value: 2.5}, price: 1010, want: 25},
+		{name: "fixed", rule: &CommissionRule{Kind: CommissionFixed, Value: 300}, price: 2500, want: 300},
+		{name: "fixed over price", rule: &CommissionRule{Kind: CommissionFixed, Value: 300}, price: 200, want: 200},
+		{name: "free pack", rule: &CommissionRule{Kind: CommissionFixed, Value: 300}, price: 0, want: 0},
+		{name: "no rule", rule: nil, price: 2500, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Compute(tt.price); got != tt.want {
+				t.Errorf("CommissionRule.Compute() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSelectCommissionRule tests that the most specific rule wins
+func TestSelectCommissionRule(t *testing.T) {
+	// GIVEN a pack of owner 7 and rules for the owner, its type and the pack
+	pack := createExpPack()
+	pack.ID = bson.NewObjectId()
+	pack.Ownerid = 7
+	rules := []CommissionRule{
+		{Ownerid: 7, Value: 5},
+		{Ownerid: 7, Packtype: pack.Packtype.ID, Value: 8},
+		{Ownerid: 7, PackID: bson.NewObjectId().Hex(), Value: 20},
+		{Ownerid: 8, PackID: pack.ID.Hex(), Value: 30},
+	}
+
+	// WHEN we select the rule of the pack
+	result := SelectCommissionRule(rules, pack)
+
+	// THEN the pack type rule is selected
+	if result == nil || result.Value != 8 {
+		t.Fatalf("Expected the pack type rule but got %+v", result)
+	}
+
+	// AND a rule of the pack is preferred
+	rules = append(rules, CommissionRule{Ownerid: 7, PackID: pack.ID.Hex(), Value: 12})
+	result = SelectCommissionRule(rules, pack)
+	if result == nil || result.Value != 12 {
+		t.Fatalf("Expected the pack rule but got %+v", result)
+	}
+
+	// AND a pack of another owner has no rule
+	pack.Ownerid = 9
+	if result = SelectCommissionRule(rules, pack); result != nil {
+		t.Fatalf("Expected no rule but got %+v", result)
+	}
+}
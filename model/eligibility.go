@@ -0,0 +1,239 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Plan types of the customer lines
+const (
+	PlanPrepaid  = "prepaid"
+	PlanPostpaid = "postpaid"
+	PlanHybrid   = "hybrid"
+)
+
+// Conditions of the eligibility rules
+const (
+	ConditionSegment      = "segment"      // customer segment
+	ConditionPlanType     = "plantype"     // plan type of the line
+	ConditionRegion       = "region"       // region of the customer
+	ConditionLineAge      = "lineage"      // days since the line was activated
+	ConditionPurchased    = "purchased"    // packs bought before
+	ConditionNotPurchased = "notpurchased" // packs never bought
+)
+
+// MaxEligibilityRules is the most rules a pack can have.
+const MaxEligibilityRules = 20
+
+// EligibilityRule contains conditions a customer must meet to buy a
+// pack, conditions that are not set are met by any customer.
+type EligibilityRule struct {
+	Name         string   `json:"name" bson:"name"`                                     // name of the rule, unique in the pack
+	Segments     []string `json:"segments,omitempty" bson:"segments,omitempty"`         // customer segments allowed. e.g. youth
+	PlanTypes    []string `json:"plantypes,omitempty" bson:"plantypes,omitempty"`       // plan types allowed. e.g. prepaid
+	Regions      []string `json:"regions,omitempty" bson:"regions,omitempty"`           // regions allowed
+	MinLineAge   int      `json:"minlineage,omitempty" bson:"minlineage,omitempty"`     // fewest days since the line was activated
+	MaxLineAge   int      `json:"maxlineage,omitempty" bson:"maxlineage,omitempty"`     // most days since the line was activated, 0 for any
+	Purchased    []string `json:"purchased,omitempty" bson:"purchased,omitempty"`       // pack codes, the customer bought at least one
+	NotPurchased []string `json:"notpurchased,omitempty" bson:"notpurchased,omitempty"` // pack codes the customer never bought
+}
+
+// CustomerContext contains the data of a customer checked by the
+// eligibility rules.
+type CustomerContext struct {
+	Msisdn    string   // line number, its completed orders are purchases too
+	Segment   string   // customer segment. e.g. youth, business
+	PlanType  string   // prepaid, postpaid or hybrid
+	Region    string   // region of the customer
+	LineAge   *int     // days since the line was activated, nil if unknown
+	Purchases []string // pack codes bought by the customer
+}
+
+// RuleFailure contains a condition of a rule that the customer does not meet.
+type RuleFailure struct {
+	Rule      string `json:"rule"`      // name of the rule
+	Condition string `json:"condition"` // failed condition. e.g. segment, lineage
+	Reason    string `json:"reason"`    // why the customer does not meet it
+}
+
+// Eligibility contains whether a customer can buy a pack and the
+// conditions that failed.
+type Eligibility struct {
+	PackID   string        `json:"packid"`
+	Packcode string        `json:"packcode"`
+	Eligible bool          `json:"eligible"`
+	Failures []RuleFailure `json:"failures"`
+}
+
+// NewEligibilityRules creates the rules of the given list argument, every
+// item has the fields of a rule.
+func NewEligibilityRules(params interface{}) []EligibilityRule {
+	list, _ := params.([]interface{})
+	rules := make([]EligibilityRule, 0, len(list))
+	for _, item := range list {
+		ruleargs, _ := item.(map[string]interface{})
+		rule := EligibilityRule{}
+		rule.Name, _ = ruleargs["name"].(string)
+		rule.Name = strings.TrimSpace(rule.Name)
+		rule.Segments = stringList(ruleargs["segments"])
+		rule.PlanTypes = stringList(ruleargs["plantypes"])
+		rule.Regions = stringList(ruleargs["regions"])
+		rule.MinLineAge, _ = ruleargs["minlineage"].(int)
+		rule.MaxLineAge, _ = ruleargs["maxlineage"].(int)
+		rule.Purchased = stringList(ruleargs["purchased"])
+		rule.NotPurchased = stringList(ruleargs["notpurchased"])
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// NewCustomerContext creates a CustomerContext with the given parameters.
+func NewCustomerContext(params map[string]interface{}) *CustomerContext {
+	customer := new(CustomerContext)
+	customer.Msisdn, _ = params["msisdn"].(string)
+	customer.Segment, _ = params["segment"].(string)
+	customer.PlanType, _ = params["plantype"].(string)
+	customer.Region, _ = params["region"].(string)
+	if lineage, ok := params["lineage"].(int); ok {
+		customer.LineAge = &lineage
+	}
+	customer.Purchases = stringList(params["purchases"])
+	return customer
+}
+
+// stringList returns the trimmed strings of a list argument, blank items
+// are removed.
+func stringList(params interface{}) []string {
+	list, _ := params.([]interface{})
+	if len(list) == 0 {
+		return nil
+	}
+	values := make([]string, 0, len(list))
+	for _, item := range list {
+		value, _ := item.(string)
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// InvalidRulesField returns the name of the first field of the rules with
+// a wrong value, empty if every value is right. A rule needs a unique
+// name and at least one condition.
+func InvalidRulesField(rules []EligibilityRule) string {
+	if len(rules) > MaxEligibilityRules {
+		return "rules"
+	}
+	names := map[string]bool{}
+	for _, rule := range rules {
+		key := strings.ToLower(rule.Name)
+		if key == "" || names[key] {
+			return "name"
+		}
+		names[key] = true
+		if field := rule.InvalidField(); field != "" {
+			return field
+		}
+	}
+	return ""
+}
+
+// InvalidField returns the name of the first field of the rule with a
+// wrong value, empty if every value is right.
+func (r *EligibilityRule) InvalidField() string {
+	for _, plantype := range r.PlanTypes {
+		switch strings.ToLower(plantype) {
+		case PlanPrepaid, PlanPostpaid, PlanHybrid:
+		default:
+			return "plantypes"
+		}
+	}
+	switch {
+	case r.MinLineAge < 0:
+		return "minlineage"
+	case r.MaxLineAge < 0 || (r.MaxLineAge > 0 && r.MaxLineAge < r.MinLineAge):
+		return "maxlineage"
+	case len(r.Segments) == 0 && len(r.PlanTypes) == 0 && len(r.Regions) == 0 && r.MinLineAge == 0 &&
+		r.MaxLineAge == 0 && len(r.Purchased) == 0 && len(r.NotPurchased) == 0:
+		return "rules"
+	}
+	return ""
+}
+
+// Check returns the conditions of the rule that the customer does not
+// meet, a condition on data the customer did not give fails.
+func (r *EligibilityRule) Check(customer *CustomerContext) []RuleFailure {
+	failures := []RuleFailure{}
+	fail := func(condition string, format string, args ...interface{}) {
+		failures = append(failures, RuleFailure{Rule: r.Name, Condition: condition, Reason: fmt.Sprintf(format, args...)})
+	}
+	if len(r.Segments) > 0 && !containsFold(r.Segments, customer.Segment) {
+		fail(ConditionSegment, "segment %q is not one of %s", customer.Segment, strings.Join(r.Segments, ", "))
+	}
+	if len(r.PlanTypes) > 0 && !containsFold(r.PlanTypes, customer.PlanType) {
+		fail(ConditionPlanType, "plan type %q is not one of %s", customer.PlanType, strings.Join(r.PlanTypes, ", "))
+	}
+	if len(r.Regions) > 0 && !containsFold(r.Regions, customer.Region) {
+		fail(ConditionRegion, "region %q is not one of %s", customer.Region, strings.Join(r.Regions, ", "))
+	}
+	if r.MinLineAge > 0 || r.MaxLineAge > 0 {
+		switch {
+		case customer.LineAge == nil:
+			fail(ConditionLineAge, "line age is unknown")
+		case *customer.LineAge < r.MinLineAge:
+			fail(ConditionLineAge, "line is %d days old, at least %d are needed", *customer.LineAge, r.MinLineAge)
+		case r.MaxLineAge > 0 && *customer.LineAge > r.MaxLineAge:
+			fail(ConditionLineAge, "line is %d days old, at most %d are allowed", *customer.LineAge, r.MaxLineAge)
+		}
+	}
+	if len(r.Purchased) > 0 && !customer.boughtAny(r.Purchased) {
+		fail(ConditionPurchased, "none of %s was bought", strings.Join(r.Purchased, ", "))
+	}
+	for _, packcode := range r.NotPurchased {
+		if customer.boughtAny([]string{packcode}) {
+			fail(ConditionNotPurchased, "%s was bought before", packcode)
+			break
+		}
+	}
+	return failures
+}
+
+// boughtAny returns true if the customer bought a pack with one of the
+// given pack codes.
+func (c *CustomerContext) boughtAny(packcodes []string) bool {
+	for _, purchase := range c.Purchases {
+		for _, packcode := range packcodes {
+			if purchase == packcode {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// containsFold returns true if the value is in the list ignoring case.
+func containsFold(list []string, value string) bool {
+	value = strings.TrimSpace(value)
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckEligibility returns whether the customer meets every rule of the
+// pack and the conditions that failed. A pack without rules is for every
+// customer.
+func (p *Pack) CheckEligibility(customer *CustomerContext) *Eligibility {
+	eligibility := &Eligibility{PackID: p.ID.Hex(), Packcode: p.Packcode, Failures: []RuleFailure{}}
+	if customer == nil {
+		customer = new(CustomerContext)
+	}
+	for i := range p.Rules {
+		eligibility.Failures = append(eligibility.Failures, p.Rules[i].Check(customer)...)
+	}
+	eligibility.Eligible = len(eligibility.Failures) == 0
+	return eligibility
+}
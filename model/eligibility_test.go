@@ -0,0 +1,83 @@
+package model
+
+import "testing"
+
+// TestEligibilityRuleCheck tests every condition of a rule
+func TestEligibilityRuleCheck(t *testing.T) {
+	rule := EligibilityRule{Name: "new prepaid youth", Segments: []string{"youth"}, PlanTypes: []string{PlanPrepaid},
+		Regions: []string{"Antioquia", "Valle"}, MaxLineAge: 30, NotPurchased: []string{"wh12"}}
+	young, old := 10, 90
+
+	tests := []struct {
+		name     string
+		customer CustomerContext
+		want     []string
+	}{
+		{name: "meets every condition", customer: CustomerContext{Segment: "Youth", PlanType: "prepaid", Region: "valle", LineAge: &young}},
+		{name: "old postpaid line", customer: CustomerContext{Segment: "youth", PlanType: "postpaid", Region: "Valle", LineAge: &old},
+			want: []string{ConditionPlanType, ConditionLineAge}},
+		{name: "nothing given", customer: CustomerContext{},
+			want: []string{ConditionSegment, ConditionPlanType, ConditionRegion, ConditionLineAge}},
+		{name: "bought the pack before", customer: CustomerContext{Segment: "youth", PlanType: "prepaid", Region: "Valle", LineAge: &young, Purchases: []string{"wh12"}},
+			want: []string{ConditionNotPurchased}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures := rule.Check(&tt.customer)
+			if len(failures) != len(tt.want) {
+				t.Fatalf("EligibilityRule.Check() = %+v, want conditions %v", failures, tt.want)
+			}
+			for i, failure := range failures {
+				if failure.Condition != tt.want[i] || failure.Rule != rule.Name || failure.Reason == "" {
+					t.Errorf("Expected failure of %s but got %+v", tt.want[i], failure)
+				}
+			}
+		})
+	}
+}
+
+// TestPackCheckEligibility tests a customer must meet every rule
+func TestPackCheckEligibility(t *testing.T) {
+	pack := createExpPack()
+	if !pack.CheckEligibility(nil).Eligible {
+		t.Errorf("Expected a pack without rules to be for every customer")
+	}
+	pack.Rules = []EligibilityRule{
+		{Name: "loyal", Purchased: []string{"wh10", "wh11"}},
+		{Name: "prepaid", PlanTypes: []string{PlanPrepaid}},
+	}
+
+	eligibility := pack.CheckEligibility(&CustomerContext{PlanType: PlanPrepaid, Purchases: []string{"wh11"}})
+	if !eligibility.Eligible || len(eligibility.Failures) != 0 {
+		t.Errorf("Expected a loyal prepaid customer to be eligible but got %+v", eligibility)
+	}
+	eligibility = pack.CheckEligibility(&CustomerContext{PlanType: PlanPrepaid})
+	if eligibility.Eligible || len(eligibility.Failures) != 1 || eligibility.Failures[0].Rule != "loyal" {
+		t.Errorf("Expected the loyal rule to fail but got %+v", eligibility)
+	}
+}
+
+// TestInvalidRulesField tests rules are validated before they are stored
+func TestInvalidRulesField(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []EligibilityRule
+		want  string
+	}{
+		{name: "no rules"},
+		{name: "valid rules", rules: []EligibilityRule{{Name: "prepaid", PlanTypes: []string{"Prepaid"}}, {Name: "new", MaxLineAge: 30}}},
+		{name: "no name", rules: []EligibilityRule{{Regions: []string{"Valle"}}}, want: "name"},
+		{name: "repeated name", rules: []EligibilityRule{{Name: "new", MaxLineAge: 30}, {Name: "New", MaxLineAge: 60}}, want: "name"},
+		{name: "unknown plan type", rules: []EligibilityRule{{Name: "plan", PlanTypes: []string{"corporate"}}}, want: "plantypes"},
+		{name: "line ages crossed", rules: []EligibilityRule{{Name: "age", MinLineAge: 60, MaxLineAge: 30}}, want: "maxlineage"},
+		{name: "negative line age", rules: []EligibilityRule{{Name: "age", MinLineAge: -1}}, want: "minlineage"},
+		{name: "no conditions", rules: []EligibilityRule{{Name: "empty"}}, want: "rules"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InvalidRulesField(tt.rules); got != tt.want {
+				t.Errorf("InvalidRulesField() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
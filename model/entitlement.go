@@ -0,0 +1,153 @@
+package model
+
+import (
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// EntitlementState defines entitlement states
+type EntitlementState int8
+
+// Entitlement states
+const (
+	EntitlementClosed EntitlementState = 0
+	EntitlementOpen   EntitlementState = 1
+)
+
+// Balance contains what a subscriber owns of a pack resource.
+type Balance struct {
+	ResourceID int16   `json:"resource_id" bson:"resource_id"` // id of the pack resource
+	Name       string  `json:"name" bson:"name"`               // resource name. e.g. datos, voz.
+	Units      string  `json:"units" bson:"units"`             // units of the resource. e.g. mb, min.
+	Granted    float32 `json:"granted" bson:"granted"`         // amount given by the pack
+	Used       float32 `json:"used" bson:"used"`               // amount consumed by the subscriber
+	Remaining  float32 `json:"remaining" bson:"remaining"`     // amount that can still be used
+	Isfree     bool    `json:"isfree" bson:"isfree"`
+}
+
+// Usage contains a consumption made over an entitlement, an amount given
+// back is a usage with a negative amount.
+type Usage struct {
+	ResourceID int16     `json:"resource_id" bson:"resource_id"`
+	Amount     float32   `json:"amount" bson:"amount"`
+	Created    time.Time `json:"created" bson:"created"`
+}
+
+// Entitlement contains a pack bought by a subscriber and the balances
+// that the subscriber still owns from it.
+type Entitlement struct {
+	ID       bson.ObjectId    `json:"id,omitempty" bson:"_id,omitempty"` // id of the entitlement in the db
+	Msisdn   string           `json:"msisdn" bson:"msisdn"`              // subscriber line number
+	PackID   string           `json:"packid" bson:"packid"`              // hex id of the purchased pack
+	Packcode string           `json:"packcode" bson:"packcode"`          // code of the purchased pack
+	MnoID    int8             `json:"mnoid" bson:"mnoid"`                // mno owner of the purchased pack
+	Ownerid  int              `json:"ownerid" bson:"ownerid"`            // company owner of the pack for resale
+	Balances []Balance        `json:"balances" bson:"balances"`          // balance of every pack resource
+	Usages   []Usage          `json:"usages,omitempty" bson:"usages"`    // consumptions made
+	Starts   time.Time        `json:"starts" bson:"starts"`              // when the entitlement was granted
+	Expires  time.Time        `json:"expires" bson:"expires"`            // when the entitlement ends
+	State    EntitlementState `json:"state" bson:"state"`                // state of the entitlement
+	Created  time.Time        `json:"created,omitempty" bson:"created"`
+	Updated  time.Time        `json:"updated,omitempty" bson:"updated"`
+}
+
+// ResourceBalance contains the remaining amount of a resource summed
+// over all the open entitlements of a subscriber.
+type ResourceBalance struct {
+	ResourceID int16     `json:"resource_id"`
+	Name       string    `json:"name"`
+	Units      string    `json:"units"`
+	Remaining  float32   `json:"remaining"`
+	Expires    time.Time `json:"expires"` // the farthest expiry of the summed balances
+}
+
+// NewEntitlement creates an open Entitlement for the given subscriber,
+// balances are copied from the pack resources and the expiry is taken
+// from the pack term starting at the given time.
+func NewEntitlement(msisdn string, pack *Pack, starts time.Time) *Entitlement {
+	if pack == nil {
+		return nil
+	}
+	newentitlement := new(Entitlement)
+	newentitlement.Msisdn = msisdn
+	newentitlement.PackID = pack.ID.Hex()
+	newentitlement.Packcode = pack.Packcode
+	if pack.Mno != nil {
+		newentitlement.MnoID = pack.Mno.ID
+	}
+	newentitlement.Ownerid = pack.Ownerid
+	newentitlement.Balances = NewBalances(pack.Resources)
+	newentitlement.Usages = []Usage{}
+	newentitlement.Starts = starts
+	newentitlement.Expires = pack.Term.ExpiresFrom(starts)
+	newentitlement.State = EntitlementOpen
+	newentitlement.Created = starts
+	newentitlement.Updated = starts
+	return newentitlement
+}
+
+// NewBalances creates the balances of an entitlement from the given
+// pack resources. if resources is nil it returns an empty array.
+func NewBalances(resources []Resource) []Balance {
+	balances := make([]Balance, len(resources))
+	for i, resource := range resources {
+		balances[i] = Balance{
+			ResourceID: resource.ID,
+			Name:       resource.Name,
+			Units:      resource.Units,
+			Granted:    resource.Amount,
+			Used:       0,
+			Remaining:  resource.Amount,
+			Isfree:     resource.Isfree,
+		}
+	}
+	return balances
+}
+
+// IsExpired returns true if the entitlement is not valid at the given time.
+func (e *Entitlement) IsExpired(now time.Time) bool {
+	return !now.Before(e.Expires)
+}
+
+// Remaining returns the amount of the given resource that still can be
+// used in this entitlement.
+func (e *Entitlement) Remaining(resourceid int16) float32 {
+	for _, balance := range e.Balances {
+		if balance.ResourceID == resourceid {
+			return balance.Remaining
+		}
+	}
+	return 0
+}
+
+// SumBalances sums the remaining amount per resource of the given
+// entitlements, ignoring the ones that are closed or expired at now.
+func SumBalances(entitlements []Entitlement, now time.Time) []ResourceBalance {
+	result := []ResourceBalance{}
+	position := make(map[int16]int)
+	for _, entitlement := range entitlements {
+		if entitlement.State != EntitlementOpen || entitlement.IsExpired(now) {
+			continue
+		}
+		for _, balance := range entitlement.Balances {
+			i, ok := position[balance.ResourceID]
+			if !ok {
+				position[balance.ResourceID] = len(result)
+				result = append(result, ResourceBalance{
+					ResourceID: balance.ResourceID,
+					Name:       balance.Name,
+					Units:      balance.Units,
+					Remaining:  balance.Remaining,
+					Expires:    entitlement.Expires,
+				})
+				continue
+			}
+			result[i].Remaining += balance.Remaining
+			if entitlement.Expires.After(result[i].Expires) {
+				result[i].Expires = entitlement.Expires
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,122 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+// TestNewEntitlement tests instances an Entitlement from a pack
+func TestNewEntitlement(t *testing.T) {
+	// GIVEN a pack with resources and a validity of 2 days
+	pack := createExpPack()
+	pack.Ownerid = 7
+	pack.Resources = []Resource{
+		{ID: 1, Name: "datos", Units: "mb", Amount: 500},
+		{ID: 2, Name: "voz", Units: "min", Amount: 60, Isfree: true},
+	}
+	starts := time.Date(2018, time.March, 10, 8, 0, 0, 0, time.UTC)
+
+	// WHEN we need to grant the pack to a subscriber
+	result := NewEntitlement("573001234567", pack, starts)
+
+	// THEN system returns an open entitlement with the pack balances
+	if result == nil {
+		t.Fatalf("Expected an Entitlement struct with data but got nil")
+	}
+	if result.State != EntitlementOpen {
+		t.Fatalf("Expected Entitlement#State %d but got %d", EntitlementOpen, result.State)
+	}
+	if result.Packcode != pack.Packcode {
+		t.Fatalf("Expected Entitlement#Packcode %s but got %s", pack.Packcode, result.Packcode)
+	}
+	if result.MnoID != pack.Mno.ID {
+		t.Fatalf("Expected Entitlement#MnoID %d but got %d", pack.Mno.ID, result.MnoID)
+	}
+	if result.Ownerid != pack.Ownerid {
+		t.Fatalf("Expected Entitlement#Ownerid %d but got %d", pack.Ownerid, result.Ownerid)
+	}
+	expexpires := starts.AddDate(0, 0, 2)
+	if !result.Expires.Equal(expexpires) {
+		t.Fatalf("Expected Entitlement#Expires %s but got %s", expexpires, result.Expires)
+	}
+	if len(result.Balances) != 2 {
+		t.Fatalf("Expected 2 balances but got %d", len(result.Balances))
+	}
+	if result.Balances[0].Remaining != 500 || result.Balances[0].Granted != 500 || result.Balances[0].Used != 0 {
+		t.Fatalf("Expected balance with 500 granted and remaining but got %+v", result.Balances[0])
+	}
+	if !result.Balances[1].Isfree {
+		t.Fatalf("Expected second balance to be free but it was not")
+	}
+}
+
+// TestNewEntitlementNilPack tests that a nil pack gives no entitlement
+func TestNewEntitlementNilPack(t *testing.T) {
+	result := NewEntitlement("573001234567", nil, time.Now())
+
+	if result != nil {
+		t.Fatalf("Expected nil but got %+v", result)
+	}
+}
+
+// TestTermExpiresFrom tests the expiry calculation for every kind of unit
+func TestTermExpiresFrom(t *testing.T) {
+	starts := time.Date(2018, time.January, 31, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		term *Term
+		want time.Time
+	}{
+		{name: "hours", term: &Term{Unit: "horas", Amount: 6}, want: starts.Add(6 * time.Hour)},
+		{name: "spanish days", term: &Term{Unit: "dia", Amount: 2}, want: starts.AddDate(0, 0, 2)},
+		{name: "weeks", term: &Term{Unit: "week", Amount: 2}, want: starts.AddDate(0, 0, 14)},
+		{name: "months", term: &Term{Unit: "Mes", Amount: 1}, want: starts.AddDate(0, 1, 0)},
+		{name: "unknown unit", term: &Term{Unit: "pepe", Amount: 3}, want: starts.AddDate(0, 0, 3)},
+		{name: "nil term", term: nil, want: starts},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.term.ExpiresFrom(starts); !got.Equal(tt.want) {
+				t.Errorf("Term.ExpiresFrom() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSumBalances tests that only open entitlements are summed per resource
+func TestSumBalances(t *testing.T) {
+	now := time.Date(2018, time.March, 10, 8, 0, 0, 0, time.UTC)
+	// GIVEN two open entitlements, one expired and one closed
+	entitlements := []Entitlement{
+		{State: EntitlementOpen, Expires: now.Add(time.Hour), Balances: []Balance{
+			{ResourceID: 1, Name: "datos", Remaining: 100},
+			{ResourceID: 2, Name: "voz", Remaining: 10},
+		}},
+		{State: EntitlementOpen, Expires: now.Add(48 * time.Hour), Balances: []Balance{
+			{ResourceID: 1, Name: "datos", Remaining: 250},
+		}},
+		{State: EntitlementOpen, Expires: now, Balances: []Balance{
+			{ResourceID: 1, Name: "datos", Remaining: 1000},
+		}},
+		{State: EntitlementClosed, Expires: now.Add(time.Hour), Balances: []Balance{
+			{ResourceID: 2, Name: "voz", Remaining: 1000},
+		}},
+	}
+
+	// WHEN we sum the balances
+	result := SumBalances(entitlements, now)
+
+	// THEN only the open and valid entitlements are summed
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 resource balances but got %d", len(result))
+	}
+	if result[0].ResourceID != 1 || result[0].Remaining != 350 {
+		t.Fatalf("Expected 350 remaining for resource 1 but got %+v", result[0])
+	}
+	if !result[0].Expires.Equal(now.Add(48 * time.Hour)) {
+		t.Fatalf("Expected the farthest expiry for resource 1 but got %s", result[0].Expires)
+	}
+	if result[1].ResourceID != 2 || result[1].Remaining != 10 {
+		t.Fatalf("Expected 10 remaining for resource 2 but got %+v", result[1])
+	}
+}
@@ -0,0 +1,125 @@
+package model
+
+import (
+	"reflect"
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Types of the pack events
+const (
+	EventPackCreated             = "PackCreated"
+	EventPackUpdated             = "PackUpdated"             // catalog data replaced with the same values
+	EventPackStateChanged        = "PackStateChanged"        // data: state
+	EventPackPriceChanged        = "PackPriceChanged"        // data: price
+	EventPackKeysChanged         = "PackKeysChanged"         // data: prodid or packcode
+	EventPackTextsChanged        = "PackTextsChanged"        // data: name, desc, imgurl, kwds or translations
+	EventPackAttributesChanged   = "PackAttributesChanged"   // data: type, mno, term or currency
+	EventPackResourcesChanged    = "PackResourcesChanged"    // data: resources and components of a bundle
+	EventPackOwnerChanged        = "PackOwnerChanged"        // data: ownerid
+	EventPackRulesChanged        = "PackRulesChanged"        // data: rules
+	EventPackAvailabilityChanged = "PackAvailabilityChanged" // data: channels and regions
+	EventStockMoved              = "StockMoved"              // data: amount added, negative if taken
+	EventPackDeleted             = "PackDeleted"
+)
+
+// EventTypes contains every type of pack event.
+var EventTypes = []string{EventPackCreated, EventPackUpdated, EventPackStateChanged, EventPackPriceChanged,
+	EventPackKeysChanged, EventPackTextsChanged, EventPackAttributesChanged, EventPackResourcesChanged,
+	EventPackOwnerChanged, EventPackRulesChanged, EventPackAvailabilityChanged, EventStockMoved, EventPackDeleted}
+
+// Event contains a change of a pack. It is written to the outbox of the
+// pack in the same write as the change, and the relay adds the pack as it
+// is when the event is delivered.
+type Event struct {
+	ID       bson.ObjectId          `json:"id" bson:"id"`                         // id of the event, the same in every delivery
+	Type     string                 `json:"type" bson:"type"`                     // type of the change. e.g. PackPriceChanged
+	PackID   string                 `json:"packid" bson:"packid"`                 // hex id of the changed pack
+	Data     map[string]interface{} `json:"data,omitempty" bson:"data,omitempty"` // new values of the changed fields
+	Occurred time.Time              `json:"occurred" bson:"occurred"`             // when the change was made
+	Pack     *Pack                  `json:"pack,omitempty" bson:"-"`              // pack when the event is delivered, the last one for deleted packs
+}
+
+// NewEvent creates an event of the given type for the pack.
+func NewEvent(eventtype string, packid string, data map[string]interface{}) Event {
+	return Event{ID: bson.NewObjectId(), Type: eventtype, PackID: packid, Data: data, Occurred: time.Now()}
+}
+
+// KnownEventType returns true if the given type is the type of a pack event.
+func KnownEventType(eventtype string) bool {
+	for _, known := range EventTypes {
+		if known == eventtype {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateEvents returns the events of replacing the catalog data of the
+// stored pack with the updated one, an event for every group of fields
+// that changed with their new values as the single field changes give.
+// The translations are only compared if the updated pack has some, as
+// they are kept otherwise. A replacement that changes nothing gives an
+// EventPackUpdated.
+func UpdateEvents(stored *Pack, updated *Pack) []Event {
+	id := stored.ID.Hex()
+	groups := []struct {
+		eventtype string
+		fields    map[string][2]interface{}
+	}{
+		{EventPackKeysChanged, map[string][2]interface{}{
+			"prodid":   {stored.ProdID, updated.ProdID},
+			"packcode": {stored.Packcode, updated.Packcode},
+		}},
+		{EventPackTextsChanged, map[string][2]interface{}{
+			"name":   {stored.Name, updated.Name},
+			"desc":   {stored.Desc, updated.Desc},
+			"imgurl": {stored.Img, updated.Img},
+			"kwds":   {stored.Kwds, updated.Kwds},
+		}},
+		{EventPackPriceChanged, map[string][2]interface{}{
+			"price": {stored.Price, updated.Price},
+		}},
+		{EventPackOwnerChanged, map[string][2]interface{}{
+			"ownerid": {stored.Ownerid, updated.Ownerid},
+		}},
+		{EventPackAttributesChanged, map[string][2]interface{}{
+			"type":     {stored.Packtype, updated.Packtype},
+			"mno":      {stored.Mno, updated.Mno},
+			"term":     {stored.Term, updated.Term},
+			"currency": {stored.Ccy, updated.Ccy},
+		}},
+		{EventPackResourcesChanged, map[string][2]interface{}{
+			"resources": {nonNilResources(stored.Resources), nonNilResources(updated.Resources)},
+		}},
+	}
+	if len(updated.Translations) > 0 {
+		groups[1].fields["translations"] = [2]interface{}{stored.Translations, updated.Translations}
+	}
+
+	events := []Event{}
+	for _, group := range groups {
+		data := map[string]interface{}{}
+		for field, values := range group.fields {
+			if !reflect.DeepEqual(values[0], values[1]) {
+				data[field] = values[1]
+			}
+		}
+		if len(data) > 0 {
+			events = append(events, NewEvent(group.eventtype, id, data))
+		}
+	}
+	if len(events) == 0 {
+		events = append(events, NewEvent(EventPackUpdated, id, nil))
+	}
+	return events
+}
+
+// nonNilResources returns the resources, an empty list if they are nil.
+func nonNilResources(resources []Resource) []Resource {
+	if resources == nil {
+		return []Resource{}
+	}
+	return resources
+}
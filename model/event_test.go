@@ -0,0 +1,40 @@
+package model
+
+import (
+	"testing"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// TestUpdateEvents tests replacing a pack gives an event with the new
+// values of every group of fields that changed
+func TestUpdateEvents(t *testing.T) {
+	// GIVEN a stored pack
+	stored := createExpPack()
+	stored.ID = bson.NewObjectId()
+
+	// WHEN it is replaced with a new price and name
+	updated := *stored
+	updated.Price = 3000
+	updated.Name = "Whatsapp semana"
+	events := UpdateEvents(stored, &updated)
+
+	// THEN there is an event for the price and another for the texts
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events but got %+v", events)
+	}
+	texts, price := events[0], events[1]
+	if texts.Type != EventPackTextsChanged || len(texts.Data) != 1 || texts.Data["name"] != "Whatsapp semana" {
+		t.Fatalf("Expected the new name in a %s event but got %+v", EventPackTextsChanged, texts)
+	}
+	if price.Type != EventPackPriceChanged || price.Data["price"] != 3000 || price.PackID != stored.ID.Hex() {
+		t.Fatalf("Expected the new price in a %s event but got %+v", EventPackPriceChanged, price)
+	}
+
+	// AND a replacement with the same data gives a PackUpdated
+	same := *stored
+	events = UpdateEvents(stored, &same)
+	if len(events) != 1 || events[0].Type != EventPackUpdated {
+		t.Fatalf("Expected a %s event but got %+v", EventPackUpdated, events)
+	}
+}
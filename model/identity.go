@@ -0,0 +1,43 @@
+package model
+
+// Roles of the callers
+const (
+	RolePlatformAdmin  = "platform-admin"  // acts for every tenant and manages api keys
+	RoleAdmin          = "admin"           // manages the packs, owners and sales of its tenant
+	RoleCatalogEditor  = "catalog-editor"  // creates and changes packs
+	RolePricingManager = "pricing-manager" // changes the price of packs
+	RoleSeller         = "seller"          // sells and grants packs to subscribers
+	RoleViewer         = "viewer"          // only queries
+)
+
+// Identity contains who is calling the service, it is set once the
+// caller is authenticated.
+type Identity struct {
+	Subject string   `json:"sub"`     // user or api key that makes the call
+	Method  string   `json:"method"`  // how the caller was authenticated. e.g. jwt, apikey
+	Roles   []string `json:"roles"`   // roles granted to the caller
+	Ownerid int      `json:"ownerid"` // owner the caller acts for, 0 if any
+	MnoID   int8     `json:"mnoid"`   // mno the caller acts for, 0 if any
+	Channel string   `json:"channel"` // channel the caller sells in, empty if any
+}
+
+// HasRole returns true if the caller was granted the given role.
+func (i *Identity) HasRole(role string) bool {
+	if i == nil {
+		return false
+	}
+	for _, granted := range i.Roles {
+		if granted == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Tenant returns the tenant the caller acts for, nil for no caller.
+func (i *Identity) Tenant() *Tenant {
+	if i == nil {
+		return nil
+	}
+	return &Tenant{Ownerid: i.Ownerid, MnoID: i.MnoID, Channel: i.Channel, Admin: i.HasRole(RolePlatformAdmin)}
+}
@@ -0,0 +1,135 @@
+package model
+
+import (
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// OrderState defines order states
+type OrderState int8
+
+// Order states
+const (
+	OrderPending   OrderState = 0 // order placed, the pack is being provisioned
+	OrderCompleted OrderState = 1 // pack activated and granted to the subscriber
+	OrderFailed    OrderState = 2 // pack could not be sold, changes were compensated
+	OrderRefunded  OrderState = 3 // price given back to the subscriber
+)
+
+// OrderKind defines why an order was placed
+type OrderKind int8
+
+// Order kinds
+const (
+	OrderPurchase     OrderKind = 0 // single sale of a pack
+	OrderSubscription OrderKind = 1 // first period of a subscription, it is created when the order completes
+	OrderRenewal      OrderKind = 2 // new period of a subscription, it is not retried by the order
+)
+
+// ProvisionStatus defines the status of a pack in the mno network
+type ProvisionStatus int8
+
+// Provision statuses
+const (
+	ProvisionUnknown  ProvisionStatus = 0
+	ProvisionPending  ProvisionStatus = 1
+	ProvisionActive   ProvisionStatus = 2
+	ProvisionInactive ProvisionStatus = 3
+	ProvisionFailed   ProvisionStatus = 4
+)
+
+// Provision contains the answer of an mno network to a pack activation.
+type Provision struct {
+	Reference string          `json:"reference"` // id of the activation in the mno network
+	Status    ProvisionStatus `json:"status"`
+}
+
+// Order contains the sale of a pack to a subscriber.
+type Order struct {
+	ID            bson.ObjectId `json:"id,omitempty" bson:"_id,omitempty"`                  // id of the order in the db
+	Msisdn        string        `json:"msisdn" bson:"msisdn"`                               // subscriber line number
+	PackID        string        `json:"packid" bson:"packid"`                               // hex id of the sold pack
+	Packcode      string        `json:"packcode" bson:"packcode"`                           // code of the sold pack
+	ProdID        string        `json:"prodid" bson:"prodid"`                               // mno product id used to provision the pack
+	Components    []string      `json:"components,omitempty" bson:"components"`             // packs of a sold bundle, their stock is reserved
+	MnoID         int8          `json:"mnoid" bson:"mnoid"`                                 // mno owner of the sold pack
+	Ownerid       int           `json:"ownerid" bson:"ownerid"`                             // company owner of the pack for resale
+	Price         int           `json:"price" bson:"price"`                                 // price charged
+	Commission    int           `json:"commission" bson:"commission"`                       // commission earned by the owner
+	Ccy           *Currency     `json:"currency" bson:"currency"`                           // currency of the price
+	Reference     string        `json:"reference,omitempty" bson:"reference"`               // activation id in the mno network
+	EntitlementID string        `json:"entitlementid,omitempty" bson:"entitlementid"`       // entitlement granted by the order
+	Attempts      int           `json:"attempts" bson:"attempts"`                           // provisioning attempts made
+	LastError     string        `json:"lasterror,omitempty" bson:"lasterror"`               // reason of the failure
+	NextAttempt   time.Time     `json:"nextattempt,omitempty" bson:"nextattempt,omitempty"` // when a pending order is provisioned again
+	State         OrderState    `json:"state" bson:"state"`                                 // state of the order
+	Kind          OrderKind     `json:"kind" bson:"kind"`                                   // why the order was placed
+	Refunded      time.Time     `json:"refunded,omitempty" bson:"refunded,omitempty"`       // when the price was given back
+	RefundReason  string        `json:"refundreason,omitempty" bson:"refundreason"`         // why the price was given back
+	Created       time.Time     `json:"created,omitempty" bson:"created"`
+	Updated       time.Time     `json:"updated,omitempty" bson:"updated"`
+}
+
+// NewOrder creates a pending order to sell the given pack to a subscriber
+// at the given price.
+func NewOrder(msisdn string, pack *Pack, price int, kind OrderKind) *Order {
+	if pack == nil {
+		return nil
+	}
+	neworder := new(Order)
+	neworder.Msisdn = msisdn
+	neworder.PackID = pack.ID.Hex()
+	neworder.Packcode = pack.Packcode
+	neworder.ProdID = pack.ProdID
+	neworder.Components = pack.Components
+	if pack.Mno != nil {
+		neworder.MnoID = pack.Mno.ID
+	}
+	neworder.Ownerid = pack.Ownerid
+	neworder.Price = price
+	neworder.Ccy = pack.Ccy
+	neworder.State = OrderPending
+	neworder.Kind = kind
+	neworder.Created = time.Now()
+	neworder.Updated = neworder.Created
+	return neworder
+}
+
+// Fail marks the order as failed with the given reason.
+func (o *Order) Fail(reason string) {
+	o.State = OrderFailed
+	o.LastError = reason
+	o.NextAttempt = time.Time{}
+	o.Updated = time.Now()
+}
+
+// Retry keeps the order pending to provision it again at next, the
+// reason of the last failure is kept.
+func (o *Order) Retry(reason string, next time.Time) {
+	o.LastError = reason
+	o.NextAttempt = next
+	o.Updated = time.Now()
+}
+
+// Complete marks the order as completed with the granted entitlement.
+func (o *Order) Complete(entitlementid string) {
+	o.State = OrderCompleted
+	o.EntitlementID = entitlementid
+	o.LastError = ""
+	o.NextAttempt = time.Time{}
+	o.Updated = time.Now()
+}
+
+// Refund marks a completed order as refunded at the given time, it returns
+// false if the order cannot be refunded.
+func (o *Order) Refund(reason string, now time.Time) bool {
+	if o.State != OrderCompleted {
+		return false
+	}
+	o.State = OrderRefunded
+	o.RefundReason = reason
+	o.Refunded = now
+	o.Updated = now
+	return true
+}
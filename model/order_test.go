@@ -0,0 +1,58 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+// TestNewOrder tests instances a pending Order from a pack
+func TestNewOrder(t *testing.T) {
+	// GIVEN a pack of a reseller
+	pack := createExpPack()
+	pack.Ownerid = 7
+
+	// WHEN we need to sell the pack to a subscriber
+	result := NewOrder("573001234567", pack, 2500, OrderSubscription)
+
+	// THEN system returns a pending order with the pack data
+	if result == nil {
+		t.Fatalf("Expected an Order struct with data but got nil")
+	}
+	if result.State != OrderPending {
+		t.Fatalf("Expected Order#State %d but got %d", OrderPending, result.State)
+	}
+	if result.Price != 2500 || result.Ownerid != 7 || result.ProdID != pack.ProdID || result.Kind != OrderSubscription {
+		t.Fatalf("Expected order at 2500 of owner 7 with the pack product but got %+v", result)
+	}
+
+	// AND it stays pending while it is retried
+	next := time.Now().Add(time.Minute)
+	result.Retry("mno unavailable", next)
+	if result.State != OrderPending || !result.NextAttempt.Equal(next) || result.LastError != "mno unavailable" {
+		t.Fatalf("Expected a pending order to retry but got %+v", result)
+	}
+
+	// AND it can be completed with the granted entitlement
+	result.Complete("5a8f1c")
+	if result.State != OrderCompleted || result.EntitlementID != "5a8f1c" || result.LastError != "" || !result.NextAttempt.IsZero() {
+		t.Fatalf("Expected a completed order but got %+v", result)
+	}
+}
+
+// TestOrderRefund tests that only completed orders are refunded
+func TestOrderRefund(t *testing.T) {
+	now := time.Date(2018, time.March, 1, 0, 0, 0, 0, time.UTC)
+	order := &Order{State: OrderFailed}
+
+	if order.Refund("customer complaint", now) {
+		t.Fatalf("Expected a failed order not to be refunded")
+	}
+
+	order.State = OrderCompleted
+	if !order.Refund("customer complaint", now) {
+		t.Fatalf("Expected a completed order to be refunded")
+	}
+	if order.State != OrderRefunded || !order.Refunded.Equal(now) || order.RefundReason != "customer complaint" {
+		t.Fatalf("Expected a refunded order but got %+v", order)
+	}
+}
@@ -0,0 +1,72 @@
+package model
+
+import "time"
+
+// OwnerState defines owner states
+type OwnerState int8
+
+// Owner states
+const (
+	OwnerInactive  OwnerState = 0 // owner cannot get new packs
+	OwnerActive    OwnerState = 1
+	OwnerSuspended OwnerState = 2 // owner blocked by the platform
+)
+
+// Contact contains the contact details of an owner.
+type Contact struct {
+	Name  string `json:"name" bson:"name"`   // name of the contact person
+	Email string `json:"email" bson:"email"` // contact email
+	Phone string `json:"phone" bson:"phone"` // contact phone number
+}
+
+// Owner contains the data of a reseller company that owns packs for resale.
+type Owner struct {
+	ID      int        `json:"id" bson:"_id"`                        // ownerid of the packs of the company
+	Name    string     `json:"name" bson:"name"`                     // company name
+	Contact *Contact   `json:"contact,omitempty" bson:"contact"`     // contact details
+	Mnos    []int8     `json:"mnos,omitempty" bson:"mnos,omitempty"` // ids of the mnos the owner can sell packs of
+	State   OwnerState `json:"state" bson:"state"`                   // state of the owner
+	Created time.Time  `json:"created,omitempty" bson:"created"`
+	Updated time.Time  `json:"updated,omitempty" bson:"updated"`
+}
+
+// NewOwner creates an instance of *Owner from the given map.
+func NewOwner(params map[string]interface{}) *Owner {
+	newowner := new(Owner)
+	newowner.ID, _ = params["id"].(int)
+	newowner.Name, _ = params["name"].(string)
+	if contact, ok := params["contact"].(map[string]interface{}); ok {
+		newowner.Contact = new(Contact)
+		newowner.Contact.Name, _ = contact["name"].(string)
+		newowner.Contact.Email, _ = contact["email"].(string)
+		newowner.Contact.Phone, _ = contact["phone"].(string)
+	}
+	newowner.Mnos = NewMnoIDs(params["mnos"])
+	return newowner
+}
+
+// NewMnoIDs converts a list of mno ids given as interface to int8 ids.
+func NewMnoIDs(param interface{}) []int8 {
+	values, _ := param.([]interface{})
+	result := make([]int8, 0, len(values))
+	for _, value := range values {
+		if id, ok := value.(int); ok {
+			result = append(result, int8(id))
+		}
+	}
+	return result
+}
+
+// CanOwn returns true if the owner is active and it is allowed to sell
+// packs of the given mno.
+func (o *Owner) CanOwn(mnoid int8) bool {
+	if o == nil || o.State != OwnerActive {
+		return false
+	}
+	for _, allowed := range o.Mnos {
+		if allowed == mnoid {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package model
+
+import "testing"
+
+// TestNewOwner tests instances an Owner from the graphql arguments
+func TestNewOwner(t *testing.T) {
+	params := map[string]interface{}{
+		"id":      7,
+		"name":    "Reseller SAS",
+		"contact": map[string]interface{}{"name": "Ana", "email": "ana@reseller.co"},
+		"mnos":    []interface{}{2, 5},
+	}
+
+	result := NewOwner(params)
+
+	if result.ID != 7 || result.Name != "Reseller SAS" {
+		t.Fatalf("Expected owner 7 Reseller SAS but got %+v", result)
+	}
+	if result.Contact == nil || result.Contact.Email != "ana@reseller.co" {
+		t.Fatalf("Expected contact email but got %+v", result.Contact)
+	}
+	if len(result.Mnos) != 2 || result.Mnos[1] != 5 {
+		t.Fatalf("Expected mnos [2 5] but got %v", result.Mnos)
+	}
+}
+
+// TestOwnerCanOwn tests the owners allowed to own a pack of an mno
+func TestOwnerCanOwn(t *testing.T) {
+	tests := []struct {
+		name  string
+		owner *Owner
+		mnoid int8
+		want  bool
+	}{
+		{name: "allowed mno", owner: &Owner{State: OwnerActive, Mnos: []int8{2, 5}}, mnoid: 5, want: true},
+		{name: "other mno", owner: &Owner{State: OwnerActive, Mnos: []int8{2, 5}}, mnoid: 3, want: false},
+		{name: "no mnos", owner: &Owner{State: OwnerActive}, mnoid: 2, want: false},
+		{name: "inactive", owner: &Owner{State: OwnerInactive, Mnos: []int8{2}}, mnoid: 2, want: false},
+		{name: "suspended", owner: &Owner{State: OwnerSuspended, Mnos: []int8{2}}, mnoid: 2, want: false},
+		{name: "nil owner", owner: nil, mnoid: 2, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.owner.CanOwn(tt.mnoid); got != tt.want {
+				t.Errorf("Owner.CanOwn() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package model
+
+import "strings"
+
+// MinBundleComponents is the number of different packs a bundle needs.
+const MinBundleComponents = 2
+
+// IsBundle returns true if the pack is a combo of other packs.
+func (p *Pack) IsBundle() bool {
+	return p != nil && len(p.Components) > 0
+}
+
+// NewComponents returns the pack ids of the given list argument, blank
+// ids are removed and the rest are trimmed.
+func NewComponents(params interface{}) []string {
+	list, _ := params.([]interface{})
+	components := []string{}
+	for _, item := range list {
+		id, _ := item.(string)
+		if id = strings.TrimSpace(id); id != "" {
+			components = append(components, id)
+		}
+	}
+	return components
+}
+
+// InvalidComponents returns true if the bundle has less than
+// MinBundleComponents packs, repeats one or contains itself.
+func (p *Pack) InvalidComponents() bool {
+	if len(p.Components) < MinBundleComponents {
+		return true
+	}
+	seen := map[string]bool{}
+	for _, id := range p.Components {
+		if seen[id] || (p.ID != "" && id == p.ID.Hex()) {
+			return true
+		}
+		seen[id] = true
+	}
+	return false
+}
+
+// HasComponent returns true if the pack with the given id is a component
+// of the bundle.
+func (p *Pack) HasComponent(id string) bool {
+	for _, component := range p.Components {
+		if component == id {
+			return true
+		}
+	}
+	return false
+}
+
+// AggregateResources returns the resources of the given packs, the
+// amounts of a resource with the same id and units are added. A resource
+// is free only if it is free in every pack.
+func AggregateResources(packs []Pack) []Resource {
+	resources := []Resource{}
+	index := map[Resource]int{}
+	for _, pack := range packs {
+		for _, resource := range pack.Resources {
+			key := Resource{ID: resource.ID, Units: resource.Units}
+			at, ok := index[key]
+			if !ok {
+				index[key] = len(resources)
+				resources = append(resources, resource)
+				continue
+			}
+			resources[at].Amount += resource.Amount
+			resources[at].Isfree = resources[at].Isfree && resource.Isfree
+		}
+	}
+	return resources
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// TestAggregateResources tests the amounts of the same resource are added
+func TestAggregateResources(t *testing.T) {
+	packs := []Pack{
+		{Resources: []Resource{{ID: 1, Name: "data", Units: "MB", Amount: 500, Isfree: false}}},
+		{Resources: []Resource{{ID: 2, Name: "whatsapp", Units: "MB", Amount: 1000, Isfree: true},
+			{ID: 1, Name: "data", Units: "MB", Amount: 250, Isfree: true}}},
+		{Resources: []Resource{{ID: 1, Name: "data", Units: "GB", Amount: 1}}},
+	}
+
+	got := AggregateResources(packs)
+
+	want := []Resource{
+		{ID: 1, Name: "data", Units: "MB", Amount: 750, Isfree: false},
+		{ID: 2, Name: "whatsapp", Units: "MB", Amount: 1000, Isfree: true},
+		{ID: 1, Name: "data", Units: "GB", Amount: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AggregateResources() = %+v, want %+v", got, want)
+	}
+	if packs[0].Resources[0].Amount != 500 {
+		t.Errorf("Expected the resources of the packs not to change but got %+v", packs[0].Resources)
+	}
+}
+
+// TestInvalidComponents tests a bundle needs two different packs
+func TestInvalidComponents(t *testing.T) {
+	id := bson.NewObjectId()
+	a, b := bson.NewObjectId().Hex(), bson.NewObjectId().Hex()
+	tests := []struct {
+		name       string
+		components []string
+		want       bool
+	}{
+		{name: "two packs", components: []string{a, b}},
+		{name: "one pack", components: []string{a}, want: true},
+		{name: "repeated pack", components: []string{a, b, a}, want: true},
+		{name: "itself", components: []string{a, id.Hex()}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bundle := &Pack{ID: id, Components: tt.components}
+			if got := bundle.InvalidComponents(); got != tt.want {
+				t.Errorf("Pack.InvalidComponents() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestNewComponents tests blank ids are removed
+func TestNewComponents(t *testing.T) {
+	got := NewComponents([]interface{}{" 5a1 ", "", "5a2"})
+	if !reflect.DeepEqual(got, []string{"5a1", "5a2"}) {
+		t.Errorf("NewComponents() = %v", got)
+	}
+	if got := NewComponents(nil); len(got) != 0 {
+		t.Errorf("Expected no components without a list but got %v", got)
+	}
+}
@@ -0,0 +1,138 @@
+package model
+
+import "time"
+
+// PackOverrides contains the data of a cloned pack that is not copied from
+// the source pack, fields that are not set are copied.
+type PackOverrides struct {
+	ProdID   string    // product id of the clone, it is mandatory
+	Packcode string    // pack code of the clone, it is mandatory
+	Name     string    // pack name
+	Desc     string    // pack description
+	Img      string    // icon image url
+	Kwds     string    // keywords for the pack searching
+	Price    *int      // price of the pack
+	Ownerid  int       // company owner of the pack for resale
+	Packtype *Type     // pack type
+	Mno      *Mno      // mobile network operator of the pack
+	Term     *Term     // validity of the pack
+	Ccy      *Currency // currency of the price
+}
+
+// NewPackOverrides creates a PackOverrides with the given parameters,
+// they have the names of the create arguments.
+func NewPackOverrides(params map[string]interface{}) *PackOverrides {
+	overrides := new(PackOverrides)
+	overrides.ProdID, _ = params["prodid"].(string)
+	overrides.Packcode, _ = params["packcode"].(string)
+	overrides.Name, _ = params["name"].(string)
+	overrides.Desc, _ = params["desc"].(string)
+	overrides.Img, _ = params["imgurl"].(string)
+	overrides.Kwds, _ = params["kwds"].(string)
+	overrides.Ownerid, _ = params["ownerid"].(int)
+	if price, ok := params["price"].(int); ok {
+		overrides.Price = &price
+	}
+	if packtype, ok := params["type"].(map[string]interface{}); ok {
+		overrides.Packtype = NewType(packtype)
+	}
+	if mno, ok := params["mno"].(map[string]interface{}); ok {
+		overrides.Mno = NewMno(mno)
+	}
+	if term, ok := params["term"].(map[string]interface{}); ok {
+		overrides.Term = NewTerm(term)
+	}
+	if ccy, ok := params["currency"].(map[string]interface{}); ok {
+		overrides.Ccy = NewCurrency(ccy)
+	}
+	return overrides
+}
+
+// Copy returns a new pack with the catalog data of the pack, it has no
+// id, stock nor dates.
+func (p *Pack) Copy() *Pack {
+	copied := *p
+	copied.ID = ""
+	copied.Stock = 0
+	copied.State = Inactive
+	copied.Created = time.Time{}
+	copied.Updated = time.Time{}
+	copied.Version = 0
+	copied.Locale = ""
+	if p.Packtype != nil {
+		packtype := *p.Packtype
+		copied.Packtype = &packtype
+	}
+	if p.Mno != nil {
+		mno := *p.Mno
+		copied.Mno = &mno
+	}
+	if p.Term != nil {
+		term := *p.Term
+		copied.Term = &term
+	}
+	if p.Ccy != nil {
+		ccy := *p.Ccy
+		copied.Ccy = &ccy
+	}
+	if p.Resources != nil {
+		copied.Resources = append([]Resource{}, p.Resources...)
+	}
+	if p.Rules != nil {
+		copied.Rules = append([]EligibilityRule{}, p.Rules...)
+	}
+	if p.Components != nil {
+		copied.Components = append([]string{}, p.Components...)
+	}
+	if p.Channels != nil {
+		copied.Channels = append([]string{}, p.Channels...)
+	}
+	if p.Regions != nil {
+		copied.Regions = append([]string{}, p.Regions...)
+	}
+	if p.Translations != nil {
+		copied.Translations = append([]Translation{}, p.Translations...)
+	}
+	return &copied
+}
+
+// Apply sets the overrides to the given pack. The translations are
+// removed if the name, description or keywords are overridden as they
+// would not match.
+func (o *PackOverrides) Apply(pack *Pack) {
+	pack.ProdID = o.ProdID
+	pack.Packcode = o.Packcode
+	if o.Name != "" || o.Desc != "" || o.Kwds != "" {
+		pack.Translations = nil
+	}
+	if o.Name != "" {
+		pack.Name = o.Name
+	}
+	if o.Desc != "" {
+		pack.Desc = o.Desc
+	}
+	if o.Img != "" {
+		pack.Img = o.Img
+	}
+	if o.Kwds != "" {
+		pack.Kwds = o.Kwds
+	}
+	if o.Price != nil {
+		pack.Price = *o.Price
+	}
+	if o.Ownerid != 0 {
+		pack.Ownerid = o.Ownerid
+	}
+	if o.Packtype != nil {
+		pack.Packtype = o.Packtype
+	}
+	if o.Mno != nil {
+		pack.Mno = o.Mno
+	}
+	if o.Term != nil {
+		pack.Term = o.Term
+	}
+	if o.Ccy != nil {
+		pack.Ccy = o.Ccy
+	}
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"testing"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// TestPackCopy tests a copy does not share data with its pack
+func TestPackCopy(t *testing.T) {
+	pack := createExpPack()
+	pack.ID = bson.NewObjectId()
+	pack.Stock = 30
+	pack.Resources = []Resource{{ID: 1, Name: "data"}}
+
+	copied := pack.Copy()
+	copied.Packtype.Name = "Data"
+	copied.Resources[0].Name = "voice"
+
+	if copied.ID != "" || copied.Stock != 0 || copied.Packcode != pack.Packcode {
+		t.Errorf("Expected a copy without id nor stock but got %+v", copied)
+	}
+	if pack.Packtype.Name != "App" || pack.Resources[0].Name != "data" {
+		t.Errorf("Expected the pack not to change with its copy but got %+v", pack)
+	}
+}
+
+// TestPackOverridesApply tests the overrides of a clone
+func TestPackOverridesApply(t *testing.T) {
+	overrides := NewPackOverrides(map[string]interface{}{
+		"prodid": "4", "packcode": "wh13", "price": 0, "mno": map[string]interface{}{"id": 5, "name": "Tigo"},
+	})
+	pack := createExpPack()
+	pack.Translations = []Translation{{Locale: "en", Name: "Weekend Whatsapp"}}
+
+	overrides.Apply(pack)
+
+	if pack.ProdID != "4" || pack.Packcode != "wh13" || pack.Price != 0 || pack.Mno.ID != 5 {
+		t.Errorf("Expected the overrides to be applied but got %+v", pack)
+	}
+	if pack.Name != "Whatsapp weekend" || len(pack.Translations) != 1 {
+		t.Errorf("Expected the texts and translations to be kept but got %+v", pack)
+	}
+
+	(&PackOverrides{ProdID: "5", Packcode: "wh14", Name: "Whatsapp 14"}).Apply(pack)
+	if pack.Name != "Whatsapp 14" || pack.Translations != nil {
+		t.Errorf("Expected the translations to be removed with a new name but got %+v", pack.Translations)
+	}
+}
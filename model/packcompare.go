@@ -0,0 +1,208 @@
+package model
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// Limits of the packs compared side by side
+const (
+	MinComparedPacks = 2
+	MaxComparedPacks = 10
+)
+
+// Weights of the similarity of two packs, they add up to 1.
+const (
+	resourceWeight = 0.5
+	priceWeight    = 0.3
+	termWeight     = 0.2
+)
+
+// termReference is the start used to measure validities in days, in april
+// a month is 30 days and a year 365.
+var termReference = time.Date(2001, time.April, 1, 0, 0, 0, 0, time.UTC)
+
+// baseUnits converts the units of a resource to the unit its price is
+// compared in. e.g. the price of data is compared per GB.
+var baseUnits = map[string]struct {
+	units  string
+	factor float64
+}{
+	"kb":      {"GB", 1.0 / (1024 * 1024)},
+	"mb":      {"GB", 1.0 / 1024},
+	"gb":      {"GB", 1},
+	"tb":      {"GB", 1024},
+	"sec":     {"min", 1.0 / 60},
+	"seconds": {"min", 1.0 / 60},
+	"min":     {"min", 1},
+	"minutes": {"min", 1},
+	"hour":    {"min", 60},
+	"hours":   {"min", 60},
+}
+
+// PackComparison contains packs side by side with their resources aligned,
+// a resource has a value for every pack in the order of the packs.
+type PackComparison struct {
+	Packs     []ComparedPack       `json:"packs"`
+	Resources []ResourceComparison `json:"resources"`
+}
+
+// ComparedPack contains a compared pack and its price per day of validity.
+type ComparedPack struct {
+	Pack         Pack    `json:"pack"`
+	ValidityDays float64 `json:"validityDays"` // days the pack is valid
+	PricePerDay  float64 `json:"pricePerDay"`  // price divided by the validity days
+}
+
+// ResourceComparison contains a resource of the compared packs.
+type ResourceComparison struct {
+	ID     int16           `json:"id"`     // id of the resource
+	Name   string          `json:"name"`   // name of the resource
+	Units  string          `json:"units"`  // units of the amounts. e.g. GB, min
+	Values []ResourceValue `json:"values"` // one value for every compared pack
+}
+
+// ResourceValue contains the amount of a resource in a compared pack. The
+// price per unit is the price of the pack divided by the amount, nil if
+// the pack does not have the resource.
+type ResourceValue struct {
+	PackID       string   `json:"packid"`
+	Amount       float64  `json:"amount"`
+	Isfree       bool     `json:"isfree"`
+	PricePerUnit *float64 `json:"pricePerUnit"`
+}
+
+// SimilarPack contains a pack and how similar it is to another one, from
+// 0 to 1.
+type SimilarPack struct {
+	Pack  Pack    `json:"pack"`
+	Score float64 `json:"score"`
+}
+
+// resourceKey identifies the resources that are compared with each other.
+type resourceKey struct {
+	id    int16
+	units string
+}
+
+// baseAmount returns the key of the resource and its amount in the units
+// its price is compared in, units without conversion are kept.
+func baseAmount(resource Resource) (resourceKey, float64) {
+	units := strings.TrimSpace(resource.Units)
+	if base, ok := baseUnits[strings.ToLower(units)]; ok {
+		return resourceKey{resource.ID, base.units}, float64(resource.Amount) * base.factor
+	}
+	return resourceKey{resource.ID, units}, float64(resource.Amount)
+}
+
+// resourceVector returns the amounts of the resources of the pack by key,
+// amounts of the same key are added.
+func (p *Pack) resourceVector() map[resourceKey]float64 {
+	vector := map[resourceKey]float64{}
+	for _, resource := range p.Resources {
+		key, amount := baseAmount(resource)
+		vector[key] += amount
+	}
+	return vector
+}
+
+// ValidityDays returns the days the pack is valid, 0 if it has no term.
+func (p *Pack) ValidityDays() float64 {
+	if p.Term == nil {
+		return 0
+	}
+	return p.Term.ExpiresFrom(termReference).Sub(termReference).Hours() / 24
+}
+
+// NewPackComparison compares the given packs side by side. Resources are
+// aligned by id and units, and the whole price of a pack is divided by the
+// amount of each resource to get its price per unit.
+func NewPackComparison(packs []Pack) *PackComparison {
+	comparison := &PackComparison{Packs: []ComparedPack{}, Resources: []ResourceComparison{}}
+	index := map[resourceKey]int{}
+	for _, pack := range packs {
+		compared := ComparedPack{Pack: pack, ValidityDays: pack.ValidityDays()}
+		if compared.ValidityDays > 0 {
+			compared.PricePerDay = roundPrice(float64(pack.Price) / compared.ValidityDays)
+		}
+		comparison.Packs = append(comparison.Packs, compared)
+		for _, resource := range pack.Resources {
+			key, _ := baseAmount(resource)
+			if _, ok := index[key]; !ok {
+				index[key] = len(comparison.Resources)
+				comparison.Resources = append(comparison.Resources,
+					ResourceComparison{ID: resource.ID, Name: resource.Name, Units: key.units})
+			}
+		}
+	}
+	for _, pack := range packs {
+		vector := pack.resourceVector()
+		for i := range comparison.Resources {
+			row := &comparison.Resources[i]
+			value := ResourceValue{PackID: pack.ID.Hex()}
+			amount, ok := vector[resourceKey{row.ID, row.Units}]
+			if ok {
+				value.Amount = amount
+				value.Isfree = pack.freeResource(row.ID)
+				if amount > 0 {
+					perunit := roundPrice(float64(pack.Price) / amount)
+					value.PricePerUnit = &perunit
+				}
+			}
+			row.Values = append(row.Values, value)
+		}
+	}
+	return comparison
+}
+
+// freeResource returns true if the resource with the given id is free in
+// the pack.
+func (p *Pack) freeResource(id int16) bool {
+	for _, resource := range p.Resources {
+		if resource.ID == id {
+			return resource.Isfree
+		}
+	}
+	return false
+}
+
+// roundPrice rounds a price to two decimals.
+func roundPrice(price float64) float64 {
+	return math.Round(price*100) / 100
+}
+
+// Similarity returns how similar the pack is to the other one, from 0 to
+// 1. It weighs the resources, the price and the validity: every resource
+// and the price and validity score the smallest value divided by the
+// biggest one, a resource only one pack has scores 0.
+func (p *Pack) Similarity(other *Pack) float64 {
+	mine, theirs := p.resourceVector(), other.resourceVector()
+	keys := map[resourceKey]bool{}
+	for key := range mine {
+		keys[key] = true
+	}
+	for key := range theirs {
+		keys[key] = true
+	}
+	resources := 1.0
+	if len(keys) > 0 {
+		total := 0.0
+		for key := range keys {
+			total += ratio(mine[key], theirs[key])
+		}
+		resources = total / float64(len(keys))
+	}
+	score := resourceWeight*resources + priceWeight*ratio(float64(p.Price), float64(other.Price)) +
+		termWeight*ratio(p.ValidityDays(), other.ValidityDays())
+	return math.Round(score*1000) / 1000
+}
+
+// ratio returns the smallest value divided by the biggest one, 1 if both
+// are 0.
+func ratio(a float64, b float64) float64 {
+	if a == b {
+		return 1
+	}
+	return math.Min(a, b) / math.Max(a, b)
+}
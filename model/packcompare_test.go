@@ -0,0 +1,66 @@
+package model
+
+import (
+	"testing"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// newComparedPack returns a pack with the given price, validity in days
+// and data in MB.
+func newComparedPack(price int, days int, datamb float32) Pack {
+	pack := createExpPack()
+	pack.ID = bson.NewObjectId()
+	pack.Price = price
+	pack.Term = &Term{UnitID: 1, Unit: "day", Amount: days}
+	pack.Resources = []Resource{{ID: 1, Name: "data", Units: "MB", Amount: datamb}}
+	return *pack
+}
+
+// TestNewPackComparison tests resources are aligned with their price per unit
+func TestNewPackComparison(t *testing.T) {
+	weekly := newComparedPack(10000, 7, 2048)
+	monthly := newComparedPack(30000, 0, 1)
+	monthly.Term = &Term{UnitID: 3, Unit: "month", Amount: 1}
+	monthly.Resources = []Resource{{ID: 1, Name: "data", Units: "GB", Amount: 10},
+		{ID: 2, Name: "sms", Units: "sms", Amount: 100, Isfree: true}}
+
+	comparison := NewPackComparison([]Pack{weekly, monthly})
+
+	if len(comparison.Packs) != 2 || comparison.Packs[1].ValidityDays != 30 || comparison.Packs[1].PricePerDay != 1000 {
+		t.Fatalf("Expected the monthly pack to be valid 30 days at 1000 a day but got %+v", comparison.Packs)
+	}
+	if len(comparison.Resources) != 2 {
+		t.Fatalf("Expected data and sms but got %+v", comparison.Resources)
+	}
+	data := comparison.Resources[0]
+	if data.Units != "GB" || data.Values[0].Amount != 2 || *data.Values[0].PricePerUnit != 5000 ||
+		*data.Values[1].PricePerUnit != 3000 {
+		t.Errorf("Expected data in GB at 5000 and 3000 per GB but got %+v", data)
+	}
+	sms := comparison.Resources[1]
+	if sms.Values[0].PricePerUnit != nil || sms.Values[0].Amount != 0 || !sms.Values[1].Isfree {
+		t.Errorf("Expected sms only in the monthly pack but got %+v", sms)
+	}
+}
+
+// TestPackSimilarity tests packs with closer resources, price and
+// validity are more similar
+func TestPackSimilarity(t *testing.T) {
+	pack := newComparedPack(10000, 7, 2048)
+	same := newComparedPack(10000, 7, 2048)
+	nearby := newComparedPack(12000, 7, 3072)
+	far := newComparedPack(50000, 30, 20480)
+	voice := newComparedPack(10000, 7, 0)
+	voice.Resources = []Resource{{ID: 3, Name: "voice", Units: "min", Amount: 100}}
+
+	if got := pack.Similarity(&same); got != 1 {
+		t.Errorf("Expected the same pack to score 1 but got %v", got)
+	}
+	if pack.Similarity(&nearby) <= pack.Similarity(&far) {
+		t.Errorf("Expected the nearby pack to score more than the far one")
+	}
+	if got := pack.Similarity(&voice); got != 0.5 {
+		t.Errorf("Expected only price and validity to match the voice pack but got %v", got)
+	}
+}
@@ -2,6 +2,8 @@ package model
 
 import (
 	"reflect"
+	"strconv"
+	"strings"
 	"time"
 
 	"gopkg.in/mgo.v2/bson"
@@ -52,24 +54,31 @@ type Resource struct {
 
 // Pack contains the regarding to packs for admin purpose.
 type Pack struct {
-	ID        bson.ObjectId `json:"id,omitempty" bson:"_id,omitempty"` // id of the pack in the db
-	ProdID    string        `json:"prodid" bson:"prodid"`              // internal mobile network provider package id
-	Packcode  string        `json:"packcode" bson:"packcode"`          // pack code
-	Name      string        `json:"name" bson:"name"`                  // pack name
-	Desc      string        `json:"desc" bson:"desc"`                  // pack description
-	Img       string        `json:"imgurl,omitempty" bson:"imgurl"`    // Icon image url for the pack
-	Kwds      string        `json:"kwds" bson:"kwds"`                  // keywords for the pack searching
-	Price     int           `json:"price" bson:"price"`                // price for the pack
-	Stock     int           `json:"stock" bson:"stock"`                // pack stock
-	Ownerid   int           `json:"ownerid"`                           // the company owner of the pack for resale
-	Created   time.Time     `json:"created,omitempty" bson:"created"`
-	Updated   time.Time     `json:"updated,omitempty" bson:"updated"`
-	Packtype  *Type         `json:"type" bson:"type"`                               // pack type
-	Mno       *Mno          `json:"mno" bson:"mno"`                                 // Mobile Network operator owner of the pack
-	Term      *Term         `json:"term" bson:"term"`                               // Duration of the pack
-	Ccy       *Currency     `json:"currency" bson:"currency"`                       // Currency of the price of the pack
-	State     PackState     `json:"state,omitempty" bson:"state"`                   // state of the pack register
-	Resources []Resource    `json:"resources,omitempty" bson:"resources,omitempty"` // resources that the pack contains
+	ID           bson.ObjectId     `json:"id,omitempty" bson:"_id,omitempty"` // id of the pack in the db
+	ProdID       string            `json:"prodid" bson:"prodid"`              // internal mobile network provider package id
+	Packcode     string            `json:"packcode" bson:"packcode"`          // pack code
+	Name         string            `json:"name" bson:"name"`                  // pack name
+	Desc         string            `json:"desc" bson:"desc"`                  // pack description
+	Img          string            `json:"imgurl,omitempty" bson:"imgurl"`    // Icon image url for the pack
+	Kwds         string            `json:"kwds" bson:"kwds"`                  // keywords for the pack searching
+	Price        int               `json:"price" bson:"price"`                // price for the pack
+	Stock        int               `json:"stock" bson:"stock"`                // pack stock
+	Ownerid      int               `json:"ownerid" bson:"ownerid"`            // the company owner of the pack for resale
+	Created      time.Time         `json:"created,omitempty" bson:"created"`
+	Updated      time.Time         `json:"updated,omitempty" bson:"updated"`
+	Version      int               `json:"version" bson:"version"`                               // revision of the pack, every change increases it
+	Packtype     *Type             `json:"type" bson:"type"`                                     // pack type
+	Mno          *Mno              `json:"mno" bson:"mno"`                                       // Mobile Network operator owner of the pack
+	Term         *Term             `json:"term" bson:"term"`                                     // Duration of the pack
+	Ccy          *Currency         `json:"currency" bson:"currency"`                             // Currency of the price of the pack
+	State        PackState         `json:"state,omitempty" bson:"state"`                         // state of the pack register
+	Resources    []Resource        `json:"resources,omitempty" bson:"resources,omitempty"`       // resources that the pack contains
+	Components   []string          `json:"components,omitempty" bson:"components,omitempty"`     // ids of the packs of a bundle, a bundle gets their resources
+	Rules        []EligibilityRule `json:"rules,omitempty" bson:"rules,omitempty"`               // conditions a customer must meet to buy the pack
+	Channels     []string          `json:"channels,omitempty" bson:"channels,omitempty"`         // channels where the pack is sold, empty for every channel
+	Regions      []string          `json:"regions,omitempty" bson:"regions,omitempty"`           // regions where the pack is sold, empty for every region
+	Translations []Translation     `json:"translations,omitempty" bson:"translations,omitempty"` // name, description and keywords in other locales
+	Locale       string            `json:"locale,omitempty" bson:"-"`                            // locale of the name, description and keywords served
 }
 
 // PackExists contains pack data to check if the pack exists.
@@ -81,9 +90,18 @@ type PackExists struct {
 
 // Result contains result message
 type Result struct {
-	Code    string `json:"code"`
-	Success bool   `json:"success"`
-	Msg     string `json:"msg"`
+	Code    string       `json:"code"`
+	Success bool         `json:"success"`
+	Msg     string       `json:"msg"`
+	Error   *ErrorDetail `json:"error,omitempty"` // details of the failure
+}
+
+// ErrorDetail contains the details of a failed operation.
+type ErrorDetail struct {
+	Code     string `json:"code"`            // stable code of the error
+	Message  string `json:"message"`         // message in the locale of the caller
+	Category string `json:"category"`        // kind of error. e.g. INVALID_ARGUMENT, NOT_FOUND
+	Field    string `json:"field,omitempty"` // argument that caused the error
 }
 
 // NewPackExists creates an instance of *PackExists from
@@ -120,6 +138,14 @@ func NewKOResult(code string, msg string) *Result {
 	return resultst
 }
 
+// NewErrorResult creates an instance of *Result with
+// .Done = false and the details of the given error.
+func NewErrorResult(detail *ErrorDetail) *Result {
+	resultst := NewKOResult("-1", detail.Message)
+	resultst.Error = detail
+	return resultst
+}
+
 // NewPackExistsFromMap creates a PackExists struct with the given parameters
 func NewPackExistsFromMap(params map[string]interface{}) *PackExists {
 	newkeys := new(PackExists)
@@ -159,6 +185,12 @@ func NewPack(params map[string]interface{}) *Pack {
 	return newpack
 }
 
+// ETag returns the entity tag of the pack, it changes with every change
+// of the pack. e.g. "7"
+func (p *Pack) ETag() string {
+	return strconv.Quote(strconv.Itoa(p.Version))
+}
+
 // NewResourcesFromInterface from a given interface that must be a slice
 // it get the items from interface using reflection. If the given parameter
 // is not a slice an empty array is returned.
@@ -253,3 +285,26 @@ func NewPackState(state int) PackState {
 
 	return result
 }
+
+// ExpiresFrom returns the moment when a pack bought at the given start
+// time stops being valid according to this term. Unit names are accepted
+// in english and spanish, an unknown unit is taken as days.
+func (t *Term) ExpiresFrom(start time.Time) time.Time {
+	if t == nil {
+		return start
+	}
+	switch strings.ToLower(strings.TrimSpace(t.Unit)) {
+	case "minute", "minutes", "min", "minuto", "minutos":
+		return start.Add(time.Duration(t.Amount) * time.Minute)
+	case "hour", "hours", "hora", "horas":
+		return start.Add(time.Duration(t.Amount) * time.Hour)
+	case "week", "weeks", "semana", "semanas":
+		return start.AddDate(0, 0, 7*t.Amount)
+	case "month", "months", "mes", "meses":
+		return start.AddDate(0, t.Amount, 0)
+	case "year", "years", "ano", "año", "anos", "años":
+		return start.AddDate(t.Amount, 0, 0)
+	default:
+		return start.AddDate(0, 0, t.Amount)
+	}
+}
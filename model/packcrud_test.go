@@ -67,6 +67,27 @@ func TestNewOKResult(t *testing.T) {
 	}
 }
 
+// TestNewErrorResult tests instances a failed Result with error details
+func TestNewErrorResult(t *testing.T) {
+	// GIVEN the details of a failure
+	detail := &ErrorDetail{Code: "07", Message: "duplicated pack", Category: "CONFLICT", Field: "packcode"}
+	// WHEN we need to return the failed result
+	result := NewErrorResult(detail)
+	// THEN system returns a failed result with the details
+	if result.Success {
+		t.Fatalf("Expected Result#Success false but got true")
+	}
+	if result.Code != "-1" {
+		t.Fatalf("Expected Result#Code -1 but got %s", result.Code)
+	}
+	if result.Msg != detail.Message {
+		t.Fatalf("Expected Result#Msg %s but got %s", detail.Message, result.Msg)
+	}
+	if result.Error != detail {
+		t.Fatalf("Expected Result#Error %+v but got %+v", detail, result.Error)
+	}
+}
+
 // TestNewPackExists tests instances a PackExists struct
 func TestNewPackExists(t *testing.T) {
 	// GIVEN data for create a new pack exists
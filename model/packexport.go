@@ -0,0 +1,199 @@
+package model
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Export file formats
+const (
+	ExportCSV       = "csv"   // a pack per row with the nested data in columns
+	ExportJSONLines = "jsonl" // a pack per line as json, it can be imported
+	ExportXLSX      = "xlsx"  // a spreadsheet with the columns of the csv
+)
+
+// maxExportResources is the number of resources of a pack that have their
+// own columns, every resource is in the resources column as json.
+const maxExportResources = 3
+
+// PackWriter writes packs one by one to a file, Close must be called
+// after the last one.
+type PackWriter interface {
+	// Write writes a pack.
+	Write(pack *Pack) error
+	// Close writes what is pending, it does not close the underlying writer.
+	Close() error
+}
+
+// ExportContentTypes contains the content type of every export format.
+var ExportContentTypes = map[string]string{
+	ExportCSV:       "text/csv",
+	ExportJSONLines: "application/x-ndjson",
+	ExportXLSX:      "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// NewPackWriter returns a writer of packs in the given format.
+func NewPackWriter(w io.Writer, format string) (PackWriter, error) {
+	switch format {
+	case ExportCSV:
+		return &csvPackWriter{writer: csv.NewWriter(w)}, nil
+	case ExportJSONLines:
+		return &jsonPackWriter{encoder: json.NewEncoder(w)}, nil
+	case ExportXLSX:
+		return &xlsxPackWriter{sheet: newXLSXWriter(w, exportNumericColumns())}, nil
+	}
+	return nil, fmt.Errorf("unknown export format %q", format)
+}
+
+// ExportColumns returns the names of the columns of a csv or xlsx export,
+// a csv export can be imported as the import reads the same columns.
+func ExportColumns() []string {
+	columns := []string{"id", "prodid", "packcode", "name", "desc", "imgurl", "kwds", "price", "stock",
+		"ownerid", "state", "typeid", "typename", "mnoid", "mnoname", "termunitid", "termunit",
+		"termamount", "currencyid", "currencyname", "created", "updated", "resources"}
+	for i := 1; i <= maxExportResources; i++ {
+		resource := "resource" + strconv.Itoa(i)
+		columns = append(columns, resource+"id", resource+"name", resource+"units", resource+"amount", resource+"isfree")
+	}
+	return columns
+}
+
+// exportNumericColumns returns the indexes of the columns whose values
+// are numbers.
+func exportNumericColumns() map[int]bool {
+	numbers := map[string]bool{"price": true, "stock": true, "ownerid": true, "state": true, "typeid": true,
+		"mnoid": true, "termunitid": true, "termamount": true, "currencyid": true}
+	for i := 1; i <= maxExportResources; i++ {
+		resource := "resource" + strconv.Itoa(i)
+		numbers[resource+"id"] = true
+		numbers[resource+"amount"] = true
+	}
+	numeric := map[int]bool{}
+	for i, column := range ExportColumns() {
+		numeric[i] = numbers[column]
+	}
+	return numeric
+}
+
+// ExportRow returns the values of the columns of the pack, the nested
+// data is flattened.
+func ExportRow(pack *Pack) []string {
+	var packtype Type
+	if pack.Packtype != nil {
+		packtype = *pack.Packtype
+	}
+	var mno Mno
+	if pack.Mno != nil {
+		mno = *pack.Mno
+	}
+	var term Term
+	if pack.Term != nil {
+		term = *pack.Term
+	}
+	var ccy Currency
+	if pack.Ccy != nil {
+		ccy = *pack.Ccy
+	}
+	resources := ""
+	if len(pack.Resources) > 0 {
+		content, _ := json.Marshal(pack.Resources)
+		resources = string(content)
+	}
+	id := ""
+	if pack.ID != "" {
+		id = pack.ID.Hex()
+	}
+	row := []string{id, pack.ProdID, pack.Packcode, pack.Name, pack.Desc, pack.Img, pack.Kwds,
+		strconv.Itoa(pack.Price), strconv.Itoa(pack.Stock), strconv.Itoa(pack.Ownerid), strconv.Itoa(int(pack.State)),
+		strconv.Itoa(int(packtype.ID)), packtype.Name, strconv.Itoa(int(mno.ID)), mno.Name,
+		strconv.Itoa(int(term.UnitID)), term.Unit, strconv.Itoa(term.Amount),
+		strconv.Itoa(int(ccy.ID)), ccy.Name, exportTime(pack.Created), exportTime(pack.Updated), resources}
+	for i := 0; i < maxExportResources; i++ {
+		if i >= len(pack.Resources) {
+			row = append(row, "", "", "", "", "")
+			continue
+		}
+		resource := pack.Resources[i]
+		row = append(row, strconv.Itoa(int(resource.ID)), resource.Name, resource.Units,
+			strconv.FormatFloat(float64(resource.Amount), 'f', -1, 32), strconv.FormatBool(resource.Isfree))
+	}
+	return row
+}
+
+// exportTime returns the time in RFC3339, empty if it is zero.
+func exportTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// csvPackWriter writes a header and a row per pack.
+type csvPackWriter struct {
+	writer *csv.Writer
+	header bool
+}
+
+func (c *csvPackWriter) Write(pack *Pack) error {
+	if !c.header {
+		c.header = true
+		if err := c.writer.Write(ExportColumns()); err != nil {
+			return err
+		}
+	}
+	return c.writer.Write(ExportRow(pack))
+}
+
+func (c *csvPackWriter) Close() error {
+	if !c.header {
+		c.header = true
+		if err := c.writer.Write(ExportColumns()); err != nil {
+			return err
+		}
+	}
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+// jsonPackWriter writes a pack per line.
+type jsonPackWriter struct {
+	encoder *json.Encoder
+}
+
+func (j *jsonPackWriter) Write(pack *Pack) error {
+	return j.encoder.Encode(pack)
+}
+
+func (j *jsonPackWriter) Close() error {
+	return nil
+}
+
+// xlsxPackWriter writes a sheet with a header and a row per pack.
+type xlsxPackWriter struct {
+	sheet  *xlsxWriter
+	header bool
+}
+
+func (x *xlsxPackWriter) Write(pack *Pack) error {
+	if !x.header {
+		x.header = true
+		if err := x.sheet.WriteRow(ExportColumns()); err != nil {
+			return err
+		}
+	}
+	return x.sheet.WriteRow(ExportRow(pack))
+}
+
+func (x *xlsxPackWriter) Close() error {
+	if !x.header {
+		x.header = true
+		if err := x.sheet.WriteRow(ExportColumns()); err != nil {
+			return err
+		}
+	}
+	return x.sheet.Close()
+}
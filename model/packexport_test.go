@@ -0,0 +1,105 @@
+package model
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+// TestExportCSVImport tests a csv export flattens the pack and can be imported
+func TestExportCSVImport(t *testing.T) {
+	// GIVEN a pack with resources
+	pack := createExpPack()
+	pack.Resources = []Resource{{ID: 1, Name: "data", Units: "MB", Amount: 500}, {ID: 2, Name: "sms", Units: "sms", Amount: 10, Isfree: true}}
+
+	// WHEN it is exported as csv
+	out := &bytes.Buffer{}
+	writer, err := NewPackWriter(out, ExportCSV)
+	if err != nil {
+		t.Fatalf("Expected err to be nil but it was: %s", err)
+	}
+	if err = writer.Write(pack); err != nil {
+		t.Fatalf("Expected err to be nil but it was: %s", err)
+	}
+	if err = writer.Close(); err != nil {
+		t.Fatalf("Expected err to be nil but it was: %s", err)
+	}
+
+	// THEN the nested data has its own columns
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "id,prodid,packcode,") {
+		t.Fatalf("Expected a header and a row but got %q", out.String())
+	}
+	for _, value := range []string{",Claro,", ",cop,", ",dia,", "1,data,MB,500,false", "2,sms,sms,10,true"} {
+		if !strings.Contains(lines[1], value) {
+			t.Errorf("Expected the row to have %q but got %s", value, lines[1])
+		}
+	}
+	// AND it is imported as the same pack
+	rows, err := ReadImportRows(strings.NewReader(out.String()), ImportCSV)
+	if err != nil || len(rows) != 1 || rows[0].Err != nil {
+		t.Fatalf("Expected the export to be imported but got %v %+v", err, rows)
+	}
+	imported := rows[0].Pack
+	if imported.Packcode != pack.Packcode || *imported.Mno != *pack.Mno || *imported.Term != *pack.Term ||
+		len(imported.Resources) != 2 || imported.Resources[1] != pack.Resources[1] {
+		t.Fatalf("Expected %+v but got %+v", pack, imported)
+	}
+}
+
+// TestExportXLSX tests an xlsx export is a workbook with a row per pack
+func TestExportXLSX(t *testing.T) {
+	pack := createExpPack()
+	pack.Name = "Whatsapp <weekend> & more"
+	out := &bytes.Buffer{}
+	writer, _ := NewPackWriter(out, ExportXLSX)
+	writer.Write(pack)
+	writer.Write(pack)
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Expected err to be nil but it was: %s", err)
+	}
+
+	workbook, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	if err != nil {
+		t.Fatalf("Expected a zip file but got: %s", err)
+	}
+	parts := map[string]string{}
+	for _, file := range workbook.File {
+		content, _ := file.Open()
+		data, _ := io.ReadAll(content)
+		parts[file.Name] = string(data)
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("Expected the workbook to have %s", name)
+		}
+	}
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	for _, value := range []string{`<row r="3">`, `Whatsapp &lt;weekend&gt; &amp; more`, `<c r="H2"><v>2500</v></c>`, `<c r="C2" t="inlineStr"><is><t xml:space="preserve">wh12</t>`} {
+		if !strings.Contains(sheet, value) {
+			t.Errorf("Expected the sheet to have %s but got %s", value, sheet)
+		}
+	}
+	if !strings.HasSuffix(sheet, "</sheetData></worksheet>") {
+		t.Errorf("Expected the sheet to be closed")
+	}
+}
+
+// TestExportFormats tests the formats of the export
+func TestExportFormats(t *testing.T) {
+	out := &bytes.Buffer{}
+	writer, _ := NewPackWriter(out, ExportJSONLines)
+	writer.Write(createExpPack())
+	writer.Close()
+	if !strings.HasPrefix(out.String(), `{"prodid":"3","packcode":"wh12"`) || strings.Count(out.String(), "\n") != 1 {
+		t.Fatalf("Expected a json line but got %s", out.String())
+	}
+	if _, err := NewPackWriter(out, "pdf"); err == nil {
+		t.Fatalf("Expected an unknown format to be rejected")
+	}
+	if xlsxColumn(0) != "A" || xlsxColumn(25) != "Z" || xlsxColumn(26) != "AA" || xlsxColumn(37) != "AL" {
+		t.Fatalf("Expected the column names of excel")
+	}
+}
@@ -0,0 +1,72 @@
+package model
+
+// PackFilter contains the conditions of the packs listed, exported or
+// changed in bulk. A zero value matches any pack.
+type PackFilter struct {
+	MnoID    int8       // mno of the packs
+	Ownerid  int        // owner of the packs
+	TypeID   int8       // type of the packs
+	State    *PackState // state of the packs, nil for any
+	MinPrice int        // lowest price of the packs
+	MaxPrice int        // highest price of the packs, 0 for any
+	Channel  string     // channel where the packs are sold, empty for any
+	Region   string     // region where the packs are sold, empty for any
+}
+
+// NewPackFilter creates a PackFilter with the given parameters, the ones
+// that are not there match any pack.
+func NewPackFilter(params map[string]interface{}) *PackFilter {
+	filter := new(PackFilter)
+	if mnoid, ok := params["mnoid"].(int); ok {
+		filter.MnoID = int8(mnoid)
+	}
+	if ownerid, ok := params["ownerid"].(int); ok {
+		filter.Ownerid = ownerid
+	}
+	if typeid, ok := params["typeid"].(int); ok {
+		filter.TypeID = int8(typeid)
+	}
+	if state, ok := params["state"].(int); ok {
+		packstate := PackState(state)
+		filter.State = &packstate
+	}
+	if minprice, ok := params["minprice"].(int); ok {
+		filter.MinPrice = minprice
+	}
+	if maxprice, ok := params["maxprice"].(int); ok {
+		filter.MaxPrice = maxprice
+	}
+	if channel, ok := params["channel"].(string); ok {
+		filter.Channel = NormalizeAvailabilityCode(channel)
+	}
+	if region, ok := params["region"].(string); ok {
+		filter.Region = NormalizeAvailabilityCode(region)
+	}
+	return filter
+}
+
+// Matches returns true if the pack meets every condition of the filter.
+func (f *PackFilter) Matches(pack *Pack) bool {
+	if f == nil {
+		return pack != nil
+	}
+	switch {
+	case pack == nil:
+		return false
+	case f.MnoID != 0 && (pack.Mno == nil || pack.Mno.ID != f.MnoID):
+		return false
+	case f.Ownerid != 0 && pack.Ownerid != f.Ownerid:
+		return false
+	case f.TypeID != 0 && (pack.Packtype == nil || pack.Packtype.ID != f.TypeID):
+		return false
+	case f.State != nil && pack.State != *f.State:
+		return false
+	case pack.Price < f.MinPrice:
+		return false
+	case f.MaxPrice != 0 && pack.Price > f.MaxPrice:
+		return false
+	case !pack.AvailableIn(f.Channel, f.Region):
+		return false
+	}
+	return true
+}
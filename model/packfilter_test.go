@@ -0,0 +1,31 @@
+package model
+
+import "testing"
+
+// TestPackFilterMatches tests which packs match a filter
+func TestPackFilterMatches(t *testing.T) {
+	pack := createExpPack()
+	pack.Ownerid = 7
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		want   bool
+	}{
+		{name: "any", params: map[string]interface{}{}, want: true},
+		{name: "mno", params: map[string]interface{}{"mnoid": 2}, want: true},
+		{name: "other mno", params: map[string]interface{}{"mnoid": 5}, want: false},
+		{name: "owner and type", params: map[string]interface{}{"ownerid": 7, "typeid": 1}, want: true},
+		{name: "other type", params: map[string]interface{}{"typeid": 2}, want: false},
+		{name: "inactive", params: map[string]interface{}{"state": 0}, want: true},
+		{name: "active", params: map[string]interface{}{"state": 1}, want: false},
+		{name: "price range", params: map[string]interface{}{"minprice": 2000, "maxprice": 3000}, want: true},
+		{name: "cheaper", params: map[string]interface{}{"maxprice": 2000}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewPackFilter(tt.params).Matches(pack); got != tt.want {
+				t.Errorf("PackFilter.Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,279 @@
+package model
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Import file formats
+const (
+	ImportCSV        = "csv"   // a pack per row, the first row has the column names
+	ImportJSONLines  = "jsonl" // a pack per line as json
+	maxImportLineLen = 1 << 20
+)
+
+// Import modes
+const (
+	ImportCreate = "create" // rows of existing packs fail
+	ImportUpsert = "upsert" // rows of existing packs update them
+)
+
+// Import actions of a row
+const (
+	ImportCreated = "created"
+	ImportUpdated = "updated"
+	ImportFailed  = "failed"
+)
+
+// importColumns contains the columns of an import csv, the columns that
+// are not required can be left out.
+var importColumns = []struct {
+	name     string
+	required bool
+}{
+	{"prodid", true}, {"packcode", true}, {"name", true}, {"desc", true},
+	{"imgurl", true}, {"kwds", true}, {"price", true}, {"ownerid", false},
+	{"typeid", true}, {"typename", true}, {"mnoid", true}, {"mnoname", true},
+	{"termunitid", true}, {"termunit", true}, {"termamount", true},
+	{"currencyid", true}, {"currencyname", true}, {"resources", false},
+}
+
+// ImportOptions contains how the rows of a file are imported.
+type ImportOptions struct {
+	Format      string // ImportCSV or ImportJSONLines
+	Mode        string // ImportCreate or ImportUpsert
+	DryRun      bool   // true to check the rows without storing them
+	Locale      string // locale of the error messages of the report
+	ChangePrice bool   // true if rows can change the price of existing packs
+	ChangeOwner bool   // true if rows can change the owner of existing packs
+}
+
+// ImportColumnError is the error of a csv whose header has not a
+// required column.
+type ImportColumnError struct {
+	Column string
+}
+
+// Error returns the missing column.
+func (e *ImportColumnError) Error() string {
+	return "column " + e.Column + " is missing in the csv header"
+}
+
+// ImportRow is a pack read from a line of an import file.
+type ImportRow struct {
+	Line   int    // line of the file, the csv header is line 1
+	Pack   *Pack  // pack of the line, nil if it could not be read
+	Column string // column that could not be read
+	Err    error  // why the line could not be read
+}
+
+// ImportRowResult contains what was done with a row of an import file.
+type ImportRowResult struct {
+	Line     int          `json:"line"`
+	Packcode string       `json:"packcode,omitempty"`
+	ProdID   string       `json:"prodid,omitempty"`
+	PackID   string       `json:"packid,omitempty"` // id of the created or updated pack
+	Action   string       `json:"action"`           // created, updated or failed
+	Error    *ErrorDetail `json:"error,omitempty"`  // why the row failed
+}
+
+// ImportReport contains the result of every row of an import file. Rows
+// are imported one by one, a failed row does not stop the others.
+type ImportReport struct {
+	Mode    string            `json:"mode"`
+	DryRun  bool              `json:"dryrun"`
+	Total   int               `json:"total"`
+	Created int               `json:"created"`
+	Updated int               `json:"updated"`
+	Failed  int               `json:"failed"`
+	Rows    []ImportRowResult `json:"rows"`
+}
+
+// NewImportReport creates an empty report of an import with the given options.
+func NewImportReport(options ImportOptions) *ImportReport {
+	return &ImportReport{Mode: options.Mode, DryRun: options.DryRun, Rows: []ImportRowResult{}}
+}
+
+// Add adds the result of a row to the report.
+func (r *ImportReport) Add(result ImportRowResult) {
+	r.Total++
+	switch result.Action {
+	case ImportCreated:
+		r.Created++
+	case ImportUpdated:
+		r.Updated++
+	default:
+		r.Failed++
+	}
+	r.Rows = append(r.Rows, result)
+}
+
+// ReadImportRows reads the packs of an import file of the given format.
+// Lines that are not a pack are returned as rows with an error, the
+// error is only returned if the file cannot be read at all.
+func ReadImportRows(r io.Reader, format string) ([]ImportRow, error) {
+	switch format {
+	case ImportCSV:
+		return readImportCSV(r)
+	case ImportJSONLines:
+		return readImportJSONLines(r)
+	}
+	return nil, fmt.Errorf("unknown import format %q", format)
+}
+
+// readImportCSV reads the packs of a csv with a header row.
+func readImportCSV(r io.Reader) ([]ImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading the csv header: %w", err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, column := range importColumns {
+		if _, ok := columns[column.name]; column.required && !ok {
+			return nil, &ImportColumnError{Column: column.name}
+		}
+	}
+
+	rows := []ImportRow{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		var parseerr *csv.ParseError
+		if errors.As(err, &parseerr) {
+			rows = append(rows, ImportRow{Line: parseerr.StartLine, Err: parseerr.Err})
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading the csv: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		rows = append(rows, newImportCSVRow(line, columns, record))
+	}
+	return rows, nil
+}
+
+// newImportCSVRow creates the row of a csv record.
+func newImportCSVRow(line int, columns map[string]int, record []string) ImportRow {
+	values := csvValues{columns: columns, record: record}
+	pack := new(Pack)
+	pack.ProdID = values.text("prodid")
+	pack.Packcode = values.text("packcode")
+	pack.Name = values.text("name")
+	pack.Desc = values.text("desc")
+	pack.Img = values.text("imgurl")
+	pack.Kwds = values.text("kwds")
+	pack.Price = values.number("price", 0)
+	pack.Ownerid = values.number("ownerid", 0)
+	pack.Packtype = &Type{ID: int8(values.number("typeid", 8)), Name: values.text("typename")}
+	pack.Mno = &Mno{ID: int8(values.number("mnoid", 8)), Name: values.text("mnoname")}
+	pack.Term = &Term{UnitID: int8(values.number("termunitid", 8)), Unit: values.text("termunit"),
+		Amount: values.number("termamount", 0)}
+	pack.Ccy = &Currency{ID: int8(values.number("currencyid", 8)), Name: values.text("currencyname")}
+	if resources := values.text("resources"); resources != "" && values.err == nil {
+		if err := json.Unmarshal([]byte(resources), &pack.Resources); err != nil {
+			values.fail("resources", err)
+		}
+	}
+	if values.err != nil {
+		return ImportRow{Line: line, Column: values.column, Err: values.err}
+	}
+	return ImportRow{Line: line, Pack: pack}
+}
+
+// csvValues reads the values of a csv record by column name, the first
+// value that cannot be read is kept as the error of the record.
+type csvValues struct {
+	columns map[string]int
+	record  []string
+	column  string
+	err     error
+}
+
+// text returns the value of the column, empty if the record has not it.
+func (v *csvValues) text(column string) string {
+	i, ok := v.columns[column]
+	if !ok || i >= len(v.record) {
+		return ""
+	}
+	return strings.TrimSpace(v.record[i])
+}
+
+// number returns the value of the column as an integer of the given bit
+// size, 0 for any size. An empty value is 0.
+func (v *csvValues) number(column string, bitsize int) int {
+	text := v.text(column)
+	if text == "" {
+		return 0
+	}
+	if bitsize == 0 {
+		bitsize = strconv.IntSize
+	}
+	value, err := strconv.ParseInt(text, 10, bitsize)
+	if err != nil {
+		v.fail(column, err)
+		return 0
+	}
+	return int(value)
+}
+
+// fail keeps the first error of the record.
+func (v *csvValues) fail(column string, err error) {
+	if v.err == nil {
+		v.column = column
+		v.err = err
+	}
+}
+
+// readImportJSONLines reads a pack per line, blank lines are skipped.
+func readImportJSONLines(r io.Reader) ([]ImportRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxImportLineLen)
+	rows := []ImportRow{}
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		pack := new(Pack)
+		err := json.Unmarshal([]byte(text), pack)
+		if err != nil {
+			rows = append(rows, ImportRow{Line: line, Err: err})
+			continue
+		}
+		rows = append(rows, ImportRow{Line: line, Pack: newImportedPack(pack)})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading line %d: %w", line+1, err)
+	}
+	return rows, nil
+}
+
+// newImportedPack keeps only the catalog data of a pack read from a file,
+// the id, stock, state and dates are set by the service.
+func newImportedPack(pack *Pack) *Pack {
+	pack.ID = ""
+	pack.Stock = 0
+	pack.State = Inactive
+	pack.Locale = ""
+	return pack
+}
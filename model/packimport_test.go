@@ -0,0 +1,92 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+const importHeader = "prodid,packcode,name,desc,imgurl,kwds,price,ownerid,typeid,typename,mnoid,mnoname,termunitid,termunit,termamount,currencyid,currencyname,resources\n"
+
+// TestReadImportCSV tests every csv line is read as a pack or as a failed row
+func TestReadImportCSV(t *testing.T) {
+	// GIVEN a csv with a good line, a bad price and a blank line
+	file := importHeader +
+		`WAPP01,0008,Whatsapp weekend,Chat all weekend,http://img/wapp.png,chat,2000,7,1,Whatsapp,2,Claro,1,day,2,1,COP,"[{""id"":1,""name"":""data"",""units"":""MB"",""amount"":500}]"` + "\n" +
+		"WAPP02,0009,Whatsapp week,Chat all week,http://img/wapp.png,chat,two,7,1,Whatsapp,2,Claro,1,day,7,1,COP,\n" +
+		"\n"
+
+	// WHEN the csv is read
+	rows, err := ReadImportRows(strings.NewReader(file), ImportCSV)
+
+	// THEN there is a row per line with the line number
+	if err != nil {
+		t.Fatalf("Expected err to be nil but it was: %s", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("Expected 2 rows but got %d: %+v", len(rows), rows)
+	}
+	good := rows[0]
+	if good.Err != nil || good.Line != 2 || good.Pack.Packcode != "0008" || good.Pack.Mno.ID != 2 ||
+		good.Pack.Term.Amount != 2 || len(good.Pack.Resources) != 1 || good.Pack.Resources[0].Amount != 500 {
+		t.Fatalf("Expected the first row to be read but got %+v %+v", good, good.Pack)
+	}
+	bad := rows[1]
+	if bad.Err == nil || bad.Line != 3 || bad.Column != "price" || bad.Pack != nil {
+		t.Fatalf("Expected the second row to fail on the price but got %+v", bad)
+	}
+}
+
+// TestReadImportCSVHeader tests a csv without the required columns is rejected
+func TestReadImportCSVHeader(t *testing.T) {
+	_, err := ReadImportRows(strings.NewReader("prodid,packcode,name\nWAPP01,0008,Whatsapp\n"), ImportCSV)
+	columnerr, ok := err.(*ImportColumnError)
+	if !ok || columnerr.Column != "desc" {
+		t.Fatalf("Expected a csv without the desc column to be rejected but got %v", err)
+	}
+	_, err = ReadImportRows(strings.NewReader(""), "xml")
+	if err == nil {
+		t.Fatalf("Expected an unknown format to be rejected")
+	}
+}
+
+// TestReadImportJSONLines tests every json line is read as a pack or as a failed row
+func TestReadImportJSONLines(t *testing.T) {
+	file := `{"id":"5a12211dcc7c76da03df50f7","prodid":"WAPP01","packcode":"0008","name":"Whatsapp weekend","stock":30,"state":1,"mno":{"id":2,"name":"Claro"}}` + "\n" +
+		"\n" +
+		`{"prodid":"WAPP02",` + "\n"
+
+	rows, err := ReadImportRows(strings.NewReader(file), ImportJSONLines)
+
+	if err != nil {
+		t.Fatalf("Expected err to be nil but it was: %s", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("Expected 2 rows but got %d: %+v", len(rows), rows)
+	}
+	pack := rows[0].Pack
+	if rows[0].Line != 1 || pack == nil || pack.Packcode != "0008" || pack.Mno.ID != 2 {
+		t.Fatalf("Expected the first line to be read but got %+v", rows[0])
+	}
+	if pack.ID != "" || pack.Stock != 0 || pack.State != Inactive {
+		t.Fatalf("Expected only the catalog data to be imported but got %+v", pack)
+	}
+	if rows[1].Line != 3 || rows[1].Err == nil {
+		t.Fatalf("Expected the third line to fail but got %+v", rows[1])
+	}
+}
+
+// TestImportReport tests the report counts every action
+func TestImportReport(t *testing.T) {
+	report := NewImportReport(ImportOptions{Mode: ImportUpsert, DryRun: true})
+	report.Add(ImportRowResult{Line: 2, Action: ImportCreated})
+	report.Add(ImportRowResult{Line: 3, Action: ImportUpdated})
+	report.Add(ImportRowResult{Line: 4, Action: ImportFailed})
+	report.Add(ImportRowResult{Line: 5, Action: ImportFailed})
+
+	if report.Total != 4 || report.Created != 1 || report.Updated != 1 || report.Failed != 2 || len(report.Rows) != 4 {
+		t.Fatalf("Expected 1 created, 1 updated and 2 failed rows but got %+v", report)
+	}
+	if report.Mode != ImportUpsert || !report.DryRun {
+		t.Fatalf("Expected the report to keep the options but got %+v", report)
+	}
+}
@@ -0,0 +1,117 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// PackMergeFields contains the fields of a pack a merge patch can change,
+// the catalog data replaced by an update. The state, the stock and the
+// other data have their own operations.
+var PackMergeFields = map[string]bool{
+	"prodid":       true,
+	"packcode":     true,
+	"name":         true,
+	"desc":         true,
+	"imgurl":       true,
+	"kwds":         true,
+	"price":        true,
+	"ownerid":      true,
+	"type":         true,
+	"mno":          true,
+	"term":         true,
+	"currency":     true,
+	"resources":    true,
+	"translations": true,
+}
+
+// PackMergeError is returned when a merge patch changes a field of the
+// pack that is not in PackMergeFields.
+type PackMergeError struct {
+	Field string // name of the field in the json of the pack
+}
+
+// Error returns the field that cannot be changed.
+func (e *PackMergeError) Error() string {
+	return "field " + e.Field + " of the pack cannot be patched"
+}
+
+// MergePack returns a copy of the pack with the given json merge patch
+// (RFC 7386) applied: the fields of the patch replace the fields of the
+// pack, objects are merged and null removes a field.
+func MergePack(pack *Pack, patch []byte) (*Pack, error) {
+	changes := map[string]interface{}{}
+	err := json.Unmarshal(patch, &changes)
+	if err != nil {
+		return nil, err
+	}
+	for field := range changes {
+		if !PackMergeFields[field] {
+			return nil, &PackMergeError{Field: field}
+		}
+	}
+	document, err := json.Marshal(pack)
+	if err != nil {
+		return nil, err
+	}
+	current := map[string]interface{}{}
+	err = json.Unmarshal(document, &current)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := json.Marshal(mergePatch(current, changes))
+	if err != nil {
+		return nil, err
+	}
+	mergedpack := new(Pack)
+	err = json.Unmarshal(merged, mergedpack)
+	if err != nil {
+		return nil, err
+	}
+	return mergedpack, nil
+}
+
+// mergePatch applies the patch to the target as RFC 7386 does.
+func mergePatch(target interface{}, patch interface{}) interface{} {
+	patchobject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetobject, ok := target.(map[string]interface{})
+	if !ok {
+		targetobject = map[string]interface{}{}
+	}
+	for key, value := range patchobject {
+		if value == nil {
+			delete(targetobject, key)
+			continue
+		}
+		targetobject[key] = mergePatch(targetobject[key], value)
+	}
+	return targetobject
+}
+
+// ChangedFields returns the fields of PackMergeFields whose json differs
+// between the pack and the new one, sorted by name.
+func (p *Pack) ChangedFields(newpack *Pack) []string {
+	current, newfields := packMergeValues(p), packMergeValues(newpack)
+	changed := []string{}
+	for field := range PackMergeFields {
+		if !bytes.Equal(current[field], newfields[field]) {
+			changed = append(changed, field)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// packMergeValues returns the json of every field of the pack.
+func packMergeValues(pack *Pack) map[string]json.RawMessage {
+	values := map[string]json.RawMessage{}
+	document, err := json.Marshal(pack)
+	if err == nil {
+		json.Unmarshal(document, &values)
+	}
+	return values
+}
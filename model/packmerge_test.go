@@ -0,0 +1,91 @@
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// TestMergePack tests a merge patch changes only the given catalog data
+func TestMergePack(t *testing.T) {
+	pack := createExpPack()
+	pack.ID = bson.NewObjectId()
+	pack.Stock = 30
+	pack.Version = 4
+	pack.Resources = []Resource{{ID: 1, Name: "data", Units: "MB", Amount: 100}}
+
+	merged, err := MergePack(pack, []byte(`{"price":3000,"imgurl":null,"term":{"amount":7},"resources":[]}`))
+	if err != nil {
+		t.Fatalf("Expected the patch to be merged but got %s", err)
+	}
+	if merged.Price != 3000 || merged.Img != "" || len(merged.Resources) != 0 {
+		t.Errorf("Expected the price, image and resources to change but got %+v", merged)
+	}
+	if merged.Term.Amount != 7 || merged.Term.Unit != "dia" {
+		t.Errorf("Expected the term to be merged but got %+v", merged.Term)
+	}
+	if merged.ID != pack.ID || merged.Stock != 30 || merged.Version != 4 || merged.Name != pack.Name {
+		t.Errorf("Expected the other fields to be kept but got %+v", merged)
+	}
+	if pack.Price != 2500 || pack.Term.Amount != 2 {
+		t.Errorf("Expected the pack not to change but got %+v", pack)
+	}
+}
+
+// TestMergePackRejected tests the patches that cannot be merged
+func TestMergePackRejected(t *testing.T) {
+	pack := createExpPack()
+	tests := []struct {
+		name      string
+		patch     string
+		wantField string // field of the PackMergeError, empty for other errors
+	}{
+		{name: "stock", patch: `{"stock":10}`, wantField: "stock"},
+		{name: "state", patch: `{"name":"Whatsapp","state":1}`, wantField: "state"},
+		{name: "not an object", patch: `[]`},
+		{name: "wrong type", patch: `{"price":"cheap"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := MergePack(pack, []byte(tt.patch))
+			var mergeerr *PackMergeError
+			if err == nil || errors.As(err, &mergeerr) != (tt.wantField != "") {
+				t.Fatalf("Expected the patch to be rejected but got %v", err)
+			}
+			if mergeerr != nil && mergeerr.Field != tt.wantField {
+				t.Errorf("Expected the field %s but got %s", tt.wantField, mergeerr.Field)
+			}
+		})
+	}
+}
+
+// TestPackETag tests the entity tag follows the version of the pack
+func TestPackETag(t *testing.T) {
+	pack := createExpPack()
+	if pack.ETag() != `"0"` {
+		t.Errorf("Expected the tag of a pack without version but got %s", pack.ETag())
+	}
+	pack.Version = 12
+	if pack.ETag() != `"12"` {
+		t.Errorf("Expected the tag of the version but got %s", pack.ETag())
+	}
+}
+
+// TestChangedFields tests only the catalog data that differs is returned
+func TestChangedFields(t *testing.T) {
+	pack := createExpPack()
+	newpack := *pack
+	newpack.Price = 3000
+	newpack.Name = "Whatsapp ilimitado"
+	newpack.Stock = 10
+
+	changed := pack.ChangedFields(&newpack)
+
+	if len(changed) != 2 || changed[0] != "name" || changed[1] != "price" {
+		t.Errorf("Expected name and price to change but got %v", changed)
+	}
+	if fields := pack.ChangedFields(pack); len(fields) != 0 {
+		t.Errorf("Expected no changes but got %v", fields)
+	}
+}
@@ -0,0 +1,118 @@
+package model
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// placeholderPattern matches a placeholder of a template text and its
+// name. e.g. {{number}}
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z][A-Za-z0-9_]*)\s*\}\}`)
+
+// PackTemplate contains the data of a series of packs, its texts can have
+// placeholders that are replaced by the values given to create a pack.
+// e.g. the name "Whatsapp weekend {{number}}".
+type PackTemplate struct {
+	ID           bson.ObjectId `json:"id,omitempty" bson:"_id,omitempty"` // id of the template in the db
+	Name         string        `json:"name" bson:"name"`                  // name of the template, it is unique
+	Pack         *Pack         `json:"pack" bson:"pack"`                  // data of the packs
+	Placeholders []string      `json:"placeholders" bson:"placeholders"`  // names of the placeholders, every one is required
+	Created      time.Time     `json:"created" bson:"created"`
+}
+
+// NewPackTemplate creates a template of the given pack, the placeholders
+// are taken from its texts.
+func NewPackTemplate(name string, pack *Pack) *PackTemplate {
+	template := &PackTemplate{Name: strings.TrimSpace(name), Pack: pack, Placeholders: []string{}}
+	if pack == nil {
+		return template
+	}
+	seen := map[string]bool{}
+	for _, text := range pack.templateTexts() {
+		for _, match := range placeholderPattern.FindAllStringSubmatch(*text.value, -1) {
+			if !seen[match[1]] {
+				seen[match[1]] = true
+				template.Placeholders = append(template.Placeholders, match[1])
+			}
+		}
+	}
+	return template
+}
+
+// templateText is a text of a pack that can have placeholders.
+type templateText struct {
+	field string
+	value *string
+}
+
+// templateTexts returns the texts of the pack that can have placeholders.
+func (p *Pack) templateTexts() []templateText {
+	return []templateText{{"prodid", &p.ProdID}, {"packcode", &p.Packcode}, {"name", &p.Name},
+		{"desc", &p.Desc}, {"imgurl", &p.Img}, {"kwds", &p.Kwds}}
+}
+
+// InvalidField returns the name of the first field of the template with a
+// wrong value, empty if every value is right. Product id and pack code
+// must have a placeholder as every pack of the template needs its own.
+func (t *PackTemplate) InvalidField() string {
+	if t.Name == "" {
+		return "name"
+	}
+	if t.Pack == nil {
+		return "pack"
+	}
+	for _, text := range t.Pack.templateTexts() {
+		rest := placeholderPattern.ReplaceAllString(*text.value, "")
+		if strings.Contains(rest, "{{") || strings.Contains(rest, "}}") {
+			return text.field
+		}
+	}
+	if !placeholderPattern.MatchString(t.Pack.ProdID) {
+		return "prodid"
+	}
+	if !placeholderPattern.MatchString(t.Pack.Packcode) {
+		return "packcode"
+	}
+	return ""
+}
+
+// MissingValue returns the name of the first placeholder without a value,
+// empty if every one has a value.
+func (t *PackTemplate) MissingValue(values map[string]string) string {
+	for _, placeholder := range t.Placeholders {
+		if strings.TrimSpace(values[placeholder]) == "" {
+			return placeholder
+		}
+	}
+	return ""
+}
+
+// UnknownValue returns the name of the first value without a placeholder,
+// empty if every value has a placeholder.
+func (t *PackTemplate) UnknownValue(values map[string]string) string {
+	known := map[string]bool{}
+	for _, placeholder := range t.Placeholders {
+		known[placeholder] = true
+	}
+	for name := range values {
+		if !known[name] {
+			return name
+		}
+	}
+	return ""
+}
+
+// Render returns a new pack with the data of the template and its
+// placeholders replaced by the given values.
+func (t *PackTemplate) Render(values map[string]string) *Pack {
+	pack := t.Pack.Copy()
+	for _, text := range pack.templateTexts() {
+		*text.value = placeholderPattern.ReplaceAllStringFunc(*text.value, func(placeholder string) string {
+			return strings.TrimSpace(values[placeholderPattern.FindStringSubmatch(placeholder)[1]])
+		})
+	}
+	return pack
+}
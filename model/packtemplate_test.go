@@ -0,0 +1,73 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func createExpTemplate() *PackTemplate {
+	pack := createExpPack()
+	pack.ProdID = "WAPP{{number}}"
+	pack.Packcode = "wh{{number}}"
+	pack.Name = "Whatsapp weekend {{ number }}"
+	pack.Desc = "Whatsapp del fin de semana {{days}}"
+	return NewPackTemplate(" weekend ", pack)
+}
+
+// TestNewPackTemplate tests the placeholders are taken from the texts
+func TestNewPackTemplate(t *testing.T) {
+	template := createExpTemplate()
+	if template.Name != "weekend" {
+		t.Errorf("Expected the name to be trimmed but got %q", template.Name)
+	}
+	if want := []string{"number", "days"}; !reflect.DeepEqual(template.Placeholders, want) {
+		t.Errorf("NewPackTemplate() placeholders = %v, want %v", template.Placeholders, want)
+	}
+	if field := template.InvalidField(); field != "" {
+		t.Errorf("Expected a valid template but %s was invalid", field)
+	}
+}
+
+// TestPackTemplateInvalidField tests the validation of a template
+func TestPackTemplateInvalidField(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(template *PackTemplate)
+		want   string
+	}{
+		{name: "no name", change: func(template *PackTemplate) { template.Name = "" }, want: "name"},
+		{name: "fixed pack code", change: func(template *PackTemplate) { template.Pack.Packcode = "wh13" }, want: "packcode"},
+		{name: "fixed product id", change: func(template *PackTemplate) { template.Pack.ProdID = "WAPP" }, want: "prodid"},
+		{name: "broken placeholder", change: func(template *PackTemplate) { template.Pack.Kwds = "chat {{1st}}" }, want: "kwds"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := createExpTemplate()
+			tt.change(template)
+			if got := template.InvalidField(); got != tt.want {
+				t.Errorf("PackTemplate.InvalidField() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestPackTemplateRender tests a pack is created with the values
+func TestPackTemplateRender(t *testing.T) {
+	template := createExpTemplate()
+	values := map[string]string{"number": "13", "days": " 2 "}
+
+	if missing := template.MissingValue(map[string]string{"number": "13"}); missing != "days" {
+		t.Errorf("PackTemplate.MissingValue() = %q, want days", missing)
+	}
+	if unknown := template.UnknownValue(map[string]string{"number": "13", "week": "1"}); unknown != "week" {
+		t.Errorf("PackTemplate.UnknownValue() = %q, want week", unknown)
+	}
+	pack := template.Render(values)
+	if pack.ProdID != "WAPP13" || pack.Packcode != "wh13" || pack.Name != "Whatsapp weekend 13" ||
+		pack.Desc != "Whatsapp del fin de semana 2" {
+		t.Errorf("PackTemplate.Render() = %+v", pack)
+	}
+	if template.Pack.Packcode != "wh{{number}}" {
+		t.Errorf("Expected the template not to change but got %s", template.Pack.Packcode)
+	}
+}
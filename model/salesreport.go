@@ -0,0 +1,283 @@
+package model
+
+import (
+	"encoding/csv"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Buckets of the sales reports
+const (
+	ReportDay   = "day"
+	ReportWeek  = "week" // weeks start on monday
+	ReportMonth = "month"
+)
+
+// Dimensions of the sales reports
+const (
+	ReportByPack  = "pack"
+	ReportByMno   = "mno"
+	ReportByOwner = "owner"
+)
+
+// MaxReportDays is the longest period of a sales report.
+const MaxReportDays = 731
+
+// SalesReportQuery contains the period, the buckets and the dimension of
+// a sales report. Ownerid, MnoID and PackID limit the orders reported
+// when they are set.
+type SalesReportQuery struct {
+	From     time.Time      // start of the period, inclusive
+	To       time.Time      // end of the period, exclusive
+	Bucket   string         // day, week or month
+	By       string         // pack, mno or owner
+	Timezone string         // name of the time zone of the buckets, e.g. America/Bogota
+	Location *time.Location // time zone of the buckets, UTC if it is nil
+	Ownerid  int
+	MnoID    int8
+	PackID   string
+}
+
+// SalesReportRow contains the sales and refunds of a pack, mno or owner in
+// a bucket. Only the field of the dimension of the report is set.
+type SalesReportRow struct {
+	Period        time.Time `json:"period"`        // start of the bucket in the time zone of the report
+	PackID        string    `json:"packid"`        // id of the sold pack
+	Packcode      string    `json:"packcode"`      // last code the pack was sold with
+	MnoID         *int8     `json:"mnoid"`         // mno of the sold packs
+	Ownerid       *int      `json:"ownerid"`       // owner of the sold packs
+	Ccy           *Currency `json:"currency"`      // currency of the amounts
+	Units         int       `json:"units"`         // packs sold
+	Revenue       int       `json:"revenue"`       // total charged in sales
+	Refunds       int       `json:"refunds"`       // orders refunded
+	RefundsAmount int       `json:"refundsAmount"` // total given back in refunds
+	NetRevenue    int       `json:"netRevenue"`    // revenue minus refunds amount
+	AvgPrice      float64   `json:"avgPrice"`      // revenue divided by units, rounded to two decimals
+}
+
+// SalesReport contains the sales and refunds of a period in buckets of a
+// day, week or month by pack, mno or owner. A sale is reported in the
+// bucket it was made in and a refund in the bucket it was given in, like
+// in settlements.
+type SalesReport struct {
+	From     time.Time        `json:"from"`
+	To       time.Time        `json:"to"`
+	Bucket   string           `json:"bucket"`
+	By       string           `json:"by"`
+	Timezone string           `json:"timezone"`
+	Rows     []SalesReportRow `json:"rows"`
+	query    SalesReportQuery
+	index    map[string]int
+}
+
+// InvalidField returns the name of the first field of the query with a
+// wrong value, empty if every value is right.
+func (q *SalesReportQuery) InvalidField() string {
+	switch {
+	case !q.From.Before(q.To) || q.To.Sub(q.From) > MaxReportDays*24*time.Hour:
+		return "from"
+	case q.Bucket != ReportDay && q.Bucket != ReportWeek && q.Bucket != ReportMonth:
+		return "bucket"
+	case q.By != ReportByPack && q.By != ReportByMno && q.By != ReportByOwner:
+		return "by"
+	}
+	return ""
+}
+
+// Scope limits the query to the orders of the tenant. It returns false if
+// the tenant cannot see the sales asked for.
+func (q *SalesReportQuery) Scope(tenant *Tenant) bool {
+	if tenant != nil && tenant.Admin {
+		return true
+	}
+	if tenant == nil || (tenant.Ownerid == 0 && tenant.MnoID == 0) {
+		return false
+	}
+	if tenant.Ownerid != 0 {
+		if q.Ownerid != 0 && q.Ownerid != tenant.Ownerid {
+			return false
+		}
+		q.Ownerid = tenant.Ownerid
+	}
+	if tenant.MnoID != 0 {
+		if q.MnoID != 0 && q.MnoID != tenant.MnoID {
+			return false
+		}
+		q.MnoID = tenant.MnoID
+	}
+	return true
+}
+
+// NewSalesReportQuery creates a SalesReportQuery with the given
+// parameters, the bucket and the dimension are in lower case.
+func NewSalesReportQuery(params map[string]interface{}) *SalesReportQuery {
+	query := new(SalesReportQuery)
+	query.From, _ = params["from"].(time.Time)
+	query.To, _ = params["to"].(time.Time)
+	bucket, _ := params["bucket"].(string)
+	query.Bucket = strings.ToLower(strings.TrimSpace(bucket))
+	by, _ := params["by"].(string)
+	query.By = strings.ToLower(strings.TrimSpace(by))
+	timezone, _ := params["timezone"].(string)
+	query.Timezone = strings.TrimSpace(timezone)
+	query.Ownerid, _ = params["ownerid"].(int)
+	if mnoid, ok := params["mnoid"].(int); ok {
+		query.MnoID = int8(mnoid)
+	}
+	packid, _ := params["packid"].(string)
+	query.PackID = strings.TrimSpace(packid)
+	return query
+}
+
+// Matches returns true if the order is one of the orders of the query.
+func (q *SalesReportQuery) Matches(order *Order) bool {
+	return (q.Ownerid == 0 || order.Ownerid == q.Ownerid) &&
+		(q.MnoID == 0 || order.MnoID == q.MnoID) &&
+		(q.PackID == "" || order.PackID == q.PackID)
+}
+
+// BucketStart returns the start of the day, week or month of t in the
+// given time zone. Weeks start on monday.
+func BucketStart(t time.Time, bucket string, location *time.Location) time.Time {
+	if location == nil {
+		location = time.UTC
+	}
+	t = t.In(location)
+	switch bucket {
+	case ReportWeek:
+		monday := t.AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+		return time.Date(monday.Year(), monday.Month(), monday.Day(), 0, 0, 0, 0, location)
+	case ReportMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, location)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, location)
+}
+
+// NewSalesReport creates an empty report of the given query, orders are
+// added one by one with Add and the report is finished with Close.
+func NewSalesReport(query *SalesReportQuery) *SalesReport {
+	report := &SalesReport{From: query.From, To: query.To, Bucket: query.Bucket, By: query.By,
+		Timezone: time.UTC.String(), Rows: []SalesReportRow{}, query: *query, index: map[string]int{}}
+	if query.Location != nil {
+		report.Timezone = query.Location.String()
+	}
+	return report
+}
+
+// Add reports the sale and the refund of the order made in the period.
+func (r *SalesReport) Add(order *Order) {
+	if order == nil || !r.query.Matches(order) {
+		return
+	}
+	sold := order.State == OrderCompleted || order.State == OrderRefunded
+	if sold && inPeriod(order.Created, r.From, r.To) {
+		row := r.row(order, order.Created)
+		row.Units++
+		row.Revenue += order.Price
+	}
+	if order.State == OrderRefunded && inPeriod(order.Refunded, r.From, r.To) {
+		row := r.row(order, order.Refunded)
+		row.Refunds++
+		row.RefundsAmount += order.Price
+	}
+}
+
+// Close calculates the net revenue and the average price of every row and
+// sorts the rows by period, dimension and currency.
+func (r *SalesReport) Close() {
+	for i := range r.Rows {
+		row := &r.Rows[i]
+		row.NetRevenue = row.Revenue - row.RefundsAmount
+		if row.Units > 0 {
+			row.AvgPrice = math.Round(float64(row.Revenue)/float64(row.Units)*100) / 100
+		}
+	}
+	sort.SliceStable(r.Rows, func(i, j int) bool {
+		a, b := r.Rows[i], r.Rows[j]
+		if !a.Period.Equal(b.Period) {
+			return a.Period.Before(b.Period)
+		}
+		if a.dimensionKey() != b.dimensionKey() {
+			return a.dimensionKey() < b.dimensionKey()
+		}
+		return idOf(a.Ccy) < idOf(b.Ccy)
+	})
+	r.index = map[string]int{}
+}
+
+// row returns the row of the order in the bucket of the given time, it is
+// created if the report does not have it.
+func (r *SalesReport) row(order *Order, t time.Time) *SalesReportRow {
+	period := BucketStart(t, r.Bucket, r.query.Location)
+	newrow := SalesReportRow{Period: period}
+	switch r.By {
+	case ReportByPack:
+		newrow.PackID = order.PackID
+	case ReportByMno:
+		mnoid := order.MnoID
+		newrow.MnoID = &mnoid
+	case ReportByOwner:
+		ownerid := order.Ownerid
+		newrow.Ownerid = &ownerid
+	}
+	if order.Ccy != nil {
+		ccy := *order.Ccy
+		newrow.Ccy = &ccy
+	}
+	key := period.Format(time.RFC3339) + "|" + newrow.dimensionKey() + "|" + strconv.Itoa(int(idOf(newrow.Ccy)))
+	i, ok := r.index[key]
+	if !ok {
+		i = len(r.Rows)
+		r.index[key] = i
+		r.Rows = append(r.Rows, newrow)
+	}
+	row := &r.Rows[i]
+	if r.By == ReportByPack && order.Packcode != "" {
+		row.Packcode = order.Packcode
+	}
+	return row
+}
+
+// dimensionKey returns the pack, mno or owner of the row as text.
+func (s *SalesReportRow) dimensionKey() string {
+	switch {
+	case s.MnoID != nil:
+		return strconv.Itoa(int(*s.MnoID))
+	case s.Ownerid != nil:
+		return strconv.Itoa(*s.Ownerid)
+	}
+	return s.PackID
+}
+
+// WriteCSV writes the report as csv, a row per row of the report with the
+// period in RFC3339 in the time zone of the report.
+func (r *SalesReport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	rows := [][]string{{"period", "packid", "packcode", "mnoid", "ownerid", "currency", "units", "revenue",
+		"refunds", "refundsamount", "netrevenue", "avgprice"}}
+	for _, row := range r.Rows {
+		mnoid, ownerid, currency := "", "", ""
+		if row.MnoID != nil {
+			mnoid = strconv.Itoa(int(*row.MnoID))
+		}
+		if row.Ownerid != nil {
+			ownerid = strconv.Itoa(*row.Ownerid)
+		}
+		if row.Ccy != nil {
+			currency = row.Ccy.Name
+		}
+		rows = append(rows, []string{row.Period.Format(time.RFC3339), row.PackID, row.Packcode, mnoid, ownerid,
+			currency, strconv.Itoa(row.Units), strconv.Itoa(row.Revenue), strconv.Itoa(row.Refunds),
+			strconv.Itoa(row.RefundsAmount), strconv.Itoa(row.NetRevenue),
+			strconv.FormatFloat(row.AvgPrice, 'f', 2, 64)})
+	}
+	err := writer.WriteAll(rows)
+	if err != nil {
+		return err
+	}
+	return writer.Error()
+}
@@ -0,0 +1,171 @@
+package model
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestBucketStart tests days, weeks and months start in the time zone
+func TestBucketStart(t *testing.T) {
+	bogota, err := time.LoadLocation("America/Bogota")
+	if err != nil {
+		t.Skipf("time zone database is not available: %s", err)
+	}
+	// 2 am UTC of sunday is 9 pm of saturday in Bogota
+	sale := time.Date(2020, time.March, 1, 2, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		bucket   string
+		location *time.Location
+		want     time.Time
+	}{
+		{name: "utc day", bucket: ReportDay, location: nil, want: time.Date(2020, time.March, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "local day", bucket: ReportDay, location: bogota, want: time.Date(2020, time.February, 29, 0, 0, 0, 0, bogota)},
+		{name: "utc week", bucket: ReportWeek, location: time.UTC, want: time.Date(2020, time.February, 24, 0, 0, 0, 0, time.UTC)},
+		{name: "local week", bucket: ReportWeek, location: bogota, want: time.Date(2020, time.February, 24, 0, 0, 0, 0, bogota)},
+		{name: "utc month", bucket: ReportMonth, location: time.UTC, want: time.Date(2020, time.March, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "local month", bucket: ReportMonth, location: bogota, want: time.Date(2020, time.February, 1, 0, 0, 0, 0, bogota)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BucketStart(sale, tt.bucket, tt.location); !got.Equal(tt.want) {
+				t.Errorf("BucketStart() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSalesReportQueryInvalidField tests the period, bucket and dimension
+// of a query are checked
+func TestSalesReportQueryInvalidField(t *testing.T) {
+	from := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		query SalesReportQuery
+		want  string
+	}{
+		{name: "right", query: SalesReportQuery{From: from, To: from.AddDate(0, 1, 0), Bucket: ReportDay, By: ReportByPack}},
+		{name: "empty period", query: SalesReportQuery{From: from, To: from, Bucket: ReportDay, By: ReportByPack}, want: "from"},
+		{name: "long period", query: SalesReportQuery{From: from, To: from.AddDate(3, 0, 0), Bucket: ReportMonth, By: ReportByMno}, want: "from"},
+		{name: "hourly", query: SalesReportQuery{From: from, To: from.AddDate(0, 1, 0), Bucket: "hour", By: ReportByMno}, want: "bucket"},
+		{name: "by msisdn", query: SalesReportQuery{From: from, To: from.AddDate(0, 1, 0), Bucket: ReportWeek, By: "msisdn"}, want: "by"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.query.InvalidField(); got != tt.want {
+				t.Errorf("SalesReportQuery.InvalidField() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSalesReportQueryScope tests callers only see the sales of their
+// owner and mno
+func TestSalesReportQueryScope(t *testing.T) {
+	tests := []struct {
+		name    string
+		tenant  *Tenant
+		ownerid int
+		want    bool
+		wantid  int
+	}{
+		{name: "admin", tenant: &Tenant{Admin: true}, ownerid: 7, want: true, wantid: 7},
+		{name: "own owner", tenant: &Tenant{Ownerid: 7}, want: true, wantid: 7},
+		{name: "other owner", tenant: &Tenant{Ownerid: 7}, ownerid: 8, want: false},
+		{name: "channel only", tenant: &Tenant{Channel: "app"}, want: false},
+		{name: "no tenant", tenant: nil, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := &SalesReportQuery{Ownerid: tt.ownerid}
+			if got := query.Scope(tt.tenant); got != tt.want || (got && query.Ownerid != tt.wantid) {
+				t.Errorf("SalesReportQuery.Scope() = %v with owner %d, want %v with owner %d", got, query.Ownerid, tt.want, tt.wantid)
+			}
+		})
+	}
+}
+
+// newReportOrders returns two sales of wh12 on the 1st and 2nd of march,
+// the first one refunded on the 3rd, and a sale of wh13 on the 2nd.
+func newReportOrders() []Order {
+	day := func(d int) time.Time { return time.Date(2020, time.March, d, 12, 0, 0, 0, time.UTC) }
+	ccy := &Currency{ID: 1, Name: "COP"}
+	return []Order{
+		{PackID: "p12", Packcode: "wh12", MnoID: 2, Ownerid: 7, Price: 2000, Ccy: ccy, State: OrderRefunded,
+			Created: day(1), Refunded: day(3)},
+		{PackID: "p12", Packcode: "wh12", MnoID: 2, Ownerid: 7, Price: 3000, Ccy: ccy, State: OrderCompleted, Created: day(2)},
+		{PackID: "p13", Packcode: "wh13", MnoID: 2, Ownerid: 8, Price: 1000, Ccy: ccy, State: OrderCompleted, Created: day(2)},
+		{PackID: "p13", Packcode: "wh13", MnoID: 2, Ownerid: 8, Price: 1000, Ccy: ccy, State: OrderFailed, Created: day(2)},
+	}
+}
+
+// TestSalesReport tests sales and refunds are reported in the bucket they
+// were made in
+func TestSalesReport(t *testing.T) {
+	from := time.Date(2020, time.March, 1, 0, 0, 0, 0, time.UTC)
+	report := NewSalesReport(&SalesReportQuery{From: from, To: from.AddDate(0, 1, 0), Bucket: ReportDay, By: ReportByPack})
+	orders := newReportOrders()
+	for i := range orders {
+		report.Add(&orders[i])
+	}
+	report.Close()
+
+	if len(report.Rows) != 4 {
+		t.Fatalf("Expected 4 rows but got %+v", report.Rows)
+	}
+	first, last := report.Rows[0], report.Rows[3]
+	if first.PackID != "p12" || first.Packcode != "wh12" || first.Units != 1 || first.Revenue != 2000 || first.Refunds != 0 {
+		t.Errorf("Expected a sale of wh12 on the 1st but got %+v", first)
+	}
+	if !last.Period.Equal(from.AddDate(0, 0, 2)) || last.Units != 0 || last.Refunds != 1 || last.NetRevenue != -2000 {
+		t.Errorf("Expected the refund of wh12 on the 3rd but got %+v", last)
+	}
+}
+
+// TestSalesReportByMonth tests the rows of a dimension are added up and
+// orders of other owners are not reported
+func TestSalesReportByMonth(t *testing.T) {
+	from := time.Date(2020, time.March, 1, 0, 0, 0, 0, time.UTC)
+	report := NewSalesReport(&SalesReportQuery{From: from, To: from.AddDate(0, 1, 0), Bucket: ReportMonth,
+		By: ReportByOwner, Ownerid: 7})
+	orders := newReportOrders()
+	for i := range orders {
+		report.Add(&orders[i])
+	}
+	report.Close()
+
+	if len(report.Rows) != 1 {
+		t.Fatalf("Expected a row of owner 7 but got %+v", report.Rows)
+	}
+	row := report.Rows[0]
+	if *row.Ownerid != 7 || row.PackID != "" || row.Units != 2 || row.Revenue != 5000 || row.Refunds != 1 ||
+		row.RefundsAmount != 2000 || row.NetRevenue != 3000 || row.AvgPrice != 2500 {
+		t.Errorf("Expected 2 sales and a refund of owner 7 but got %+v", row)
+	}
+}
+
+// TestSalesReportWriteCSV tests a line per row after the header
+func TestSalesReportWriteCSV(t *testing.T) {
+	from := time.Date(2020, time.March, 1, 0, 0, 0, 0, time.UTC)
+	report := NewSalesReport(&SalesReportQuery{From: from, To: from.AddDate(0, 1, 0), Bucket: ReportWeek, By: ReportByMno})
+	orders := newReportOrders()
+	for i := range orders {
+		report.Add(&orders[i])
+	}
+	report.Close()
+	buffer := new(bytes.Buffer)
+
+	err := report.WriteCSV(buffer)
+
+	if err != nil {
+		t.Fatalf("Expected err to be nil but it was: %s", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	want := "2020-02-24T00:00:00Z,,,2,,COP,1,2000,0,0,2000,2000.00"
+	if len(lines) != 3 || lines[1] != want {
+		t.Errorf("Expected the week of february 24th as %q but got %v", want, lines)
+	}
+}
@@ -0,0 +1,114 @@
+package model
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Kinds of settlement lines
+const (
+	SettlementSale   = "sale"
+	SettlementRefund = "refund"
+)
+
+// SettlementLine contains a sale or a refund of a settlement.
+type SettlementLine struct {
+	Kind       string    `json:"kind" bson:"kind"`             // sale or refund
+	OrderID    string    `json:"orderid" bson:"orderid"`       // order sold or refunded
+	Msisdn     string    `json:"msisdn" bson:"msisdn"`         // subscriber line number
+	Packcode   string    `json:"packcode" bson:"packcode"`     // code of the sold pack
+	Amount     int       `json:"amount" bson:"amount"`         // price charged, negative for refunds
+	Commission int       `json:"commission" bson:"commission"` // commission earned, negative for refunds
+	Date       time.Time `json:"date" bson:"date"`             // when the sale or the refund was made
+}
+
+// Settlement contains the statement of the sales of a reseller in a period.
+type Settlement struct {
+	ID            bson.ObjectId    `json:"id,omitempty" bson:"_id,omitempty"`      // id of the settlement in the db
+	Ownerid       int              `json:"ownerid" bson:"ownerid"`                 // company owner of the packs for resale
+	From          time.Time        `json:"from" bson:"from"`                       // start of the period, inclusive
+	To            time.Time        `json:"to" bson:"to"`                           // end of the period, exclusive
+	Sales         int              `json:"sales" bson:"sales"`                     // number of sales
+	SalesAmount   int              `json:"salesamount" bson:"salesamount"`         // total charged in sales
+	Commissions   int              `json:"commissions" bson:"commissions"`         // commissions earned in sales
+	Refunds       int              `json:"refunds" bson:"refunds"`                 // number of refunds
+	RefundsAmount int              `json:"refundsamount" bson:"refundsamount"`     // total given back in refunds
+	RefundedComm  int              `json:"refundedcomm" bson:"refundedcomm"`       // commissions taken back in refunds
+	Payable       int              `json:"payable" bson:"payable"`                 // commissions owed to the reseller
+	Lines         []SettlementLine `json:"lines,omitempty" bson:"lines,omitempty"` // sales and refunds of the period
+	Created       time.Time        `json:"created,omitempty" bson:"created"`
+}
+
+// NewSettlement creates the statement of the given owner for the period
+// [from, to). Orders completed in the period are sales and orders refunded
+// in the period are refunds, an order sold and refunded in the same
+// period counts as both.
+func NewSettlement(ownerid int, from time.Time, to time.Time, orders []Order) *Settlement {
+	settlement := new(Settlement)
+	settlement.Ownerid = ownerid
+	settlement.From = from
+	settlement.To = to
+	settlement.Lines = []SettlementLine{}
+	for _, order := range orders {
+		if order.Ownerid != ownerid {
+			continue
+		}
+		sold := order.State == OrderCompleted || order.State == OrderRefunded
+		if sold && inPeriod(order.Created, from, to) {
+			settlement.Sales++
+			settlement.SalesAmount += order.Price
+			settlement.Commissions += order.Commission
+			settlement.Lines = append(settlement.Lines, newSettlementLine(SettlementSale, order, 1, order.Created))
+		}
+		if order.State == OrderRefunded && inPeriod(order.Refunded, from, to) {
+			settlement.Refunds++
+			settlement.RefundsAmount += order.Price
+			settlement.RefundedComm += order.Commission
+			settlement.Lines = append(settlement.Lines, newSettlementLine(SettlementRefund, order, -1, order.Refunded))
+		}
+	}
+	settlement.Payable = settlement.Commissions - settlement.RefundedComm
+	settlement.Created = time.Now()
+	return settlement
+}
+
+// newSettlementLine creates a line of the given order, sign is -1 for refunds.
+func newSettlementLine(kind string, order Order, sign int, date time.Time) SettlementLine {
+	return SettlementLine{
+		Kind:       kind,
+		OrderID:    order.ID.Hex(),
+		Msisdn:     order.Msisdn,
+		Packcode:   order.Packcode,
+		Amount:     sign * order.Price,
+		Commission: sign * order.Commission,
+		Date:       date,
+	}
+}
+
+// inPeriod returns true if t is in [from, to).
+func inPeriod(t time.Time, from time.Time, to time.Time) bool {
+	return !t.Before(from) && t.Before(to)
+}
+
+// WriteCSV writes the settlement as csv, a row per line followed by a row
+// with the totals.
+func (s *Settlement) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	rows := [][]string{{"ownerid", "kind", "orderid", "msisdn", "packcode", "amount", "commission", "date"}}
+	owner := strconv.Itoa(s.Ownerid)
+	for _, line := range s.Lines {
+		rows = append(rows, []string{owner, line.Kind, line.OrderID, line.Msisdn, line.Packcode,
+			strconv.Itoa(line.Amount), strconv.Itoa(line.Commission), line.Date.Format(time.RFC3339)})
+	}
+	rows = append(rows, []string{owner, "total", "", "", "",
+		strconv.Itoa(s.SalesAmount - s.RefundsAmount), strconv.Itoa(s.Payable), s.To.Format(time.RFC3339)})
+	err := writer.WriteAll(rows)
+	if err != nil {
+		return err
+	}
+	return writer.Error()
+}
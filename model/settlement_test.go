@@ -0,0 +1,72 @@
+package model
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// TestNewSettlement tests the totals of a reseller statement
+func TestNewSettlement(t *testing.T) {
+	from := time.Date(2018, time.March, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	// GIVEN sales of owner 7 in the period, one refunded in the period
+	// and one sold before the period but refunded in it
+	orders := []Order{
+		{ID: bson.NewObjectId(), Ownerid: 7, Price: 2500, Commission: 250, State: OrderCompleted, Created: from.Add(time.Hour)},
+		{ID: bson.NewObjectId(), Ownerid: 7, Price: 1000, Commission: 100, State: OrderRefunded,
+			Created: from.Add(2 * time.Hour), Refunded: from.Add(3 * time.Hour)},
+		{ID: bson.NewObjectId(), Ownerid: 7, Price: 3000, Commission: 300, State: OrderRefunded,
+			Created: from.Add(-time.Hour), Refunded: from.Add(time.Hour)},
+		{ID: bson.NewObjectId(), Ownerid: 7, Price: 5000, Commission: 500, State: OrderFailed, Created: from.Add(time.Hour)},
+		{ID: bson.NewObjectId(), Ownerid: 7, Price: 4000, Commission: 400, State: OrderCompleted, Created: to},
+		{ID: bson.NewObjectId(), Ownerid: 8, Price: 9000, Commission: 900, State: OrderCompleted, Created: from},
+	}
+
+	// WHEN we settle the period
+	result := NewSettlement(7, from, to, orders)
+
+	// THEN only the sales and refunds of the owner in the period count
+	if result.Sales != 2 || result.SalesAmount != 3500 || result.Commissions != 350 {
+		t.Fatalf("Expected 2 sales of 3500 with 350 of commission but got %+v", result)
+	}
+	if result.Refunds != 2 || result.RefundsAmount != 4000 || result.RefundedComm != 400 {
+		t.Fatalf("Expected 2 refunds of 4000 with 400 of commission but got %+v", result)
+	}
+	if result.Payable != -50 {
+		t.Fatalf("Expected -50 payable but got %d", result.Payable)
+	}
+	if len(result.Lines) != 4 {
+		t.Fatalf("Expected 4 lines but got %d", len(result.Lines))
+	}
+}
+
+// TestSettlementWriteCSV tests the csv export of a settlement
+func TestSettlementWriteCSV(t *testing.T) {
+	from := time.Date(2018, time.March, 1, 0, 0, 0, 0, time.UTC)
+	orders := []Order{
+		{ID: bson.NewObjectId(), Ownerid: 7, Msisdn: "573001234567", Packcode: "wh13",
+			Price: 2500, Commission: 250, State: OrderCompleted, Created: from},
+	}
+	settlement := NewSettlement(7, from, from.AddDate(0, 1, 0), orders)
+	var buffer bytes.Buffer
+
+	err := settlement.WriteCSV(&buffer)
+
+	if err != nil {
+		t.Fatalf("Expected err to be nil but it was: %s", err)
+	}
+	rows := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(rows) != 3 {
+		t.Fatalf("Expected header, line and total rows but got %q", rows)
+	}
+	if !strings.HasPrefix(rows[1], "7,sale,"+orders[0].ID.Hex()+",573001234567,wh13,2500,250,") {
+		t.Fatalf("Unexpected sale row %q", rows[1])
+	}
+	if !strings.HasPrefix(rows[2], "7,total,,,,2500,250,") {
+		t.Fatalf("Unexpected total row %q", rows[2])
+	}
+}
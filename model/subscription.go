@@ -0,0 +1,149 @@
+package model
+
+import (
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// SubscriptionState defines subscription states
+type SubscriptionState int8
+
+// Subscription states
+const (
+	SubscriptionCanceled  SubscriptionState = 0
+	SubscriptionActive    SubscriptionState = 1
+	SubscriptionPaused    SubscriptionState = 2
+	SubscriptionSuspended SubscriptionState = 3 // renewal failed too many times
+)
+
+// PriceNotice contains a pack price change announced to a subscriber,
+// the new price is charged once the notice period is over.
+type PriceNotice struct {
+	Price   int       `json:"price" bson:"price"`     // new price of the pack
+	Noticed time.Time `json:"noticed" bson:"noticed"` // when the change was noticed
+}
+
+// Renewal contains a renewal made to a subscription.
+type Renewal struct {
+	OrderID       string    `json:"orderid" bson:"orderid"`             // order that sold the renewal
+	EntitlementID string    `json:"entitlementid" bson:"entitlementid"` // entitlement granted by the renewal
+	Price         int       `json:"price" bson:"price"`                 // price charged
+	Created       time.Time `json:"created" bson:"created"`
+}
+
+// Subscription links a subscriber to a pack that is renewed at the end of its term.
+type Subscription struct {
+	ID          bson.ObjectId     `json:"id,omitempty" bson:"_id,omitempty"`            // id of the subscription in the db
+	Msisdn      string            `json:"msisdn" bson:"msisdn"`                         // subscriber line number
+	PackID      string            `json:"packid" bson:"packid"`                         // hex id of the subscribed pack
+	Packcode    string            `json:"packcode" bson:"packcode"`                     // code of the subscribed pack
+	MnoID       int8              `json:"mnoid" bson:"mnoid"`                           // mno owner of the subscribed pack
+	Ownerid     int               `json:"ownerid" bson:"ownerid"`                       // company owner of the pack for resale
+	Price       int               `json:"price" bson:"price"`                           // price the subscriber pays on every renewal
+	PriceNotice *PriceNotice      `json:"pricenotice,omitempty" bson:"pricenotice"`     // pending price change
+	NextRenewal time.Time         `json:"nextrenewal" bson:"nextrenewal"`               // when the next renewal is due
+	Attempts    int               `json:"attempts" bson:"attempts"`                     // failed renewal attempts in a row
+	LastError   string            `json:"lasterror,omitempty" bson:"lasterror"`         // reason of the last failed renewal
+	Renewals    []Renewal         `json:"renewals,omitempty" bson:"renewals,omitempty"` // renewals made
+	State       SubscriptionState `json:"state" bson:"state"`                           // state of the subscription
+	Created     time.Time         `json:"created,omitempty" bson:"created"`
+	Updated     time.Time         `json:"updated,omitempty" bson:"updated"`
+}
+
+// RenewalPolicy contains the rules used to renew subscriptions.
+type RenewalPolicy struct {
+	PriceNotice time.Duration // time the subscribed price is kept after a pack price change
+	RetryBase   time.Duration // wait before retrying a failed renewal the first time
+	RetryMax    time.Duration // longest wait between retries
+	MaxAttempts int           // failed renewals in a row before the subscription is suspended
+}
+
+// NewSubscription creates an active subscription of the given subscriber
+// to the pack, the first renewal is due at the given time.
+func NewSubscription(msisdn string, pack *Pack, nextrenewal time.Time) *Subscription {
+	if pack == nil {
+		return nil
+	}
+	newsubscription := new(Subscription)
+	newsubscription.Msisdn = msisdn
+	newsubscription.PackID = pack.ID.Hex()
+	newsubscription.Packcode = pack.Packcode
+	if pack.Mno != nil {
+		newsubscription.MnoID = pack.Mno.ID
+	}
+	newsubscription.Ownerid = pack.Ownerid
+	newsubscription.Price = pack.Price
+	newsubscription.NextRenewal = nextrenewal
+	newsubscription.Renewals = []Renewal{}
+	newsubscription.State = SubscriptionActive
+	newsubscription.Created = time.Now()
+	newsubscription.Updated = newsubscription.Created
+	return newsubscription
+}
+
+// RetryDelay returns the wait before the given retry attempt, it doubles
+// on every attempt until it reaches RetryMax.
+func (p RenewalPolicy) RetryDelay(attempt int) time.Duration {
+	return backoff(p.RetryBase, p.RetryMax, attempt)
+}
+
+// backoff returns base doubled on every attempt after the first one, max
+// is the longest wait if it is not 0.
+func backoff(base time.Duration, max time.Duration, attempt int) time.Duration {
+	delay := base
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if max > 0 && delay >= max {
+			return max
+		}
+	}
+	if max > 0 && delay > max {
+		return max
+	}
+	return delay
+}
+
+// ApplyPackPrice compares the current pack price with the subscribed
+// one. A different price is noticed first and it is only taken once the
+// notice period of the policy is over. Returns the price to charge.
+func (s *Subscription) ApplyPackPrice(packprice int, now time.Time, policy RenewalPolicy) int {
+	if packprice == s.Price {
+		s.PriceNotice = nil
+		return s.Price
+	}
+	if s.PriceNotice == nil || s.PriceNotice.Price != packprice {
+		s.PriceNotice = &PriceNotice{Price: packprice, Noticed: now}
+		return s.Price
+	}
+	if !now.Before(s.PriceNotice.Noticed.Add(policy.PriceNotice)) {
+		s.Price = packprice
+		s.PriceNotice = nil
+	}
+	return s.Price
+}
+
+// RenewalFailed records a failed renewal, the next attempt is delayed
+// according to the policy and after too many attempts the subscription
+// is suspended.
+func (s *Subscription) RenewalFailed(reason string, now time.Time, policy RenewalPolicy) {
+	s.Attempts++
+	s.LastError = reason
+	s.Updated = now
+	if policy.MaxAttempts > 0 && s.Attempts >= policy.MaxAttempts {
+		s.State = SubscriptionSuspended
+		return
+	}
+	s.NextRenewal = now.Add(policy.RetryDelay(s.Attempts))
+}
+
+// Renewed records a renewal sold by the given order, the next one is due
+// when the granted entitlement expires.
+func (s *Subscription) Renewed(order *Order, entitlement *Entitlement, now time.Time) *Renewal {
+	s.Attempts = 0
+	s.LastError = ""
+	s.NextRenewal = entitlement.Expires
+	s.Updated = now
+	return &Renewal{OrderID: order.ID.Hex(), EntitlementID: entitlement.ID.Hex(),
+		Price: order.Price, Created: now}
+}
@@ -0,0 +1,155 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+// TestNewSubscription tests instances a Subscription from a pack
+func TestNewSubscription(t *testing.T) {
+	// GIVEN a pack and the moment of its first renewal
+	pack := createExpPack()
+	pack.Ownerid = 7
+	nextrenewal := time.Date(2018, time.March, 12, 8, 0, 0, 0, time.UTC)
+
+	// WHEN we need to subscribe a subscriber to the pack
+	result := NewSubscription("573001234567", pack, nextrenewal)
+
+	// THEN system returns an active subscription at the pack price
+	if result == nil {
+		t.Fatalf("Expected a Subscription struct with data but got nil")
+	}
+	if result.State != SubscriptionActive {
+		t.Fatalf("Expected Subscription#State %d but got %d", SubscriptionActive, result.State)
+	}
+	if result.Price != pack.Price {
+		t.Fatalf("Expected Subscription#Price %d but got %d", pack.Price, result.Price)
+	}
+	if result.Ownerid != pack.Ownerid {
+		t.Fatalf("Expected Subscription#Ownerid %d but got %d", pack.Ownerid, result.Ownerid)
+	}
+	if !result.NextRenewal.Equal(nextrenewal) {
+		t.Fatalf("Expected Subscription#NextRenewal %s but got %s", nextrenewal, result.NextRenewal)
+	}
+}
+
+// TestRenewalPolicyRetryDelay tests the exponential backoff of retries
+func TestRenewalPolicyRetryDelay(t *testing.T) {
+	policy := RenewalPolicy{RetryBase: time.Minute, RetryMax: 10 * time.Minute}
+	tests := []struct {
+		name    string
+		attempt int
+		want    time.Duration
+	}{
+		{name: "first attempt", attempt: 1, want: time.Minute},
+		{name: "second attempt", attempt: 2, want: 2 * time.Minute},
+		{name: "fourth attempt", attempt: 4, want: 8 * time.Minute},
+		{name: "capped attempt", attempt: 5, want: 10 * time.Minute},
+		{name: "far attempt", attempt: 60, want: 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.RetryDelay(tt.attempt); got != tt.want {
+				t.Errorf("RenewalPolicy.RetryDelay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestApplyPackPrice tests that the subscribed price is kept until the
+// notice period of a price change is over
+func TestApplyPackPrice(t *testing.T) {
+	policy := RenewalPolicy{PriceNotice: 30 * 24 * time.Hour}
+	now := time.Date(2018, time.March, 1, 0, 0, 0, 0, time.UTC)
+	// GIVEN a subscription at 2500
+	subscription := &Subscription{Price: 2500}
+
+	// WHEN the pack price rises to 3000
+	price := subscription.ApplyPackPrice(3000, now, policy)
+
+	// THEN the change is noticed and the old price is charged
+	if price != 2500 {
+		t.Fatalf("Expected price 2500 but got %d", price)
+	}
+	if subscription.PriceNotice == nil || subscription.PriceNotice.Price != 3000 {
+		t.Fatalf("Expected a price notice of 3000 but got %+v", subscription.PriceNotice)
+	}
+
+	// AND the old price is kept before the notice period is over
+	price = subscription.ApplyPackPrice(3000, now.AddDate(0, 0, 29), policy)
+	if price != 2500 {
+		t.Fatalf("Expected price 2500 within the notice period but got %d", price)
+	}
+
+	// AND the new price is charged once the notice period is over
+	price = subscription.ApplyPackPrice(3000, now.AddDate(0, 0, 30), policy)
+	if price != 3000 || subscription.Price != 3000 {
+		t.Fatalf("Expected price 3000 after the notice period but got %d", price)
+	}
+	if subscription.PriceNotice != nil {
+		t.Fatalf("Expected no price notice but got %+v", subscription.PriceNotice)
+	}
+}
+
+// TestApplyPackPriceChangedAgain tests that a new price change restarts the notice
+func TestApplyPackPriceChangedAgain(t *testing.T) {
+	policy := RenewalPolicy{PriceNotice: 30 * 24 * time.Hour}
+	now := time.Date(2018, time.March, 1, 0, 0, 0, 0, time.UTC)
+	later := now.AddDate(0, 0, 20)
+	subscription := &Subscription{Price: 2500, PriceNotice: &PriceNotice{Price: 3000, Noticed: now}}
+
+	price := subscription.ApplyPackPrice(3500, later, policy)
+
+	if price != 2500 {
+		t.Fatalf("Expected price 2500 but got %d", price)
+	}
+	if subscription.PriceNotice.Price != 3500 || !subscription.PriceNotice.Noticed.Equal(later) {
+		t.Fatalf("Expected a new price notice of 3500 but got %+v", subscription.PriceNotice)
+	}
+}
+
+// TestRenewalFailed tests the retries of a failed renewal until the
+// subscription is suspended
+func TestRenewalFailed(t *testing.T) {
+	policy := RenewalPolicy{RetryBase: time.Minute, RetryMax: time.Hour, MaxAttempts: 3}
+	now := time.Date(2018, time.March, 1, 0, 0, 0, 0, time.UTC)
+	subscription := &Subscription{State: SubscriptionActive}
+
+	subscription.RenewalFailed("pack is not active", now, policy)
+	if subscription.Attempts != 1 || !subscription.NextRenewal.Equal(now.Add(time.Minute)) {
+		t.Fatalf("Expected first retry in 1 minute but got %+v", subscription)
+	}
+
+	subscription.RenewalFailed("pack is not active", now, policy)
+	if !subscription.NextRenewal.Equal(now.Add(2 * time.Minute)) {
+		t.Fatalf("Expected second retry in 2 minutes but got %s", subscription.NextRenewal)
+	}
+
+	subscription.RenewalFailed("pack is not active", now, policy)
+	if subscription.State != SubscriptionSuspended {
+		t.Fatalf("Expected subscription to be suspended but state was %d", subscription.State)
+	}
+	if subscription.LastError != "pack is not active" {
+		t.Fatalf("Expected last error to be recorded but got %s", subscription.LastError)
+	}
+}
+
+// TestRenewed tests that a renewal resets the retries
+func TestRenewed(t *testing.T) {
+	now := time.Date(2018, time.March, 1, 0, 0, 0, 0, time.UTC)
+	subscription := &Subscription{State: SubscriptionActive, Attempts: 2, LastError: "boom"}
+	entitlement := &Entitlement{Expires: now.AddDate(0, 0, 7)}
+	order := &Order{Price: 2500}
+
+	renewal := subscription.Renewed(order, entitlement, now)
+
+	if subscription.Attempts != 0 || subscription.LastError != "" {
+		t.Fatalf("Expected retries to be reset but got %+v", subscription)
+	}
+	if !subscription.NextRenewal.Equal(entitlement.Expires) {
+		t.Fatalf("Expected next renewal at %s but got %s", entitlement.Expires, subscription.NextRenewal)
+	}
+	if renewal == nil || renewal.Price != 2500 {
+		t.Fatalf("Expected a renewal at 2500 but got %+v", renewal)
+	}
+}
@@ -0,0 +1,55 @@
+package model
+
+// Tenant contains the owner, the mno or the sales channel a caller acts
+// for, packs of other tenants are not visible to the caller.
+type Tenant struct {
+	Ownerid int    `json:"ownerid"` // owner the caller acts for, 0 if any
+	MnoID   int8   `json:"mnoid"`   // mno the caller acts for, 0 if any
+	Channel string `json:"channel"` // channel the caller sells in, empty if any
+	Admin   bool   `json:"admin"`   // platform admin, it acts for every tenant
+}
+
+// IsEmpty returns true if the tenant does not act for any owner, mno or
+// channel.
+func (t *Tenant) IsEmpty() bool {
+	return t == nil || (!t.Admin && t.Ownerid == 0 && t.MnoID == 0 && t.Channel == "")
+}
+
+// Owns returns true if the pack belongs to the tenant.
+func (t *Tenant) Owns(pack *Pack) bool {
+	if t.IsEmpty() || pack == nil {
+		return false
+	}
+	if t.Admin {
+		return true
+	}
+	if t.Ownerid != 0 && pack.Ownerid != t.Ownerid {
+		return false
+	}
+	if t.MnoID != 0 && (pack.Mno == nil || pack.Mno.ID != t.MnoID) {
+		return false
+	}
+	if t.Channel != "" && !pack.AvailableIn(t.Channel, "") {
+		return false
+	}
+	return true
+}
+
+// OwnsSale returns true if a sale of a pack of the given owner in the
+// given mno belongs to the tenant. Sales do not keep the channel they
+// were made in, so a tenant that only sells in a channel owns none.
+func (t *Tenant) OwnsSale(ownerid int, mnoid int8) bool {
+	if t.IsEmpty() {
+		return false
+	}
+	if t.Admin {
+		return true
+	}
+	if t.Ownerid == 0 && t.MnoID == 0 {
+		return false
+	}
+	if t.Ownerid != 0 && ownerid != t.Ownerid {
+		return false
+	}
+	return t.MnoID == 0 || mnoid == t.MnoID
+}
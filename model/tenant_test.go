@@ -0,0 +1,60 @@
+package model
+
+import "testing"
+
+// TestTenantOwns tests which packs belong to a tenant
+func TestTenantOwns(t *testing.T) {
+	pack := createExpPack()
+	pack.Ownerid = 7
+	pack.Mno = &Mno{ID: 2, Name: "Movil"}
+	tests := []struct {
+		name   string
+		tenant *Tenant
+		want   bool
+	}{
+		{name: "owner", tenant: &Tenant{Ownerid: 7}, want: true},
+		{name: "other owner", tenant: &Tenant{Ownerid: 8}, want: false},
+		{name: "mno", tenant: &Tenant{MnoID: 2}, want: true},
+		{name: "other mno", tenant: &Tenant{MnoID: 5}, want: false},
+		{name: "owner in mno", tenant: &Tenant{Ownerid: 7, MnoID: 2}, want: true},
+		{name: "owner in other mno", tenant: &Tenant{Ownerid: 7, MnoID: 5}, want: false},
+		{name: "channel of every pack", tenant: &Tenant{Channel: "retail"}, want: true},
+		{name: "platform admin", tenant: &Tenant{Admin: true}, want: true},
+		{name: "empty tenant", tenant: &Tenant{}, want: false},
+		{name: "no tenant", tenant: nil, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.tenant.Owns(pack); got != tt.want {
+				t.Errorf("Tenant.Owns() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestTenantOwnsSale tests which sales belong to a tenant
+func TestTenantOwnsSale(t *testing.T) {
+	tests := []struct {
+		name   string
+		tenant *Tenant
+		want   bool
+	}{
+		{name: "owner", tenant: &Tenant{Ownerid: 7}, want: true},
+		{name: "other owner", tenant: &Tenant{Ownerid: 8}, want: false},
+		{name: "mno", tenant: &Tenant{MnoID: 2}, want: true},
+		{name: "other mno", tenant: &Tenant{MnoID: 5}, want: false},
+		{name: "owner in other mno", tenant: &Tenant{Ownerid: 7, MnoID: 5}, want: false},
+		{name: "owner selling in a channel", tenant: &Tenant{Ownerid: 7, Channel: "retail"}, want: true},
+		{name: "only a channel", tenant: &Tenant{Channel: "retail"}, want: false},
+		{name: "platform admin", tenant: &Tenant{Admin: true}, want: true},
+		{name: "empty tenant", tenant: &Tenant{}, want: false},
+		{name: "no tenant", tenant: nil, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.tenant.OwnsSale(7, 2); got != tt.want {
+				t.Errorf("Tenant.OwnsSale() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
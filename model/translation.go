@@ -0,0 +1,85 @@
+package model
+
+import "strings"
+
+// Translation contains the name, description and keywords of a pack in
+// a locale.
+type Translation struct {
+	Locale string `json:"locale" bson:"locale"` // language of the translation. e.g. en, es
+	Name   string `json:"name" bson:"name"`     // pack name
+	Desc   string `json:"desc" bson:"desc"`     // pack description
+	Kwds   string `json:"kwds" bson:"kwds"`     // keywords for the pack searching
+}
+
+// NormalizeLocale returns the language of the given locale in lower case.
+// e.g. es-CO gives es.
+func NormalizeLocale(locale string) string {
+	locale = strings.TrimSpace(locale)
+	if i := strings.IndexAny(locale, "-_"); i > 0 {
+		locale = locale[:i]
+	}
+	return strings.ToLower(locale)
+}
+
+// IsValid returns true if the translation has a locale and a name.
+func (t *Translation) IsValid() bool {
+	return t != nil && NormalizeLocale(t.Locale) != "" && t.Name != ""
+}
+
+// Translation returns the translation of the pack in the given locale,
+// nil if there is none.
+func (p *Pack) Translation(locale string) *Translation {
+	locale = NormalizeLocale(locale)
+	for i := range p.Translations {
+		if p.Translations[i].Locale == locale {
+			return &p.Translations[i]
+		}
+	}
+	return nil
+}
+
+// SetTranslation adds the given translation to the pack or replaces the
+// existing one of its locale.
+func (p *Pack) SetTranslation(translation Translation) {
+	translation.Locale = NormalizeLocale(translation.Locale)
+	if existing := p.Translation(translation.Locale); existing != nil {
+		*existing = translation
+		return
+	}
+	p.Translations = append(p.Translations, translation)
+}
+
+// RemoveTranslation removes the translation of the given locale, it
+// returns false if the pack had none.
+func (p *Pack) RemoveTranslation(locale string) bool {
+	locale = NormalizeLocale(locale)
+	for i := range p.Translations {
+		if p.Translations[i].Locale == locale {
+			p.Translations = append(p.Translations[:i], p.Translations[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Localize returns a copy of the pack with the name, description and
+// keywords of the first given locale it has. The pack own texts are in
+// the default locale and they are used if there is no translation.
+func (p *Pack) Localize(locales []string, defaultlocale string) *Pack {
+	localized := *p
+	localized.Locale = NormalizeLocale(defaultlocale)
+	for _, locale := range locales {
+		locale = NormalizeLocale(locale)
+		if locale == localized.Locale {
+			return &localized
+		}
+		if translation := p.Translation(locale); translation != nil {
+			localized.Locale = translation.Locale
+			localized.Name = translation.Name
+			localized.Desc = translation.Desc
+			localized.Kwds = translation.Kwds
+			return &localized
+		}
+	}
+	return &localized
+}
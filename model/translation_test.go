@@ -0,0 +1,73 @@
+package model
+
+import "testing"
+
+// TestSetTranslation tests translations are added and replaced by locale
+func TestSetTranslation(t *testing.T) {
+	// GIVEN a pack in spanish
+	pack := createExpPack()
+
+	// WHEN we add an english translation and then replace it
+	pack.SetTranslation(Translation{Locale: "en-US", Name: "Whatsapp weekend", Desc: "Chat all weekend", Kwds: "chat weekend"})
+	pack.SetTranslation(Translation{Locale: "EN", Name: "Weekend Whatsapp", Desc: "Chat all weekend", Kwds: "chat weekend"})
+
+	// THEN the pack has one english translation with the last name
+	if len(pack.Translations) != 1 {
+		t.Fatalf("Expected 1 translation but got %d", len(pack.Translations))
+	}
+	if translation := pack.Translation("en"); translation == nil || translation.Name != "Weekend Whatsapp" {
+		t.Fatalf("Expected the english translation to be replaced but got %+v", translation)
+	}
+	if !pack.RemoveTranslation("en") || len(pack.Translations) != 0 {
+		t.Fatalf("Expected the english translation to be removed but got %+v", pack.Translations)
+	}
+	if pack.RemoveTranslation("en") {
+		t.Fatalf("Expected no translation to remove")
+	}
+}
+
+// TestLocalize tests the texts served for every asked locale
+func TestLocalize(t *testing.T) {
+	pack := createExpPack()
+	pack.Translations = []Translation{
+		{Locale: "en", Name: "Weekend Whatsapp", Desc: "Chat all weekend", Kwds: "chat weekend"},
+		{Locale: "pt", Name: "Whatsapp fim de semana", Desc: "Converse todo o fim de semana", Kwds: "chat fim semana"},
+	}
+	tests := []struct {
+		name       string
+		locales    []string
+		wantLocale string
+		wantName   string
+	}{
+		{name: "translated", locales: []string{"en"}, wantLocale: "en", wantName: "Weekend Whatsapp"},
+		{name: "region", locales: []string{"pt-BR"}, wantLocale: "pt", wantName: "Whatsapp fim de semana"},
+		{name: "first with translation", locales: []string{"fr", "pt", "en"}, wantLocale: "pt", wantName: "Whatsapp fim de semana"},
+		{name: "default before translation", locales: []string{"es", "en"}, wantLocale: "es", wantName: pack.Name},
+		{name: "no translation", locales: []string{"fr"}, wantLocale: "es", wantName: pack.Name},
+		{name: "no locale", locales: nil, wantLocale: "es", wantName: pack.Name},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pack.Localize(tt.locales, "es")
+			if got.Locale != tt.wantLocale || got.Name != tt.wantName {
+				t.Errorf("Pack.Localize(%v) = %s %q, want %s %q", tt.locales, got.Locale, got.Name, tt.wantLocale, tt.wantName)
+			}
+		})
+	}
+	if pack.Locale != "" {
+		t.Fatalf("Expected the pack to be unchanged but its locale is %s", pack.Locale)
+	}
+}
+
+// TestTranslationIsValid tests a translation needs a locale and a name
+func TestTranslationIsValid(t *testing.T) {
+	if (&Translation{Locale: "en"}).IsValid() {
+		t.Fatalf("Expected a translation without name to be invalid")
+	}
+	if (&Translation{Name: "Weekend Whatsapp"}).IsValid() {
+		t.Fatalf("Expected a translation without locale to be invalid")
+	}
+	if !(&Translation{Locale: "en", Name: "Weekend Whatsapp"}).IsValid() {
+		t.Fatalf("Expected the translation to be valid")
+	}
+}
@@ -0,0 +1,232 @@
+package model
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// webhookSecretPrefix starts every generated webhook secret.
+const webhookSecretPrefix = "whsec_"
+
+// MinWebhookSecret is the length of the shortest secret a partner can give.
+const MinWebhookSecret = 16
+
+// MaxWebhookAttemptsKept is the number of attempts kept in the log of a
+// delivery, the oldest ones are dropped.
+const MaxWebhookAttemptsKept = 20
+
+// Headers of the webhook requests
+const (
+	WebhookSignatureHeader = "X-Pack-Signature" // t=<unix time>,v1=<hex hmac-sha256 of "<t>.<body>">
+	WebhookEventHeader     = "X-Pack-Event"     // type of the event
+	WebhookDeliveryHeader  = "X-Pack-Delivery"  // id of the delivery, the same in every attempt
+)
+
+// Status of webhook deliveries
+const (
+	DeliveryPending   = "pending"   // waiting for its next attempt
+	DeliveryDelivered = "delivered" // the target answered with a 2xx status
+	DeliveryDead      = "dead"      // it failed too many times, it waits for a manual redelivery
+)
+
+// Webhook contains a target a partner wants the pack events posted to.
+type Webhook struct {
+	ID      bson.ObjectId `json:"id,omitempty" bson:"_id,omitempty"` // id of the webhook in the db
+	Ownerid int           `json:"ownerid" bson:"ownerid"`            // owner whose packs are notified, 0 for every pack
+	URL     string        `json:"url" bson:"url"`                    // http or https address the events are posted to
+	Events  []string      `json:"events" bson:"events"`              // types of the events posted, empty for every type
+	Secret  string        `json:"-" bson:"secret"`                   // key of the signatures of the requests
+	Created time.Time     `json:"created,omitempty" bson:"created"`
+}
+
+// WebhookAttempt contains an attempt to deliver an event to a webhook.
+type WebhookAttempt struct {
+	At       time.Time `json:"at" bson:"at"`
+	Status   int       `json:"status" bson:"status"`                   // http status of the answer, 0 if there was none
+	Error    string    `json:"error,omitempty" bson:"error,omitempty"` // why the attempt failed
+	Duration int       `json:"duration" bson:"duration"`               // milliseconds the target took to answer
+}
+
+// WebhookDelivery contains an event to post to a webhook and the log of
+// its attempts. The payload is kept so every attempt sends the same body.
+type WebhookDelivery struct {
+	ID          bson.ObjectId    `json:"id,omitempty" bson:"_id,omitempty"` // id of the delivery in the db
+	WebhookID   string           `json:"webhookid" bson:"webhookid"`        // hex id of the webhook
+	EventID     string           `json:"eventid" bson:"eventid"`            // hex id of the event
+	EventType   string           `json:"eventtype" bson:"eventtype"`        // type of the event
+	PackID      string           `json:"packid" bson:"packid"`              // hex id of the changed pack
+	Payload     string           `json:"payload" bson:"payload"`            // json body posted
+	State       string           `json:"state" bson:"state"`                // pending, delivered or dead
+	Tries       int              `json:"tries" bson:"tries"`                // failed attempts in a row
+	NextAttempt time.Time        `json:"nextattempt" bson:"nextattempt"`    // when the next attempt is due
+	Attempts    []WebhookAttempt `json:"attempts" bson:"attempts"`          // last attempts, the newest last
+	Created     time.Time        `json:"created" bson:"created"`
+	Updated     time.Time        `json:"updated" bson:"updated"`
+	Delivered   time.Time        `json:"delivered,omitempty" bson:"delivered,omitempty"`
+}
+
+// WebhookPolicy contains the rules used to retry webhook deliveries.
+type WebhookPolicy struct {
+	Timeout     time.Duration // wait for the answer of the target
+	RetryBase   time.Duration // wait before retrying a failed delivery the first time
+	RetryMax    time.Duration // longest wait between retries
+	MaxAttempts int           // failed attempts in a row before the delivery is dead
+	// AllowPrivateTargets lets webhooks post to loopback, private and
+	// link-local addresses, only for local tests.
+	AllowPrivateTargets bool
+}
+
+// NewWebhook creates a Webhook with the given parameters, the event types
+// are not repeated.
+func NewWebhook(params map[string]interface{}) *Webhook {
+	newwebhook := new(Webhook)
+	newwebhook.Ownerid, _ = params["ownerid"].(int)
+	webhookurl, _ := params["url"].(string)
+	newwebhook.URL = strings.TrimSpace(webhookurl)
+	newwebhook.Events = []string{}
+	seen := map[string]bool{}
+	for _, eventtype := range stringList(params["events"]) {
+		if !seen[eventtype] {
+			seen[eventtype] = true
+			newwebhook.Events = append(newwebhook.Events, eventtype)
+		}
+	}
+	newwebhook.Secret, _ = params["secret"].(string)
+	return newwebhook
+}
+
+// NewWebhookSecret returns a random secret for a webhook.
+func NewWebhookSecret() (string, error) {
+	secret := make([]byte, 24)
+	_, err := rand.Read(secret)
+	if err != nil {
+		return "", err
+	}
+	return webhookSecretPrefix + hex.EncodeToString(secret), nil
+}
+
+// InvalidField returns the name of the first field of the webhook with a
+// wrong value, empty if every value is right. An empty secret is right,
+// one is generated.
+func (w *Webhook) InvalidField() string {
+	target, err := url.Parse(w.URL)
+	switch {
+	case w.Ownerid < 0:
+		return "ownerid"
+	case err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "":
+		return "url"
+	case w.Secret != "" && len(w.Secret) < MinWebhookSecret:
+		return "secret"
+	}
+	for _, eventtype := range w.Events {
+		if !KnownEventType(eventtype) {
+			return "events"
+		}
+	}
+	return ""
+}
+
+// PrivateAddress returns true if the ip is a loopback, private, link-local
+// or unspecified address, where webhooks must not post unless the policy
+// allows it.
+func PrivateAddress(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsUnspecified()
+}
+
+// Matches returns true if the event is posted to the webhook: its type is
+// one of the types of the webhook and the pack belongs to its owner.
+func (w *Webhook) Matches(event *Event) bool {
+	if event == nil {
+		return false
+	}
+	if w.Ownerid != 0 && (event.Pack == nil || event.Pack.Ownerid != w.Ownerid) {
+		return false
+	}
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, eventtype := range w.Events {
+		if eventtype == event.Type {
+			return true
+		}
+	}
+	return false
+}
+
+// NewWebhookDelivery creates a pending delivery of the event to the
+// webhook with the given json payload, due now.
+func NewWebhookDelivery(webhook *Webhook, event *Event, payload []byte, now time.Time) *WebhookDelivery {
+	return &WebhookDelivery{
+		WebhookID:   webhook.ID.Hex(),
+		EventID:     event.ID.Hex(),
+		EventType:   event.Type,
+		PackID:      event.PackID,
+		Payload:     string(payload),
+		State:       DeliveryPending,
+		NextAttempt: now,
+		Attempts:    []WebhookAttempt{},
+		Created:     now,
+		Updated:     now,
+	}
+}
+
+// Attempted records an attempt of the delivery. A 2xx status delivers it,
+// other answers are retried later according to the policy and after too
+// many failures in a row the delivery is dead.
+func (d *WebhookDelivery) Attempted(attempt WebhookAttempt, policy WebhookPolicy) {
+	d.Attempts = append(d.Attempts, attempt)
+	if len(d.Attempts) > MaxWebhookAttemptsKept {
+		d.Attempts = d.Attempts[len(d.Attempts)-MaxWebhookAttemptsKept:]
+	}
+	d.Updated = attempt.At
+	if attempt.Error == "" && attempt.Status >= 200 && attempt.Status < 300 {
+		d.State = DeliveryDelivered
+		d.Tries = 0
+		d.Delivered = attempt.At
+		return
+	}
+	d.Tries++
+	if policy.MaxAttempts > 0 && d.Tries >= policy.MaxAttempts {
+		d.State = DeliveryDead
+		return
+	}
+	d.State = DeliveryPending
+	d.NextAttempt = attempt.At.Add(policy.RetryDelay(d.Tries))
+}
+
+// Redeliver makes the delivery pending again and due now, with its tries
+// back to 0.
+func (d *WebhookDelivery) Redeliver(now time.Time) {
+	d.State = DeliveryPending
+	d.Tries = 0
+	d.NextAttempt = now
+	d.Updated = now
+}
+
+// RetryDelay returns the wait before the given retry attempt, it doubles
+// on every attempt until it reaches RetryMax.
+func (p WebhookPolicy) RetryDelay(attempt int) time.Duration {
+	return backoff(p.RetryBase, p.RetryMax, attempt)
+}
+
+// SignWebhook returns the value of the signature header of a request with
+// the given body sent at the given unix time. The signature is the hex
+// hmac-sha256 of the time, a dot and the body, so a request cannot be
+// replayed with another time.
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	t := strconv.FormatInt(timestamp, 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(t + "."))
+	mac.Write(body)
+	return "t=" + t + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,131 @@
+package model
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestWebhookInvalidField tests the validation of the webhook data
+func TestWebhookInvalidField(t *testing.T) {
+	tests := map[string]struct {
+		params map[string]interface{}
+		field  string
+	}{
+		"valid":         {map[string]interface{}{"url": " https://partner.example/hooks ", "ownerid": 7}, ""},
+		"no url":        {map[string]interface{}{}, "url"},
+		"ftp url":       {map[string]interface{}{"url": "ftp://partner.example"}, "url"},
+		"no host":       {map[string]interface{}{"url": "http:///hooks"}, "url"},
+		"short secret":  {map[string]interface{}{"url": "http://localhost:9000", "secret": "abc"}, "secret"},
+		"unknown event": {map[string]interface{}{"url": "http://localhost:9000", "events": []interface{}{"PackSold"}}, "events"},
+		"bad owner":     {map[string]interface{}{"url": "http://localhost:9000", "ownerid": -1}, "ownerid"},
+	}
+	for name, test := range tests {
+		webhook := NewWebhook(test.params)
+		if field := webhook.InvalidField(); field != test.field {
+			t.Errorf("%s: expected invalid field %q but got %q", name, test.field, field)
+		}
+	}
+}
+
+// TestPrivateAddress tests the addresses webhooks must not post to
+func TestPrivateAddress(t *testing.T) {
+	tests := map[string]bool{
+		"127.0.0.1": true, "::1": true, "10.1.2.3": true, "172.16.0.9": true, "192.168.1.1": true,
+		"169.254.169.254": true, "fe80::1": true, "fd00::1": true, "0.0.0.0": true, "::ffff:127.0.0.1": true,
+		"8.8.8.8": false, "2001:4860:4860::8888": false,
+	}
+	for address, private := range tests {
+		if PrivateAddress(net.ParseIP(address)) != private {
+			t.Errorf("%s: expected private %t", address, private)
+		}
+	}
+}
+
+// TestNewWebhookEvents tests the event types of a webhook are not repeated
+func TestNewWebhookEvents(t *testing.T) {
+	webhook := NewWebhook(map[string]interface{}{"events": []interface{}{EventPackPriceChanged, " ",
+		EventStockMoved, EventPackPriceChanged}})
+
+	if len(webhook.Events) != 2 || webhook.Events[0] != EventPackPriceChanged || webhook.Events[1] != EventStockMoved {
+		t.Errorf("Expected price and stock events but got %v", webhook.Events)
+	}
+}
+
+// TestWebhookMatches tests the events posted to a webhook
+func TestWebhookMatches(t *testing.T) {
+	mine := &Event{Type: EventPackPriceChanged, Pack: &Pack{Ownerid: 7}}
+	other := &Event{Type: EventPackPriceChanged, Pack: &Pack{Ownerid: 8}}
+	stock := &Event{Type: EventStockMoved, Pack: &Pack{Ownerid: 7}}
+	unknown := &Event{Type: EventPackPriceChanged}
+
+	owned := &Webhook{Ownerid: 7, Events: []string{EventPackPriceChanged}}
+	every := &Webhook{}
+
+	if !owned.Matches(mine) || owned.Matches(other) || owned.Matches(stock) || owned.Matches(unknown) {
+		t.Errorf("Expected the owner webhook to get only the price changes of its packs")
+	}
+	if !every.Matches(mine) || !every.Matches(other) || !every.Matches(stock) || !every.Matches(unknown) {
+		t.Errorf("Expected the webhook of every owner and type to get every event")
+	}
+}
+
+// TestDeliveryAttempted tests the backoff and the dead letter of a
+// delivery that keeps failing
+func TestDeliveryAttempted(t *testing.T) {
+	policy := WebhookPolicy{RetryBase: time.Minute, RetryMax: 3 * time.Minute, MaxAttempts: 4}
+	now := time.Date(2021, time.March, 1, 10, 0, 0, 0, time.UTC)
+	delivery := &WebhookDelivery{State: DeliveryPending}
+
+	waits := []time.Duration{}
+	for i := 0; i < 3; i++ {
+		delivery.Attempted(WebhookAttempt{At: now, Status: 500, Error: "target answered 500"}, policy)
+		waits = append(waits, delivery.NextAttempt.Sub(now))
+	}
+	if delivery.State != DeliveryPending || waits[0] != time.Minute || waits[1] != 2*time.Minute ||
+		waits[2] != 3*time.Minute {
+		t.Fatalf("Expected waits of 1, 2 and 3 minutes but got %v in state %s", waits, delivery.State)
+	}
+	delivery.Attempted(WebhookAttempt{At: now, Error: "connection refused"}, policy)
+	if delivery.State != DeliveryDead || delivery.Tries != 4 || len(delivery.Attempts) != 4 {
+		t.Fatalf("Expected a dead delivery after 4 tries but got %s after %d", delivery.State, delivery.Tries)
+	}
+
+	delivery.Redeliver(now)
+	delivery.Attempted(WebhookAttempt{At: now, Status: 204}, policy)
+	if delivery.State != DeliveryDelivered || delivery.Tries != 0 || !delivery.Delivered.Equal(now) {
+		t.Errorf("Expected a delivered delivery but got %+v", delivery)
+	}
+}
+
+// TestDeliveryAttemptsKept tests only the last attempts are kept
+func TestDeliveryAttemptsKept(t *testing.T) {
+	delivery := &WebhookDelivery{}
+	start := time.Date(2021, time.March, 1, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < MaxWebhookAttemptsKept+5; i++ {
+		delivery.Attempted(WebhookAttempt{At: start.Add(time.Duration(i) * time.Minute), Status: 500}, WebhookPolicy{})
+	}
+
+	if len(delivery.Attempts) != MaxWebhookAttemptsKept || !delivery.Attempts[0].At.Equal(start.Add(5*time.Minute)) {
+		t.Errorf("Expected the last %d attempts but got %d from %s", MaxWebhookAttemptsKept,
+			len(delivery.Attempts), delivery.Attempts[0].At)
+	}
+}
+
+// TestSignWebhook tests the signature of a webhook request
+func TestSignWebhook(t *testing.T) {
+	signature := SignWebhook("whsec_test", 1614592800, []byte(`{"type":"StockMoved"}`))
+
+	if !strings.HasPrefix(signature, "t=1614592800,v1=") || len(signature) != len("t=1614592800,v1=")+64 {
+		t.Fatalf("Expected a hex sha256 signature of the time but got %s", signature)
+	}
+	if SignWebhook("whsec_other", 1614592800, []byte(`{"type":"StockMoved"}`)) == signature ||
+		SignWebhook("whsec_test", 1614592801, []byte(`{"type":"StockMoved"}`)) == signature {
+		t.Errorf("Expected other secrets and times to sign differently")
+	}
+	secret, err := NewWebhookSecret()
+	if err != nil || !strings.HasPrefix(secret, "whsec_") || len(secret) < MinWebhookSecret {
+		t.Errorf("Expected a whsec_ secret but got %q: %v", secret, err)
+	}
+}
@@ -0,0 +1,125 @@
+package model
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"io"
+	"strconv"
+)
+
+// xlsxParts contains the parts of a workbook with a sheet, the sheet is
+// written row by row after them.
+var xlsxParts = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/workbook.xml", xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="packs" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+	{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+// xlsxWriter writes a workbook of a sheet row by row, nothing but the
+// current row is kept in memory. Cells are text unless their column is
+// numeric.
+type xlsxWriter struct {
+	zip     *zip.Writer
+	sheet   *bufio.Writer
+	numeric map[int]bool // columns whose values are numbers
+	rows    int
+	err     error
+}
+
+// newXLSXWriter creates a writer of a workbook, numeric contains the
+// indexes of the columns whose values are numbers.
+func newXLSXWriter(w io.Writer, numeric map[int]bool) *xlsxWriter {
+	x := &xlsxWriter{zip: zip.NewWriter(w), numeric: numeric}
+	for _, part := range xlsxParts {
+		x.part(part.name, part.content)
+	}
+	sheet, err := x.zip.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		x.err = err
+		return x
+	}
+	x.sheet = bufio.NewWriter(sheet)
+	x.write(xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	return x
+}
+
+// part writes a part of the workbook.
+func (x *xlsxWriter) part(name string, content string) {
+	if x.err != nil {
+		return
+	}
+	part, err := x.zip.Create(name)
+	if err == nil {
+		_, err = io.WriteString(part, content)
+	}
+	x.err = err
+}
+
+// write writes the given xml to the sheet.
+func (x *xlsxWriter) write(content string) {
+	if x.err == nil {
+		_, x.err = x.sheet.WriteString(content)
+	}
+}
+
+// WriteRow writes a row of the sheet.
+func (x *xlsxWriter) WriteRow(values []string) error {
+	x.rows++
+	row := strconv.Itoa(x.rows)
+	x.write(`<row r="` + row + `">`)
+	for i, value := range values {
+		if value == "" {
+			continue
+		}
+		ref := xlsxColumn(i) + row
+		if _, err := strconv.ParseFloat(value, 64); err == nil && x.numeric[i] && x.rows > 1 {
+			x.write(`<c r="` + ref + `"><v>` + value + `</v></c>`)
+			continue
+		}
+		x.write(`<c r="` + ref + `" t="inlineStr"><is><t xml:space="preserve">`)
+		if x.err == nil {
+			x.err = xml.EscapeText(x.sheet, []byte(value))
+		}
+		x.write(`</t></is></c>`)
+	}
+	x.write(`</row>`)
+	return x.err
+}
+
+// Close ends the sheet and the workbook.
+func (x *xlsxWriter) Close() error {
+	x.write(`</sheetData></worksheet>`)
+	if x.err == nil {
+		x.err = x.sheet.Flush()
+	}
+	err := x.zip.Close()
+	if x.err != nil {
+		return x.err
+	}
+	return err
+}
+
+// xlsxColumn returns the name of the column of the given index, A for 0.
+func xlsxColumn(index int) string {
+	name := ""
+	for index++; index > 0; index = (index - 1) / 26 {
+		name = string(rune('A'+(index-1)%26)) + name
+	}
+	return name
+}
@@ -0,0 +1,2240 @@
+// pack.v1 is the grpc api of the pack catalog for internal services, it
+// serves the operations of the pack service with the same roles, tenants
+// and errors as the graphql and rest apis.
+//
+// The go code is generated with protoc-gen-go and protoc-gen-go-grpc:
+// protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative packpb/pack.proto
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: packpb/pack.proto
+
+package packpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// PackState is the state of a pack, only active packs are sold.
+type PackState int32
+
+const (
+	PackState_PACK_STATE_INACTIVE PackState = 0
+	PackState_PACK_STATE_ACTIVE   PackState = 1
+)
+
+// Enum value maps for PackState.
+var (
+	PackState_name = map[int32]string{
+		0: "PACK_STATE_INACTIVE",
+		1: "PACK_STATE_ACTIVE",
+	}
+	PackState_value = map[string]int32{
+		"PACK_STATE_INACTIVE": 0,
+		"PACK_STATE_ACTIVE":   1,
+	}
+)
+
+func (x PackState) Enum() *PackState {
+	p := new(PackState)
+	*p = x
+	return p
+}
+
+func (x PackState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PackState) Descriptor() protoreflect.EnumDescriptor {
+	return file_packpb_pack_proto_enumTypes[0].Descriptor()
+}
+
+func (PackState) Type() protoreflect.EnumType {
+	return &file_packpb_pack_proto_enumTypes[0]
+}
+
+func (x PackState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PackState.Descriptor instead.
+func (PackState) EnumDescriptor() ([]byte, []int) {
+	return file_packpb_pack_proto_rawDescGZIP(), []int{0}
+}
+
+// Pack contains the data of a pack of the catalog.
+type Pack struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProdId        string                 `protobuf:"bytes,2,opt,name=prod_id,json=prodId,proto3" json:"prod_id,omitempty"` // internal mobile network provider package id
+	PackCode      string                 `protobuf:"bytes,3,opt,name=pack_code,json=packCode,proto3" json:"pack_code,omitempty"`
+	Name          string                 `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	Desc          string                 `protobuf:"bytes,5,opt,name=desc,proto3" json:"desc,omitempty"`
+	ImageUrl      string                 `protobuf:"bytes,6,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
+	Keywords      string                 `protobuf:"bytes,7,opt,name=keywords,proto3" json:"keywords,omitempty"`
+	Price         int64                  `protobuf:"varint,8,opt,name=price,proto3" json:"price,omitempty"`
+	Stock         int64                  `protobuf:"varint,9,opt,name=stock,proto3" json:"stock,omitempty"`
+	OwnerId       int64                  `protobuf:"varint,10,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"` // the company owner of the pack for resale
+	Created       *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created,proto3" json:"created,omitempty"`
+	Updated       *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=updated,proto3" json:"updated,omitempty"`
+	Version       int64                  `protobuf:"varint,13,opt,name=version,proto3" json:"version,omitempty"` // revision of the pack, every change increases it
+	Type          *PackType              `protobuf:"bytes,14,opt,name=type,proto3" json:"type,omitempty"`
+	Mno           *Mno                   `protobuf:"bytes,15,opt,name=mno,proto3" json:"mno,omitempty"`
+	Term          *Term                  `protobuf:"bytes,16,opt,name=term,proto3" json:"term,omitempty"`
+	Currency      *Currency              `protobuf:"bytes,17,opt,name=currency,proto3" json:"currency,omitempty"`
+	State         PackState              `protobuf:"varint,18,opt,name=state,proto3,enum=pack.v1.PackState" json:"state,omitempty"`
+	Resources     []*Resource            `protobuf:"bytes,19,rep,name=resources,proto3" json:"resources,omitempty"`
+	Components    []string               `protobuf:"bytes,20,rep,name=components,proto3" json:"components,omitempty"` // ids of the packs of a bundle
+	Rules         []*EligibilityRule     `protobuf:"bytes,21,rep,name=rules,proto3" json:"rules,omitempty"`
+	Channels      []string               `protobuf:"bytes,22,rep,name=channels,proto3" json:"channels,omitempty"` // channels where the pack is sold, empty for every channel
+	Regions       []string               `protobuf:"bytes,23,rep,name=regions,proto3" json:"regions,omitempty"`   // regions where the pack is sold, empty for every region
+	Translations  []*Translation         `protobuf:"bytes,24,rep,name=translations,proto3" json:"translations,omitempty"`
+	Locale        string                 `protobuf:"bytes,25,opt,name=locale,proto3" json:"locale,omitempty"` // locale of the name, description and keywords
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Pack) Reset() {
+	*x = Pack{}
+	mi := &file_packpb_pack_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Pack) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Pack) ProtoMessage() {}
+
+func (x *Pack) ProtoReflect() protoreflect.Message {
+	mi := &file_packpb_pack_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Pack.ProtoReflect.Descriptor instead.
+func (*Pack) Descriptor() ([]byte, []int) {
+	return file_packpb_pack_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Pack) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Pack) GetProdId() string {
+	if x != nil {
+		return x.ProdId
+	}
+	return ""
+}
+
+func (x *Pack) GetPackCode() string {
+	if x != nil {
+		return x.PackCode
+	}
+	return ""
+}
+
+func (x *Pack) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Pack) GetDesc() string {
+	if x != nil {
+		return x.Desc
+	}
+	return ""
+}
+
+func (x *Pack) GetImageUrl() string {
+	if x != nil {
+		return x.ImageUrl
+	}
+	return ""
+}
+
+func (x *Pack) GetKeywords() string {
+	if x != nil {
+		return x.Keywords
+	}
+	return ""
+}
+
+func (x *Pack) GetPrice() int64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Pack) GetStock() int64 {
+	if x != nil {
+		return x.Stock
+	}
+	return 0
+}
+
+func (x *Pack) GetOwnerId() int64 {
+	if x != nil {
+		return x.OwnerId
+	}
+	return 0
+}
+
+func (x *Pack) GetCreated() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Created
+	}
+	return nil
+}
+
+func (x *Pack) GetUpdated() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Updated
+	}
+	return nil
+}
+
+func (x *Pack) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Pack) GetType() *PackType {
+	if x != nil {
+		return x.Type
+	}
+	return nil
+}
+
+func (x *Pack) GetMno() *Mno {
+	if x != nil {
+		return x.Mno
+	}
+	return nil
+}
+
+func (x *Pack) GetTerm() *Term {
+	if x != nil {
+		return x.Term
+	}
+	return nil
+}
+
+func (x *Pack) GetCurrency() *Currency {
+	if x != nil {
+		return x.Currency
+	}
+	return nil
+}
+
+func (x *Pack) GetState() PackState {
+	if x != nil {
+		return x.State
+	}
+	return PackState_PACK_STATE_INACTIVE
+}
+
+func (x *Pack) GetResources() []*Resource {
+	if x != nil {
+		return x.Resources
+	}
+	return nil
+}
+
+func (x *Pack) GetComponents() []string {
+	if x != nil {
+		return x.Components
+	}
+	return nil
+}
+
+func (x *Pack) GetRules() []*EligibilityRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *Pack) GetChannels() []string {
+	if x != nil {
+		return x.Channels
+	}
+	return nil
+}
+
+func (x *Pack) GetRegions() []string {
+	if x != nil {
+		return x.Regions
+	}
+	return nil
+}
+
+func (x *Pack) GetTranslations() []*Translation {
+	if x != nil {
+		return x.Translations
+	}
+	return nil
+}
+
+func (x *Pack) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+// PackType is the type of a pack.
+type PackType struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PackType) Reset() {
+	*x = PackType{}
+	mi := &file_packpb_pack_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PackType) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PackType) ProtoMessage() {}
+
+func (x *PackType) ProtoReflect() protoreflect.Message {
+	mi := &file_packpb_pack_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PackType.ProtoReflect.Descriptor instead.
+func (*PackType) Descriptor() ([]byte, []int) {
+	return file_packpb_pack_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PackType) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *PackType) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// Mno is the mobile network operator of a pack.
+type Mno struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Mno) Reset() {
+	*x = Mno{}
+	mi := &file_packpb_pack_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Mno) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Mno) ProtoMessage() {}
+
+func (x *Mno) ProtoReflect() protoreflect.Message {
+	mi := &file_packpb_pack_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Mno.ProtoReflect.Descriptor instead.
+func (*Mno) Descriptor() ([]byte, []int) {
+	return file_packpb_pack_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Mno) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Mno) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// Term is the duration of a pack.
+type Term struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UnitId        int32                  `protobuf:"varint,1,opt,name=unit_id,json=unitId,proto3" json:"unit_id,omitempty"`
+	Unit          string                 `protobuf:"bytes,2,opt,name=unit,proto3" json:"unit,omitempty"` // unit name. e.g. day, week
+	Amount        int32                  `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Term) Reset() {
+	*x = Term{}
+	mi := &file_packpb_pack_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Term) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Term) ProtoMessage() {}
+
+func (x *Term) ProtoReflect() protoreflect.Message {
+	mi := &file_packpb_pack_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Term.ProtoReflect.Descriptor instead.
+func (*Term) Descriptor() ([]byte, []int) {
+	return file_packpb_pack_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Term) GetUnitId() int32 {
+	if x != nil {
+		return x.UnitId
+	}
+	return 0
+}
+
+func (x *Term) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *Term) GetAmount() int32 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+// Currency is the currency of the price of a pack.
+type Currency struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Currency) Reset() {
+	*x = Currency{}
+	mi := &file_packpb_pack_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Currency) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Currency) ProtoMessage() {}
+
+func (x *Currency) ProtoReflect() protoreflect.Message {
+	mi := &file_packpb_pack_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Currency.ProtoReflect.Descriptor instead.
+func (*Currency) Descriptor() ([]byte, []int) {
+	return file_packpb_pack_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Currency) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Currency) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// Resource is a resource a pack gives. e.g. 100 MB of data
+type Resource struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Units         string                 `protobuf:"bytes,3,opt,name=units,proto3" json:"units,omitempty"`
+	Amount        float32                `protobuf:"fixed32,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	IsFree        bool                   `protobuf:"varint,5,opt,name=is_free,json=isFree,proto3" json:"is_free,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Resource) Reset() {
+	*x = Resource{}
+	mi := &file_packpb_pack_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Resource) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Resource) ProtoMessage() {}
+
+func (x *Resource) ProtoReflect() protoreflect.Message {
+	mi := &file_packpb_pack_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Resource.ProtoReflect.Descriptor instead.
+func (*Resource) Descriptor() ([]byte, []int) {
+	return file_packpb_pack_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Resource) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Resource) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Resource) GetUnits() string {
+	if x != nil {
+		return x.Units
+	}
+	return ""
+}
+
+func (x *Resource) GetAmount() float32 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Resource) GetIsFree() bool {
+	if x != nil {
+		return x.IsFree
+	}
+	return false
+}
+
+// EligibilityRule is a condition a customer must meet to buy a pack.
+type EligibilityRule struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Segments      []string               `protobuf:"bytes,2,rep,name=segments,proto3" json:"segments,omitempty"`
+	PlanTypes     []string               `protobuf:"bytes,3,rep,name=plan_types,json=planTypes,proto3" json:"plan_types,omitempty"`
+	Regions       []string               `protobuf:"bytes,4,rep,name=regions,proto3" json:"regions,omitempty"`
+	MinLineAge    int32                  `protobuf:"varint,5,opt,name=min_line_age,json=minLineAge,proto3" json:"min_line_age,omitempty"`
+	MaxLineAge    int32                  `protobuf:"varint,6,opt,name=max_line_age,json=maxLineAge,proto3" json:"max_line_age,omitempty"`
+	Purchased     []string               `protobuf:"bytes,7,rep,name=purchased,proto3" json:"purchased,omitempty"`
+	NotPurchased  []string               `protobuf:"bytes,8,rep,name=not_purchased,json=notPurchased,proto3" json:"not_purchased,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EligibilityRule) Reset() {
+	*x = EligibilityRule{}
+	mi := &file_packpb_pack_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EligibilityRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EligibilityRule) ProtoMessage() {}
+
+func (x *EligibilityRule) ProtoReflect() protoreflect.Message {
+	mi := &file_packpb_pack_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EligibilityRule.ProtoReflect.Descriptor instead.
+func (*EligibilityRule) Descriptor() ([]byte, []int) {
+	return file_packpb_pack_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *EligibilityRule) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *EligibilityRule) GetSegments() []string {
+	if x != nil {
+		return x.Segments
+	}
+	return nil
+}
+
+func (x *EligibilityRule) GetPlanTypes() []string {
+	if x != nil {
+		return x.PlanTypes
+	}
+	return nil
+}
+
+func (x *EligibilityRule) GetRegions() []string {
+	if x != nil {
+		return x.Regions
+	}
+	return nil
+}
+
+func (x *EligibilityRule) GetMinLineAge() int32 {
+	if x != nil {
+		return x.MinLineAge
+	}
+	return 0
+}
+
+func (x *EligibilityRule) GetMaxLineAge() int32 {
+	if x != nil {
+		return x.MaxLineAge
+	}
+	return 0
+}
+
+func (x *EligibilityRule) GetPurchased() []string {
+	if x != nil {
+		return x.Purchased
+	}
+	return nil
+}
+
+func (x *EligibilityRule) GetNotPurchased() []string {
+	if x != nil {
+		return x.NotPurchased
+	}
+	return nil
+}
+
+// Translation contains the texts of a pack in a locale.
+type Translation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Locale        string                 `protobuf:"bytes,1,opt,name=locale,proto3" json:"locale,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Desc          string                 `protobuf:"bytes,3,opt,name=desc,proto3" json:"desc,omitempty"`
+	Keywords      string                 `protobuf:"bytes,4,opt,name=keywords,proto3" json:"keywords,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Translation) Reset() {
+	*x = Translation{}
+	mi := &file_packpb_pack_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Translation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Translation) ProtoMessage() {}
+
+func (x *Translation) ProtoReflect() protoreflect.Message {
+	mi := &file_packpb_pack_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Translation.ProtoReflect.Descriptor instead.
+func (*Translation) Descriptor() ([]byte, []int) {
+	return file_packpb_pack_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Translation) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *Translation) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Translation) GetDesc() string {
+	if x != nil {
+		return x.Desc
+	}
+	return ""
+}
+
+func (x *Translation) GetKeywords() string {
+	if x != nil {
+		return x.Keywords
+	}
+	return ""
+}
+
+// PackFilter contains the conditions of the packs listed or exported,
+// the ones that are not set match any pack.
+type PackFilter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MnoId         int32                  `protobuf:"varint,1,opt,name=mno_id,json=mnoId,proto3" json:"mno_id,omitempty"`
+	OwnerId       int64                  `protobuf:"varint,2,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	TypeId        int32                  `protobuf:"varint,3,opt,name=type_id,json=typeId,proto3" json:"type_id,omitempty"`
+	State         *PackState             `protobuf:"varint,4,opt,name=state,proto3,enum=pack.v1.PackState,oneof" json:"state,omitempty"`
+	MinPrice      int64                  `protobuf:"varint,5,opt,name=min_price,json=minPrice,proto3" json:"min_price,omitempty"`
+	MaxPrice      int64                  `protobuf:"varint,6,opt,name=max_price,json=maxPrice,proto3" json:"max_price,omitempty"` // 0 for any
+	Channel       string                 `protobuf:"bytes,7,opt,name=channel,proto3" json:"channel,omitempty"`
+	Region        string                 `protobuf:"bytes,8,opt,name=region,proto3" json:"region,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PackFilter) Reset() {
+	*x = PackFilter{}
+	mi := &file_packpb_pack_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PackFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PackFilter) ProtoMessage() {}
+
+func (x *PackFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_packpb_pack_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PackFilter.ProtoReflect.Descriptor instead.
+func (*PackFilter) Descriptor() ([]byte, []int) {
+	return file_packpb_pack_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PackFilter) GetMnoId() int32 {
+	if x != nil {
+		return x.MnoId
+	}
+	return 0
+}
+
+func (x *PackFilter) GetOwnerId() int64 {
+	if x != nil {
+		return x.OwnerId
+	}
+	return 0
+}
+
+func (x *PackFilter) GetTypeId() int32 {
+	if x != nil {
+		return x.TypeId
+	}
+	return 0
+}
+
+func (x *PackFilter) GetState() PackState {
+	if x != nil && x.State != nil {
+		return *x.State
+	}
+	return PackState_PACK_STATE_INACTIVE
+}
+
+func (x *PackFilter) GetMinPrice() int64 {
+	if x != nil {
+		return x.MinPrice
+	}
+	return 0
+}
+
+func (x *PackFilter) GetMaxPrice() int64 {
+	if x != nil {
+		return x.MaxPrice
+	}
+	return 0
+}
+
+func (x *PackFilter) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *PackFilter) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+type GetPackRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPackRequest) Reset() {
+	*x = GetPackRequest{}
+	mi := &file_packpb_pack_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPackRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPackRequest) ProtoMessage() {}
+
+func (x *GetPackRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_packpb_pack_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPackRequest.ProtoReflect.Descriptor instead.
+func (*GetPackRequest) Descriptor() ([]byte, []int) {
+	return file_packpb_pack_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetPackRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetPackByCodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PackCode      string                 `protobuf:"bytes,1,opt,name=pack_code,json=packCode,proto3" json:"pack_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPackByCodeRequest) Reset() {
+	*x = GetPackByCodeRequest{}
+	mi := &file_packpb_pack_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPackByCodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPackByCodeRequest) ProtoMessage() {}
+
+func (x *GetPackByCodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_packpb_pack_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPackByCodeRequest.ProtoReflect.Descriptor instead.
+func (*GetPackByCodeRequest) Descriptor() ([]byte, []int) {
+	return file_packpb_pack_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetPackByCodeRequest) GetPackCode() string {
+	if x != nil {
+		return x.PackCode
+	}
+	return ""
+}
+
+type GetPackByProductIDRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProdId        string                 `protobuf:"bytes,1,opt,name=prod_id,json=prodId,proto3" json:"prod_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPackByProductIDRequest) Reset() {
+	*x = GetPackByProductIDRequest{}
+	mi := &file_packpb_pack_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPackByProductIDRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPackByProductIDRequest) ProtoMessage() {}
+
+func (x *GetPackByProductIDRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_packpb_pack_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPackByProductIDRequest.ProtoReflect.Descriptor instead.
+func (*GetPackByProductIDRequest) Descriptor() ([]byte, []int) {
+	return file_packpb_pack_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetPackByProductIDRequest) GetProdId() string {
+	if x != nil {
+		return x.ProdId
+	}
+	return ""
+}
+
+type CreatePackRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pack          *Pack                  `protobuf:"bytes,1,opt,name=pack,proto3" json:"pack,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePackRequest) Reset() {
+	*x = CreatePackRequest{}
+	mi := &file_packpb_pack_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePackRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePackRequest) ProtoMessage() {}
+
+func (x *CreatePackRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_packpb_pack_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePackRequest.ProtoReflect.Descriptor instead.
+func (*CreatePackRequest) Descriptor() ([]byte, []int) {
+	return file_packpb_pack_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CreatePackRequest) GetPack() *Pack {
+	if x != nil {
+		return x.Pack
+	}
+	return nil
+}
+
+// The changes of a pack only apply if it is still at the version, when
+// it is given.
+type UpdatePackRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Pack          *Pack                  `protobuf:"bytes,2,opt,name=pack,proto3" json:"pack,omitempty"`
+	Version       *int64                 `protobuf:"varint,3,opt,name=version,proto3,oneof" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdatePackRequest) Reset() {
+	*x = UpdatePackRequest{}
+	mi := &file_packpb_pack_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdatePackRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePackRequest) ProtoMessage() {}
+
+func (x *UpdatePackRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_packpb_pack_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePackRequest.ProtoReflect.Descriptor instead.
+func (*UpdatePackRequest) Descriptor() ([]byte, []int) {
+	return file_packpb_pack_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *UpdatePackRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdatePackRequest) GetPack() *Pack {
+	if x != nil {
+		return x.Pack
+	}
+	return nil
+}
+
+func (x *UpdatePackRequest) GetVersion() int64 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+type ChangePackStateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	State         PackState              `protobuf:"varint,2,opt,name=state,proto3,enum=pack.v1.PackState" json:"state,omitempty"`
+	Version       *int64                 `protobuf:"varint,3,opt,name=version,proto3,oneof" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangePackStateRequest) Reset() {
+	*x = ChangePackStateRequest{}
+	mi := &file_packpb_pack_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePackStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePackStateRequest) ProtoMessage() {}
+
+func (x *ChangePackStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_packpb_pack_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePackStateRequest.ProtoReflect.Descriptor instead.
+func (*ChangePackStateRequest) Descriptor() ([]byte, []int) {
+	return file_packpb_pack_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ChangePackStateRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ChangePackStateRequest) GetState() PackState {
+	if x != nil {
+		return x.State
+	}
+	return PackState_PACK_STATE_INACTIVE
+}
+
+func (x *ChangePackStateRequest) GetVersion() int64 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+type ChangePackPriceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Price         int64                  `protobuf:"varint,2,opt,name=price,proto3" json:"price,omitempty"`
+	Version       *int64                 `protobuf:"varint,3,opt,name=version,proto3,oneof" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangePackPriceRequest) Reset() {
+	*x = ChangePackPriceRequest{}
+	mi := &file_packpb_pack_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePackPriceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePackPriceRequest) ProtoMessage() {}
+
+func (x *ChangePackPriceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_packpb_pack_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePackPriceRequest.ProtoReflect.Descriptor instead.
+func (*ChangePackPriceRequest) Descriptor() ([]byte, []int) {
+	return file_packpb_pack_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ChangePackPriceRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ChangePackPriceRequest) GetPrice() int64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *ChangePackPriceRequest) GetVersion() int64 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+type MoveStockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Amount        int64                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Version       *int64                 `protobuf:"varint,3,opt,name=version,proto3,oneof" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveStockRequest) Reset() {
+	*x = MoveStockRequest{}
+	mi := &file_packpb_pack_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveStockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveStockRequest) ProtoMessage() {}
+
+func (x *MoveStockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_packpb_pack_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveStockRequest.ProtoReflect.Descriptor instead.
+func (*MoveStockRequest) Descriptor() ([]byte, []int) {
+	return file_packpb_pack_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *MoveStockRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *MoveStockRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *MoveStockRequest) GetVersion() int64 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+type ReplaceResourcesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Resources     []*Resource            `protobuf:"bytes,2,rep,name=resources,proto3" json:"resources,omitempty"`
+	Version       *int64                 `protobuf:"varint,3,opt,name=version,proto3,oneof" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplaceResourcesRequest) Reset() {
+	*x = ReplaceResourcesRequest{}
+	mi := &file_packpb_pack_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplaceResourcesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplaceResourcesRequest) ProtoMessage() {}
+
+func (x *ReplaceResourcesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_packpb_pack_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplaceResourcesRequest.ProtoReflect.Descriptor instead.
+func (*ReplaceResourcesRequest) Descriptor() ([]byte, []int) {
+	return file_packpb_pack_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ReplaceResourcesRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ReplaceResourcesRequest) GetResources() []*Resource {
+	if x != nil {
+		return x.Resources
+	}
+	return nil
+}
+
+func (x *ReplaceResourcesRequest) GetVersion() int64 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+type DeleteResourcesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Version       *int64                 `protobuf:"varint,2,opt,name=version,proto3,oneof" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteResourcesRequest) Reset() {
+	*x = DeleteResourcesRequest{}
+	mi := &file_packpb_pack_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteResourcesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteResourcesRequest) ProtoMessage() {}
+
+func (x *DeleteResourcesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_packpb_pack_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteResourcesRequest.ProtoReflect.Descriptor instead.
+func (*DeleteResourcesRequest) Descriptor() ([]byte, []int) {
+	return file_packpb_pack_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *DeleteResourcesRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeleteResourcesRequest) GetVersion() int64 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+type SetTranslationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Translation   *Translation           `protobuf:"bytes,2,opt,name=translation,proto3" json:"translation,omitempty"`
+	Version       *int64                 `protobuf:"varint,3,opt,name=version,proto3,oneof" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetTranslationRequest) Reset() {
+	*x = SetTranslationRequest{}
+	mi := &file_packpb_pack_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetTranslationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetTranslationRequest) ProtoMessage() {}
+
+func (x *SetTranslationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_packpb_pack_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetTranslationRequest.ProtoReflect.Descriptor instead.
+func (*SetTranslationRequest) Descriptor() ([]byte, []int) {
+	return file_packpb_pack_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SetTranslationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SetTranslationRequest) GetTranslation() *Translation {
+	if x != nil {
+		return x.Translation
+	}
+	return nil
+}
+
+func (x *SetTranslationRequest) GetVersion() int64 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+type RemoveTranslationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Locale        string                 `protobuf:"bytes,2,opt,name=locale,proto3" json:"locale,omitempty"`
+	Version       *int64                 `protobuf:"varint,3,opt,name=version,proto3,oneof" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveTranslationRequest) Reset() {
+	*x = RemoveTranslationRequest{}
+	mi := &file_packpb_pack_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveTranslationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveTranslationRequest) ProtoMessage() {}
+
+func (x *RemoveTranslationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_packpb_pack_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveTranslationRequest.ProtoReflect.Descriptor instead.
+func (*RemoveTranslationRequest) Descriptor() ([]byte, []int) {
+	return file_packpb_pack_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *RemoveTranslationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RemoveTranslationRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *RemoveTranslationRequest) GetVersion() int64 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+type TransferOwnershipRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	NewOwnerId    int64                  `protobuf:"varint,2,opt,name=new_owner_id,json=newOwnerId,proto3" json:"new_owner_id,omitempty"`
+	Version       *int64                 `protobuf:"varint,3,opt,name=version,proto3,oneof" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferOwnershipRequest) Reset() {
+	*x = TransferOwnershipRequest{}
+	mi := &file_packpb_pack_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferOwnershipRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferOwnershipRequest) ProtoMessage() {}
+
+func (x *TransferOwnershipRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_packpb_pack_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferOwnershipRequest.ProtoReflect.Descriptor instead.
+func (*TransferOwnershipRequest) Descriptor() ([]byte, []int) {
+	return file_packpb_pack_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *TransferOwnershipRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TransferOwnershipRequest) GetNewOwnerId() int64 {
+	if x != nil {
+		return x.NewOwnerId
+	}
+	return 0
+}
+
+func (x *TransferOwnershipRequest) GetVersion() int64 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+type DeletePackRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Version       *int64                 `protobuf:"varint,2,opt,name=version,proto3,oneof" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeletePackRequest) Reset() {
+	*x = DeletePackRequest{}
+	mi := &file_packpb_pack_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletePackRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePackRequest) ProtoMessage() {}
+
+func (x *DeletePackRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_packpb_pack_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePackRequest.ProtoReflect.Descriptor instead.
+func (*DeletePackRequest) Descriptor() ([]byte, []int) {
+	return file_packpb_pack_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *DeletePackRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeletePackRequest) GetVersion() int64 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+type SearchPacksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"` // most packs returned
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchPacksRequest) Reset() {
+	*x = SearchPacksRequest{}
+	mi := &file_packpb_pack_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchPacksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchPacksRequest) ProtoMessage() {}
+
+func (x *SearchPacksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_packpb_pack_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchPacksRequest.ProtoReflect.Descriptor instead.
+func (*SearchPacksRequest) Descriptor() ([]byte, []int) {
+	return file_packpb_pack_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *SearchPacksRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *SearchPacksRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type SearchPacksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Packs         []*Pack                `protobuf:"bytes,1,rep,name=packs,proto3" json:"packs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchPacksResponse) Reset() {
+	*x = SearchPacksResponse{}
+	mi := &file_packpb_pack_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchPacksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchPacksResponse) ProtoMessage() {}
+
+func (x *SearchPacksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_packpb_pack_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchPacksResponse.ProtoReflect.Descriptor instead.
+func (*SearchPacksResponse) Descriptor() ([]byte, []int) {
+	return file_packpb_pack_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *SearchPacksResponse) GetPacks() []*Pack {
+	if x != nil {
+		return x.Packs
+	}
+	return nil
+}
+
+type ListPacksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *PackFilter            `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	Skip          int32                  `protobuf:"varint,2,opt,name=skip,proto3" json:"skip,omitempty"`   // packs skipped
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"` // most packs streamed, 0 for every pack
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPacksRequest) Reset() {
+	*x = ListPacksRequest{}
+	mi := &file_packpb_pack_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPacksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPacksRequest) ProtoMessage() {}
+
+func (x *ListPacksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_packpb_pack_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPacksRequest.ProtoReflect.Descriptor instead.
+func (*ListPacksRequest) Descriptor() ([]byte, []int) {
+	return file_packpb_pack_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ListPacksRequest) GetFilter() *PackFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ListPacksRequest) GetSkip() int32 {
+	if x != nil {
+		return x.Skip
+	}
+	return 0
+}
+
+func (x *ListPacksRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ExportPacksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *PackFilter            `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	Format        string                 `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"` // csv (default), jsonl or xlsx
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportPacksRequest) Reset() {
+	*x = ExportPacksRequest{}
+	mi := &file_packpb_pack_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportPacksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportPacksRequest) ProtoMessage() {}
+
+func (x *ExportPacksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_packpb_pack_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportPacksRequest.ProtoReflect.Descriptor instead.
+func (*ExportPacksRequest) Descriptor() ([]byte, []int) {
+	return file_packpb_pack_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ExportPacksRequest) GetFilter() *PackFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ExportPacksRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+// ExportChunk is a part of the exported file, the first one has its
+// content type.
+type ExportChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	ContentType   string                 `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportChunk) Reset() {
+	*x = ExportChunk{}
+	mi := &file_packpb_pack_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportChunk) ProtoMessage() {}
+
+func (x *ExportChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_packpb_pack_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportChunk.ProtoReflect.Descriptor instead.
+func (*ExportChunk) Descriptor() ([]byte, []int) {
+	return file_packpb_pack_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ExportChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ExportChunk) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+var File_packpb_pack_proto protoreflect.FileDescriptor
+
+const file_packpb_pack_proto_rawDesc = "" +
+	"\n" +
+	"\x11packpb/pack.proto\x12\apack.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xc6\x06\n" +
+	"\x04Pack\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\aprod_id\x18\x02 \x01(\tR\x06prodId\x12\x1b\n" +
+	"\tpack_code\x18\x03 \x01(\tR\bpackCode\x12\x12\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\x12\x12\n" +
+	"\x04desc\x18\x05 \x01(\tR\x04desc\x12\x1b\n" +
+	"\timage_url\x18\x06 \x01(\tR\bimageUrl\x12\x1a\n" +
+	"\bkeywords\x18\a \x01(\tR\bkeywords\x12\x14\n" +
+	"\x05price\x18\b \x01(\x03R\x05price\x12\x14\n" +
+	"\x05stock\x18\t \x01(\x03R\x05stock\x12\x19\n" +
+	"\bowner_id\x18\n" +
+	" \x01(\x03R\aownerId\x124\n" +
+	"\acreated\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\acreated\x124\n" +
+	"\aupdated\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\aupdated\x12\x18\n" +
+	"\aversion\x18\r \x01(\x03R\aversion\x12%\n" +
+	"\x04type\x18\x0e \x01(\v2\x11.pack.v1.PackTypeR\x04type\x12\x1e\n" +
+	"\x03mno\x18\x0f \x01(\v2\f.pack.v1.MnoR\x03mno\x12!\n" +
+	"\x04term\x18\x10 \x01(\v2\r.pack.v1.TermR\x04term\x12-\n" +
+	"\bcurrency\x18\x11 \x01(\v2\x11.pack.v1.CurrencyR\bcurrency\x12(\n" +
+	"\x05state\x18\x12 \x01(\x0e2\x12.pack.v1.PackStateR\x05state\x12/\n" +
+	"\tresources\x18\x13 \x03(\v2\x11.pack.v1.ResourceR\tresources\x12\x1e\n" +
+	"\n" +
+	"components\x18\x14 \x03(\tR\n" +
+	"components\x12.\n" +
+	"\x05rules\x18\x15 \x03(\v2\x18.pack.v1.EligibilityRuleR\x05rules\x12\x1a\n" +
+	"\bchannels\x18\x16 \x03(\tR\bchannels\x12\x18\n" +
+	"\aregions\x18\x17 \x03(\tR\aregions\x128\n" +
+	"\ftranslations\x18\x18 \x03(\v2\x14.pack.v1.TranslationR\ftranslations\x12\x16\n" +
+	"\x06locale\x18\x19 \x01(\tR\x06locale\".\n" +
+	"\bPackType\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\")\n" +
+	"\x03Mno\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"K\n" +
+	"\x04Term\x12\x17\n" +
+	"\aunit_id\x18\x01 \x01(\x05R\x06unitId\x12\x12\n" +
+	"\x04unit\x18\x02 \x01(\tR\x04unit\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x05R\x06amount\".\n" +
+	"\bCurrency\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"u\n" +
+	"\bResource\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05units\x18\x03 \x01(\tR\x05units\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x02R\x06amount\x12\x17\n" +
+	"\ais_free\x18\x05 \x01(\bR\x06isFree\"\x81\x02\n" +
+	"\x0fEligibilityRule\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1a\n" +
+	"\bsegments\x18\x02 \x03(\tR\bsegments\x12\x1d\n" +
+	"\n" +
+	"plan_types\x18\x03 \x03(\tR\tplanTypes\x12\x18\n" +
+	"\aregions\x18\x04 \x03(\tR\aregions\x12 \n" +
+	"\fmin_line_age\x18\x05 \x01(\x05R\n" +
+	"minLineAge\x12 \n" +
+	"\fmax_line_age\x18\x06 \x01(\x05R\n" +
+	"maxLineAge\x12\x1c\n" +
+	"\tpurchased\x18\a \x03(\tR\tpurchased\x12#\n" +
+	"\rnot_purchased\x18\b \x03(\tR\fnotPurchased\"i\n" +
+	"\vTranslation\x12\x16\n" +
+	"\x06locale\x18\x01 \x01(\tR\x06locale\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04desc\x18\x03 \x01(\tR\x04desc\x12\x1a\n" +
+	"\bkeywords\x18\x04 \x01(\tR\bkeywords\"\xfc\x01\n" +
+	"\n" +
+	"PackFilter\x12\x15\n" +
+	"\x06mno_id\x18\x01 \x01(\x05R\x05mnoId\x12\x19\n" +
+	"\bowner_id\x18\x02 \x01(\x03R\aownerId\x12\x17\n" +
+	"\atype_id\x18\x03 \x01(\x05R\x06typeId\x12-\n" +
+	"\x05state\x18\x04 \x01(\x0e2\x12.pack.v1.PackStateH\x00R\x05state\x88\x01\x01\x12\x1b\n" +
+	"\tmin_price\x18\x05 \x01(\x03R\bminPrice\x12\x1b\n" +
+	"\tmax_price\x18\x06 \x01(\x03R\bmaxPrice\x12\x18\n" +
+	"\achannel\x18\a \x01(\tR\achannel\x12\x16\n" +
+	"\x06region\x18\b \x01(\tR\x06regionB\b\n" +
+	"\x06_state\" \n" +
+	"\x0eGetPackRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"3\n" +
+	"\x14GetPackByCodeRequest\x12\x1b\n" +
+	"\tpack_code\x18\x01 \x01(\tR\bpackCode\"4\n" +
+	"\x19GetPackByProductIDRequest\x12\x17\n" +
+	"\aprod_id\x18\x01 \x01(\tR\x06prodId\"6\n" +
+	"\x11CreatePackRequest\x12!\n" +
+	"\x04pack\x18\x01 \x01(\v2\r.pack.v1.PackR\x04pack\"q\n" +
+	"\x11UpdatePackRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12!\n" +
+	"\x04pack\x18\x02 \x01(\v2\r.pack.v1.PackR\x04pack\x12\x1d\n" +
+	"\aversion\x18\x03 \x01(\x03H\x00R\aversion\x88\x01\x01B\n" +
+	"\n" +
+	"\b_version\"}\n" +
+	"\x16ChangePackStateRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12(\n" +
+	"\x05state\x18\x02 \x01(\x0e2\x12.pack.v1.PackStateR\x05state\x12\x1d\n" +
+	"\aversion\x18\x03 \x01(\x03H\x00R\aversion\x88\x01\x01B\n" +
+	"\n" +
+	"\b_version\"i\n" +
+	"\x16ChangePackPriceRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05price\x18\x02 \x01(\x03R\x05price\x12\x1d\n" +
+	"\aversion\x18\x03 \x01(\x03H\x00R\aversion\x88\x01\x01B\n" +
+	"\n" +
+	"\b_version\"e\n" +
+	"\x10MoveStockRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x03R\x06amount\x12\x1d\n" +
+	"\aversion\x18\x03 \x01(\x03H\x00R\aversion\x88\x01\x01B\n" +
+	"\n" +
+	"\b_version\"\x85\x01\n" +
+	"\x17ReplaceResourcesRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12/\n" +
+	"\tresources\x18\x02 \x03(\v2\x11.pack.v1.ResourceR\tresources\x12\x1d\n" +
+	"\aversion\x18\x03 \x01(\x03H\x00R\aversion\x88\x01\x01B\n" +
+	"\n" +
+	"\b_version\"S\n" +
+	"\x16DeleteResourcesRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\aversion\x18\x02 \x01(\x03H\x00R\aversion\x88\x01\x01B\n" +
+	"\n" +
+	"\b_version\"\x8a\x01\n" +
+	"\x15SetTranslationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x126\n" +
+	"\vtranslation\x18\x02 \x01(\v2\x14.pack.v1.TranslationR\vtranslation\x12\x1d\n" +
+	"\aversion\x18\x03 \x01(\x03H\x00R\aversion\x88\x01\x01B\n" +
+	"\n" +
+	"\b_version\"m\n" +
+	"\x18RemoveTranslationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06locale\x18\x02 \x01(\tR\x06locale\x12\x1d\n" +
+	"\aversion\x18\x03 \x01(\x03H\x00R\aversion\x88\x01\x01B\n" +
+	"\n" +
+	"\b_version\"w\n" +
+	"\x18TransferOwnershipRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12 \n" +
+	"\fnew_owner_id\x18\x02 \x01(\x03R\n" +
+	"newOwnerId\x12\x1d\n" +
+	"\aversion\x18\x03 \x01(\x03H\x00R\aversion\x88\x01\x01B\n" +
+	"\n" +
+	"\b_version\"N\n" +
+	"\x11DeletePackRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\aversion\x18\x02 \x01(\x03H\x00R\aversion\x88\x01\x01B\n" +
+	"\n" +
+	"\b_version\">\n" +
+	"\x12SearchPacksRequest\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\":\n" +
+	"\x13SearchPacksResponse\x12#\n" +
+	"\x05packs\x18\x01 \x03(\v2\r.pack.v1.PackR\x05packs\"i\n" +
+	"\x10ListPacksRequest\x12+\n" +
+	"\x06filter\x18\x01 \x01(\v2\x13.pack.v1.PackFilterR\x06filter\x12\x12\n" +
+	"\x04skip\x18\x02 \x01(\x05R\x04skip\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"Y\n" +
+	"\x12ExportPacksRequest\x12+\n" +
+	"\x06filter\x18\x01 \x01(\v2\x13.pack.v1.PackFilterR\x06filter\x12\x16\n" +
+	"\x06format\x18\x02 \x01(\tR\x06format\"D\n" +
+	"\vExportChunk\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\x12!\n" +
+	"\fcontent_type\x18\x02 \x01(\tR\vcontentType*;\n" +
+	"\tPackState\x12\x17\n" +
+	"\x13PACK_STATE_INACTIVE\x10\x00\x12\x15\n" +
+	"\x11PACK_STATE_ACTIVE\x10\x012\xe0\b\n" +
+	"\vPackService\x121\n" +
+	"\aGetPack\x12\x17.pack.v1.GetPackRequest\x1a\r.pack.v1.Pack\x12=\n" +
+	"\rGetPackByCode\x12\x1d.pack.v1.GetPackByCodeRequest\x1a\r.pack.v1.Pack\x12G\n" +
+	"\x12GetPackByProductID\x12\".pack.v1.GetPackByProductIDRequest\x1a\r.pack.v1.Pack\x127\n" +
+	"\n" +
+	"CreatePack\x12\x1a.pack.v1.CreatePackRequest\x1a\r.pack.v1.Pack\x127\n" +
+	"\n" +
+	"UpdatePack\x12\x1a.pack.v1.UpdatePackRequest\x1a\r.pack.v1.Pack\x12A\n" +
+	"\x0fChangePackState\x12\x1f.pack.v1.ChangePackStateRequest\x1a\r.pack.v1.Pack\x12A\n" +
+	"\x0fChangePackPrice\x12\x1f.pack.v1.ChangePackPriceRequest\x1a\r.pack.v1.Pack\x125\n" +
+	"\tMoveStock\x12\x19.pack.v1.MoveStockRequest\x1a\r.pack.v1.Pack\x12C\n" +
+	"\x10ReplaceResources\x12 .pack.v1.ReplaceResourcesRequest\x1a\r.pack.v1.Pack\x12A\n" +
+	"\x0fDeleteResources\x12\x1f.pack.v1.DeleteResourcesRequest\x1a\r.pack.v1.Pack\x12?\n" +
+	"\x0eSetTranslation\x12\x1e.pack.v1.SetTranslationRequest\x1a\r.pack.v1.Pack\x12E\n" +
+	"\x11RemoveTranslation\x12!.pack.v1.RemoveTranslationRequest\x1a\r.pack.v1.Pack\x12N\n" +
+	"\x11TransferOwnership\x12!.pack.v1.TransferOwnershipRequest\x1a\x16.google.protobuf.Empty\x12@\n" +
+	"\n" +
+	"DeletePack\x12\x1a.pack.v1.DeletePackRequest\x1a\x16.google.protobuf.Empty\x12H\n" +
+	"\vSearchPacks\x12\x1b.pack.v1.SearchPacksRequest\x1a\x1c.pack.v1.SearchPacksResponse\x127\n" +
+	"\tListPacks\x12\x19.pack.v1.ListPacksRequest\x1a\r.pack.v1.Pack0\x01\x12B\n" +
+	"\vExportPacks\x12\x1b.pack.v1.ExportPacksRequest\x1a\x14.pack.v1.ExportChunk0\x01B'Z%github.com/fernandoocampo/pack/packpbb\x06proto3"
+
+var (
+	file_packpb_pack_proto_rawDescOnce sync.Once
+	file_packpb_pack_proto_rawDescData []byte
+)
+
+func file_packpb_pack_proto_rawDescGZIP() []byte {
+	file_packpb_pack_proto_rawDescOnce.Do(func() {
+		file_packpb_pack_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_packpb_pack_proto_rawDesc), len(file_packpb_pack_proto_rawDesc)))
+	})
+	return file_packpb_pack_proto_rawDescData
+}
+
+var file_packpb_pack_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_packpb_pack_proto_msgTypes = make([]protoimpl.MessageInfo, 28)
+var file_packpb_pack_proto_goTypes = []any{
+	(PackState)(0),                    // 0: pack.v1.PackState
+	(*Pack)(nil),                      // 1: pack.v1.Pack
+	(*PackType)(nil),                  // 2: pack.v1.PackType
+	(*Mno)(nil),                       // 3: pack.v1.Mno
+	(*Term)(nil),                      // 4: pack.v1.Term
+	(*Currency)(nil),                  // 5: pack.v1.Currency
+	(*Resource)(nil),                  // 6: pack.v1.Resource
+	(*EligibilityRule)(nil),           // 7: pack.v1.EligibilityRule
+	(*Translation)(nil),               // 8: pack.v1.Translation
+	(*PackFilter)(nil),                // 9: pack.v1.PackFilter
+	(*GetPackRequest)(nil),            // 10: pack.v1.GetPackRequest
+	(*GetPackByCodeRequest)(nil),      // 11: pack.v1.GetPackByCodeRequest
+	(*GetPackByProductIDRequest)(nil), // 12: pack.v1.GetPackByProductIDRequest
+	(*CreatePackRequest)(nil),         // 13: pack.v1.CreatePackRequest
+	(*UpdatePackRequest)(nil),         // 14: pack.v1.UpdatePackRequest
+	(*ChangePackStateRequest)(nil),    // 15: pack.v1.ChangePackStateRequest
+	(*ChangePackPriceRequest)(nil),    // 16: pack.v1.ChangePackPriceRequest
+	(*MoveStockRequest)(nil),          // 17: pack.v1.MoveStockRequest
+	(*ReplaceResourcesRequest)(nil),   // 18: pack.v1.ReplaceResourcesRequest
+	(*DeleteResourcesRequest)(nil),    // 19: pack.v1.DeleteResourcesRequest
+	(*SetTranslationRequest)(nil),     // 20: pack.v1.SetTranslationRequest
+	(*RemoveTranslationRequest)(nil),  // 21: pack.v1.RemoveTranslationRequest
+	(*TransferOwnershipRequest)(nil),  // 22: pack.v1.TransferOwnershipRequest
+	(*DeletePackRequest)(nil),         // 23: pack.v1.DeletePackRequest
+	(*SearchPacksRequest)(nil),        // 24: pack.v1.SearchPacksRequest
+	(*SearchPacksResponse)(nil),       // 25: pack.v1.SearchPacksResponse
+	(*ListPacksRequest)(nil),          // 26: pack.v1.ListPacksRequest
+	(*ExportPacksRequest)(nil),        // 27: pack.v1.ExportPacksRequest
+	(*ExportChunk)(nil),               // 28: pack.v1.ExportChunk
+	(*timestamppb.Timestamp)(nil),     // 29: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),             // 30: google.protobuf.Empty
+}
+var file_packpb_pack_proto_depIdxs = []int32{
+	29, // 0: pack.v1.Pack.created:type_name -> google.protobuf.Timestamp
+	29, // 1: pack.v1.Pack.updated:type_name -> google.protobuf.Timestamp
+	2,  // 2: pack.v1.Pack.type:type_name -> pack.v1.PackType
+	3,  // 3: pack.v1.Pack.mno:type_name -> pack.v1.Mno
+	4,  // 4: pack.v1.Pack.term:type_name -> pack.v1.Term
+	5,  // 5: pack.v1.Pack.currency:type_name -> pack.v1.Currency
+	0,  // 6: pack.v1.Pack.state:type_name -> pack.v1.PackState
+	6,  // 7: pack.v1.Pack.resources:type_name -> pack.v1.Resource
+	7,  // 8: pack.v1.Pack.rules:type_name -> pack.v1.EligibilityRule
+	8,  // 9: pack.v1.Pack.translations:type_name -> pack.v1.Translation
+	0,  // 10: pack.v1.PackFilter.state:type_name -> pack.v1.PackState
+	1,  // 11: pack.v1.CreatePackRequest.pack:type_name -> pack.v1.Pack
+	1,  // 12: pack.v1.UpdatePackRequest.pack:type_name -> pack.v1.Pack
+	0,  // 13: pack.v1.ChangePackStateRequest.state:type_name -> pack.v1.PackState
+	6,  // 14: pack.v1.ReplaceResourcesRequest.resources:type_name -> pack.v1.Resource
+	8,  // 15: pack.v1.SetTranslationRequest.translation:type_name -> pack.v1.Translation
+	1,  // 16: pack.v1.SearchPacksResponse.packs:type_name -> pack.v1.Pack
+	9,  // 17: pack.v1.ListPacksRequest.filter:type_name -> pack.v1.PackFilter
+	9,  // 18: pack.v1.ExportPacksRequest.filter:type_name -> pack.v1.PackFilter
+	10, // 19: pack.v1.PackService.GetPack:input_type -> pack.v1.GetPackRequest
+	11, // 20: pack.v1.PackService.GetPackByCode:input_type -> pack.v1.GetPackByCodeRequest
+	12, // 21: pack.v1.PackService.GetPackByProductID:input_type -> pack.v1.GetPackByProductIDRequest
+	13, // 22: pack.v1.PackService.CreatePack:input_type -> pack.v1.CreatePackRequest
+	14, // 23: pack.v1.PackService.UpdatePack:input_type -> pack.v1.UpdatePackRequest
+	15, // 24: pack.v1.PackService.ChangePackState:input_type -> pack.v1.ChangePackStateRequest
+	16, // 25: pack.v1.PackService.ChangePackPrice:input_type -> pack.v1.ChangePackPriceRequest
+	17, // 26: pack.v1.PackService.MoveStock:input_type -> pack.v1.MoveStockRequest
+	18, // 27: pack.v1.PackService.ReplaceResources:input_type -> pack.v1.ReplaceResourcesRequest
+	19, // 28: pack.v1.PackService.DeleteResources:input_type -> pack.v1.DeleteResourcesRequest
+	20, // 29: pack.v1.PackService.SetTranslation:input_type -> pack.v1.SetTranslationRequest
+	21, // 30: pack.v1.PackService.RemoveTranslation:input_type -> pack.v1.RemoveTranslationRequest
+	22, // 31: pack.v1.PackService.TransferOwnership:input_type -> pack.v1.TransferOwnershipRequest
+	23, // 32: pack.v1.PackService.DeletePack:input_type -> pack.v1.DeletePackRequest
+	24, // 33: pack.v1.PackService.SearchPacks:input_type -> pack.v1.SearchPacksRequest
+	26, // 34: pack.v1.PackService.ListPacks:input_type -> pack.v1.ListPacksRequest
+	27, // 35: pack.v1.PackService.ExportPacks:input_type -> pack.v1.ExportPacksRequest
+	1,  // 36: pack.v1.PackService.GetPack:output_type -> pack.v1.Pack
+	1,  // 37: pack.v1.PackService.GetPackByCode:output_type -> pack.v1.Pack
+	1,  // 38: pack.v1.PackService.GetPackByProductID:output_type -> pack.v1.Pack
+	1,  // 39: pack.v1.PackService.CreatePack:output_type -> pack.v1.Pack
+	1,  // 40: pack.v1.PackService.UpdatePack:output_type -> pack.v1.Pack
+	1,  // 41: pack.v1.PackService.ChangePackState:output_type -> pack.v1.Pack
+	1,  // 42: pack.v1.PackService.ChangePackPrice:output_type -> pack.v1.Pack
+	1,  // 43: pack.v1.PackService.MoveStock:output_type -> pack.v1.Pack
+	1,  // 44: pack.v1.PackService.ReplaceResources:output_type -> pack.v1.Pack
+	1,  // 45: pack.v1.PackService.DeleteResources:output_type -> pack.v1.Pack
+	1,  // 46: pack.v1.PackService.SetTranslation:output_type -> pack.v1.Pack
+	1,  // 47: pack.v1.PackService.RemoveTranslation:output_type -> pack.v1.Pack
+	30, // 48: pack.v1.PackService.TransferOwnership:output_type -> google.protobuf.Empty
+	30, // 49: pack.v1.PackService.DeletePack:output_type -> google.protobuf.Empty
+	25, // 50: pack.v1.PackService.SearchPacks:output_type -> pack.v1.SearchPacksResponse
+	1,  // 51: pack.v1.PackService.ListPacks:output_type -> pack.v1.Pack
+	28, // 52: pack.v1.PackService.ExportPacks:output_type -> pack.v1.ExportChunk
+	36, // [36:53] is the sub-list for method output_type
+	19, // [19:36] is the sub-list for method input_type
+	19, // [19:19] is the sub-list for extension type_name
+	19, // [19:19] is the sub-list for extension extendee
+	0,  // [0:19] is the sub-list for field type_name
+}
+
+func init() { file_packpb_pack_proto_init() }
+func file_packpb_pack_proto_init() {
+	if File_packpb_pack_proto != nil {
+		return
+	}
+	file_packpb_pack_proto_msgTypes[8].OneofWrappers = []any{}
+	file_packpb_pack_proto_msgTypes[13].OneofWrappers = []any{}
+	file_packpb_pack_proto_msgTypes[14].OneofWrappers = []any{}
+	file_packpb_pack_proto_msgTypes[15].OneofWrappers = []any{}
+	file_packpb_pack_proto_msgTypes[16].OneofWrappers = []any{}
+	file_packpb_pack_proto_msgTypes[17].OneofWrappers = []any{}
+	file_packpb_pack_proto_msgTypes[18].OneofWrappers = []any{}
+	file_packpb_pack_proto_msgTypes[19].OneofWrappers = []any{}
+	file_packpb_pack_proto_msgTypes[20].OneofWrappers = []any{}
+	file_packpb_pack_proto_msgTypes[21].OneofWrappers = []any{}
+	file_packpb_pack_proto_msgTypes[22].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_packpb_pack_proto_rawDesc), len(file_packpb_pack_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   28,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_packpb_pack_proto_goTypes,
+		DependencyIndexes: file_packpb_pack_proto_depIdxs,
+		EnumInfos:         file_packpb_pack_proto_enumTypes,
+		MessageInfos:      file_packpb_pack_proto_msgTypes,
+	}.Build()
+	File_packpb_pack_proto = out.File
+	file_packpb_pack_proto_goTypes = nil
+	file_packpb_pack_proto_depIdxs = nil
+}
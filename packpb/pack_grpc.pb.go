@@ -0,0 +1,820 @@
+// pack.v1 is the grpc api of the pack catalog for internal services, it
+// serves the operations of the pack service with the same roles, tenants
+// and errors as the graphql and rest apis.
+//
+// The go code is generated with protoc-gen-go and protoc-gen-go-grpc:
+// protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative packpb/pack.proto
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: packpb/pack.proto
+
+package packpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	PackService_GetPack_FullMethodName            = "/pack.v1.PackService/GetPack"
+	PackService_GetPackByCode_FullMethodName      = "/pack.v1.PackService/GetPackByCode"
+	PackService_GetPackByProductID_FullMethodName = "/pack.v1.PackService/GetPackByProductID"
+	PackService_CreatePack_FullMethodName         = "/pack.v1.PackService/CreatePack"
+	PackService_UpdatePack_FullMethodName         = "/pack.v1.PackService/UpdatePack"
+	PackService_ChangePackState_FullMethodName    = "/pack.v1.PackService/ChangePackState"
+	PackService_ChangePackPrice_FullMethodName    = "/pack.v1.PackService/ChangePackPrice"
+	PackService_MoveStock_FullMethodName          = "/pack.v1.PackService/MoveStock"
+	PackService_ReplaceResources_FullMethodName   = "/pack.v1.PackService/ReplaceResources"
+	PackService_DeleteResources_FullMethodName    = "/pack.v1.PackService/DeleteResources"
+	PackService_SetTranslation_FullMethodName     = "/pack.v1.PackService/SetTranslation"
+	PackService_RemoveTranslation_FullMethodName  = "/pack.v1.PackService/RemoveTranslation"
+	PackService_TransferOwnership_FullMethodName  = "/pack.v1.PackService/TransferOwnership"
+	PackService_DeletePack_FullMethodName         = "/pack.v1.PackService/DeletePack"
+	PackService_SearchPacks_FullMethodName        = "/pack.v1.PackService/SearchPacks"
+	PackService_ListPacks_FullMethodName          = "/pack.v1.PackService/ListPacks"
+	PackService_ExportPacks_FullMethodName        = "/pack.v1.PackService/ExportPacks"
+)
+
+// PackServiceClient is the client API for PackService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// PackService reads and changes the packs of the tenant of the caller.
+// The caller is authenticated with the authorization or x-api-key
+// metadata and the messages of the errors are in the language of the
+// accept-language metadata.
+//
+// Errors have the code of their category: INVALID_ARGUMENT,
+// PERMISSION_DENIED, NOT_FOUND, FAILED_PRECONDITION when the pack is not
+// in a state that allows the change and ABORTED when the pack is not at
+// the expected version. Their google.rpc.ErrorInfo detail has the code
+// of the catalog error as reason and its category and field as metadata.
+type PackServiceClient interface {
+	// GetPack returns the pack with the given id.
+	GetPack(ctx context.Context, in *GetPackRequest, opts ...grpc.CallOption) (*Pack, error)
+	// GetPackByCode returns the pack with the given pack code.
+	GetPackByCode(ctx context.Context, in *GetPackByCodeRequest, opts ...grpc.CallOption) (*Pack, error)
+	// GetPackByProductID returns the pack with the given product id of the
+	// mno.
+	GetPackByProductID(ctx context.Context, in *GetPackByProductIDRequest, opts ...grpc.CallOption) (*Pack, error)
+	// CreatePack creates an active pack without stock.
+	CreatePack(ctx context.Context, in *CreatePackRequest, opts ...grpc.CallOption) (*Pack, error)
+	// UpdatePack replaces the catalog data of the pack, its state, stock,
+	// rules and availability are kept, as its translations if none are
+	// given.
+	UpdatePack(ctx context.Context, in *UpdatePackRequest, opts ...grpc.CallOption) (*Pack, error)
+	// ChangePackState activates or inactivates the pack.
+	ChangePackState(ctx context.Context, in *ChangePackStateRequest, opts ...grpc.CallOption) (*Pack, error)
+	// ChangePackPrice changes the price of the pack.
+	ChangePackPrice(ctx context.Context, in *ChangePackPriceRequest, opts ...grpc.CallOption) (*Pack, error)
+	// MoveStock adds units to the stock of the pack, or reduces it with a
+	// negative amount.
+	MoveStock(ctx context.Context, in *MoveStockRequest, opts ...grpc.CallOption) (*Pack, error)
+	// ReplaceResources replaces the resources of a pack that is not a
+	// bundle.
+	ReplaceResources(ctx context.Context, in *ReplaceResourcesRequest, opts ...grpc.CallOption) (*Pack, error)
+	// DeleteResources removes the resources of a pack that is not a
+	// bundle.
+	DeleteResources(ctx context.Context, in *DeleteResourcesRequest, opts ...grpc.CallOption) (*Pack, error)
+	// SetTranslation adds or replaces the texts of the pack in a locale.
+	SetTranslation(ctx context.Context, in *SetTranslationRequest, opts ...grpc.CallOption) (*Pack, error)
+	// RemoveTranslation removes the texts of the pack in a locale.
+	RemoveTranslation(ctx context.Context, in *RemoveTranslationRequest, opts ...grpc.CallOption) (*Pack, error)
+	// TransferOwnership gives the pack to another owner.
+	TransferOwnership(ctx context.Context, in *TransferOwnershipRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// DeletePack removes the pack, it cannot be a component of an active
+	// bundle.
+	DeletePack(ctx context.Context, in *DeletePackRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// SearchPacks returns the packs whose texts in any locale match the
+	// text.
+	SearchPacks(ctx context.Context, in *SearchPacksRequest, opts ...grpc.CallOption) (*SearchPacksResponse, error)
+	// ListPacks streams the packs that match the filter sorted by pack
+	// code.
+	ListPacks(ctx context.Context, in *ListPacksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Pack], error)
+	// ExportPacks streams the file of the packs that match the filter in
+	// chunks, as they are read.
+	ExportPacks(ctx context.Context, in *ExportPacksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportChunk], error)
+}
+
+type packServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPackServiceClient(cc grpc.ClientConnInterface) PackServiceClient {
+	return &packServiceClient{cc}
+}
+
+func (c *packServiceClient) GetPack(ctx context.Context, in *GetPackRequest, opts ...grpc.CallOption) (*Pack, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Pack)
+	err := c.cc.Invoke(ctx, PackService_GetPack_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *packServiceClient) GetPackByCode(ctx context.Context, in *GetPackByCodeRequest, opts ...grpc.CallOption) (*Pack, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Pack)
+	err := c.cc.Invoke(ctx, PackService_GetPackByCode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *packServiceClient) GetPackByProductID(ctx context.Context, in *GetPackByProductIDRequest, opts ...grpc.CallOption) (*Pack, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Pack)
+	err := c.cc.Invoke(ctx, PackService_GetPackByProductID_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *packServiceClient) CreatePack(ctx context.Context, in *CreatePackRequest, opts ...grpc.CallOption) (*Pack, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Pack)
+	err := c.cc.Invoke(ctx, PackService_CreatePack_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *packServiceClient) UpdatePack(ctx context.Context, in *UpdatePackRequest, opts ...grpc.CallOption) (*Pack, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Pack)
+	err := c.cc.Invoke(ctx, PackService_UpdatePack_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *packServiceClient) ChangePackState(ctx context.Context, in *ChangePackStateRequest, opts ...grpc.CallOption) (*Pack, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Pack)
+	err := c.cc.Invoke(ctx, PackService_ChangePackState_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *packServiceClient) ChangePackPrice(ctx context.Context, in *ChangePackPriceRequest, opts ...grpc.CallOption) (*Pack, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Pack)
+	err := c.cc.Invoke(ctx, PackService_ChangePackPrice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *packServiceClient) MoveStock(ctx context.Context, in *MoveStockRequest, opts ...grpc.CallOption) (*Pack, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Pack)
+	err := c.cc.Invoke(ctx, PackService_MoveStock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *packServiceClient) ReplaceResources(ctx context.Context, in *ReplaceResourcesRequest, opts ...grpc.CallOption) (*Pack, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Pack)
+	err := c.cc.Invoke(ctx, PackService_ReplaceResources_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *packServiceClient) DeleteResources(ctx context.Context, in *DeleteResourcesRequest, opts ...grpc.CallOption) (*Pack, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Pack)
+	err := c.cc.Invoke(ctx, PackService_DeleteResources_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *packServiceClient) SetTranslation(ctx context.Context, in *SetTranslationRequest, opts ...grpc.CallOption) (*Pack, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Pack)
+	err := c.cc.Invoke(ctx, PackService_SetTranslation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *packServiceClient) RemoveTranslation(ctx context.Context, in *RemoveTranslationRequest, opts ...grpc.CallOption) (*Pack, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Pack)
+	err := c.cc.Invoke(ctx, PackService_RemoveTranslation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *packServiceClient) TransferOwnership(ctx context.Context, in *TransferOwnershipRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, PackService_TransferOwnership_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *packServiceClient) DeletePack(ctx context.Context, in *DeletePackRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, PackService_DeletePack_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *packServiceClient) SearchPacks(ctx context.Context, in *SearchPacksRequest, opts ...grpc.CallOption) (*SearchPacksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchPacksResponse)
+	err := c.cc.Invoke(ctx, PackService_SearchPacks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *packServiceClient) ListPacks(ctx context.Context, in *ListPacksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Pack], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PackService_ServiceDesc.Streams[0], PackService_ListPacks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListPacksRequest, Pack]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PackService_ListPacksClient = grpc.ServerStreamingClient[Pack]
+
+func (c *packServiceClient) ExportPacks(ctx context.Context, in *ExportPacksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PackService_ServiceDesc.Streams[1], PackService_ExportPacks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportPacksRequest, ExportChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PackService_ExportPacksClient = grpc.ServerStreamingClient[ExportChunk]
+
+// PackServiceServer is the server API for PackService service.
+// All implementations must embed UnimplementedPackServiceServer
+// for forward compatibility.
+//
+// PackService reads and changes the packs of the tenant of the caller.
+// The caller is authenticated with the authorization or x-api-key
+// metadata and the messages of the errors are in the language of the
+// accept-language metadata.
+//
+// Errors have the code of their category: INVALID_ARGUMENT,
+// PERMISSION_DENIED, NOT_FOUND, FAILED_PRECONDITION when the pack is not
+// in a state that allows the change and ABORTED when the pack is not at
+// the expected version. Their google.rpc.ErrorInfo detail has the code
+// of the catalog error as reason and its category and field as metadata.
+type PackServiceServer interface {
+	// GetPack returns the pack with the given id.
+	GetPack(context.Context, *GetPackRequest) (*Pack, error)
+	// GetPackByCode returns the pack with the given pack code.
+	GetPackByCode(context.Context, *GetPackByCodeRequest) (*Pack, error)
+	// GetPackByProductID returns the pack with the given product id of the
+	// mno.
+	GetPackByProductID(context.Context, *GetPackByProductIDRequest) (*Pack, error)
+	// CreatePack creates an active pack without stock.
+	CreatePack(context.Context, *CreatePackRequest) (*Pack, error)
+	// UpdatePack replaces the catalog data of the pack, its state, stock,
+	// rules and availability are kept, as its translations if none are
+	// given.
+	UpdatePack(context.Context, *UpdatePackRequest) (*Pack, error)
+	// ChangePackState activates or inactivates the pack.
+	ChangePackState(context.Context, *ChangePackStateRequest) (*Pack, error)
+	// ChangePackPrice changes the price of the pack.
+	ChangePackPrice(context.Context, *ChangePackPriceRequest) (*Pack, error)
+	// MoveStock adds units to the stock of the pack, or reduces it with a
+	// negative amount.
+	MoveStock(context.Context, *MoveStockRequest) (*Pack, error)
+	// ReplaceResources replaces the resources of a pack that is not a
+	// bundle.
+	ReplaceResources(context.Context, *ReplaceResourcesRequest) (*Pack, error)
+	// DeleteResources removes the resources of a pack that is not a
+	// bundle.
+	DeleteResources(context.Context, *DeleteResourcesRequest) (*Pack, error)
+	// SetTranslation adds or replaces the texts of the pack in a locale.
+	SetTranslation(context.Context, *SetTranslationRequest) (*Pack, error)
+	// RemoveTranslation removes the texts of the pack in a locale.
+	RemoveTranslation(context.Context, *RemoveTranslationRequest) (*Pack, error)
+	// TransferOwnership gives the pack to another owner.
+	TransferOwnership(context.Context, *TransferOwnershipRequest) (*emptypb.Empty, error)
+	// DeletePack removes the pack, it cannot be a component of an active
+	// bundle.
+	DeletePack(context.Context, *DeletePackRequest) (*emptypb.Empty, error)
+	// SearchPacks returns the packs whose texts in any locale match the
+	// text.
+	SearchPacks(context.Context, *SearchPacksRequest) (*SearchPacksResponse, error)
+	// ListPacks streams the packs that match the filter sorted by pack
+	// code.
+	ListPacks(*ListPacksRequest, grpc.ServerStreamingServer[Pack]) error
+	// ExportPacks streams the file of the packs that match the filter in
+	// chunks, as they are read.
+	ExportPacks(*ExportPacksRequest, grpc.ServerStreamingServer[ExportChunk]) error
+	mustEmbedUnimplementedPackServiceServer()
+}
+
+// UnimplementedPackServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPackServiceServer struct{}
+
+func (UnimplementedPackServiceServer) GetPack(context.Context, *GetPackRequest) (*Pack, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPack not implemented")
+}
+func (UnimplementedPackServiceServer) GetPackByCode(context.Context, *GetPackByCodeRequest) (*Pack, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPackByCode not implemented")
+}
+func (UnimplementedPackServiceServer) GetPackByProductID(context.Context, *GetPackByProductIDRequest) (*Pack, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPackByProductID not implemented")
+}
+func (UnimplementedPackServiceServer) CreatePack(context.Context, *CreatePackRequest) (*Pack, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePack not implemented")
+}
+func (UnimplementedPackServiceServer) UpdatePack(context.Context, *UpdatePackRequest) (*Pack, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdatePack not implemented")
+}
+func (UnimplementedPackServiceServer) ChangePackState(context.Context, *ChangePackStateRequest) (*Pack, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangePackState not implemented")
+}
+func (UnimplementedPackServiceServer) ChangePackPrice(context.Context, *ChangePackPriceRequest) (*Pack, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangePackPrice not implemented")
+}
+func (UnimplementedPackServiceServer) MoveStock(context.Context, *MoveStockRequest) (*Pack, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveStock not implemented")
+}
+func (UnimplementedPackServiceServer) ReplaceResources(context.Context, *ReplaceResourcesRequest) (*Pack, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplaceResources not implemented")
+}
+func (UnimplementedPackServiceServer) DeleteResources(context.Context, *DeleteResourcesRequest) (*Pack, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteResources not implemented")
+}
+func (UnimplementedPackServiceServer) SetTranslation(context.Context, *SetTranslationRequest) (*Pack, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetTranslation not implemented")
+}
+func (UnimplementedPackServiceServer) RemoveTranslation(context.Context, *RemoveTranslationRequest) (*Pack, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveTranslation not implemented")
+}
+func (UnimplementedPackServiceServer) TransferOwnership(context.Context, *TransferOwnershipRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TransferOwnership not implemented")
+}
+func (UnimplementedPackServiceServer) DeletePack(context.Context, *DeletePackRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePack not implemented")
+}
+func (UnimplementedPackServiceServer) SearchPacks(context.Context, *SearchPacksRequest) (*SearchPacksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchPacks not implemented")
+}
+func (UnimplementedPackServiceServer) ListPacks(*ListPacksRequest, grpc.ServerStreamingServer[Pack]) error {
+	return status.Errorf(codes.Unimplemented, "method ListPacks not implemented")
+}
+func (UnimplementedPackServiceServer) ExportPacks(*ExportPacksRequest, grpc.ServerStreamingServer[ExportChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportPacks not implemented")
+}
+func (UnimplementedPackServiceServer) mustEmbedUnimplementedPackServiceServer() {}
+func (UnimplementedPackServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafePackServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PackServiceServer will
+// result in compilation errors.
+type UnsafePackServiceServer interface {
+	mustEmbedUnimplementedPackServiceServer()
+}
+
+func RegisterPackServiceServer(s grpc.ServiceRegistrar, srv PackServiceServer) {
+	// If the following call pancis, it indicates UnimplementedPackServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PackService_ServiceDesc, srv)
+}
+
+func _PackService_GetPack_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PackServiceServer).GetPack(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PackService_GetPack_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PackServiceServer).GetPack(ctx, req.(*GetPackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PackService_GetPackByCode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPackByCodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PackServiceServer).GetPackByCode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PackService_GetPackByCode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PackServiceServer).GetPackByCode(ctx, req.(*GetPackByCodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PackService_GetPackByProductID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPackByProductIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PackServiceServer).GetPackByProductID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PackService_GetPackByProductID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PackServiceServer).GetPackByProductID(ctx, req.(*GetPackByProductIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PackService_CreatePack_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PackServiceServer).CreatePack(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PackService_CreatePack_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PackServiceServer).CreatePack(ctx, req.(*CreatePackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PackService_UpdatePack_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdatePackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PackServiceServer).UpdatePack(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PackService_UpdatePack_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PackServiceServer).UpdatePack(ctx, req.(*UpdatePackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PackService_ChangePackState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangePackStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PackServiceServer).ChangePackState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PackService_ChangePackState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PackServiceServer).ChangePackState(ctx, req.(*ChangePackStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PackService_ChangePackPrice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangePackPriceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PackServiceServer).ChangePackPrice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PackService_ChangePackPrice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PackServiceServer).ChangePackPrice(ctx, req.(*ChangePackPriceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PackService_MoveStock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveStockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PackServiceServer).MoveStock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PackService_MoveStock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PackServiceServer).MoveStock(ctx, req.(*MoveStockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PackService_ReplaceResources_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplaceResourcesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PackServiceServer).ReplaceResources(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PackService_ReplaceResources_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PackServiceServer).ReplaceResources(ctx, req.(*ReplaceResourcesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PackService_DeleteResources_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteResourcesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PackServiceServer).DeleteResources(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PackService_DeleteResources_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PackServiceServer).DeleteResources(ctx, req.(*DeleteResourcesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PackService_SetTranslation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetTranslationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PackServiceServer).SetTranslation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PackService_SetTranslation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PackServiceServer).SetTranslation(ctx, req.(*SetTranslationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PackService_RemoveTranslation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveTranslationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PackServiceServer).RemoveTranslation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PackService_RemoveTranslation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PackServiceServer).RemoveTranslation(ctx, req.(*RemoveTranslationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PackService_TransferOwnership_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferOwnershipRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PackServiceServer).TransferOwnership(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PackService_TransferOwnership_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PackServiceServer).TransferOwnership(ctx, req.(*TransferOwnershipRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PackService_DeletePack_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PackServiceServer).DeletePack(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PackService_DeletePack_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PackServiceServer).DeletePack(ctx, req.(*DeletePackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PackService_SearchPacks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchPacksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PackServiceServer).SearchPacks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PackService_SearchPacks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PackServiceServer).SearchPacks(ctx, req.(*SearchPacksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PackService_ListPacks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListPacksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PackServiceServer).ListPacks(m, &grpc.GenericServerStream[ListPacksRequest, Pack]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PackService_ListPacksServer = grpc.ServerStreamingServer[Pack]
+
+func _PackService_ExportPacks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportPacksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PackServiceServer).ExportPacks(m, &grpc.GenericServerStream[ExportPacksRequest, ExportChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PackService_ExportPacksServer = grpc.ServerStreamingServer[ExportChunk]
+
+// PackService_ServiceDesc is the grpc.ServiceDesc for PackService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PackService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pack.v1.PackService",
+	HandlerType: (*PackServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetPack",
+			Handler:    _PackService_GetPack_Handler,
+		},
+		{
+			MethodName: "GetPackByCode",
+			Handler:    _PackService_GetPackByCode_Handler,
+		},
+		{
+			MethodName: "GetPackByProductID",
+			Handler:    _PackService_GetPackByProductID_Handler,
+		},
+		{
+			MethodName: "CreatePack",
+			Handler:    _PackService_CreatePack_Handler,
+		},
+		{
+			MethodName: "UpdatePack",
+			Handler:    _PackService_UpdatePack_Handler,
+		},
+		{
+			MethodName: "ChangePackState",
+			Handler:    _PackService_ChangePackState_Handler,
+		},
+		{
+			MethodName: "ChangePackPrice",
+			Handler:    _PackService_ChangePackPrice_Handler,
+		},
+		{
+			MethodName: "MoveStock",
+			Handler:    _PackService_MoveStock_Handler,
+		},
+		{
+			MethodName: "ReplaceResources",
+			Handler:    _PackService_ReplaceResources_Handler,
+		},
+		{
+			MethodName: "DeleteResources",
+			Handler:    _PackService_DeleteResources_Handler,
+		},
+		{
+			MethodName: "SetTranslation",
+			Handler:    _PackService_SetTranslation_Handler,
+		},
+		{
+			MethodName: "RemoveTranslation",
+			Handler:    _PackService_RemoveTranslation_Handler,
+		},
+		{
+			MethodName: "TransferOwnership",
+			Handler:    _PackService_TransferOwnership_Handler,
+		},
+		{
+			MethodName: "DeletePack",
+			Handler:    _PackService_DeletePack_Handler,
+		},
+		{
+			MethodName: "SearchPacks",
+			Handler:    _PackService_SearchPacks_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListPacks",
+			Handler:       _PackService_ListPacks_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExportPacks",
+			Handler:       _PackService_ExportPacks_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "packpb/pack.proto",
+}
@@ -0,0 +1,137 @@
+package provisioning
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/fernandoocampo/pack/model"
+)
+
+// HTTPProvisioner implements IProvisioner calling a json api of the mno.
+//
+// Activations are created with POST {url}/activations, removed with
+// DELETE {url}/activations/{reference} and queried with
+// GET {url}/activations/{reference}.
+type HTTPProvisioner struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+// activationRequest is the body sent to activate a pack.
+type activationRequest struct {
+	Msisdn string `json:"msisdn"`
+	ProdID string `json:"prodid"`
+}
+
+// activationResponse is the body answered by the mno api.
+type activationResponse struct {
+	Reference string `json:"reference"`
+	Status    string `json:"status"`
+}
+
+// NewHTTPProvisioner creates an HTTPProvisioner from its configuration.
+func NewHTTPProvisioner(config Config) (IProvisioner, error) {
+	if config.URL == "" {
+		return nil, errors.New("url of the mno api is mandatory")
+	}
+	timeout := time.Duration(config.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	adapter := &HTTPProvisioner{
+		baseURL: strings.TrimRight(config.URL, "/"),
+		token:   config.Token,
+		client:  &http.Client{Timeout: timeout},
+	}
+	return adapter, nil
+}
+
+// Activate implements IProvisioner.Activate.
+func (h *HTTPProvisioner) Activate(msisdn string, prodid string) (*model.Provision, error) {
+	body, err := json.Marshal(activationRequest{Msisdn: msisdn, ProdID: prodid})
+	if err != nil {
+		return nil, err
+	}
+	response := activationResponse{}
+	err = h.call(http.MethodPost, h.baseURL+"/activations", body, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Provision{Reference: response.Reference, Status: parseStatus(response.Status)}, nil
+}
+
+// Deactivate implements IProvisioner.Deactivate.
+func (h *HTTPProvisioner) Deactivate(msisdn string, prodid string, reference string) error {
+	return h.call(http.MethodDelete, h.activationURL(msisdn, prodid, reference), nil, nil)
+}
+
+// Status implements IProvisioner.Status.
+func (h *HTTPProvisioner) Status(msisdn string, prodid string, reference string) (model.ProvisionStatus, error) {
+	response := activationResponse{}
+	err := h.call(http.MethodGet, h.activationURL(msisdn, prodid, reference), nil, &response)
+	if err != nil {
+		return model.ProvisionUnknown, err
+	}
+	return parseStatus(response.Status), nil
+}
+
+// activationURL returns the url of an activation.
+func (h *HTTPProvisioner) activationURL(msisdn string, prodid string, reference string) string {
+	query := url.Values{}
+	query.Set("msisdn", msisdn)
+	query.Set("prodid", prodid)
+	return h.baseURL + "/activations/" + url.PathEscape(reference) + "?" + query.Encode()
+}
+
+// call makes a request to the mno api and decodes the answer in result.
+// Network errors and 5xx answers are temporary, 4xx answers are not.
+func (h *HTTPProvisioner) call(method string, target string, body []byte, result interface{}) error {
+	request, err := http.NewRequest(method, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if h.token != "" {
+		request.Header.Set("Authorization", "Bearer "+h.token)
+	}
+
+	response, err := h.client.Do(request)
+	if err != nil {
+		return &TemporaryError{Err: err}
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= http.StatusInternalServerError || response.StatusCode == http.StatusTooManyRequests {
+		return &TemporaryError{Err: fmt.Errorf("mno api answered %d", response.StatusCode)}
+	}
+	if response.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("mno api rejected the request with %d", response.StatusCode)
+	}
+	if result == nil || response.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(response.Body).Decode(result)
+}
+
+// parseStatus converts the status name answered by the mno api.
+func parseStatus(status string) model.ProvisionStatus {
+	switch strings.ToLower(status) {
+	case "pending":
+		return model.ProvisionPending
+	case "active":
+		return model.ProvisionActive
+	case "inactive":
+		return model.ProvisionInactive
+	case "failed":
+		return model.ProvisionFailed
+	default:
+		return model.ProvisionUnknown
+	}
+}
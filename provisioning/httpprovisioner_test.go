@@ -0,0 +1,79 @@
+package provisioning_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/provisioning"
+)
+
+// TestHTTPProvisionerActivate verify the calls made to the mno api.
+func TestHTTPProvisionerActivate(t *testing.T) {
+	// GIVEN an mno api that activates packs
+	var request map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/activations" {
+			t.Errorf("unexpected call %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer s3cr3t" {
+			t.Errorf("expected bearer token but got %q", r.Header.Get("Authorization"))
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		w.Write([]byte(`{"reference":"ref-1","status":"active"}`))
+	}))
+	defer server.Close()
+
+	adapter, err1 := provisioning.NewHTTPProvisioner(provisioning.Config{URL: server.URL, Token: "s3cr3t"})
+	if err1 != nil {
+		t.Fatalf("Expected err1 to be nil but it was: %s", err1)
+	}
+
+	// WHEN we activate a pack
+	provision, err2 := adapter.Activate("573001234567", "13")
+
+	// THEN the api receives the subscriber and product
+	if err2 != nil {
+		t.Fatalf("Expected err2 to be nil but it was: %s", err2)
+	}
+	if request["msisdn"] != "573001234567" || request["prodid"] != "13" {
+		t.Fatalf("Expected msisdn and prodid in request but got %v", request)
+	}
+	if provision.Reference != "ref-1" || provision.Status != model.ProvisionActive {
+		t.Fatalf("Expected active provision ref-1 but got %+v", provision)
+	}
+}
+
+// TestHTTPProvisionerErrors verify which answers can be retried.
+func TestHTTPProvisionerErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		temporary bool
+	}{
+		{name: "unavailable", status: http.StatusServiceUnavailable, temporary: true},
+		{name: "too many requests", status: http.StatusTooManyRequests, temporary: true},
+		{name: "bad request", status: http.StatusBadRequest, temporary: false},
+		{name: "not found", status: http.StatusNotFound, temporary: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+			adapter, _ := provisioning.NewHTTPProvisioner(provisioning.Config{URL: server.URL})
+
+			_, err := adapter.Activate("573001234567", "13")
+
+			if err == nil {
+				t.Fatalf("Expected an error but it was nil")
+			}
+			if provisioning.IsTemporary(err) != tt.temporary {
+				t.Errorf("IsTemporary() = %v, want %v", provisioning.IsTemporary(err), tt.temporary)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+// Package provisioning activates the packs sold in the network of the
+// mobile network operators.
+package provisioning
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/fernandoocampo/pack/model"
+)
+
+// IProvisioner defines the operations to manage packs in the network of an mno.
+type IProvisioner interface {
+	// Activate enables the mno product for the subscriber and returns
+	// the reference of the activation in the mno network.
+	Activate(msisdn string, prodid string) (*model.Provision, error)
+	// Deactivate disables a previous activation.
+	Deactivate(msisdn string, prodid string, reference string) error
+	// Status returns the status of a previous activation.
+	Status(msisdn string, prodid string, reference string) (model.ProvisionStatus, error)
+}
+
+// Config contains the parameters to build a provisioner for an mno.
+type Config struct {
+	MnoID    int8    `mapstructure:"mnoid"`    // mno served by the adapter
+	Kind     string  `mapstructure:"kind"`     // adapter kind. e.g. http, simulator.
+	URL      string  `mapstructure:"url"`      // base url of the mno api
+	Token    string  `mapstructure:"token"`    // credential sent to the mno api
+	Timeout  int     `mapstructure:"timeout"`  // seconds to wait for the mno api
+	Delay    int     `mapstructure:"delay"`    // milliseconds added to every call by the simulator
+	FailRate float64 `mapstructure:"failrate"` // ratio of calls that fail in the simulator, from 0 to 1
+}
+
+// Factory builds a provisioner from its configuration.
+type Factory func(config Config) (IProvisioner, error)
+
+// TemporaryError is returned when an operation failed but it may
+// succeed if it is retried. e.g. timeouts or unavailable services.
+type TemporaryError struct {
+	Err error
+}
+
+// ErrNoProvisioner is returned when there is no adapter for an mno.
+var ErrNoProvisioner = errors.New("there is no provisioner for the mno")
+
+var (
+	mutex     sync.RWMutex
+	factories = map[string]Factory{}
+	adapters  = map[int8]IProvisioner{}
+)
+
+func init() {
+	RegisterKind("http", NewHTTPProvisioner)
+	RegisterKind("simulator", NewSimulatorFromConfig)
+}
+
+// Error implements error interface.
+func (e *TemporaryError) Error() string {
+	return fmt.Sprintf("temporary provisioning error: %v", e.Err)
+}
+
+// IsTemporary checks if the given error, or an error it wraps, can be
+// retried.
+func IsTemporary(err error) bool {
+	var temporary *TemporaryError
+	return errors.As(err, &temporary)
+}
+
+// RegisterKind adds a new kind of adapter that can be configured.
+func RegisterKind(kind string, factory Factory) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	factories[kind] = factory
+}
+
+// Register sets the provisioner used for the given mno.
+func Register(mnoid int8, adapter IProvisioner) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	adapters[mnoid] = adapter
+}
+
+// Configure builds the adapter described by the config and registers it
+// for its mno.
+func Configure(config Config) error {
+	mutex.RLock()
+	factory, ok := factories[config.Kind]
+	mutex.RUnlock()
+	if !ok {
+		return fmt.Errorf("unknown provisioner kind: %s", config.Kind)
+	}
+	adapter, err := factory(config)
+	if err != nil {
+		return err
+	}
+	Register(config.MnoID, adapter)
+	log.Infof("provisioner %s registered for mno %d", config.Kind, config.MnoID)
+	return nil
+}
+
+// Get returns the provisioner registered for the given mno.
+func Get(mnoid int8) (IProvisioner, error) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	adapter, ok := adapters[mnoid]
+	if !ok {
+		return nil, ErrNoProvisioner
+	}
+	return adapter, nil
+}
@@ -0,0 +1,106 @@
+package provisioning
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/fernandoocampo/pack/model"
+)
+
+// Simulator implements IProvisioner in memory, it is meant for local
+// environments and tests. Delays and failures can be injected.
+type Simulator struct {
+	mutex       sync.Mutex
+	delay       time.Duration
+	failrate    float64
+	failnext    int
+	sequence    int
+	activations map[string]model.ProvisionStatus
+}
+
+// NewSimulator creates a Simulator that waits delay on every call and
+// fails the given ratio of calls with temporary errors.
+func NewSimulator(delay time.Duration, failrate float64) *Simulator {
+	return &Simulator{
+		delay:       delay,
+		failrate:    failrate,
+		activations: map[string]model.ProvisionStatus{},
+	}
+}
+
+// NewSimulatorFromConfig creates a Simulator from its configuration.
+func NewSimulatorFromConfig(config Config) (IProvisioner, error) {
+	if config.FailRate < 0 || config.FailRate > 1 {
+		return nil, errors.New("simulator failrate must be between 0 and 1")
+	}
+	return NewSimulator(time.Duration(config.Delay)*time.Millisecond, config.FailRate), nil
+}
+
+// FailNext makes the next n calls fail with a temporary error.
+func (s *Simulator) FailNext(n int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.failnext = n
+}
+
+// Activate implements IProvisioner.Activate.
+func (s *Simulator) Activate(msisdn string, prodid string) (*model.Provision, error) {
+	err := s.simulate()
+	if err != nil {
+		return nil, err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.sequence++
+	reference := fmt.Sprintf("sim-%s-%s-%d", msisdn, prodid, s.sequence)
+	s.activations[reference] = model.ProvisionActive
+	return &model.Provision{Reference: reference, Status: model.ProvisionActive}, nil
+}
+
+// Deactivate implements IProvisioner.Deactivate.
+func (s *Simulator) Deactivate(msisdn string, prodid string, reference string) error {
+	err := s.simulate()
+	if err != nil {
+		return err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, ok := s.activations[reference]; !ok {
+		return fmt.Errorf("activation %s does not exist", reference)
+	}
+	s.activations[reference] = model.ProvisionInactive
+	return nil
+}
+
+// Status implements IProvisioner.Status.
+func (s *Simulator) Status(msisdn string, prodid string, reference string) (model.ProvisionStatus, error) {
+	err := s.simulate()
+	if err != nil {
+		return model.ProvisionUnknown, err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.activations[reference], nil
+}
+
+// simulate waits the configured delay and decides if the call fails.
+func (s *Simulator) simulate() error {
+	s.mutex.Lock()
+	delay := s.delay
+	fail := s.failnext > 0 || (s.failrate > 0 && rand.Float64() < s.failrate)
+	if s.failnext > 0 {
+		s.failnext--
+	}
+	s.mutex.Unlock()
+
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+	if fail {
+		return &TemporaryError{Err: errors.New("simulated failure")}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package provisioning_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/provisioning"
+)
+
+// TestSimulatorActivate verify the activation lifecycle in the simulator.
+func TestSimulatorActivate(t *testing.T) {
+	// GIVEN a simulator without failures
+	simulator := provisioning.NewSimulator(0, 0)
+
+	// WHEN we activate a pack
+	provision, err1 := simulator.Activate("573001234567", "13")
+
+	// THEN we get a reference of an active provision
+	if err1 != nil {
+		t.Fatalf("Expected err1 to be nil but it was: %s", err1)
+	}
+	if provision.Reference == "" || provision.Status != model.ProvisionActive {
+		t.Fatalf("Expected an active provision with reference but got %+v", provision)
+	}
+
+	// AND it can be deactivated
+	err2 := simulator.Deactivate("573001234567", "13", provision.Reference)
+	if err2 != nil {
+		t.Fatalf("Expected err2 to be nil but it was: %s", err2)
+	}
+	status, err3 := simulator.Status("573001234567", "13", provision.Reference)
+	if err3 != nil {
+		t.Fatalf("Expected err3 to be nil but it was: %s", err3)
+	}
+	if status != model.ProvisionInactive {
+		t.Fatalf("Expected status %d but got %d", model.ProvisionInactive, status)
+	}
+}
+
+// TestSimulatorFailures verify that injected failures are temporary errors.
+func TestSimulatorFailures(t *testing.T) {
+	// GIVEN a simulator that fails the next 2 calls
+	simulator := provisioning.NewSimulator(0, 0)
+	simulator.FailNext(2)
+
+	// WHEN we activate a pack 3 times
+	_, err1 := simulator.Activate("573001234567", "13")
+	_, err2 := simulator.Activate("573001234567", "13")
+	_, err3 := simulator.Activate("573001234567", "13")
+
+	// THEN the first two fail with a temporary error, also when an
+	// adapter wraps it
+	if !provisioning.IsTemporary(err1) || !provisioning.IsTemporary(fmt.Errorf("mno 2: %w", err2)) {
+		t.Fatalf("Expected temporary errors but got: %v, %v", err1, err2)
+	}
+	if err3 != nil {
+		t.Fatalf("Expected err3 to be nil but it was: %s", err3)
+	}
+}
+
+// TestSimulatorDelay verify that the simulator waits the injected delay.
+func TestSimulatorDelay(t *testing.T) {
+	simulator := provisioning.NewSimulator(20*time.Millisecond, 0)
+
+	start := time.Now()
+	_, err := simulator.Activate("573001234567", "13")
+
+	if err != nil {
+		t.Fatalf("Expected err to be nil but it was: %s", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Fatalf("Expected activation to take at least 20ms but it took %s", elapsed)
+	}
+}
+
+// TestConfigure verify that adapters are registered per mno.
+func TestConfigure(t *testing.T) {
+	// GIVEN the configuration of a simulator for mno 9
+	config := provisioning.Config{MnoID: 9, Kind: "simulator"}
+
+	// WHEN we configure it
+	err1 := provisioning.Configure(config)
+
+	// THEN it is returned for mno 9 only
+	if err1 != nil {
+		t.Fatalf("Expected err1 to be nil but it was: %s", err1)
+	}
+	adapter, err2 := provisioning.Get(9)
+	if err2 != nil || adapter == nil {
+		t.Fatalf("Expected an adapter for mno 9 but got: %v", err2)
+	}
+	_, err3 := provisioning.Get(10)
+	if err3 != provisioning.ErrNoProvisioner {
+		t.Fatalf("Expected ErrNoProvisioner for mno 10 but got: %v", err3)
+	}
+	err4 := provisioning.Configure(provisioning.Config{MnoID: 11, Kind: "pepe"})
+	if err4 == nil {
+		t.Fatalf("Expected an error for an unknown kind but it was nil")
+	}
+}
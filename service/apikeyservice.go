@@ -0,0 +1,13 @@
+package service
+
+import "github.com/fernandoocampo/pack/model"
+
+// IAPIKeyService defines the behavior of the api keys of machine callers.
+type IAPIKeyService interface {
+	// Create generates a new api key, the key is returned only once.
+	Create(name string, roles []string, ownerid int, mnoid int8, channel string) (*model.APIKey, string, error)
+	// GetAll returns every api key without its secret.
+	GetAll() ([]model.APIKey, error)
+	// Revoke stops accepting an api key.
+	Revoke(id string) error
+}
@@ -0,0 +1,15 @@
+package service
+
+import "github.com/fernandoocampo/pack/model"
+
+// IAvailabilityService defines the behavior of the catalog of sales
+// channels and regions where packs are sold.
+type IAvailabilityService interface {
+	// Add registers a new channel or region.
+	Add(code *model.AvailabilityCode) error
+	// GetAll returns the channels or regions of the catalog.
+	GetAll(kind string) ([]model.AvailabilityCode, error)
+	// Remove removes a channel or region from the catalog, packs that
+	// have it keep it until their availability is changed.
+	Remove(kind string, code string) error
+}
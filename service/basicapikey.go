@@ -0,0 +1,55 @@
+package service
+
+import (
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/model"
+)
+
+// apiKeyDAO makes references to api key DAO
+var apiKeyDAO dao.IAPIKeyDAO
+
+// BasicAPIKey implements the behaviour of IAPIKeyService.
+type BasicAPIKey struct {
+}
+
+// Create implements IAPIKeyService.Create.
+func (m *BasicAPIKey) Create(name string, roles []string, ownerid int, mnoid int8, channel string) (*model.APIKey, string, error) {
+	if name == "" || ownerid < 0 || mnoid < 0 {
+		return nil, "", ErrAPIKeyInvalid
+	}
+	channel = model.NormalizeAvailabilityCode(channel)
+	if channel != "" {
+		err := validateCodes(model.AvailabilityChannel, []string{channel}, ErrChannelUnknown.WithField("channel"))
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	key, plain, err := model.NewAPIKey(name, roles, ownerid, mnoid)
+	if err != nil {
+		return nil, "", err
+	}
+	key.Channel = channel
+	err = apiKeyDAO.Create(key)
+	if err != nil {
+		return nil, "", err
+	}
+	return key, plain, nil
+}
+
+// GetAll implements IAPIKeyService.GetAll.
+func (m *BasicAPIKey) GetAll() ([]model.APIKey, error) {
+	return apiKeyDAO.GetAll()
+}
+
+// Revoke implements IAPIKeyService.Revoke.
+func (m *BasicAPIKey) Revoke(id string) error {
+	if id == "" {
+		return ErrAPIKeyIDInvalid
+	}
+	return apiKeyDAO.Revoke(id)
+}
+
+// SetAPIKeyDAO set the api key dao for this business logic.
+func SetAPIKeyDAO(dao dao.IAPIKeyDAO) {
+	apiKeyDAO = dao
+}
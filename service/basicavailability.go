@@ -0,0 +1,93 @@
+package service
+
+import (
+	"time"
+
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/model"
+)
+
+// availabilityDAO makes references to availability DAO
+var availabilityDAO dao.IAvailabilityDAO
+
+// BasicAvailability implements the behaviour of IAvailabilityService.
+type BasicAvailability struct {
+}
+
+// Add implements IAvailabilityService.Add.
+func (m *BasicAvailability) Add(code *model.AvailabilityCode) error {
+	if code == nil {
+		return ErrAvailabilityCodeInvalid
+	}
+	if field := code.InvalidField(); field != "" {
+		return ErrAvailabilityCodeInvalid.WithField(field)
+	}
+
+	existing, err := availabilityDAO.GetByCode(code.Kind, code.Code)
+	if err != nil {
+		return ErrAvailabilityNotValidated.Wrap(err)
+	}
+	if existing != nil {
+		return ErrAvailabilityDuplicated
+	}
+
+	code.Created = time.Now()
+	return availabilityDAO.Create(code)
+}
+
+// GetAll implements IAvailabilityService.GetAll.
+func (m *BasicAvailability) GetAll(kind string) ([]model.AvailabilityCode, error) {
+	if kind != model.AvailabilityChannel && kind != model.AvailabilityRegion {
+		return nil, ErrAvailabilityCodeInvalid.WithField("kind")
+	}
+	return availabilityDAO.GetAll(kind)
+}
+
+// Remove implements IAvailabilityService.Remove.
+func (m *BasicAvailability) Remove(kind string, code string) error {
+	code = model.NormalizeAvailabilityCode(code)
+	existing, err := availabilityDAO.GetByCode(kind, code)
+	if err != nil {
+		return ErrAvailabilityNotValidated.Wrap(err)
+	}
+	if existing == nil {
+		return ErrAvailabilityNotFound
+	}
+	return availabilityDAO.Delete(kind, code)
+}
+
+// validateAvailability checks that the given channels and regions are in
+// the catalog. Empty lists mean everywhere and need no catalog.
+func validateAvailability(channels []string, regions []string) error {
+	err := validateCodes(model.AvailabilityChannel, channels, ErrChannelUnknown)
+	if err != nil {
+		return err
+	}
+	return validateCodes(model.AvailabilityRegion, regions, ErrRegionUnknown)
+}
+
+// validateCodes returns unknown if a code of the given kind is not in the
+// catalog.
+func validateCodes(kind string, codes []string, unknown *Error) error {
+	if len(codes) == 0 {
+		return nil
+	}
+	if availabilityDAO == nil {
+		return unknown
+	}
+	for _, code := range codes {
+		existing, err := availabilityDAO.GetByCode(kind, code)
+		if err != nil {
+			return ErrAvailabilityNotValidated.Wrap(err)
+		}
+		if existing == nil {
+			return unknown
+		}
+	}
+	return nil
+}
+
+// SetAvailabilityDAO set the availability dao for this business logic.
+func SetAvailabilityDAO(dao dao.IAvailabilityDAO) {
+	availabilityDAO = dao
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/model"
+)
+
+// availabilityMemDAO keeps the channels and regions in memory.
+type availabilityMemDAO struct {
+	dao.IAvailabilityDAO
+	codes []model.AvailabilityCode
+}
+
+func (d *availabilityMemDAO) Create(code *model.AvailabilityCode) error {
+	d.codes = append(d.codes, *code)
+	return nil
+}
+
+func (d *availabilityMemDAO) GetByCode(kind string, code string) (*model.AvailabilityCode, error) {
+	for i := range d.codes {
+		if d.codes[i].Kind == kind && d.codes[i].Code == code {
+			return &d.codes[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// availabilityPackDAO keeps the packs in memory with the method to
+// change their availability.
+type availabilityPackDAO struct {
+	clonePackDAO
+}
+
+func (d *availabilityPackDAO) UpdateAvailability(id string, channels []string, regions []string) error {
+	pack, _ := d.GetByID(id)
+	pack.Channels = channels
+	pack.Regions = regions
+	return nil
+}
+
+// TestAddAvailabilityCode tests the validations of a new channel or region
+func TestAddAvailabilityCode(t *testing.T) {
+	codes := &availabilityMemDAO{}
+	SetAvailabilityDAO(codes)
+	defer SetAvailabilityDAO(nil)
+
+	tests := []struct {
+		name string
+		code *model.AvailabilityCode
+		want *Error
+	}{
+		{name: "channel", code: &model.AvailabilityCode{Kind: model.AvailabilityChannel, Code: "app", Name: "App"}},
+		{name: "same code in regions", code: &model.AvailabilityCode{Kind: model.AvailabilityRegion, Code: "app", Name: "App"}},
+		{name: "duplicated", code: &model.AvailabilityCode{Kind: model.AvailabilityChannel, Code: "app", Name: "Apps"}, want: ErrAvailabilityDuplicated},
+		{name: "invalid code", code: &model.AvailabilityCode{Kind: model.AvailabilityChannel, Code: "Web!", Name: "Web"}, want: ErrAvailabilityCodeInvalid},
+		{name: "no code", code: nil, want: ErrAvailabilityCodeInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := new(BasicAvailability).Add(tt.code)
+			if (tt.want == nil && err != nil) || (tt.want != nil && !tt.want.Is(err)) {
+				t.Errorf("BasicAvailability.Add() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+	if len(codes.codes) != 2 || codes.codes[0].Created.IsZero() {
+		t.Errorf("Expected two codes with their creation time but got %+v", codes.codes)
+	}
+	err := new(BasicAvailability).Remove(model.AvailabilityRegion, "north")
+	if !ErrAvailabilityNotFound.Is(err) {
+		t.Errorf("Expected region north not to be found but got %v", err)
+	}
+}
+
+// TestSetAvailability tests only channels and regions of the catalog can
+// be given to a pack
+func TestSetAvailability(t *testing.T) {
+	pack := newClonePack()
+	packs := &availabilityPackDAO{clonePackDAO{packs: []*model.Pack{pack}}}
+	codes := &availabilityMemDAO{codes: []model.AvailabilityCode{
+		{Kind: model.AvailabilityChannel, Code: "app"}, {Kind: model.AvailabilityRegion, Code: "north"}}}
+	SetPackDAO(packs)
+	SetAvailabilityDAO(codes)
+	defer SetPackDAO(nil)
+	defer SetAvailabilityDAO(nil)
+
+	tests := []struct {
+		name     string
+		channels []string
+		regions  []string
+		want     *Error
+	}{
+		{name: "unknown channel", channels: []string{"app", "retail"}, want: ErrChannelUnknown},
+		{name: "unknown region", channels: []string{"app"}, regions: []string{"south"}, want: ErrRegionUnknown},
+		{name: "known codes", channels: []string{"app"}, regions: []string{"north"}},
+		{name: "everywhere", channels: []string{}, regions: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := new(BasicPack).SetAvailability(pack.ID.Hex(), tt.channels, tt.regions)
+			if (tt.want == nil && err != nil) || (tt.want != nil && !tt.want.Is(err)) {
+				t.Fatalf("BasicPack.SetAvailability() error = %v, want %v", err, tt.want)
+			}
+			if tt.want == nil && len(pack.Channels) != len(tt.channels) {
+				t.Errorf("Expected channels %v but got %v", tt.channels, pack.Channels)
+			}
+		})
+	}
+
+	clone, err := new(BasicPack).Clone(pack.ID.Hex(), &model.PackOverrides{ProdID: "WAPP13", Packcode: "wh13"})
+	if err != nil || len(clone.Channels) != 0 {
+		t.Errorf("Expected a clone sold everywhere but got %+v, %v", clone, err)
+	}
+	pack.Regions = []string{"south"}
+	_, err = new(BasicPack).Clone(pack.ID.Hex(), &model.PackOverrides{ProdID: "WAPP14", Packcode: "wh14"})
+	if !ErrRegionUnknown.Is(err) {
+		t.Errorf("Expected the unknown region of the source to be rejected but got %v", err)
+	}
+}
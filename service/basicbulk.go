@@ -0,0 +1,236 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/model"
+)
+
+// bulkJobDAO makes references to bulk job DAO
+var bulkJobDAO dao.IBulkJobDAO
+
+// auditDAO makes references to audit DAO
+var auditDAO dao.IAuditDAO
+
+// limits of the bulk queries
+const (
+	bulkProgressEvery  = 50  // packs processed between two saves of the progress of a job
+	bulkChangeAttempts = 3   // times a pack changed meanwhile is read again before it fails
+	maxBulkJobs        = 50  // jobs returned by GetJobs
+	maxAuditEntries    = 100 // audit entries returned by GetAudit
+)
+
+// BasicBulk implements the behaviour of IBulkService.
+type BasicBulk struct {
+}
+
+// Preview implements IBulkService.Preview.
+func (m *BasicBulk) Preview(job *model.BulkJob) (*model.BulkJob, error) {
+	err := validateBulkJob(job)
+	if err != nil {
+		return nil, err
+	}
+	total, err := packDAO.Scoped(job.Tenant).Count(job.Filter)
+	if err != nil {
+		return nil, ErrBulkFailed.Wrap(err)
+	}
+	job.Total = total
+	job.Status = model.BulkPreview
+	return job, nil
+}
+
+// Start implements IBulkService.Start.
+func (m *BasicBulk) Start(job *model.BulkJob) (*model.BulkJob, error) {
+	job, err := m.Preview(job)
+	if err != nil {
+		return nil, err
+	}
+	job.Status = model.BulkPending
+	job.Created = time.Now()
+	err = bulkJobDAO.Create(job)
+	if err != nil {
+		return nil, ErrBulkFailed.Wrap(err)
+	}
+	// the caller gets the pending job while its copy runs
+	running := *job
+	go m.run(&running)
+	return job, nil
+}
+
+// GetJob implements IBulkService.GetJob.
+func (m *BasicBulk) GetJob(id string) (*model.BulkJob, error) {
+	if id == "" {
+		return nil, ErrBulkJobNotFound
+	}
+	job, err := bulkJobDAO.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, ErrBulkJobNotFound
+	}
+	return job, nil
+}
+
+// GetJobs implements IBulkService.GetJobs.
+func (m *BasicBulk) GetJobs(subject string) ([]model.BulkJob, error) {
+	return bulkJobDAO.GetBySubject(subject, maxBulkJobs)
+}
+
+// GetAudit implements IBulkService.GetAudit.
+func (m *BasicBulk) GetAudit(packid string) ([]model.AuditEntry, error) {
+	return auditDAO.GetByPack(packid, maxAuditEntries)
+}
+
+// InterruptRunning implements IBulkService.InterruptRunning.
+func (m *BasicBulk) InterruptRunning() (int, error) {
+	return bulkJobDAO.InterruptRunning()
+}
+
+// validateBulkJob checks the change of the job, a job without filter
+// changes every pack of its tenant.
+func validateBulkJob(job *model.BulkJob) error {
+	if job.Filter == nil {
+		job.Filter = new(model.PackFilter)
+	}
+	switch job.Kind {
+	case model.BulkUpdate:
+		if job.Patch.IsEmpty() {
+			return ErrBulkPatchEmpty
+		}
+		if field := job.Patch.InvalidField(); field != "" {
+			return ErrBulkPatchInvalid.WithField(field)
+		}
+	case model.BulkChangeState:
+		if job.State != model.Active && job.State != model.Inactive {
+			return ErrBulkStateInvalid
+		}
+	default:
+		return ErrInternal.Wrap(fmt.Errorf("unknown bulk job kind %q", job.Kind))
+	}
+	return nil
+}
+
+// run changes the packs of the job one by one and saves its progress. The
+// ids are read first so the changed packs are not read again.
+func (m *BasicBulk) run(job *model.BulkJob) {
+	packs := packDAO.Scoped(job.Tenant)
+	ids := []string{}
+	err := packs.Each(job.Filter, func(pack *model.Pack) error {
+		ids = append(ids, pack.ID.Hex())
+		return nil
+	})
+	if err != nil {
+		log.Errorf("reading the packs of bulk job %s: %v", job.ID.Hex(), err)
+		job.Status = model.BulkInterrupted
+		job.Finished = time.Now()
+		saveBulkJob(job)
+		return
+	}
+
+	job.Status = model.BulkRunning
+	job.Total = len(ids)
+	job.Started = time.Now()
+	saveBulkJob(job)
+	for i, id := range ids {
+		m.change(packs, job, id)
+		if (i+1)%bulkProgressEvery == 0 {
+			saveBulkJob(job)
+		}
+	}
+	job.Status = model.BulkDone
+	job.Finished = time.Now()
+	saveBulkJob(job)
+	log.Infof("bulk job %s changed %d of %d packs, %d failed", job.ID.Hex(), job.Succeeded, job.Total, job.Failed)
+}
+
+// change applies the change of the job to a pack and audits it. Packs that
+// were removed, stopped matching the filter or already had the change are
+// skipped. The pack is written at the version it was read, a pack changed
+// meanwhile is read again and the change applied again.
+func (m *BasicBulk) change(packs dao.IPackDAO, job *model.BulkJob, id string) {
+	for attempt := 1; ; attempt++ {
+		pack, err := packs.GetByID(id)
+		if err != nil {
+			log.Errorf("reading pack %s of bulk job %s: %v", id, job.ID.Hex(), err)
+			job.AddFailure(id, "", ErrBulkPackFailed.Detail(job.Locale))
+			return
+		}
+		if pack == nil || !job.Filter.Matches(pack) {
+			job.Processed++
+			job.Skipped++
+			return
+		}
+
+		var changes []model.AuditChange
+		var action string
+		versioned := packs.AtVersion(pack.Version)
+		switch job.Kind {
+		case model.BulkUpdate:
+			action = model.AuditBulkUpdate
+			changes = job.Patch.Apply(pack)
+			if len(changes) > 0 {
+				err = versioned.Update(id, pack)
+			}
+		case model.BulkChangeState:
+			action = model.AuditBulkChangeState
+			if pack.State != job.State {
+				// bundles and their components are checked as changeState does
+				if failure := checkStateChange(pack, job.State); failure != nil {
+					job.AddFailure(id, pack.Packcode, AsError(failure).Detail(job.Locale))
+					return
+				}
+				changes = []model.AuditChange{model.NewAuditChange("state", pack.State, job.State)}
+				err = versioned.ChangeState(id, job.State)
+			}
+		}
+		if errors.Is(err, dao.ErrVersionChanged) && attempt < bulkChangeAttempts {
+			continue
+		}
+		if errors.Is(err, dao.ErrVersionChanged) {
+			job.AddFailure(id, pack.Packcode, ErrPackModified.Detail(job.Locale))
+			return
+		}
+		if err != nil {
+			log.Errorf("changing pack %s of bulk job %s: %v", id, job.ID.Hex(), err)
+			job.AddFailure(id, pack.Packcode, ErrBulkPackFailed.Detail(job.Locale))
+			return
+		}
+		job.Processed++
+		if len(changes) == 0 {
+			job.Skipped++
+			return
+		}
+		job.Succeeded++
+
+		entry := &model.AuditEntry{PackID: id, Action: action, Subject: job.Subject, JobID: job.ID.Hex(),
+			Changes: changes, Created: time.Now()}
+		err = auditDAO.Create(entry)
+		if err != nil {
+			log.Errorf("auditing pack %s of bulk job %s: %v", id, job.ID.Hex(), err)
+		}
+		return
+	}
+}
+
+// saveBulkJob stores the progress of the job, a failure is only logged as
+// the packs keep being changed.
+func saveBulkJob(job *model.BulkJob) {
+	err := bulkJobDAO.Update(job)
+	if err != nil {
+		log.Errorf("saving the progress of bulk job %s: %v", job.ID.Hex(), err)
+	}
+}
+
+// SetBulkJobDAO set the bulk job dao for this business logic.
+func SetBulkJobDAO(dao dao.IBulkJobDAO) {
+	bulkJobDAO = dao
+}
+
+// SetAuditDAO set the audit dao for this business logic.
+func SetAuditDAO(dao dao.IAuditDAO) {
+	auditDAO = dao
+}
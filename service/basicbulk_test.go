@@ -0,0 +1,283 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/model"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// bulkPackDAO keeps the packs in memory, the methods the bulk jobs do
+// not use are left to the embedded nil dao.
+type bulkPackDAO struct {
+	dao.IPackDAO
+	packs       []*model.Pack
+	failing     string         // id of the pack whose changes fail
+	interfering map[string]int // changes made by others to a pack before it is written
+	modified    map[string]int
+	version     int
+	versioned   bool
+}
+
+func (d *bulkPackDAO) Scoped(tenant *model.Tenant) dao.IPackDAO {
+	return d
+}
+
+func (d *bulkPackDAO) AtVersion(version int) dao.IPackDAO {
+	copied := *d
+	copied.version, copied.versioned = version, true
+	return &copied
+}
+
+func (d *bulkPackDAO) Count(filter *model.PackFilter) (int, error) {
+	count := 0
+	for _, pack := range d.packs {
+		if filter.Matches(pack) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (d *bulkPackDAO) Each(filter *model.PackFilter, fn func(pack *model.Pack) error) error {
+	for _, pack := range d.packs {
+		if filter.Matches(pack) {
+			copied := *pack
+			if err := fn(&copied); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (d *bulkPackDAO) GetByID(id string) (*model.Pack, error) {
+	for _, pack := range d.packs {
+		if pack.ID.Hex() == id {
+			copied := *pack
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (d *bulkPackDAO) Update(id string, packdata *model.Pack) error {
+	return d.modify(id, func(pack *model.Pack) { *pack = *packdata })
+}
+
+func (d *bulkPackDAO) ChangeState(id string, newstate model.PackState) error {
+	return d.modify(id, func(pack *model.Pack) { pack.State = newstate })
+}
+
+func (d *bulkPackDAO) GetBundlesOf(id string) ([]model.Pack, error) {
+	bundles := []model.Pack{}
+	for _, pack := range d.packs {
+		if pack.HasComponent(id) {
+			bundles = append(bundles, *pack)
+		}
+	}
+	return bundles, nil
+}
+
+func (d *bulkPackDAO) modify(id string, change func(pack *model.Pack)) error {
+	if id == d.failing {
+		return errors.New("no reachable servers")
+	}
+	for _, pack := range d.packs {
+		if pack.ID.Hex() != id {
+			continue
+		}
+		if d.interfering[id] > 0 {
+			d.interfering[id]--
+			pack.Version++
+		}
+		if d.versioned && pack.Version != d.version {
+			return dao.ErrVersionChanged
+		}
+		change(pack)
+		pack.Version++
+		d.modified[id]++
+	}
+	return nil
+}
+
+// bulkJobMemDAO keeps the last saved job.
+type bulkJobMemDAO struct {
+	dao.IBulkJobDAO
+	saved model.BulkJob
+	saves int
+}
+
+func (d *bulkJobMemDAO) Create(job *model.BulkJob) error {
+	job.ID = bson.NewObjectId()
+	d.saved = *job
+	return nil
+}
+
+func (d *bulkJobMemDAO) Update(job *model.BulkJob) error {
+	d.saved = *job
+	d.saves++
+	return nil
+}
+
+// auditMemDAO keeps the audit entries.
+type auditMemDAO struct {
+	dao.IAuditDAO
+	entries []*model.AuditEntry
+}
+
+func (d *auditMemDAO) Create(entry *model.AuditEntry) error {
+	d.entries = append(d.entries, entry)
+	return nil
+}
+
+func newBulkPacks() *bulkPackDAO {
+	packs := &bulkPackDAO{modified: map[string]int{}, interfering: map[string]int{}}
+	for i, price := range []int{1000, 2000, 3000} {
+		packs.packs = append(packs.packs, &model.Pack{ID: bson.NewObjectId(), Packcode: "bk" + string(rune('a'+i)),
+			Price: price, State: model.Active, Mno: &model.Mno{ID: 2}, Packtype: &model.Type{ID: 1, Name: "App"}})
+	}
+	packs.packs[2].Mno = &model.Mno{ID: 5}
+	return packs
+}
+
+// TestBulkUpdate tests every matching pack is changed, audited and
+// reported on its own
+func TestBulkUpdate(t *testing.T) {
+	// GIVEN two packs of the mno 2, the changes of the second one fail
+	packs := newBulkPacks()
+	packs.failing = packs.packs[1].ID.Hex()
+	jobs := new(bulkJobMemDAO)
+	audit := new(auditMemDAO)
+	SetPackDAO(packs)
+	SetBulkJobDAO(jobs)
+	SetAuditDAO(audit)
+	defer SetPackDAO(nil)
+	defer SetBulkJobDAO(nil)
+	defer SetAuditDAO(nil)
+	job := model.NewBulkUpdate(&model.PackFilter{MnoID: 2}, &model.PackPatch{PricePercent: 5})
+	job.Subject = "editor"
+
+	// WHEN their prices are raised 5%
+	preview, err := new(BasicBulk).Preview(job)
+	if err != nil || preview.Total != 2 || preview.Status != model.BulkPreview {
+		t.Fatalf("Expected a preview of 2 packs but got %+v, %v", preview, err)
+	}
+	job.ID = bson.NewObjectId()
+	new(BasicBulk).run(job)
+
+	// THEN the first one is changed and audited and the second one failed
+	saved := jobs.saved
+	if saved.Status != model.BulkDone || saved.Total != 2 || saved.Processed != 2 || saved.Succeeded != 1 || saved.Failed != 1 {
+		t.Fatalf("Expected a done job with a change and a failure but got %+v", saved)
+	}
+	if len(saved.Failures) != 1 || saved.Failures[0].Packcode != "bkb" || saved.Failures[0].Error.Code != ErrBulkPackFailed.Code {
+		t.Errorf("Expected the failure of pack bkb but got %+v", saved.Failures)
+	}
+	if packs.packs[0].Price != 1050 || packs.packs[2].Price != 3000 {
+		t.Errorf("Expected only the price of bka to change but got %d, %d", packs.packs[0].Price, packs.packs[2].Price)
+	}
+	if len(audit.entries) != 1 || audit.entries[0].JobID != job.ID.Hex() || audit.entries[0].Subject != "editor" {
+		t.Fatalf("Expected an audit entry of the job but got %+v", audit.entries)
+	}
+	want := model.AuditChange{Field: "price", Before: "1000", After: "1050"}
+	if changes := audit.entries[0].Changes; len(changes) != 1 || changes[0] != want {
+		t.Errorf("Expected the price change in the audit but got %+v", changes)
+	}
+}
+
+// TestBulkChangeState tests packs that already have the state are skipped
+func TestBulkChangeState(t *testing.T) {
+	// GIVEN three packs and the first one is inactive
+	packs := newBulkPacks()
+	packs.packs[0].State = model.Inactive
+	jobs := new(bulkJobMemDAO)
+	audit := new(auditMemDAO)
+	SetPackDAO(packs)
+	SetBulkJobDAO(jobs)
+	SetAuditDAO(audit)
+	defer SetPackDAO(nil)
+	defer SetBulkJobDAO(nil)
+	defer SetAuditDAO(nil)
+
+	// WHEN every pack is suspended
+	job := model.NewBulkChangeState(nil, model.Inactive)
+	job, err := new(BasicBulk).Preview(job)
+	if err != nil {
+		t.Fatalf("Expected err to be nil but it was: %s", err)
+	}
+	job.ID = bson.NewObjectId()
+	new(BasicBulk).run(job)
+
+	// THEN the other two are changed
+	saved := jobs.saved
+	if saved.Status != model.BulkDone || saved.Succeeded != 2 || saved.Skipped != 1 || saved.Failed != 0 {
+		t.Fatalf("Expected two changes and a skipped pack but got %+v", saved)
+	}
+	for _, pack := range packs.packs {
+		if pack.State != model.Inactive {
+			t.Errorf("Expected pack %s to be inactive", pack.Packcode)
+		}
+	}
+	if len(audit.entries) != 2 || audit.entries[0].Action != model.AuditBulkChangeState {
+		t.Errorf("Expected two state changes in the audit but got %+v", audit.entries)
+	}
+}
+
+// TestBulkPreviewInvalid tests jobs that cannot be started
+func TestBulkPreviewInvalid(t *testing.T) {
+	negative := -5
+	tests := []struct {
+		name string
+		job  *model.BulkJob
+		want *Error
+	}{
+		{name: "empty patch", job: model.NewBulkUpdate(nil, &model.PackPatch{}), want: ErrBulkPatchEmpty},
+		{name: "negative price", job: model.NewBulkUpdate(nil, &model.PackPatch{Price: &negative}), want: ErrBulkPatchInvalid},
+		{name: "unknown state", job: model.NewBulkChangeState(nil, model.PackState(4)), want: ErrBulkStateInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := new(BasicBulk).Preview(tt.job)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("BasicBulk.Preview() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestBulkUpdateChangedMeanwhile tests a pack changed by someone else
+// while the job changes it is read again, and fails if it keeps changing
+func TestBulkUpdateChangedMeanwhile(t *testing.T) {
+	// GIVEN a pack changed once by someone else and another one that keeps
+	// being changed
+	packs := newBulkPacks()
+	packs.interfering[packs.packs[0].ID.Hex()] = 1
+	packs.interfering[packs.packs[1].ID.Hex()] = bulkChangeAttempts
+	jobs := new(bulkJobMemDAO)
+	SetPackDAO(packs)
+	SetBulkJobDAO(jobs)
+	SetAuditDAO(new(auditMemDAO))
+	defer SetPackDAO(nil)
+	defer SetBulkJobDAO(nil)
+	defer SetAuditDAO(nil)
+
+	// WHEN their prices are raised 5%
+	job := model.NewBulkUpdate(&model.PackFilter{MnoID: 2}, &model.PackPatch{PricePercent: 5})
+	job.ID = bson.NewObjectId()
+	new(BasicBulk).run(job)
+
+	// THEN the first one is changed once and the second one failed as modified
+	saved := jobs.saved
+	if saved.Succeeded != 1 || saved.Failed != 1 || packs.packs[0].Price != 1050 || packs.modified[packs.packs[0].ID.Hex()] != 1 {
+		t.Fatalf("Expected bka changed once and a failure but got %+v %d", saved, packs.packs[0].Price)
+	}
+	if len(saved.Failures) != 1 || saved.Failures[0].Packcode != "bkb" || saved.Failures[0].Error.Code != ErrPackModified.Code {
+		t.Errorf("Expected bkb to fail as modified but got %+v", saved.Failures)
+	}
+	if packs.packs[1].Price != 2000 {
+		t.Errorf("Expected the price of bkb to be kept but got %d", packs.packs[1].Price)
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"time"
+
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/model"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// commissionDAO makes references to commission DAO
+var commissionDAO dao.ICommissionDAO
+
+// BasicCommission implements the behaviour of ICommissionService.
+type BasicCommission struct {
+}
+
+// AddRule implements ICommissionService.AddRule.
+func (m *BasicCommission) AddRule(rule *model.CommissionRule) error {
+	if rule == nil || rule.Ownerid <= 0 || rule.Value < 0 {
+		return ErrCommissionRuleInvalid
+	}
+	if rule.Kind != model.CommissionPercentage && rule.Kind != model.CommissionFixed {
+		return ErrCommissionRuleInvalid
+	}
+	if rule.Kind == model.CommissionPercentage && rule.Value > 100 {
+		return ErrCommissionRuleInvalid
+	}
+	if rule.PackID != "" && !bson.IsObjectIdHex(rule.PackID) {
+		return ErrCommissionRuleInvalid
+	}
+	rule.Created = time.Now()
+	return commissionDAO.Create(rule)
+}
+
+// DeleteRule implements ICommissionService.DeleteRule.
+func (m *BasicCommission) DeleteRule(id string) error {
+	if id == "" {
+		return ErrCommissionRuleIDEmpty
+	}
+	return commissionDAO.Delete(id)
+}
+
+// GetRule implements ICommissionService.GetRule.
+func (m *BasicCommission) GetRule(id string) (*model.CommissionRule, error) {
+	if id == "" {
+		return nil, ErrCommissionRuleIDEmpty
+	}
+	return commissionDAO.GetByID(id)
+}
+
+// GetRules implements ICommissionService.GetRules.
+func (m *BasicCommission) GetRules(ownerid int) ([]model.CommissionRule, error) {
+	if ownerid <= 0 {
+		return []model.CommissionRule{}, nil
+	}
+	return commissionDAO.GetByOwner(ownerid)
+}
+
+// commissionOf returns the commission the owner of the pack earns when it
+// is sold at the given price. Packs without owner earn no commission.
+func commissionOf(pack *model.Pack, price int) (int, error) {
+	if pack.Ownerid <= 0 || commissionDAO == nil {
+		return 0, nil
+	}
+	rules, err := commissionDAO.GetByOwner(pack.Ownerid)
+	if err != nil {
+		return 0, err
+	}
+	return model.SelectCommissionRule(rules, pack).Compute(price), nil
+}
+
+// SetCommissionDAO set the commission dao for this business logic.
+func SetCommissionDAO(dao dao.ICommissionDAO) {
+	commissionDAO = dao
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"time"
+
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/model"
+)
+
+// entitlementDAO makes references to entitlement DAO
+var entitlementDAO dao.IEntitlementDAO
+
+// BasicEntitlement implements the behaviour of IEntitlementService.
+type BasicEntitlement struct {
+}
+
+// Grant implements IEntitlementService.Grant.
+func (m *BasicEntitlement) Grant(msisdn string, packid string) (*model.Entitlement, error) {
+	if !isValidMsisdn(msisdn) || packid == "" {
+		return nil, ErrGrantArgs
+	}
+
+	pack, err := packDAO.GetByID(packid)
+	if err != nil {
+		return nil, ErrPackNotValidated.Wrap(err)
+	}
+	if pack == nil {
+		return nil, ErrPackNotFound
+	}
+	return grantPack(msisdn, pack)
+}
+
+// grantPack creates the entitlement of the given subscriber for an
+// already loaded pack.
+func grantPack(msisdn string, pack *model.Pack) (*model.Entitlement, error) {
+	if pack.State != model.Active {
+		return nil, ErrPackNotActive
+	}
+
+	entitlement := model.NewEntitlement(msisdn, pack, time.Now())
+	err := entitlementDAO.Create(entitlement)
+	if err != nil {
+		return nil, err
+	}
+	return entitlement, nil
+}
+
+// GetByMsisdn implements IEntitlementService.GetByMsisdn.
+func (m *BasicEntitlement) GetByMsisdn(msisdn string, onlyopen bool) ([]model.Entitlement, error) {
+	if msisdn == "" {
+		return []model.Entitlement{}, nil
+	}
+
+	return entitlementDAO.GetByMsisdn(msisdn, onlyopen)
+}
+
+// Balances implements IEntitlementService.Balances.
+func (m *BasicEntitlement) Balances(msisdn string) ([]model.ResourceBalance, error) {
+	if msisdn == "" {
+		return []model.ResourceBalance{}, nil
+	}
+
+	entitlements, err := entitlementDAO.GetByMsisdn(msisdn, true)
+	if err != nil {
+		return nil, err
+	}
+	return model.SumBalances(entitlements, time.Now()), nil
+}
+
+// Consume implements IEntitlementService.Consume. When no single
+// entitlement has enough balance the amount is split among them.
+func (m *BasicEntitlement) Consume(msisdn string, resourceid int16, amount float32) error {
+	if msisdn == "" || resourceid < 1 || amount <= 0 {
+		return ErrConsumeArgs
+	}
+
+	entitlements, err := entitlementDAO.GetByMsisdn(msisdn, true)
+	if err != nil {
+		return err
+	}
+
+	var available float32
+	for _, entitlement := range entitlements {
+		available += entitlement.Remaining(resourceid)
+	}
+	if available < amount {
+		return ErrNotEnoughBalance
+	}
+
+	pending := amount
+	consumed := map[string]float32{}
+	for _, entitlement := range entitlements {
+		remaining := entitlement.Remaining(resourceid)
+		if remaining <= 0 {
+			continue
+		}
+		portion := pending
+		if remaining < portion {
+			portion = remaining
+		}
+		ok, err := entitlementDAO.Consume(entitlement.ID.Hex(), resourceid, portion)
+		if err != nil {
+			giveBack(consumed, resourceid)
+			return err
+		}
+		if !ok {
+			// balance changed or the entitlement expired since it was read.
+			continue
+		}
+		consumed[entitlement.ID.Hex()] = portion
+		pending -= portion
+		if pending <= 0 {
+			return nil
+		}
+	}
+
+	// the usage is not made, so the portions already taken are given back
+	giveBack(consumed, resourceid)
+	return ErrNotEnoughBalance
+}
+
+// giveBack returns the portions consumed from every entitlement of a
+// usage that could not be made.
+func giveBack(consumed map[string]float32, resourceid int16) {
+	for id, portion := range consumed {
+		err := entitlementDAO.GiveBack(id, resourceid, portion)
+		if err != nil {
+			log.Errorf("%g of resource %d consumed from entitlement %s cannot be given back: %v", portion, resourceid, id, err)
+		}
+	}
+}
+
+// CloseExpired implements IEntitlementService.CloseExpired.
+func (m *BasicEntitlement) CloseExpired() (int, error) {
+	return entitlementDAO.CloseExpired(time.Now())
+}
+
+// isValidMsisdn checks that msisdn is a line number in international
+// format, only digits with an optional leading plus.
+func isValidMsisdn(msisdn string) bool {
+	if len(msisdn) > 0 && msisdn[0] == '+' {
+		msisdn = msisdn[1:]
+	}
+	if len(msisdn) < 7 || len(msisdn) > 15 {
+		return false
+	}
+	for _, digit := range msisdn {
+		if digit < '0' || digit > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// SetEntitlementDAO set the entitlement dao for this business logic.
+func SetEntitlementDAO(dao dao.IEntitlementDAO) {
+	entitlementDAO = dao
+}
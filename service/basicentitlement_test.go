@@ -0,0 +1,65 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/model"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// consumeEntitlementDAO keeps the entitlements in memory, the consume of
+// the entitlements in rejected fails as if their balance changed.
+type consumeEntitlementDAO struct {
+	dao.IEntitlementDAO
+	entitlements []model.Entitlement
+	rejected     map[string]bool
+	used         map[string]float32
+}
+
+func (d *consumeEntitlementDAO) GetByMsisdn(msisdn string, onlyopen bool) ([]model.Entitlement, error) {
+	return d.entitlements, nil
+}
+
+func (d *consumeEntitlementDAO) Consume(id string, resourceid int16, amount float32) (bool, error) {
+	if d.rejected[id] {
+		return false, nil
+	}
+	d.used[id] += amount
+	return true, nil
+}
+
+func (d *consumeEntitlementDAO) GiveBack(id string, resourceid int16, amount float32) error {
+	d.used[id] -= amount
+	return nil
+}
+
+// TestConsumeGivesBack tests the portions of a usage that cannot be made
+// are given back to their entitlements
+func TestConsumeGivesBack(t *testing.T) {
+	// GIVEN two entitlements with 100 mb each, the second one changed
+	// since it was read
+	entitlements := []model.Entitlement{}
+	for i := 0; i < 2; i++ {
+		entitlement := model.Entitlement{ID: bson.NewObjectId(), State: model.EntitlementOpen, Expires: time.Now().Add(time.Hour),
+			Balances: []model.Balance{{ResourceID: 1, Granted: 100, Remaining: 100}}}
+		entitlements = append(entitlements, entitlement)
+	}
+	first, second := entitlements[0].ID.Hex(), entitlements[1].ID.Hex()
+	entitlementdao := &consumeEntitlementDAO{entitlements: entitlements, rejected: map[string]bool{second: true}, used: map[string]float32{}}
+	SetEntitlementDAO(entitlementdao)
+	defer SetEntitlementDAO(nil)
+
+	// WHEN 150 mb are consumed
+	err := new(BasicEntitlement).Consume("573001234567", 1, 150)
+
+	// THEN the usage fails and the first entitlement gets its 100 mb back
+	if !errors.Is(err, ErrNotEnoughBalance) {
+		t.Fatalf("Expected ErrNotEnoughBalance but got %v", err)
+	}
+	if entitlementdao.used[first] != 0 || entitlementdao.used[second] != 0 {
+		t.Errorf("Expected nothing consumed but got %v", entitlementdao.used)
+	}
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/events"
+	"github.com/fernandoocampo/pack/model"
+)
+
+// outboxDAO makes references to outbox DAO
+var outboxDAO dao.IOutboxDAO
+
+// relayBatchSize is the number of packs whose events are relayed at once.
+const relayBatchSize = 100
+
+// BasicEvent implements the behaviour of IEventService.
+type BasicEvent struct {
+}
+
+// Relay implements IEventService.Relay. It stops at the first event a sink
+// fails to publish, so the events of a pack are delivered in order.
+func (m *BasicEvent) Relay() (int, error) {
+	relayed := 0
+	for {
+		pending, err := outboxDAO.Pending(relayBatchSize)
+		if err != nil {
+			return relayed, err
+		}
+		delivered, publisherr := publish(pending, events.Sinks())
+		err = outboxDAO.Delivered(delivered)
+		if err != nil {
+			return relayed, err
+		}
+		relayed += len(delivered)
+		if publisherr != nil {
+			return relayed, publisherr
+		}
+		if len(delivered) == 0 {
+			return relayed, nil
+		}
+	}
+}
+
+// publish publishes the events to every sink until one fails, it returns
+// the events every sink published.
+func publish(pending []model.Event, sinks []events.ISink) ([]model.Event, error) {
+	for i := range pending {
+		for _, sink := range sinks {
+			err := sink.Publish(&pending[i])
+			if err != nil {
+				return pending[:i], err
+			}
+		}
+	}
+	return pending, nil
+}
+
+// SetOutboxDAO set the outbox dao for this business logic.
+func SetOutboxDAO(dao dao.IOutboxDAO) {
+	outboxDAO = dao
+}
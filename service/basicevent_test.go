@@ -0,0 +1,79 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/fernandoocampo/pack/events"
+	"github.com/fernandoocampo/pack/model"
+)
+
+// memOutboxDAO keeps the outbox in memory.
+type memOutboxDAO struct {
+	outbox []model.Event
+}
+
+func (d *memOutboxDAO) Pending(limit int) ([]model.Event, error) {
+	return append([]model.Event{}, d.outbox...), nil
+}
+
+func (d *memOutboxDAO) Delivered(delivered []model.Event) error {
+	left := []model.Event{}
+	for _, event := range d.outbox {
+		found := false
+		for _, done := range delivered {
+			found = found || done.ID == event.ID
+		}
+		if !found {
+			left = append(left, event)
+		}
+	}
+	d.outbox = left
+	return nil
+}
+
+// recordingSink records the ids of the events it publishes, it fails
+// from the event number failAt on.
+type recordingSink struct {
+	published []string
+	failAt    int
+}
+
+func (s *recordingSink) Publish(event *model.Event) error {
+	if s.failAt > 0 && len(s.published)+1 >= s.failAt {
+		return errors.New("sink is down")
+	}
+	s.published = append(s.published, event.ID.Hex())
+	return nil
+}
+
+// TestRelay tests events stay in the outbox until every sink publishes
+// them and are published again after a failure
+func TestRelay(t *testing.T) {
+	outbox := &memOutboxDAO{}
+	for _, eventtype := range []string{model.EventPackCreated, model.EventPackPriceChanged, model.EventStockMoved} {
+		outbox.outbox = append(outbox.outbox, model.NewEvent(eventtype, "p1", nil))
+	}
+	SetOutboxDAO(outbox)
+	defer SetOutboxDAO(nil)
+	first, second := &recordingSink{}, &recordingSink{failAt: 2}
+	events.Register("test-first", first)
+	events.Register("test-second", second)
+	defer events.Register("test-first", nil)
+	defer events.Register("test-second", nil)
+
+	relayed, err := new(BasicEvent).Relay()
+
+	if err == nil || relayed != 1 || len(outbox.outbox) != 2 {
+		t.Fatalf("Expected 1 event relayed and 2 left but got %d, %d: %v", relayed, len(outbox.outbox), err)
+	}
+	second.failAt = 0
+	relayed, err = new(BasicEvent).Relay()
+	if err != nil || relayed != 2 || len(outbox.outbox) != 0 {
+		t.Fatalf("Expected the 2 events left relayed but got %d, %d: %v", relayed, len(outbox.outbox), err)
+	}
+	if len(first.published) != 4 || len(second.published) != 3 || first.published[1] != first.published[2] {
+		t.Errorf("Expected the second event published twice to the first sink but got %v and %v",
+			first.published, second.published)
+	}
+}
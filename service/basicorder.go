@@ -0,0 +1,313 @@
+package service
+
+import (
+	"time"
+
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/provisioning"
+)
+
+// orderDAO makes references to order DAO
+var orderDAO dao.IOrderDAO
+
+// provisionLease is the time a due order is reserved for the process
+// that provisions it.
+const provisionLease = 5 * time.Minute
+
+// provisioning retries, the wait doubles after every attempt.
+var (
+	provisionAttempts = 3
+	provisionWait     = time.Second
+)
+
+// BasicOrder implements the behaviour of IOrderService.
+type BasicOrder struct {
+}
+
+// Purchase implements IOrderService.Purchase.
+func (m *BasicOrder) Purchase(msisdn string, packid string) (*model.Order, error) {
+	if !isValidMsisdn(msisdn) || packid == "" {
+		return nil, ErrPurchaseArgs
+	}
+
+	pack, err := packDAO.GetByID(packid)
+	if err != nil {
+		return nil, ErrPackNotValidated.Wrap(err)
+	}
+	if pack == nil {
+		return nil, ErrPackNotFound
+	}
+
+	order, _, err := placeOrder(msisdn, pack, pack.Price, model.OrderPurchase)
+	return order, err
+}
+
+// GetByID implements IOrderService.GetByID.
+func (m *BasicOrder) GetByID(id string) (*model.Order, error) {
+	if id == "" {
+		return &model.Order{}, nil
+	}
+
+	return orderDAO.GetByID(id)
+}
+
+// GetByMsisdn implements IOrderService.GetByMsisdn.
+func (m *BasicOrder) GetByMsisdn(msisdn string) ([]model.Order, error) {
+	if msisdn == "" {
+		return []model.Order{}, nil
+	}
+
+	return orderDAO.GetByMsisdn(msisdn)
+}
+
+// ProvisionStatus implements IOrderService.ProvisionStatus.
+func (m *BasicOrder) ProvisionStatus(id string) (model.ProvisionStatus, error) {
+	order, err := orderDAO.GetByID(id)
+	if err != nil {
+		return model.ProvisionUnknown, err
+	}
+	if order == nil || order.Reference == "" {
+		return model.ProvisionUnknown, ErrOrderNotProvisioned
+	}
+
+	adapter, err := provisioning.Get(order.MnoID)
+	if err != nil {
+		return model.ProvisionUnknown, ErrNoProvisioner
+	}
+	return adapter.Status(order.Msisdn, order.ProdID, order.Reference)
+}
+
+// Refund implements IOrderService.Refund.
+func (m *BasicOrder) Refund(id string, reason string) error {
+	order, err := orderDAO.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if order == nil {
+		return ErrOrderNotProvisioned
+	}
+	if !order.Refund(reason, time.Now()) {
+		return ErrOrderNotRefundable
+	}
+	return orderDAO.Update(order)
+}
+
+// placeOrder sells the pack to the subscriber at the given price. The
+// steps are reserve stock, activate in the mno network and grant the
+// entitlement, if a step fails the previous ones are undone. A temporary
+// failure of the activation leaves the order pending to be retried by
+// ProvisionDue, except for renewals that are retried by the subscription.
+func placeOrder(msisdn string, pack *model.Pack, price int, kind model.OrderKind) (*model.Order, *model.Entitlement, error) {
+	if pack.State != model.Active {
+		return nil, nil, ErrPackNotActive
+	}
+	if pack.Mno == nil {
+		return nil, nil, ErrNoProvisioner
+	}
+	adapter, err := provisioning.Get(pack.Mno.ID)
+	if err != nil {
+		return nil, nil, ErrNoProvisioner
+	}
+
+	order := model.NewOrder(msisdn, pack, price, kind)
+	order.Commission, err = commissionOf(pack, price)
+	if err != nil {
+		return nil, nil, ErrCommissionFailed.Wrap(err)
+	}
+	err = orderDAO.Create(order)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !reserveStock(order) {
+		return order, nil, failOrder(order, ErrOutOfStock, "pack is out of stock")
+	}
+
+	entitlement, err := provisionOrder(adapter, order, pack)
+	return order, entitlement, err
+}
+
+// ProvisionDue implements IOrderService.ProvisionDue.
+func (m *BasicOrder) ProvisionDue() (int, error) {
+	// orders that fail now are due later than this moment, so they are
+	// not taken again in this run.
+	now := time.Now()
+	processed := 0
+	for {
+		order, err := orderDAO.ClaimDue(now, provisionLease)
+		if err != nil {
+			return processed, err
+		}
+		if order == nil {
+			return processed, nil
+		}
+		err = retryOrder(order)
+		if err != nil {
+			return processed, err
+		}
+		processed++
+	}
+}
+
+// retryOrder provisions again a pending order whose stock is reserved, a
+// subscription order creates its subscription once it is completed unless
+// the subscriber already has one.
+func retryOrder(order *model.Order) error {
+	pack, err := packDAO.GetByID(order.PackID)
+	if err != nil {
+		return err
+	}
+	if pack == nil {
+		releaseStock(order)
+		failOrder(order, ErrPackNotFound, "pack does not exist")
+		return nil
+	}
+	adapter, err := provisioning.Get(order.MnoID)
+	if err != nil {
+		releaseStock(order)
+		failOrder(order, ErrNoProvisioner, err.Error())
+		return nil
+	}
+
+	entitlement, err := provisionOrder(adapter, order, pack)
+	if err != nil || order.Kind != model.OrderSubscription {
+		// the failure is kept in the order
+		return nil
+	}
+	subscribed, err := subscriptionDAO.IsSubscribed(order.Msisdn, order.PackID)
+	if err == nil && subscribed {
+		log.Warnf("order %s completed but the subscriber is already subscribed to pack %s", order.ID.Hex(), order.PackID)
+		return nil
+	}
+	if err == nil {
+		_, err = subscribe(order.Msisdn, pack, entitlement)
+	}
+	if err != nil {
+		log.Errorf("order %s completed but its subscription cannot be created: %v", order.ID.Hex(), err)
+	}
+	return nil
+}
+
+// provisionOrder activates the pack of an order whose stock is reserved
+// and grants it. An activation that failed for a temporary reason is
+// retried later while the order has attempts left, returning
+// ErrProvisionPending, otherwise the stock is released and the order fails.
+func provisionOrder(adapter provisioning.IProvisioner, order *model.Order, pack *model.Pack) (*model.Entitlement, error) {
+	order.Attempts++
+	provision, err := adapter.Activate(order.Msisdn, order.ProdID)
+	if err != nil {
+		log.Warnf("activation of order %s failed, attempt %d: %v", order.ID.Hex(), order.Attempts, err)
+		if provisioning.IsTemporary(err) && order.Kind != model.OrderRenewal && order.Attempts < provisionAttempts {
+			return nil, retryLater(order, err)
+		}
+		releaseStock(order)
+		return nil, failOrder(order, ErrProvisionFailed.Wrap(err), err.Error())
+	}
+	order.Reference = provision.Reference
+
+	entitlement, err := grantPack(order.Msisdn, pack)
+	if err != nil {
+		deactivate(adapter, order)
+		releaseStock(order)
+		return nil, failOrder(order, ErrProvisionFailed.Wrap(err), err.Error())
+	}
+
+	order.Complete(entitlement.ID.Hex())
+	err = orderDAO.Update(order)
+	if err != nil {
+		log.Errorf("order %s completed but it cannot be stored: %v", order.ID.Hex(), err)
+	}
+	return entitlement, nil
+}
+
+// retryLater keeps the order pending until its next attempt, the wait
+// doubles after every attempt. The order fails if it cannot be stored,
+// as no job would retry it.
+func retryLater(order *model.Order, failure error) error {
+	wait := provisionWait
+	for attempt := 1; attempt < order.Attempts; attempt++ {
+		wait *= 2
+	}
+	order.Retry(failure.Error(), time.Now().Add(wait))
+	err := orderDAO.Update(order)
+	if err != nil {
+		releaseStock(order)
+		return failOrder(order, ErrProvisionFailed.Wrap(err), failure.Error())
+	}
+	return ErrProvisionPending.Wrap(failure)
+}
+
+// deactivate undoes the activation of an order, it is tried once as the
+// caller is waiting.
+func deactivate(adapter provisioning.IProvisioner, order *model.Order) {
+	err := adapter.Deactivate(order.Msisdn, order.ProdID, order.Reference)
+	if err != nil {
+		log.Errorf("activation %s of order %s cannot be undone: %v", order.Reference, order.ID.Hex(), err)
+	}
+}
+
+// reserveStock reserves a unit of the pack of the order, a bundle has no
+// stock of its own so a unit of every component is reserved. Returns false
+// if a pack is out of stock, the units already reserved are given back.
+func reserveStock(order *model.Order) bool {
+	packids := stockedPacks(order)
+	for i, packid := range packids {
+		reserved, err := packDAO.ReserveStock(packid, 1)
+		if err != nil || !reserved {
+			giveBackStock(order, packids[:i])
+			return false
+		}
+	}
+	return true
+}
+
+// releaseStock gives back the units reserved by an order.
+func releaseStock(order *model.Order) {
+	giveBackStock(order, stockedPacks(order))
+}
+
+// stockedPacks returns the ids of the packs whose stock the order takes.
+func stockedPacks(order *model.Order) []string {
+	if len(order.Components) > 0 {
+		return order.Components
+	}
+	return []string{order.PackID}
+}
+
+// giveBackStock gives back a unit of the given packs reserved by an order.
+func giveBackStock(order *model.Order, packids []string) {
+	for _, packid := range packids {
+		err := packDAO.ChangeStock(packid, 1)
+		if err != nil {
+			log.Errorf("stock of pack %s reserved by order %s cannot be released: %v", packid, order.ID.Hex(), err)
+		}
+	}
+}
+
+// failOrder stores the order as failed and returns the given failure.
+func failOrder(order *model.Order, failure *Error, reason string) error {
+	order.Fail(reason)
+	err := orderDAO.Update(order)
+	if err != nil {
+		log.Errorf("order %s failed but it cannot be stored: %v", order.ID.Hex(), err)
+	}
+	return failure
+}
+
+// SetOrderDAO set the order dao for this business logic.
+func SetOrderDAO(dao dao.IOrderDAO) {
+	orderDAO = dao
+}
+
+// SetProvisionRetries set how many times an activation is tried and the
+// wait before the first retry made by ProvisionDue. Values lower than 1
+// keep the defaults.
+func SetProvisionRetries(attempts int, wait time.Duration) {
+	if attempts > 0 {
+		provisionAttempts = attempts
+	}
+	if wait > 0 {
+		provisionWait = wait
+	}
+}
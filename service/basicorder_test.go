@@ -0,0 +1,292 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/model"
+	"github.com/fernandoocampo/pack/provisioning"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// orderTestMno is the mno of the packs sold in the order tests, it has a
+// provisioner of its own.
+const orderTestMno int8 = 91
+
+// memOrderDAO keeps the orders in memory.
+type memOrderDAO struct {
+	dao.IOrderDAO
+	orders []model.Order
+}
+
+func (d *memOrderDAO) Create(order *model.Order) error {
+	order.ID = bson.NewObjectId()
+	d.orders = append(d.orders, *order)
+	return nil
+}
+
+func (d *memOrderDAO) GetPending(msisdn string, packid string, kind model.OrderKind) (*model.Order, error) {
+	for i := range d.orders {
+		order := d.orders[i]
+		if order.Msisdn == msisdn && order.PackID == packid && order.Kind == kind && order.State == model.OrderPending {
+			return &order, nil
+		}
+	}
+	return nil, nil
+}
+
+func (d *memOrderDAO) Update(order *model.Order) error {
+	for i := range d.orders {
+		if d.orders[i].ID == order.ID {
+			d.orders[i] = *order
+		}
+	}
+	return nil
+}
+
+func (d *memOrderDAO) ClaimDue(now time.Time, lease time.Duration) (*model.Order, error) {
+	for i := range d.orders {
+		order := &d.orders[i]
+		if order.State == model.OrderPending && !order.NextAttempt.IsZero() && !order.NextAttempt.After(now) {
+			order.NextAttempt = now.Add(lease)
+			claimed := *order
+			return &claimed, nil
+		}
+	}
+	return nil, nil
+}
+
+// dueAll makes the pending orders due right away.
+func (d *memOrderDAO) dueAll() {
+	for i := range d.orders {
+		if !d.orders[i].NextAttempt.IsZero() {
+			d.orders[i].NextAttempt = time.Now().Add(-time.Second)
+		}
+	}
+}
+
+// orderPackDAO has one pack with its stock.
+type orderPackDAO struct {
+	dao.IPackDAO
+	pack *model.Pack
+}
+
+func (d *orderPackDAO) GetByID(id string) (*model.Pack, error) {
+	if d.pack.ID.Hex() != id {
+		return nil, nil
+	}
+	return d.pack, nil
+}
+
+func (d *orderPackDAO) ReserveStock(id string, amount int) (bool, error) {
+	if d.pack.Stock < amount {
+		return false, nil
+	}
+	d.pack.Stock -= amount
+	return true, nil
+}
+
+func (d *orderPackDAO) ChangeStock(id string, amount int) error {
+	d.pack.Stock += amount
+	return nil
+}
+
+// grantEntitlementDAO keeps the granted entitlements in memory.
+type grantEntitlementDAO struct {
+	dao.IEntitlementDAO
+	granted []model.Entitlement
+}
+
+func (d *grantEntitlementDAO) Create(entitlement *model.Entitlement) error {
+	entitlement.ID = bson.NewObjectId()
+	d.granted = append(d.granted, *entitlement)
+	return nil
+}
+
+// memSubscriptionDAO keeps the subscriptions in memory.
+type memSubscriptionDAO struct {
+	dao.ISubscriptionDAO
+	subscriptions []model.Subscription
+}
+
+func (d *memSubscriptionDAO) Create(subscription *model.Subscription) error {
+	subscription.ID = bson.NewObjectId()
+	d.subscriptions = append(d.subscriptions, *subscription)
+	return nil
+}
+
+func (d *memSubscriptionDAO) IsSubscribed(msisdn string, packid string) (bool, error) {
+	for _, subscription := range d.subscriptions {
+		if subscription.Msisdn == msisdn && subscription.PackID == packid && subscription.State != model.SubscriptionCanceled {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// flakyProvisioner fails the activations with a temporary error while
+// it has failures left.
+type flakyProvisioner struct {
+	provisioning.IProvisioner
+	failures    int
+	activations int
+}
+
+func (p *flakyProvisioner) Activate(msisdn string, prodid string) (*model.Provision, error) {
+	p.activations++
+	if p.failures > 0 {
+		p.failures--
+		return nil, &provisioning.TemporaryError{Err: errors.New("mno timeout")}
+	}
+	return &model.Provision{Reference: "act-1", Status: model.ProvisionActive}, nil
+}
+
+// newOrderTest sets the daos of the sales with an active pack with 10
+// units of stock and a provisioner that fails the given times. The
+// first retry waits a minute.
+func newOrderTest(t *testing.T, failures int) (*memOrderDAO, *orderPackDAO, *grantEntitlementDAO, *flakyProvisioner) {
+	pack := &model.Pack{ID: bson.NewObjectId(), Packcode: "0008", ProdID: "13", State: model.Active, Price: 2500, Stock: 10,
+		Mno: &model.Mno{ID: orderTestMno}, Term: &model.Term{Unit: "days", Amount: 30}}
+	orders, packs, entitlements := &memOrderDAO{}, &orderPackDAO{pack: pack}, &grantEntitlementDAO{}
+	provisioner := &flakyProvisioner{failures: failures}
+	provisioning.Register(orderTestMno, provisioner)
+	oldattempts, oldwait := provisionAttempts, provisionWait
+	SetOrderDAO(orders)
+	SetPackDAO(packs)
+	SetEntitlementDAO(entitlements)
+	SetProvisionRetries(3, time.Minute)
+	t.Cleanup(func() {
+		SetOrderDAO(nil)
+		SetPackDAO(nil)
+		SetEntitlementDAO(nil)
+		provisionAttempts, provisionWait = oldattempts, oldwait
+	})
+	return orders, packs, entitlements, provisioner
+}
+
+// TestPurchaseRetriedByJob tests a temporary failure of the mno network
+// leaves the order pending and the job completes it later
+func TestPurchaseRetriedByJob(t *testing.T) {
+	// GIVEN an mno network that fails once
+	orders, packs, entitlements, _ := newOrderTest(t, 1)
+
+	// WHEN the pack is purchased
+	order, err := new(BasicOrder).Purchase("573001234567", packs.pack.ID.Hex())
+
+	// THEN the order is pending with its stock reserved and nothing granted
+	if !errors.Is(err, ErrProvisionPending) || order == nil {
+		t.Fatalf("Expected a pending order but got %v %v", order, err)
+	}
+	stored := orders.orders[0]
+	if stored.State != model.OrderPending || stored.Attempts != 1 || stored.NextAttempt.Before(time.Now().Add(50*time.Second)) {
+		t.Fatalf("Expected a pending order retried in a minute but got %+v", stored)
+	}
+	if packs.pack.Stock != 9 || len(entitlements.granted) != 0 {
+		t.Fatalf("Expected the stock reserved and no entitlement but got %d %d", packs.pack.Stock, len(entitlements.granted))
+	}
+
+	// AND the job does not retry it before it is due
+	processed, err := new(BasicOrder).ProvisionDue()
+	if err != nil || processed != 0 {
+		t.Fatalf("Expected no order to be due but got %d %v", processed, err)
+	}
+
+	// AND the job completes it once it is due
+	orders.dueAll()
+	processed, err = new(BasicOrder).ProvisionDue()
+	if err != nil || processed != 1 {
+		t.Fatalf("Expected the order to be retried but got %d %v", processed, err)
+	}
+	stored = orders.orders[0]
+	if stored.State != model.OrderCompleted || stored.Attempts != 2 || stored.Reference != "act-1" || !stored.NextAttempt.IsZero() {
+		t.Fatalf("Expected a completed order but got %+v", stored)
+	}
+	if packs.pack.Stock != 9 || len(entitlements.granted) != 1 || stored.EntitlementID != entitlements.granted[0].ID.Hex() {
+		t.Fatalf("Expected the pack granted once but got %d %+v", packs.pack.Stock, entitlements.granted)
+	}
+}
+
+// TestPendingOrderFails tests an order fails and gives back its stock
+// once it has no attempts left, and renewals are never kept pending
+func TestPendingOrderFails(t *testing.T) {
+	// GIVEN an mno network that keeps failing
+	orders, packs, _, provisioner := newOrderTest(t, 10)
+
+	// WHEN the pack is purchased and retried until it has no attempts left
+	_, err := new(BasicOrder).Purchase("573001234567", packs.pack.ID.Hex())
+	for i := 0; i < 3; i++ {
+		orders.dueAll()
+		new(BasicOrder).ProvisionDue()
+	}
+
+	// THEN the order is failed after 3 attempts and the stock is released
+	stored := orders.orders[0]
+	if !errors.Is(err, ErrProvisionPending) || stored.State != model.OrderFailed || stored.Attempts != 3 || provisioner.activations != 3 {
+		t.Fatalf("Expected a failed order after 3 attempts but got %+v %v", stored, err)
+	}
+	if packs.pack.Stock != 10 {
+		t.Fatalf("Expected the stock to be released but got %d", packs.pack.Stock)
+	}
+
+	// AND a renewal fails at its first attempt
+	order, _, err := placeOrder("573001234567", packs.pack, 2500, model.OrderRenewal)
+	if !errors.Is(err, ErrProvisionFailed) || order.State != model.OrderFailed || packs.pack.Stock != 10 {
+		t.Fatalf("Expected a failed renewal but got %+v %v", order, err)
+	}
+}
+
+// TestSubscribeRetriedByJob tests the subscription of a pending order is
+// created when the job completes the order
+func TestSubscribeRetriedByJob(t *testing.T) {
+	// GIVEN an mno network that fails once
+	orders, packs, _, _ := newOrderTest(t, 1)
+	subscriptions := &memSubscriptionDAO{}
+	SetSubscriptionDAO(subscriptions)
+	defer SetSubscriptionDAO(nil)
+
+	// WHEN the subscriber subscribes to the pack
+	subscription, err := new(BasicSubscription).Subscribe("573001234567", packs.pack.ID.Hex())
+
+	// THEN the subscription waits for the order
+	if !errors.Is(err, ErrProvisionPending) || subscription != nil || len(subscriptions.subscriptions) != 0 {
+		t.Fatalf("Expected a pending subscription but got %v %v", subscription, err)
+	}
+
+	// AND subscribing again places no other order
+	_, err = new(BasicSubscription).Subscribe("573001234567", packs.pack.ID.Hex())
+	if !errors.Is(err, ErrProvisionPending) || len(orders.orders) != 1 || packs.pack.Stock != 9 {
+		t.Fatalf("Expected the pending order to be kept alone but got %v %d %d", err, len(orders.orders), packs.pack.Stock)
+	}
+
+	// AND it is created when the job completes the order
+	orders.dueAll()
+	new(BasicOrder).ProvisionDue()
+	if len(subscriptions.subscriptions) != 1 || subscriptions.subscriptions[0].PackID != packs.pack.ID.Hex() {
+		t.Fatalf("Expected the subscription to be created but got %+v", subscriptions.subscriptions)
+	}
+}
+
+// TestRetryOrderAlreadySubscribed tests the job does not create a second
+// subscription when the subscriber got one while the order was pending
+func TestRetryOrderAlreadySubscribed(t *testing.T) {
+	// GIVEN a pending subscription order
+	orders, packs, _, _ := newOrderTest(t, 1)
+	subscriptions := &memSubscriptionDAO{}
+	SetSubscriptionDAO(subscriptions)
+	defer SetSubscriptionDAO(nil)
+	new(BasicSubscription).Subscribe("573001234567", packs.pack.ID.Hex())
+
+	// AND a subscription to the pack made meanwhile
+	subscriptions.Create(&model.Subscription{Msisdn: "573001234567", PackID: packs.pack.ID.Hex(), State: model.SubscriptionActive})
+
+	// WHEN the job completes the order
+	orders.dueAll()
+	new(BasicOrder).ProvisionDue()
+
+	// THEN the order is completed and no other subscription is created
+	if orders.orders[0].State != model.OrderCompleted || len(subscriptions.subscriptions) != 1 {
+		t.Fatalf("Expected a single subscription but got %+v", subscriptions.subscriptions)
+	}
+}
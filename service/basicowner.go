@@ -0,0 +1,101 @@
+package service
+
+import (
+	"time"
+
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/model"
+)
+
+// ownerDAO makes references to owner DAO
+var ownerDAO dao.IOwnerDAO
+
+// BasicOwner implements the behaviour of IOwnerService.
+type BasicOwner struct {
+}
+
+// Create implements IOwnerService.Create.
+func (m *BasicOwner) Create(owner *model.Owner) error {
+	err := isValidOwner(owner)
+	if err != nil {
+		return err
+	}
+
+	existing, err := ownerDAO.GetByID(owner.ID)
+	if err != nil {
+		return ErrOwnerNotValidated.Wrap(err)
+	}
+	if existing != nil {
+		return ErrOwnerDuplicated
+	}
+
+	owner.State = model.OwnerActive
+	owner.Created = time.Now()
+	owner.Updated = owner.Created
+	return ownerDAO.Create(owner)
+}
+
+// GetByID implements IOwnerService.GetByID.
+func (m *BasicOwner) GetByID(id int) (*model.Owner, error) {
+	if id < 1 {
+		return nil, nil
+	}
+	return ownerDAO.GetByID(id)
+}
+
+// GetAll implements IOwnerService.GetAll.
+func (m *BasicOwner) GetAll() ([]model.Owner, error) {
+	return ownerDAO.GetAll()
+}
+
+// Update implements IOwnerService.Update.
+func (m *BasicOwner) Update(owner *model.Owner) error {
+	err := isValidOwner(owner)
+	if err != nil {
+		return err
+	}
+	return ownerDAO.Update(owner)
+}
+
+// ChangeState implements IOwnerService.ChangeState.
+func (m *BasicOwner) ChangeState(id int, newstate model.OwnerState) error {
+	if id < 1 || newstate < model.OwnerInactive || newstate > model.OwnerSuspended {
+		return ErrOwnerInvalid
+	}
+	return ownerDAO.ChangeState(id, newstate)
+}
+
+// isValidOwner validates the data of an owner to store.
+func isValidOwner(owner *model.Owner) error {
+	if owner == nil || owner.ID < 1 || owner.Name == "" {
+		return ErrOwnerInvalid
+	}
+	return nil
+}
+
+// validateOwner checks that the given owner exists and it can own packs
+// of the mno. Ownerid 0 is a pack that is not for resale.
+func validateOwner(ownerid int, mnoid int8) error {
+	if ownerid == 0 {
+		return nil
+	}
+	if ownerid < 0 || ownerDAO == nil {
+		return ErrOwnerNotFound
+	}
+	owner, err := ownerDAO.GetByID(ownerid)
+	if err != nil {
+		return ErrOwnerNotValidated.Wrap(err)
+	}
+	if owner == nil {
+		return ErrOwnerNotFound
+	}
+	if !owner.CanOwn(mnoid) {
+		return ErrOwnerNotAllowed
+	}
+	return nil
+}
+
+// SetOwnerDAO set the owner dao for this business logic.
+func SetOwnerDAO(dao dao.IOwnerDAO) {
+	ownerDAO = dao
+}
@@ -1,7 +1,6 @@
 package service
 
 import (
-	"fmt"
 	"time"
 
 	"github.com/fernandoocampo/pack/dao"
@@ -13,6 +12,33 @@ var packDAO dao.IPackDAO
 
 // BasicPack implements the behaviour made in pack Services
 type BasicPack struct {
+	packs  dao.IPackDAO  // pack dao scoped to a tenant or at a version
+	tenant *model.Tenant // tenant of the scoped dao
+	scoped bool          // true if the dao is scoped to the tenant
+}
+
+// WithTenant implements *IPackService.WithTenant.
+func (m *BasicPack) WithTenant(tenant *model.Tenant) IPackService {
+	return &BasicPack{packs: packDAO.Scoped(tenant), tenant: tenant, scoped: true}
+}
+
+// WithVersion implements *IPackService.WithVersion.
+func (m *BasicPack) WithVersion(version int) IPackService {
+	return &BasicPack{packs: m.dao().AtVersion(version), tenant: m.tenant, scoped: m.scoped}
+}
+
+// dao returns the pack dao of the tenant.
+func (m *BasicPack) dao() dao.IPackDAO {
+	if m.packs == nil {
+		return packDAO
+	}
+	return m.packs
+}
+
+// owns returns true if the pack belongs to the tenant of the service,
+// every pack does if the service is not scoped.
+func (m *BasicPack) owns(pack *model.Pack) bool {
+	return !m.scoped || m.tenant.Owns(pack)
 }
 
 // FindByID implements *IPackService.FindByID using mongo implementation.
@@ -21,7 +47,7 @@ func (m *BasicPack) FindByID(id string) (*model.Pack, error) {
 		return &model.Pack{}, nil
 	}
 
-	return packDAO.GetByID(id)
+	return m.dao().GetByID(id)
 }
 
 // GetByPackCode implements *IPackService.GetByPackCode.
@@ -30,7 +56,7 @@ func (m *BasicPack) GetByPackCode(packcode string) (*model.Pack, error) {
 		return &model.Pack{}, nil
 	}
 
-	return packDAO.GetByPackCode(packcode)
+	return m.dao().GetByPackCode(packcode)
 }
 
 // GetByProductID implements *IPackService.GetByProductId.
@@ -39,7 +65,7 @@ func (m *BasicPack) GetByProductID(productid string) (*model.Pack, error) {
 		return &model.Pack{}, nil
 	}
 
-	return packDAO.GetByProductID(productid)
+	return m.dao().GetByProductID(productid)
 }
 
 // GetIDByCode implements *IPackDAO.GetIDByCode.
@@ -48,54 +74,159 @@ func (m *BasicPack) GetIDByCode(packcode string) (string, error) {
 		return "", nil
 	}
 
-	return packDAO.GetIDByCode(packcode)
+	return m.dao().GetIDByCode(packcode)
 }
 
-// IsThereThisPack implements *IPackService.IsThereThisPack
+// IsThereThisPack implements *IPackService.IsThereThisPack, it is scoped
+// to the tenant on purpose so callers cannot probe the packs of others.
 func (m *BasicPack) IsThereThisPack(pack *model.PackExists) (bool, error) {
 	if pack == nil || pack.MnoID < 1 || (pack.Packcode == "" && pack.ProdID == "") {
 		return false, nil
 	}
-	return packDAO.IsThereThisPack(pack)
+	return m.dao().IsThereThisPack(pack)
 }
 
-// Create implements *IPackService.Create.
+// Create implements *IPackService.Create. Packs are unique across tenants
+// so the existing pack codes and product ids are checked with the
+// unscoped packDAO.
 func (m *BasicPack) Create(packdata *model.Pack) error {
+	if packdata != nil && !m.owns(packdata) {
+		return ErrNotOwner
+	}
 	// check valid input data
 	err0 := isValidPackToCreate(packdata)
 	if err0 != nil {
 		return err0
 	}
 
+	// Check the eligibility rules of the pack
+	if field := model.InvalidRulesField(packdata.Rules); field != "" {
+		return ErrEligibilityRuleInvalid.WithField(field)
+	}
+
+	// Check the channels and regions where the pack is sold
+	err1 := validateAvailability(packdata.Channels, packdata.Regions)
+	if err1 != nil {
+		return err1
+	}
+
+	// Check that the owner can own the pack
+	err2 := validateOwner(packdata.Ownerid, packdata.Mno.ID)
+	if err2 != nil {
+		return err2
+	}
+
+	// A bundle gets the resources of its components
+	if packdata.IsBundle() {
+		err3 := m.aggregateComponents(packdata)
+		if err3 != nil {
+			return err3
+		}
+	}
+
 	// Check that packcode and product id do not exist
 	packexists := model.NewPackExists(packdata)
 	result, err1 := packDAO.IsThereThisPack(packexists)
 	if err1 != nil {
-		return fmt.Errorf("06") // existing pack cannot be validated
+		return ErrPackNotValidated.Wrap(err1)
 	}
 	if result {
-		return fmt.Errorf("07") // There is a pack with mnoid and (pack code or product id)
+		return ErrPackDuplicated
 	}
 
 	packdata.State = model.Active
 	packdata.Created = time.Now()
 	packdata.Updated = time.Now()
-	return packDAO.Create(packdata)
+	return m.dao().Create(packdata)
+}
+
+// Update implements *IPackService.Update.
+func (m *BasicPack) Update(id string, packdata *model.Pack) error {
+	if id == "" {
+		return ErrPackNotFound.WithField("id")
+	}
+	err := isValidPackToCreate(packdata)
+	if err != nil {
+		return err
+	}
+	if !m.owns(packdata) {
+		return ErrNotOwner
+	}
+	err = validateOwner(packdata.Ownerid, packdata.Mno.ID)
+	if err != nil {
+		return err
+	}
+
+	existing, err := m.dao().GetByID(id)
+	if err != nil {
+		return ErrPackNotValidated.Wrap(err)
+	}
+	if existing == nil {
+		return ErrPackNotFound.WithField("id")
+	}
+	err = checkKeysOf(id, packdata)
+	if err != nil {
+		return err
+	}
+
+	// the resources of a bundle come from its components
+	if existing.IsBundle() {
+		packdata.Resources = existing.Resources
+	}
+	err = m.dao().Update(id, packdata)
+	if err != nil {
+		return err
+	}
+	refreshBundles(id)
+	return nil
+}
+
+// checkKeysOf returns an error if another pack of the mno has the pack
+// code or the product id of the pack with the given id. Packs are unique
+// across tenants so they are looked up with the unscoped packDAO.
+func checkKeysOf(id string, pack *model.Pack) error {
+	packcode, err := packDAO.GetByKeys(&model.PackExists{MnoID: pack.Mno.ID, Packcode: pack.Packcode})
+	if err != nil {
+		return ErrPackNotValidated.Wrap(err)
+	}
+	if packcode != nil && packcode.ID.Hex() != id {
+		return ErrPackCodeDuplicated
+	}
+	prodid, err := packDAO.GetByKeys(&model.PackExists{MnoID: pack.Mno.ID, ProdID: pack.ProdID})
+	if err != nil {
+		return ErrPackNotValidated.Wrap(err)
+	}
+	if prodid != nil && prodid.ID.Hex() != id {
+		return ErrProductIDDuplicated
+	}
+	return nil
 }
 
 // ChangeState implements *IPackService.ChangeState.
 func (m *BasicPack) ChangeState(id string, newstate model.PackState) error {
 	if id == "" {
-		return fmt.Errorf("08") // pack id for change state is empty
+		return ErrChangeStateArgs
+	}
+
+	pack, err := m.dao().GetByID(id)
+	if err != nil {
+		return ErrPackNotValidated.Wrap(err)
+	}
+	if pack == nil {
+		return ErrPackNotFound.WithField("id")
+	}
+	err = checkStateChange(pack, newstate)
+	if err != nil {
+		return err
 	}
 
-	return packDAO.ChangeState(id, newstate)
+	return m.dao().ChangeState(id, newstate)
 }
 
 // ChangeProductID implements *IPackService.ChangeProductID.
 func (m *BasicPack) ChangeProductID(id string, mnoid int8, newprodid string) error {
 	if id == "" || newprodid == "" {
-		return fmt.Errorf("09") // pack id for change product id is empty
+		return ErrChangeProductIDArgs
 	}
 
 	// check if the product id with the given mno id already exists
@@ -104,19 +235,19 @@ func (m *BasicPack) ChangeProductID(id string, mnoid int8, newprodid string) err
 	packexists.ProdID = newprodid
 	result, err1 := packDAO.IsThereThisPack(packexists)
 	if err1 != nil {
-		return fmt.Errorf("06") // existing pack cannot be validated
+		return ErrPackNotValidated.Wrap(err1)
 	}
 	if result {
-		return fmt.Errorf("10") // There is a pack with mnoid and given product id
+		return ErrProductIDDuplicated
 	}
 
-	return packDAO.ChangeProductID(id, newprodid)
+	return m.dao().ChangeProductID(id, newprodid)
 }
 
 // ChangePackCode implements *IPackService.ChangePackCode.
 func (m *BasicPack) ChangePackCode(id string, mnoid int8, newpackcode string) error {
 	if id == "" || newpackcode == "" {
-		return fmt.Errorf("11") // pack id for change pack code or pack code is empty
+		return ErrChangePackCodeArgs
 	}
 
 	// check if the product id with the given mno id already exists
@@ -125,74 +256,74 @@ func (m *BasicPack) ChangePackCode(id string, mnoid int8, newpackcode string) er
 	packexists.Packcode = newpackcode
 	result, err1 := packDAO.IsThereThisPack(packexists)
 	if err1 != nil {
-		return fmt.Errorf("06") // existing pack cannot be validated
+		return ErrPackNotValidated.Wrap(err1)
 	}
 	if result {
-		return fmt.Errorf("12") // There is a pack with mnoid and given pack code
+		return ErrPackCodeDuplicated
 	}
 
-	return packDAO.ChangePackCode(id, newpackcode)
+	return m.dao().ChangePackCode(id, newpackcode)
 }
 
 // ChangeName implements *IPackService.ChangeName.
 func (m *BasicPack) ChangeName(id string, newname string) error {
 	if id == "" || newname == "" {
-		return fmt.Errorf("13") // pack id or new name for change name is empty
+		return ErrChangeNameArgs
 	}
 
-	return packDAO.ChangeName(id, newname)
+	return m.dao().ChangeName(id, newname)
 }
 
 // ChangeDesc implements *IPackService.ChangeDesc.
 func (m *BasicPack) ChangeDesc(id string, newdesc string) error {
 	if id == "" || newdesc == "" {
-		return fmt.Errorf("14") // pack id or new desc for change desc is empty
+		return ErrChangeDescArgs
 	}
 
-	return packDAO.ChangeDesc(id, newdesc)
+	return m.dao().ChangeDesc(id, newdesc)
 }
 
 // ChangeImg implements *IPackService.ChangeImg.
 func (m *BasicPack) ChangeImg(id string, newimgurl string) error {
 	if id == "" || newimgurl == "" {
-		return fmt.Errorf("15") // pack id or new image for change image is empty
+		return ErrChangeImageArgs
 	}
 
-	return packDAO.ChangeImg(id, newimgurl)
+	return m.dao().ChangeImg(id, newimgurl)
 }
 
 // ChangeKeyword implements *IPackService.ChangeKeyword.
 func (m *BasicPack) ChangeKeyword(id string, newkeyword string) error {
 	if id == "" || newkeyword == "" {
-		return fmt.Errorf("16") // pack id or new key word for change key word is empty
+		return ErrChangeKeywordsArgs
 	}
 
-	return packDAO.ChangeKeyword(id, newkeyword)
+	return m.dao().ChangeKeyword(id, newkeyword)
 }
 
 // ChangePrice implements *IPackService.ChangePrice.
 func (m *BasicPack) ChangePrice(id string, newprice int) error {
 	if id == "" {
-		return fmt.Errorf("17") // pack id or new price for change price is empty
+		return ErrChangePriceArgs
 	}
 
-	return packDAO.ChangePrice(id, newprice)
+	return m.dao().ChangePrice(id, newprice)
 }
 
 // ChangePackType implements *IPackService.ChangePackType.
 func (m *BasicPack) ChangePackType(id string, newtype *model.Type) error {
 	if id == "" || newtype == nil || newtype.ID < 1 || newtype.Name == "" {
-		return fmt.Errorf("18") // pack id or new type for change type is empty
+		return ErrChangeTypeArgs
 	}
 
-	return packDAO.ChangePackType(id, newtype)
+	return m.dao().ChangePackType(id, newtype)
 }
 
 // ChangeMNO implements *IPackService.ChangeMNO.
 func (m *BasicPack) ChangeMNO(id string, prodid string, packcode string, newmno *model.Mno) error {
 	if id == "" || prodid == "" || packcode == "" || newmno == nil ||
 		newmno.ID < 1 || newmno.Name == "" {
-		return fmt.Errorf("19") // pack id or new mno for change mno is empty
+		return ErrChangeMnoArgs
 	}
 
 	// check if the product id and pack code with the given mno id already exists
@@ -204,72 +335,136 @@ func (m *BasicPack) ChangeMNO(id string, prodid string, packcode string, newmno
 	result, err1 := packDAO.IsThereThisPack(packexists)
 
 	if err1 != nil {
-		return fmt.Errorf("06") // existing pack cannot be validated
+		return ErrPackNotValidated.Wrap(err1)
 	}
 	if result {
-		return fmt.Errorf("07") // There is a pack with mnoid and (pack code or product id)
+		return ErrPackDuplicated
 	}
 
-	return packDAO.ChangeMNO(id, newmno)
+	return m.dao().ChangeMNO(id, newmno)
 }
 
 // ChangeValidity implements *IPackService.ChangeValidity.
 func (m *BasicPack) ChangeValidity(id string, newterm *model.Term) error {
 	if id == "" || newterm == nil || newterm.UnitID < 1 || newterm.Unit == "" {
-		return fmt.Errorf("20") // pack id or new term for change validity is empty
+		return ErrChangeValidityArgs
 	}
 
-	return packDAO.ChangeValidity(id, newterm)
+	return m.dao().ChangeValidity(id, newterm)
 }
 
 // ChangeCurrency implements *IPackService.ChangeCurrency.
 func (m *BasicPack) ChangeCurrency(id string, newccy *model.Currency) error {
 	if id == "" || newccy == nil || newccy.ID < 1 || newccy.Name == "" {
-		return fmt.Errorf("21") // pack id or new currency for change currency is empty
+		return ErrChangeCurrencyArgs
 	}
 
-	return packDAO.ChangeCurrency(id, newccy)
+	return m.dao().ChangeCurrency(id, newccy)
 }
 
 // MoveStock implements *IPackService.MoveStock.
 func (m *BasicPack) MoveStock(id string, amount int) error {
 	if id == "" || amount == 0 {
-		return fmt.Errorf("23") // invalid values
+		return ErrMoveStockArgs
 	}
-	return packDAO.ChangeStock(id, amount)
+	return m.dao().ChangeStock(id, amount)
 }
 
 // Delete implements *IPackService.Delete.
 func (m *BasicPack) Delete(id string) error {
 	if id == "" {
-		return fmt.Errorf("22") // pack id for delete is empty
+		return ErrDeleteArgs
+	}
+
+	// Check that no active bundle has the pack
+	err := checkNotInUse(id)
+	if err != nil {
+		return err
 	}
 
-	return packDAO.Delete(id)
+	return m.dao().Delete(id)
 }
 
 // UpdateResources replace the resources that we configured for a pack.
 func (m *BasicPack) UpdateResources(id string, newresources []model.Resource) error {
 	if id == "" || newresources == nil {
-		return fmt.Errorf("24") // pack id or resources for update resources are empty
+		return ErrReplaceResourcesArgs
 	}
 
-	return packDAO.UpdateResources(id, newresources)
+	return m.changeResources(id, newresources)
 }
 
 // DeleteResources remove the resources that we configured for a pack.
 func (m *BasicPack) DeleteResources(id string) error {
 	if id == "" {
-		return fmt.Errorf("25") // pack id for delete resources is empty
+		return ErrDeleteResourcesArgs
 	}
 
-	return packDAO.UpdateResources(id, []model.Resource{})
+	return m.changeResources(id, []model.Resource{})
+}
+
+// changeResources replaces the resources of a pack that is not a bundle,
+// the bundles that have it as a component get the new resources too.
+func (m *BasicPack) changeResources(id string, newresources []model.Resource) error {
+	pack, err := m.dao().GetByID(id)
+	if err != nil {
+		return ErrPackNotValidated.Wrap(err)
+	}
+	if pack == nil {
+		return ErrPackNotFound.WithField("id")
+	}
+	if pack.IsBundle() {
+		return ErrBundleResources
+	}
+
+	err = m.dao().UpdateResources(id, newresources)
+	if err != nil {
+		return err
+	}
+	refreshBundles(id)
+	return nil
+}
+
+// TransferOwnership implements *IPackService.TransferOwnership.
+func (m *BasicPack) TransferOwnership(id string, newownerid int) error {
+	if id == "" || newownerid < 1 {
+		return ErrTransferArgs
+	}
+
+	pack, err := m.dao().GetByID(id)
+	if err != nil {
+		return ErrPackNotValidated.Wrap(err)
+	}
+	if pack == nil {
+		return ErrPackNotFound
+	}
+	if pack.Ownerid == newownerid {
+		return ErrAlreadyOwner
+	}
+	var mnoid int8
+	if pack.Mno != nil {
+		mnoid = pack.Mno.ID
+	}
+	err = validateOwner(newownerid, mnoid)
+	if err != nil {
+		return err
+	}
+
+	return m.dao().ChangeOwner(id, newownerid)
+}
+
+// GetByOwner implements *IPackService.GetByOwner.
+func (m *BasicPack) GetByOwner(ownerid int) ([]model.Pack, error) {
+	if ownerid < 1 {
+		return []model.Pack{}, nil
+	}
+	return m.dao().GetByOwner(ownerid)
 }
 
 // isValidPackToCreate validates if model.Pack data is ok..
 func isValidPackToCreate(pack *model.Pack) error {
 	if pack == nil {
-		return fmt.Errorf("00")
+		return ErrPackEmpty
 	}
 	errbasic := validateBasicData(pack)
 	if errbasic != nil {
@@ -282,23 +477,23 @@ func isValidPackToCreate(pack *model.Pack) error {
 
 func validateBasicData(pack *model.Pack) error {
 	if pack.ProdID == "" || pack.Packcode == "" || pack.Name == "" {
-		return fmt.Errorf("01")
+		return ErrPackKeysEmpty
 	}
 	if pack.Desc == "" || pack.Kwds == "" || pack.Img == "" {
-		return fmt.Errorf("02")
+		return ErrPackTextsEmpty
 	}
 	return nil
 }
 
 func validateComplexData(pack *model.Pack) error {
 	if pack.Packtype == nil || pack.Packtype.ID < 1 || pack.Packtype.Name == "" {
-		return fmt.Errorf("03")
+		return ErrPackTypeInvalid
 	}
 	if pack.Mno == nil || pack.Mno.ID < 1 || pack.Mno.Name == "" {
-		return fmt.Errorf("04")
+		return ErrPackMnoInvalid
 	}
 	if pack.Term == nil || pack.Term.UnitID < 1 || pack.Term.Unit == "" {
-		return fmt.Errorf("05")
+		return ErrPackTermInvalid
 	}
 	return nil
 }
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/model"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// updatePackDAO keeps the packs in memory with the methods updates use,
+// a dao at a version only changes packs at that version.
+type updatePackDAO struct {
+	clonePackDAO
+	version   int
+	versioned bool
+}
+
+func (d *updatePackDAO) AtVersion(version int) dao.IPackDAO {
+	return &updatePackDAO{clonePackDAO: d.clonePackDAO, version: version, versioned: true}
+}
+
+func (d *updatePackDAO) GetByKeys(keys *model.PackExists) (*model.Pack, error) {
+	for _, pack := range d.packs {
+		if pack.Mno.ID == keys.MnoID && (pack.Packcode == keys.Packcode || pack.ProdID == keys.ProdID) {
+			return pack, nil
+		}
+	}
+	return nil, nil
+}
+
+func (d *updatePackDAO) Update(id string, packdata *model.Pack) error {
+	pack, _ := d.GetByID(id)
+	if pack == nil || (d.versioned && pack.Version != d.version) {
+		return dao.ErrVersionChanged
+	}
+	pack.Name = packdata.Name
+	pack.Price = packdata.Price
+	pack.Resources = packdata.Resources
+	pack.Version++
+	return nil
+}
+
+func (d *updatePackDAO) GetBundlesOf(id string) ([]model.Pack, error) {
+	return []model.Pack{}, nil
+}
+
+// TestUpdatePack tests the catalog data of a pack is replaced when its
+// keys are its own and it is at the expected version
+func TestUpdatePack(t *testing.T) {
+	tests := []struct {
+		name      string
+		change    func(pack *model.Pack)
+		version   int // version the update is made at, -1 for any
+		wantErr   error
+		wantPrice int
+	}{
+		{name: "any version", change: func(pack *model.Pack) { pack.Price = 3000 }, version: -1, wantPrice: 3000},
+		{name: "current version", change: func(pack *model.Pack) { pack.Price = 3500 }, version: 4, wantPrice: 3500},
+		{name: "old version", change: func(pack *model.Pack) { pack.Price = 3500 }, version: 3, wantErr: ErrPackModified, wantPrice: 2000},
+		{name: "pack code of another pack", change: func(pack *model.Pack) { pack.Packcode = "other" }, version: -1, wantErr: ErrPackCodeDuplicated, wantPrice: 2000},
+		{name: "product id of another pack", change: func(pack *model.Pack) { pack.ProdID = "OTHER" }, version: -1, wantErr: ErrProductIDDuplicated, wantPrice: 2000},
+		{name: "no name", change: func(pack *model.Pack) { pack.Name = "" }, version: -1, wantErr: ErrPackKeysEmpty, wantPrice: 2000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN a pack at version 4 and another pack of the same mno
+			pack := newClonePack()
+			pack.ID = bson.NewObjectId()
+			pack.Price = 2000
+			pack.Version = 4
+			other := newClonePack()
+			other.ID = bson.NewObjectId()
+			other.ProdID = "OTHER"
+			other.Packcode = "other"
+			packs := &updatePackDAO{clonePackDAO: clonePackDAO{packs: []*model.Pack{pack, other}}}
+			SetPackDAO(packs)
+			defer SetPackDAO(nil)
+
+			// WHEN a changed copy replaces it
+			changed := *pack
+			tt.change(&changed)
+			var packservice IPackService = new(BasicPack)
+			if tt.version >= 0 {
+				packservice = packservice.WithVersion(tt.version)
+			}
+			err := packservice.Update(pack.ID.Hex(), &changed)
+
+			// THEN only a valid change at the current version is stored
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("Expected the pack to be updated but got %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(AsError(err), tt.wantErr) {
+				t.Fatalf("Expected %v but got %v", tt.wantErr, err)
+			}
+			if pack.Price != tt.wantPrice {
+				t.Errorf("Expected price %d but got %d", tt.wantPrice, pack.Price)
+			}
+		})
+	}
+}
+
+// TestUpdateBundleKeepsResources tests the resources of a bundle are not
+// replaced, they come from its components
+func TestUpdateBundleKeepsResources(t *testing.T) {
+	bundle := newClonePack()
+	bundle.ID = bson.NewObjectId()
+	bundle.Components = []string{bson.NewObjectId().Hex(), bson.NewObjectId().Hex()}
+	bundle.Resources = []model.Resource{{ID: 1, Name: "data", Units: "MB", Amount: 100}}
+	packs := &updatePackDAO{clonePackDAO: clonePackDAO{packs: []*model.Pack{bundle}}}
+	SetPackDAO(packs)
+	defer SetPackDAO(nil)
+
+	changed := *bundle
+	changed.Resources = nil
+	err := new(BasicPack).Update(bundle.ID.Hex(), &changed)
+
+	if err != nil {
+		t.Fatalf("Expected the bundle to be updated but got %v", err)
+	}
+	if len(bundle.Resources) != 1 {
+		t.Errorf("Expected the resources of the bundle to be kept but got %+v", bundle.Resources)
+	}
+}
+
+// TestCreatePackOfOtherTenant tests a tenant cannot create a pack of
+// another owner
+func TestCreatePackOfOtherTenant(t *testing.T) {
+	SetPackDAO(&bulkPackDAO{})
+	defer SetPackDAO(nil)
+	packs := new(BasicPack).WithTenant(&model.Tenant{Ownerid: 7})
+
+	err := packs.Create(&model.Pack{Packcode: "0008", ProdID: "13", Name: "Whatsapp", Ownerid: 8, Mno: &model.Mno{ID: 2, Name: "Claro"}})
+
+	if !errors.Is(err, ErrNotOwner) {
+		t.Fatalf("Expected ErrNotOwner but got %v", err)
+	}
+}
@@ -0,0 +1,22 @@
+package service
+
+// SetAvailability implements *IPackService.SetAvailability.
+func (m *BasicPack) SetAvailability(id string, channels []string, regions []string) error {
+	if id == "" {
+		return ErrPackNotFound.WithField("id")
+	}
+	err := validateAvailability(channels, regions)
+	if err != nil {
+		return err
+	}
+
+	pack, err := m.dao().GetByID(id)
+	if err != nil {
+		return ErrPackNotValidated.Wrap(err)
+	}
+	if pack == nil {
+		return ErrPackNotFound.WithField("id")
+	}
+
+	return m.dao().UpdateAvailability(id, channels, regions)
+}
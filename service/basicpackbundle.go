@@ -0,0 +1,147 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/model"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// CreateBundle implements *IPackService.CreateBundle.
+func (m *BasicPack) CreateBundle(bundle *model.Pack) error {
+	if bundle == nil {
+		return ErrPackEmpty
+	}
+	if !bundle.IsBundle() {
+		return ErrBundleComponentsInvalid
+	}
+	return m.Create(bundle)
+}
+
+// ChangeComponents implements *IPackService.ChangeComponents.
+func (m *BasicPack) ChangeComponents(id string, components []string) error {
+	if id == "" {
+		return ErrNotBundle
+	}
+	bundle, err := m.dao().GetByID(id)
+	if err != nil {
+		return ErrPackNotValidated.Wrap(err)
+	}
+	if bundle == nil {
+		return ErrPackNotFound.WithField("id")
+	}
+	if !bundle.IsBundle() {
+		return ErrNotBundle
+	}
+	bundle.Components = components
+	err = m.aggregateComponents(bundle)
+	if err != nil {
+		return err
+	}
+	return m.dao().UpdateComponents(id, bundle.Components, bundle.Resources)
+}
+
+// aggregateComponents checks the components of the bundle and sets their
+// resources to it. Components must be visible to the tenant, active, of
+// the mno of the bundle and not bundles themselves.
+func (m *BasicPack) aggregateComponents(bundle *model.Pack) error {
+	if bundle.InvalidComponents() {
+		return ErrBundleComponentsInvalid
+	}
+	components, err := componentsOf(m.dao(), bundle)
+	if err != nil {
+		return err
+	}
+	for _, component := range components {
+		if component.IsBundle() || component.State != model.Active || component.Mno == nil ||
+			bundle.Mno == nil || component.Mno.ID != bundle.Mno.ID {
+			return ErrBundleComponentInvalid
+		}
+	}
+	bundle.Resources = model.AggregateResources(components)
+	return nil
+}
+
+// componentsOf reads the components of the bundle with the given dao, a
+// component that is not there is reported as not found.
+func componentsOf(packs dao.IPackDAO, bundle *model.Pack) ([]model.Pack, error) {
+	components := make([]model.Pack, 0, len(bundle.Components))
+	for _, id := range bundle.Components {
+		if !bson.IsObjectIdHex(id) {
+			return nil, ErrPackNotFound.WithField("components")
+		}
+		component, err := packs.GetByID(id)
+		if err != nil {
+			return nil, ErrPackNotValidated.Wrap(err)
+		}
+		if component == nil {
+			return nil, ErrPackNotFound.WithField("components")
+		}
+		components = append(components, *component)
+	}
+	return components, nil
+}
+
+// checkStateChange returns an error if the pack cannot take the new
+// state. A component of an active bundle cannot be deactivated and a
+// bundle is only activated if every component is active.
+func checkStateChange(pack *model.Pack, newstate model.PackState) error {
+	if pack.State == newstate {
+		return nil
+	}
+	if newstate == model.Inactive {
+		return checkNotInUse(pack.ID.Hex())
+	}
+	if !pack.IsBundle() {
+		return nil
+	}
+	components, err := componentsOf(packDAO, pack)
+	if err != nil {
+		if errors.Is(err, ErrPackNotFound) {
+			return ErrBundleComponentInactive
+		}
+		return err
+	}
+	for _, component := range components {
+		if component.State != model.Active {
+			return ErrBundleComponentInactive
+		}
+	}
+	return nil
+}
+
+// checkNotInUse returns ErrComponentInUse if an active bundle of any
+// tenant has the pack with the given id as a component.
+func checkNotInUse(id string) error {
+	bundles, err := packDAO.GetBundlesOf(id)
+	if err != nil {
+		return ErrPackNotValidated.Wrap(err)
+	}
+	for _, bundle := range bundles {
+		if bundle.State == model.Active {
+			return ErrComponentInUse
+		}
+	}
+	return nil
+}
+
+// refreshBundles aggregates again the resources of the bundles that have
+// the pack with the given id as a component, after its resources changed.
+// Failures are only logged as the change of the component is done.
+func refreshBundles(id string) {
+	bundles, err := packDAO.GetBundlesOf(id)
+	if err != nil {
+		log.Errorf("bundles of pack %s cannot be read to refresh their resources: %v", id, err)
+		return
+	}
+	for _, bundle := range bundles {
+		components, err := componentsOf(packDAO, &bundle)
+		if err == nil {
+			err = packDAO.UpdateResources(bundle.ID.Hex(), model.AggregateResources(components))
+		}
+		if err != nil {
+			log.Errorf("resources of bundle %s cannot be refreshed: %v", bundle.ID.Hex(), err)
+		}
+	}
+}
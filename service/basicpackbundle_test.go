@@ -0,0 +1,224 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/fernandoocampo/pack/model"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// bundlePackDAO keeps the packs in memory with the methods bundles use.
+type bundlePackDAO struct {
+	clonePackDAO
+}
+
+func (d *bundlePackDAO) GetBundlesOf(id string) ([]model.Pack, error) {
+	bundles := []model.Pack{}
+	for _, pack := range d.packs {
+		if pack.HasComponent(id) {
+			bundles = append(bundles, *pack)
+		}
+	}
+	return bundles, nil
+}
+
+func (d *bundlePackDAO) ChangeState(id string, newstate model.PackState) error {
+	pack, _ := d.GetByID(id)
+	pack.State = newstate
+	return nil
+}
+
+func (d *bundlePackDAO) UpdateResources(id string, newresources []model.Resource) error {
+	pack, _ := d.GetByID(id)
+	pack.Resources = newresources
+	return nil
+}
+
+func (d *bundlePackDAO) Delete(id string) error {
+	for i, pack := range d.packs {
+		if pack.ID.Hex() == id {
+			d.packs = append(d.packs[:i], d.packs[i+1:]...)
+		}
+	}
+	return nil
+}
+
+func (d *bundlePackDAO) ReserveStock(id string, amount int) (bool, error) {
+	pack, _ := d.GetByID(id)
+	if pack.Stock < amount {
+		return false, nil
+	}
+	pack.Stock -= amount
+	return true, nil
+}
+
+func (d *bundlePackDAO) ChangeStock(id string, amount int) error {
+	pack, _ := d.GetByID(id)
+	pack.Stock += amount
+	return nil
+}
+
+// newBundlePacks returns a data pack, a whatsapp pack, an inactive pack
+// and a pack of another mno.
+func newBundlePacks() *bundlePackDAO {
+	packs := new(bundlePackDAO)
+	for i, name := range []string{"data", "whatsapp", "voice", "sms"} {
+		pack := newClonePack()
+		pack.ID = bson.NewObjectId()
+		pack.ProdID = "CMP" + name
+		pack.Packcode = name
+		pack.Resources = []model.Resource{{ID: int16(i + 1), Name: name, Units: "MB", Amount: 100}}
+		packs.packs = append(packs.packs, pack)
+	}
+	packs.packs[2].State = model.Inactive
+	packs.packs[3].Mno = &model.Mno{ID: 5, Name: "Tigo"}
+	return packs
+}
+
+// newBundle returns a bundle of the packs with the given ids.
+func newBundle(components ...string) *model.Pack {
+	bundle := newClonePack()
+	bundle.ID = ""
+	bundle.ProdID = "COMBO1"
+	bundle.Packcode = "combo1"
+	bundle.Components = components
+	return bundle
+}
+
+// TestCreateBundle tests a bundle gets the resources of valid components
+func TestCreateBundle(t *testing.T) {
+	packs := newBundlePacks()
+	SetPackDAO(packs)
+	defer SetPackDAO(nil)
+	data, whatsapp, voice, sms := packs.packs[0].ID.Hex(), packs.packs[1].ID.Hex(), packs.packs[2].ID.Hex(), packs.packs[3].ID.Hex()
+
+	tests := []struct {
+		name       string
+		components []string
+		want       *Error
+	}{
+		{name: "one component", components: []string{data}, want: ErrBundleComponentsInvalid},
+		{name: "inactive component", components: []string{data, voice}, want: ErrBundleComponentInvalid},
+		{name: "component of other mno", components: []string{data, sms}, want: ErrBundleComponentInvalid},
+		{name: "unknown component", components: []string{data, bson.NewObjectId().Hex()}, want: ErrPackNotFound},
+		{name: "no components", want: ErrBundleComponentsInvalid},
+		{name: "data and whatsapp", components: []string{data, whatsapp}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bundle := newBundle(tt.components...)
+			err := new(BasicPack).CreateBundle(bundle)
+			if tt.want != nil {
+				if !errors.Is(err, tt.want) {
+					t.Errorf("BasicPack.CreateBundle() error = %v, want %v", err, tt.want)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected err to be nil but it was: %s", err)
+			}
+			if len(bundle.Resources) != 2 || bundle.Resources[1].Name != "whatsapp" || bundle.Price != 2000 {
+				t.Errorf("Expected a bundle with the resources of its components but got %+v", bundle)
+			}
+		})
+	}
+
+	// a bundle cannot be a component
+	bundle := packs.packs[len(packs.packs)-1].ID.Hex()
+	err := new(BasicPack).CreateBundle(&model.Pack{ProdID: "COMBO2", Packcode: "combo2", Name: "Combo",
+		Desc: "Combo", Img: "http://img/combo.png", Kwds: "combo", Packtype: &model.Type{ID: 1, Name: "App"},
+		Mno: &model.Mno{ID: 2, Name: "Claro"}, Term: &model.Term{UnitID: 1, Unit: "day", Amount: 2},
+		Components: []string{data, bundle}})
+	if !errors.Is(err, ErrBundleComponentInvalid) {
+		t.Errorf("Expected a bundle not to be a component but got %v", err)
+	}
+}
+
+// TestComponentInUse tests the components of an active bundle are kept
+func TestComponentInUse(t *testing.T) {
+	// GIVEN an active bundle of data and whatsapp
+	packs := newBundlePacks()
+	SetPackDAO(packs)
+	defer SetPackDAO(nil)
+	data, whatsapp := packs.packs[0].ID.Hex(), packs.packs[1].ID.Hex()
+	bundle := newBundle(data, whatsapp)
+	err := new(BasicPack).CreateBundle(bundle)
+	if err != nil {
+		t.Fatalf("Expected err to be nil but it was: %s", err)
+	}
+
+	// WHEN its components are deleted or deactivated
+	// THEN they are not
+	if err := new(BasicPack).Delete(data); !errors.Is(err, ErrComponentInUse) {
+		t.Errorf("Expected the data pack not to be deleted but got %v", err)
+	}
+	if err := new(BasicPack).ChangeState(whatsapp, model.Inactive); !errors.Is(err, ErrComponentInUse) {
+		t.Errorf("Expected the whatsapp pack not to be deactivated but got %v", err)
+	}
+	if err := new(BasicPack).UpdateResources(bundle.ID.Hex(), []model.Resource{}); !errors.Is(err, ErrBundleResources) {
+		t.Errorf("Expected the resources of the bundle not to be replaced but got %v", err)
+	}
+
+	// WHEN the bundle is deactivated
+	err = new(BasicPack).ChangeState(bundle.ID.Hex(), model.Inactive)
+	if err != nil {
+		t.Fatalf("Expected err to be nil but it was: %s", err)
+	}
+	// THEN a component can be deactivated and the bundle is not activated again
+	err = new(BasicPack).ChangeState(whatsapp, model.Inactive)
+	if err != nil {
+		t.Fatalf("Expected err to be nil but it was: %s", err)
+	}
+	err = new(BasicPack).ChangeState(bundle.ID.Hex(), model.Active)
+	if !errors.Is(err, ErrBundleComponentInactive) {
+		t.Errorf("Expected the bundle not to be activated but got %v", err)
+	}
+}
+
+// TestBundleResourcesRefresh tests a bundle gets the new resources of a
+// component
+func TestBundleResourcesRefresh(t *testing.T) {
+	packs := newBundlePacks()
+	SetPackDAO(packs)
+	defer SetPackDAO(nil)
+	data, whatsapp := packs.packs[0].ID.Hex(), packs.packs[1].ID.Hex()
+	bundle := newBundle(data, whatsapp)
+	err := new(BasicPack).CreateBundle(bundle)
+	if err != nil {
+		t.Fatalf("Expected err to be nil but it was: %s", err)
+	}
+
+	err = new(BasicPack).UpdateResources(data, []model.Resource{{ID: 2, Name: "whatsapp", Units: "MB", Amount: 50}})
+
+	if err != nil {
+		t.Fatalf("Expected err to be nil but it was: %s", err)
+	}
+	if len(bundle.Resources) != 1 || bundle.Resources[0].Amount != 150 {
+		t.Errorf("Expected the whatsapp megabytes of both components but got %+v", bundle.Resources)
+	}
+}
+
+// TestReserveBundleStock tests a bundle is sold only if every component
+// has stock
+func TestReserveBundleStock(t *testing.T) {
+	packs := newBundlePacks()
+	SetPackDAO(packs)
+	defer SetPackDAO(nil)
+	packs.packs[1].Stock = 1
+	order := &model.Order{PackID: bson.NewObjectId().Hex(), Components: []string{packs.packs[0].ID.Hex(), packs.packs[1].ID.Hex()}}
+
+	if !reserveStock(order) || packs.packs[0].Stock != 39 || packs.packs[1].Stock != 0 {
+		t.Fatalf("Expected a unit of every component to be reserved but got %d, %d", packs.packs[0].Stock, packs.packs[1].Stock)
+	}
+	if reserveStock(order) {
+		t.Fatalf("Expected the bundle to be out of stock")
+	}
+	if packs.packs[0].Stock != 39 {
+		t.Errorf("Expected the unit of the data pack to be given back but got %d", packs.packs[0].Stock)
+	}
+	releaseStock(order)
+	if packs.packs[0].Stock != 40 || packs.packs[1].Stock != 1 {
+		t.Errorf("Expected the units to be released but got %d, %d", packs.packs[0].Stock, packs.packs[1].Stock)
+	}
+}
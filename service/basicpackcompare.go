@@ -0,0 +1,111 @@
+package service
+
+import (
+	"sort"
+
+	"github.com/fernandoocampo/pack/model"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// maxSimilarPacks is the most packs similarPacks returns.
+const maxSimilarPacks = 50
+
+// ComparePacks implements *IPackService.ComparePacks.
+func (m *BasicPack) ComparePacks(ids []string) (*model.PackComparison, error) {
+	if len(ids) < model.MinComparedPacks || len(ids) > model.MaxComparedPacks {
+		return nil, ErrCompareArgs
+	}
+	seen := map[string]bool{}
+	packs := make([]model.Pack, 0, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return nil, ErrCompareArgs
+		}
+		seen[id] = true
+		if !bson.IsObjectIdHex(id) {
+			return nil, ErrPackNotFound.WithField("ids")
+		}
+		pack, err := m.dao().GetByID(id)
+		if err != nil {
+			return nil, ErrPackNotValidated.Wrap(err)
+		}
+		if pack == nil {
+			return nil, ErrPackNotFound.WithField("ids")
+		}
+		packs = append(packs, *pack)
+	}
+	return model.NewPackComparison(packs), nil
+}
+
+// SimilarPacks implements *IPackService.SimilarPacks.
+func (m *BasicPack) SimilarPacks(id string, filter *model.PackFilter, limit int) ([]model.SimilarPack, error) {
+	if !bson.IsObjectIdHex(id) {
+		return nil, ErrPackNotFound.WithField("id")
+	}
+	if limit < 1 || limit > maxSimilarPacks {
+		limit = maxSimilarPacks
+	}
+	pack, err := m.dao().GetByID(id)
+	if err != nil {
+		return nil, ErrPackNotValidated.Wrap(err)
+	}
+	if pack == nil {
+		return nil, ErrPackNotFound.WithField("id")
+	}
+	if pack.Mno == nil {
+		return []model.SimilarPack{}, nil
+	}
+
+	active := model.Active
+	if filter == nil {
+		filter = new(model.PackFilter)
+	}
+	filter.MnoID = pack.Mno.ID
+	filter.State = &active
+
+	similar := []model.SimilarPack{}
+	err = m.dao().Each(filter, func(candidate *model.Pack) error {
+		if candidate.ID == pack.ID {
+			return nil
+		}
+		score := pack.Similarity(candidate)
+		if len(similar) == limit && score <= similar[limit-1].Score {
+			return nil
+		}
+		instock, err := inStock(candidate)
+		if err != nil || !instock {
+			return err
+		}
+		// keep the best limit packs sorted by score
+		at := sort.Search(len(similar), func(i int) bool { return similar[i].Score < score })
+		similar = append(similar, model.SimilarPack{})
+		copy(similar[at+1:], similar[at:])
+		similar[at] = model.SimilarPack{Pack: *candidate, Score: score}
+		if len(similar) > limit {
+			similar = similar[:limit]
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, ErrPackNotValidated.Wrap(err)
+	}
+	return similar, nil
+}
+
+// inStock returns true if the pack can be sold. A bundle has no stock of
+// its own, it can be sold while every component has stock.
+func inStock(pack *model.Pack) (bool, error) {
+	if !pack.IsBundle() {
+		return pack.Stock > 0, nil
+	}
+	components, err := componentsOf(packDAO, pack)
+	if err != nil {
+		return false, err
+	}
+	for _, component := range components {
+		if component.Stock < 1 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
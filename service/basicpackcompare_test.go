@@ -0,0 +1,70 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/fernandoocampo/pack/model"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// TestComparePacks tests the ids of a comparison
+func TestComparePacks(t *testing.T) {
+	pack, other := newClonePack(), newClonePack()
+	packs := &clonePackDAO{packs: []*model.Pack{pack, other}}
+	SetPackDAO(packs)
+	defer SetPackDAO(nil)
+
+	tests := []struct {
+		name string
+		ids  []string
+		want *Error
+	}{
+		{name: "two packs", ids: []string{pack.ID.Hex(), other.ID.Hex()}},
+		{name: "one pack", ids: []string{pack.ID.Hex()}, want: ErrCompareArgs},
+		{name: "repeated pack", ids: []string{pack.ID.Hex(), pack.ID.Hex()}, want: ErrCompareArgs},
+		{name: "unknown pack", ids: []string{pack.ID.Hex(), bson.NewObjectId().Hex()}, want: ErrPackNotFound},
+		{name: "invalid id", ids: []string{pack.ID.Hex(), "wh12"}, want: ErrPackNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comparison, err := new(BasicPack).ComparePacks(tt.ids)
+			if (tt.want == nil && err != nil) || (tt.want != nil && !tt.want.Is(err)) {
+				t.Fatalf("BasicPack.ComparePacks() error = %v, want %v", err, tt.want)
+			}
+			if tt.want == nil && len(comparison.Packs) != len(tt.ids) {
+				t.Errorf("Expected %d packs but got %+v", len(tt.ids), comparison.Packs)
+			}
+		})
+	}
+}
+
+// TestSimilarPacks tests only active packs in stock of the mno are
+// ranked, the most similar first
+func TestSimilarPacks(t *testing.T) {
+	source := newClonePack()
+	source.Stock = 0
+	packs := &eligibilityPackDAO{}
+	packs.packs = append(packs.packs, source)
+	for _, price := range []int{9000, 2500, 2000, 2100, 2200} {
+		pack := newClonePack()
+		pack.Price = price
+		packs.packs = append(packs.packs, pack)
+	}
+	packs.packs[3].Stock = 0
+	packs.packs[4].State = model.Inactive
+	packs.packs[5].Mno = &model.Mno{ID: 3, Name: "Tigo"}
+	SetPackDAO(packs)
+	defer SetPackDAO(nil)
+
+	similar, err := new(BasicPack).SimilarPacks(source.ID.Hex(), nil, 2)
+
+	if err != nil {
+		t.Fatalf("Expected err to be nil but it was: %s", err)
+	}
+	if len(similar) != 2 || similar[0].Pack.Price != 2500 || similar[1].Pack.Price != 9000 {
+		t.Fatalf("Expected the packs of 2500 and 9000 but got %+v", similar)
+	}
+	if similar[0].Score <= similar[1].Score {
+		t.Errorf("Expected the first pack to be the most similar but got %v and %v", similar[0].Score, similar[1].Score)
+	}
+}
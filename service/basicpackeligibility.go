@@ -0,0 +1,102 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/fernandoocampo/pack/model"
+)
+
+// maxEligiblePacks is the most packs eligiblePacks returns.
+const maxEligiblePacks = 100
+
+// errEnoughPacks stops reading packs once there are enough eligible ones.
+var errEnoughPacks = errors.New("enough eligible packs")
+
+// SetRules implements *IPackService.SetRules.
+func (m *BasicPack) SetRules(id string, rules []model.EligibilityRule) error {
+	if id == "" {
+		return ErrEligibilityRuleInvalid.WithField("id")
+	}
+	if field := model.InvalidRulesField(rules); field != "" {
+		return ErrEligibilityRuleInvalid.WithField(field)
+	}
+
+	pack, err := m.dao().GetByID(id)
+	if err != nil {
+		return ErrPackNotValidated.Wrap(err)
+	}
+	if pack == nil {
+		return ErrPackNotFound.WithField("id")
+	}
+
+	return m.dao().UpdateRules(id, rules)
+}
+
+// CheckEligibility implements *IPackService.CheckEligibility.
+func (m *BasicPack) CheckEligibility(id string, customer *model.CustomerContext) (*model.Eligibility, error) {
+	if id == "" {
+		return nil, ErrPackNotFound.WithField("id")
+	}
+	pack, err := m.dao().GetByID(id)
+	if err != nil {
+		return nil, ErrPackNotValidated.Wrap(err)
+	}
+	if pack == nil {
+		return nil, ErrPackNotFound.WithField("id")
+	}
+	err = addPurchases(customer)
+	if err != nil {
+		return nil, err
+	}
+	return pack.CheckEligibility(customer), nil
+}
+
+// EligiblePacks implements *IPackService.EligiblePacks.
+func (m *BasicPack) EligiblePacks(customer *model.CustomerContext, filter *model.PackFilter, limit int) ([]model.Pack, error) {
+	if limit < 1 || limit > maxEligiblePacks {
+		limit = maxEligiblePacks
+	}
+	err := addPurchases(customer)
+	if err != nil {
+		return nil, err
+	}
+	active := model.Active
+	if filter == nil {
+		filter = new(model.PackFilter)
+	}
+	filter.State = &active
+
+	packs := []model.Pack{}
+	err = m.dao().Each(filter, func(pack *model.Pack) error {
+		if !pack.CheckEligibility(customer).Eligible {
+			return nil
+		}
+		packs = append(packs, *pack)
+		if len(packs) == limit {
+			return errEnoughPacks
+		}
+		return nil
+	})
+	if err != nil && err != errEnoughPacks {
+		return nil, ErrPackNotValidated.Wrap(err)
+	}
+	return packs, nil
+}
+
+// addPurchases adds the pack codes of the completed orders of the
+// customer line to its purchases.
+func addPurchases(customer *model.CustomerContext) error {
+	if customer == nil || customer.Msisdn == "" {
+		return nil
+	}
+	orders, err := orderDAO.GetByMsisdn(customer.Msisdn)
+	if err != nil {
+		return ErrEligibilityNotChecked.Wrap(err)
+	}
+	for _, order := range orders {
+		if order.State == model.OrderCompleted {
+			customer.Purchases = append(customer.Purchases, order.Packcode)
+		}
+	}
+	return nil
+}
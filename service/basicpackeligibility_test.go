@@ -0,0 +1,112 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/model"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// eligibilityPackDAO keeps the packs in memory with the methods the
+// eligibility rules use.
+type eligibilityPackDAO struct {
+	clonePackDAO
+}
+
+func (d *eligibilityPackDAO) Each(filter *model.PackFilter, fn func(pack *model.Pack) error) error {
+	for _, pack := range d.packs {
+		if filter.Matches(pack) {
+			copied := *pack
+			if err := fn(&copied); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (d *eligibilityPackDAO) UpdateRules(id string, rules []model.EligibilityRule) error {
+	pack, _ := d.GetByID(id)
+	pack.Rules = rules
+	return nil
+}
+
+// eligibilityOrderDAO returns the orders of a line.
+type eligibilityOrderDAO struct {
+	dao.IOrderDAO
+	orders []model.Order
+}
+
+func (d *eligibilityOrderDAO) GetByMsisdn(msisdn string) ([]model.Order, error) {
+	return d.orders, nil
+}
+
+// TestEligiblePacks tests only active packs whose rules the customer
+// meets are returned, orders of the line count as purchases
+func TestEligiblePacks(t *testing.T) {
+	// GIVEN a pack for everyone, a pack for who bought wh10 and an
+	// inactive pack for everyone
+	packs := &eligibilityPackDAO{}
+	for _, packcode := range []string{"wh11", "wh12", "wh13"} {
+		pack := newClonePack()
+		pack.ID = bson.NewObjectId()
+		pack.Packcode = packcode
+		packs.packs = append(packs.packs, pack)
+	}
+	packs.packs[2].State = model.Inactive
+	orders := &eligibilityOrderDAO{orders: []model.Order{{Packcode: "wh10", State: model.OrderFailed}}}
+	SetPackDAO(packs)
+	SetOrderDAO(orders)
+	defer SetPackDAO(nil)
+	defer SetOrderDAO(nil)
+	loyal := []model.EligibilityRule{{Name: "loyal", Purchased: []string{"wh10"}}}
+	err := new(BasicPack).SetRules(packs.packs[1].ID.Hex(), loyal)
+	if err != nil {
+		t.Fatalf("Expected err to be nil but it was: %s", err)
+	}
+
+	// WHEN the line did not complete the order of wh10
+	eligible, err := new(BasicPack).EligiblePacks(&model.CustomerContext{Msisdn: "3001234567"}, nil, 10)
+
+	// THEN only the pack for everyone is eligible
+	if err != nil || len(eligible) != 1 || eligible[0].Packcode != "wh11" {
+		t.Fatalf("Expected only pack wh11 but got %+v, %v", eligible, err)
+	}
+	eligibility, err := new(BasicPack).CheckEligibility(packs.packs[1].ID.Hex(), &model.CustomerContext{Msisdn: "3001234567"})
+	if err != nil || eligibility.Eligible || eligibility.Failures[0].Condition != model.ConditionPurchased {
+		t.Errorf("Expected the purchased condition to fail but got %+v, %v", eligibility, err)
+	}
+
+	// WHEN the order of wh10 is completed
+	orders.orders[0].State = model.OrderCompleted
+	eligible, err = new(BasicPack).EligiblePacks(&model.CustomerContext{Msisdn: "3001234567"}, nil, 10)
+
+	// THEN the pack for who bought it is eligible too
+	if err != nil || len(eligible) != 2 {
+		t.Errorf("Expected packs wh11 and wh12 but got %+v, %v", eligible, err)
+	}
+}
+
+// TestSetRules tests the rules are validated before they are stored
+func TestSetRules(t *testing.T) {
+	pack := newClonePack()
+	packs := &eligibilityPackDAO{clonePackDAO{packs: []*model.Pack{pack}}}
+	SetPackDAO(packs)
+	defer SetPackDAO(nil)
+
+	err := new(BasicPack).SetRules(pack.ID.Hex(), []model.EligibilityRule{{Name: "corporate", PlanTypes: []string{"corporate"}}})
+
+	var catalogerr *Error
+	if !errors.As(err, &catalogerr) || catalogerr.Code != ErrEligibilityRuleInvalid.Code || catalogerr.Field != "plantypes" {
+		t.Errorf("Expected the plan types to be invalid but got %v", err)
+	}
+	if pack.Rules != nil {
+		t.Errorf("Expected the rules not to be stored but got %+v", pack.Rules)
+	}
+	err = new(BasicPack).SetRules(bson.NewObjectId().Hex(), nil)
+	if !errors.Is(err, ErrPackNotFound) {
+		t.Errorf("Expected an unknown pack but got %v", err)
+	}
+}
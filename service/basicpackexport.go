@@ -0,0 +1,38 @@
+package service
+
+import (
+	"io"
+
+	"github.com/fernandoocampo/pack/model"
+)
+
+// maxListResults is the most packs a list returns.
+const maxListResults = 500
+
+// List implements *IPackService.List.
+func (m *BasicPack) List(filter *model.PackFilter, skip int, limit int) ([]model.Pack, error) {
+	if skip < 0 {
+		skip = 0
+	}
+	if limit < 1 || limit > maxListResults {
+		limit = maxListResults
+	}
+	return m.dao().List(filter, skip, limit)
+}
+
+// Export implements *IPackService.Export.
+func (m *BasicPack) Export(w io.Writer, format string, filter *model.PackFilter) error {
+	writer, err := model.NewPackWriter(w, format)
+	if err != nil {
+		return ErrExportFormat
+	}
+	err = m.dao().Each(filter, writer.Write)
+	if err != nil {
+		return ErrExportFailed.Wrap(err)
+	}
+	err = writer.Close()
+	if err != nil {
+		return ErrExportFailed.Wrap(err)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/fernandoocampo/pack/model"
+)
+
+// ImportPacks implements *IPackService.ImportPacks.
+func (m *BasicPack) ImportPacks(file io.Reader, options model.ImportOptions) (*model.ImportReport, error) {
+	if options.Format != model.ImportCSV && options.Format != model.ImportJSONLines {
+		return nil, ErrImportFormat
+	}
+	if options.Mode != model.ImportCreate && options.Mode != model.ImportUpsert {
+		return nil, ErrImportMode
+	}
+	rows, err := model.ReadImportRows(file, options.Format)
+	if err != nil {
+		var columnerr *model.ImportColumnError
+		if errors.As(err, &columnerr) {
+			return nil, ErrImportFile.Wrap(err).WithField(columnerr.Column)
+		}
+		return nil, ErrImportFile.Wrap(err)
+	}
+
+	report := model.NewImportReport(options)
+	seen := map[string]bool{}
+	for _, row := range rows {
+		result := model.ImportRowResult{Line: row.Line}
+		if row.Pack != nil {
+			result.Packcode = row.Pack.Packcode
+			result.ProdID = row.Pack.ProdID
+		}
+		action, packid, err := m.importRow(row, options, seen)
+		if err != nil {
+			catalogerr := AsError(err)
+			if catalogerr.Category == CategoryInternal || catalogerr.Category == CategoryUnavailable {
+				log.Errorf("importing line %d: %v", row.Line, catalogerr)
+			}
+			result.Action = model.ImportFailed
+			result.Error = catalogerr.Detail(options.Locale)
+		} else {
+			result.Action = action
+			result.PackID = packid
+		}
+		report.Add(result)
+	}
+	return report, nil
+}
+
+// importRow creates or updates the pack of a row, nothing is stored in a
+// dry run. seen contains the keys of the rows already imported.
+func (m *BasicPack) importRow(row model.ImportRow, options model.ImportOptions, seen map[string]bool) (string, string, error) {
+	if row.Err != nil {
+		return "", "", ErrImportRowInvalid.Wrap(row.Err).WithField(row.Column)
+	}
+	pack := row.Pack
+	err := isValidPackToCreate(pack)
+	if err != nil {
+		return "", "", err
+	}
+	if !m.owns(pack) {
+		return "", "", ErrNotOwner
+	}
+	err = seenKeys(seen, pack)
+	if err != nil {
+		return "", "", err
+	}
+	err = validateOwner(pack.Ownerid, pack.Mno.ID)
+	if err != nil {
+		return "", "", err
+	}
+
+	// packs are unique across tenants so they are looked up unscoped
+	existing, err := packDAO.GetByKeys(model.NewPackExists(pack))
+	if err != nil {
+		return "", "", ErrPackNotValidated.Wrap(err)
+	}
+	if existing == nil {
+		if options.DryRun {
+			return model.ImportCreated, "", nil
+		}
+		pack.State = model.Active
+		pack.Created = time.Now()
+		pack.Updated = time.Now()
+		err = m.dao().Create(pack)
+		if err != nil {
+			return "", "", err
+		}
+		return model.ImportCreated, pack.ID.Hex(), nil
+	}
+
+	// an existing pack is only updated if both keys are its own and the
+	// tenant can see it
+	if options.Mode == model.ImportCreate || existing.Packcode != pack.Packcode ||
+		existing.ProdID != pack.ProdID || !m.owns(existing) {
+		return "", "", ErrPackDuplicated
+	}
+	if existing.Price != pack.Price && !options.ChangePrice {
+		return "", "", ErrForbidden.WithField("price")
+	}
+	if existing.Ownerid != pack.Ownerid && !options.ChangeOwner {
+		return "", "", ErrForbidden.WithField("ownerid")
+	}
+	// the resources of a bundle come from its components
+	if existing.IsBundle() {
+		pack.Resources = existing.Resources
+	}
+	packid := existing.ID.Hex()
+	if !options.DryRun {
+		err = m.dao().Update(packid, pack)
+		if err != nil {
+			return "", "", err
+		}
+		refreshBundles(packid)
+	}
+	return model.ImportUpdated, packid, nil
+}
+
+// seenKeys returns ErrImportRowRepeated if an earlier row of the file has
+// the pack code or the product id of the pack in the same mno, otherwise
+// it keeps the keys of the pack.
+func seenKeys(seen map[string]bool, pack *model.Pack) error {
+	mno := strconv.Itoa(int(pack.Mno.ID))
+	packcode := mno + "/packcode/" + pack.Packcode
+	prodid := mno + "/prodid/" + pack.ProdID
+	if seen[packcode] {
+		return ErrImportRowRepeated
+	}
+	if seen[prodid] {
+		return ErrImportRowRepeated.WithField("prodid")
+	}
+	seen[packcode] = true
+	seen[prodid] = true
+	return nil
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/model"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// importPackDAO keeps the packs in memory, the methods the import does
+// not use are left to the embedded nil dao.
+type importPackDAO struct {
+	dao.IPackDAO
+	packs   []*model.Pack
+	created int
+	updated int
+}
+
+func (d *importPackDAO) GetByKeys(keys *model.PackExists) (*model.Pack, error) {
+	for _, pack := range d.packs {
+		if pack.Mno.ID == keys.MnoID && (pack.Packcode == keys.Packcode || pack.ProdID == keys.ProdID) {
+			return pack, nil
+		}
+	}
+	return nil, nil
+}
+
+func (d *importPackDAO) Create(packdata *model.Pack) error {
+	packdata.ID = bson.NewObjectId()
+	d.packs = append(d.packs, packdata)
+	d.created++
+	return nil
+}
+
+func (d *importPackDAO) Update(id string, packdata *model.Pack) error {
+	d.updated++
+	return nil
+}
+
+func (d *importPackDAO) GetBundlesOf(id string) ([]model.Pack, error) {
+	return []model.Pack{}, nil
+}
+
+const importFile = "prodid,packcode,name,desc,imgurl,kwds,price,typeid,typename,mnoid,mnoname,termunitid,termunit,termamount,currencyid,currencyname\n" +
+	"WAPP01,0008,Whatsapp weekend,Chat all weekend,http://img/wapp.png,chat,2000,1,Whatsapp,2,Claro,1,day,2,1,COP\n" +
+	"WAPP02,0009,Whatsapp week,Chat all week,http://img/wapp.png,chat,5000,1,Whatsapp,2,Claro,1,day,7,1,COP\n" +
+	"WAPP03,0010,,Chat all month,http://img/wapp.png,chat,9000,1,Whatsapp,2,Claro,1,day,30,1,COP\n" +
+	"WAPP04,0009,Whatsapp week,Chat all week,http://img/wapp.png,chat,5000,1,Whatsapp,2,Claro,1,day,7,1,COP\n" +
+	"WAPP05,0011,Whatsapp day,Chat all day,http://img/wapp.png,chat,five,1,Whatsapp,2,Claro,1,day,1,1,COP\n"
+
+// TestImportPacks tests every row is imported or reported on its own
+func TestImportPacks(t *testing.T) {
+	// GIVEN the pack 0008 already exists
+	packs := &importPackDAO{packs: []*model.Pack{{ID: bson.NewObjectId(), ProdID: "WAPP01", Packcode: "0008", Mno: &model.Mno{ID: 2}}}}
+	SetPackDAO(packs)
+	defer SetPackDAO(nil)
+
+	tests := []struct {
+		name        string
+		options     model.ImportOptions
+		wantActions []string
+		wantCodes   []string
+		wantStored  [2]int
+	}{
+		{
+			name:        "create only",
+			options:     model.ImportOptions{Format: model.ImportCSV, Mode: model.ImportCreate, DryRun: true},
+			wantActions: []string{model.ImportFailed, model.ImportCreated, model.ImportFailed, model.ImportFailed, model.ImportFailed},
+			wantCodes:   []string{ErrPackDuplicated.Code, "", ErrPackKeysEmpty.Code, ErrImportRowRepeated.Code, ErrImportRowInvalid.Code},
+		},
+		{
+			name:        "upsert",
+			options:     model.ImportOptions{Format: model.ImportCSV, Mode: model.ImportUpsert, ChangePrice: true},
+			wantActions: []string{model.ImportUpdated, model.ImportCreated, model.ImportFailed, model.ImportFailed, model.ImportFailed},
+			wantCodes:   []string{"", "", ErrPackKeysEmpty.Code, ErrImportRowRepeated.Code, ErrImportRowInvalid.Code},
+			wantStored:  [2]int{1, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// WHEN the file is imported
+			report, err := new(BasicPack).ImportPacks(strings.NewReader(importFile), tt.options)
+
+			// THEN every row has its result and only a real run stores packs
+			if err != nil {
+				t.Fatalf("Expected err to be nil but it was: %s", err)
+			}
+			if len(report.Rows) != len(tt.wantActions) {
+				t.Fatalf("Expected %d rows but got %+v", len(tt.wantActions), report.Rows)
+			}
+			for i, row := range report.Rows {
+				code := ""
+				if row.Error != nil {
+					code = row.Error.Code
+				}
+				if row.Line != i+2 || row.Action != tt.wantActions[i] || code != tt.wantCodes[i] {
+					t.Errorf("Expected line %d to be %s %s but got %+v %+v", i+2, tt.wantActions[i], tt.wantCodes[i], row, row.Error)
+				}
+			}
+			if packs.created != tt.wantStored[0] || packs.updated != tt.wantStored[1] {
+				t.Errorf("Expected %v packs created and updated but got %d %d", tt.wantStored, packs.created, packs.updated)
+			}
+		})
+	}
+}
+
+// TestImportPacksOptions tests an import with wrong options or file fails as a whole
+func TestImportPacksOptions(t *testing.T) {
+	tests := []struct {
+		options model.ImportOptions
+		file    string
+		want    *Error
+	}{
+		{options: model.ImportOptions{Format: "xml", Mode: model.ImportCreate}, want: ErrImportFormat},
+		{options: model.ImportOptions{Format: model.ImportCSV, Mode: "replace"}, want: ErrImportMode},
+		{options: model.ImportOptions{Format: model.ImportCSV, Mode: model.ImportCreate}, file: "prodid,packcode\n", want: ErrImportFile},
+	}
+	for _, tt := range tests {
+		_, err := new(BasicPack).ImportPacks(strings.NewReader(tt.file), tt.options)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("Expected %v but got %v", tt.want, err)
+		}
+	}
+}
+
+// TestImportPacksChanges tests an upsert only changes the price and the
+// owner of existing packs if the caller can change them
+func TestImportPacksChanges(t *testing.T) {
+	// GIVEN the pack 0008 exists with a price of 1500
+	packs := &importPackDAO{packs: []*model.Pack{{ID: bson.NewObjectId(), ProdID: "WAPP01", Packcode: "0008", Price: 1500, Mno: &model.Mno{ID: 2}}}}
+	SetPackDAO(packs)
+	defer SetPackDAO(nil)
+	file := strings.Split(importFile, "\n")[0] + "\n" + strings.Split(importFile, "\n")[1] + "\n"
+
+	tests := []struct {
+		name     string
+		options  model.ImportOptions
+		wantCode string
+	}{
+		{
+			name:     "catalog editor",
+			options:  model.ImportOptions{Format: model.ImportCSV, Mode: model.ImportUpsert},
+			wantCode: ErrForbidden.Code,
+		},
+		{
+			name:    "pricing manager",
+			options: model.ImportOptions{Format: model.ImportCSV, Mode: model.ImportUpsert, ChangePrice: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// WHEN a file with another price is imported
+			report, err := new(BasicPack).ImportPacks(strings.NewReader(file), tt.options)
+
+			// THEN the price is only changed by a caller that can change it
+			if err != nil || len(report.Rows) != 1 {
+				t.Fatalf("Expected the report of a row but got %+v %v", report, err)
+			}
+			row := report.Rows[0]
+			if tt.wantCode == "" && row.Action != model.ImportUpdated {
+				t.Errorf("Expected the pack to be updated but got %+v %+v", row, row.Error)
+			}
+			if tt.wantCode != "" && (row.Action != model.ImportFailed || row.Error.Code != tt.wantCode ||
+				row.Error.Category != string(CategoryForbidden) || row.Error.Field != "price") {
+				t.Errorf("Expected the row to be forbidden but got %+v %+v", row, row.Error)
+			}
+		})
+	}
+	if packs.updated != 1 {
+		t.Errorf("Expected 1 pack updated but got %d", packs.updated)
+	}
+}
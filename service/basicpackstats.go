@@ -0,0 +1,15 @@
+package service
+
+import "github.com/fernandoocampo/pack/model"
+
+// CatalogStats implements *IPackService.CatalogStats.
+func (m *BasicPack) CatalogStats(filter *model.PackFilter, groupBy []string) ([]model.CatalogStats, error) {
+	if invalid := model.InvalidStatsGroup(groupBy); invalid != "" {
+		return nil, ErrStatsGroupInvalid
+	}
+	stats, err := m.dao().Stats(filter, groupBy)
+	if err != nil {
+		return nil, ErrStatsFailed.Wrap(err)
+	}
+	return stats, nil
+}
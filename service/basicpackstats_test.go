@@ -0,0 +1,57 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/fernandoocampo/pack/model"
+)
+
+// statsPackDAO calculates the stats of its packs with a StatsAccumulator.
+type statsPackDAO struct {
+	clonePackDAO
+}
+
+func (d *statsPackDAO) Stats(filter *model.PackFilter, groupBy []string) ([]model.CatalogStats, error) {
+	accumulator := model.NewStatsAccumulator(groupBy)
+	for _, pack := range d.packs {
+		if filter.Matches(pack) {
+			accumulator.Add(pack)
+		}
+	}
+	return accumulator.Stats(), nil
+}
+
+// TestCatalogStats tests the group fields are validated before the stats
+// are calculated
+func TestCatalogStats(t *testing.T) {
+	inactive := newClonePack()
+	inactive.State = model.Inactive
+	packs := &statsPackDAO{}
+	packs.packs = []*model.Pack{newClonePack(), newClonePack(), inactive}
+	SetPackDAO(packs)
+	defer SetPackDAO(nil)
+
+	tests := []struct {
+		name    string
+		groupBy []string
+		groups  int
+		want    *Error
+	}{
+		{name: "no group", groupBy: nil, groups: 1},
+		{name: "by state", groupBy: []string{model.StatsByState}, groups: 2},
+		{name: "by mno and state", groupBy: []string{model.StatsByMno, model.StatsByState}, groups: 2},
+		{name: "unknown field", groupBy: []string{"price"}, want: ErrStatsGroupInvalid},
+		{name: "repeated field", groupBy: []string{model.StatsByMno, model.StatsByMno}, want: ErrStatsGroupInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats, err := new(BasicPack).CatalogStats(nil, tt.groupBy)
+			if (tt.want == nil && err != nil) || (tt.want != nil && !tt.want.Is(err)) {
+				t.Fatalf("BasicPack.CatalogStats() error = %v, want %v", err, tt.want)
+			}
+			if len(stats) != tt.groups {
+				t.Errorf("Expected %d groups but got %+v", tt.groups, stats)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"time"
+
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/model"
+)
+
+// packTemplateDAO makes references to pack template DAO
+var packTemplateDAO dao.IPackTemplateDAO
+
+// Clone implements *IPackService.Clone.
+func (m *BasicPack) Clone(id string, overrides *model.PackOverrides) (*model.Pack, error) {
+	if id == "" || overrides == nil || overrides.ProdID == "" || overrides.Packcode == "" {
+		return nil, ErrCloneArgs
+	}
+	source, err := m.dao().GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if source == nil {
+		return nil, ErrPackNotFound.WithField("id")
+	}
+	clone := source.Copy()
+	overrides.Apply(clone)
+	if !m.owns(clone) {
+		return nil, ErrNotOwner
+	}
+	err = m.Create(clone)
+	if err != nil {
+		return nil, err
+	}
+	return clone, nil
+}
+
+// CreateTemplate implements *IPackService.CreateTemplate. The pack of the
+// template is validated as create does with every placeholder replaced by
+// its name.
+func (m *BasicPack) CreateTemplate(template *model.PackTemplate) error {
+	if template == nil {
+		return ErrTemplateInvalid
+	}
+	if field := template.InvalidField(); field != "" {
+		return ErrTemplateInvalid.WithField(field)
+	}
+	sample := map[string]string{}
+	for _, placeholder := range template.Placeholders {
+		sample[placeholder] = placeholder
+	}
+	pack := template.Render(sample)
+	err := isValidPackToCreate(pack)
+	if err != nil {
+		return err
+	}
+	if !m.owns(pack) {
+		return ErrNotOwner
+	}
+	err = validateOwner(pack.Ownerid, pack.Mno.ID)
+	if err != nil {
+		return err
+	}
+	err = validateAvailability(pack.Channels, pack.Regions)
+	if err != nil {
+		return err
+	}
+
+	existing, err := packTemplateDAO.GetByName(template.Name)
+	if err != nil {
+		return ErrTemplateInvalid.Wrap(err)
+	}
+	if existing != nil {
+		return ErrTemplateDuplicated
+	}
+	template.Created = time.Now()
+	return packTemplateDAO.Create(template)
+}
+
+// GetTemplate implements *IPackService.GetTemplate.
+func (m *BasicPack) GetTemplate(id string) (*model.PackTemplate, error) {
+	if id == "" {
+		return nil, ErrTemplateNotFound
+	}
+	template, err := packTemplateDAO.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if template == nil || !m.owns(template.Pack) {
+		return nil, ErrTemplateNotFound
+	}
+	return template, nil
+}
+
+// GetTemplates implements *IPackService.GetTemplates.
+func (m *BasicPack) GetTemplates() ([]model.PackTemplate, error) {
+	templates, err := packTemplateDAO.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	result := []model.PackTemplate{}
+	for _, template := range templates {
+		if m.owns(template.Pack) {
+			result = append(result, template)
+		}
+	}
+	return result, nil
+}
+
+// DeleteTemplate implements *IPackService.DeleteTemplate.
+func (m *BasicPack) DeleteTemplate(id string) error {
+	_, err := m.GetTemplate(id)
+	if err != nil {
+		return err
+	}
+	return packTemplateDAO.Delete(id)
+}
+
+// CreateFromTemplate implements *IPackService.CreateFromTemplate.
+func (m *BasicPack) CreateFromTemplate(id string, values map[string]string) (*model.Pack, error) {
+	template, err := m.GetTemplate(id)
+	if err != nil {
+		return nil, err
+	}
+	if name := template.UnknownValue(values); name != "" {
+		return nil, ErrTemplateValueUnknown.WithField(name)
+	}
+	if placeholder := template.MissingValue(values); placeholder != "" {
+		return nil, ErrTemplateValueMissing.WithField(placeholder)
+	}
+	pack := template.Render(values)
+	if !m.owns(pack) {
+		return nil, ErrNotOwner
+	}
+	err = m.Create(pack)
+	if err != nil {
+		return nil, err
+	}
+	return pack, nil
+}
+
+// SetPackTemplateDAO set the pack template dao for this business logic.
+func SetPackTemplateDAO(dao dao.IPackTemplateDAO) {
+	packTemplateDAO = dao
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/model"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// clonePackDAO keeps the packs in memory, the methods clones and templates
+// do not use are left to the embedded nil dao.
+type clonePackDAO struct {
+	dao.IPackDAO
+	packs []*model.Pack
+}
+
+func (d *clonePackDAO) GetByID(id string) (*model.Pack, error) {
+	for _, pack := range d.packs {
+		if pack.ID.Hex() == id {
+			return pack, nil
+		}
+	}
+	return nil, nil
+}
+
+func (d *clonePackDAO) IsThereThisPack(keys *model.PackExists) (bool, error) {
+	for _, pack := range d.packs {
+		if pack.Mno.ID == keys.MnoID && (pack.Packcode == keys.Packcode || pack.ProdID == keys.ProdID) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (d *clonePackDAO) Create(packdata *model.Pack) error {
+	packdata.ID = bson.NewObjectId()
+	d.packs = append(d.packs, packdata)
+	return nil
+}
+
+// templateMemDAO keeps the templates in memory.
+type templateMemDAO struct {
+	dao.IPackTemplateDAO
+	templates []*model.PackTemplate
+}
+
+func (d *templateMemDAO) Create(template *model.PackTemplate) error {
+	template.ID = bson.NewObjectId()
+	d.templates = append(d.templates, template)
+	return nil
+}
+
+func (d *templateMemDAO) GetByID(id string) (*model.PackTemplate, error) {
+	for _, template := range d.templates {
+		if template.ID.Hex() == id {
+			return template, nil
+		}
+	}
+	return nil, nil
+}
+
+func (d *templateMemDAO) GetByName(name string) (*model.PackTemplate, error) {
+	for _, template := range d.templates {
+		if template.Name == name {
+			return template, nil
+		}
+	}
+	return nil, nil
+}
+
+func newClonePack() *model.Pack {
+	return &model.Pack{ID: bson.NewObjectId(), ProdID: "WAPP12", Packcode: "wh12", Name: "Whatsapp weekend 12",
+		Desc: "Chat all weekend", Img: "http://img/wapp.png", Kwds: "chat", Price: 2000, Stock: 40,
+		Packtype: &model.Type{ID: 1, Name: "App"}, Mno: &model.Mno{ID: 2, Name: "Claro"},
+		Term: &model.Term{UnitID: 1, Unit: "day", Amount: 2}, State: model.Active}
+}
+
+// TestClone tests a clone gets its own keys and the validations of create
+func TestClone(t *testing.T) {
+	source := newClonePack()
+	packs := &clonePackDAO{packs: []*model.Pack{source}}
+	SetPackDAO(packs)
+	defer SetPackDAO(nil)
+	price := 2500
+
+	tests := []struct {
+		name      string
+		id        string
+		overrides *model.PackOverrides
+		want      *Error
+	}{
+		{name: "new keys", id: source.ID.Hex(), overrides: &model.PackOverrides{ProdID: "WAPP13", Packcode: "wh13", Price: &price}},
+		{name: "same pack code", id: source.ID.Hex(), overrides: &model.PackOverrides{ProdID: "WAPP14", Packcode: "wh12"}, want: ErrPackDuplicated},
+		{name: "no pack code", id: source.ID.Hex(), overrides: &model.PackOverrides{ProdID: "WAPP14"}, want: ErrCloneArgs},
+		{name: "unknown pack", id: bson.NewObjectId().Hex(), overrides: &model.PackOverrides{ProdID: "WAPP14", Packcode: "wh14"}, want: ErrPackNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clone, err := new(BasicPack).Clone(tt.id, tt.overrides)
+			if tt.want != nil {
+				if !errors.Is(err, tt.want) {
+					t.Errorf("BasicPack.Clone() error = %v, want %v", err, tt.want)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected err to be nil but it was: %s", err)
+			}
+			if clone.ID == source.ID || clone.Packcode != "wh13" || clone.Price != 2500 || clone.Stock != 0 ||
+				clone.Name != source.Name {
+				t.Errorf("Expected a copy with the overrides but got %+v", clone)
+			}
+		})
+	}
+}
+
+// TestCreateFromTemplate tests a series of packs is created from a template
+func TestCreateFromTemplate(t *testing.T) {
+	packs := &clonePackDAO{}
+	templates := &templateMemDAO{}
+	SetPackDAO(packs)
+	SetPackTemplateDAO(templates)
+	defer SetPackDAO(nil)
+	defer SetPackTemplateDAO(nil)
+
+	// GIVEN a template of the weekend packs
+	pack := newClonePack()
+	pack.ProdID = "WAPP{{number}}"
+	pack.Packcode = "wh{{number}}"
+	pack.Name = "Whatsapp weekend {{number}}"
+	err := new(BasicPack).CreateTemplate(model.NewPackTemplate("weekend", pack))
+	if err != nil {
+		t.Fatalf("Expected err to be nil but it was: %s", err)
+	}
+	err = new(BasicPack).CreateTemplate(model.NewPackTemplate("weekend", pack))
+	if !errors.Is(err, ErrTemplateDuplicated) {
+		t.Errorf("Expected a duplicated template but got %v", err)
+	}
+	id := templates.templates[0].ID.Hex()
+
+	// WHEN packs are created with it
+	created, err := new(BasicPack).CreateFromTemplate(id, map[string]string{"number": "13"})
+
+	// THEN they have the values in their texts
+	if err != nil || created.Packcode != "wh13" || created.Name != "Whatsapp weekend 13" {
+		t.Fatalf("Expected pack wh13 but got %+v, %v", created, err)
+	}
+	_, err = new(BasicPack).CreateFromTemplate(id, map[string]string{"number": "13"})
+	if !errors.Is(err, ErrPackDuplicated) {
+		t.Errorf("Expected a duplicated pack but got %v", err)
+	}
+	_, err = new(BasicPack).CreateFromTemplate(id, map[string]string{})
+	var catalogerr *Error
+	if !errors.As(err, &catalogerr) || catalogerr.Code != ErrTemplateValueMissing.Code || catalogerr.Field != "number" {
+		t.Errorf("Expected the number to be missing but got %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"github.com/fernandoocampo/pack/model"
+)
+
+// maxSearchResults is the most packs a search returns.
+const maxSearchResults = 100
+
+// contentLocale is the locale of the pack texts when the mno has none.
+var contentLocale = "es"
+
+// mnoLocales contains the locale of the pack texts of every mno.
+var mnoLocales = map[int8]string{}
+
+// ContentLocale returns the locale of the name, description and keywords
+// of the packs of the given mno.
+func ContentLocale(mnoid int8) string {
+	if locale, ok := mnoLocales[mnoid]; ok {
+		return locale
+	}
+	return contentLocale
+}
+
+// SetTranslation implements *IPackService.SetTranslation.
+func (m *BasicPack) SetTranslation(id string, translation model.Translation) error {
+	if id == "" || !translation.IsValid() {
+		return ErrTranslationInvalid
+	}
+
+	pack, err := m.dao().GetByID(id)
+	if err != nil {
+		return ErrPackNotValidated.Wrap(err)
+	}
+	if pack == nil {
+		return ErrPackNotFound.WithField("id")
+	}
+	if model.NormalizeLocale(translation.Locale) == ContentLocale(mnoOf(pack)) {
+		return ErrTranslationDefaultLocale
+	}
+	pack.SetTranslation(translation)
+
+	return m.dao().UpdateTranslations(id, pack.Translations)
+}
+
+// RemoveTranslation implements *IPackService.RemoveTranslation.
+func (m *BasicPack) RemoveTranslation(id string, locale string) error {
+	if id == "" || model.NormalizeLocale(locale) == "" {
+		return ErrTranslationInvalid
+	}
+
+	pack, err := m.dao().GetByID(id)
+	if err != nil {
+		return ErrPackNotValidated.Wrap(err)
+	}
+	if pack == nil {
+		return ErrPackNotFound.WithField("id")
+	}
+	if !pack.RemoveTranslation(locale) {
+		return ErrTranslationNotFound
+	}
+
+	return m.dao().UpdateTranslations(id, pack.Translations)
+}
+
+// Search implements *IPackService.Search.
+func (m *BasicPack) Search(text string, limit int) ([]model.Pack, error) {
+	if limit < 1 || limit > maxSearchResults {
+		limit = maxSearchResults
+	}
+	return m.dao().Search(text, limit)
+}
+
+// mnoOf returns the id of the mno of the pack, 0 if it has none.
+func mnoOf(pack *model.Pack) int8 {
+	if pack.Mno == nil {
+		return 0
+	}
+	return pack.Mno.ID
+}
+
+// SetContentLocales sets the locale of the pack texts, by default and
+// for every mno.
+func SetContentLocales(defaultlocale string, bymno map[int8]string) {
+	if locale := model.NormalizeLocale(defaultlocale); locale != "" {
+		contentLocale = locale
+	}
+	mnoLocales = map[int8]string{}
+	for mnoid, locale := range bymno {
+		if locale = model.NormalizeLocale(locale); locale != "" {
+			mnoLocales[mnoid] = locale
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"time"
+
+	"github.com/fernandoocampo/pack/model"
+)
+
+// BasicSalesReport implements the behaviour of ISalesReportService.
+type BasicSalesReport struct {
+}
+
+// Report implements ISalesReportService.Report.
+func (m *BasicSalesReport) Report(query *model.SalesReportQuery) (*model.SalesReport, error) {
+	if query == nil {
+		return nil, ErrReportPeriod
+	}
+	switch query.InvalidField() {
+	case "from":
+		return nil, ErrReportPeriod
+	case "bucket":
+		return nil, ErrReportBucket
+	case "by":
+		return nil, ErrReportDimension
+	}
+	query.Location = settlementLocation
+	if query.Timezone != "" {
+		location, err := time.LoadLocation(query.Timezone)
+		if err != nil {
+			return nil, ErrReportTimezone
+		}
+		query.Location = location
+	}
+
+	report := model.NewSalesReport(query)
+	err := orderDAO.EachForReport(query, func(order *model.Order) error {
+		report.Add(order)
+		return nil
+	})
+	if err != nil {
+		return nil, ErrReportFailed.Wrap(err)
+	}
+	report.Close()
+	return report, nil
+}
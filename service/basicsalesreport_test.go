@@ -0,0 +1,62 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/model"
+)
+
+// reportOrderDAO reads the orders of a report from memory.
+type reportOrderDAO struct {
+	dao.IOrderDAO
+	orders []model.Order
+}
+
+func (d *reportOrderDAO) EachForReport(query *model.SalesReportQuery, fn func(order *model.Order) error) error {
+	for i := range d.orders {
+		if err := fn(&d.orders[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TestReport tests the query is validated and buckets are in its time zone
+func TestReport(t *testing.T) {
+	orders := &reportOrderDAO{orders: []model.Order{{PackID: "p12", Packcode: "wh12", Price: 2000,
+		State: model.OrderCompleted, Created: time.Date(2020, time.March, 1, 2, 0, 0, 0, time.UTC)}}}
+	SetOrderDAO(orders)
+	defer SetOrderDAO(nil)
+	from := time.Date(2020, time.February, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		query    model.SalesReportQuery
+		wantDays int
+		want     *Error
+	}{
+		{name: "utc", query: model.SalesReportQuery{From: from, To: from.AddDate(0, 2, 0), Bucket: "day", By: "pack"}, wantDays: 1},
+		{name: "bogota", query: model.SalesReportQuery{From: from, To: from.AddDate(0, 2, 0), Bucket: "day", By: "pack",
+			Timezone: "America/Bogota"}, wantDays: 29},
+		{name: "empty period", query: model.SalesReportQuery{From: from, To: from, Bucket: "day", By: "pack"}, want: ErrReportPeriod},
+		{name: "unknown bucket", query: model.SalesReportQuery{From: from, To: from.AddDate(0, 2, 0), Bucket: "hour", By: "pack"},
+			want: ErrReportBucket},
+		{name: "unknown dimension", query: model.SalesReportQuery{From: from, To: from.AddDate(0, 2, 0), Bucket: "day", By: "msisdn"},
+			want: ErrReportDimension},
+		{name: "unknown time zone", query: model.SalesReportQuery{From: from, To: from.AddDate(0, 2, 0), Bucket: "day", By: "pack",
+			Timezone: "Mars/Olympus"}, want: ErrReportTimezone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := new(BasicSalesReport).Report(&tt.query)
+			if (tt.want == nil && err != nil) || (tt.want != nil && !tt.want.Is(err)) {
+				t.Fatalf("BasicSalesReport.Report() error = %v, want %v", err, tt.want)
+			}
+			if tt.want == nil && (len(report.Rows) != 1 || report.Rows[0].Period.Day() != tt.wantDays) {
+				t.Errorf("Expected the sale on day %d but got %+v", tt.wantDays, report.Rows)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/model"
+)
+
+// settlementDAO makes references to settlement DAO
+var settlementDAO dao.ISettlementDAO
+
+// settlementLocation is the time zone of the settlement periods.
+var settlementLocation = time.UTC
+
+// BasicSettlement implements the behaviour of ISettlementService.
+type BasicSettlement struct {
+}
+
+// Statement implements ISettlementService.Statement.
+func (m *BasicSettlement) Statement(ownerid int, from time.Time, to time.Time) (*model.Settlement, error) {
+	if ownerid <= 0 || !from.Before(to) {
+		return nil, ErrSettlementArgs
+	}
+	orders, err := orderDAO.GetForSettlement(ownerid, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return model.NewSettlement(ownerid, from, to, orders), nil
+}
+
+// Settle implements ISettlementService.Settle.
+func (m *BasicSettlement) Settle(ownerid int, from time.Time, to time.Time) (*model.Settlement, error) {
+	exists, err := settlementDAO.Exists(ownerid, from)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrAlreadySettled
+	}
+	settlement, err := m.Statement(ownerid, from, to)
+	if err != nil {
+		return nil, err
+	}
+	err = settlementDAO.Create(settlement)
+	if err != nil {
+		return nil, err
+	}
+	return settlement, nil
+}
+
+// GetByID implements ISettlementService.GetByID.
+func (m *BasicSettlement) GetByID(id string) (*model.Settlement, error) {
+	if id == "" {
+		return nil, nil
+	}
+	return settlementDAO.GetByID(id)
+}
+
+// GetByOwner implements ISettlementService.GetByOwner.
+func (m *BasicSettlement) GetByOwner(ownerid int) ([]model.Settlement, error) {
+	if ownerid <= 0 {
+		return []model.Settlement{}, nil
+	}
+	return settlementDAO.GetByOwner(ownerid)
+}
+
+// SettleDue implements ISettlementService.SettleDue. Periods are calendar
+// months, so the job settles the previous month.
+func (m *BasicSettlement) SettleDue() (int, error) {
+	from, to := previousMonth(time.Now().In(settlementLocation))
+	owners, err := orderDAO.GetOwners(from, to)
+	if err != nil {
+		return 0, err
+	}
+	settled := 0
+	for _, ownerid := range owners {
+		if ownerid <= 0 {
+			continue
+		}
+		_, err := m.Settle(ownerid, from, to)
+		if err != nil {
+			if !errors.Is(err, ErrAlreadySettled) {
+				log.Errorf("settling owner %d from %s: %v", ownerid, from, err)
+			}
+			continue
+		}
+		settled++
+	}
+	return settled, nil
+}
+
+// previousMonth returns the period [from, to) of the month before now.
+func previousMonth(now time.Time) (time.Time, time.Time) {
+	to := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	return to.AddDate(0, -1, 0), to
+}
+
+// SetSettlementDAO set the settlement dao for this business logic.
+func SetSettlementDAO(dao dao.ISettlementDAO) {
+	settlementDAO = dao
+}
+
+// SetSettlementLocation set the time zone of the settlement periods.
+func SetSettlementLocation(location *time.Location) {
+	if location != nil {
+		settlementLocation = location
+	}
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/model"
+)
+
+// settleOrderDAO returns the owners with sales in every period.
+type settleOrderDAO struct {
+	dao.IOrderDAO
+	owners []int
+}
+
+func (d *settleOrderDAO) GetOwners(from time.Time, to time.Time) ([]int, error) {
+	return d.owners, nil
+}
+
+func (d *settleOrderDAO) GetForSettlement(ownerid int, from time.Time, to time.Time) ([]model.Order, error) {
+	return []model.Order{}, nil
+}
+
+// memSettlementDAO keeps the settlements in memory.
+type memSettlementDAO struct {
+	dao.ISettlementDAO
+	settlements []model.Settlement
+}
+
+func (d *memSettlementDAO) Create(settlement *model.Settlement) error {
+	d.settlements = append(d.settlements, *settlement)
+	return nil
+}
+
+func (d *memSettlementDAO) Exists(ownerid int, from time.Time) (bool, error) {
+	for _, settlement := range d.settlements {
+		if settlement.Ownerid == ownerid && settlement.From.Equal(from) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// TestSettleDueAlreadySettled tests the owners whose period was already
+// settled are skipped without logging an error
+func TestSettleDueAlreadySettled(t *testing.T) {
+	// GIVEN two owners with sales last month, the first one settled
+	from, _ := previousMonth(time.Now().In(settlementLocation))
+	settlements := &memSettlementDAO{settlements: []model.Settlement{{Ownerid: 7, From: from}}}
+	SetOrderDAO(&settleOrderDAO{owners: []int{7, 8}})
+	SetSettlementDAO(settlements)
+	defer SetOrderDAO(nil)
+	defer SetSettlementDAO(nil)
+	output := new(bytes.Buffer)
+	log.Logger.SetOutput(output)
+	defer log.Logger.SetOutput(os.Stderr)
+
+	// WHEN the job settles the due periods
+	settled, err := new(BasicSettlement).SettleDue()
+
+	// THEN only the second owner is settled and nothing is logged
+	if err != nil || settled != 1 || len(settlements.settlements) != 2 {
+		t.Fatalf("Expected one owner settled but got %d %v %+v", settled, err, settlements.settlements)
+	}
+	if strings.Contains(output.String(), "settling owner") {
+		t.Errorf("Expected an already settled owner not to be logged but got %s", output.String())
+	}
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/model"
+)
+
+// renewalLease is the time a due subscription is reserved for the
+// process that renews it.
+const renewalLease = 5 * time.Minute
+
+// subscriptionDAO makes references to subscription DAO
+var subscriptionDAO dao.ISubscriptionDAO
+
+// renewalPolicy contains the rules to renew subscriptions
+var renewalPolicy model.RenewalPolicy
+
+// BasicSubscription implements the behaviour of ISubscriptionService.
+type BasicSubscription struct {
+}
+
+// Subscribe implements ISubscriptionService.Subscribe.
+func (m *BasicSubscription) Subscribe(msisdn string, packid string) (*model.Subscription, error) {
+	if !isValidMsisdn(msisdn) || packid == "" {
+		return nil, ErrSubscribeArgs
+	}
+
+	subscribed, err := subscriptionDAO.IsSubscribed(msisdn, packid)
+	if err != nil {
+		return nil, err
+	}
+	if subscribed {
+		return nil, ErrAlreadySubscribed
+	}
+	// a subscription waiting for its first order is created by the job
+	pending, err := orderDAO.GetPending(msisdn, packid, model.OrderSubscription)
+	if err != nil {
+		return nil, err
+	}
+	if pending != nil {
+		return nil, ErrProvisionPending
+	}
+
+	pack, err := packDAO.GetByID(packid)
+	if err != nil {
+		return nil, ErrPackNotValidated.Wrap(err)
+	}
+	if pack == nil {
+		return nil, ErrPackNotFound
+	}
+
+	_, entitlement, err := placeOrder(msisdn, pack, pack.Price, model.OrderSubscription)
+	if err != nil {
+		return nil, err
+	}
+	return subscribe(msisdn, pack, entitlement)
+}
+
+// subscribe creates the subscription to the pack whose first period is
+// the given entitlement.
+func subscribe(msisdn string, pack *model.Pack, entitlement *model.Entitlement) (*model.Subscription, error) {
+	subscription := model.NewSubscription(msisdn, pack, entitlement.Expires)
+	err := subscriptionDAO.Create(subscription)
+	if errors.Is(err, dao.ErrAlreadySubscribed) {
+		return nil, ErrAlreadySubscribed
+	}
+	if err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// GetByID implements ISubscriptionService.GetByID.
+func (m *BasicSubscription) GetByID(id string) (*model.Subscription, error) {
+	if id == "" {
+		return nil, ErrSubscriptionIDEmpty
+	}
+
+	return subscriptionDAO.GetByID(id)
+}
+
+// GetByMsisdn implements ISubscriptionService.GetByMsisdn.
+func (m *BasicSubscription) GetByMsisdn(msisdn string) ([]model.Subscription, error) {
+	if msisdn == "" {
+		return []model.Subscription{}, nil
+	}
+
+	return subscriptionDAO.GetByMsisdn(msisdn)
+}
+
+// Pause implements ISubscriptionService.Pause.
+func (m *BasicSubscription) Pause(id string) error {
+	return changeSubscriptionState(id, []model.SubscriptionState{model.SubscriptionActive},
+		model.SubscriptionPaused)
+}
+
+// Resume implements ISubscriptionService.Resume.
+func (m *BasicSubscription) Resume(id string) error {
+	return changeSubscriptionState(id, []model.SubscriptionState{model.SubscriptionPaused},
+		model.SubscriptionActive)
+}
+
+// Cancel implements ISubscriptionService.Cancel.
+func (m *BasicSubscription) Cancel(id string) error {
+	return changeSubscriptionState(id, []model.SubscriptionState{model.SubscriptionActive,
+		model.SubscriptionPaused, model.SubscriptionSuspended}, model.SubscriptionCanceled)
+}
+
+// RenewDue implements ISubscriptionService.RenewDue.
+func (m *BasicSubscription) RenewDue() (int, error) {
+	// subscriptions that fail now are due later than this moment, so
+	// they are not taken again in this run.
+	now := time.Now()
+	processed := 0
+	for {
+		subscription, err := subscriptionDAO.ClaimDue(now, renewalLease)
+		if err != nil {
+			return processed, err
+		}
+		if subscription == nil {
+			return processed, nil
+		}
+		err = renew(subscription)
+		if err != nil {
+			return processed, err
+		}
+		processed++
+	}
+}
+
+// renew sells a new period of the subscribed pack at the subscribed
+// price. A failed renewal is retried later according to the policy.
+func renew(subscription *model.Subscription) error {
+	now := time.Now()
+	pack, err := packDAO.GetByID(subscription.PackID)
+	if err != nil || pack == nil {
+		reason := "pack cannot be loaded"
+		if err == nil {
+			reason = "pack does not exist"
+		}
+		subscription.RenewalFailed(reason, now, renewalPolicy)
+		return subscriptionDAO.UpdateRenewal(subscription, nil)
+	}
+
+	price := subscription.ApplyPackPrice(pack.Price, now, renewalPolicy)
+	order, entitlement, err := placeOrder(subscription.Msisdn, pack, price, model.OrderRenewal)
+	if err != nil {
+		reason := err.Error()
+		if order != nil {
+			reason = order.LastError
+		}
+		subscription.RenewalFailed(reason, now, renewalPolicy)
+		return subscriptionDAO.UpdateRenewal(subscription, nil)
+	}
+
+	renewal := subscription.Renewed(order, entitlement, now)
+	return subscriptionDAO.UpdateRenewal(subscription, renewal)
+}
+
+// changeSubscriptionState moves a subscription to a new state if its
+// current state is one of the given ones.
+func changeSubscriptionState(id string, from []model.SubscriptionState, to model.SubscriptionState) error {
+	if id == "" {
+		return ErrSubscriptionIDEmpty
+	}
+
+	changed, err := subscriptionDAO.ChangeState(id, from, to)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return ErrSubscriptionState
+	}
+	return nil
+}
+
+// SetSubscriptionDAO set the subscription dao for this business logic.
+func SetSubscriptionDAO(dao dao.ISubscriptionDAO) {
+	subscriptionDAO = dao
+}
+
+// SetRenewalPolicy set the rules used to renew subscriptions.
+func SetRenewalPolicy(policy model.RenewalPolicy) {
+	renewalPolicy = policy
+}
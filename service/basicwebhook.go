@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/model"
+)
+
+// deliveryLease is the time a due delivery is reserved for the process
+// that posts it.
+const deliveryLease = 5 * time.Minute
+
+// maxDeliveriesListed is the longest list of deliveries returned at once.
+const maxDeliveriesListed = 500
+
+// webhookDAO makes references to webhook DAO
+var webhookDAO dao.IWebhookDAO
+
+// webhookDeliveryDAO makes references to webhook delivery DAO
+var webhookDeliveryDAO dao.IWebhookDeliveryDAO
+
+// webhookPolicy contains the rules to retry webhook deliveries
+var webhookPolicy = model.WebhookPolicy{Timeout: 10 * time.Second, RetryBase: time.Minute, RetryMax: 6 * time.Hour,
+	MaxAttempts: 8}
+
+// webhookClient posts the deliveries, redirects are answers and not
+// followed.
+var webhookClient = newWebhookClient(webhookPolicy)
+
+// errPrivateTarget is the error of dialing a private address for a
+// delivery.
+var errPrivateTarget = errors.New("webhook target is a private address")
+
+// BasicWebhook implements the behaviour of IWebhookService. It is also
+// the sink of the pack events that creates the deliveries.
+type BasicWebhook struct {
+}
+
+// Create implements IWebhookService.Create.
+func (m *BasicWebhook) Create(webhook *model.Webhook) (string, error) {
+	if webhook == nil {
+		return "", ErrWebhookInvalid
+	}
+	if field := webhook.InvalidField(); field != "" {
+		return "", ErrWebhookInvalid.WithField(field)
+	}
+	if !webhookPolicy.AllowPrivateTargets && !publicTarget(webhook.URL) {
+		return "", ErrWebhookInvalid.WithField("url")
+	}
+	if webhook.Secret == "" {
+		secret, err := model.NewWebhookSecret()
+		if err != nil {
+			return "", err
+		}
+		webhook.Secret = secret
+	}
+	webhook.Created = time.Now()
+	err := webhookDAO.Create(webhook)
+	if err != nil {
+		return "", err
+	}
+	return webhook.Secret, nil
+}
+
+// GetByID implements IWebhookService.GetByID.
+func (m *BasicWebhook) GetByID(id string) (*model.Webhook, error) {
+	webhook, err := webhookDAO.GetByID(id)
+	if err != nil {
+		return nil, ErrWebhookNotValidated.Wrap(err)
+	}
+	if webhook == nil {
+		return nil, ErrWebhookNotFound
+	}
+	return webhook, nil
+}
+
+// GetAll implements IWebhookService.GetAll.
+func (m *BasicWebhook) GetAll(ownerid int) ([]model.Webhook, error) {
+	return webhookDAO.GetAll(ownerid)
+}
+
+// Delete implements IWebhookService.Delete.
+func (m *BasicWebhook) Delete(id string) error {
+	_, err := m.GetByID(id)
+	if err != nil {
+		return err
+	}
+	return webhookDAO.Delete(id)
+}
+
+// GetDelivery implements IWebhookService.GetDelivery.
+func (m *BasicWebhook) GetDelivery(id string) (*model.WebhookDelivery, error) {
+	delivery, err := webhookDeliveryDAO.GetByID(id)
+	if err != nil {
+		return nil, ErrWebhookNotValidated.Wrap(err)
+	}
+	if delivery == nil {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	return delivery, nil
+}
+
+// Deliveries implements IWebhookService.Deliveries.
+func (m *BasicWebhook) Deliveries(webhookid string, state string, limit int) ([]model.WebhookDelivery, error) {
+	state = strings.ToLower(strings.TrimSpace(state))
+	if state != "" && state != model.DeliveryPending && state != model.DeliveryDelivered && state != model.DeliveryDead {
+		return nil, ErrWebhookInvalid.WithField("state")
+	}
+	if limit <= 0 || limit > maxDeliveriesListed {
+		return nil, ErrWebhookInvalid.WithField("limit")
+	}
+	return webhookDeliveryDAO.GetByWebhook(webhookid, state, limit)
+}
+
+// Redeliver implements IWebhookService.Redeliver.
+func (m *BasicWebhook) Redeliver(id string) (*model.WebhookDelivery, error) {
+	delivery, err := m.GetDelivery(id)
+	if err != nil {
+		return nil, err
+	}
+	delivery.Redeliver(time.Now())
+	err = deliver(delivery)
+	if err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// DeliverDue implements IWebhookService.DeliverDue.
+func (m *BasicWebhook) DeliverDue() (int, error) {
+	// deliveries that fail now are due later than this moment, so they
+	// are not taken again in this run.
+	now := time.Now()
+	processed := 0
+	for {
+		delivery, err := webhookDeliveryDAO.ClaimDue(now, deliveryLease)
+		if err != nil {
+			return processed, err
+		}
+		if delivery == nil {
+			return processed, nil
+		}
+		err = deliver(delivery)
+		if err != nil {
+			return processed, err
+		}
+		processed++
+	}
+}
+
+// Publish implements events.ISink. It creates a delivery of the event for
+// every webhook it matches, they are posted by DeliverDue.
+func (m *BasicWebhook) Publish(event *model.Event) error {
+	webhooks, err := webhookDAO.GetAll(0)
+	if err != nil {
+		return err
+	}
+	var payload []byte
+	for i := range webhooks {
+		if !webhooks[i].Matches(event) {
+			continue
+		}
+		if payload == nil {
+			payload, err = json.Marshal(event)
+			if err != nil {
+				return err
+			}
+		}
+		err = webhookDeliveryDAO.Create(model.NewWebhookDelivery(&webhooks[i], event, payload, event.Occurred))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deliver posts the delivery to its webhook and saves the attempt. The
+// deliveries of a deleted webhook die without a retry.
+func deliver(delivery *model.WebhookDelivery) error {
+	webhook, err := webhookDAO.GetByID(delivery.WebhookID)
+	if err != nil {
+		return err
+	}
+	if webhook == nil {
+		attempt := model.WebhookAttempt{At: time.Now(), Error: "webhook does not exist"}
+		delivery.Attempted(attempt, model.WebhookPolicy{MaxAttempts: 1})
+		return webhookDeliveryDAO.Update(delivery)
+	}
+	delivery.Attempted(post(webhook, delivery), webhookPolicy)
+	return webhookDeliveryDAO.Update(delivery)
+}
+
+// post sends the payload of the delivery signed with the secret of the
+// webhook and returns the attempt. Any answer out of 2xx is a failure.
+func post(webhook *model.Webhook, delivery *model.WebhookDelivery) model.WebhookAttempt {
+	attempt := model.WebhookAttempt{At: time.Now()}
+	body := []byte(delivery.Payload)
+	request, err := http.NewRequest(http.MethodPost, webhook.URL, strings.NewReader(delivery.Payload))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(model.WebhookEventHeader, delivery.EventType)
+	request.Header.Set(model.WebhookDeliveryHeader, delivery.ID.Hex())
+	request.Header.Set(model.WebhookSignatureHeader, model.SignWebhook(webhook.Secret, attempt.At.Unix(), body))
+
+	response, err := webhookClient.Do(request)
+	attempt.Duration = int(time.Since(attempt.At) / time.Millisecond)
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer response.Body.Close()
+	// the answer is read so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 64*1024))
+	attempt.Status = response.StatusCode
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		attempt.Error = "target answered " + response.Status
+	}
+	return attempt
+}
+
+// newWebhookClient returns the client that posts the deliveries with the
+// timeout of the policy. It refuses to connect to private addresses unless
+// the policy allows them, as a host may resolve to other addresses after
+// the webhook was created.
+func newWebhookClient(policy model.WebhookPolicy) *http.Client {
+	dialer := &net.Dialer{Timeout: policy.Timeout}
+	if !policy.AllowPrivateTargets {
+		dialer.Control = func(network string, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || model.PrivateAddress(ip) {
+				return errPrivateTarget
+			}
+			return nil
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   policy.Timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// publicTarget returns true if the host of the url resolves and none of
+// its addresses is private.
+func publicTarget(webhookurl string) bool {
+	target, err := url.Parse(webhookurl)
+	if err != nil {
+		return false
+	}
+	addresses, err := net.DefaultResolver.LookupIPAddr(context.Background(), target.Hostname())
+	if err != nil || len(addresses) == 0 {
+		return false
+	}
+	for _, address := range addresses {
+		if model.PrivateAddress(address.IP) {
+			return false
+		}
+	}
+	return true
+}
+
+// SetWebhookDAO set the webhook dao for this business logic.
+func SetWebhookDAO(dao dao.IWebhookDAO) {
+	webhookDAO = dao
+}
+
+// SetWebhookDeliveryDAO set the webhook delivery dao for this business logic.
+func SetWebhookDeliveryDAO(dao dao.IWebhookDeliveryDAO) {
+	webhookDeliveryDAO = dao
+}
+
+// SetWebhookPolicy set the rules used to retry webhook deliveries.
+func SetWebhookPolicy(policy model.WebhookPolicy) {
+	webhookPolicy = policy
+	webhookClient = newWebhookClient(policy)
+}
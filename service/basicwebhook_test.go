@@ -0,0 +1,314 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/model"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// memWebhookDAO keeps the webhooks in memory.
+type memWebhookDAO struct {
+	dao.IWebhookDAO
+	webhooks []model.Webhook
+}
+
+func (d *memWebhookDAO) GetByID(id string) (*model.Webhook, error) {
+	for i := range d.webhooks {
+		if d.webhooks[i].ID.Hex() == id {
+			webhook := d.webhooks[i]
+			return &webhook, nil
+		}
+	}
+	return nil, nil
+}
+
+func (d *memWebhookDAO) GetAll(ownerid int) ([]model.Webhook, error) {
+	return append([]model.Webhook{}, d.webhooks...), nil
+}
+
+// memDeliveryDAO keeps the webhook deliveries in memory.
+type memDeliveryDAO struct {
+	dao.IWebhookDeliveryDAO
+	deliveries []model.WebhookDelivery
+}
+
+func (d *memDeliveryDAO) Create(delivery *model.WebhookDelivery) error {
+	for _, existing := range d.deliveries {
+		if existing.WebhookID == delivery.WebhookID && existing.EventID == delivery.EventID {
+			return nil
+		}
+	}
+	delivery.ID = bson.NewObjectId()
+	d.deliveries = append(d.deliveries, *delivery)
+	return nil
+}
+
+func (d *memDeliveryDAO) GetByID(id string) (*model.WebhookDelivery, error) {
+	for i := range d.deliveries {
+		if d.deliveries[i].ID.Hex() == id {
+			delivery := d.deliveries[i]
+			return &delivery, nil
+		}
+	}
+	return nil, nil
+}
+
+func (d *memDeliveryDAO) GetByWebhook(webhookid string, state string, limit int) ([]model.WebhookDelivery, error) {
+	result := []model.WebhookDelivery{}
+	for _, delivery := range d.deliveries {
+		if delivery.WebhookID == webhookid && (state == "" || delivery.State == state) && len(result) < limit {
+			result = append(result, delivery)
+		}
+	}
+	return result, nil
+}
+
+func (d *memDeliveryDAO) ClaimDue(now time.Time, lease time.Duration) (*model.WebhookDelivery, error) {
+	for i := range d.deliveries {
+		delivery := &d.deliveries[i]
+		if delivery.State == model.DeliveryPending && !delivery.NextAttempt.After(now) {
+			delivery.NextAttempt = now.Add(lease)
+			claimed := *delivery
+			return &claimed, nil
+		}
+	}
+	return nil, nil
+}
+
+func (d *memDeliveryDAO) Update(delivery *model.WebhookDelivery) error {
+	for i := range d.deliveries {
+		if d.deliveries[i].ID == delivery.ID {
+			d.deliveries[i] = *delivery
+		}
+	}
+	return nil
+}
+
+// partnerServer is a local partner that checks the signatures and answers
+// with the status it is given.
+type partnerServer struct {
+	*httptest.Server
+	mutex    sync.Mutex
+	status   int
+	received []string
+	invalid  int
+}
+
+func newPartnerServer(secret string, status int) *partnerServer {
+	partner := &partnerServer{status: status}
+	partner.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		partner.mutex.Lock()
+		defer partner.mutex.Unlock()
+		signature := r.Header.Get(model.WebhookSignatureHeader)
+		timestamp, _ := strconv.ParseInt(strings.TrimPrefix(strings.Split(signature, ",")[0], "t="), 10, 64)
+		if signature != model.SignWebhook(secret, timestamp, body) || r.Header.Get(model.WebhookDeliveryHeader) == "" {
+			partner.invalid++
+		}
+		partner.received = append(partner.received, r.Header.Get(model.WebhookEventHeader))
+		w.WriteHeader(partner.status)
+	}))
+	return partner
+}
+
+// setWebhookFakes sets the in memory daos and a policy with short waits.
+func setWebhookFakes(t *testing.T, webhooks ...model.Webhook) (*memDeliveryDAO, func()) {
+	t.Helper()
+	deliveries := &memDeliveryDAO{}
+	previous := webhookPolicy
+	SetWebhookDAO(&memWebhookDAO{webhooks: webhooks})
+	SetWebhookDeliveryDAO(deliveries)
+	SetWebhookPolicy(model.WebhookPolicy{Timeout: 2 * time.Second, RetryBase: time.Millisecond,
+		RetryMax: time.Millisecond, MaxAttempts: 2, AllowPrivateTargets: true})
+	return deliveries, func() {
+		SetWebhookDAO(nil)
+		SetWebhookDeliveryDAO(nil)
+		SetWebhookPolicy(previous)
+	}
+}
+
+// TestWebhookDelivery tests an event is posted once to the matching
+// webhooks, signed, and retried until the partner accepts it
+func TestWebhookDelivery(t *testing.T) {
+	partner := newPartnerServer("whsec_partner_seven", http.StatusServiceUnavailable)
+	defer partner.Close()
+	mine := model.Webhook{ID: bson.NewObjectId(), Ownerid: 7, URL: partner.URL, Secret: "whsec_partner_seven",
+		Events: []string{model.EventPackPriceChanged}}
+	other := model.Webhook{ID: bson.NewObjectId(), Ownerid: 8, URL: partner.URL, Secret: "whsec_partner_eight"}
+	deliveries, reset := setWebhookFakes(t, mine, other)
+	defer reset()
+	basicwebhook := new(BasicWebhook)
+
+	event := model.NewEvent(model.EventPackPriceChanged, "p1", map[string]interface{}{"price": 1200})
+	event.Pack = &model.Pack{Ownerid: 7, Price: 1200}
+	for i := 0; i < 2; i++ {
+		if err := basicwebhook.Publish(&event); err != nil {
+			t.Fatalf("Expected the event published but got: %s", err)
+		}
+	}
+	if len(deliveries.deliveries) != 1 || deliveries.deliveries[0].WebhookID != mine.ID.Hex() {
+		t.Fatalf("Expected one delivery to the webhook of owner 7 but got %+v", deliveries.deliveries)
+	}
+
+	attempted, err := basicwebhook.DeliverDue()
+	delivery := deliveries.deliveries[0]
+	if err != nil || attempted != 1 || delivery.State != model.DeliveryPending || delivery.Tries != 1 ||
+		delivery.Attempts[0].Status != http.StatusServiceUnavailable {
+		t.Fatalf("Expected a failed attempt to retry but got %d, %+v: %v", attempted, delivery, err)
+	}
+
+	partner.mutex.Lock()
+	partner.status = http.StatusNoContent
+	partner.mutex.Unlock()
+	time.Sleep(5 * time.Millisecond)
+	attempted, err = basicwebhook.DeliverDue()
+	delivery = deliveries.deliveries[0]
+	if err != nil || attempted != 1 || delivery.State != model.DeliveryDelivered || len(delivery.Attempts) != 2 {
+		t.Fatalf("Expected the retry delivered but got %d, %+v: %v", attempted, delivery, err)
+	}
+	if partner.invalid != 0 || len(partner.received) != 2 || partner.received[1] != model.EventPackPriceChanged {
+		t.Errorf("Expected 2 signed posts of the price change but got %v with %d invalid",
+			partner.received, partner.invalid)
+	}
+}
+
+// TestWebhookDeadLetter tests a delivery dies after repeated failures and
+// is delivered by a manual redelivery
+func TestWebhookDeadLetter(t *testing.T) {
+	partner := newPartnerServer("whsec_partner_seven", http.StatusInternalServerError)
+	defer partner.Close()
+	webhook := model.Webhook{ID: bson.NewObjectId(), URL: partner.URL, Secret: "whsec_partner_seven"}
+	deliveries, reset := setWebhookFakes(t, webhook)
+	defer reset()
+	basicwebhook := new(BasicWebhook)
+	event := model.NewEvent(model.EventStockMoved, "p1", map[string]interface{}{"amount": -1})
+	if err := basicwebhook.Publish(&event); err != nil {
+		t.Fatalf("Expected the event published but got: %s", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		time.Sleep(5 * time.Millisecond)
+		if _, err := basicwebhook.DeliverDue(); err != nil {
+			t.Fatalf("Expected err to be nil but it was: %s", err)
+		}
+	}
+	dead, err := basicwebhook.Deliveries(webhook.ID.Hex(), model.DeliveryDead, 50)
+	if err != nil || len(dead) != 1 || dead[0].Tries != 2 {
+		t.Fatalf("Expected a dead delivery after 2 failures but got %+v: %v", dead, err)
+	}
+	if _, err = basicwebhook.Deliveries(webhook.ID.Hex(), "lost", 50); err == nil {
+		t.Fatalf("Expected an unknown state to be rejected")
+	}
+	attempted, _ := basicwebhook.DeliverDue()
+	if attempted != 0 {
+		t.Fatalf("Expected dead deliveries not to be attempted but %d were", attempted)
+	}
+
+	partner.mutex.Lock()
+	partner.status = http.StatusOK
+	partner.mutex.Unlock()
+	redelivered, err := basicwebhook.Redeliver(deliveries.deliveries[0].ID.Hex())
+	if err != nil || redelivered.State != model.DeliveryDelivered || len(redelivered.Attempts) != 3 {
+		t.Fatalf("Expected the redelivery delivered but got %+v: %v", redelivered, err)
+	}
+	if _, err = basicwebhook.Redeliver(bson.NewObjectId().Hex()); err != ErrWebhookDeliveryNotFound {
+		t.Errorf("Expected ErrWebhookDeliveryNotFound but got %v", err)
+	}
+}
+
+// TestWebhookDeleted tests the deliveries of a deleted webhook die
+// without posting
+func TestWebhookDeleted(t *testing.T) {
+	deliveries, reset := setWebhookFakes(t)
+	defer reset()
+	gone := &model.Webhook{ID: bson.NewObjectId()}
+	event := model.NewEvent(model.EventPackDeleted, "p1", nil)
+	_ = deliveries.Create(model.NewWebhookDelivery(gone, &event, []byte(`{}`), time.Now()))
+
+	attempted, err := new(BasicWebhook).DeliverDue()
+
+	if err != nil || attempted != 1 || deliveries.deliveries[0].State != model.DeliveryDead {
+		t.Errorf("Expected the delivery dead but got %d, %+v: %v", attempted, deliveries.deliveries[0], err)
+	}
+}
+
+// TestCreateWebhook tests the validation and the secret of a new webhook
+func TestCreateWebhook(t *testing.T) {
+	webhooks := &createdWebhookDAO{}
+	previous := webhookPolicy
+	SetWebhookDAO(webhooks)
+	SetWebhookPolicy(model.WebhookPolicy{Timeout: time.Second, AllowPrivateTargets: true})
+	defer SetWebhookDAO(nil)
+	defer SetWebhookPolicy(previous)
+	basicwebhook := new(BasicWebhook)
+
+	_, err := basicwebhook.Create(model.NewWebhook(map[string]interface{}{"url": "mailto:ops@partner.example"}))
+	if err == nil || AsError(err).Field != "url" {
+		t.Fatalf("Expected an invalid url but got %v", err)
+	}
+	secret, err := basicwebhook.Create(model.NewWebhook(map[string]interface{}{"url": "http://localhost:9000"}))
+	if err != nil || !strings.HasPrefix(secret, "whsec_") || webhooks.created[0].Secret != secret {
+		t.Errorf("Expected a generated secret but got %q: %v", secret, err)
+	}
+	secret, _ = basicwebhook.Create(model.NewWebhook(map[string]interface{}{"url": "http://localhost:9000",
+		"secret": "my-own-long-secret"}))
+	if secret != "my-own-long-secret" {
+		t.Errorf("Expected the given secret but got %q", secret)
+	}
+}
+
+// TestWebhookPrivateTarget tests the webhooks cannot post to private
+// addresses unless the policy allows them
+func TestWebhookPrivateTarget(t *testing.T) {
+	// GIVEN the default policy
+	webhooks := &createdWebhookDAO{}
+	previous := webhookPolicy
+	SetWebhookDAO(webhooks)
+	SetWebhookPolicy(model.WebhookPolicy{Timeout: time.Second})
+	defer SetWebhookDAO(nil)
+	defer SetWebhookPolicy(previous)
+
+	// WHEN webhooks of loopback, private and link-local targets are created
+	for _, target := range []string{"http://localhost:9000", "http://10.0.0.5/hooks", "http://169.254.169.254/latest",
+		"http://[::1]:8080"} {
+		_, err := new(BasicWebhook).Create(model.NewWebhook(map[string]interface{}{"url": target}))
+
+		// THEN they are refused
+		if !errors.Is(err, ErrWebhookInvalid) || AsError(err).Field != "url" {
+			t.Errorf("Expected %s to be refused but got %v", target, err)
+		}
+	}
+	if len(webhooks.created) != 0 {
+		t.Errorf("Expected no webhook created but got %+v", webhooks.created)
+	}
+
+	// AND a delivery to a local partner is not posted
+	partner := newPartnerServer("my-own-long-secret", http.StatusOK)
+	defer partner.Close()
+	request, _ := http.NewRequest(http.MethodPost, partner.URL, strings.NewReader("{}"))
+	_, err := webhookClient.Do(request)
+	if !errors.Is(err, errPrivateTarget) || len(partner.received) != 0 {
+		t.Errorf("Expected the local partner to be refused but got %v, %v", err, partner.received)
+	}
+}
+
+// createdWebhookDAO records the webhooks created.
+type createdWebhookDAO struct {
+	dao.IWebhookDAO
+	created []model.Webhook
+}
+
+func (d *createdWebhookDAO) Create(webhook *model.Webhook) error {
+	d.created = append(d.created, *webhook)
+	return nil
+}
@@ -0,0 +1,23 @@
+package service
+
+import "github.com/fernandoocampo/pack/model"
+
+// IBulkService defines the behavior of the changes of many packs at once.
+type IBulkService interface {
+	// Preview counts the packs of the tenant of the job that match its
+	// filter, nothing is changed.
+	Preview(job *model.BulkJob) (*model.BulkJob, error)
+	// Start stores the job and changes the packs of the tenant of the job
+	// that match its filter in background, the returned job is pending.
+	Start(job *model.BulkJob) (*model.BulkJob, error)
+	// GetJob returns the job with the given id and its progress.
+	GetJob(id string) (*model.BulkJob, error)
+	// GetJobs returns the last jobs started by the given caller without
+	// their failures.
+	GetJobs(subject string) ([]model.BulkJob, error)
+	// GetAudit returns the last changes of the given pack.
+	GetAudit(packid string) ([]model.AuditEntry, error)
+	// InterruptRunning marks the jobs left running by a previous run of
+	// the service as interrupted and returns how many there were.
+	InterruptRunning() (int, error)
+}
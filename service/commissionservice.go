@@ -0,0 +1,16 @@
+package service
+
+import "github.com/fernandoocampo/pack/model"
+
+// ICommissionService defines the behavior of reseller commission rules.
+type ICommissionService interface {
+	// AddRule creates a commission rule for an owner.
+	AddRule(rule *model.CommissionRule) error
+	// DeleteRule removes a commission rule, sales already made keep
+	// their commission.
+	DeleteRule(id string) error
+	// GetRule returns the commission rule with the given id.
+	GetRule(id string) (*model.CommissionRule, error)
+	// GetRules returns the commission rules of an owner.
+	GetRules(ownerid int) ([]model.CommissionRule, error)
+}
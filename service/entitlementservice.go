@@ -0,0 +1,21 @@
+package service
+
+import "github.com/fernandoocampo/pack/model"
+
+// IEntitlementService defines the behavior to track the packs that
+// subscribers own and the balances left on them.
+type IEntitlementService interface {
+	// Grant gives the subscriber an entitlement for the given pack,
+	// balances are copied from the pack resources.
+	Grant(msisdn string, packid string) (*model.Entitlement, error)
+	// GetByMsisdn returns the entitlements of a subscriber. If onlyopen
+	// is true closed and expired entitlements are left out.
+	GetByMsisdn(msisdn string, onlyopen bool) ([]model.Entitlement, error)
+	// Balances returns the remaining amount per resource of a subscriber.
+	Balances(msisdn string) ([]model.ResourceBalance, error)
+	// Consume records the usage of an amount of the given resource, it is
+	// taken from the open entitlements, the first to expire is used first.
+	Consume(msisdn string, resourceid int16, amount float32) error
+	// CloseExpired closes the entitlements that already expired.
+	CloseExpired() (int, error)
+}
@@ -0,0 +1,135 @@
+package service
+
+// DefaultLocale is the locale of the messages when the caller asks for
+// none or for one without translations.
+const DefaultLocale = "en"
+
+// errorMessages contains the translations of the catalog messages by
+// locale and code, english messages are in the catalog.
+var errorMessages = map[string]map[string]string{
+	"es": {
+		"00":  "los datos del paquete están vacíos",
+		"01":  "el id de producto, el código y el nombre del paquete son obligatorios",
+		"02":  "la descripción, las palabras clave y la url de la imagen son obligatorias",
+		"03":  "el tipo de paquete no es válido",
+		"04":  "el operador no es válido",
+		"05":  "la vigencia no es válida",
+		"06":  "no se pueden validar los paquetes existentes",
+		"07":  "ya existe un paquete del operador con el código o el id de producto",
+		"08":  "el id del paquete para cambiar el estado está vacío",
+		"09":  "el id del paquete o el id de producto a cambiar están vacíos",
+		"10":  "ya existe un paquete del operador con el id de producto",
+		"11":  "el id del paquete o el código a cambiar están vacíos",
+		"12":  "ya existe un paquete del operador con el código",
+		"13":  "el id del paquete o el nombre a cambiar están vacíos",
+		"14":  "el id del paquete o la descripción a cambiar están vacíos",
+		"15":  "el id del paquete o la url de la imagen a cambiar están vacíos",
+		"16":  "el id del paquete o las palabras clave a cambiar están vacíos",
+		"17":  "el id del paquete o el precio a cambiar están vacíos",
+		"18":  "el id del paquete o el tipo a cambiar están vacíos",
+		"19":  "el id del paquete o el operador a cambiar están vacíos",
+		"20":  "el id del paquete o la vigencia a cambiar están vacíos",
+		"21":  "el id del paquete o la moneda a cambiar están vacíos",
+		"22":  "el id del paquete a borrar está vacío",
+		"23":  "el id del paquete o la cantidad de inventario a mover están vacíos",
+		"24":  "el id del paquete o los recursos a reemplazar están vacíos",
+		"25":  "el id del paquete para borrar los recursos está vacío",
+		"26":  "el msisdn o el id del paquete a otorgar no son válidos",
+		"27":  "el paquete no existe",
+		"28":  "el paquete no está activo",
+		"29":  "el msisdn, el recurso o la cantidad a consumir no son válidos",
+		"30":  "no hay saldo suficiente para consumir",
+		"31":  "el msisdn o el id del paquete a suscribir no son válidos",
+		"32":  "el suscriptor ya está suscrito al paquete",
+		"33":  "el id de la suscripción está vacío",
+		"34":  "la suscripción no existe o su estado no puede cambiar",
+		"35":  "el msisdn o el id del paquete a comprar no son válidos",
+		"36":  "el paquete no tiene inventario",
+		"37":  "no hay aprovisionador para el operador del paquete",
+		"38":  "el paquete no se pudo activar en la red del operador",
+		"39":  "la orden no existe o no fue aprovisionada",
+		"40":  "solo se pueden reembolsar las órdenes completadas",
+		"41":  "no se puede calcular la comisión de la venta",
+		"42":  "los datos de la regla de comisión no son válidos",
+		"43":  "el id de la regla de comisión no es válido",
+		"44":  "el dueño o el periodo a liquidar no son válidos",
+		"45":  "el periodo ya fue liquidado",
+		"46":  "los datos del dueño no son válidos",
+		"47":  "no se puede validar el dueño",
+		"48":  "ya existe un dueño con el id",
+		"49":  "el dueño no existe",
+		"50":  "el dueño no está activo o no puede vender paquetes del operador",
+		"51":  "el id del paquete o el nuevo dueño a transferir no son válidos",
+		"52":  "el paquete ya pertenece al nuevo dueño",
+		"53":  "los datos de la llave de api no son válidos",
+		"54":  "el id de la llave de api no es válido",
+		"55":  "quien llama no es administrador de la plataforma",
+		"56":  "quien llama no puede ver los datos del dueño",
+		"57":  "quien llama no tiene permiso para usar el campo",
+		"58":  "la operación no se pudo realizar",
+		"59":  "el id del paquete, el idioma o el nombre de la traducción están vacíos",
+		"60":  "el paquete no tiene traducción en el idioma",
+		"61":  "los textos del paquete en el idioma por defecto del operador se cambian con changeName, changeDescription y changeKeywords",
+		"62":  "el formato de importación debe ser csv o jsonl",
+		"63":  "el modo de importación debe ser create o upsert",
+		"64":  "el archivo de importación no se puede leer",
+		"65":  "la fila no se puede leer como un paquete",
+		"66":  "una fila anterior del archivo tiene el código de paquete o el id de producto",
+		"67":  "la simulación debe ser true o false",
+		"68":  "el formato de exportación debe ser csv, jsonl o xlsx",
+		"69":  "los paquetes no se pueden exportar",
+		"70":  "el filtro de los paquetes no es válido",
+		"71":  "el parche de la actualización masiva no cambia nada",
+		"72":  "el parche de la actualización masiva no es válido",
+		"73":  "el estado del cambio masivo debe ser 0 o 1",
+		"74":  "el trabajo masivo no existe",
+		"75":  "los paquetes a cambiar no se pueden leer",
+		"76":  "el paquete no se puede cambiar",
+		"77":  "el id del paquete, el id de producto o el código de paquete del clon están vacíos",
+		"78":  "los datos de la plantilla de paquetes no son válidos",
+		"79":  "ya existe una plantilla de paquetes con el nombre",
+		"80":  "la plantilla de paquetes no existe",
+		"81":  "un marcador de la plantilla no tiene valor",
+		"82":  "la plantilla no tiene un marcador con el nombre del valor",
+		"83":  "un combo necesita al menos dos paquetes diferentes y no puede contenerse a sí mismo",
+		"84":  "un componente debe ser un paquete activo del operador del combo que no sea un combo",
+		"85":  "el paquete es componente de un combo activo",
+		"86":  "un componente del combo está inactivo",
+		"87":  "los recursos de un combo se suman de sus componentes",
+		"88":  "el paquete no es un combo",
+		"89":  "las reglas de elegibilidad del paquete no son válidas",
+		"90":  "las compras del cliente no se pueden leer para revisar la elegibilidad",
+		"91":  "un canal del paquete no está en el catálogo de canales",
+		"92":  "una región del paquete no está en el catálogo de regiones",
+		"93":  "los datos del canal o la región no son válidos",
+		"94":  "ya existe un canal o una región con el código",
+		"95":  "el canal o la región no existe",
+		"96":  "los canales y las regiones no se pueden validar",
+		"97":  "se comparan entre 2 y 10 ids de paquetes diferentes",
+		"98":  "las estadísticas se agrupan por mno, type, state, owner o currency, cada uno una vez",
+		"99":  "las estadísticas del catálogo no se pueden calcular",
+		"100": "el periodo del reporte debe empezar antes de terminar y durar máximo 2 años",
+		"101": "los reportes se agrupan por día, semana o mes",
+		"102": "los reportes son por paquete, operador o dueño",
+		"103": "la zona horaria del reporte no existe",
+		"104": "las órdenes del reporte no se pueden leer",
+		"105": "los datos del webhook no son válidos",
+		"106": "el webhook no existe",
+		"107": "la entrega del webhook no existe",
+		"108": "los webhooks no se pueden validar",
+		"109": "la suscripción se atrasó con los eventos de los paquetes, suscríbase de nuevo",
+		"110": "la conexión tiene demasiadas suscripciones",
+		"111": "el mensaje de la suscripción no es válido",
+		"112": "el paquete cambió desde que se leyó, léalo de nuevo",
+		"113": "los datos del paquete no son un json válido",
+		"114": "el campo del paquete no se puede cambiar con esta operación",
+		"115": "la regla de comisión no existe",
+		"116": "la activación del paquete está pendiente, se reintentará más tarde",
+	},
+}
+
+// SupportedLocale returns true if there are messages for the given locale.
+func SupportedLocale(locale string) bool {
+	_, ok := errorMessages[locale]
+	return ok || locale == DefaultLocale
+}
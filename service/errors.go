@@ -0,0 +1,233 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/fernandoocampo/pack/dao"
+	"github.com/fernandoocampo/pack/model"
+)
+
+// ErrorCategory groups the errors of the catalog as http status codes do.
+type ErrorCategory string
+
+// Error categories
+const (
+	CategoryInvalid      ErrorCategory = "INVALID_ARGUMENT"    // 400, the caller sent wrong data
+	CategoryForbidden    ErrorCategory = "FORBIDDEN"           // 403, the caller cannot do it
+	CategoryNotFound     ErrorCategory = "NOT_FOUND"           // 404, the data does not exist
+	CategoryConflict     ErrorCategory = "CONFLICT"            // 409, the data is not in a state that allows it
+	CategoryPrecondition ErrorCategory = "FAILED_PRECONDITION" // 412, the data changed since the caller read it
+	CategoryInternal     ErrorCategory = "INTERNAL"            // 500, the service failed
+	CategoryUnavailable  ErrorCategory = "UNAVAILABLE"         // 503, the db or the mno network failed
+)
+
+// Error is an error of the catalog, its code never changes so callers can
+// rely on it.
+type Error struct {
+	Code     string        // stable code of the error
+	Message  string        // english message
+	Category ErrorCategory // kind of error
+	Field    string        // argument that caused the error, empty if none
+	Err      error         // cause of the error, it is not shown to callers
+}
+
+// Errors of the catalog
+var (
+	ErrPackEmpty                = newError("00", "pack data is empty", CategoryInvalid, "")
+	ErrPackKeysEmpty            = newError("01", "product id, pack code and name are mandatory", CategoryInvalid, "packcode")
+	ErrPackTextsEmpty           = newError("02", "description, keywords and image url are mandatory", CategoryInvalid, "desc")
+	ErrPackTypeInvalid          = newError("03", "pack type is invalid", CategoryInvalid, "type")
+	ErrPackMnoInvalid           = newError("04", "mno is invalid", CategoryInvalid, "mno")
+	ErrPackTermInvalid          = newError("05", "validity is invalid", CategoryInvalid, "term")
+	ErrPackNotValidated         = newError("06", "existing packs cannot be validated", CategoryUnavailable, "")
+	ErrPackDuplicated           = newError("07", "there is a pack of the mno with the pack code or product id", CategoryConflict, "packcode")
+	ErrChangeStateArgs          = newError("08", "pack id to change the state is empty", CategoryInvalid, "id")
+	ErrChangeProductIDArgs      = newError("09", "pack id or product id to change are empty", CategoryInvalid, "prodid")
+	ErrProductIDDuplicated      = newError("10", "there is a pack of the mno with the product id", CategoryConflict, "prodid")
+	ErrChangePackCodeArgs       = newError("11", "pack id or pack code to change are empty", CategoryInvalid, "packcode")
+	ErrPackCodeDuplicated       = newError("12", "there is a pack of the mno with the pack code", CategoryConflict, "packcode")
+	ErrChangeNameArgs           = newError("13", "pack id or name to change are empty", CategoryInvalid, "name")
+	ErrChangeDescArgs           = newError("14", "pack id or description to change are empty", CategoryInvalid, "desc")
+	ErrChangeImageArgs          = newError("15", "pack id or image url to change are empty", CategoryInvalid, "imgurl")
+	ErrChangeKeywordsArgs       = newError("16", "pack id or keywords to change are empty", CategoryInvalid, "kwds")
+	ErrChangePriceArgs          = newError("17", "pack id or price to change are empty", CategoryInvalid, "price")
+	ErrChangeTypeArgs           = newError("18", "pack id or type to change are empty", CategoryInvalid, "type")
+	ErrChangeMnoArgs            = newError("19", "pack id or mno to change are empty", CategoryInvalid, "mno")
+	ErrChangeValidityArgs       = newError("20", "pack id or validity to change are empty", CategoryInvalid, "term")
+	ErrChangeCurrencyArgs       = newError("21", "pack id or currency to change are empty", CategoryInvalid, "currency")
+	ErrDeleteArgs               = newError("22", "pack id to delete is empty", CategoryInvalid, "id")
+	ErrMoveStockArgs            = newError("23", "pack id or stock amount to move are empty", CategoryInvalid, "amount")
+	ErrReplaceResourcesArgs     = newError("24", "pack id or resources to replace are empty", CategoryInvalid, "resources")
+	ErrDeleteResourcesArgs      = newError("25", "pack id to delete resources is empty", CategoryInvalid, "id")
+	ErrGrantArgs                = newError("26", "msisdn or pack id to grant are invalid", CategoryInvalid, "msisdn")
+	ErrPackNotFound             = newError("27", "pack does not exist", CategoryNotFound, "packid")
+	ErrPackNotActive            = newError("28", "pack is not active", CategoryConflict, "packid")
+	ErrConsumeArgs              = newError("29", "msisdn, resource or amount to consume are invalid", CategoryInvalid, "amount")
+	ErrNotEnoughBalance         = newError("30", "there is not enough balance to consume", CategoryConflict, "amount")
+	ErrSubscribeArgs            = newError("31", "msisdn or pack id to subscribe are invalid", CategoryInvalid, "msisdn")
+	ErrAlreadySubscribed        = newError("32", "subscriber is already subscribed to the pack", CategoryConflict, "packid")
+	ErrSubscriptionIDEmpty      = newError("33", "subscription id is empty", CategoryInvalid, "id")
+	ErrSubscriptionState        = newError("34", "subscription does not exist or its state cannot change", CategoryConflict, "id")
+	ErrPurchaseArgs             = newError("35", "msisdn or pack id to purchase are invalid", CategoryInvalid, "msisdn")
+	ErrOutOfStock               = newError("36", "pack is out of stock", CategoryConflict, "packid")
+	ErrNoProvisioner            = newError("37", "there is no provisioner for the mno of the pack", CategoryUnavailable, "")
+	ErrProvisionFailed          = newError("38", "pack cannot be activated in the mno network", CategoryUnavailable, "")
+	ErrOrderNotProvisioned      = newError("39", "order does not exist or it was not provisioned", CategoryNotFound, "id")
+	ErrOrderNotRefundable       = newError("40", "only completed orders can be refunded", CategoryConflict, "id")
+	ErrCommissionFailed         = newError("41", "commission of the sale cannot be calculated", CategoryUnavailable, "")
+	ErrCommissionRuleInvalid    = newError("42", "commission rule data is invalid", CategoryInvalid, "")
+	ErrCommissionRuleIDEmpty    = newError("43", "commission rule id is invalid", CategoryInvalid, "id")
+	ErrSettlementArgs           = newError("44", "owner or period to settle are invalid", CategoryInvalid, "ownerid")
+	ErrAlreadySettled           = newError("45", "the period was already settled", CategoryConflict, "from")
+	ErrOwnerInvalid             = newError("46", "owner data is invalid", CategoryInvalid, "")
+	ErrOwnerNotValidated        = newError("47", "owner cannot be validated", CategoryUnavailable, "")
+	ErrOwnerDuplicated          = newError("48", "there is an owner with the id", CategoryConflict, "id")
+	ErrOwnerNotFound            = newError("49", "owner does not exist", CategoryNotFound, "ownerid")
+	ErrOwnerNotAllowed          = newError("50", "owner is not active or it cannot sell packs of the mno", CategoryConflict, "ownerid")
+	ErrTransferArgs             = newError("51", "pack id or new owner to transfer are invalid", CategoryInvalid, "newOwnerId")
+	ErrAlreadyOwner             = newError("52", "pack already belongs to the new owner", CategoryConflict, "newOwnerId")
+	ErrAPIKeyInvalid            = newError("53", "api key data is invalid", CategoryInvalid, "")
+	ErrAPIKeyIDInvalid          = newError("54", "api key id is invalid", CategoryInvalid, "id")
+	ErrNotAdmin                 = newError("55", "caller is not a platform admin", CategoryForbidden, "")
+	ErrNotOwner                 = newError("56", "caller cannot see the data of the owner", CategoryForbidden, "ownerid")
+	ErrForbidden                = newError("57", "caller is not allowed to use the field", CategoryForbidden, "")
+	ErrInternal                 = newError("58", "the operation cannot be done", CategoryInternal, "")
+	ErrTranslationInvalid       = newError("59", "pack id, locale or name of the translation are empty", CategoryInvalid, "locale")
+	ErrTranslationNotFound      = newError("60", "pack has no translation in the locale", CategoryNotFound, "locale")
+	ErrTranslationDefaultLocale = newError("61", "the pack texts in the default locale of the mno are changed with changeName, changeDescription and changeKeywords", CategoryConflict, "locale")
+	ErrImportFormat             = newError("62", "import format must be csv or jsonl", CategoryInvalid, "format")
+	ErrImportMode               = newError("63", "import mode must be create or upsert", CategoryInvalid, "mode")
+	ErrImportFile               = newError("64", "import file cannot be read", CategoryInvalid, "file")
+	ErrImportRowInvalid         = newError("65", "row cannot be read as a pack", CategoryInvalid, "")
+	ErrImportRowRepeated        = newError("66", "an earlier row of the file has the pack code or product id", CategoryConflict, "packcode")
+	ErrImportDryRun             = newError("67", "dry run must be true or false", CategoryInvalid, "dryRun")
+	ErrExportFormat             = newError("68", "export format must be csv, jsonl or xlsx", CategoryInvalid, "format")
+	ErrExportFailed             = newError("69", "packs cannot be exported", CategoryUnavailable, "")
+	ErrPackFilterInvalid        = newError("70", "filter of the packs is invalid", CategoryInvalid, "")
+	ErrBulkPatchEmpty           = newError("71", "patch of the bulk update does not change anything", CategoryInvalid, "patch")
+	ErrBulkPatchInvalid         = newError("72", "patch of the bulk update is invalid", CategoryInvalid, "patch")
+	ErrBulkStateInvalid         = newError("73", "state of the bulk change must be 0 or 1", CategoryInvalid, "state")
+	ErrBulkJobNotFound          = newError("74", "bulk job does not exist", CategoryNotFound, "id")
+	ErrBulkFailed               = newError("75", "packs to change cannot be read", CategoryUnavailable, "")
+	ErrBulkPackFailed           = newError("76", "pack cannot be changed", CategoryUnavailable, "")
+	ErrCloneArgs                = newError("77", "pack id, product id or pack code of the clone are empty", CategoryInvalid, "id")
+	ErrTemplateInvalid          = newError("78", "pack template data is invalid", CategoryInvalid, "")
+	ErrTemplateDuplicated       = newError("79", "there is a pack template with the name", CategoryConflict, "name")
+	ErrTemplateNotFound         = newError("80", "pack template does not exist", CategoryNotFound, "id")
+	ErrTemplateValueMissing     = newError("81", "a placeholder of the template has no value", CategoryInvalid, "values")
+	ErrTemplateValueUnknown     = newError("82", "the template has no placeholder with the name of the value", CategoryInvalid, "values")
+	ErrBundleComponentsInvalid  = newError("83", "a bundle needs at least two different packs and cannot contain itself", CategoryInvalid, "components")
+	ErrBundleComponentInvalid   = newError("84", "a component must be an active pack of the mno of the bundle that is not a bundle", CategoryInvalid, "components")
+	ErrComponentInUse           = newError("85", "the pack is a component of an active bundle", CategoryConflict, "id")
+	ErrBundleComponentInactive  = newError("86", "a component of the bundle is inactive", CategoryConflict, "id")
+	ErrBundleResources          = newError("87", "the resources of a bundle are aggregated from its components", CategoryConflict, "resources")
+	ErrNotBundle                = newError("88", "the pack is not a bundle", CategoryConflict, "id")
+	ErrEligibilityRuleInvalid   = newError("89", "eligibility rules of the pack are invalid", CategoryInvalid, "rules")
+	ErrEligibilityNotChecked    = newError("90", "purchases of the customer cannot be read to check the eligibility", CategoryUnavailable, "")
+	ErrChannelUnknown           = newError("91", "a channel of the pack is not in the catalog of channels", CategoryInvalid, "channels")
+	ErrRegionUnknown            = newError("92", "a region of the pack is not in the catalog of regions", CategoryInvalid, "regions")
+	ErrAvailabilityCodeInvalid  = newError("93", "channel or region data is invalid", CategoryInvalid, "code")
+	ErrAvailabilityDuplicated   = newError("94", "there is a channel or region with the code", CategoryConflict, "code")
+	ErrAvailabilityNotFound     = newError("95", "channel or region does not exist", CategoryNotFound, "code")
+	ErrAvailabilityNotValidated = newError("96", "channels and regions cannot be validated", CategoryUnavailable, "")
+	ErrCompareArgs              = newError("97", "between 2 and 10 different pack ids are compared", CategoryInvalid, "ids")
+	ErrStatsGroupInvalid        = newError("98", "stats are grouped by mno, type, state, owner or currency, each one once", CategoryInvalid, "groupBy")
+	ErrStatsFailed              = newError("99", "catalog stats cannot be calculated", CategoryUnavailable, "")
+	ErrReportPeriod             = newError("100", "report period must start before it ends and last at most 2 years", CategoryInvalid, "from")
+	ErrReportBucket             = newError("101", "reports are bucketed by day, week or month", CategoryInvalid, "bucket")
+	ErrReportDimension          = newError("102", "reports are by pack, mno or owner", CategoryInvalid, "by")
+	ErrReportTimezone           = newError("103", "time zone of the report is unknown", CategoryInvalid, "timezone")
+	ErrReportFailed             = newError("104", "orders of the report cannot be read", CategoryUnavailable, "")
+	ErrWebhookInvalid           = newError("105", "webhook data is invalid", CategoryInvalid, "url")
+	ErrWebhookNotFound          = newError("106", "webhook does not exist", CategoryNotFound, "id")
+	ErrWebhookDeliveryNotFound  = newError("107", "webhook delivery does not exist", CategoryNotFound, "id")
+	ErrWebhookNotValidated      = newError("108", "webhooks cannot be validated", CategoryUnavailable, "")
+	ErrSubscriptionBehind       = newError("109", "subscription fell behind the pack events, subscribe again", CategoryUnavailable, "")
+	ErrSubscriptionLimit        = newError("110", "connection has too many subscriptions", CategoryConflict, "")
+	ErrSubscriptionInvalid      = newError("111", "subscription message is invalid", CategoryInvalid, "")
+	ErrPackModified             = newError("112", "pack was changed since it was read, read it again", CategoryPrecondition, "")
+	ErrPackBodyInvalid          = newError("113", "pack data is not valid json", CategoryInvalid, "body")
+	ErrPackFieldReadOnly        = newError("114", "field of the pack cannot be changed with this operation", CategoryInvalid, "")
+	ErrCommissionRuleNotFound   = newError("115", "commission rule does not exist", CategoryNotFound, "id")
+	ErrProvisionPending         = newError("116", "pack activation is pending, it is retried later", CategoryUnavailable, "")
+)
+
+// newError creates an error of the catalog.
+func newError(code string, message string, category ErrorCategory, field string) *Error {
+	return &Error{Code: code, Message: message, Category: category, Field: field}
+}
+
+// Error returns the message of the error with its code.
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Code, e.Message, e.Err)
+	}
+	return e.Code + ": " + e.Message
+}
+
+// Unwrap returns the cause of the error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is returns true if the target is an error of the catalog with the
+// same code.
+func (e *Error) Is(target error) bool {
+	catalogerr, ok := target.(*Error)
+	return ok && catalogerr.Code == e.Code
+}
+
+// Wrap returns a copy of the error caused by the given error.
+func (e *Error) Wrap(cause error) *Error {
+	wrapped := *e
+	wrapped.Err = cause
+	return &wrapped
+}
+
+// WithField returns a copy of the error caused by the given argument.
+func (e *Error) WithField(field string) *Error {
+	withfield := *e
+	withfield.Field = field
+	return &withfield
+}
+
+// Localize returns the message of the error in the given locale, the
+// english message if there is no translation.
+func (e *Error) Localize(locale string) string {
+	if message, ok := errorMessages[locale][e.Code]; ok {
+		return message
+	}
+	return e.Message
+}
+
+// Detail returns the details of the error shown to callers in the given locale.
+func (e *Error) Detail(locale string) *model.ErrorDetail {
+	return &model.ErrorDetail{
+		Code:     e.Code,
+		Message:  e.Localize(locale),
+		Category: string(e.Category),
+		Field:    e.Field,
+	}
+}
+
+// AsError returns the error of the catalog in the chain of err, a change
+// of a pack at an old version is ErrPackModified, a change of a pack out
+// of the tenant is ErrNotOwner and the other errors out of the catalog are
+// returned as ErrInternal.
+func AsError(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	var catalogerr *Error
+	if errors.As(err, &catalogerr) {
+		return catalogerr
+	}
+	if errors.Is(err, dao.ErrVersionChanged) {
+		return ErrPackModified.Wrap(err)
+	}
+	if errors.Is(err, dao.ErrNotInTenant) {
+		return ErrNotOwner.Wrap(err)
+	}
+	return ErrInternal.Wrap(err)
+}
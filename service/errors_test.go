@@ -0,0 +1,131 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/fernandoocampo/pack/dao"
+)
+
+// catalog contains every error of the catalog.
+var catalog = []*Error{ErrPackEmpty, ErrPackKeysEmpty, ErrPackTextsEmpty, ErrPackTypeInvalid,
+	ErrPackMnoInvalid, ErrPackTermInvalid, ErrPackNotValidated, ErrPackDuplicated, ErrChangeStateArgs,
+	ErrChangeProductIDArgs, ErrProductIDDuplicated, ErrChangePackCodeArgs, ErrPackCodeDuplicated,
+	ErrChangeNameArgs, ErrChangeDescArgs, ErrChangeImageArgs, ErrChangeKeywordsArgs, ErrChangePriceArgs,
+	ErrChangeTypeArgs, ErrChangeMnoArgs, ErrChangeValidityArgs, ErrChangeCurrencyArgs, ErrDeleteArgs,
+	ErrMoveStockArgs, ErrReplaceResourcesArgs, ErrDeleteResourcesArgs, ErrGrantArgs, ErrPackNotFound,
+	ErrPackNotActive, ErrConsumeArgs, ErrNotEnoughBalance, ErrSubscribeArgs, ErrAlreadySubscribed,
+	ErrSubscriptionIDEmpty, ErrSubscriptionState, ErrPurchaseArgs, ErrOutOfStock, ErrNoProvisioner,
+	ErrProvisionFailed, ErrOrderNotProvisioned, ErrOrderNotRefundable, ErrCommissionFailed,
+	ErrCommissionRuleInvalid, ErrCommissionRuleIDEmpty, ErrSettlementArgs, ErrAlreadySettled,
+	ErrOwnerInvalid, ErrOwnerNotValidated, ErrOwnerDuplicated, ErrOwnerNotFound, ErrOwnerNotAllowed,
+	ErrTransferArgs, ErrAlreadyOwner, ErrAPIKeyInvalid, ErrAPIKeyIDInvalid, ErrNotAdmin, ErrNotOwner,
+	ErrForbidden, ErrInternal, ErrTranslationInvalid, ErrTranslationNotFound, ErrTranslationDefaultLocale,
+	ErrImportFormat, ErrImportMode, ErrImportFile, ErrImportRowInvalid, ErrImportRowRepeated,
+	ErrImportDryRun, ErrExportFormat, ErrExportFailed,
+	ErrPackFilterInvalid, ErrBulkPatchEmpty, ErrBulkPatchInvalid, ErrBulkStateInvalid, ErrBulkJobNotFound,
+	ErrBulkFailed, ErrBulkPackFailed, ErrCloneArgs, ErrTemplateInvalid, ErrTemplateDuplicated,
+	ErrTemplateNotFound, ErrTemplateValueMissing, ErrTemplateValueUnknown, ErrBundleComponentsInvalid,
+	ErrBundleComponentInvalid, ErrComponentInUse, ErrBundleComponentInactive, ErrBundleResources, ErrNotBundle,
+	ErrEligibilityRuleInvalid, ErrEligibilityNotChecked, ErrChannelUnknown, ErrRegionUnknown,
+	ErrAvailabilityCodeInvalid, ErrAvailabilityDuplicated, ErrAvailabilityNotFound, ErrAvailabilityNotValidated,
+	ErrCompareArgs, ErrStatsGroupInvalid, ErrStatsFailed, ErrReportPeriod, ErrReportBucket, ErrReportDimension,
+	ErrReportTimezone, ErrReportFailed, ErrWebhookInvalid, ErrWebhookNotFound, ErrWebhookDeliveryNotFound,
+	ErrWebhookNotValidated, ErrSubscriptionBehind, ErrSubscriptionLimit, ErrSubscriptionInvalid,
+	ErrPackModified, ErrPackBodyInvalid, ErrPackFieldReadOnly, ErrCommissionRuleNotFound, ErrProvisionPending}
+
+// TestCatalogCodes tests codes are unique and every message is translated
+func TestCatalogCodes(t *testing.T) {
+	codes := map[string]bool{}
+	for _, catalogerr := range catalog {
+		if codes[catalogerr.Code] {
+			t.Errorf("Expected code %s to be unique but it is repeated", catalogerr.Code)
+		}
+		codes[catalogerr.Code] = true
+		if catalogerr.Message == "" || catalogerr.Category == "" {
+			t.Errorf("Expected message and category for code %s", catalogerr.Code)
+		}
+		for locale, messages := range errorMessages {
+			if _, ok := messages[catalogerr.Code]; !ok {
+				t.Errorf("Expected a %s message for code %s", locale, catalogerr.Code)
+			}
+		}
+	}
+	for locale, messages := range errorMessages {
+		for code := range messages {
+			if !codes[code] {
+				t.Errorf("Expected %s message for code %s to be in the catalog", locale, code)
+			}
+		}
+	}
+}
+
+// TestErrorWrap tests wrapped causes are kept and the code is matched
+func TestErrorWrap(t *testing.T) {
+	// GIVEN a dao error
+	cause := errors.New("An error checking pack existence - mongodao: no reachable servers")
+
+	// WHEN it is wrapped by an error of the catalog and returned by a caller
+	err := fmt.Errorf("creating pack: %w", ErrPackNotValidated.Wrap(cause))
+
+	// THEN the catalog error and its cause are found in the chain
+	if !errors.Is(err, ErrPackNotValidated) {
+		t.Fatalf("Expected err to be ErrPackNotValidated but it was not: %v", err)
+	}
+	if !errors.Is(err, cause) {
+		t.Fatalf("Expected the cause in the chain of err: %v", err)
+	}
+	if AsError(err).Code != "06" {
+		t.Fatalf("Expected code 06 but got %s", AsError(err).Code)
+	}
+	if ErrPackNotValidated.Err != nil {
+		t.Fatalf("Expected the catalog error to keep no cause but got %v", ErrPackNotValidated.Err)
+	}
+}
+
+// TestAsErrorOutOfCatalog tests errors out of the catalog are internal errors
+func TestAsErrorOutOfCatalog(t *testing.T) {
+	cause := errors.New("boom")
+
+	result := AsError(cause)
+
+	if result.Code != ErrInternal.Code || result.Err != cause {
+		t.Fatalf("Expected an internal error caused by boom but got %+v", result)
+	}
+	if AsError(nil) != nil {
+		t.Fatalf("Expected no error for nil")
+	}
+}
+
+// TestAsErrorVersionChanged tests a change at an old version of a pack is
+// ErrPackModified
+func TestAsErrorVersionChanged(t *testing.T) {
+	err := fmt.Errorf("An error updating a pack name - mongodao: %w", dao.ErrVersionChanged)
+
+	result := AsError(err)
+
+	if result.Code != ErrPackModified.Code || result.Category != CategoryPrecondition {
+		t.Fatalf("Expected ErrPackModified but got %+v", result)
+	}
+}
+
+// TestLocalize tests the messages are translated with english as fallback
+func TestLocalize(t *testing.T) {
+	if got := ErrPackNotFound.Localize("es"); got != "el paquete no existe" {
+		t.Fatalf("Expected the spanish message but got %s", got)
+	}
+	if got := ErrPackNotFound.Localize("fr"); got != ErrPackNotFound.Message {
+		t.Fatalf("Expected the english message but got %s", got)
+	}
+}
+
+// TestAsErrorNotInTenant tests a change of a pack out of the tenant of a
+// scoped dao is ErrNotOwner
+func TestAsErrorNotInTenant(t *testing.T) {
+	result := AsError(dao.ErrNotInTenant)
+
+	if result.Code != ErrNotOwner.Code || result.Category != CategoryForbidden {
+		t.Fatalf("Expected ErrNotOwner but got %+v", result)
+	}
+}
@@ -0,0 +1,9 @@
+package service
+
+// IEventService defines the behavior of the relay of the pack events.
+type IEventService interface {
+	// Relay publishes the events waiting in the outbox to every sink and
+	// returns how many were delivered. An event leaves the outbox only
+	// when every sink published it, so sinks get it at least once.
+	Relay() (int, error)
+}
@@ -0,0 +1,28 @@
+package service
+
+import "time"
+
+// Job is a task that runs in background, it returns how many
+// items it processed.
+type Job func() (int, error)
+
+// RunJob runs the given job every interval until the done channel is closed.
+func RunJob(name string, job Job, interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			processed, err := job()
+			if err != nil {
+				log.Errorf("running job %s: %v", name, err)
+				continue
+			}
+			if processed > 0 {
+				log.Infof("job %s processed %d items", name, processed)
+			}
+		case <-done:
+			return
+		}
+	}
+}
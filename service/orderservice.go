@@ -0,0 +1,26 @@
+package service
+
+import "github.com/fernandoocampo/pack/model"
+
+// IOrderService defines the behavior to sell packs to subscribers.
+type IOrderService interface {
+	// Purchase sells the pack to the subscriber at its current price. The
+	// pack is activated in the mno network and granted to the subscriber,
+	// on failure every change made is compensated. If the mno network is
+	// temporarily unavailable the pending order is returned with
+	// ErrProvisionPending, ProvisionDue retries it.
+	Purchase(msisdn string, packid string) (*model.Order, error)
+	// GetByID returns the order with the given id.
+	GetByID(id string) (*model.Order, error)
+	// GetByMsisdn returns the orders of a subscriber.
+	GetByMsisdn(msisdn string) ([]model.Order, error)
+	// ProvisionStatus asks the mno network for the status of the
+	// activation made by an order.
+	ProvisionStatus(id string) (model.ProvisionStatus, error)
+	// Refund gives back the price of a completed order, the commission
+	// of the owner is taken back in its next settlement.
+	Refund(id string, reason string) error
+	// ProvisionDue retries the pending orders whose next attempt is due,
+	// it returns how many were attempted.
+	ProvisionDue() (int, error)
+}
@@ -0,0 +1,17 @@
+package service
+
+import "github.com/fernandoocampo/pack/model"
+
+// IOwnerService defines the behavior of the companies that own packs for resale.
+type IOwnerService interface {
+	// Create registers a new active owner.
+	Create(owner *model.Owner) error
+	// GetByID returns the owner with the given id.
+	GetByID(id int) (*model.Owner, error)
+	// GetAll returns every owner.
+	GetAll() ([]model.Owner, error)
+	// Update changes the name, contact details and allowed mnos of an owner.
+	Update(owner *model.Owner) error
+	// ChangeState activates, deactivates or suspends an owner.
+	ChangeState(id int, newstate model.OwnerState) error
+}
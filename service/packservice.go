@@ -1,9 +1,20 @@
 package service
 
-import "github.com/fernandoocampo/pack/model"
+import (
+	"io"
+
+	"github.com/fernandoocampo/pack/model"
+)
 
 // IPackService defines pack service behavior for management purpose.
 type IPackService interface {
+	// WithTenant returns a service that only reads and changes the packs
+	// of the given tenant.
+	WithTenant(tenant *model.Tenant) IPackService
+	// WithVersion returns a service whose changes only apply to a pack
+	// still at the given version, otherwise they fail with
+	// ErrPackModified.
+	WithVersion(version int) IPackService
 	// FindByID search a pack with the given id
 	// and return it.
 	FindByID(id string) (*model.Pack, error)
@@ -23,6 +34,11 @@ type IPackService interface {
 	// Create inserts a new Pack in the system. Returns
 	// true if the Pack is created
 	Create(packdata *model.Pack) error
+	// Update replaces the catalog data of an existent pack with the given
+	// one, as an upsert import does. Its state, stock, rules and
+	// availability are kept, as its translations if the given pack has
+	// none.
+	Update(id string, packdata *model.Pack) error
 	// ChangeState changes the state of a pack.
 	ChangeState(id string, newstate model.PackState) error
 	// ChangeProductID changes the mno internal product id.
@@ -55,4 +71,72 @@ type IPackService interface {
 	Delete(id string) error
 	// DeleteResources remove the resources that we configured for a pack.
 	DeleteResources(id string) error
+	// TransferOwnership gives the pack to another owner, the new owner
+	// must be active and allowed to sell packs of the pack mno.
+	TransferOwnership(id string, newownerid int) error
+	// GetByOwner returns the packs of an owner.
+	GetByOwner(ownerid int) ([]model.Pack, error)
+	// SetTranslation adds or replaces the name, description and keywords
+	// of the pack in the locale of the translation.
+	SetTranslation(id string, translation model.Translation) error
+	// RemoveTranslation removes the translation of the pack in the given locale.
+	RemoveTranslation(id string, locale string) error
+	// Search returns the packs whose texts in any locale match the given
+	// text, at most limit packs.
+	Search(text string, limit int) ([]model.Pack, error)
+	// ImportPacks creates, or updates in upsert mode, the packs of a csv
+	// or json lines file one by one. The report has the result of every
+	// row, a failed row does not stop the others.
+	ImportPacks(file io.Reader, options model.ImportOptions) (*model.ImportReport, error)
+	// List returns the packs that match the filter sorted by pack code,
+	// skipping the first skip packs and at most limit packs.
+	List(filter *model.PackFilter, skip int, limit int) ([]model.Pack, error)
+	// Export writes every pack that matches the filter in the given
+	// format, packs are written as they are read.
+	Export(w io.Writer, format string, filter *model.PackFilter) error
+	// Clone creates a copy of a pack with the given overrides, the copy
+	// goes through the validations of Create.
+	Clone(id string, overrides *model.PackOverrides) (*model.Pack, error)
+	// CreateTemplate stores a template of packs, its pack goes through
+	// the validations of Create.
+	CreateTemplate(template *model.PackTemplate) error
+	// GetTemplate returns the template with the given id.
+	GetTemplate(id string) (*model.PackTemplate, error)
+	// GetTemplates returns every template sorted by name.
+	GetTemplates() ([]model.PackTemplate, error)
+	// DeleteTemplate removes the template with the given id, the packs
+	// created with it are kept.
+	DeleteTemplate(id string) error
+	// CreateFromTemplate creates a pack with the data of the template and
+	// its placeholders replaced by the given values, every placeholder
+	// needs a value.
+	CreateFromTemplate(id string, values map[string]string) (*model.Pack, error)
+	// CreateBundle creates a pack that combines other packs, it has its
+	// own price and validity and the resources of its components.
+	CreateBundle(bundle *model.Pack) error
+	// ChangeComponents replaces the packs of a bundle, the bundle gets
+	// the resources of the new components.
+	ChangeComponents(id string, components []string) error
+	// SetRules replaces the eligibility rules of the pack, an empty list
+	// makes it available to every customer.
+	SetRules(id string, rules []model.EligibilityRule) error
+	// CheckEligibility returns whether the customer can buy the pack and
+	// the conditions of its rules that failed.
+	CheckEligibility(id string, customer *model.CustomerContext) (*model.Eligibility, error)
+	// EligiblePacks returns the active packs that match the filter and
+	// the customer can buy, at most limit packs.
+	EligiblePacks(customer *model.CustomerContext, filter *model.PackFilter, limit int) ([]model.Pack, error)
+	// SetAvailability replaces the channels and regions where the pack is
+	// sold, empty lists make it available everywhere.
+	SetAvailability(id string, channels []string, regions []string) error
+	// ComparePacks returns the packs with the given ids side by side, their
+	// resources aligned and their prices per unit and per day.
+	ComparePacks(ids []string) (*model.PackComparison, error)
+	// SimilarPacks returns the active packs in stock of the mno of the
+	// given pack that match the filter, the most similar first and at most
+	// limit packs.
+	SimilarPacks(id string, filter *model.PackFilter, limit int) ([]model.SimilarPack, error)
+	// CatalogStats returns the count, prices, stock and resource totals
+	// of the packs that match the filter grouped by the given fields.
+	CatalogStats(filter *model.PackFilter, groupBy []string) ([]model.CatalogStats, error)
 }
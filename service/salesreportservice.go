@@ -0,0 +1,11 @@
+package service
+
+import "github.com/fernandoocampo/pack/model"
+
+// ISalesReportService defines the behavior of the sales reports.
+type ISalesReportService interface {
+	// Report returns the units sold, revenue, refunds and average price
+	// of the period of the query in buckets of its time zone. The time
+	// zone of the settlements is used if the query does not have one.
+	Report(query *model.SalesReportQuery) (*model.SalesReport, error)
+}
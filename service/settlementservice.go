@@ -0,0 +1,24 @@
+package service
+
+import (
+	"time"
+
+	"github.com/fernandoocampo/pack/model"
+)
+
+// ISettlementService defines the behavior of reseller settlements.
+type ISettlementService interface {
+	// Statement calculates the statement of an owner for the period
+	// [from, to) without storing it.
+	Statement(ownerid int, from time.Time, to time.Time) (*model.Settlement, error)
+	// Settle calculates and stores the statement of an owner for the
+	// period [from, to).
+	Settle(ownerid int, from time.Time, to time.Time) (*model.Settlement, error)
+	// GetByID returns the settlement with the given id.
+	GetByID(id string) (*model.Settlement, error)
+	// GetByOwner returns the settlements of an owner.
+	GetByOwner(ownerid int) ([]model.Settlement, error)
+	// SettleDue settles the last closed period of every owner with
+	// sales or refunds in it and returns how many were settled.
+	SettleDue() (int, error)
+}
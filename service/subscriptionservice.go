@@ -0,0 +1,27 @@
+package service
+
+import "github.com/fernandoocampo/pack/model"
+
+// ISubscriptionService defines the behavior of packs renewed automatically.
+type ISubscriptionService interface {
+	// Subscribe links the subscriber to the pack, the first period is
+	// granted right away and the next one at the end of the pack term. If
+	// the mno network is temporarily unavailable ErrProvisionPending is
+	// returned, the subscription is created when the order is completed.
+	// Subscribing again meanwhile returns ErrProvisionPending too.
+	Subscribe(msisdn string, packid string) (*model.Subscription, error)
+	// GetByID returns the subscription with the given id.
+	GetByID(id string) (*model.Subscription, error)
+	// GetByMsisdn returns the subscriptions of a subscriber.
+	GetByMsisdn(msisdn string) ([]model.Subscription, error)
+	// Pause stops the renewals of an active subscription.
+	Pause(id string) error
+	// Resume restarts the renewals of a paused subscription, a renewal
+	// that became due while paused is made right away.
+	Resume(id string) error
+	// Cancel ends a subscription, the current period is kept until it expires.
+	Cancel(id string) error
+	// RenewDue renews the subscriptions whose renewal is due and
+	// returns how many were processed.
+	RenewDue() (int, error)
+}
@@ -0,0 +1,29 @@
+package service
+
+import "github.com/fernandoocampo/pack/model"
+
+// IWebhookService defines the behavior of the webhooks partners are
+// called on when their packs change.
+type IWebhookService interface {
+	// Create registers a webhook, a secret is generated if it has none.
+	// The secret is returned only once.
+	Create(webhook *model.Webhook) (string, error)
+	// GetByID returns the webhook with the given id.
+	GetByID(id string) (*model.Webhook, error)
+	// GetAll returns the webhooks of the given owner, every webhook if it
+	// is 0.
+	GetAll(ownerid int) ([]model.Webhook, error)
+	// Delete removes a webhook, its pending deliveries die.
+	Delete(id string) error
+	// GetDelivery returns the delivery with the given id.
+	GetDelivery(id string) (*model.WebhookDelivery, error)
+	// Deliveries returns the last deliveries of a webhook in the given
+	// state, every state if it is empty. Dead deliveries are the ones
+	// that wait for a manual redelivery.
+	Deliveries(webhookid string, state string, limit int) ([]model.WebhookDelivery, error)
+	// Redeliver posts a delivery again right away, whatever its state.
+	Redeliver(id string) (*model.WebhookDelivery, error)
+	// DeliverDue posts the pending deliveries that are due, it returns
+	// how many were attempted.
+	DeliverDue() (int, error)
+}